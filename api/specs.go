@@ -0,0 +1,24 @@
+// Package api embeds the OpenAPI specifications so that serving and validating
+// them does not depend on the working directory of the process.
+package api
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed auth/openapi.yaml v1/openapi.yaml
+var specs embed.FS
+
+// Names lists the embedded specifications in the order they are offered in the docs UI
+var Names = []string{"auth", "v1"}
+
+// Spec returns the raw YAML of the named specification
+func Spec(name string) ([]byte, error) {
+	data, err := fs.ReadFile(specs, name+"/openapi.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown OpenAPI spec %q: %w", name, err)
+	}
+	return data, nil
+}
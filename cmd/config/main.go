@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"example.com/internal/infrastructure/config"
+)
+
+const usage = `Usage:
+  config print [--redacted] [--config <file>] [--<key>=<value>...]
+      Prints the effective configuration with the source of every value.
+  config audit [--config <file>] [--<key>=<value>...]
+      Lists the settings that would prevent starting in production.
+
+Both accept the same config file, environment variables and flags as the server.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "print":
+		printConfig(os.Args[2:])
+	case "audit":
+		auditConfig(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func printConfig(args []string) {
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	redacted := fs.Bool("redacted", false, "mask secret values")
+
+	cfg, sources, err := load(fs, args)
+
+	if printErr := config.Print(os.Stdout, cfg, sources, *redacted); printErr != nil {
+		log.Fatalf("Failed to print configuration: %v", printErr)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func auditConfig(args []string) {
+	cfg, _, err := load(flag.NewFlagSet("config audit", flag.ContinueOnError), args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	findings := config.Audit(cfg)
+	for _, finding := range findings {
+		fmt.Println(finding)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+	fmt.Println("No findings")
+}
+
+// load resolves the configuration, exiting unless a (possibly invalid) configuration was resolved
+func load(fs *flag.FlagSet, args []string) (*config.Config, config.Sources, error) {
+	loader := config.NewLoader(args)
+	loader.FlagSet = fs
+
+	cfg, sources, err := loader.Load()
+	if cfg == nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	return cfg, sources, err
+}
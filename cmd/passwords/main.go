@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1" // #nosec G505 - breach corpora are published as SHA-1 hashes
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"example.com/pkg/security"
+)
+
+const usage = `Usage:
+  passwords import --out <file> [--min-count <n>] [--plain] <file or directory>...
+      Builds the breach dataset read from PASSWORD_BREACH_DATASET_FILE. Inputs hold
+      "<SHA-1>:<count>" lines in ascending order, like the "ordered by hash" download of
+      Pwned Passwords, or are k-anonymity range files named after their 5 digit prefix
+      holding "<suffix>:<count>" lines; the files of a directory are read in name order.
+      With --plain, inputs list one password per line in any order instead.
+      The dataset is renamed over --out once complete, so updating it is atomic.
+  passwords check --dataset <file>
+      Reads passwords from stdin, one per line, and tells whether each one is breached.
+`
+
+// prefixLength is the number of hex digits naming a k-anonymity range file
+const prefixLength = 5
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "import":
+		importDataset(os.Args[2:])
+	case "check":
+		checkPasswords(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func importDataset(args []string) {
+	fs := flag.NewFlagSet("passwords import", flag.ExitOnError)
+	out := fs.String("out", "", "dataset file to write")
+	minCount := fs.Int("min-count", 1, "skip hashes seen in fewer breaches")
+	plain := fs.Bool("plain", false, "inputs list passwords instead of hashes")
+	_ = fs.Parse(args)
+	if *out == "" || fs.NArg() == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	files, err := inputFiles(fs.Args())
+	if err != nil {
+		log.Fatalf("Failed to list inputs: %v", err)
+	}
+
+	n, err := writeDataset(*out, func(w *security.BreachDatasetWriter) error {
+		if *plain {
+			return addPasswords(w, files)
+		}
+		return addHashes(w, files, *minCount)
+	})
+	if err != nil {
+		log.Fatalf("Failed to import: %v", err)
+	}
+	fmt.Printf("Wrote %d hashes to %s\n", n, *out)
+}
+
+// inputFiles expands directories to their files in name order
+func inputFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// writeDataset writes the dataset to a temporary file next to path and renames it over path
+func writeDataset(path string, add func(*security.BreachDatasetWriter) error) (int, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".breach-dataset-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w, err := security.NewBreachDatasetWriter(tmp)
+	if err != nil {
+		return 0, err
+	}
+	if err := add(w); err != nil {
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	// The dataset holds no secrets and is read by the server, which may run as another user
+	if err := tmp.Chmod(0o644); err != nil {
+		return 0, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return w.Len(), os.Rename(tmp.Name(), path)
+}
+
+func addHashes(w *security.BreachDatasetWriter, files []string, minCount int) error {
+	for _, file := range files {
+		// Range files carry the first digits of their hashes in their name
+		prefix := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if _, err := hex.DecodeString(prefix + "0"); err != nil || len(prefix) != prefixLength {
+			prefix = ""
+		}
+
+		err := eachLine(file, func(line string) error {
+			digest, count, err := parseHashLine(prefix + line)
+			if err != nil || count < minCount {
+				return err
+			}
+			return w.Add(digest)
+		})
+		if errors.Is(err, security.ErrUnsortedBreachHashes) {
+			return fmt.Errorf("%s: %w; use --plain for unsorted lists", file, err)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// parseHashLine parses "<40 hex digits>[:<count>]"
+func parseHashLine(line string) (digest [sha1.Size]byte, count int, err error) {
+	hash, rawCount, hasCount := strings.Cut(line, ":")
+	if len(hash) != 2*sha1.Size {
+		return digest, 0, fmt.Errorf("invalid hash %q", hash)
+	}
+	if _, err := hex.Decode(digest[:], []byte(hash)); err != nil {
+		return digest, 0, fmt.Errorf("invalid hash %q", hash)
+	}
+
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(strings.TrimSpace(rawCount)); err != nil {
+			return digest, 0, fmt.Errorf("invalid count %q", rawCount)
+		}
+	}
+	return digest, count, nil
+}
+
+// addPasswords hashes the passwords of files in memory, sorts them and adds them
+func addPasswords(w *security.BreachDatasetWriter, files []string) error {
+	var digests [][sha1.Size]byte
+	for _, file := range files {
+		err := eachLine(file, func(line string) error {
+			digests = append(digests, sha1.Sum([]byte(line))) // #nosec G401 - see the import
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	slices.SortFunc(digests, func(a, b [sha1.Size]byte) int { return bytes.Compare(a[:], b[:]) })
+	for _, digest := range digests {
+		if err := w.Add(digest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachLine calls fn with every non-blank line of file, without surrounding white space
+func eachLine(file string, fn func(line string) error) error {
+	f, err := os.Open(file) // #nosec G304 - files are given on the command line
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return scanLines(f, fn)
+}
+
+func scanLines(r io.Reader, fn func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func checkPasswords(args []string) {
+	fs := flag.NewFlagSet("passwords check", flag.ExitOnError)
+	path := fs.String("dataset", "", "dataset file to check against")
+	_ = fs.Parse(args)
+	if *path == "" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	dataset, err := security.OpenBreachDataset(*path)
+	if err != nil {
+		log.Fatalf("Failed to open dataset: %v", err)
+	}
+	defer dataset.Close()
+
+	err = scanLines(os.Stdin, func(password string) error {
+		breached, err := dataset.Breached(context.Background(), password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			fmt.Println("breached")
+		} else {
+			fmt.Println("not found")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to check passwords: %v", err)
+	}
+}
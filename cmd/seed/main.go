@@ -1,25 +1,42 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"example.com/internal/app"
 	"example.com/internal/domain/repository"
+	"example.com/internal/infrastructure/config"
 	"example.com/internal/infrastructure/database"
 	"example.com/pkg/security"
+	"example.com/pkg/tenant"
 )
 
 func main() {
-	container, err := app.BuildContainer()
+	// The server flags are not parsed here; the configuration comes from the environment
+	container, err := app.BuildContainer(nil)
 	if err != nil {
 		log.Fatalf("Failed to build container: %v", err)
 	}
 
 	err = container.Invoke(func(
+		cfg *config.Config,
 		userRepo repository.UserRepository,
+		roleRepo repository.RoleRepository,
 		hasher security.PasswordHasher,
 	) error {
-		return database.SeedDatabase(userRepo, hasher)
+		if !cfg.Tenancy.Enabled {
+			return database.SeedDatabase(context.Background(), userRepo, roleRepo, hasher)
+		}
+
+		// Every tenant gets its own demo users
+		for _, id := range cfg.Tenancy.Tenants {
+			log.Printf("Seeding tenant %s", id)
+			if err := database.SeedDatabase(tenant.NewContext(context.Background(), id), userRepo, roleRepo, hasher); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 
 	if err != nil {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"gorm.io/gorm"
 
@@ -10,7 +11,7 @@ import (
 )
 
 func main() {
-	container, err := app.BuildContainer()
+	container, err := app.BuildContainer(os.Args[1:])
 	if err != nil {
 		log.Fatalf("Failed to build container: %v", err)
 	}
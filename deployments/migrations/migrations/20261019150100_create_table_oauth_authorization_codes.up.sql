@@ -0,0 +1,15 @@
+CREATE TABLE oauth_authorization_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri VARCHAR(2048) NOT NULL,
+    code_challenge VARCHAR(128) NOT NULL,
+    nonce VARCHAR(255),
+    scopes JSONB NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_oauth_authorization_codes_client_id ON oauth_authorization_codes (client_id);
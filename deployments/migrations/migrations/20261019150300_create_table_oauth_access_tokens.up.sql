@@ -0,0 +1,15 @@
+CREATE TABLE oauth_access_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    code_id UUID,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_oauth_access_tokens_client_id ON oauth_access_tokens (client_id);
+CREATE INDEX idx_oauth_access_tokens_user_id ON oauth_access_tokens (user_id);
+CREATE INDEX idx_oauth_access_tokens_code_id ON oauth_access_tokens (code_id);
@@ -0,0 +1,14 @@
+CREATE TABLE login_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    method VARCHAR(8) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    device_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_login_challenges_user_id ON login_challenges (user_id);
+CREATE INDEX idx_login_challenges_created_at ON login_challenges (created_at);
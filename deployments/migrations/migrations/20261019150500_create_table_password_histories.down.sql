@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS password_histories;
+
+ALTER TABLE users DROP COLUMN sessions_revoked_at;
+ALTER TABLE users DROP COLUMN password_changed_at;
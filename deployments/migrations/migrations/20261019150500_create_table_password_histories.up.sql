@@ -0,0 +1,13 @@
+-- Password changes end the sessions started before them
+ALTER TABLE users ADD COLUMN password_changed_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN sessions_revoked_at TIMESTAMPTZ;
+
+CREATE TABLE password_histories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_password_histories_user_id ON password_histories (user_id);
+CREATE INDEX idx_password_histories_created_at ON password_histories (created_at);
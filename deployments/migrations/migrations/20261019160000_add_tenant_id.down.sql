@@ -0,0 +1,46 @@
+DROP POLICY IF EXISTS tenant_isolation ON invitations;
+ALTER TABLE invitations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE invitations DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON memberships;
+ALTER TABLE memberships NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE memberships DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON organizations;
+ALTER TABLE organizations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE organizations DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON user_identities;
+ALTER TABLE user_identities NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_identities DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON refresh_tokens;
+ALTER TABLE refresh_tokens NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE refresh_tokens DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON api_tokens;
+ALTER TABLE api_tokens NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE api_tokens DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON users;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_invitations_tenant_id;
+ALTER TABLE invitations DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_memberships_tenant_id;
+ALTER TABLE memberships DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_organizations_tenant_id;
+ALTER TABLE organizations DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_user_identities_tenant_provider_subject;
+ALTER TABLE user_identities ADD CONSTRAINT user_identities_provider_subject_key UNIQUE (provider, subject);
+ALTER TABLE user_identities DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_tenant_id;
+ALTER TABLE refresh_tokens DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_api_tokens_tenant_id;
+ALTER TABLE api_tokens DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_users_tenant_email;
+DROP INDEX IF EXISTS idx_users_tenant_user_name;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE users ADD CONSTRAINT users_user_name_key UNIQUE (user_name);
+ALTER TABLE users DROP COLUMN tenant_id;
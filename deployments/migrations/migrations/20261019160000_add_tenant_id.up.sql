@@ -0,0 +1,55 @@
+-- Every row belongs to a tenant; rows created before tenancy belong to the empty tenant
+ALTER TABLE users ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+ALTER TABLE users DROP CONSTRAINT users_user_name_key;
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX idx_users_tenant_user_name ON users (tenant_id, user_name);
+CREATE UNIQUE INDEX idx_users_tenant_email ON users (tenant_id, email);
+
+ALTER TABLE api_tokens ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_api_tokens_tenant_id ON api_tokens (tenant_id);
+
+ALTER TABLE refresh_tokens ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_refresh_tokens_tenant_id ON refresh_tokens (tenant_id);
+
+ALTER TABLE user_identities ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+ALTER TABLE user_identities DROP CONSTRAINT user_identities_provider_subject_key;
+CREATE UNIQUE INDEX idx_user_identities_tenant_provider_subject ON user_identities (tenant_id, provider, subject);
+
+ALTER TABLE organizations ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_organizations_tenant_id ON organizations (tenant_id);
+
+ALTER TABLE memberships ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_memberships_tenant_id ON memberships (tenant_id);
+
+ALTER TABLE invitations ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_invitations_tenant_id ON invitations (tenant_id);
+
+-- Statements only see the rows of the tenant in app.tenant_id, which the application sets
+-- for the transaction of every statement. Superusers bypass these policies.
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON users USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE api_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_tokens USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE refresh_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE refresh_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON refresh_tokens USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE user_identities ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_identities FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_identities USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE organizations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organizations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON organizations USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE memberships ENABLE ROW LEVEL SECURITY;
+ALTER TABLE memberships FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON memberships USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE invitations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invitations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON invitations USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN password_reset_required;
+ALTER TABLE users DROP COLUMN locked_at;
+ALTER TABLE users DROP COLUMN email_verified_at;
@@ -0,0 +1,6 @@
+DROP INDEX idx_users_active_email;
+DROP INDEX idx_users_active_user_name;
+CREATE UNIQUE INDEX idx_users_tenant_user_name ON users (tenant_id, user_name);
+CREATE UNIQUE INDEX idx_users_tenant_email ON users (tenant_id, email);
+
+ALTER TABLE users DROP COLUMN purged_at;
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN purged_at TIMESTAMPTZ;
+
+-- Deleted users no longer keep others from signing up with their user name or email
+DROP INDEX idx_users_tenant_user_name;
+DROP INDEX idx_users_tenant_email;
+CREATE UNIQUE INDEX idx_users_active_user_name ON users (tenant_id, user_name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_users_active_email ON users (tenant_id, email) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
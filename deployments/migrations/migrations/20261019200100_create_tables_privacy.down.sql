@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS account_deletions;
+DROP TABLE IF EXISTS data_exports;
+DROP TABLE IF EXISTS audit_events;
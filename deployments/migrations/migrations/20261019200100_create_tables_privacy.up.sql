@@ -0,0 +1,58 @@
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT '',
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL,
+    detail VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_events_user_id ON audit_events (user_id);
+CREATE INDEX idx_audit_events_tenant_id ON audit_events (tenant_id);
+
+CREATE TABLE data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT '',
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    token_hash VARCHAR(64),
+    size BIGINT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports (user_id);
+CREATE INDEX idx_data_exports_status ON data_exports (status);
+CREATE INDEX idx_data_exports_token_hash ON data_exports (token_hash);
+CREATE INDEX idx_data_exports_expires_at ON data_exports (expires_at);
+CREATE INDEX idx_data_exports_tenant_id ON data_exports (tenant_id);
+
+CREATE TABLE account_deletions (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    scheduled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_account_deletions_token_hash ON account_deletions (token_hash);
+CREATE INDEX idx_account_deletions_scheduled_at ON account_deletions (scheduled_at);
+CREATE INDEX idx_account_deletions_tenant_id ON account_deletions (tenant_id);
+
+-- Privacy requests belong to a tenant like users, see 20261019160000_add_tenant_id
+ALTER TABLE audit_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_events USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE data_exports ENABLE ROW LEVEL SECURITY;
+ALTER TABLE data_exports FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON data_exports USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE account_deletions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE account_deletions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON account_deletions USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
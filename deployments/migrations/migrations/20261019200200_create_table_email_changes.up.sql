@@ -0,0 +1,24 @@
+CREATE TABLE email_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT '',
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_email VARCHAR(50) NOT NULL,
+    new_email VARCHAR(50) NOT NULL,
+    confirm_hash VARCHAR(64) NOT NULL,
+    revert_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    revert_expires_at TIMESTAMPTZ NOT NULL,
+    reverted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_email_changes_confirm_hash ON email_changes (confirm_hash);
+CREATE UNIQUE INDEX idx_email_changes_revert_hash ON email_changes (revert_hash);
+CREATE INDEX idx_email_changes_user_id ON email_changes (user_id);
+CREATE INDEX idx_email_changes_tenant_id ON email_changes (tenant_id);
+
+-- Email changes belong to a tenant like users, see 20261019160000_add_tenant_id
+ALTER TABLE email_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE email_changes FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON email_changes USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
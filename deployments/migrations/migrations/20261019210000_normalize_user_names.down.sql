@@ -0,0 +1,2 @@
+-- The user names lower-cased by the up migration stay lower-case
+DROP INDEX IF EXISTS idx_users_user_name_skeleton;
@@ -0,0 +1,13 @@
+-- User names are stored in lower case; names whose lower-case form another user already has
+-- are left for an administrator to rename
+UPDATE users u SET user_name = lower(u.user_name)
+WHERE u.user_name <> lower(u.user_name)
+  AND NOT EXISTS (
+    SELECT 1 FROM users o
+    WHERE o.tenant_id = u.tenant_id AND o.user_name = lower(u.user_name) AND o.deleted_at IS NULL
+  );
+
+-- Confusable user names are looked up by their skeleton, see security.UsernameSkeleton
+CREATE INDEX IF NOT EXISTS idx_users_user_name_skeleton ON users (
+  tenant_id, (translate(replace(replace(lower(user_name), 'rn', 'm'), 'vv', 'w'), '01i.-', 'oll__'))
+) WHERE deleted_at IS NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE user_name_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT '',
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_user_name VARCHAR(15) NOT NULL,
+    old_skeleton VARCHAR(15) NOT NULL,
+    new_user_name VARCHAR(15) NOT NULL,
+    redirect_expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_user_name_changes_user_id ON user_name_changes (user_id);
+CREATE INDEX idx_user_name_changes_old_skeleton ON user_name_changes (old_skeleton);
+CREATE INDEX idx_user_name_changes_tenant_id ON user_name_changes (tenant_id);
+
+-- Renames belong to a tenant like users, see 20261019160000_add_tenant_id
+ALTER TABLE user_name_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_name_changes FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_name_changes USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
@@ -0,0 +1,24 @@
+DROP POLICY IF EXISTS tenant_isolation ON oauth_access_tokens;
+ALTER TABLE oauth_access_tokens NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE oauth_access_tokens DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON oauth_consents;
+ALTER TABLE oauth_consents NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE oauth_consents DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON oauth_authorization_codes;
+ALTER TABLE oauth_authorization_codes NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE oauth_authorization_codes DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON oauth_clients;
+ALTER TABLE oauth_clients NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE oauth_clients DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_oauth_access_tokens_tenant_id;
+ALTER TABLE oauth_access_tokens DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_oauth_consents_tenant_id;
+ALTER TABLE oauth_consents DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_oauth_authorization_codes_tenant_id;
+ALTER TABLE oauth_authorization_codes DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_oauth_clients_tenant_id;
+ALTER TABLE oauth_clients DROP COLUMN tenant_id;
@@ -0,0 +1,29 @@
+-- Clients, grants and tokens belong to the tenant of their owner or user, like the other
+-- tokens since 20261019160000_add_tenant_id
+ALTER TABLE oauth_clients ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_oauth_clients_tenant_id ON oauth_clients (tenant_id);
+
+ALTER TABLE oauth_authorization_codes ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_oauth_authorization_codes_tenant_id ON oauth_authorization_codes (tenant_id);
+
+ALTER TABLE oauth_consents ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_oauth_consents_tenant_id ON oauth_consents (tenant_id);
+
+ALTER TABLE oauth_access_tokens ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX idx_oauth_access_tokens_tenant_id ON oauth_access_tokens (tenant_id);
+
+ALTER TABLE oauth_clients ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oauth_clients FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON oauth_clients USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE oauth_authorization_codes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oauth_authorization_codes FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON oauth_authorization_codes USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE oauth_consents ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oauth_consents FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON oauth_consents USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE oauth_access_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oauth_access_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON oauth_access_tokens USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
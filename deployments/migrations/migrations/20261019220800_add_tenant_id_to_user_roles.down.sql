@@ -0,0 +1,6 @@
+DROP POLICY IF EXISTS tenant_isolation ON user_roles;
+ALTER TABLE user_roles NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_roles DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_user_roles_tenant_id;
+ALTER TABLE user_roles DROP COLUMN tenant_id;
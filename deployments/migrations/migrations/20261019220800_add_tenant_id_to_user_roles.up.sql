@@ -0,0 +1,9 @@
+-- Role assignments belong to the tenant of their user, like the other rows of users since
+-- 20261019160000_add_tenant_id. Roles themselves are shared by all tenants.
+ALTER TABLE user_roles ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+UPDATE user_roles SET tenant_id = users.tenant_id FROM users WHERE users.id = user_roles.user_id;
+CREATE INDEX idx_user_roles_tenant_id ON user_roles (tenant_id);
+
+ALTER TABLE user_roles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_roles FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_roles USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
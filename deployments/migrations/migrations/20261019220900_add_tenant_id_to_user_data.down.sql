@@ -0,0 +1,18 @@
+DROP POLICY IF EXISTS tenant_isolation ON password_histories;
+ALTER TABLE password_histories NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE password_histories DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON login_challenges;
+ALTER TABLE login_challenges NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE login_challenges DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON user_profiles;
+ALTER TABLE user_profiles NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_profiles DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_password_histories_tenant_id;
+ALTER TABLE password_histories DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_login_challenges_tenant_id;
+ALTER TABLE login_challenges DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_user_profiles_tenant_id;
+ALTER TABLE user_profiles DROP COLUMN tenant_id;
@@ -0,0 +1,25 @@
+-- Profiles, passwordless logins and password history belong to the tenant of their user, like
+-- the other rows of users since 20261019160000_add_tenant_id
+ALTER TABLE user_profiles ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+UPDATE user_profiles SET tenant_id = users.tenant_id FROM users WHERE users.id = user_profiles.user_id;
+CREATE INDEX idx_user_profiles_tenant_id ON user_profiles (tenant_id);
+
+ALTER TABLE login_challenges ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+UPDATE login_challenges SET tenant_id = users.tenant_id FROM users WHERE users.id = login_challenges.user_id;
+CREATE INDEX idx_login_challenges_tenant_id ON login_challenges (tenant_id);
+
+ALTER TABLE password_histories ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT '';
+UPDATE password_histories SET tenant_id = users.tenant_id FROM users WHERE users.id = password_histories.user_id;
+CREATE INDEX idx_password_histories_tenant_id ON password_histories (tenant_id);
+
+ALTER TABLE user_profiles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_profiles FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_profiles USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE login_challenges ENABLE ROW LEVEL SECURITY;
+ALTER TABLE login_challenges FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON login_challenges USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
+
+ALTER TABLE password_histories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE password_histories FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON password_histories USING (tenant_id = coalesce(current_setting('app.tenant_id', true), ''));
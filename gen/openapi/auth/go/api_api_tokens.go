@@ -0,0 +1,38 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type APITokensAPI struct {
+}
+
+// Post /api/v1/auth/tokens
+// Create an API token
+func (api *APITokensAPI) CreateApiToken(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/auth/tokens
+// List the API tokens of the current user
+func (api *APITokensAPI) ListApiTokens(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/auth/tokens/:tokenId
+// Revoke an API token
+func (api *APITokensAPI) RevokeApiToken(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
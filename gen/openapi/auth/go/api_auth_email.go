@@ -0,0 +1,38 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AuthEmailAPI struct {
+}
+
+// Post /api/v1/auth/email/change
+// Change the email of the current user
+func (api *AuthEmailAPI) RequestEmailChange(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/auth/email/confirm
+// Confirm an email change with the link sent to the new address
+func (api *AuthEmailAPI) ConfirmEmailChange(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/auth/email/revert
+// Revert an email change with the link sent to the old address
+func (api *AuthEmailAPI) RevertEmailChange(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
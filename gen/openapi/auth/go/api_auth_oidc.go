@@ -0,0 +1,59 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AuthOIDCAPI struct {
+}
+
+// Get /api/v1/auth/identities
+// List the provider identities linked to the current user
+func (api *AuthOIDCAPI) ListIdentities(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/auth/oidc/providers
+// List the providers users can sign in with
+func (api *AuthOIDCAPI) ListOidcProviders(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/auth/oidc/:provider/callback
+// Complete a sign-in or link
+func (api *AuthOIDCAPI) OidcCallback(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/auth/oidc/:provider/link
+// Link a provider to the current user
+func (api *AuthOIDCAPI) StartOidcLink(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/auth/oidc/:provider/login
+// Sign in with a provider
+func (api *AuthOIDCAPI) StartOidcLogin(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/auth/identities/:identityId
+// Unlink a provider identity
+func (api *AuthOIDCAPI) UnlinkIdentity(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
@@ -0,0 +1,24 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AuthPasswordAPI struct {
+}
+
+// Post /api/v1/auth/password/change
+// Change the password of the current user
+func (api *AuthPasswordAPI) ChangePassword(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
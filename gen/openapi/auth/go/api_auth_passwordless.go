@@ -0,0 +1,31 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AuthPasswordlessAPI struct {
+}
+
+// Post /api/v1/auth/passwordless/start
+// Email a login link or code
+func (api *AuthPasswordlessAPI) StartPasswordlessLogin(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/auth/passwordless/verify
+// Complete a login with the emailed link or code
+func (api *AuthPasswordlessAPI) VerifyPasswordlessLogin(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
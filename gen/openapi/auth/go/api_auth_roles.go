@@ -0,0 +1,45 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AuthRolesAPI struct {
+}
+
+// Put /api/v1/auth/users/:userId/roles/:role
+// Assign a role to a user
+func (api *AuthRolesAPI) AssignRole(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/auth/roles
+// List the roles and their permissions
+func (api *AuthRolesAPI) ListRoles(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/auth/users/:userId/roles
+// List the roles of a user
+func (api *AuthRolesAPI) ListUserRoles(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/auth/users/:userId/roles/:role
+// Remove a role from a user
+func (api *AuthRolesAPI) UnassignRole(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
@@ -0,0 +1,24 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AuthTokenAPI struct {
+}
+
+// Post /api/v1/auth/token/refresh
+// Exchange a refresh token for a new token pair
+func (api *AuthTokenAPI) RefreshAccessToken(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
@@ -17,13 +17,29 @@ type AuthUserAPI struct {
 }
 
 // Post /auth/user/login
+// Log in a user (legacy)
+// Deprecated
+func (api *AuthUserAPI) LegacyUserLogin(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /auth/user/signup
+// Sign up a new user (legacy)
+// Deprecated
+func (api *AuthUserAPI) LegacyUserSignup(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/auth/login
 // Log in a user
 func (api *AuthUserAPI) UserLogin(c *gin.Context) {
 	// Your handler implementation
 	c.JSON(200, gin.H{"status": "OK"})
 }
 
-// Post /auth/user/signup
+// Post /api/v1/auth/signup
 // Sign up a new user
 func (api *AuthUserAPI) UserSignup(c *gin.Context) {
 	// Your handler implementation
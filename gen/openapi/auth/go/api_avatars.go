@@ -0,0 +1,24 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AvatarsAPI struct {
+}
+
+// Get /avatars/:userId/:file
+// Get a thumbnail of an avatar
+func (api *AvatarsAPI) GetAvatar(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
@@ -0,0 +1,66 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type OAuth2API struct {
+}
+
+// Get /api/v1/oauth2/consent
+// Get the authorization request awaiting consent
+func (api *OAuth2API) GetOAuthConsent(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /.well-known/openid-configuration
+// Get the authorization server metadata
+func (api *OAuth2API) GetOpenIdConfiguration(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /oauth2/authorize
+// Start an authorization code flow
+func (api *OAuth2API) OauthAuthorize(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /oauth2/introspect
+// Describe an access token
+func (api *OAuth2API) OauthIntrospect(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /oauth2/revoke
+// Revoke an access token
+func (api *OAuth2API) OauthRevoke(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /oauth2/token
+// Issue an access token
+func (api *OAuth2API) OauthToken(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/oauth2/consent
+// Approve or deny the authorization request awaiting consent
+func (api *OAuth2API) SubmitOAuthConsent(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
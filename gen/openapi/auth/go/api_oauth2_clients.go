@@ -0,0 +1,38 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type OAuth2ClientsAPI struct {
+}
+
+// Post /api/v1/oauth2/clients
+// Register an OAuth client
+func (api *OAuth2ClientsAPI) CreateOAuthClient(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/oauth2/clients/:clientId
+// Delete an OAuth client
+func (api *OAuth2ClientsAPI) DeleteOAuthClient(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/oauth2/clients
+// List the OAuth clients of the current user
+func (api *OAuth2ClientsAPI) ListOAuthClients(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
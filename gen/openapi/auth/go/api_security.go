@@ -16,6 +16,13 @@ import (
 type SecurityAPI struct {
 }
 
+// Get /.well-known/jwks.json
+// Get the public keys verifying access tokens
+func (api *SecurityAPI) GetJwks(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
 // Get /csrf-token
 // Get CSRF token
 func (api *SecurityAPI) GetCsrfToken(c *gin.Context) {
@@ -0,0 +1,33 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"time"
+)
+
+type ApiToken struct {
+	Id string `json:"id"`
+
+	Name string `json:"name"`
+
+	// First characters of the token
+	Hint string `json:"hint"`
+
+	Scopes []ApiTokenScope `json:"scopes"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
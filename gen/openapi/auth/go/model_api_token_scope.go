@@ -0,0 +1,21 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+// ApiTokenScope : `users:read` allows `GET /api/v1/user/lookup`, `profile:read` and `profile:write` allow `GET` and
+// `PATCH /api/v1/users/me/profile`. Tokens can only reach routes requiring one of their scopes.
+type ApiTokenScope string
+
+// List of ApiTokenScope
+const (
+	API_TOKEN_SCOPE_USERSREAD    ApiTokenScope = "users:read"
+	API_TOKEN_SCOPE_PROFILEREAD  ApiTokenScope = "profile:read"
+	API_TOKEN_SCOPE_PROFILEWRITE ApiTokenScope = "profile:write"
+)
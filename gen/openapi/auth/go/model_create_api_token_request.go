@@ -0,0 +1,23 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"time"
+)
+
+type CreateApiTokenRequest struct {
+	Name string `json:"name"`
+
+	Scopes []ApiTokenScope `json:"scopes"`
+
+	// Omit for a token that never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
@@ -0,0 +1,17 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type CreateApiTokenResponse struct {
+	// The plain token; it cannot be retrieved again
+	Token string `json:"token"`
+
+	ApiToken ApiToken `json:"apiToken"`
+}
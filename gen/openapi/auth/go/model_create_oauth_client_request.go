@@ -0,0 +1,24 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type CreateOAuthClientRequest struct {
+	Name string `json:"name"`
+
+	// Absolute URIs; plain http is only accepted for loopback addresses
+	RedirectUris []string `json:"redirectUris,omitempty"`
+
+	GrantTypes []string `json:"grantTypes"`
+
+	Scopes []OAuthScope `json:"scopes,omitempty"`
+
+	// Register a client without secret, e.g. a single-page or native app
+	Public bool `json:"public,omitempty"`
+}
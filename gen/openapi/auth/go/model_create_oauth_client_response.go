@@ -0,0 +1,17 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type CreateOAuthClientResponse struct {
+	Client OAuthClient `json:"client"`
+
+	// Set for confidential clients; it cannot be retrieved again
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
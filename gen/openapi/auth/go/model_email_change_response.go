@@ -0,0 +1,26 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"time"
+)
+
+type EmailChangeResponse struct {
+	// The email of the user, which a requested change leaves unchanged until confirmed
+	Email string `json:"email"`
+
+	PendingEmail string `json:"pendingEmail,omitempty"`
+
+	// When the link to confirm the pending email expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	Message string `json:"message"`
+}
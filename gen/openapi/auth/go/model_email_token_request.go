@@ -0,0 +1,15 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type EmailTokenRequest struct {
+	// The `email_change_token` or `email_revert_token` query parameter of the emailed link
+	Token string `json:"token"`
+}
@@ -13,4 +13,6 @@ type Error struct {
 	Error string `json:"error"`
 
 	Message string `json:"message,omitempty"`
+
+	Details []FieldError `json:"details,omitempty"`
 }
@@ -0,0 +1,20 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type FieldError struct {
+
+	// Dot-separated path of the offending value
+	Field string `json:"field"`
+
+	In string `json:"in"`
+
+	Message string `json:"message"`
+}
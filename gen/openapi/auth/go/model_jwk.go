@@ -0,0 +1,32 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type Jwk struct {
+	Kty string `json:"kty"`
+
+	Kid string `json:"kid"`
+
+	Use string `json:"use"`
+
+	Alg string `json:"alg"`
+
+	// Curve of OKP keys
+	Crv string `json:"crv,omitempty"`
+
+	// Public key of OKP keys
+	X string `json:"x,omitempty"`
+
+	// Modulus of RSA keys
+	N string `json:"n,omitempty"`
+
+	// Exponent of RSA keys
+	E string `json:"e,omitempty"`
+}
@@ -10,7 +10,11 @@
 package authapi
 
 type LoginRequest struct {
+	// Email or user name of the account; user names match regardless of case
 	Email string `json:"email"`
 
 	Password string `json:"password"`
+
+	// Return an access token and refresh token instead of starting a cookie session
+	IssueTokens bool `json:"issueTokens,omitempty"`
 }
@@ -13,4 +13,15 @@ type LoginResponse struct {
 	User User `json:"user"`
 
 	Message string `json:"message"`
+
+	// Set when logging in with `issueTokens`
+	AccessToken string `json:"accessToken,omitempty"`
+
+	// Set when logging in with `issueTokens`
+	RefreshToken string `json:"refreshToken,omitempty"`
+
+	TokenType string `json:"tokenType,omitempty"`
+
+	// Lifetime of the access token in seconds
+	ExpiresIn int32 `json:"expiresIn,omitempty"`
 }
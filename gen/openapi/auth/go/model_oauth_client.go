@@ -0,0 +1,31 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"time"
+)
+
+type OAuthClient struct {
+	// The client_id
+	Id string `json:"id"`
+
+	Name string `json:"name"`
+
+	Confidential bool `json:"confidential"`
+
+	RedirectUris []string `json:"redirectUris"`
+
+	GrantTypes []string `json:"grantTypes"`
+
+	Scopes []OAuthScope `json:"scopes"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,18 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthConsent struct {
+	ClientId string `json:"clientId"`
+
+	ClientName string `json:"clientName"`
+
+	Scopes []OAuthScope `json:"scopes"`
+}
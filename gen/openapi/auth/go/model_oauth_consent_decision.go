@@ -0,0 +1,14 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthConsentDecision struct {
+	Approve bool `json:"approve"`
+}
@@ -0,0 +1,15 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthConsentResult struct {
+	// Redirect URI of the client with the outcome
+	RedirectTo string `json:"redirectTo"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthError struct {
+	Error string `json:"error"`
+
+	ErrorDescription string `json:"error_description,omitempty"`
+}
@@ -0,0 +1,27 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthIntrospection struct {
+	Active bool `json:"active"`
+
+	Scope string `json:"scope,omitempty"`
+
+	ClientId string `json:"client_id,omitempty"`
+
+	// ID of the user, absent for client credentials tokens
+	Sub string `json:"sub,omitempty"`
+
+	TokenType string `json:"token_type,omitempty"`
+
+	Iat int64 `json:"iat,omitempty"`
+
+	Exp int64 `json:"exp,omitempty"`
+}
@@ -0,0 +1,20 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+// OAuthScope : `openid` adds an ID token to the token response and requires JWT signing keys; `profile` and `email` add the user name and email to it.
+type OAuthScope string
+
+// List of OAuthScope
+const (
+	OAUTH_SCOPE_OPENID  OAuthScope = "openid"
+	OAUTH_SCOPE_PROFILE OAuthScope = "profile"
+	OAUTH_SCOPE_EMAIL   OAuthScope = "email"
+)
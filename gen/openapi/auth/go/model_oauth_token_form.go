@@ -0,0 +1,20 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthTokenForm struct {
+	Token string `json:"token"`
+
+	TokenTypeHint string `json:"token_type_hint,omitempty"`
+
+	ClientId string `json:"client_id,omitempty"`
+
+	ClientSecret string `json:"client_secret,omitempty"`
+}
@@ -0,0 +1,28 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthTokenRequest struct {
+	// `authorization_code` or `client_credentials`
+	GrantType string `json:"grant_type"`
+
+	Code string `json:"code,omitempty"`
+
+	RedirectUri string `json:"redirect_uri,omitempty"`
+
+	CodeVerifier string `json:"code_verifier,omitempty"`
+
+	// Space-separated scopes of a client credentials grant
+	Scope string `json:"scope,omitempty"`
+
+	ClientId string `json:"client_id,omitempty"`
+
+	ClientSecret string `json:"client_secret,omitempty"`
+}
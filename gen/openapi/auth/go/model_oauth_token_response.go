@@ -0,0 +1,25 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OAuthTokenResponse struct {
+	// Opaque token that resource servers verify through introspection
+	AccessToken string `json:"access_token"`
+
+	TokenType string `json:"token_type"`
+
+	// Lifetime of the access token in seconds
+	ExpiresIn int32 `json:"expires_in"`
+
+	Scope string `json:"scope,omitempty"`
+
+	// Set when the openid scope was granted
+	IdToken string `json:"id_token,omitempty"`
+}
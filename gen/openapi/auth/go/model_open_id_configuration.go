@@ -0,0 +1,39 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type OpenIdConfiguration struct {
+	Issuer string `json:"issuer"`
+
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+
+	TokenEndpoint string `json:"token_endpoint"`
+
+	IntrospectionEndpoint string `json:"introspection_endpoint,omitempty"`
+
+	RevocationEndpoint string `json:"revocation_endpoint,omitempty"`
+
+	// Set when ID tokens are signed, i.e. JWT access tokens are enabled
+	JwksUri string `json:"jwks_uri,omitempty"`
+
+	ScopesSupported []string `json:"scopes_supported,omitempty"`
+
+	ResponseTypesSupported []string `json:"response_types_supported"`
+
+	GrantTypesSupported []string `json:"grant_types_supported,omitempty"`
+
+	SubjectTypesSupported []string `json:"subject_types_supported"`
+
+	IdTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported,omitempty"`
+
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
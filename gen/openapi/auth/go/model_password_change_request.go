@@ -0,0 +1,17 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+
+	// Checked against the password policy configured with `PASSWORD_*`
+	NewPassword string `json:"newPassword"`
+}
@@ -0,0 +1,17 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type PasswordlessStartRequest struct {
+	Email string `json:"email"`
+
+	// Whether to email a link to `PASSWORDLESS_LINK_URL` or a 6-digit code
+	Method string `json:"method"`
+}
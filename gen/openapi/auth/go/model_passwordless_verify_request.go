@@ -0,0 +1,21 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+// PasswordlessVerifyRequest - Exactly one of `token` and `code`
+type PasswordlessVerifyRequest struct {
+	// The `passwordless_token` query parameter of the emailed link
+	Token string `json:"token,omitempty"`
+
+	Code string `json:"code,omitempty"`
+
+	// Return an access token and refresh token instead of starting a cookie session
+	IssueTokens bool `json:"issueTokens,omitempty"`
+}
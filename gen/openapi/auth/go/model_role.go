@@ -0,0 +1,18 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type Role struct {
+	Name string `json:"name"`
+
+	Description string `json:"description"`
+
+	Permissions []string `json:"permissions"`
+}
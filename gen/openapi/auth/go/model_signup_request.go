@@ -12,6 +12,7 @@ package authapi
 type SignupRequest struct {
 	Email string `json:"email"`
 
+	// Checked against the password policy configured with `PASSWORD_*`
 	Password string `json:"password"`
 
 	Username string `json:"username,omitempty"`
@@ -0,0 +1,22 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+type TokenResponse struct {
+	AccessToken string `json:"accessToken"`
+
+	// Replaces the refresh token that was exchanged
+	RefreshToken string `json:"refreshToken"`
+
+	TokenType string `json:"tokenType"`
+
+	// Lifetime of the access token in seconds
+	ExpiresIn int32 `json:"expiresIn"`
+}
@@ -0,0 +1,27 @@
+/*
+ * Auth API
+ *
+ * Routes derived from your Gin router.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package authapi
+
+import (
+	"time"
+)
+
+type UserIdentity struct {
+	Id string `json:"id"`
+
+	Provider string `json:"provider"`
+
+	// Email reported by the provider when the identity was linked
+	Email string `json:"email,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+}
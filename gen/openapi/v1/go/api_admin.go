@@ -0,0 +1,59 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AdminAPI struct {
+}
+
+// Delete /api/v1/admin/users/:userId
+// Soft-delete a user
+func (api *AdminAPI) DeleteUser(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/admin/users
+// Search and list users
+func (api *AdminAPI) ListUsers(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Put /api/v1/admin/users/:userId/lock
+// Lock a user
+func (api *AdminAPI) LockUser(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/admin/users/:userId/password-reset
+// Require a user to change their password
+func (api *AdminAPI) RequirePasswordReset(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/admin/users/:userId/restore
+// Restore a deleted user
+func (api *AdminAPI) RestoreUser(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/admin/users/:userId/lock
+// Unlock a user
+func (api *AdminAPI) UnlockUser(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
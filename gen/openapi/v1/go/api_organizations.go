@@ -0,0 +1,87 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationsAPI struct {
+}
+
+// Post /api/v1/invitations/accept
+// Accept an invitation
+func (api *OrganizationsAPI) AcceptInvitation(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/organizations
+// Create an organization owned by the current user
+func (api *OrganizationsAPI) CreateOrganization(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/invitations/decline
+// Decline an invitation
+func (api *OrganizationsAPI) DeclineInvitation(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/organizations/:organizationId/invitations
+// Invite someone to join an organization
+func (api *OrganizationsAPI) InviteMember(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/organizations/:organizationId/invitations
+// List the pending invitations of an organization
+func (api *OrganizationsAPI) ListInvitations(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/organizations/:organizationId/members
+// List the members of an organization
+func (api *OrganizationsAPI) ListMembers(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/organizations
+// List the organizations of the current user
+func (api *OrganizationsAPI) ListOrganizations(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/organizations/:organizationId/members/:userId
+// Remove a member from an organization
+func (api *OrganizationsAPI) RemoveMember(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/organizations/:organizationId/invitations/:invitationId
+// Revoke a pending invitation
+func (api *OrganizationsAPI) RevokeInvitation(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Put /api/v1/organizations/:organizationId/owner
+// Transfer the ownership of an organization
+func (api *OrganizationsAPI) TransferOwnership(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
@@ -0,0 +1,66 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyAPI struct {
+}
+
+// Delete /api/v1/users/me/deletion
+// Cancel the account deletion
+func (api *PrivacyAPI) CancelAccountDeletion(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/users/me/deletion/confirm
+// Confirm the account deletion
+func (api *PrivacyAPI) ConfirmAccountDeletion(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/data-export/download
+// Download a data export
+func (api *PrivacyAPI) DownloadDataExport(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/users/me/deletion
+// Get the account deletion requested by the current user
+func (api *PrivacyAPI) GetAccountDeletion(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/users/me/data-export
+// Get the latest data export of the current user
+func (api *PrivacyAPI) GetDataExport(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/users/me/deletion
+// Request the deletion of the account of the current user
+func (api *PrivacyAPI) RequestAccountDeletion(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Post /api/v1/users/me/data-export
+// Request an export of the personal data of the current user
+func (api *PrivacyAPI) RequestDataExport(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
@@ -0,0 +1,45 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileAPI struct {
+}
+
+// Get /api/v1/users/me/profile
+// Get the profile of the current user
+func (api *ProfileAPI) GetProfile(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Patch /api/v1/users/me/profile
+// Change the profile of the current user
+func (api *ProfileAPI) UpdateProfile(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Put /api/v1/users/me/profile/avatar
+// Upload the avatar of the current user
+func (api *ProfileAPI) UploadAvatar(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Delete /api/v1/users/me/profile/avatar
+// Remove the avatar of the current user
+func (api *ProfileAPI) DeleteAvatar(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
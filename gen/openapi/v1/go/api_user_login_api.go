@@ -17,7 +17,7 @@ type UserLoginAPIAPI struct {
 }
 
 // Get /api/v1/user/lookup
-// Get username by email
+// Get a user by email or username
 func (api *UserLoginAPIAPI) UserLookup(c *gin.Context) {
 	// Your handler implementation
 	c.JSON(200, gin.H{"status": "OK"})
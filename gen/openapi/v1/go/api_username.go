@@ -0,0 +1,31 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type UsernameAPI struct {
+}
+
+// Put /api/v1/users/me/username
+// Rename the current user
+func (api *UsernameAPI) ChangeUsername(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
+
+// Get /api/v1/users/me/username
+// Get the username of the current user and its changes
+func (api *UsernameAPI) GetUsername(c *gin.Context) {
+	// Your handler implementation
+	c.JSON(200, gin.H{"status": "OK"})
+}
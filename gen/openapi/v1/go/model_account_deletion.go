@@ -0,0 +1,30 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"time"
+)
+
+type AccountDeletion struct {
+
+	// Requests are scheduled once confirmed
+	Status string `json:"status"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	// End of the confirmation link
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
+
+	// When the account is erased, unless the deletion is cancelled before
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
+}
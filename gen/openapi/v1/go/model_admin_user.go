@@ -0,0 +1,37 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"time"
+)
+
+type AdminUser struct {
+	Id string `json:"id"`
+
+	Username string `json:"username"`
+
+	Email string `json:"email"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty"`
+
+	LockedAt *time.Time `json:"lockedAt,omitempty"`
+
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	// When the personal data of the deleted user was purged
+	PurgedAt *time.Time `json:"purgedAt,omitempty"`
+
+	PasswordResetRequired bool `json:"passwordResetRequired"`
+}
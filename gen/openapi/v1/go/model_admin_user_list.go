@@ -0,0 +1,17 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+type AdminUserList struct {
+	Users []AdminUser `json:"users"`
+
+	// Cursor of the next page, missing on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,23 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+// Avatar - Signed URLs of the thumbnails of the avatar, valid for at least half of `AVATARS_URL_TTL`. The same URLs are returned until then, so that browsers can cache the images.
+type Avatar struct {
+
+	// 64 × 64 pixels
+	Small string `json:"small"`
+
+	// 128 × 128 pixels
+	Medium string `json:"medium"`
+
+	// 256 × 256 pixels
+	Large string `json:"large"`
+}
@@ -0,0 +1,30 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"time"
+)
+
+type DataExport struct {
+	Id string `json:"id"`
+
+	Status string `json:"status"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	// End of the download link, once the export is ready
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Size of the archive in bytes, once the export is ready
+	Size int64 `json:"size,omitempty"`
+}
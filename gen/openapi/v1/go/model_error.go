@@ -10,5 +10,9 @@
 package v1api
 
 type Error struct {
+	Error string `json:"error,omitempty"`
+
 	Message string `json:"message"`
+
+	Details []FieldError `json:"details,omitempty"`
 }
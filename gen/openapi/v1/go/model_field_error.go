@@ -0,0 +1,20 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+type FieldError struct {
+
+	// Dot-separated path of the offending value
+	Field string `json:"field"`
+
+	In string `json:"in"`
+
+	Message string `json:"message"`
+}
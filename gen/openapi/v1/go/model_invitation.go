@@ -0,0 +1,28 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"time"
+)
+
+type Invitation struct {
+	Id string `json:"id"`
+
+	Email string `json:"email"`
+
+	Role MembershipRole `json:"role"`
+
+	InvitedBy string `json:"invitedBy"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	ExpiresAt time.Time `json:"expiresAt"`
+}
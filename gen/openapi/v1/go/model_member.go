@@ -0,0 +1,26 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"time"
+)
+
+type Member struct {
+	UserId string `json:"userId"`
+
+	Email string `json:"email"`
+
+	Username string `json:"username"`
+
+	Role MembershipRole `json:"role"`
+
+	JoinedAt time.Time `json:"joinedAt"`
+}
@@ -0,0 +1,19 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+type MembershipRole string
+
+// List of MembershipRole
+const (
+	OWNER  MembershipRole = "owner"
+	ADMIN  MembershipRole = "admin"
+	MEMBER MembershipRole = "member"
+)
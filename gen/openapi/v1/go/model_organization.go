@@ -0,0 +1,24 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"time"
+)
+
+type Organization struct {
+	Id string `json:"id"`
+
+	Name string `json:"name"`
+
+	Role MembershipRole `json:"role"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,26 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+type Profile struct {
+
+	// How the user is shown to others instead of the user name
+	DisplayName string `json:"displayName,omitempty"`
+
+	Bio string `json:"bio,omitempty"`
+
+	// BCP 47 language tag
+	Locale string `json:"locale,omitempty"`
+
+	// IANA time zone name
+	Timezone string `json:"timezone,omitempty"`
+
+	Avatar *Avatar `json:"avatar,omitempty"`
+}
@@ -0,0 +1,20 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+type ProfilePatch struct {
+	DisplayName *string `json:"displayName,omitempty"`
+
+	Bio *string `json:"bio,omitempty"`
+
+	Locale *string `json:"locale,omitempty"`
+
+	Timezone *string `json:"timezone,omitempty"`
+}
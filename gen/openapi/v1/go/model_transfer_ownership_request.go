@@ -0,0 +1,15 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+type TransferOwnershipRequest struct {
+	// Member becoming the owner
+	UserId string `json:"userId"`
+}
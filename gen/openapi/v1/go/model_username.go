@@ -0,0 +1,16 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+type Username struct {
+	Username string `json:"username"`
+
+	Changes []UsernameChange `json:"changes"`
+}
@@ -0,0 +1,25 @@
+/*
+ * example.com API
+ *
+ * Internal API for managing users, skills and projects.
+ *
+ * API version: 1.0.0
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package v1api
+
+import (
+	"time"
+)
+
+type UsernameChange struct {
+	OldUsername string `json:"oldUsername"`
+
+	NewUsername string `json:"newUsername"`
+
+	ChangedAt time.Time `json:"changedAt"`
+
+	// End of the time the old username leads to the user
+	RedirectExpiresAt time.Time `json:"redirectExpiresAt"`
+}
@@ -1,26 +1,61 @@
 package app
 
 import (
+	"slices"
+	"strings"
+
 	"go.uber.org/dig"
 	"gorm.io/gorm"
 
 	"example.com/internal/domain/repository"
+	adminservice "example.com/internal/domain/service/admin"
+	apitokenservice "example.com/internal/domain/service/apitoken"
 	authservice "example.com/internal/domain/service/auth"
+	avatarservice "example.com/internal/domain/service/avatar"
+	emailservice "example.com/internal/domain/service/email"
+	identityservice "example.com/internal/domain/service/identity"
+	oauthservice "example.com/internal/domain/service/oauth"
+	organizationservice "example.com/internal/domain/service/organization"
+	passwordservice "example.com/internal/domain/service/password"
+	passwordlessservice "example.com/internal/domain/service/passwordless"
+	policyservice "example.com/internal/domain/service/policy"
+	privacyservice "example.com/internal/domain/service/privacy"
+	profileservice "example.com/internal/domain/service/profile"
+	retentionservice "example.com/internal/domain/service/retention"
+	tokenservice "example.com/internal/domain/service/token"
+	usernameservice "example.com/internal/domain/service/username"
 	userservice "example.com/internal/domain/service/v1"
 	authusecase "example.com/internal/domain/usecase/auth"
 	userusecase "example.com/internal/domain/usecase/v1"
 	"example.com/internal/infrastructure/config"
 	"example.com/internal/infrastructure/database"
 	"example.com/internal/infrastructure/logger"
+	"example.com/internal/infrastructure/metrics"
 	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/authz"
+	"example.com/pkg/mail"
+	"example.com/pkg/oidc"
 	"example.com/pkg/security"
+	"example.com/pkg/storage"
 )
 
-func BuildContainer() (*dig.Container, error) {
+// BuildContainer wires the application, configured from the environment and args, the command
+// line flags of the server
+func BuildContainer(args []string) (*dig.Container, error) {
 	container := dig.New()
 
-	// Configuration
-	if err := container.Provide(config.Load); err != nil {
+	// Configuration, audited before anything connects with it
+	if err := container.Provide(func() (*config.Config, error) {
+		cfg, err := config.Load(args)
+		if err != nil {
+			return nil, err
+		}
+		if findings := config.Audit(cfg); cfg.Server.Env == "production" && len(findings) > 0 {
+			return nil, &config.AuditError{Findings: findings}
+		}
+		return cfg, nil
+	}); err != nil {
 		return nil, err
 	}
 
@@ -33,19 +68,28 @@ func BuildContainer() (*dig.Container, error) {
 
 	// Database
 	if err := container.Provide(func(cfg *config.Config) (*gorm.DB, error) {
+		var db *gorm.DB
+		var err error
 		if cfg.Database.URL != "" {
-			return database.ConnectFromURL(cfg.Database.URL)
+			db, err = database.ConnectFromURL(cfg.Database.URL)
+		} else {
+			db, err = database.Connect(database.Config{
+				Host:     cfg.Database.Host,
+				Port:     cfg.Database.Port,
+				User:     cfg.Database.User,
+				Password: cfg.Database.Password,
+				DBName:   cfg.Database.DBName,
+				SSLMode:  cfg.Database.SSLMode,
+			})
+		}
+		if err != nil || !cfg.Tenancy.Enabled {
+			return db, err
 		}
 
-		dbCfg := database.Config{
-			Host:     cfg.Database.Host,
-			Port:     cfg.Database.Port,
-			User:     cfg.Database.User,
-			Password: cfg.Database.Password,
-			DBName:   cfg.Database.DBName,
-			SSLMode:  cfg.Database.SSLMode,
+		if err := database.EnableTenancy(db); err != nil {
+			return nil, err
 		}
-		return database.Connect(dbCfg)
+		return db, nil
 	}); err != nil {
 		return nil, err
 	}
@@ -54,6 +98,57 @@ func BuildContainer() (*dig.Container, error) {
 	if err := container.Provide(security.NewBcryptHasher); err != nil {
 		return nil, err
 	}
+	if err := container.Provide(func(cfg *config.Config) (*security.JWTIssuer, error) {
+		return cfg.JWT.TokenIssuer()
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(cfg *config.Config, log logger.Logger) (mail.Sender, error) {
+		return cfg.Mail.Sender(log)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(cfg *config.Config) (storage.BlobStore, error) {
+		return cfg.Avatars.BlobStore()
+	}); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(cfg *config.Config) (*security.URLSigner, error) {
+		return cfg.Avatars.URLSigner(cfg.Security)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(cfg *config.Config) *oidc.Registry {
+		return cfg.OIDC.Registry(cfg.OpenAPI.PublicURL)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Metrics
+	if err := container.Provide(metrics.NewRegistry); err != nil {
+		return nil, err
+	}
+
+	// OpenAPI validation
+	if err := container.Provide(func(cfg *config.Config) (*middleware.OpenAPIValidator, error) {
+		authDoc, err := middleware.LoadOpenAPIDocument("auth")
+		if err != nil {
+			return nil, err
+		}
+		v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+		if err != nil {
+			return nil, err
+		}
+
+		return middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{
+			ValidateResponses: cfg.OpenAPI.ValidateResponses,
+		}, authDoc, v1Doc)
+	}); err != nil {
+		return nil, err
+	}
 
 	// Repositories
 	if err := container.Provide(func(db *gorm.DB) repository.UserRepository {
@@ -61,6 +156,63 @@ func BuildContainer() (*dig.Container, error) {
 	}); err != nil {
 		return nil, err
 	}
+	if err := container.Provide(database.NewAPITokenRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewRefreshTokenRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewUserIdentityRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewOAuthClientRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewOAuthAuthorizationCodeRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewOAuthConsentRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewOAuthAccessTokenRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewLoginChallengeRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewPasswordHistoryRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewRoleRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewUserProfileRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewOrganizationRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewInvitationRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewAuditEventRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewDataExportRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewAccountDeletionRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewPersonalDataRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewEmailChangeRepository); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(database.NewUserNameChangeRepository); err != nil {
+		return nil, err
+	}
 
 	// Services
 	if err := container.Provide(authservice.NewService); err != nil {
@@ -69,6 +221,214 @@ func BuildContainer() (*dig.Container, error) {
 	if err := container.Provide(userservice.NewService); err != nil {
 		return nil, err
 	}
+	if err := container.Provide(profileservice.NewService); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(
+		profileService profileservice.Service,
+		profileRepo repository.UserProfileRepository,
+		store storage.BlobStore,
+		cfg *config.Config,
+	) avatarservice.Service {
+		return avatarservice.NewService(profileService, profileRepo, store, avatarservice.Config{
+			MaxBytes:  cfg.Avatars.MaxUploadSize,
+			MaxPixels: cfg.Avatars.MaxPixels,
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(
+		userRepo repository.UserRepository,
+		changeRepo repository.UserNameChangeRepository,
+		auditRepo repository.AuditEventRepository,
+		cfg *config.Config,
+	) (usernameservice.Service, error) {
+		policy, err := cfg.Users.UsernamePolicy()
+		if err != nil {
+			return nil, err
+		}
+		return usernameservice.NewService(userRepo, changeRepo, auditRepo, usernameservice.Config{
+			Policy:         policy,
+			RedirectTTL:    cfg.Users.UsernameRedirectTTL,
+			RenameInterval: cfg.Users.UsernameRenameInterval,
+		}), nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(apitokenservice.NewService); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(identityservice.NewService); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(policyservice.NewService); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(
+		repo repository.RefreshTokenRepository, issuer *security.JWTIssuer, cfg *config.Config,
+	) tokenservice.Service {
+		return tokenservice.NewService(repo, issuer, cfg.JWT.RefreshTokenTTL)
+	}); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(
+		clientRepo repository.OAuthClientRepository,
+		codeRepo repository.OAuthAuthorizationCodeRepository,
+		consentRepo repository.OAuthConsentRepository,
+		tokenRepo repository.OAuthAccessTokenRepository,
+		userRepo repository.UserRepository,
+		issuer *security.JWTIssuer,
+		cfg *config.Config,
+	) oauthservice.Service {
+		return oauthservice.NewService(clientRepo, codeRepo, consentRepo, tokenRepo, userRepo, issuer, oauthservice.Config{
+			FirstPartyClients: cfg.OAuth.FirstPartyClients,
+			CodeTTL:           cfg.OAuth.CodeTTL,
+			AccessTokenTTL:    cfg.OAuth.AccessTokenTTL,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(
+		userRepo repository.UserRepository,
+		historyRepo repository.PasswordHistoryRepository,
+		refreshTokenRepo repository.RefreshTokenRepository,
+		apiTokenRepo repository.APITokenRepository,
+		oauthCodeRepo repository.OAuthAuthorizationCodeRepository,
+		oauthAccessTokenRepo repository.OAuthAccessTokenRepository,
+		hasher security.PasswordHasher,
+		cfg *config.Config,
+	) (passwordservice.Service, error) {
+		policy, err := cfg.Password.Policy()
+		if err != nil {
+			return nil, err
+		}
+		breachChecker, err := cfg.Password.BreachChecker()
+		if err != nil {
+			return nil, err
+		}
+		return passwordservice.NewService(
+			userRepo, historyRepo, refreshTokenRepo, apiTokenRepo, oauthCodeRepo, oauthAccessTokenRepo, hasher,
+			passwordservice.Config{
+				Policy:        policy,
+				BreachChecker: breachChecker,
+				HistorySize:   cfg.Password.HistorySize,
+			},
+		), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(
+		userRepo repository.UserRepository,
+		changeRepo repository.EmailChangeRepository,
+		refreshTokenRepo repository.RefreshTokenRepository,
+		auditRepo repository.AuditEventRepository,
+		hasher security.PasswordHasher,
+		sender mail.Sender,
+		cfg *config.Config,
+	) emailservice.Service {
+		return emailservice.NewService(userRepo, changeRepo, refreshTokenRepo, auditRepo, hasher, sender, emailservice.Config{
+			LinkURL:    cfg.Users.EmailChangeURL,
+			ConfirmTTL: cfg.Users.EmailChangeTTL,
+			RevertTTL:  cfg.Users.EmailRevertTTL,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(
+		challengeRepo repository.LoginChallengeRepository,
+		userRepo repository.UserRepository,
+		authService authservice.Service,
+		sender mail.Sender,
+		cfg *config.Config,
+	) passwordlessservice.Service {
+		return passwordlessservice.NewService(challengeRepo, userRepo, authService, sender, passwordlessservice.Config{
+			LinkURL:     cfg.Passwordless.LinkURL,
+			TTL:         cfg.Passwordless.TTL,
+			MaxAttempts: cfg.Passwordless.MaxAttempts,
+			MaxPerHour:  cfg.Passwordless.MaxPerHour,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(
+		organizationRepo repository.OrganizationRepository,
+		invitationRepo repository.InvitationRepository,
+		userRepo repository.UserRepository,
+		sender mail.Sender,
+		cfg *config.Config,
+	) organizationservice.Service {
+		return organizationservice.NewService(organizationRepo, invitationRepo, userRepo, sender, organizationservice.Config{
+			InviteURL: cfg.Organizations.InviteURL,
+			TTL:       cfg.Organizations.InvitationTTL,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(adminservice.NewService); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(
+		userRepo repository.UserRepository,
+		exportRepo repository.DataExportRepository,
+		store storage.BlobStore,
+		cfg *config.Config,
+	) retentionservice.Service {
+		return retentionservice.NewService(userRepo, exportRepo, store, retentionservice.Config{
+			DeletedRetention: cfg.Users.DeletedRetention,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	// Export archives are kept in the blob store of avatars, under their own prefix
+	if err := container.Provide(func(
+		userRepo repository.UserRepository,
+		organizationRepo repository.OrganizationRepository,
+		exportRepo repository.DataExportRepository,
+		deletionRepo repository.AccountDeletionRepository,
+		personalDataRepo repository.PersonalDataRepository,
+		auditRepo repository.AuditEventRepository,
+		retentionService retentionservice.Service,
+		store storage.BlobStore,
+		sender mail.Sender,
+		cfg *config.Config,
+	) privacyservice.Service {
+		return privacyservice.NewService(
+			userRepo, organizationRepo, exportRepo, deletionRepo, personalDataRepo, auditRepo, retentionService, store, sender,
+			privacyservice.Config{
+				DownloadURL: strings.TrimSuffix(cfg.OpenAPI.PublicURL, "/") + "/api/v1/data-export/download",
+				ExportTTL:   cfg.Privacy.ExportTTL,
+				ConfirmURL:  cfg.Privacy.DeletionConfirmURL,
+				ConfirmTTL:  cfg.Privacy.DeletionConfirmTTL,
+				CoolingOff:  cfg.Privacy.DeletionCoolingOff,
+			},
+		)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container.Provide(func(policyService policyservice.Service, log logger.Logger, cfg *config.Config) (authz.Authorizer, error) {
+		policies, err := cfg.Authz.Policies()
+		if err != nil {
+			return nil, err
+		}
+		authzConfig := authz.Config{
+			Attributes: policyService.SubjectAttributes,
+			Resources:  policyService.ResourceAttributes,
+		}
+		if cfg.Authz.LogDecisions {
+			authzConfig.Log = authz.LogDecisions(log)
+		}
+		return authz.NewAuthorizer(policies, authzConfig), nil
+	}); err != nil {
+		return nil, err
+	}
 
 	// Use Cases
 	if err := container.Provide(authusecase.NewSignupUseCase); err != nil {
@@ -77,9 +437,216 @@ func BuildContainer() (*dig.Container, error) {
 	if err := container.Provide(authusecase.NewLoginUseCase); err != nil {
 		return nil, err
 	}
+	if err := container.Provide(authusecase.NewChangePasswordUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewRequestEmailChangeUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewConfirmEmailChangeUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewRevertEmailChangeUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewValidateSessionUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewRecordLoginUseCase); err != nil {
+		return nil, err
+	}
 	if err := container.Provide(userusecase.NewUserLookupUseCase); err != nil {
 		return nil, err
 	}
+	if err := container.Provide(userusecase.NewUsernameLookupUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewGetUsernameUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewChangeUsernameUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewGetProfileUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewUpdateProfileUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewUploadAvatarUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewDeleteAvatarUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewOpenAvatarUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewCreateOrganizationUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewListOrganizationsUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewListMembersUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewRemoveMemberUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewTransferOwnershipUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewInviteMemberUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewListInvitationsUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewRevokeInvitationUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewAcceptInvitationUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewDeclineInvitationUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewListUsersUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewLockUserUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewUnlockUserUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewRequirePasswordResetUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewDeleteUserUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewRestoreUserUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewPurgeDeletedUsersUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewRequestDataExportUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewGetDataExportUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewDownloadDataExportUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewProcessDataExportsUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewRequestAccountDeletionUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewConfirmAccountDeletionUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewGetAccountDeletionUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewCancelAccountDeletionUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(userusecase.NewEraseDueAccountsUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewCheckPermissionUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewListRolesUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewListUserRolesUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewAssignRoleUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewUnassignRoleUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewCreateAPITokenUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewListAPITokensUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewRevokeAPITokenUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewAuthenticateAPITokenUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewIssueTokensUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewRefreshTokenUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewAuthenticateAccessTokenUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewGetJWKSUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewListOIDCProvidersUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewStartOIDCUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewOIDCLoginUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewStartPasswordlessLoginUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewPasswordlessLoginUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewLinkIdentityUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewListIdentitiesUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewUnlinkIdentityUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewRegisterOAuthClientUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewListOAuthClientsUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewDeleteOAuthClientUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewAuthorizeUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewConsentUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewOAuthTokenUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewIntrospectOAuthTokenUseCase); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(authusecase.NewRevokeOAuthTokenUseCase); err != nil {
+		return nil, err
+	}
 
 	// API Handlers
 	if err := container.Provide(api.NewAuthAPIHandler); err != nil {
@@ -88,6 +655,101 @@ func BuildContainer() (*dig.Container, error) {
 	if err := container.Provide(api.NewUserAPIHandler); err != nil {
 		return nil, err
 	}
+	if err := container.Provide(func(
+		get userusecase.GetProfileUseCase,
+		update userusecase.UpdateProfileUseCase,
+		uploadAvatar userusecase.UploadAvatarUseCase,
+		deleteAvatar userusecase.DeleteAvatarUseCase,
+		avatarURLs *security.URLSigner,
+		log logger.Logger,
+		cfg *config.Config,
+	) *api.ProfileAPIHandler {
+		return api.NewProfileAPIHandler(get, update, uploadAvatar, deleteAvatar, avatarURLs, log, cfg.OpenAPI.PublicURL)
+	}); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewUsernameAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewAvatarAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewAPITokenAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewTokenAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(
+		listProviders authusecase.ListOIDCProvidersUseCase,
+		start authusecase.StartOIDCUseCase,
+		login authusecase.OIDCLoginUseCase,
+		link authusecase.LinkIdentityUseCase,
+		listIdentities authusecase.ListIdentitiesUseCase,
+		unlink authusecase.UnlinkIdentityUseCase,
+		log logger.Logger,
+		cfg *config.Config,
+	) *api.OIDCAPIHandler {
+		return api.NewOIDCAPIHandler(listProviders, start, login, link, listIdentities, unlink, log, cfg.OIDC.RedirectURL)
+	}); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewPasswordlessAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewPasswordAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewEmailAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewRoleAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewOrganizationAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewAdminAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewPrivacyAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(
+		authorize authusecase.AuthorizeUseCase,
+		consent authusecase.ConsentUseCase,
+		token authusecase.OAuthTokenUseCase,
+		introspect authusecase.IntrospectOAuthTokenUseCase,
+		revoke authusecase.RevokeOAuthTokenUseCase,
+		issuer *security.JWTIssuer,
+		log logger.Logger,
+		cfg *config.Config,
+	) *api.OAuthAPIHandler {
+		settings := api.OAuthServerSettings{
+			Issuer:     cfg.JWT.Issuer,
+			BaseURL:    cfg.OpenAPI.PublicURL,
+			LoginURL:   cfg.OAuth.LoginURL,
+			ConsentURL: cfg.OAuth.ConsentURL,
+		}
+		if issuer != nil {
+			for _, key := range issuer.JWKS().Keys {
+				if !slices.Contains(settings.SigningAlgorithms, key.Algorithm) {
+					settings.SigningAlgorithms = append(settings.SigningAlgorithms, key.Algorithm)
+				}
+			}
+		}
+		return api.NewOAuthAPIHandler(authorize, consent, token, introspect, revoke, log, settings)
+	}); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(api.NewOAuthClientAPIHandler); err != nil {
+		return nil, err
+	}
+	if err := container.Provide(func(cfg *config.Config) (*api.OpenAPIHandler, error) {
+		return api.NewOpenAPIHandler(cfg.OpenAPI.PublicURL)
+	}); err != nil {
+		return nil, err
+	}
 
 	return container, nil
 }
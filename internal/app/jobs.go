@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"example.com/pkg/tenant"
+)
+
+// runJob calls run once the server starts and then every interval, until ctx is done. With
+// tenancy run is called for every tenant served in turn. The number of items run reports
+// having handled is logged with done, its failures with failed.
+func (s *Server) runJob(ctx context.Context, interval time.Duration, run func(context.Context) (int, error), done, failed string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, ctx := range s.tenantContexts(ctx) {
+			id, _ := tenant.FromContext(ctx)
+			count, err := run(ctx)
+			if count > 0 {
+				s.logger.Info(done, "count", count, "tenant", id)
+			}
+			if err != nil {
+				s.logger.Error(failed, "error", err.Error(), "tenant", id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tenantContexts returns ctx acting for each tenant served, or ctx alone without tenancy
+func (s *Server) tenantContexts(ctx context.Context) []context.Context {
+	if !s.config.Tenancy.Enabled {
+		return []context.Context{ctx}
+	}
+	contexts := make([]context.Context, len(s.config.Tenancy.Tenants))
+	for i, id := range s.config.Tenancy.Tenants {
+		contexts[i] = tenant.NewContext(ctx, id)
+	}
+	return contexts
+}
@@ -0,0 +1,378 @@
+package app
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/interfaces/middleware"
+)
+
+// legacyUsageMaxSeries bounds the number of route/client pairs tracked for legacy routes
+const legacyUsageMaxSeries = 1000
+
+// multipartOverhead is the room left for the boundaries and part headers of an upload on top
+// of the size of the file itself
+const multipartOverhead = 16 << 10
+
+// csrfExemptPaths authenticate with a token or client credentials in the request instead of a
+// cookie, so a cross-site request cannot use them on behalf of a user
+var csrfExemptPaths = []string{"/api/v1/auth/token/refresh", "/oauth2/token", "/oauth2/introspect", "/oauth2/revoke"}
+
+// tenantPaths read or write the data of a tenant and need one when tenancy is enabled. The
+// documents, metrics and discovery routes outside them serve every tenant alike.
+var tenantPaths = []string{"/api/", "/oauth2/", "/auth/"}
+
+// apiVersion mounts the routes of one version of the API under /api/<name>
+type apiVersion struct {
+	mount func(group *gin.RouterGroup, cfg *config.Config, handlers Handlers)
+	name  string
+}
+
+// apiVersions lists every version served side by side. A new version gets its own
+// mount function here and reuses handlers from previous versions where nothing changed.
+var apiVersions = []apiVersion{
+	{name: "v1", mount: mountV1},
+}
+
+func setupRoutes(engine *gin.Engine, cfg *config.Config, handlers Handlers) {
+	// CSRF token endpoint
+	engine.GET("/csrf-token", handlers.Validator.Operation("getCsrfToken"), middleware.CSRFToken())
+
+	if cfg.JWT.Enabled() && handlers.Tokens != nil {
+		engine.GET("/.well-known/jwks.json", handlers.Validator.Operation("getJwks"), handlers.Tokens.GetJwks)
+	}
+
+	if cfg.OAuth.Enabled && handlers.OAuth != nil {
+		mountOAuthServer(engine, handlers)
+	}
+
+	// Pages load avatars without credentials, so their URLs are signed instead and they are
+	// served outside tenantPaths
+	if handlers.Avatars != nil {
+		engine.GET("/avatars/:userId/:file", handlers.Validator.Operation("getAvatar"), handlers.Avatars.GetAvatar)
+	}
+
+	// Versioned API routes
+	api := engine.Group("/api")
+	for _, version := range apiVersions {
+		group := api.Group("/" + version.name)
+		group.Use(apiVersionHeader(version.name))
+		version.mount(group, cfg, handlers)
+	}
+
+	mountLegacy(engine, cfg.Legacy, handlers)
+}
+
+func mountV1(v1 *gin.RouterGroup, cfg *config.Config, handlers Handlers) {
+	validator := handlers.Validator
+
+	// Routes with XSRF protection
+	auth := v1.Group("/auth")
+	auth.Use(middleware.RequireXSRF())
+	{
+		auth.POST("/signup", validator.Operation("userSignup"), handlers.Auth.UserSignup)
+		auth.POST("/login", validator.Operation("userLogin"), handlers.Auth.UserLogin)
+	}
+
+	// The refresh token in the body authenticates the request, see csrfExemptPaths
+	if cfg.JWT.Enabled() && handlers.Tokens != nil {
+		v1.POST("/auth/token/refresh", validator.Operation("refreshAccessToken"), handlers.Tokens.RefreshAccessToken)
+	}
+
+	// Changing the password checks the current one and revokes the other sessions of the user,
+	// so it is done from a browser session
+	if handlers.Password != nil {
+		password := v1.Group("/auth/password")
+		password.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+		{
+			password.POST("/change", validator.Operation("changePassword"), handlers.Password.ChangePassword)
+		}
+	}
+
+	// Email changes are requested from a browser session. The emailed links confirm or revert
+	// them from any device, authenticated by their token.
+	if handlers.Email != nil {
+		email := v1.Group("/auth/email")
+		email.Use(middleware.RequireXSRF())
+		{
+			email.POST("/change", middleware.RequireSessionAuth(), validator.Operation("requestEmailChange"),
+				handlers.Email.RequestEmailChange)
+			email.POST("/confirm", validator.Operation("confirmEmailChange"), handlers.Email.ConfirmEmailChange)
+			email.POST("/revert", validator.Operation("revertEmailChange"), handlers.Email.RevertEmailChange)
+		}
+	}
+
+	// Token management needs a browser session so that a leaked token cannot mint new ones
+	tokens := v1.Group("/auth/tokens")
+	tokens.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		tokens.GET("", validator.Operation("listApiTokens"), handlers.APITokens.ListApiTokens)
+		tokens.POST("", validator.Operation("createApiToken"), handlers.APITokens.CreateApiToken)
+		tokens.DELETE("/:tokenId", validator.Operation("revokeApiToken"), handlers.APITokens.RevokeApiToken)
+	}
+
+	if handlers.Roles != nil {
+		mountRoles(v1, handlers)
+	}
+
+	if handlers.Organizations != nil {
+		mountOrganizations(v1, handlers)
+	}
+
+	if handlers.Admin != nil {
+		mountAdmin(v1, handlers)
+	}
+
+	if handlers.Privacy != nil {
+		mountPrivacy(v1, handlers)
+	}
+
+	if handlers.OIDC != nil {
+		mountOIDC(v1, handlers)
+	}
+
+	if cfg.Passwordless.Enabled && handlers.Passwordless != nil {
+		passwordless := v1.Group("/auth/passwordless")
+		passwordless.Use(middleware.RequireXSRF())
+		{
+			passwordless.POST("/start", validator.Operation("startPasswordlessLogin"), handlers.Passwordless.StartPasswordlessLogin)
+			passwordless.POST("/verify", validator.Operation("verifyPasswordlessLogin"), handlers.Passwordless.VerifyPasswordlessLogin)
+		}
+	}
+
+	if cfg.OAuth.Enabled && handlers.OAuth != nil && handlers.OAuthClients != nil {
+		mountOAuthServerAPI(v1, handlers)
+	}
+
+	user := v1.Group("/user")
+	user.Use(middleware.RequireXSRF())
+	{
+		// Whether the user may read the user found is decided by the authorization policies
+		user.GET("/lookup",
+			middleware.RequireAuth(),
+			middleware.RequireScope(entity.ScopeUsersRead),
+			validator.Operation("userLookup"),
+			handlers.User.UserLookup,
+		)
+	}
+
+	if handlers.Profile != nil {
+		profile := v1.Group("/users/me/profile")
+		profile.Use(middleware.RequireXSRF(), middleware.RequireAuth())
+		{
+			profile.GET("", middleware.RequireScope(entity.ScopeProfileRead), validator.Operation("getProfile"), handlers.Profile.GetProfile)
+			profile.PATCH("",
+				middleware.RequireScope(entity.ScopeProfileWrite),
+				validator.Operation("updateProfile"),
+				handlers.Profile.UpdateProfile,
+			)
+			profile.PUT("/avatar",
+				middleware.RequireScope(entity.ScopeProfileWrite),
+				middleware.LimitBody(int64(cfg.Avatars.MaxUploadSize)+multipartOverhead),
+				validator.Operation("uploadAvatar"),
+				handlers.Profile.UploadAvatar,
+			)
+			profile.DELETE("/avatar",
+				middleware.RequireScope(entity.ScopeProfileWrite),
+				validator.Operation("deleteAvatar"),
+				handlers.Profile.DeleteAvatar,
+			)
+		}
+	}
+
+	if handlers.Username != nil {
+		// Renames need a browser session so that a leaked token cannot give the name away
+		username := v1.Group("/users/me/username")
+		username.Use(middleware.RequireXSRF())
+		{
+			username.GET("",
+				middleware.RequireAuth(),
+				middleware.RequireScope(entity.ScopeProfileRead),
+				validator.Operation("getUsername"),
+				handlers.Username.GetUsername,
+			)
+			username.PUT("", middleware.RequireSessionAuth(), validator.Operation("changeUsername"), handlers.Username.ChangeUsername)
+		}
+	}
+}
+
+// mountRoles serves role management: reading roles needs roles:read and changing assignments
+// roles:assign. Assignments grant permissions, so API tokens cannot change them.
+func mountRoles(v1 *gin.RouterGroup, handlers Handlers) {
+	validator := handlers.Validator
+	readRoles := middleware.RequirePermission(entity.PermissionRolesRead)
+	assignRoles := middleware.RequirePermission(entity.PermissionRolesAssign)
+
+	roles := v1.Group("/auth")
+	roles.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		roles.GET("/roles", readRoles, validator.Operation("listRoles"), handlers.Roles.ListRoles)
+		roles.GET("/users/:userId/roles", readRoles, validator.Operation("listUserRoles"), handlers.Roles.ListUserRoles)
+		roles.PUT("/users/:userId/roles/:role", assignRoles, validator.Operation("assignRole"), handlers.Roles.AssignRole)
+		roles.DELETE("/users/:userId/roles/:role", assignRoles, validator.Operation("unassignRole"), handlers.Roles.UnassignRole)
+	}
+}
+
+// mountAdmin serves account management to support staff: listing users needs users:read and
+// deleting, locking or resetting accounts users:manage. Staff act on the accounts of others from
+// their session only, never with an API token.
+func mountAdmin(v1 *gin.RouterGroup, handlers Handlers) {
+	validator := handlers.Validator
+	h := handlers.Admin
+	readUsers := middleware.RequirePermission(entity.PermissionUsersRead)
+	manageUsers := middleware.RequirePermission(entity.PermissionUsersManage)
+
+	users := v1.Group("/admin/users")
+	users.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		users.GET("", readUsers, validator.Operation("listUsers"), h.ListUsers)
+		users.DELETE("/:userId", manageUsers, validator.Operation("deleteUser"), h.DeleteUser)
+		users.POST("/:userId/restore", manageUsers, validator.Operation("restoreUser"), h.RestoreUser)
+		users.PUT("/:userId/lock", manageUsers, validator.Operation("lockUser"), h.LockUser)
+		users.DELETE("/:userId/lock", manageUsers, validator.Operation("unlockUser"), h.UnlockUser)
+		users.POST("/:userId/password-reset", manageUsers, validator.Operation("requirePasswordReset"), h.RequirePasswordReset)
+	}
+}
+
+// mountPrivacy serves the data subject requests of the logged in user. Exports and deletions
+// concern the whole account, beyond the scopes of any API token, so users make them from their
+// session. Downloading an export is the exception: the link emailed to the user authenticates
+// it, and the browser navigates to it without an XSRF token.
+func mountPrivacy(v1 *gin.RouterGroup, handlers Handlers) {
+	validator := handlers.Validator
+	h := handlers.Privacy
+
+	me := v1.Group("/users/me")
+	me.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		me.GET("/data-export", validator.Operation("getDataExport"), h.GetDataExport)
+		me.POST("/data-export", validator.Operation("requestDataExport"), h.RequestDataExport)
+		me.GET("/deletion", validator.Operation("getAccountDeletion"), h.GetAccountDeletion)
+		me.POST("/deletion", validator.Operation("requestAccountDeletion"), h.RequestAccountDeletion)
+		me.DELETE("/deletion", validator.Operation("cancelAccountDeletion"), h.CancelAccountDeletion)
+		me.POST("/deletion/confirm", validator.Operation("confirmAccountDeletion"), h.ConfirmAccountDeletion)
+	}
+
+	v1.GET("/data-export/download", validator.Operation("downloadDataExport"), h.DownloadDataExport)
+}
+
+// mountOrganizations serves organizations to their members. The role of the member in the
+// organization decides what they may do, so the routes only need a browser session.
+func mountOrganizations(v1 *gin.RouterGroup, handlers Handlers) {
+	validator := handlers.Validator
+	h := handlers.Organizations
+
+	organizations := v1.Group("/organizations")
+	organizations.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		organizations.GET("", validator.Operation("listOrganizations"), h.ListOrganizations)
+		organizations.POST("", validator.Operation("createOrganization"), h.CreateOrganization)
+		organizations.GET("/:organizationId/members", validator.Operation("listMembers"), h.ListMembers)
+		organizations.DELETE("/:organizationId/members/:userId", validator.Operation("removeMember"), h.RemoveMember)
+		organizations.PUT("/:organizationId/owner", validator.Operation("transferOwnership"), h.TransferOwnership)
+		organizations.GET("/:organizationId/invitations", validator.Operation("listInvitations"), h.ListInvitations)
+		organizations.POST("/:organizationId/invitations", validator.Operation("inviteMember"), h.InviteMember)
+		organizations.DELETE("/:organizationId/invitations/:invitationId", validator.Operation("revokeInvitation"), h.RevokeInvitation)
+	}
+
+	invitations := v1.Group("/invitations")
+	invitations.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		invitations.POST("/accept", validator.Operation("acceptInvitation"), h.AcceptInvitation)
+		invitations.POST("/decline", validator.Operation("declineInvitation"), h.DeclineInvitation)
+	}
+}
+
+// mountOIDC serves sign-in with external providers. The browser navigates to these routes, so
+// they carry no XSRF token; the state of each flow is bound to the session instead.
+func mountOIDC(v1 *gin.RouterGroup, handlers Handlers) {
+	validator := handlers.Validator
+
+	oidc := v1.Group("/auth/oidc")
+	{
+		oidc.GET("/providers", validator.Operation("listOidcProviders"), handlers.OIDC.ListOidcProviders)
+		oidc.GET("/:provider/login", validator.Operation("startOidcLogin"), handlers.OIDC.StartOidcLogin)
+		oidc.GET("/:provider/link", middleware.RequireSessionAuth(), validator.Operation("startOidcLink"), handlers.OIDC.StartOidcLink)
+		oidc.GET("/:provider/callback", validator.Operation("oidcCallback"), handlers.OIDC.OidcCallback)
+	}
+
+	identities := v1.Group("/auth/identities")
+	identities.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		identities.GET("", validator.Operation("listIdentities"), handlers.OIDC.ListIdentities)
+		identities.DELETE("/:identityId", validator.Operation("unlinkIdentity"), handlers.OIDC.UnlinkIdentity)
+	}
+}
+
+// mountOAuthServer serves the protocol endpoints of the authorization server. Browsers navigate
+// to the authorization endpoint; the other endpoints authenticate clients, see csrfExemptPaths.
+func mountOAuthServer(engine *gin.Engine, handlers Handlers) {
+	validator := handlers.Validator
+
+	engine.GET("/.well-known/openid-configuration", validator.Operation("getOpenIdConfiguration"), handlers.OAuth.GetOpenIdConfiguration)
+
+	oauth := engine.Group("/oauth2")
+	{
+		oauth.GET("/authorize", validator.Operation("oauthAuthorize"), handlers.OAuth.OauthAuthorize)
+		oauth.POST("/token", validator.Operation("oauthToken"), handlers.OAuth.OauthToken)
+		oauth.POST("/introspect", validator.Operation("oauthIntrospect"), handlers.OAuth.OauthIntrospect)
+		oauth.POST("/revoke", validator.Operation("oauthRevoke"), handlers.OAuth.OauthRevoke)
+	}
+}
+
+// mountOAuthServerAPI serves the consent page and client registration to logged in users
+func mountOAuthServerAPI(v1 *gin.RouterGroup, handlers Handlers) {
+	validator := handlers.Validator
+
+	oauth := v1.Group("/oauth2")
+	oauth.Use(middleware.RequireSessionAuth(), middleware.RequireXSRF())
+	{
+		oauth.GET("/consent", validator.Operation("getOAuthConsent"), handlers.OAuth.GetOAuthConsent)
+		oauth.POST("/consent", validator.Operation("submitOAuthConsent"), handlers.OAuth.SubmitOAuthConsent)
+		oauth.GET("/clients", validator.Operation("listOAuthClients"), handlers.OAuthClients.ListOAuthClients)
+		oauth.POST("/clients", validator.Operation("createOAuthClient"), handlers.OAuthClients.CreateOAuthClient)
+		oauth.DELETE("/clients/:clientId", validator.Operation("deleteOAuthClient"), handlers.OAuthClients.DeleteOAuthClient)
+	}
+}
+
+// mountLegacy serves the unversioned auth routes kept for backward compatibility.
+// They announce their deprecation and successor so clients can migrate before the sunset.
+func mountLegacy(engine *gin.Engine, cfg config.LegacyAPIConfig, handlers Handlers) {
+	validator := handlers.Validator
+	usage := handlers.Metrics.CounterVec(
+		"legacy_api_requests_total",
+		"Requests served by deprecated unversioned routes, by route and client.",
+		legacyUsageMaxSeries,
+		"route", "client",
+	)
+	policy := middleware.DeprecationPolicy{
+		DeprecatedAt:      cfg.DeprecatedAt,
+		Sunset:            cfg.Sunset,
+		DocsURL:           cfg.DocsURL,
+		RejectAfterSunset: cfg.RejectAfterSunset,
+	}
+
+	legacyAuth := engine.Group("/auth/user")
+	{
+		legacyAuth.POST("/signup",
+			middleware.Deprecated(policy, "/api/v1/auth/signup", usage),
+			middleware.RequireXSRF(),
+			validator.Operation("legacyUserSignup"),
+			handlers.Auth.UserSignup,
+		)
+		legacyAuth.POST("/login",
+			middleware.Deprecated(policy, "/api/v1/auth/login", usage),
+			middleware.RequireXSRF(),
+			validator.Operation("legacyUserLogin"),
+			handlers.Auth.UserLogin,
+		)
+	}
+}
+
+func apiVersionHeader(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("API-Version", version)
+		c.Next()
+	}
+}
@@ -1,16 +1,20 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/dig"
 
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
 	"example.com/internal/infrastructure/config"
 	"example.com/internal/infrastructure/logger"
+	"example.com/internal/infrastructure/metrics"
 	"example.com/internal/interfaces/api"
 	"example.com/internal/interfaces/middleware"
 )
@@ -19,28 +23,96 @@ type Server struct {
 	engine *gin.Engine
 	config *config.Config
 	logger logger.Logger
+	jobs   Jobs
+}
+
+// Jobs groups the use cases the server runs in the background, see Run
+type Jobs struct {
+	dig.In
+
+	PurgeDeletedUsers  userusecase.PurgeDeletedUsersUseCase
+	ProcessDataExports userusecase.ProcessDataExportsUseCase
+	EraseDueAccounts   userusecase.EraseDueAccountsUseCase
+}
+
+// Handlers groups the handlers and route middleware mounted by the router
+type Handlers struct {
+	dig.In
+
+	Validator     *middleware.OpenAPIValidator
+	Auth          *api.AuthAPIHandler
+	User          *api.UserAPIHandler
+	Profile       *api.ProfileAPIHandler
+	Username      *api.UsernameAPIHandler
+	Avatars       *api.AvatarAPIHandler
+	APITokens     *api.APITokenAPIHandler
+	Tokens        *api.TokenAPIHandler
+	OIDC          *api.OIDCAPIHandler
+	Passwordless  *api.PasswordlessAPIHandler
+	Password      *api.PasswordAPIHandler
+	Email         *api.EmailAPIHandler
+	Roles         *api.RoleAPIHandler
+	Organizations *api.OrganizationAPIHandler
+	Admin         *api.AdminAPIHandler
+	Privacy       *api.PrivacyAPIHandler
+	OAuth         *api.OAuthAPIHandler
+	OAuthClients  *api.OAuthClientAPIHandler
+	OpenAPI       *api.OpenAPIHandler
+	Metrics       *metrics.Registry
+	// AuthenticateToken and AuthenticateAccessToken enable bearer authentication with personal
+	// access tokens and JWT access tokens; routers built without them only accept sessions
+	AuthenticateToken       authusecase.AuthenticateAPITokenUseCase    `optional:"true"`
+	AuthenticateAccessToken authusecase.AuthenticateAccessTokenUseCase `optional:"true"`
+	// ValidateSession ends sessions revoked by a password change; without it sessions are
+	// trusted until they expire
+	ValidateSession authusecase.ValidateSessionUseCase `optional:"true"`
+	// CheckPermission decides RequirePermission; without it routes requiring a permission refuse
+	// every request
+	CheckPermission authusecase.CheckPermissionUseCase `optional:"true"`
+	// RecordLogin adds every login to the audit events of the user, reporting failures to Logger
+	RecordLogin authusecase.RecordLoginUseCase `optional:"true"`
+	Logger      logger.Logger                  `optional:"true"`
 }
 
 func NewServer(container *dig.Container) (*Server, error) {
 	var cfg *config.Config
 	var log logger.Logger
-	var authAPIHandler *api.AuthAPIHandler
-	var userAPIHandler *api.UserAPIHandler
-
-	if err := container.Invoke(func(
-		c *config.Config,
-		l logger.Logger,
-		aah *api.AuthAPIHandler,
-		uah *api.UserAPIHandler,
-	) {
+	var handlers Handlers
+	var jobs Jobs
+
+	if err := container.Invoke(func(c *config.Config, l logger.Logger, h Handlers, j Jobs) {
 		cfg = c
 		log = l
-		authAPIHandler = aah
-		userAPIHandler = uah
+		handlers = h
+		jobs = j
 	}); err != nil {
 		return nil, fmt.Errorf("failed to resolve dependencies: %w", err)
 	}
 
+	// Production refuses to start with findings (see BuildContainer), elsewhere they are reported
+	for _, finding := range config.Audit(cfg) {
+		log.Warn("Insecure configuration", "key", finding.Path, "issue", finding.Message)
+	}
+
+	engine, err := NewRouter(cfg, handlers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		engine: engine,
+		config: cfg,
+		logger: log,
+		jobs:   jobs,
+	}, nil
+}
+
+// NewRouter builds the gin engine with every middleware and route registered
+func NewRouter(cfg *config.Config, handlers Handlers) (*gin.Engine, error) {
+	if handlers.Metrics == nil {
+		handlers.Metrics = metrics.NewRegistry()
+	}
+
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -48,77 +120,120 @@ func NewServer(container *dig.Container) (*Server, error) {
 	engine := gin.New()
 	engine.Use(gin.Recovery())
 
-	// CORS middleware for Swagger UI
-	if cfg.Server.Env != "production" {
+	// CORS middleware for Swagger UI and browser clients on other origins
+	if origins := cfg.Server.CORSAllowedOrigins; len(origins) > 0 {
 		corsConfig := cors.DefaultConfig()
-		corsConfig.AllowAllOrigins = true
+		if slices.Contains(origins, "*") {
+			corsConfig.AllowAllOrigins = true
+		} else {
+			corsConfig.AllowOrigins = origins
+		}
 		corsConfig.AllowHeaders = []string{
 			"Origin", "Content-Type", "Accept", "X-XSRF-TOKEN",
 			"X-Requested-With", "X-CSRF-Token", "Authorization",
 		}
 		corsConfig.AllowCredentials = true
 		corsConfig.ExposeHeaders = []string{"Set-Cookie", "X-CSRF-Token"}
-		corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+		corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
 		engine.Use(cors.New(corsConfig))
 	}
 
 	// Middleware
-	engine.Use(middleware.Session(cfg.Security.SessionSecret))
-	engine.Use(middleware.CSRF(cfg.Security.CSRFSecret))
+	sessionSigning, err := cfg.Security.SessionSigningRing()
+	if err != nil {
+		return nil, fmt.Errorf("invalid session signing keys: %w", err)
+	}
+	sessionEncryption, err := cfg.Security.SessionEncryptionRing()
+	if err != nil {
+		return nil, fmt.Errorf("invalid session encryption keys: %w", err)
+	}
+	csrfKeys, err := cfg.Security.CSRFRing()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSRF keys: %w", err)
+	}
+	if cfg.Tenancy.Enabled {
+		resolver, err := cfg.Tenancy.Resolver()
+		if err != nil {
+			return nil, fmt.Errorf("invalid tenancy: %w", err)
+		}
+		engine.Use(middleware.ResolveTenant(resolver))
+	}
+	engine.Use(middleware.Session(sessionSigning, sessionEncryption, cfg.Security.CookieSecure))
+	if handlers.ValidateSession != nil {
+		engine.Use(middleware.ValidateSession(handlers.ValidateSession))
+	}
+	if handlers.AuthenticateToken != nil || handlers.AuthenticateAccessToken != nil {
+		engine.Use(middleware.BearerAuth(handlers.AuthenticateToken, handlers.AuthenticateAccessToken))
+	}
+	if cfg.Tenancy.Enabled {
+		engine.Use(middleware.RequireTenant(tenantPaths...))
+	}
+	engine.Use(middleware.CSRF(csrfKeys, csrfExemptPaths...))
+	if handlers.CheckPermission != nil {
+		engine.Use(middleware.Permissions(handlers.CheckPermission))
+	}
+	if handlers.RecordLogin != nil && handlers.Logger != nil {
+		engine.Use(middleware.Audit(handlers.RecordLogin, handlers.Logger))
+	}
 
 	// Routes
-	setupRoutes(engine, authAPIHandler, userAPIHandler)
+	if err := setupDocsRoutes(engine, cfg, handlers.OpenAPI); err != nil {
+		return nil, err
+	}
+	if err := setupMetricsRoutes(engine, cfg, handlers.Metrics); err != nil {
+		return nil, err
+	}
+	setupRoutes(engine, cfg, handlers)
 
-	return &Server{
-		engine: engine,
-		config: cfg,
-		logger: log,
-	}, nil
+	return engine, nil
 }
 
 func (s *Server) Run() error {
 	addr := ":" + s.config.Server.Port
 	s.logger.Info("Starting server", "address", addr, "env", s.config.Server.Env)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.runJob(ctx, s.config.Users.PurgeInterval, s.jobs.PurgeDeletedUsers.Call, "Purged deleted users", "Failed to purge deleted users")
+	go s.runJob(ctx, s.config.Privacy.JobInterval, s.jobs.ProcessDataExports.Call, "Built data exports", "Failed to build data exports")
+	go s.runJob(ctx, s.config.Privacy.JobInterval, s.jobs.EraseDueAccounts.Call, "Erased accounts", "Failed to erase accounts")
+
 	return s.engine.Run(addr)
 }
 
-func setupRoutes(engine *gin.Engine, authAPIHandler *api.AuthAPIHandler, userAPIHandler *api.UserAPIHandler) {
-	// Serve OpenAPI specs first
-	engine.Static("/api/auth", "./api/auth")
-	engine.Static("/api/v1", "./api/v1")
-
-	// Swagger UI endpoints
-	engine.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/api/auth/openapi.yaml")))
-
-	// CSRF token endpoint
-	engine.GET("/csrf-token", middleware.CSRFToken())
-
-	// API routes with XSRF protection
-	api := engine.Group("/api")
-	{
-		v1 := api.Group("/v1")
-		{
-			auth := v1.Group("/auth")
-			auth.Use(middleware.RequireXSRF())
-			{
-				auth.POST("/signup", authAPIHandler.UserSignup)
-				auth.POST("/login", authAPIHandler.UserLogin)
-			}
-
-			user := v1.Group("/user")
-			user.Use(middleware.RequireXSRF())
-			{
-				user.GET("/lookup", userAPIHandler.UserLookup)
-			}
-		}
+// setupMetricsRoutes serves the metrics, which reveal routes and clients, behind basic auth when
+// credentials are configured, as Prometheus scrapes them
+func setupMetricsRoutes(engine *gin.Engine, cfg *config.Config, registry *metrics.Registry) error {
+	if !cfg.Metrics.Enabled {
+		return nil
 	}
 
-	// Legacy auth routes for backward compatibility
-	legacyAuth := engine.Group("/auth/user")
-	legacyAuth.Use(middleware.RequireXSRF())
-	{
-		legacyAuth.POST("/signup", authAPIHandler.UserSignup)
-		legacyAuth.POST("/login", authAPIHandler.UserLogin)
+	handlers := []gin.HandlerFunc{gin.WrapH(registry.Handler())}
+	if cfg.Metrics.Username != "" && cfg.Metrics.Password != "" {
+		handlers = append([]gin.HandlerFunc{gin.BasicAuth(gin.Accounts{cfg.Metrics.Username: cfg.Metrics.Password})}, handlers...)
+	} else if cfg.Server.Env == "production" {
+		return errors.New("metrics must be disabled or protected with METRICS_USERNAME and METRICS_PASSWORD in production")
 	}
+
+	engine.GET("/metrics", handlers...)
+	return nil
+}
+
+func setupDocsRoutes(engine *gin.Engine, cfg *config.Config, openAPIHandler *api.OpenAPIHandler) error {
+	if !cfg.OpenAPI.DocsEnabled {
+		return nil
+	}
+
+	docs := engine.Group("")
+	if cfg.OpenAPI.DocsUsername != "" && cfg.OpenAPI.DocsPassword != "" {
+		docs.Use(gin.BasicAuth(gin.Accounts{cfg.OpenAPI.DocsUsername: cfg.OpenAPI.DocsPassword}))
+	} else if cfg.Server.Env == "production" {
+		return errors.New("API docs must be disabled or protected with OPENAPI_DOCS_USERNAME and OPENAPI_DOCS_PASSWORD in production")
+	}
+
+	// OpenAPI specs and Swagger UI
+	docs.GET("/openapi/:file", openAPIHandler.Spec)
+	docs.GET("/docs/*any", openAPIHandler.Docs)
+
+	return nil
 }
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+)
+
+// APITokenPrefix starts every personal access token
+const APITokenPrefix = "pat_"
+
+// API token scopes. Requests authenticated with a token may only reach routes requiring
+// one of its scopes; browser sessions are not restricted by scopes.
+const (
+	ScopeUsersRead    = "users:read"
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+)
+
+// APITokenScopes lists every scope a token can be granted
+var APITokenScopes = []string{ScopeUsersRead, ScopeProfileRead, ScopeProfileWrite}
+
+// APIToken is a personal access token letting scripts and integrations call the API on
+// behalf of a user without a browser session. Only the hash of the token is stored.
+type APIToken struct {
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ID         string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID   string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID     string     `gorm:"type:char(36);not null;index" json:"user_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	// Hint holds the first characters of the token so that users can tell their tokens apart
+	Hint      string   `gorm:"size:16;not null" json:"hint"`
+	TokenHash string   `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes    []string `gorm:"serializer:json;type:jsonb;not null" json:"scopes"`
+}
+
+func (t *APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// Active reports whether the token can still authenticate requests at the given time
+func (t *APIToken) Active(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// HasScope reports whether the token was granted scope
+func (t *APIToken) HasScope(scope string) bool {
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
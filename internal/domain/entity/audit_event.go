@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+)
+
+// Actions of audit events
+const (
+	AuditLogin                    = "login"
+	AuditDataExportRequested      = "data_export.requested"
+	AuditAccountDeletionRequested = "account_deletion.requested"
+	AuditAccountDeletionConfirmed = "account_deletion.confirmed"
+	AuditAccountDeletionCancelled = "account_deletion.cancelled"
+	AuditEmailChangeRequested     = "email_change.requested"
+	AuditEmailChangeConfirmed     = "email_change.confirmed"
+	AuditEmailChangeReverted      = "email_change.reverted"
+	AuditUserNameChanged          = "user_name.changed"
+)
+
+// AuditEvent records something that happened to the account of a user, which users get to
+// see in the export of their data
+type AuditEvent struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID        string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID  string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID    string    `gorm:"type:char(36);not null;index" json:"user_id"`
+	Action    string    `gorm:"size:50;not null" json:"action"`
+	// Detail tells more about the action, like the login method
+	Detail    string `gorm:"size:100;not null;default:''" json:"detail,omitempty"`
+	IPAddress string `gorm:"size:45;not null;default:''" json:"ip_address,omitempty"`
+	UserAgent string `gorm:"size:255;not null;default:''" json:"user_agent,omitempty"`
+}
+
+func (e *AuditEvent) TableName() string {
+	return "audit_events"
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+)
+
+// Token prefixes of the links of email changes
+const (
+	EmailConfirmPrefix = "ecc_"
+	EmailRevertPrefix  = "ecr_"
+)
+
+// EmailChange moves a user from OldEmail to NewEmail. The user keeps OldEmail until they follow
+// the link emailed to NewEmail; the link of the notice emailed to OldEmail reverts the change
+// until RevertExpiresAt, in case someone else made it. Only hashes of the link tokens are stored.
+type EmailChange struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	// ExpiresAt ends the time the change can be confirmed in
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`
+	RevertExpiresAt time.Time  `gorm:"not null" json:"revert_expires_at"`
+	RevertedAt      *time.Time `json:"reverted_at,omitempty"`
+	ID              string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID        string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID          string     `gorm:"type:char(36);not null;index" json:"user_id"`
+	OldEmail        string     `gorm:"size:50;not null" json:"old_email"`
+	NewEmail        string     `gorm:"size:50;not null" json:"new_email"`
+	ConfirmHash     string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	RevertHash      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+}
+
+func (c *EmailChange) TableName() string {
+	return "email_changes"
+}
+
+// Pending reports whether the change can still be confirmed at the given time
+func (c *EmailChange) Pending(now time.Time) bool {
+	return c.ConfirmedAt == nil && c.RevertedAt == nil && now.Before(c.ExpiresAt)
+}
+
+// Revertible reports whether the change can still be reverted at the given time
+func (c *EmailChange) Revertible(now time.Time) bool {
+	return c.RevertedAt == nil && now.Before(c.RevertExpiresAt)
+}
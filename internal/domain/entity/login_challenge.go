@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+)
+
+// LoginLinkPrefix starts every token of a passwordless login link
+const LoginLinkPrefix = "mlt_"
+
+// Passwordless login methods
+const (
+	LoginMethodLink = "link"
+	LoginMethodCode = "code"
+)
+
+// LoginChallenge is a passwordless login waiting for the user to follow the emailed link or
+// enter the emailed code. It can only be completed from the device that requested it, which
+// holds the device secret. Only hashes of the link or code and of the device secret are stored.
+type LoginChallenge struct {
+	CreatedAt  time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+	ID         string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID   string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID     string     `gorm:"type:char(36);not null;index" json:"user_id"`
+	Method     string     `gorm:"size:8;not null" json:"method"`
+	SecretHash string     `gorm:"size:64;not null" json:"-"`
+	DeviceHash string     `gorm:"size:64;not null" json:"-"`
+	// Attempts counts the wrong links or codes presented for the challenge
+	Attempts int `gorm:"not null;default:0" json:"attempts"`
+}
+
+func (c *LoginChallenge) TableName() string {
+	return "login_challenges"
+}
+
+// Pending reports whether the challenge can still be completed at the given time
+func (c *LoginChallenge) Pending(now time.Time) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt)
+}
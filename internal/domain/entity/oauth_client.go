@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// OAuthClientSecretPrefix starts every client secret
+const OAuthClientSecretPrefix = "ocs_"
+
+// Grant types an OAuth client can be registered for
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// Scopes of the authorization server. openid requests an ID token, profile and email add
+// the user name and email address to it.
+const (
+	OAuthScopeOpenID  = "openid"
+	OAuthScopeProfile = "profile"
+	OAuthScopeEmail   = "email"
+)
+
+// OAuthScopes lists every scope a client can be registered for
+var OAuthScopes = []string{OAuthScopeOpenID, OAuthScopeProfile, OAuthScopeEmail}
+
+// OAuthClient is an application authenticating users of this service through the OAuth2
+// authorization server. Public clients, such as single page and mobile apps, have no secret
+// and can only use the authorization code grant with PKCE.
+type OAuthClient struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID        string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID  string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	// OwnerID is the user who registered the client
+	OwnerID      string   `gorm:"type:char(36);not null;index" json:"owner_id"`
+	Name         string   `gorm:"size:100;not null" json:"name"`
+	SecretHash   string   `gorm:"size:64" json:"-"`
+	RedirectURIs []string `gorm:"serializer:json;type:jsonb;not null" json:"redirect_uris"`
+	GrantTypes   []string `gorm:"serializer:json;type:jsonb;not null" json:"grant_types"`
+	Scopes       []string `gorm:"serializer:json;type:jsonb;not null" json:"scopes"`
+}
+
+func (c *OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// Confidential reports whether the client authenticates with a secret
+func (c *OAuthClient) Confidential() bool {
+	return c.SecretHash != ""
+}
+
+// AllowsGrant reports whether the client was registered for grantType
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	return slices.Contains(c.GrantTypes, grantType)
+}
+
+// AllowsRedirectURI reports whether uri exactly matches a registered redirect URI
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// AllowsScopes reports whether every scope was registered for the client
+func (c *OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// OAuthAccessTokenPrefix starts every access token issued by the authorization server
+const OAuthAccessTokenPrefix = "oat_"
+
+// OAuthAuthorizationCode is a single-use code a client exchanges for tokens, bound to the
+// redirect URI and PKCE challenge of its authorization request. Only its hash is stored.
+type OAuthAuthorizationCode struct {
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt        *time.Time `json:"used_at,omitempty"`
+	ID            string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID      string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	CodeHash      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ClientID      string     `gorm:"type:char(36);not null;index" json:"client_id"`
+	UserID        string     `gorm:"type:char(36);not null" json:"user_id"`
+	RedirectURI   string     `gorm:"size:2048;not null" json:"redirect_uri"`
+	CodeChallenge string     `gorm:"size:128;not null" json:"-"`
+	Nonce         string     `gorm:"size:255" json:"-"`
+	Scopes        []string   `gorm:"serializer:json;type:jsonb;not null" json:"scopes"`
+}
+
+func (c *OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+// OAuthConsent records the scopes a user granted to a client, so that the consent screen is
+// only shown again when the client asks for more
+type OAuthConsent struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	TenantID  string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID    string    `gorm:"primaryKey;type:char(36)" json:"user_id"`
+	ClientID  string    `gorm:"primaryKey;type:char(36)" json:"client_id"`
+	Scopes    []string  `gorm:"serializer:json;type:jsonb;not null" json:"scopes"`
+}
+
+func (c *OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+// Covers reports whether the consent includes every scope
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// OAuthAccessToken is an opaque access token issued to a client, on behalf of a user or,
+// for the client credentials grant, of the client itself. Only its hash is stored.
+type OAuthAccessToken struct {
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	ID        string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID  string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ClientID  string     `gorm:"type:char(36);not null;index" json:"client_id"`
+	// UserID is empty for tokens of the client credentials grant
+	UserID string `gorm:"type:char(36);index;default:null" json:"user_id,omitempty"`
+	// CodeID is the authorization code the token was issued for, revoking it when the code is replayed
+	CodeID string   `gorm:"type:char(36);index;default:null" json:"code_id,omitempty"`
+	Scopes []string `gorm:"serializer:json;type:jsonb;not null" json:"scopes"`
+}
+
+func (t *OAuthAccessToken) TableName() string {
+	return "oauth_access_tokens"
+}
+
+// Active reports whether the token can still be used at the given time
+func (t *OAuthAccessToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// OAuthTokenSet is the result of a token request
+type OAuthTokenSet struct {
+	ExpiresAt   time.Time
+	AccessToken string
+	// IDToken is set when the openid scope was granted
+	IDToken string
+	Scopes  []string
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+)
+
+// InvitationPrefix starts every token of an invitation link
+const InvitationPrefix = "inv_"
+
+// Roles of the members of an organization. Every organization has exactly one owner; owners
+// and admins manage the members, and only the owner makes admins.
+const (
+	MembershipOwner  = "owner"
+	MembershipAdmin  = "admin"
+	MembershipMember = "member"
+)
+
+// Statuses of invitations. Only pending invitations can be accepted, declined or revoked.
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+// Organization is a customer account shared by the users who are members of it
+type Organization struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID        string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID  string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+}
+
+func (o *Organization) TableName() string {
+	return "organizations"
+}
+
+// Membership makes a user a member of an organization with one of the membership roles
+type Membership struct {
+	CreatedAt      time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+	OrganizationID string        `gorm:"primaryKey;type:char(36)" json:"organization_id"`
+	TenantID       string        `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID         string        `gorm:"primaryKey;type:char(36);index" json:"user_id"`
+	Role           string        `gorm:"size:10;not null" json:"role"`
+	Organization   *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	User           *User         `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (m *Membership) TableName() string {
+	return "memberships"
+}
+
+// Manages reports whether the member may invite and remove members
+func (m *Membership) Manages() bool {
+	return m.Role == MembershipOwner || m.Role == MembershipAdmin
+}
+
+// Invitation asks the user with Email to join an organization with Role. It is accepted or
+// declined with the token emailed to them, of which only the hash is stored.
+type Invitation struct {
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	RespondedAt    *time.Time `json:"responded_at,omitempty"`
+	ID             string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID       string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	OrganizationID string     `gorm:"type:char(36);not null;index" json:"organization_id"`
+	Email          string     `gorm:"size:50;not null" json:"email"`
+	Role           string     `gorm:"size:10;not null" json:"role"`
+	InvitedBy      string     `gorm:"type:char(36);not null" json:"invited_by"`
+	Status         string     `gorm:"size:10;not null;default:pending" json:"status"`
+	TokenHash      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+}
+
+func (i *Invitation) TableName() string {
+	return "invitations"
+}
+
+// Pending reports whether the invitation can still be accepted at the given time
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.Status == InvitationPending && now.Before(i.ExpiresAt)
+}
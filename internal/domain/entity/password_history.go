@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+)
+
+// PasswordHistory keeps the hash of a password a user replaced, so that it is not chosen again
+type PasswordHistory struct {
+	CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	ID           string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID     string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID       string    `gorm:"type:char(36);not null;index" json:"user_id"`
+	PasswordHash string    `gorm:"size:100;not null" json:"-"`
+}
+
+func (h *PasswordHistory) TableName() string {
+	return "password_histories"
+}
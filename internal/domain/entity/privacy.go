@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"time"
+)
+
+// Token prefixes of the links of data subject requests
+const (
+	DataExportPrefix      = "dex_"
+	AccountDeletionPrefix = "adr_"
+)
+
+// Statuses of data exports
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+)
+
+// DataExport is an archive of the personal data of a user. Exports are built in the background
+// and the user is emailed a link to download the archive until it expires. Only the hash of the
+// link token is stored.
+type DataExport struct {
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	ID          string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID    string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID      string     `gorm:"type:char(36);not null;index" json:"user_id"`
+	Status      string     `gorm:"size:10;not null;default:pending;index" json:"status"`
+	TokenHash   string     `gorm:"size:64;index" json:"-"`
+	Size        int64      `gorm:"not null;default:0" json:"size"`
+}
+
+func (e *DataExport) TableName() string {
+	return "data_exports"
+}
+
+// ArchiveKey returns the blob key of the archive, like "exports/<user>/<id>.zip"
+func (e *DataExport) ArchiveKey() string {
+	return "exports/" + e.UserID + "/" + e.ID + ".zip"
+}
+
+// Available reports whether the archive can be downloaded at the given time
+func (e *DataExport) Available(now time.Time) bool {
+	return e.Status == DataExportReady && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
+
+// AccountDeletion is the request of a user to erase their account. The user confirms it with
+// the token emailed to them, of which only the hash is stored, and may cancel it until the
+// cooling-off period ends at ScheduledAt.
+type AccountDeletion struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	// ExpiresAt ends the time the request can be confirmed in
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	// ScheduledAt is when the account is erased, set once the request is confirmed
+	ScheduledAt *time.Time `gorm:"index" json:"scheduled_at,omitempty"`
+	UserID      string     `gorm:"primaryKey;type:char(36)" json:"user_id"`
+	TenantID    string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	TokenHash   string     `gorm:"size:64;not null;index" json:"-"`
+}
+
+func (d *AccountDeletion) TableName() string {
+	return "account_deletions"
+}
+
+// Confirmed reports whether the user confirmed the request
+func (d *AccountDeletion) Confirmed() bool {
+	return d.ConfirmedAt != nil
+}
+
+// PersonalData is everything stored about a user, as handed to them by a data export. Secrets
+// such as password and token hashes are left out by the JSON encoding of the entities.
+type PersonalData struct {
+	User    *User
+	Profile *UserProfile
+	// Sessions are the refresh tokens of the user, one family per login
+	Sessions      []*RefreshToken
+	APITokens     []*APIToken
+	Identities    []*UserIdentity
+	OAuthConsents []*OAuthConsent
+	Memberships   []*Membership
+	Roles         []*Role
+	// LoginChallenges are the passwordless logins the user started
+	LoginChallenges []*LoginChallenge
+	EmailChanges    []*EmailChange
+	UserNameChanges []*UserNameChange
+	AuditEvents     []*AuditEvent
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+)
+
+// RefreshTokenPrefix starts every refresh token
+const RefreshTokenPrefix = "rt_"
+
+// RefreshToken is a single-use token exchanged for a new access token and refresh token.
+// Tokens descending from the same login share a FamilyID, so presenting a token that was
+// already exchanged revokes every token of its family. Only the hash of the token is stored.
+type RefreshToken struct {
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	ID        string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID  string     `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID    string     `gorm:"type:char(36);not null;index" json:"user_id"`
+	FamilyID  string     `gorm:"type:char(36);not null;index" json:"family_id"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+}
+
+func (t *RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// TokenPair is the result of a token login or refresh
+type TokenPair struct {
+	AccessTokenExpiresAt time.Time
+	AccessToken          string
+	RefreshToken         string
+}
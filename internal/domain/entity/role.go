@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Permissions granted to users through their roles. Routes guarded by RequirePermission need
+// the user to hold the permission, whether the request uses a session or a bearer token.
+const (
+	PermissionUsersRead   = "users:read"
+	PermissionUsersManage = "users:manage"
+	PermissionRolesRead   = "roles:read"
+	PermissionRolesAssign = "roles:assign"
+)
+
+// Permissions lists every permission, as stored in the permissions table by the seed
+var Permissions = []Permission{
+	{Name: PermissionUsersRead, Description: "Look up, search and list users"},
+	{Name: PermissionUsersManage, Description: "Lock, unlock and delete users and require password resets"},
+	{Name: PermissionRolesRead, Description: "List roles and the roles of users"},
+	{Name: PermissionRolesAssign, Description: "Assign roles to users and remove them"},
+}
+
+// RoleAdmin is the seeded role holding every permission
+const RoleAdmin = "admin"
+
+type Permission struct {
+	Name        string `gorm:"primaryKey;size:50" json:"name"`
+	Description string `gorm:"size:255;not null" json:"description"`
+}
+
+func (p *Permission) TableName() string {
+	return "permissions"
+}
+
+// Role is a named set of permissions assigned to users
+type Role struct {
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	ID          string       `gorm:"primaryKey;type:char(36)" json:"id"`
+	Name        string       `gorm:"size:50;not null;unique" json:"name"`
+	Description string       `gorm:"size:255;not null" json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions;joinForeignKey:RoleID;joinReferences:PermissionName" json:"permissions"`
+}
+
+func (r *Role) TableName() string {
+	return "roles"
+}
+
+// PermissionNames returns the names of the permissions of the role, sorted
+func (r *Role) PermissionNames() []string {
+	names := make([]string, len(r.Permissions))
+	for i, permission := range r.Permissions {
+		names[i] = permission.Name
+	}
+	slices.Sort(names)
+	return names
+}
+
+// UserRole assigns a role to a user. Roles are shared by all tenants, their assignments belong
+// to the tenant of the user.
+type UserRole struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	TenantID  string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID    string    `gorm:"primaryKey;type:char(36)" json:"user_id"`
+	RoleID    string    `gorm:"primaryKey;type:char(36);index" json:"role_id"`
+}
+
+func (ur *UserRole) TableName() string {
+	return "user_roles"
+}
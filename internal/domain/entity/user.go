@@ -1,29 +1,101 @@
 package entity
 
 import (
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// UserNameMaxLength matches the size of users.user_name
+const UserNameMaxLength = 15
+
 type User struct {
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
-	LastLoginAt  *time.Time     `json:"last_login_at,omitempty"`
-	Profile      *UserProfile   `gorm:"foreignKey:UserID" json:"profile,omitempty"`
-	ID           string         `gorm:"primaryKey;type:char(36)" json:"id"`
-	UserName     string         `gorm:"size:15;not null;unique" json:"user_name"`
-	Email        string         `gorm:"size:50;not null;unique" json:"email"`
-	PasswordHash string         `gorm:"size:100;not null" json:"-"`
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	LastLoginAt       *time.Time     `json:"last_login_at,omitempty"`
+	PasswordChangedAt *time.Time     `json:"password_changed_at,omitempty"`
+	// SessionsRevokedAt ends every cookie session started before it
+	SessionsRevokedAt *time.Time `json:"-"`
+	// EmailVerifiedAt is when the user first proved to own their email, by a passwordless
+	// login or by signing up through a provider that verified it
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// LockedAt is when an administrator locked the account; locked users cannot log in
+	LockedAt *time.Time `json:"locked_at,omitempty"`
+	// PasswordResetRequired refuses logins with the password until the user changes it
+	PasswordResetRequired bool `gorm:"not null;default:false" json:"password_reset_required"`
+	// PurgedAt is when the personal data of the deleted user was erased after the retention
+	// period; purged users can no longer be restored
+	PurgedAt *time.Time   `json:"purged_at,omitempty"`
+	Profile  *UserProfile `gorm:"foreignKey:UserID" json:"profile,omitempty"`
+	ID       string       `gorm:"primaryKey;type:char(36)" json:"id"`
+	// User names and emails are unique among the users of a tenant that are not deleted
+	TenantID     string `gorm:"size:63;not null;default:'';uniqueIndex:idx_users_active_user_name;uniqueIndex:idx_users_active_email" json:"-"`
+	UserName     string `gorm:"size:15;not null;uniqueIndex:idx_users_active_user_name,where:deleted_at IS NULL" json:"user_name"`
+	Email        string `gorm:"size:50;not null;uniqueIndex:idx_users_active_email,where:deleted_at IS NULL" json:"email"`
+	PasswordHash string `gorm:"size:100;not null" json:"-"`
+}
+
+// SessionValid reports whether a session started at the given time survived every revocation
+func (u *User) SessionValid(startedAt time.Time) bool {
+	return u.SessionsRevokedAt == nil || !startedAt.Before(*u.SessionsRevokedAt)
+}
+
+// Locked reports whether an administrator locked the account
+func (u *User) Locked() bool {
+	return u.LockedAt != nil
+}
+
+// Purged reports whether the personal data of the deleted user was erased
+func (u *User) Purged() bool {
+	return u.PurgedAt != nil
 }
 
+// Profile field limits, in characters
+const (
+	ProfileDisplayNameMaxLength = 50
+	ProfileBioMaxLength         = 500
+)
+
+// UserProfile holds what users tell about themselves. Every user has at most one profile,
+// and fields the user never set are empty.
 type UserProfile struct {
-	UserID string `gorm:"type:char(36);not null" json:"user_id"`
-	Name   string `gorm:"size:100" json:"name,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	TenantID  string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID    string    `gorm:"type:char(36);not null;uniqueIndex" json:"user_id"`
+	// DisplayName is how the user is shown to others instead of the user name
+	DisplayName string `gorm:"size:50;not null;default:''" json:"display_name,omitempty"`
+	Bio         string `gorm:"size:500;not null;default:''" json:"bio,omitempty"`
+	// Locale is a BCP 47 language tag like en-US
+	Locale string `gorm:"size:35;not null;default:''" json:"locale,omitempty"`
+	// Timezone is an IANA time zone name like Europe/Berlin
+	Timezone string `gorm:"size:64;not null;default:''" json:"timezone,omitempty"`
+	// Avatar names the profile picture of the user, like "<hash>.jpg"; see AvatarKey
+	Avatar string `gorm:"size:64;not null;default:''" json:"avatar,omitempty"`
 	ID     uint   `gorm:"primaryKey" json:"id"`
 }
 
+// Edge lengths in pixels of the square thumbnails every avatar is stored as
+const (
+	AvatarSmall  = 64
+	AvatarMedium = 128
+	AvatarLarge  = 256
+)
+
+// AvatarSizes lists the thumbnails of an avatar, largest first
+var AvatarSizes = []int{AvatarLarge, AvatarMedium, AvatarSmall}
+
+// AvatarKey returns the blob key of the thumbnail of the avatar with the given edge length,
+// like "avatars/<user>/<hash>_256.jpg"
+func (up *UserProfile) AvatarKey(size int) string {
+	ext := path.Ext(up.Avatar)
+	return "avatars/" + up.UserID + "/" + strings.TrimSuffix(up.Avatar, ext) + "_" + strconv.Itoa(size) + ext
+}
+
 func (u *User) TableName() string {
 	return "users"
 }
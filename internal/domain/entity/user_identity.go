@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// UserIdentity links a user to the subject of an external OpenID Connect provider, letting
+// the user sign in through that provider
+type UserIdentity struct {
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	ID          string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	// A provider account may sign in to several tenants, once in each
+	TenantID string `gorm:"size:63;not null;default:'';uniqueIndex:idx_user_identities_tenant_provider_subject" json:"-"`
+	UserID   string `gorm:"type:char(36);not null;index" json:"user_id"`
+	Provider string `gorm:"size:32;not null;uniqueIndex:idx_user_identities_tenant_provider_subject" json:"provider"`
+	Subject  string `gorm:"size:255;not null;uniqueIndex:idx_user_identities_tenant_provider_subject" json:"subject"`
+	// Email is the address the provider reported when the identity was linked
+	Email string `gorm:"size:255" json:"email,omitempty"`
+	// Provisioned marks the identity the user account was created through on first sign-in
+	Provisioned bool `gorm:"not null;default:false" json:"provisioned"`
+}
+
+func (i *UserIdentity) TableName() string {
+	return "user_identities"
+}
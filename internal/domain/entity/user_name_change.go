@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+)
+
+// UserNameChange records that a user renamed themselves from OldUserName to NewUserName. Until
+// RedirectExpiresAt the old name still leads to the user, and nobody else may take it or a
+// name that looks like it.
+type UserNameChange struct {
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	RedirectExpiresAt time.Time `gorm:"not null" json:"redirect_expires_at"`
+	ID                string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	TenantID          string    `gorm:"size:63;not null;default:'';index" json:"-"`
+	UserID            string    `gorm:"type:char(36);not null;index" json:"user_id"`
+	OldUserName       string    `gorm:"size:15;not null" json:"old_user_name"`
+	// OldSkeleton is what OldUserName looks like, see security.UsernameSkeleton
+	OldSkeleton string `gorm:"size:15;not null;index" json:"-"`
+	NewUserName string `gorm:"size:15;not null" json:"new_user_name"`
+}
+
+func (c *UserNameChange) TableName() string {
+	return "user_name_changes"
+}
+
+// Redirects reports whether the old name still leads to the user at the given time
+func (c *UserNameChange) Redirects(now time.Time) bool {
+	return now.Before(c.RedirectExpiresAt)
+}
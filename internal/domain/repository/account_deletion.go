@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type AccountDeletionRepository interface {
+	// Save creates the request or replaces the pending request of the same user
+	Save(ctx context.Context, deletion *entity.AccountDeletion) error
+	FindByUserID(ctx context.Context, userID string) (*entity.AccountDeletion, error)
+	// ListDue returns at most limit confirmed requests scheduled before the given time
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*entity.AccountDeletion, error)
+	// Delete returns gorm.ErrRecordNotFound when userID has no request
+	Delete(ctx context.Context, userID string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *entity.APIToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*entity.APIToken, error)
+	FindByUserID(ctx context.Context, userID string) ([]*entity.APIToken, error)
+	// Revoke marks the token as revoked, returning gorm.ErrRecordNotFound when userID has no such active token
+	Revoke(ctx context.Context, userID, id string, at time.Time) error
+	// RevokeUser revokes every token of userID that is not revoked yet
+	RevokeUser(ctx context.Context, userID string, at time.Time) error
+	UpdateLastUsed(ctx context.Context, id string, at time.Time) error
+}
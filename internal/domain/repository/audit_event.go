@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *entity.AuditEvent) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *entity.DataExport) error
+	Update(ctx context.Context, export *entity.DataExport) error
+	// FindLatest returns the latest export requested by userID
+	FindLatest(ctx context.Context, userID string) (*entity.DataExport, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.DataExport, error)
+	// ListByUserID returns every export of userID, whatever its status
+	ListByUserID(ctx context.Context, userID string) ([]*entity.DataExport, error)
+	// ListPending returns at most limit exports waiting to be built, oldest first
+	ListPending(ctx context.Context, limit int) ([]*entity.DataExport, error)
+	// ListExpired returns at most limit exports that expired before the given time
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*entity.DataExport, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type EmailChangeRepository interface {
+	Create(ctx context.Context, change *entity.EmailChange) error
+	FindByConfirmHash(ctx context.Context, confirmHash string) (*entity.EmailChange, error)
+	FindByRevertHash(ctx context.Context, revertHash string) (*entity.EmailChange, error)
+	// Confirm marks the change confirmed, returning gorm.ErrRecordNotFound when it was
+	// confirmed or reverted meanwhile
+	Confirm(ctx context.Context, id string, at time.Time) error
+	// Revert marks the change reverted, returning gorm.ErrRecordNotFound when it was reverted
+	// meanwhile
+	Revert(ctx context.Context, id string, at time.Time) error
+	// RevertPending marks the changes of userID that were neither confirmed nor reverted reverted
+	RevertPending(ctx context.Context, userID string, at time.Time) error
+}
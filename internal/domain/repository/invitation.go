@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *entity.Invitation) error
+	FindByID(ctx context.Context, id string) (*entity.Invitation, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error)
+	// ListPending returns the invitations of the organization that are pending at now, newest first
+	ListPending(ctx context.Context, organizationID string, now time.Time) ([]*entity.Invitation, error)
+	// Accept marks the invitation accepted and creates membership, returning
+	// gorm.ErrRecordNotFound when the invitation is no longer pending
+	Accept(ctx context.Context, id string, membership *entity.Membership, at time.Time) error
+	// Respond sets the status of the invitation to declined or revoked, returning
+	// gorm.ErrRecordNotFound when it is no longer pending
+	Respond(ctx context.Context, id, status string, at time.Time) error
+	// RevokePending revokes the pending invitations of email to the organization
+	RevokePending(ctx context.Context, organizationID, email string, at time.Time) error
+}
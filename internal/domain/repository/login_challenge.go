@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type LoginChallengeRepository interface {
+	Create(ctx context.Context, challenge *entity.LoginChallenge) error
+	FindByID(ctx context.Context, id string) (*entity.LoginChallenge, error)
+	// CountSince counts the challenges created for userID since the given time
+	CountSince(ctx context.Context, userID string, since time.Time) (int64, error)
+	// RecordFailedAttempt counts a wrong link or code, returning gorm.ErrRecordNotFound when
+	// the challenge was used or already has maxAttempts failed attempts
+	RecordFailedAttempt(ctx context.Context, id string, maxAttempts int) error
+	// MarkUsed completes the challenge, returning gorm.ErrRecordNotFound when it already was
+	MarkUsed(ctx context.Context, id string, at time.Time) error
+	// MarkPendingUsed voids every unused challenge of userID
+	MarkPendingUsed(ctx context.Context, userID string, at time.Time) error
+}
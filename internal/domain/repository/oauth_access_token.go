@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type OAuthAccessTokenRepository interface {
+	Create(ctx context.Context, token *entity.OAuthAccessToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*entity.OAuthAccessToken, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// RevokeByCode revokes every token issued for the authorization code
+	RevokeByCode(ctx context.Context, codeID string, at time.Time) error
+	// RevokeUser revokes every token issued on behalf of userID that is not revoked yet
+	RevokeUser(ctx context.Context, userID string, at time.Time) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type OAuthAuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *entity.OAuthAuthorizationCode) error
+	FindByHash(ctx context.Context, codeHash string) (*entity.OAuthAuthorizationCode, error)
+	// MarkUsed marks the code as exchanged, returning gorm.ErrRecordNotFound when it already was
+	MarkUsed(ctx context.Context, id string, at time.Time) error
+	// RevokeUser marks every code of userID that was not exchanged yet as used, so that it
+	// can no longer be exchanged
+	RevokeUser(ctx context.Context, userID string, at time.Time) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *entity.OAuthClient) error
+	FindByID(ctx context.Context, id string) (*entity.OAuthClient, error)
+	FindByOwnerID(ctx context.Context, ownerID string) ([]*entity.OAuthClient, error)
+	// Delete removes the client, returning gorm.ErrRecordNotFound when ownerID has no such client
+	Delete(ctx context.Context, ownerID, id string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type OAuthConsentRepository interface {
+	Find(ctx context.Context, userID, clientID string) (*entity.OAuthConsent, error)
+	// Save creates the consent or replaces its scopes
+	Save(ctx context.Context, consent *entity.OAuthConsent) error
+}
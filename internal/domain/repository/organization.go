@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type OrganizationRepository interface {
+	// Create stores the organization together with the membership of its owner
+	Create(ctx context.Context, organization *entity.Organization, owner *entity.Membership) error
+	FindByID(ctx context.Context, id string) (*entity.Organization, error)
+	// ListByUserID returns the memberships of userID with their organization, by organization name
+	ListByUserID(ctx context.Context, userID string) ([]*entity.Membership, error)
+	FindMembership(ctx context.Context, organizationID, userID string) (*entity.Membership, error)
+	// ListMembers returns the memberships of the organization with their user, oldest first
+	ListMembers(ctx context.Context, organizationID string) ([]*entity.Membership, error)
+	// RemoveMember returns gorm.ErrRecordNotFound when userID is not a member
+	RemoveMember(ctx context.Context, organizationID, userID string) error
+	// TransferOwnership makes newOwnerID the owner and ownerID an admin, returning
+	// gorm.ErrRecordNotFound unless ownerID is the owner and newOwnerID a member
+	TransferOwnership(ctx context.Context, organizationID, ownerID, newOwnerID string) error
+}
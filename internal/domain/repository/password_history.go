@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, entry *entity.PasswordHistory) error
+	// ListRecent returns the latest limit entries of userID, newest first
+	ListRecent(ctx context.Context, userID string, limit int) ([]*entity.PasswordHistory, error)
+	// Prune deletes the entries of userID beyond the newest keep ones
+	Prune(ctx context.Context, userID string, keep int) error
+}
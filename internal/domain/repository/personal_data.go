@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type PersonalDataRepository interface {
+	// Collect returns everything stored about userID
+	Collect(ctx context.Context, userID string) (*entity.PersonalData, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entity.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// MarkUsed marks the token as exchanged, returning gorm.ErrRecordNotFound when it already was
+	MarkUsed(ctx context.Context, id string, at time.Time) error
+	// RevokeFamily revokes every token of the family that is not revoked yet
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	// RevokeUser revokes every token of userID that is not revoked yet
+	RevokeUser(ctx context.Context, userID string, at time.Time) error
+}
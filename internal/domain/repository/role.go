@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type RoleRepository interface {
+	// SavePermissions creates the permissions or updates their description
+	SavePermissions(ctx context.Context, permissions []entity.Permission) error
+	// Save creates the role, or updates the role of the same name and sets role.ID to its ID,
+	// replacing its permissions
+	Save(ctx context.Context, role *entity.Role) error
+	FindByName(ctx context.Context, name string) (*entity.Role, error)
+	List(ctx context.Context) ([]*entity.Role, error)
+	FindByUserID(ctx context.Context, userID string) ([]*entity.Role, error)
+	// Assign gives the role to userID; assigning a role the user already has is not an error
+	Assign(ctx context.Context, userID, roleID string) error
+	// Unassign takes the role from userID, returning gorm.ErrRecordNotFound when the user does not have it
+	Unassign(ctx context.Context, userID, roleID string) error
+	// UserHasPermission reports whether one of the roles of userID grants permission
+	UserHasPermission(ctx context.Context, userID, permission string) (bool, error)
+}
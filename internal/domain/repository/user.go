@@ -2,16 +2,84 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"example.com/internal/domain/entity"
 )
 
 type UserRepository interface {
+	// Create returns gorm.ErrDuplicatedKey when another user has the user name or email
 	Create(ctx context.Context, user *entity.User) error
+	// FindByID returns the user with their profile, which is nil when the user has none
 	FindByID(ctx context.Context, id string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	FindByUserName(ctx context.Context, userName string) (*entity.User, error)
+	// FindByUserNameSkeleton returns a user whose user name looks like names with the given
+	// skeleton, see security.UsernameSkeleton
+	FindByUserNameSkeleton(ctx context.Context, skeleton string) (*entity.User, error)
 	FindByUserNameOrEmail(ctx context.Context, identifier string) (*entity.User, error)
+	// List returns the users matching query in its order, at most query.Limit of them
+	List(ctx context.Context, query UserQuery) ([]*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
+	// ChangeEmail replaces the email from of the user with to, verified at the given time. It
+	// returns gorm.ErrRecordNotFound when the user no longer has the email from, and
+	// gorm.ErrDuplicatedKey when another user took the email to.
+	ChangeEmail(ctx context.Context, id, from, to string, verifiedAt time.Time) error
+	// ChangeUserName replaces the user name from of the user with to. It returns
+	// gorm.ErrRecordNotFound when the user no longer has the name from, and
+	// gorm.ErrDuplicatedKey when another user took the name to.
+	ChangeUserName(ctx context.Context, id, from, to string) error
+	// Delete soft-deletes the user, who is no longer found until restored
 	Delete(ctx context.Context, id string) error
+	// FindDeleted returns the soft-deleted user with the given ID and their profile, or
+	// gorm.ErrRecordNotFound when no deleted user has it
+	FindDeleted(ctx context.Context, id string) (*entity.User, error)
+	// Restore undoes the soft deletion of the user. It returns gorm.ErrDuplicatedKey when
+	// another user took the user name or email of the deleted user meanwhile.
+	Restore(ctx context.Context, id string) error
+	// ListPurgeable returns, oldest deletion first, at most limit users deleted before the given
+	// time whose personal data was not purged yet, with their profile
+	ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]*entity.User, error)
+	// Purge erases the personal data of the deleted user at the given time: the user name, email
+	// and password are replaced, and the profile, identities, tokens, grants, memberships,
+	// audit events and other rows of the user deleted along with the invitations to their
+	// email. The row itself remains, so that whatever else refers to the user stays intact.
+	Purge(ctx context.Context, id string, at time.Time) error
+}
+
+// What purged users are left with instead of their user name and email
+const (
+	PurgedUserName = "deleted"
+	PurgedEmail    = "deleted@invalid"
+)
+
+// UserSort names a column users are listed in the order of. Users sharing a value are
+// ordered by ID, so that every order is total.
+type UserSort string
+
+const (
+	UserSortCreatedAt UserSort = "created_at"
+	UserSortUserName  UserSort = "user_name"
+	UserSortEmail     UserSort = "email"
+)
+
+// UserQuery specifies the users UserRepository.List returns. Filters left nil match every
+// user, except that soft-deleted users are only listed when Deleted asks for them.
+type UserQuery struct {
+	// Search matches users whose user name or email contains every word of it, ignoring case
+	Search string
+	// CreatedAfter and CreatedBefore bound the creation time, inclusive and exclusive
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Verified      *bool
+	Locked        *bool
+	Deleted       *bool
+	// Sort defaults to UserSortCreatedAt
+	Sort       UserSort
+	Descending bool
+	// After is the last user of the previous page, of which only the ID and the field Sort
+	// orders by are read. Paging by the position of a user rather than an offset neither
+	// skips nor repeats users when others are created or deleted between pages.
+	After *entity.User
+	Limit int
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *entity.UserIdentity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	FindByUserID(ctx context.Context, userID string) ([]*entity.UserIdentity, error)
+	UpdateLastLogin(ctx context.Context, id string, at time.Time) error
+	// Delete removes the identity, returning gorm.ErrRecordNotFound when userID has no such identity
+	Delete(ctx context.Context, userID, id string) error
+}
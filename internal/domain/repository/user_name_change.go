@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+type UserNameChangeRepository interface {
+	Create(ctx context.Context, change *entity.UserNameChange) error
+	// ListByUserID returns the changes of userID, newest first
+	ListByUserID(ctx context.Context, userID string) ([]*entity.UserNameChange, error)
+	// FindRedirect returns the newest change away from a name with the given skeleton whose
+	// redirect lasts beyond the given time, or gorm.ErrRecordNotFound when there is none
+	FindRedirect(ctx context.Context, oldSkeleton string, now time.Time) (*entity.UserNameChange, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+)
+
+type UserProfileRepository interface {
+	FindByUserID(ctx context.Context, userID string) (*entity.UserProfile, error)
+	// Save creates the profile of its user or replaces the fields of the existing one
+	Save(ctx context.Context, profile *entity.UserProfile) error
+}
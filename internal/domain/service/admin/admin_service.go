@@ -0,0 +1,298 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+// Page sizes of List
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrInvalidCursor = errors.New("invalid cursor, or it belongs to another sort order")
+	// ErrSelf keeps administrators from locking themselves out
+	ErrSelf = errors.New("administrators cannot lock or delete their own account")
+	// ErrUserPurged is returned for deleted users whose personal data was already erased
+	ErrUserPurged = errors.New("the retention period of the deleted user is over")
+	// ErrUserTaken is returned when restoring a user whose user name or email was taken by
+	// another user after the deletion
+	ErrUserTaken = errors.New("another user took the user name or email of the deleted user")
+)
+
+// Page is a page of users and the cursor of the next one
+type Page struct {
+	Users []*entity.User
+	// Next is empty on the last page
+	Next string
+}
+
+type Service interface {
+	// List returns the page of users matching query that follows cursor, the Next of the
+	// previous page or empty for the first page. The After and Limit of query are ignored;
+	// a Limit outside 1 to MaxPageSize is replaced by DefaultPageSize.
+	List(ctx context.Context, query repository.UserQuery, cursor string) (*Page, error)
+	// Lock keeps userID from logging in and revokes every session and token of the user,
+	// including the OAuth2 codes and access tokens applications hold. JWT access tokens are
+	// verified without a lookup and stay valid until they expire.
+	Lock(ctx context.Context, actorID, userID string) (*entity.User, error)
+	// Unlock lets a locked user log in again
+	Unlock(ctx context.Context, userID string) (*entity.User, error)
+	// RequirePasswordReset refuses the password of userID until the user changes it, and
+	// revokes every session and token of the user like Lock
+	RequirePasswordReset(ctx context.Context, userID string) (*entity.User, error)
+	// Delete soft-deletes userID and revokes every token of the user. The user can be restored
+	// until the retention period is over.
+	Delete(ctx context.Context, actorID, userID string) error
+	// Restore undoes the deletion of userID. Sessions and tokens revoked by the deletion stay
+	// revoked.
+	Restore(ctx context.Context, userID string) (*entity.User, error)
+}
+
+type service struct {
+	userRepo             repository.UserRepository
+	refreshTokenRepo     repository.RefreshTokenRepository
+	apiTokenRepo         repository.APITokenRepository
+	oauthCodeRepo        repository.OAuthAuthorizationCodeRepository
+	oauthAccessTokenRepo repository.OAuthAccessTokenRepository
+	now                  func() time.Time
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	apiTokenRepo repository.APITokenRepository,
+	oauthCodeRepo repository.OAuthAuthorizationCodeRepository,
+	oauthAccessTokenRepo repository.OAuthAccessTokenRepository,
+) Service {
+	return &service{
+		userRepo:             userRepo,
+		refreshTokenRepo:     refreshTokenRepo,
+		apiTokenRepo:         apiTokenRepo,
+		oauthCodeRepo:        oauthCodeRepo,
+		oauthAccessTokenRepo: oauthAccessTokenRepo,
+		now:                  time.Now,
+	}
+}
+
+// cursor is the position of the last user of a page, encoded into the opaque cursor handed
+// out with it. It holds the sort order too, so that it is never read in another one.
+type cursor struct {
+	Sort       repository.UserSort `json:"s"`
+	Descending bool                `json:"d,omitempty"`
+	ID         string              `json:"i"`
+	CreatedAt  *time.Time          `json:"c,omitempty"`
+	UserName   string              `json:"u,omitempty"`
+	Email      string              `json:"e,omitempty"`
+}
+
+func (s *service) List(ctx context.Context, query repository.UserQuery, encoded string) (*Page, error) {
+	if query.Sort == "" {
+		query.Sort = repository.UserSortCreatedAt
+	}
+	if query.Limit < 1 || query.Limit > MaxPageSize {
+		query.Limit = DefaultPageSize
+	}
+	query.After = nil
+	if encoded != "" {
+		after, err := decodeCursor(encoded, query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+	}
+
+	// One more user than asked for tells whether there is a next page
+	limit := query.Limit
+	query.Limit++
+	users, err := s.userRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Users: users}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.Next = encodeCursor(page.Users[limit-1], query)
+	}
+	return page, nil
+}
+
+func (s *service) Lock(ctx context.Context, actorID, userID string) (*entity.User, error) {
+	if actorID == userID {
+		return nil, ErrSelf
+	}
+	user, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Locked() {
+		return user, nil
+	}
+
+	now := s.now()
+	user.LockedAt = &now
+	user.SessionsRevokedAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.revokeTokens(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *service) Unlock(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Locked() {
+		return user, nil
+	}
+
+	user.LockedAt = nil
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *service) RequirePasswordReset(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Whoever knows the password may be logged in already, or have created tokens with it
+	now := s.now()
+	user.PasswordResetRequired = true
+	user.SessionsRevokedAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.revokeTokens(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *service) Delete(ctx context.Context, actorID, userID string) error {
+	if actorID == userID {
+		return ErrSelf
+	}
+	user, err := s.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Sessions end by themselves as deleted users are no longer found
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+	return s.revokeTokens(ctx, user.ID, s.now())
+}
+
+func (s *service) Restore(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.userRepo.FindDeleted(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Restoring a user that is not deleted changes nothing
+		return s.find(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Purged() {
+		return nil, ErrUserPurged
+	}
+
+	// Others may sign up with the user name and email of deleted users
+	for _, find := range []func() (*entity.User, error){
+		func() (*entity.User, error) { return s.userRepo.FindByUserName(ctx, user.UserName) },
+		func() (*entity.User, error) { return s.userRepo.FindByEmail(ctx, user.Email) },
+	} {
+		if _, err := find(); err == nil {
+			return nil, ErrUserTaken
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	// The unique indexes catch users taking the name or email since the checks above
+	err = s.userRepo.Restore(ctx, user.ID)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrUserTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	return user, nil
+}
+
+func (s *service) find(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// revokeTokens revokes the refresh tokens, personal access tokens and OAuth2 grants of userID
+func (s *service) revokeTokens(ctx context.Context, userID string, at time.Time) error {
+	for _, revoke := range []func(context.Context, string, time.Time) error{
+		s.refreshTokenRepo.RevokeUser,
+		s.apiTokenRepo.RevokeUser,
+		s.oauthCodeRepo.RevokeUser,
+		s.oauthAccessTokenRepo.RevokeUser,
+	} {
+		if err := revoke(ctx, userID, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeCursor(last *entity.User, query repository.UserQuery) string {
+	c := cursor{Sort: query.Sort, Descending: query.Descending, ID: last.ID}
+	switch query.Sort {
+	case repository.UserSortCreatedAt:
+		c.CreatedAt = &last.CreatedAt
+	case repository.UserSortUserName:
+		c.UserName = last.UserName
+	case repository.UserSortEmail:
+		c.Email = last.Email
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded string, query repository.UserQuery) (*entity.User, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != query.Sort || c.Descending != query.Descending {
+		return nil, ErrInvalidCursor
+	}
+	after := &entity.User{ID: c.ID, UserName: c.UserName, Email: c.Email}
+	if c.CreatedAt != nil {
+		after.CreatedAt = *c.CreatedAt
+	}
+	return after, nil
+}
@@ -0,0 +1,124 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/security"
+)
+
+// hintLength is the number of leading token characters kept to identify a token in listings
+const hintLength = 12
+
+// lastUsedResolution throttles last-used updates so that busy tokens do not write on every request
+const lastUsedResolution = time.Minute
+
+var (
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrTokenNotFound = errors.New("token not found")
+	ErrUnknownScope  = errors.New("unknown scope")
+	ErrNoScopes      = errors.New("at least one scope is required")
+	ErrExpiryInPast  = errors.New("expiry must be in the future")
+)
+
+type Service interface {
+	// Issue creates a token for userID and returns it together with the plain token, which is not stored
+	Issue(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (*entity.APIToken, string, error)
+	List(ctx context.Context, userID string) ([]*entity.APIToken, error)
+	Revoke(ctx context.Context, userID, tokenID string) error
+	// Authenticate resolves a plain token to its active record and records its use
+	Authenticate(ctx context.Context, token string) (*entity.APIToken, error)
+}
+
+type service struct {
+	tokenRepo repository.APITokenRepository
+	now       func() time.Time
+}
+
+func NewService(tokenRepo repository.APITokenRepository) Service {
+	return &service{
+		tokenRepo: tokenRepo,
+		now:       time.Now,
+	}
+}
+
+func (s *service) Issue(
+	ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time,
+) (*entity.APIToken, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", ErrNoScopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(entity.APITokenScopes, scope) {
+			return nil, "", fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(s.now()) {
+		return nil, "", ErrExpiryInPast
+	}
+
+	plain, err := security.GenerateToken(entity.APITokenPrefix)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &entity.APIToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      name,
+		Hint:      plain[:hintLength],
+		TokenHash: security.HashToken(plain),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, plain, nil
+}
+
+func (s *service) List(ctx context.Context, userID string) ([]*entity.APIToken, error) {
+	return s.tokenRepo.FindByUserID(ctx, userID)
+}
+
+func (s *service) Revoke(ctx context.Context, userID, tokenID string) error {
+	err := s.tokenRepo.Revoke(ctx, userID, tokenID, s.now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTokenNotFound
+	}
+	return err
+}
+
+func (s *service) Authenticate(ctx context.Context, plain string) (*entity.APIToken, error) {
+	if !security.HasTokenPrefix(plain, entity.APITokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, security.HashToken(plain))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := s.now()
+	if !token.Active(now) {
+		return nil, ErrInvalidToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		// Last-used tracking is informational; a failed update must not reject the request
+		if err := s.tokenRepo.UpdateLastUsed(ctx, token.ID, now); err == nil {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
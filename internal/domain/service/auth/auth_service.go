@@ -3,9 +3,11 @@ package auth
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"example.com/internal/domain/entity"
 	"example.com/internal/domain/repository"
@@ -15,6 +17,12 @@ import (
 var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAccountLocked is returned by every way of logging in once the password, code or
+	// provider proved who the user is
+	ErrAccountLocked = errors.New("account is locked")
+	// ErrPasswordResetRequired refuses the password of a user who must change it; the user
+	// logs in another way, such as a passwordless login, to do so
+	ErrPasswordResetRequired = errors.New("password reset required")
 )
 
 type Service interface {
@@ -22,6 +30,10 @@ type Service interface {
 	CreateUser(ctx context.Context, email, password, username string) (*entity.User, error)
 	AuthenticateUser(ctx context.Context, email, password string) (*entity.User, error)
 	UpdateLastLogin(ctx context.Context, userID string) error
+	// VerifyEmail records that userID proved to own their email, unless they already did
+	VerifyEmail(ctx context.Context, userID string) error
+	// SessionValid reports whether a session of userID started at startedAt may still be used
+	SessionValid(ctx context.Context, userID string, startedAt time.Time) (bool, error)
 }
 
 type service struct {
@@ -63,7 +75,12 @@ func (s *service) CreateUser(ctx context.Context, email, password, username stri
 		PasswordHash: hashedPassword,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	// Another signup may have taken the user name or email since they were checked
+	err = s.userRepo.Create(ctx, user)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrUserAlreadyExists
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -71,7 +88,12 @@ func (s *service) CreateUser(ctx context.Context, email, password, username stri
 }
 
 func (s *service) AuthenticateUser(ctx context.Context, email, password string) (*entity.User, error) {
-	user, err := s.userRepo.FindByUserNameOrEmail(ctx, email)
+	// User names are stored normalized and cannot hold an @, emails are stored as given
+	identifier := email
+	if !strings.Contains(identifier, "@") {
+		identifier = security.NormalizeUsername(identifier)
+	}
+	user, err := s.userRepo.FindByUserNameOrEmail(ctx, identifier)
 	if err != nil {
 		return nil, ErrInvalidCredentials
 	}
@@ -80,6 +102,14 @@ func (s *service) AuthenticateUser(ctx context.Context, email, password string)
 		return nil, ErrInvalidCredentials
 	}
 
+	// Checked after the password so that only its owner learns about the account state
+	if user.Locked() {
+		return nil, ErrAccountLocked
+	}
+	if user.PasswordResetRequired {
+		return nil, ErrPasswordResetRequired
+	}
+
 	return user, nil
 }
 
@@ -93,3 +123,29 @@ func (s *service) UpdateLastLogin(ctx context.Context, userID string) error {
 	user.LastLoginAt = &now
 	return s.userRepo.Update(ctx, user)
 }
+
+func (s *service) VerifyEmail(ctx context.Context, userID string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return s.userRepo.Update(ctx, user)
+}
+
+func (s *service) SessionValid(ctx context.Context, userID string, startedAt time.Time) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return user.SessionValid(startedAt), nil
+}
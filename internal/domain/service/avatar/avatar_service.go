@@ -0,0 +1,175 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"io"
+	"net/http"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	profileservice "example.com/internal/domain/service/profile"
+	"example.com/pkg/storage"
+)
+
+var (
+	ErrTooLarge        = errors.New("avatar image is too large")
+	ErrUnsupportedType = errors.New("avatars are JPEG, PNG, GIF or WebP images")
+	ErrInvalidImage    = errors.New("avatar image cannot be decoded")
+	ErrNotFound        = errors.New("avatar not found")
+)
+
+// supportedTypes maps the media types accepted, as sniffed from the upload, to the format
+// name image.Decode reports for them
+var supportedTypes = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+type Config struct {
+	// MaxBytes bounds the size of an upload
+	MaxBytes int
+	// MaxPixels bounds width times height of an upload, which is decoded in memory
+	MaxPixels int
+}
+
+type Service interface {
+	// Upload makes the image read from r the avatar of userID and returns the profile saved.
+	// The image is stored as the thumbnails of entity.AvatarSizes, re-encoded without any of
+	// the metadata of the upload, and the previous avatar is deleted.
+	Upload(ctx context.Context, userID string, r io.Reader) (*entity.UserProfile, error)
+	// Delete removes the avatar of userID and returns the profile saved
+	Delete(ctx context.Context, userID string) (*entity.UserProfile, error)
+	// Open returns the thumbnail stored under key, as returned by UserProfile.AvatarKey
+	Open(ctx context.Context, key string) (*storage.Blob, error)
+}
+
+type service struct {
+	profileService profileservice.Service
+	profileRepo    repository.UserProfileRepository
+	store          storage.BlobStore
+	cfg            Config
+}
+
+func NewService(
+	profileService profileservice.Service,
+	profileRepo repository.UserProfileRepository,
+	store storage.BlobStore,
+	cfg Config,
+) Service {
+	return &service{
+		profileService: profileService,
+		profileRepo:    profileRepo,
+		store:          store,
+		cfg:            cfg,
+	}
+}
+
+func (s *service) Upload(ctx context.Context, userID string, r io.Reader) (*entity.UserProfile, error) {
+	profile, err := s.profileService.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(s.cfg.MaxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > s.cfg.MaxBytes {
+		return nil, ErrTooLarge
+	}
+	img, err := s.decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails, ext, err := thumbnails(img, exifOrientation(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Naming avatars by their content gives every upload new URLs, which caches never mix up
+	sum := sha256.Sum256(data)
+	previous := *profile
+	profile.Avatar = hex.EncodeToString(sum[:16]) + ext
+	for i, size := range entity.AvatarSizes {
+		if err := s.store.Put(ctx, profile.AvatarKey(size), thumbnails[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.profileRepo.Save(ctx, profile); err != nil {
+		return nil, err
+	}
+	if previous.Avatar != "" && previous.Avatar != profile.Avatar {
+		s.deleteThumbnails(ctx, &previous)
+	}
+	return profile, nil
+}
+
+func (s *service) Delete(ctx context.Context, userID string) (*entity.UserProfile, error) {
+	profile, err := s.profileService.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Avatar == "" {
+		return profile, nil
+	}
+
+	previous := *profile
+	profile.Avatar = ""
+	if err := s.profileRepo.Save(ctx, profile); err != nil {
+		return nil, err
+	}
+	s.deleteThumbnails(ctx, &previous)
+	return profile, nil
+}
+
+func (s *service) Open(ctx context.Context, key string) (*storage.Blob, error) {
+	blob, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		return nil, ErrNotFound
+	}
+	return blob, err
+}
+
+// decode sniffs the type of data rather than trusting the name or type the client sent, and
+// reads the dimensions from the header before decoding so that small files declaring huge
+// images are refused before they are expanded in memory
+func (s *service) decode(data []byte) (image.Image, error) {
+	format, ok := supportedTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, configFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || configFormat != format {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if config.Width > s.cfg.MaxPixels/config.Height {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return img, nil
+}
+
+// deleteThumbnails removes the thumbnails of a replaced avatar. The profile no longer points
+// to them, so a failure only leaves unreachable blobs behind and is not reported.
+func (s *service) deleteThumbnails(ctx context.Context, profile *entity.UserProfile) {
+	for _, size := range entity.AvatarSizes {
+		_ = s.store.Delete(ctx, profile.AvatarKey(size))
+	}
+}
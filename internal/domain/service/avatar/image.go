@@ -0,0 +1,184 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif" // registers the GIF decoder with image.Decode
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder with image.Decode
+
+	"example.com/internal/domain/entity"
+)
+
+// jpegQuality is good enough for faces at thumbnail sizes without bloating them
+const jpegQuality = 85
+
+// thumbnails crops the center square of img and scales it to every size of
+// entity.AvatarSizes, returning the encoded thumbnails and their extension. Opaque images
+// become JPEGs and images with transparency PNGs. Encoding from pixels leaves every piece of
+// metadata of the original behind, such as EXIF locations; only its orientation is applied.
+func thumbnails(img image.Image, orientation int) ([][]byte, string, error) {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(bounds.Min).Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+
+	// Smaller thumbnails are scaled from the largest rather than from a possibly huge original.
+	// The center square of a rotated image is the rotated center square, so only the small
+	// square is rotated.
+	largest := scale(img, crop, entity.AvatarSizes[0])
+	largest = orient(largest, orientation)
+
+	encode := encodePNG
+	ext := ".png"
+	if largest.Opaque() {
+		encode, ext = encodeJPEG, ".jpg"
+	}
+
+	encoded := make([][]byte, len(entity.AvatarSizes))
+	for i, size := range entity.AvatarSizes {
+		thumbnail := largest
+		if i > 0 {
+			thumbnail = scale(largest, largest.Bounds(), size)
+		}
+		data, err := encode(thumbnail)
+		if err != nil {
+			return nil, "", err
+		}
+		encoded[i] = data
+	}
+	return encoded, ext, nil
+}
+
+func scale(src image.Image, rect image.Rectangle, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, rect, draw.Src, nil)
+	return dst
+}
+
+func encodeJPEG(img *image.RGBA) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePNG(img *image.RGBA) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// orient turns a square image as EXIF orientation 1 to 8 asks for. Cameras store photos as
+// the sensor saw them and record how to turn them in this tag.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	n := src.Bounds().Dx()
+	dst := image.NewRGBA(src.Bounds())
+	for y := range n {
+		for x := range n {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror
+				dx, dy = n-1-x, y
+			case 3: // turn around
+				dx, dy = n-1-x, n-1-y
+			case 4: // flip
+				dx, dy = x, n-1-y
+			case 5: // mirror along the main diagonal
+				dx, dy = y, x
+			case 6: // turn right
+				dx, dy = n-1-y, x
+			case 7: // mirror along the other diagonal
+				dx, dy = n-1-y, n-1-x
+			case 8: // turn left
+				dx, dy = y, n-1-x
+			}
+			dst.SetRGBA(dx, dy, src.RGBAAt(x, y))
+		}
+	}
+	return dst
+}
+
+// exifOrientation returns the orientation recorded in the EXIF segment of a JPEG, or 1 for
+// the upright orientation when there is none
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF: // fill byte
+			i++
+			continue
+		case marker == 0x01 || marker >= 0xD0 && marker <= 0xD8: // markers without a segment
+			i += 2
+			continue
+		case marker == 0xDA || marker == 0xD9: // metadata precedes the image data
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag of the first image file directory of the TIFF
+// structure EXIF is written in
+func tiffOrientation(tiff []byte) int {
+	const orientationTag = 0x0112
+
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := range count {
+		entry := ifd + 2 + 12*i
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			return 1
+		}
+	}
+	return 1
+}
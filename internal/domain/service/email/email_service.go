@@ -0,0 +1,313 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/mail"
+	"example.com/pkg/security"
+)
+
+// Query parameters carrying the tokens of the emailed links
+const (
+	ConfirmTokenParam = "email_change_token"
+	RevertTokenParam  = "email_revert_token"
+)
+
+var (
+	ErrIncorrectPassword = errors.New("incorrect current password")
+	ErrSameEmail         = errors.New("the new email is the current email")
+	// ErrEmailTaken is also returned when another user took the email before the change was
+	// confirmed or reverted
+	ErrEmailTaken = errors.New("email belongs to another user")
+	// ErrInvalidToken is returned for unknown tokens, expired links and changes that were
+	// confirmed, reverted or replaced by another change
+	ErrInvalidToken = errors.New("the link is invalid or expired")
+)
+
+// Config tunes email changes
+type Config struct {
+	// LinkURL is the frontend page the links point to, with the token in the email_change_token
+	// query parameter to confirm a change and in email_revert_token to revert it
+	LinkURL string
+	// ConfirmTTL is how long the link sent to the new email works
+	ConfirmTTL time.Duration
+	// RevertTTL is how long the link sent to the old email works, from the request
+	RevertTTL time.Duration
+}
+
+type Service interface {
+	// RequestChange emails a link to confirm newEmail to that address and a notice with a link
+	// to revert the change to the current email of userID, after verifying password. The user
+	// keeps their email until the change is confirmed; a new request voids the pending one.
+	RequestChange(ctx context.Context, userID, password, newEmail string) (*entity.EmailChange, error)
+	// ConfirmChange gives the user of the change the email the token was sent to, verified
+	ConfirmChange(ctx context.Context, token string) (*entity.User, error)
+	// RevertChange gives the user of the change back the email the token was sent to and ends
+	// every session and refresh token of the user. Changes not confirmed yet are cancelled.
+	RevertChange(ctx context.Context, token string) (*entity.User, error)
+}
+
+type service struct {
+	userRepo         repository.UserRepository
+	changeRepo       repository.EmailChangeRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditRepo        repository.AuditEventRepository
+	hasher           security.PasswordHasher
+	sender           mail.Sender
+	config           Config
+	now              func() time.Time
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	changeRepo repository.EmailChangeRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	auditRepo repository.AuditEventRepository,
+	hasher security.PasswordHasher,
+	sender mail.Sender,
+	config Config,
+) Service {
+	return &service{
+		userRepo:         userRepo,
+		changeRepo:       changeRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+		hasher:           hasher,
+		sender:           sender,
+		config:           config,
+		now:              time.Now,
+	}
+}
+
+func (s *service) RequestChange(ctx context.Context, userID, password, newEmail string) (*entity.EmailChange, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.hasher.Verify(password, user.PasswordHash) {
+		return nil, ErrIncorrectPassword
+	}
+	if newEmail == user.Email {
+		return nil, ErrSameEmail
+	}
+	if err := s.checkAvailable(ctx, newEmail); err != nil {
+		return nil, err
+	}
+
+	confirmToken, err := security.GenerateToken(entity.EmailConfirmPrefix)
+	if err != nil {
+		return nil, err
+	}
+	revertToken, err := security.GenerateToken(entity.EmailRevertPrefix)
+	if err != nil {
+		return nil, err
+	}
+	confirmMsg, err := s.confirmMessage(newEmail, confirmToken)
+	if err != nil {
+		return nil, err
+	}
+	noticeMsg, err := s.noticeMessage(user.Email, newEmail, revertToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if err := s.changeRepo.RevertPending(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	change := &entity.EmailChange{
+		ID:              uuid.NewString(),
+		UserID:          user.ID,
+		OldEmail:        user.Email,
+		NewEmail:        newEmail,
+		ConfirmHash:     security.HashToken(confirmToken),
+		RevertHash:      security.HashToken(revertToken),
+		ExpiresAt:       now.Add(s.config.ConfirmTTL),
+		RevertExpiresAt: now.Add(s.config.RevertTTL),
+	}
+	if err := s.changeRepo.Create(ctx, change); err != nil {
+		return nil, err
+	}
+	if err := s.sender.Send(ctx, confirmMsg); err != nil {
+		return nil, err
+	}
+	if err := s.sender.Send(ctx, noticeMsg); err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, user.ID, entity.AuditEmailChangeRequested); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (s *service) ConfirmChange(ctx context.Context, token string) (*entity.User, error) {
+	change, err := s.changeRepo.FindByConfirmHash(ctx, security.HashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if !change.Pending(now) {
+		return nil, ErrInvalidToken
+	}
+	user, err := s.userRepo.FindByID(ctx, change.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Others may have signed up with the email since the request. The unique index settles
+	// signups racing the change, and the user must still have the email the change started from.
+	if err := s.checkAvailable(ctx, change.NewEmail); err != nil {
+		return nil, err
+	}
+	if err := s.changeEmail(ctx, user, change.OldEmail, change.NewEmail, now); err != nil {
+		return nil, err
+	}
+	if err := s.changeRepo.Confirm(ctx, change.ID, now); err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, user.ID, entity.AuditEmailChangeConfirmed); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *service) RevertChange(ctx context.Context, token string) (*entity.User, error) {
+	change, err := s.changeRepo.FindByRevertHash(ctx, security.HashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if !change.Revertible(now) {
+		return nil, ErrInvalidToken
+	}
+	user, err := s.userRepo.FindByID(ctx, change.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Whoever changed the email may have changed it again since, so the user gets the old
+	// email back from whatever email they have now
+	if user.Email != change.OldEmail {
+		if err := s.checkAvailable(ctx, change.OldEmail); err != nil {
+			return nil, err
+		}
+		if err := s.changeEmail(ctx, user, user.Email, change.OldEmail, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.changeRepo.Revert(ctx, change.ID, now); err != nil {
+		return nil, err
+	}
+	if err := s.changeRepo.RevertPending(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+
+	// Whoever changed the email knew the password, so their sessions end too
+	user.SessionsRevokedAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.RevokeUser(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, user.ID, entity.AuditEmailChangeReverted); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkAvailable refuses emails of other users
+func (s *service) checkAvailable(ctx context.Context, email string) error {
+	_, err := s.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		return ErrEmailTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// changeEmail moves user from the email from to the verified email to
+func (s *service) changeEmail(ctx context.Context, user *entity.User, from, to string, now time.Time) error {
+	err := s.userRepo.ChangeEmail(ctx, user.ID, from, to, now)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrEmailTaken
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The email changed meanwhile, by another link or request
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	user.Email = to
+	user.EmailVerifiedAt = &now
+	return nil
+}
+
+func (s *service) record(ctx context.Context, userID, action string) error {
+	return s.auditRepo.Create(ctx, &entity.AuditEvent{ID: uuid.NewString(), UserID: userID, Action: action})
+}
+
+func (s *service) confirmMessage(to, token string) (mail.Message, error) {
+	link, err := tokenLink(s.config.LinkURL, ConfirmTokenParam, token)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		To:      to,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("You asked to use this address for your account. Follow this link to confirm:\n\n%s\n\n"+
+			"The link expires in %d hours. Until then your account keeps its current address.\n\n"+
+			"If you did not ask for this, ignore this email.\n", link, int(s.config.ConfirmTTL.Hours())),
+	}, nil
+}
+
+func (s *service) noticeMessage(to, newEmail, token string) (mail.Message, error) {
+	link, err := tokenLink(s.config.LinkURL, RevertTokenParam, token)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		To:      to,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf("Someone asked to change the email address of your account to %s. It changes once "+
+			"the new address is confirmed.\n\nIf it was not you, follow this link to keep this address and "+
+			"sign out everywhere, then change your password:\n\n%s\n\nThe link works for %d days.\n",
+			newEmail, link, int(s.config.RevertTTL.Hours()/24)),
+	}, nil
+}
+
+// tokenLink returns base with token in the query parameter param
+func tokenLink(base, param, token string) (string, error) {
+	link, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	query := link.Query()
+	query.Set(param, token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
@@ -0,0 +1,198 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	authservice "example.com/internal/domain/service/auth"
+	usernameservice "example.com/internal/domain/service/username"
+	"example.com/pkg/oidc"
+)
+
+var (
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrIdentityLinked   = errors.New("identity is linked to another user")
+	ErrEmailNotVerified = errors.New("provider did not verify the email address")
+	ErrEmailInUse       = errors.New("email belongs to an existing user; sign in and link the provider instead")
+	ErrLastIdentity     = errors.New("cannot unlink the only way to sign in")
+)
+
+type Service interface {
+	// SignIn returns the user linked to the identity, creating the user on first sign-in
+	SignIn(ctx context.Context, provider string, claims *oidc.Claims) (*entity.User, error)
+	// Link attaches the identity to userID; linking an identity twice is a no-op
+	Link(ctx context.Context, userID, provider string, claims *oidc.Claims) (*entity.UserIdentity, error)
+	List(ctx context.Context, userID string) ([]*entity.UserIdentity, error)
+	// Unlink removes an identity unless the user was created through it and has no other one
+	Unlink(ctx context.Context, userID, identityID string) error
+}
+
+type service struct {
+	identityRepo    repository.UserIdentityRepository
+	userRepo        repository.UserRepository
+	authService     authservice.Service
+	usernameService usernameservice.Service
+	now             func() time.Time
+}
+
+func NewService(
+	identityRepo repository.UserIdentityRepository,
+	userRepo repository.UserRepository,
+	authService authservice.Service,
+	usernameService usernameservice.Service,
+) Service {
+	return &service{
+		identityRepo:    identityRepo,
+		userRepo:        userRepo,
+		authService:     authService,
+		usernameService: usernameService,
+		now:             time.Now,
+	}
+}
+
+func (s *service) SignIn(ctx context.Context, provider string, claims *oidc.Claims) (*entity.User, error) {
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user.Locked() {
+			return nil, authservice.ErrAccountLocked
+		}
+		// Login tracking is informational and must not fail the sign-in
+		_ = s.identityRepo.UpdateLastLogin(ctx, identity.ID, s.now())
+		_ = s.authService.UpdateLastLogin(ctx, user.ID)
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return s.provision(ctx, provider, claims)
+}
+
+// provision creates a user for an identity seen for the first time. An existing account
+// with the same email is never taken over: its owner has to sign in and link the provider.
+func (s *service) provision(ctx context.Context, provider string, claims *oidc.Claims) (*entity.User, error) {
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	if err := s.authService.CheckUserExists(ctx, claims.Email, ""); err != nil {
+		if errors.Is(err, authservice.ErrUserAlreadyExists) {
+			return nil, ErrEmailInUse
+		}
+		return nil, err
+	}
+
+	localPart, _, _ := strings.Cut(claims.Email, "@")
+	userName, err := s.usernameService.Generate(ctx, claims.PreferredUsername, localPart)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user signs in through the provider; nobody knows this password
+	password, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.authService.CreateUser(ctx, claims.Email, password, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	identity := &entity.UserIdentity{
+		ID:          uuid.NewString(),
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		Provisioned: true,
+		LastLoginAt: &now,
+	}
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	// Only emails the provider verified are provisioned
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *service) Link(ctx context.Context, userID, provider string, claims *oidc.Claims) (*entity.UserIdentity, error) {
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		if identity.UserID != userID {
+			return nil, ErrIdentityLinked
+		}
+		return identity, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	identity = &entity.UserIdentity{
+		ID:       uuid.NewString(),
+		UserID:   userID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *service) List(ctx context.Context, userID string) ([]*entity.UserIdentity, error) {
+	return s.identityRepo.FindByUserID(ctx, userID)
+}
+
+func (s *service) Unlink(ctx context.Context, userID, identityID string) error {
+	identities, err := s.identityRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var target *entity.UserIdentity
+	for _, identity := range identities {
+		if identity.ID == identityID {
+			target = identity
+		}
+	}
+	if target == nil {
+		return ErrIdentityNotFound
+	}
+	// A provisioned user has no known password, so its last identity is its only login
+	if len(identities) == 1 && target.Provisioned {
+		return ErrLastIdentity
+	}
+
+	err = s.identityRepo.Delete(ctx, userID, identityID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrIdentityNotFound
+	}
+	return err
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,571 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/security"
+)
+
+// codeChallengeMethod is the only PKCE method accepted; plain would reveal the verifier
+const codeChallengeMethod = "S256"
+
+var (
+	ErrClientNotFound          = errors.New("unknown client")
+	ErrInvalidRedirectURI      = errors.New("redirect URI is not registered for the client")
+	ErrInvalidClient           = errors.New("client authentication failed")
+	ErrInvalidClientMetadata   = errors.New("invalid client registration")
+	ErrUnauthorizedClient      = errors.New("client is not allowed to use this grant")
+	ErrUnsupportedResponseType = errors.New("only the code response type is supported")
+	ErrPKCERequired            = errors.New("an S256 code challenge is required")
+	ErrInvalidScope            = errors.New("invalid scope")
+	ErrInvalidGrant            = errors.New("invalid, expired or used authorization code")
+	ErrUnsupportedGrantType    = errors.New("unsupported grant type")
+	ErrLoginRequired           = errors.New("the user has to log in")
+	ErrConsentRequired         = errors.New("the user has to consent")
+	ErrAccessDenied            = errors.New("the user denied the request")
+)
+
+// Config tunes the authorization server
+type Config struct {
+	// FirstPartyClients skip the consent screen
+	FirstPartyClients []string
+	CodeTTL           time.Duration
+	AccessTokenTTL    time.Duration
+}
+
+// AuthorizationRequest holds the parameters of a request to the authorization endpoint
+type AuthorizationRequest struct {
+	ClientID            string   `json:"client_id"`
+	RedirectURI         string   `json:"redirect_uri"`
+	ResponseType        string   `json:"response_type"`
+	State               string   `json:"state,omitempty"`
+	Nonce               string   `json:"nonce,omitempty"`
+	CodeChallenge       string   `json:"code_challenge"`
+	CodeChallengeMethod string   `json:"code_challenge_method"`
+	Scopes              []string `json:"scopes"`
+}
+
+// TokenRequest holds the parameters of a request to the token endpoint
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	Scopes       []string
+}
+
+// Introspection describes a token to a resource server (RFC 7662)
+type Introspection struct {
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	ClientID  string
+	UserID    string
+	Scopes    []string
+	Active    bool
+}
+
+type Service interface {
+	// RegisterClient creates a client owned by ownerID and returns its secret, which is not
+	// stored; public clients get no secret
+	RegisterClient(
+		ctx context.Context, ownerID, name string, redirectURIs, grantTypes, scopes []string, public bool,
+	) (*entity.OAuthClient, string, error)
+	ListClients(ctx context.Context, ownerID string) ([]*entity.OAuthClient, error)
+	DeleteClient(ctx context.Context, ownerID, clientID string) error
+
+	// Authorize validates an authorization request. ErrClientNotFound and ErrInvalidRedirectURI
+	// must be shown to the user: redirecting to an unverified URI would make an open redirect.
+	Authorize(ctx context.Context, req *AuthorizationRequest) (*entity.OAuthClient, error)
+	// NeedsConsent reports whether userID has yet to grant scopes to client
+	NeedsConsent(ctx context.Context, userID string, client *entity.OAuthClient, scopes []string) (bool, error)
+	GrantConsent(ctx context.Context, userID, clientID string, scopes []string) error
+	// IssueCode issues an authorization code for a validated request of userID
+	IssueCode(ctx context.Context, userID string, req *AuthorizationRequest) (string, error)
+
+	// AuthenticateClient checks the credentials of a client calling the token endpoints;
+	// public clients authenticate with their ID alone
+	AuthenticateClient(ctx context.Context, clientID, secret string) (*entity.OAuthClient, error)
+	// ExchangeCode redeems an authorization code. Redeeming a code twice revokes the tokens
+	// issued for it, as the code has leaked.
+	ExchangeCode(ctx context.Context, client *entity.OAuthClient, code, redirectURI, codeVerifier string) (*entity.OAuthTokenSet, error)
+	ClientCredentials(ctx context.Context, client *entity.OAuthClient, scopes []string) (*entity.OAuthTokenSet, error)
+	// Introspect describes a token to a confidential client. Tokens of locked or deleted users
+	// are inactive.
+	Introspect(ctx context.Context, caller *entity.OAuthClient, token string) (*Introspection, error)
+	// Revoke revokes a token issued to caller; other and unknown tokens are ignored (RFC 7009)
+	Revoke(ctx context.Context, caller *entity.OAuthClient, token string) error
+}
+
+type service struct {
+	clientRepo  repository.OAuthClientRepository
+	codeRepo    repository.OAuthAuthorizationCodeRepository
+	consentRepo repository.OAuthConsentRepository
+	tokenRepo   repository.OAuthAccessTokenRepository
+	userRepo    repository.UserRepository
+	issuer      *security.JWTIssuer
+	now         func() time.Time
+	config      Config
+}
+
+// NewService creates the authorization server; a nil issuer disables ID tokens and the openid scope
+func NewService(
+	clientRepo repository.OAuthClientRepository,
+	codeRepo repository.OAuthAuthorizationCodeRepository,
+	consentRepo repository.OAuthConsentRepository,
+	tokenRepo repository.OAuthAccessTokenRepository,
+	userRepo repository.UserRepository,
+	issuer *security.JWTIssuer,
+	config Config,
+) Service {
+	return &service{
+		clientRepo:  clientRepo,
+		codeRepo:    codeRepo,
+		consentRepo: consentRepo,
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		issuer:      issuer,
+		now:         time.Now,
+		config:      config,
+	}
+}
+
+func (s *service) RegisterClient(
+	ctx context.Context, ownerID, name string, redirectURIs, grantTypes, scopes []string, public bool,
+) (*entity.OAuthClient, string, error) {
+	if err := validateClient(redirectURIs, grantTypes, scopes, public); err != nil {
+		return nil, "", err
+	}
+
+	client := &entity.OAuthClient{
+		ID:           uuid.NewString(),
+		OwnerID:      ownerID,
+		Name:         name,
+		RedirectURIs: append([]string{}, redirectURIs...),
+		GrantTypes:   normalize(grantTypes),
+		Scopes:       normalize(scopes),
+	}
+
+	var secret string
+	if !public {
+		var err error
+		secret, err = security.GenerateToken(entity.OAuthClientSecretPrefix)
+		if err != nil {
+			return nil, "", err
+		}
+		client.SecretHash = security.HashToken(secret)
+	}
+
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+func (s *service) ListClients(ctx context.Context, ownerID string) ([]*entity.OAuthClient, error) {
+	return s.clientRepo.FindByOwnerID(ctx, ownerID)
+}
+
+func (s *service) DeleteClient(ctx context.Context, ownerID, clientID string) error {
+	err := s.clientRepo.Delete(ctx, ownerID, clientID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrClientNotFound
+	}
+	return err
+}
+
+func (s *service) Authorize(ctx context.Context, req *AuthorizationRequest) (*entity.OAuthClient, error) {
+	client, err := s.client(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return client, ErrUnsupportedResponseType
+	}
+	if !client.AllowsGrant(entity.GrantTypeAuthorizationCode) {
+		return client, ErrUnauthorizedClient
+	}
+	// A verifier has 43 to 128 characters, so its S256 challenge always has 43
+	if req.CodeChallengeMethod != codeChallengeMethod || len(req.CodeChallenge) != 43 {
+		return client, ErrPKCERequired
+	}
+	if err := s.checkScopes(client, req.Scopes, true); err != nil {
+		return client, err
+	}
+
+	return client, nil
+}
+
+func (s *service) NeedsConsent(ctx context.Context, userID string, client *entity.OAuthClient, scopes []string) (bool, error) {
+	if slices.Contains(s.config.FirstPartyClients, client.ID) {
+		return false, nil
+	}
+
+	consent, err := s.consentRepo.Find(ctx, userID, client.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !consent.Covers(scopes), nil
+}
+
+func (s *service) GrantConsent(ctx context.Context, userID, clientID string, scopes []string) error {
+	granted := slices.Clone(scopes)
+	if consent, err := s.consentRepo.Find(ctx, userID, clientID); err == nil {
+		granted = append(granted, consent.Scopes...)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return s.consentRepo.Save(ctx, &entity.OAuthConsent{
+		UserID:   userID,
+		ClientID: clientID,
+		Scopes:   normalize(granted),
+	})
+}
+
+func (s *service) IssueCode(ctx context.Context, userID string, req *AuthorizationRequest) (string, error) {
+	plain, err := security.GenerateToken("")
+	if err != nil {
+		return "", err
+	}
+
+	code := &entity.OAuthAuthorizationCode{
+		ID:            uuid.NewString(),
+		CodeHash:      security.HashToken(plain),
+		ClientID:      req.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+		Scopes:        req.Scopes,
+		ExpiresAt:     s.now().Add(s.config.CodeTTL),
+	}
+	if err := s.codeRepo.Create(ctx, code); err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+func (s *service) AuthenticateClient(ctx context.Context, clientID, secret string) (*entity.OAuthClient, error) {
+	client, err := s.client(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if !client.Confidential() {
+		if secret != "" {
+			return nil, ErrInvalidClient
+		}
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(security.HashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
+
+func (s *service) ExchangeCode(
+	ctx context.Context, client *entity.OAuthClient, plain, redirectURI, codeVerifier string,
+) (*entity.OAuthTokenSet, error) {
+	if !client.AllowsGrant(entity.GrantTypeAuthorizationCode) {
+		return nil, ErrUnauthorizedClient
+	}
+
+	code, err := s.codeRepo.FindByHash(ctx, security.HashToken(plain))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	if code.ClientID != client.ID {
+		return nil, ErrInvalidGrant
+	}
+	if code.UsedAt != nil {
+		return nil, s.revokeCode(ctx, code.ID, now)
+	}
+	if !now.Before(code.ExpiresAt) || code.RedirectURI != redirectURI || !verifyChallenge(codeVerifier, code.CodeChallenge) {
+		return nil, ErrInvalidGrant
+	}
+
+	if err := s.codeRepo.MarkUsed(ctx, code.ID, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Redeemed concurrently by someone else
+			return nil, s.revokeCode(ctx, code.ID, now)
+		}
+		return nil, err
+	}
+
+	tokens, err := s.issueAccessToken(ctx, client.ID, code.UserID, code.ID, code.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(code.Scopes, entity.OAuthScopeOpenID) {
+		tokens.IDToken, err = s.idToken(ctx, client.ID, code)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+func (s *service) ClientCredentials(ctx context.Context, client *entity.OAuthClient, scopes []string) (*entity.OAuthTokenSet, error) {
+	if !client.Confidential() || !client.AllowsGrant(entity.GrantTypeClientCredentials) {
+		return nil, ErrUnauthorizedClient
+	}
+	if err := s.checkScopes(client, scopes, false); err != nil {
+		return nil, err
+	}
+	return s.issueAccessToken(ctx, client.ID, "", "", scopes)
+}
+
+func (s *service) Introspect(ctx context.Context, caller *entity.OAuthClient, plain string) (*Introspection, error) {
+	if !caller.Confidential() {
+		return nil, ErrUnauthorizedClient
+	}
+	if !security.HasTokenPrefix(plain, entity.OAuthAccessTokenPrefix) {
+		return &Introspection{}, nil
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, security.HashToken(plain))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &Introspection{}, nil
+		}
+		return nil, err
+	}
+	if !token.Active(s.now()) {
+		return &Introspection{}, nil
+	}
+	if token.UserID != "" {
+		// Tokens of users locked or deleted since they were issued are no longer active,
+		// whether or not they were revoked with the user
+		user, err := s.userRepo.FindByID(ctx, token.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &Introspection{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if user.Locked() {
+			return &Introspection{}, nil
+		}
+	}
+
+	return &Introspection{
+		Active:    true,
+		ClientID:  token.ClientID,
+		UserID:    token.UserID,
+		Scopes:    token.Scopes,
+		IssuedAt:  token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+func (s *service) Revoke(ctx context.Context, caller *entity.OAuthClient, plain string) error {
+	if !security.HasTokenPrefix(plain, entity.OAuthAccessTokenPrefix) {
+		return nil
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, security.HashToken(plain))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if token.ClientID != caller.ID {
+		return nil
+	}
+	return s.tokenRepo.Revoke(ctx, token.ID, s.now())
+}
+
+// client looks up a client, reporting malformed IDs as unknown
+func (s *service) client(ctx context.Context, clientID string) (*entity.OAuthClient, error) {
+	if _, err := uuid.Parse(clientID); err != nil {
+		return nil, ErrClientNotFound
+	}
+
+	client, err := s.clientRepo.FindByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClientNotFound
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// checkScopes requires scopes the client is registered for; openid needs a user and ID token signing keys
+func (s *service) checkScopes(client *entity.OAuthClient, scopes []string, forUser bool) error {
+	if forUser && len(scopes) == 0 {
+		return fmt.Errorf("%w: no scope requested", ErrInvalidScope)
+	}
+	if !client.AllowsScopes(scopes) {
+		return fmt.Errorf("%w: the client is not registered for every requested scope", ErrInvalidScope)
+	}
+	if slices.Contains(scopes, entity.OAuthScopeOpenID) && (!forUser || s.issuer == nil) {
+		return fmt.Errorf("%w: openid is not available", ErrInvalidScope)
+	}
+	return nil
+}
+
+func (s *service) issueAccessToken(ctx context.Context, clientID, userID, codeID string, scopes []string) (*entity.OAuthTokenSet, error) {
+	plain, err := security.GenerateToken(entity.OAuthAccessTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &entity.OAuthAccessToken{
+		ID:        uuid.NewString(),
+		TokenHash: security.HashToken(plain),
+		ClientID:  clientID,
+		UserID:    userID,
+		CodeID:    codeID,
+		Scopes:    scopes,
+		ExpiresAt: s.now().Add(s.config.AccessTokenTTL),
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &entity.OAuthTokenSet{
+		AccessToken: plain,
+		ExpiresAt:   token.ExpiresAt,
+		Scopes:      scopes,
+	}, nil
+}
+
+// idTokenClaims are the claims of an OpenID Connect ID token
+type idTokenClaims struct {
+	Issuer            string `json:"iss"`
+	Subject           string `json:"sub"`
+	Audience          string `json:"aud"`
+	Nonce             string `json:"nonce,omitempty"`
+	Email             string `json:"email,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	IssuedAt          int64  `json:"iat"`
+	ExpiresAt         int64  `json:"exp"`
+}
+
+func (s *service) idToken(ctx context.Context, clientID string, code *entity.OAuthAuthorizationCode) (string, error) {
+	user, err := s.userRepo.FindByID(ctx, code.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
+	claims := idTokenClaims{
+		Issuer:    s.issuer.Issuer(),
+		Subject:   user.ID,
+		Audience:  clientID,
+		Nonce:     code.Nonce,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.config.AccessTokenTTL).Unix(),
+	}
+	if slices.Contains(code.Scopes, entity.OAuthScopeEmail) {
+		claims.Email = user.Email
+	}
+	if slices.Contains(code.Scopes, entity.OAuthScopeProfile) {
+		claims.PreferredUsername = user.UserName
+	}
+	return s.issuer.Sign(claims)
+}
+
+// revokeCode answers the replay of an authorization code
+func (s *service) revokeCode(ctx context.Context, codeID string, now time.Time) error {
+	if err := s.tokenRepo.RevokeByCode(ctx, codeID, now); err != nil {
+		return err
+	}
+	return ErrInvalidGrant
+}
+
+// normalize sorts values and drops duplicates, returning an empty slice rather than nil
+func normalize(values []string) []string {
+	return append([]string{}, slices.Compact(slices.Sorted(slices.Values(values)))...)
+}
+
+func verifyChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	digest := sha256.Sum256([]byte(verifier))
+	return subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(digest[:])), []byte(challenge)) == 1
+}
+
+func validateClient(redirectURIs, grantTypes, scopes []string, public bool) error {
+	if len(grantTypes) == 0 {
+		return fmt.Errorf("%w: at least one grant type is required", ErrInvalidClientMetadata)
+	}
+	for _, grantType := range grantTypes {
+		switch grantType {
+		case entity.GrantTypeAuthorizationCode:
+		case entity.GrantTypeClientCredentials:
+			if public {
+				return fmt.Errorf("%w: public clients cannot use client credentials", ErrInvalidClientMetadata)
+			}
+		default:
+			return fmt.Errorf("%w: unsupported grant type %s", ErrInvalidClientMetadata, grantType)
+		}
+	}
+
+	if slices.Contains(grantTypes, entity.GrantTypeAuthorizationCode) && len(redirectURIs) == 0 {
+		return fmt.Errorf("%w: the authorization code grant needs a redirect URI", ErrInvalidClientMetadata)
+	}
+	for _, uri := range redirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return fmt.Errorf("%w: redirect URI %s %s", ErrInvalidClientMetadata, uri, err)
+		}
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(entity.OAuthScopes, scope) {
+			return fmt.Errorf("%w: unknown scope %s", ErrInvalidClientMetadata, scope)
+		}
+	}
+	return nil
+}
+
+// validateRedirectURI accepts absolute URIs without fragment. Plain HTTP is only allowed on
+// the loopback interface, for native apps and local development (RFC 8252).
+func validateRedirectURI(uri string) error {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme == "" {
+		return errors.New("is not an absolute URI")
+	}
+	if parsed.Fragment != "" {
+		return errors.New("must not have a fragment")
+	}
+	if parsed.Scheme == "http" {
+		switch parsed.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+		default:
+			return errors.New("must use https")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/mail"
+	"example.com/pkg/security"
+)
+
+// InvitationTokenParam is the query parameter carrying the token of an invitation link
+const InvitationTokenParam = "invitation_token"
+
+var (
+	// ErrOrganizationNotFound is also returned to users who are not members, so that they
+	// cannot tell which organizations exist
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrInvalidName          = errors.New("organization names cannot be blank or hold control characters")
+	ErrNotAllowed           = errors.New("your role in the organization does not allow this")
+	ErrInvalidRole          = errors.New("members are invited as admin or member")
+	ErrAlreadyMember        = errors.New("the user is already a member of the organization")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrOwnerRemoval         = errors.New("the owner cannot leave the organization, transfer the ownership first")
+	// ErrInvitationNotFound is returned for unknown, expired and answered invitations and for
+	// invitations addressed to another email
+	ErrInvitationNotFound = errors.New("invitation not found, expired or not addressed to you")
+)
+
+// Config tunes invitations
+type Config struct {
+	// InviteURL is the page invitation links point to
+	InviteURL string
+	TTL       time.Duration
+}
+
+type Service interface {
+	// Create creates an organization owned by userID and returns the owner's membership
+	Create(ctx context.Context, userID, name string) (*entity.Membership, error)
+	// List returns the memberships of userID with their organization
+	List(ctx context.Context, userID string) ([]*entity.Membership, error)
+	// Members lists the members of the organization to one of them
+	Members(ctx context.Context, actorID, organizationID string) ([]*entity.Membership, error)
+	// RemoveMember takes userID out of the organization. Owners remove anyone but themselves,
+	// admins remove members and everyone but the owner may leave.
+	RemoveMember(ctx context.Context, actorID, organizationID, userID string) error
+	// TransferOwnership lets the owner hand the organization to another member, staying an admin
+	TransferOwnership(ctx context.Context, actorID, organizationID, newOwnerID string) error
+	// Invite emails an invitation to join the organization with role, voiding the pending
+	// invitations of email. Owners invite admins and members, admins invite members.
+	Invite(ctx context.Context, actorID, organizationID, email, role string) (*entity.Invitation, error)
+	// Invitations lists the pending invitations of the organization to its owner and admins
+	Invitations(ctx context.Context, actorID, organizationID string) ([]*entity.Invitation, error)
+	// RevokeInvitation voids a pending invitation of the organization
+	RevokeInvitation(ctx context.Context, actorID, organizationID, invitationID string) error
+	// Accept makes userID a member with the invitation of token, which must be addressed to
+	// the user's email, and returns the membership with its organization
+	Accept(ctx context.Context, userID, token string) (*entity.Membership, error)
+	// Decline refuses the invitation of token addressed to the email of userID
+	Decline(ctx context.Context, userID, token string) error
+}
+
+type service struct {
+	organizationRepo repository.OrganizationRepository
+	invitationRepo   repository.InvitationRepository
+	userRepo         repository.UserRepository
+	sender           mail.Sender
+	now              func() time.Time
+	config           Config
+}
+
+func NewService(
+	organizationRepo repository.OrganizationRepository,
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	sender mail.Sender,
+	config Config,
+) Service {
+	return &service{
+		organizationRepo: organizationRepo,
+		invitationRepo:   invitationRepo,
+		userRepo:         userRepo,
+		sender:           sender,
+		now:              time.Now,
+		config:           config,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID, name string) (*entity.Membership, error) {
+	// The name goes into the subject of invitation emails
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsFunc(name, unicode.IsControl) {
+		return nil, ErrInvalidName
+	}
+
+	organization := &entity.Organization{ID: uuid.NewString(), Name: name}
+	owner := &entity.Membership{OrganizationID: organization.ID, UserID: userID, Role: entity.MembershipOwner}
+	if err := s.organizationRepo.Create(ctx, organization, owner); err != nil {
+		return nil, err
+	}
+
+	owner.Organization = organization
+	return owner, nil
+}
+
+func (s *service) List(ctx context.Context, userID string) ([]*entity.Membership, error) {
+	return s.organizationRepo.ListByUserID(ctx, userID)
+}
+
+func (s *service) Members(ctx context.Context, actorID, organizationID string) ([]*entity.Membership, error) {
+	if _, err := s.membership(ctx, organizationID, actorID); err != nil {
+		return nil, err
+	}
+	return s.organizationRepo.ListMembers(ctx, organizationID)
+}
+
+func (s *service) RemoveMember(ctx context.Context, actorID, organizationID, userID string) error {
+	actor, err := s.membership(ctx, organizationID, actorID)
+	if err != nil {
+		return err
+	}
+	member := actor
+	if userID != actorID {
+		if member, err = s.member(ctx, organizationID, userID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case member.Role == entity.MembershipOwner:
+		return ErrOwnerRemoval
+	case userID != actorID && !actor.Manages():
+		return ErrNotAllowed
+	case member.Role == entity.MembershipAdmin && userID != actorID && actor.Role != entity.MembershipOwner:
+		return ErrNotAllowed
+	}
+
+	err = s.organizationRepo.RemoveMember(ctx, organizationID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMemberNotFound
+	}
+	return err
+}
+
+func (s *service) TransferOwnership(ctx context.Context, actorID, organizationID, newOwnerID string) error {
+	actor, err := s.membership(ctx, organizationID, actorID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != entity.MembershipOwner {
+		return ErrNotAllowed
+	}
+	if newOwnerID == actorID {
+		return nil
+	}
+	if _, err := s.member(ctx, organizationID, newOwnerID); err != nil {
+		return err
+	}
+
+	err = s.organizationRepo.TransferOwnership(ctx, organizationID, actorID, newOwnerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The actor stopped being the owner or the new owner left in the meantime
+		return ErrNotAllowed
+	}
+	return err
+}
+
+func (s *service) Invite(ctx context.Context, actorID, organizationID, email, role string) (*entity.Invitation, error) {
+	actor, err := s.membership(ctx, organizationID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if role != entity.MembershipAdmin && role != entity.MembershipMember {
+		return nil, ErrInvalidRole
+	}
+	if !actor.Manages() || (role == entity.MembershipAdmin && actor.Role != entity.MembershipOwner) {
+		return nil, ErrNotAllowed
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if _, err := s.member(ctx, organizationID, user.ID); err == nil {
+			return nil, ErrAlreadyMember
+		} else if !errors.Is(err, ErrMemberNotFound) {
+			return nil, err
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	organization, err := s.organizationRepo.FindByID(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := security.GenerateToken(entity.InvitationPrefix)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := s.message(email, organization, token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if err := s.invitationRepo.RevokePending(ctx, organizationID, email, now); err != nil {
+		return nil, err
+	}
+	invitation := &entity.Invitation{
+		ID:             uuid.NewString(),
+		OrganizationID: organizationID,
+		Email:          email,
+		Role:           role,
+		InvitedBy:      actorID,
+		Status:         entity.InvitationPending,
+		TokenHash:      security.HashToken(token),
+		ExpiresAt:      now.Add(s.config.TTL),
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	if err := s.sender.Send(ctx, msg); err != nil {
+		return nil, fmt.Errorf("send invitation: %w", err)
+	}
+	return invitation, nil
+}
+
+func (s *service) Invitations(ctx context.Context, actorID, organizationID string) ([]*entity.Invitation, error) {
+	actor, err := s.membership(ctx, organizationID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.Manages() {
+		return nil, ErrNotAllowed
+	}
+	return s.invitationRepo.ListPending(ctx, organizationID, s.now())
+}
+
+func (s *service) RevokeInvitation(ctx context.Context, actorID, organizationID, invitationID string) error {
+	actor, err := s.membership(ctx, organizationID, actorID)
+	if err != nil {
+		return err
+	}
+	if !actor.Manages() {
+		return ErrNotAllowed
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && invitation.OrganizationID != organizationID) {
+		return ErrInvitationNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.invitationRepo.Respond(ctx, invitation.ID, entity.InvitationRevoked, s.now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvitationNotFound
+	}
+	return err
+}
+
+func (s *service) Accept(ctx context.Context, userID, token string) (*entity.Membership, error) {
+	invitation, err := s.invitation(ctx, userID, token)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.member(ctx, invitation.OrganizationID, userID); err == nil {
+		return nil, ErrAlreadyMember
+	} else if !errors.Is(err, ErrMemberNotFound) {
+		return nil, err
+	}
+
+	organization, err := s.organizationRepo.FindByID(ctx, invitation.OrganizationID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	membership := &entity.Membership{OrganizationID: organization.ID, UserID: userID, Role: invitation.Role}
+	err = s.invitationRepo.Accept(ctx, invitation.ID, membership, s.now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	membership.Organization = organization
+	return membership, nil
+}
+
+func (s *service) Decline(ctx context.Context, userID, token string) error {
+	invitation, err := s.invitation(ctx, userID, token)
+	if err != nil {
+		return err
+	}
+
+	err = s.invitationRepo.Respond(ctx, invitation.ID, entity.InvitationDeclined, s.now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvitationNotFound
+	}
+	return err
+}
+
+// invitation finds the pending invitation of token addressed to the email of userID
+func (s *service) invitation(ctx context.Context, userID, token string) (*entity.Invitation, error) {
+	invitation, err := s.invitationRepo.FindByTokenHash(ctx, security.HashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !invitation.Pending(s.now()) {
+		return nil, ErrInvitationNotFound
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// A leaked link must not let someone else join
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+// membership returns the membership of the actor, hiding organizations the actor is not in
+func (s *service) membership(ctx context.Context, organizationID, actorID string) (*entity.Membership, error) {
+	membership, err := s.organizationRepo.FindMembership(ctx, organizationID, actorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrganizationNotFound
+	}
+	return membership, err
+}
+
+func (s *service) member(ctx context.Context, organizationID, userID string) (*entity.Membership, error) {
+	membership, err := s.organizationRepo.FindMembership(ctx, organizationID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	return membership, err
+}
+
+// message writes the email delivering the invitation token
+func (s *service) message(to string, organization *entity.Organization, token string) (mail.Message, error) {
+	link, err := url.Parse(s.config.InviteURL)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	query := link.Query()
+	query.Set(InvitationTokenParam, token)
+	link.RawQuery = query.Encode()
+
+	return mail.Message{
+		To:      to,
+		Subject: fmt.Sprintf("You are invited to join %s", organization.Name),
+		Body: fmt.Sprintf("You are invited to join the organization %s. Follow this link to accept or decline "+
+			"the invitation:\n\n%s\n\nThe link expires in %d hours. Sign in or sign up with this email address "+
+			"to answer it.\n\nIf you did not expect this invitation, you can ignore this email.\n",
+			organization.Name, link, int(s.config.TTL.Hours())),
+	}, nil
+}
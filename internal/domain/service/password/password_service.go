@@ -0,0 +1,172 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/security"
+)
+
+// breachedViolation is reported for passwords found by the breach checker
+const breachedViolation = "appears in a known data breach"
+
+var (
+	ErrIncorrectPassword = errors.New("incorrect current password")
+	ErrPasswordReused    = errors.New("password was used recently")
+)
+
+type Service interface {
+	// Check returns an error matching security.ErrWeakPassword when password breaks the policy
+	// for a user with the given username and email or is known from a data breach
+	Check(ctx context.Context, password, username, email string) error
+	// Change replaces the password of userID after verifying the current one and revokes
+	// every session and token of the user, including personal access tokens and the OAuth2
+	// grants of applications. It satisfies a required password reset.
+	Change(ctx context.Context, userID, current, next string) (*entity.User, error)
+}
+
+// Config tunes the password service
+type Config struct {
+	Policy *security.PasswordPolicy
+	// BreachChecker refuses passwords known from data breaches unless it is nil
+	BreachChecker security.PasswordBreachChecker
+	// HistorySize is the number of previous passwords a new password must not repeat; the
+	// current password is always refused
+	HistorySize int
+}
+
+type service struct {
+	userRepo             repository.UserRepository
+	historyRepo          repository.PasswordHistoryRepository
+	refreshTokenRepo     repository.RefreshTokenRepository
+	apiTokenRepo         repository.APITokenRepository
+	oauthCodeRepo        repository.OAuthAuthorizationCodeRepository
+	oauthAccessTokenRepo repository.OAuthAccessTokenRepository
+	hasher               security.PasswordHasher
+	config               Config
+	now                  func() time.Time
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	historyRepo repository.PasswordHistoryRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	apiTokenRepo repository.APITokenRepository,
+	oauthCodeRepo repository.OAuthAuthorizationCodeRepository,
+	oauthAccessTokenRepo repository.OAuthAccessTokenRepository,
+	hasher security.PasswordHasher,
+	config Config,
+) Service {
+	return &service{
+		userRepo:             userRepo,
+		historyRepo:          historyRepo,
+		refreshTokenRepo:     refreshTokenRepo,
+		apiTokenRepo:         apiTokenRepo,
+		oauthCodeRepo:        oauthCodeRepo,
+		oauthAccessTokenRepo: oauthAccessTokenRepo,
+		hasher:               hasher,
+		config:               config,
+		now:                  time.Now,
+	}
+}
+
+func (s *service) Check(ctx context.Context, password, username, email string) error {
+	if s.config.Policy != nil {
+		if err := s.config.Policy.Check(password, username, email); err != nil {
+			return err
+		}
+	}
+
+	// The breach check comes last as it reads the dataset
+	if s.config.BreachChecker != nil {
+		breached, err := s.config.BreachChecker.Breached(ctx, password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return &security.PasswordPolicyError{Violations: []string{breachedViolation}}
+		}
+	}
+	return nil
+}
+
+func (s *service) Change(ctx context.Context, userID, current, next string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.hasher.Verify(current, user.PasswordHash) {
+		return nil, ErrIncorrectPassword
+	}
+	if err := s.Check(ctx, next, user.UserName, user.Email); err != nil {
+		return nil, err
+	}
+	if err := s.checkReuse(ctx, user, next); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(next)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.HistorySize > 0 {
+		entry := &entity.PasswordHistory{ID: uuid.NewString(), UserID: user.ID, PasswordHash: user.PasswordHash}
+		if err := s.historyRepo.Create(ctx, entry); err != nil {
+			return nil, err
+		}
+		if err := s.historyRepo.Prune(ctx, user.ID, s.config.HistorySize); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.now()
+	user.PasswordHash = hash
+	user.PasswordChangedAt = &now
+	user.SessionsRevokedAt = &now
+	user.PasswordResetRequired = false
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Whoever knew the old password may have created tokens with it, or authorized an
+	// application of theirs
+	for _, revoke := range []func(context.Context, string, time.Time) error{
+		s.refreshTokenRepo.RevokeUser,
+		s.apiTokenRepo.RevokeUser,
+		s.oauthCodeRepo.RevokeUser,
+		s.oauthAccessTokenRepo.RevokeUser,
+	} {
+		if err := revoke(ctx, user.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// checkReuse refuses the current password and the ones kept in the history
+func (s *service) checkReuse(ctx context.Context, user *entity.User, next string) error {
+	if s.hasher.Verify(next, user.PasswordHash) {
+		return ErrPasswordReused
+	}
+	if s.config.HistorySize == 0 {
+		return nil
+	}
+
+	entries, err := s.historyRepo.ListRecent(ctx, user.ID, s.config.HistorySize)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if s.hasher.Verify(next, entry.PasswordHash) {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package passwordless
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	authservice "example.com/internal/domain/service/auth"
+	"example.com/pkg/mail"
+	"example.com/pkg/security"
+)
+
+// LinkTokenParam is the query parameter carrying the token of a login link
+const LinkTokenParam = "passwordless_token"
+
+// codeDigits is the length of emailed login codes
+const codeDigits = 6
+
+var (
+	ErrInvalidMethod   = errors.New("unsupported passwordless login method")
+	ErrNoPendingLogin  = errors.New("no pending login for this device, or it expired")
+	ErrIncorrectSecret = errors.New("incorrect login code or link")
+	ErrTooManyAttempts = errors.New("too many incorrect attempts, request a new code")
+)
+
+// Config tunes passwordless login
+type Config struct {
+	// LinkURL is the page login links point to
+	LinkURL     string
+	TTL         time.Duration
+	MaxAttempts int
+	MaxPerHour  int
+}
+
+// Binding ties a challenge to the device that requested it. The device keeps it, e.g. in its
+// session, and presents it with the emailed link or code.
+type Binding struct {
+	ChallengeID  string `json:"challenge_id"`
+	DeviceSecret string `json:"device_secret"`
+}
+
+type Service interface {
+	// Start emails a login link or code to the user with the given email and returns the
+	// binding the requesting device has to present. Unknown emails and users over the hourly
+	// limit get a binding but no email, so that responses do not reveal who has an account.
+	// Starting a login voids the pending ones of the user.
+	Start(ctx context.Context, email, method string) (*Binding, error)
+	// Verify completes the challenge of binding with the token of the link or the code
+	Verify(ctx context.Context, binding Binding, secret string) (*entity.User, error)
+}
+
+type service struct {
+	challengeRepo repository.LoginChallengeRepository
+	userRepo      repository.UserRepository
+	authService   authservice.Service
+	sender        mail.Sender
+	now           func() time.Time
+	config        Config
+}
+
+func NewService(
+	challengeRepo repository.LoginChallengeRepository,
+	userRepo repository.UserRepository,
+	authService authservice.Service,
+	sender mail.Sender,
+	config Config,
+) Service {
+	return &service{
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		authService:   authService,
+		sender:        sender,
+		now:           time.Now,
+		config:        config,
+	}
+}
+
+func (s *service) Start(ctx context.Context, email, method string) (*Binding, error) {
+	if method != entity.LoginMethodLink && method != entity.LoginMethodCode {
+		return nil, ErrInvalidMethod
+	}
+
+	deviceSecret, err := security.GenerateToken("")
+	if err != nil {
+		return nil, err
+	}
+	binding := &Binding{ChallengeID: uuid.NewString(), DeviceSecret: deviceSecret}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return binding, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	sent, err := s.challengeRepo.CountSince(ctx, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if sent >= int64(s.config.MaxPerHour) {
+		return binding, nil
+	}
+
+	secret, err := newSecret(method)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := s.message(user.Email, method, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.challengeRepo.MarkPendingUsed(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	challenge := &entity.LoginChallenge{
+		ID:         binding.ChallengeID,
+		UserID:     user.ID,
+		Method:     method,
+		SecretHash: secretHash(binding.ChallengeID, secret),
+		DeviceHash: security.HashToken(deviceSecret),
+		ExpiresAt:  now.Add(s.config.TTL),
+	}
+	if err := s.challengeRepo.Create(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	if err := s.sender.Send(ctx, msg); err != nil {
+		return nil, fmt.Errorf("send login %s: %w", method, err)
+	}
+	return binding, nil
+}
+
+func (s *service) Verify(ctx context.Context, binding Binding, secret string) (*entity.User, error) {
+	challenge, err := s.challengeRepo.FindByID(ctx, binding.ChallengeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoPendingLogin
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if !constantTimeEqual(challenge.DeviceHash, security.HashToken(binding.DeviceSecret)) || !challenge.Pending(now) {
+		return nil, ErrNoPendingLogin
+	}
+	if challenge.Attempts >= s.config.MaxAttempts {
+		return nil, ErrTooManyAttempts
+	}
+
+	if !constantTimeEqual(challenge.SecretHash, secretHash(challenge.ID, strings.TrimSpace(secret))) {
+		if err := s.challengeRepo.RecordFailedAttempt(ctx, challenge.ID, s.config.MaxAttempts); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTooManyAttempts
+			}
+			return nil, err
+		}
+		if challenge.Attempts+1 >= s.config.MaxAttempts {
+			return nil, ErrTooManyAttempts
+		}
+		return nil, ErrIncorrectSecret
+	}
+
+	if err := s.challengeRepo.MarkUsed(ctx, challenge.ID, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoPendingLogin
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Locked() {
+		return nil, authservice.ErrAccountLocked
+	}
+	// Login tracking is informational and must not fail the login. The secret reached the
+	// user by email, which proves they own it.
+	_ = s.authService.UpdateLastLogin(ctx, user.ID)
+	_ = s.authService.VerifyEmail(ctx, user.ID)
+	return user, nil
+}
+
+// message writes the email delivering secret
+func (s *service) message(to, method, secret string) (mail.Message, error) {
+	minutes := int(s.config.TTL.Minutes())
+	footer := "If you did not try to sign in, you can ignore this email."
+
+	switch method {
+	case entity.LoginMethodLink:
+		link, err := url.Parse(s.config.LinkURL)
+		if err != nil {
+			return mail.Message{}, err
+		}
+		query := link.Query()
+		query.Set(LinkTokenParam, secret)
+		link.RawQuery = query.Encode()
+
+		return mail.Message{
+			To:      to,
+			Subject: "Your sign-in link",
+			Body: fmt.Sprintf("Follow this link to sign in:\n\n%s\n\nThe link expires in %d minutes and only works once, "+
+				"in the browser where you asked for it.\n\n%s\n", link, minutes, footer),
+		}, nil
+	case entity.LoginMethodCode:
+		return mail.Message{
+			To:      to,
+			Subject: "Your sign-in code",
+			Body: fmt.Sprintf("Your sign-in code is %s\n\nThe code expires in %d minutes and only works once, "+
+				"in the browser where you asked for it.\n\n%s\n", secret, minutes, footer),
+		}, nil
+	}
+	return mail.Message{}, ErrInvalidMethod
+}
+
+// newSecret returns the token of a login link or a zero-padded numeric code
+func newSecret(method string) (string, error) {
+	if method == entity.LoginMethodLink {
+		return security.GenerateToken(entity.LoginLinkPrefix)
+	}
+
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(codeDigits), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n), nil
+}
+
+// secretHash salts the secret with the challenge ID, as codes are short enough to be looked up
+// in a precomputed table
+func secretHash(challengeID, secret string) string {
+	return security.HashToken(challengeID + ":" + secret)
+}
+
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
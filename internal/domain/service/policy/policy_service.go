@@ -0,0 +1,172 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/authz"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrRoleNotFound    = errors.New("role not found")
+	ErrRoleNotAssigned = errors.New("role not assigned to the user")
+)
+
+// Service decides what users may do from the permissions of their roles
+type Service interface {
+	// Can reports whether one of the roles of userID grants permission
+	Can(ctx context.Context, userID, permission string) (bool, error)
+	ListRoles(ctx context.Context) ([]*entity.Role, error)
+	UserRoles(ctx context.Context, userID string) ([]*entity.Role, error)
+	Assign(ctx context.Context, userID, roleName string) error
+	Unassign(ctx context.Context, userID, roleName string) error
+	// SubjectAttributes returns the roles and permissions of a user and the organizations they
+	// are a member of and manage for the authorization policies; anonymous subjects have none
+	SubjectAttributes(ctx context.Context, subject authz.Entity) (map[string]any, error)
+	// ResourceAttributes returns the organizations a user resource is a member of for the
+	// authorization policies; other resources have no attributes to load
+	ResourceAttributes(ctx context.Context, resource authz.Entity) (map[string]any, error)
+}
+
+type service struct {
+	roleRepo         repository.RoleRepository
+	userRepo         repository.UserRepository
+	organizationRepo repository.OrganizationRepository
+}
+
+func NewService(
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+) Service {
+	return &service{
+		roleRepo:         roleRepo,
+		userRepo:         userRepo,
+		organizationRepo: organizationRepo,
+	}
+}
+
+func (s *service) Can(ctx context.Context, userID, permission string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	return s.roleRepo.UserHasPermission(ctx, userID, permission)
+}
+
+func (s *service) ListRoles(ctx context.Context) ([]*entity.Role, error) {
+	return s.roleRepo.List(ctx)
+}
+
+func (s *service) UserRoles(ctx context.Context, userID string) ([]*entity.Role, error) {
+	if err := s.findUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.FindByUserID(ctx, userID)
+}
+
+func (s *service) Assign(ctx context.Context, userID, roleName string) error {
+	if err := s.findUser(ctx, userID); err != nil {
+		return err
+	}
+	role, err := s.findRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	return s.roleRepo.Assign(ctx, userID, role.ID)
+}
+
+func (s *service) Unassign(ctx context.Context, userID, roleName string) error {
+	if err := s.findUser(ctx, userID); err != nil {
+		return err
+	}
+	role, err := s.findRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	err = s.roleRepo.Unassign(ctx, userID, role.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRoleNotAssigned
+	}
+	return err
+}
+
+func (s *service) SubjectAttributes(ctx context.Context, subject authz.Entity) (map[string]any, error) {
+	if subject.ID == "" {
+		return nil, nil
+	}
+	roles, err := s.roleRepo.FindByUserID(ctx, subject.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(roles))
+	var permissions []string
+	for _, role := range roles {
+		names = append(names, role.Name)
+		permissions = append(permissions, role.PermissionNames()...)
+	}
+	slices.Sort(permissions)
+
+	organizations, managed, err := s.organizations(ctx, subject.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"roles":                 names,
+		"permissions":           slices.Compact(permissions),
+		"organizations":         organizations,
+		"managed_organizations": managed,
+	}, nil
+}
+
+func (s *service) ResourceAttributes(ctx context.Context, resource authz.Entity) (map[string]any, error) {
+	if resource.Type != "user" || resource.ID == "" {
+		return nil, nil
+	}
+	organizations, _, err := s.organizations(ctx, resource.ID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"organizations": organizations}, nil
+}
+
+// organizations returns the IDs of the organizations userID is a member of and of those they
+// manage as owner or admin
+func (s *service) organizations(ctx context.Context, userID string) (member, managed []string, err error) {
+	memberships, err := s.organizationRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	member = make([]string, 0, len(memberships))
+	managed = []string{}
+	for _, membership := range memberships {
+		member = append(member, membership.OrganizationID)
+		if membership.Manages() {
+			managed = append(managed, membership.OrganizationID)
+		}
+	}
+	return member, managed, nil
+}
+
+func (s *service) findUser(ctx context.Context, userID string) error {
+	_, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (s *service) findRole(ctx context.Context, name string) (*entity.Role, error) {
+	role, err := s.roleRepo.FindByName(ctx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	return role, err
+}
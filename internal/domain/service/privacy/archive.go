@@ -0,0 +1,78 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+
+	"example.com/internal/domain/entity"
+)
+
+// archiveFile is a JSON document of a data export archive
+type archiveFile struct {
+	name    string
+	content any
+}
+
+// loginHistory is how the user logged in: every login recorded, and the passwordless logins
+// started with a link or code
+type loginHistory struct {
+	Logins             []*entity.AuditEvent     `json:"logins"`
+	PasswordlessLogins []*entity.LoginChallenge `json:"passwordless_logins"`
+}
+
+// buildArchive writes data as a ZIP archive of JSON documents. Lists are written as empty
+// arrays rather than null when the user has nothing of a kind.
+func buildArchive(data *entity.PersonalData) ([]byte, error) {
+	user := *data.User
+	user.Profile = nil
+	history := loginHistory{
+		Logins:             []*entity.AuditEvent{},
+		PasswordlessLogins: nonNil(data.LoginChallenges),
+	}
+	for _, event := range data.AuditEvents {
+		if event.Action == entity.AuditLogin {
+			history.Logins = append(history.Logins, event)
+		}
+	}
+
+	files := []archiveFile{
+		{"user.json", user},
+		{"profile.json", data.Profile},
+		{"sessions.json", nonNil(data.Sessions)},
+		{"api_tokens.json", nonNil(data.APITokens)},
+		{"identities.json", nonNil(data.Identities)},
+		{"oauth_consents.json", nonNil(data.OAuthConsents)},
+		{"organizations.json", nonNil(data.Memberships)},
+		{"roles.json", nonNil(data.Roles)},
+		{"login_history.json", history},
+		{"email_changes.json", nonNil(data.EmailChanges)},
+		{"user_name_changes.json", nonNil(data.UserNameChanges)},
+		{"audit_events.json", nonNil(data.AuditEvents)},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func nonNil[T any](rows []T) []T {
+	if rows == nil {
+		return []T{}
+	}
+	return rows
+}
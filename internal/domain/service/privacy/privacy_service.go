@@ -0,0 +1,418 @@
+package privacy
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	retentionservice "example.com/internal/domain/service/retention"
+	"example.com/pkg/mail"
+	"example.com/pkg/security"
+	"example.com/pkg/storage"
+)
+
+// Query parameters carrying the tokens of the emailed links
+const (
+	DownloadTokenParam = "token"
+	DeletionTokenParam = "deletion_token"
+)
+
+// batchSize bounds the exports built and accounts erased per query
+const batchSize = 20
+
+var (
+	// ErrExportNotFound is also returned for exports that are not ready yet or expired
+	ErrExportNotFound   = errors.New("data export not found or expired")
+	ErrDeletionNotFound = errors.New("no account deletion was requested")
+	// ErrInvalidDeletionToken is returned for tokens of another request and expired requests
+	ErrInvalidDeletionToken = errors.New("the confirmation link is invalid or expired, request the deletion again")
+	// ErrOrganizationOwner keeps organizations from losing their owner
+	ErrOrganizationOwner = errors.New("transfer the ownership of your organizations before deleting your account")
+)
+
+// Config tunes data exports and account deletions
+type Config struct {
+	// DownloadURL is where data export links point to, with the token in the token query parameter
+	DownloadURL string
+	ExportTTL   time.Duration
+	// ConfirmURL is the frontend page deletion links point to, with the token in the
+	// deletion_token query parameter
+	ConfirmURL string
+	ConfirmTTL time.Duration
+	// CoolingOff is how long confirmed deletions can be cancelled before the account is erased
+	CoolingOff time.Duration
+}
+
+type Service interface {
+	// Record stores an audit event of a user
+	Record(ctx context.Context, event *entity.AuditEvent) error
+	// RequestExport queues an export of the personal data of userID, or returns the export
+	// already waiting to be built
+	RequestExport(ctx context.Context, userID string) (*entity.DataExport, error)
+	// LatestExport returns the export userID requested last
+	LatestExport(ctx context.Context, userID string) (*entity.DataExport, error)
+	// Download opens the ZIP archive of the export of the emailed token
+	Download(ctx context.Context, token string) (*entity.DataExport, *storage.Blob, error)
+	// ProcessExports builds the pending exports and emails their link, then deletes the expired
+	// exports. It returns the number of exports built.
+	ProcessExports(ctx context.Context) (int, error)
+	// RequestDeletion emails userID a link to confirm the deletion of their account, replacing
+	// the unconfirmed request of the user. Confirmed requests are returned unchanged.
+	RequestDeletion(ctx context.Context, userID string) (*entity.AccountDeletion, error)
+	// ConfirmDeletion schedules the erasure of the account of userID at the end of the
+	// cooling-off period
+	ConfirmDeletion(ctx context.Context, userID, token string) (*entity.AccountDeletion, error)
+	// Deletion returns the account deletion requested by userID
+	Deletion(ctx context.Context, userID string) (*entity.AccountDeletion, error)
+	// CancelDeletion withdraws the request of userID, confirmed or not
+	CancelDeletion(ctx context.Context, userID string) error
+	// EraseDueAccounts irreversibly erases the accounts whose cooling-off period is over and
+	// returns the number erased
+	EraseDueAccounts(ctx context.Context) (int, error)
+}
+
+type service struct {
+	userRepo         repository.UserRepository
+	organizationRepo repository.OrganizationRepository
+	exportRepo       repository.DataExportRepository
+	deletionRepo     repository.AccountDeletionRepository
+	personalDataRepo repository.PersonalDataRepository
+	auditRepo        repository.AuditEventRepository
+	retentionService retentionservice.Service
+	store            storage.BlobStore
+	sender           mail.Sender
+	now              func() time.Time
+	config           Config
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	exportRepo repository.DataExportRepository,
+	deletionRepo repository.AccountDeletionRepository,
+	personalDataRepo repository.PersonalDataRepository,
+	auditRepo repository.AuditEventRepository,
+	retentionService retentionservice.Service,
+	store storage.BlobStore,
+	sender mail.Sender,
+	config Config,
+) Service {
+	return &service{
+		userRepo:         userRepo,
+		organizationRepo: organizationRepo,
+		exportRepo:       exportRepo,
+		deletionRepo:     deletionRepo,
+		personalDataRepo: personalDataRepo,
+		auditRepo:        auditRepo,
+		retentionService: retentionService,
+		store:            store,
+		sender:           sender,
+		now:              time.Now,
+		config:           config,
+	}
+}
+
+func (s *service) Record(ctx context.Context, event *entity.AuditEvent) error {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	return s.auditRepo.Create(ctx, event)
+}
+
+func (s *service) RequestExport(ctx context.Context, userID string) (*entity.DataExport, error) {
+	latest, err := s.exportRepo.FindLatest(ctx, userID)
+	if err == nil && latest.Status == entity.DataExportPending {
+		return latest, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	export := &entity.DataExport{ID: uuid.NewString(), UserID: userID, Status: entity.DataExportPending}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	if err := s.Record(ctx, &entity.AuditEvent{UserID: userID, Action: entity.AuditDataExportRequested}); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (s *service) LatestExport(ctx context.Context, userID string) (*entity.DataExport, error) {
+	export, err := s.exportRepo.FindLatest(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrExportNotFound
+	}
+	return export, err
+}
+
+func (s *service) Download(ctx context.Context, token string) (*entity.DataExport, *storage.Blob, error) {
+	export, err := s.exportRepo.FindByTokenHash(ctx, security.HashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !export.Available(s.now()) {
+		return nil, nil, ErrExportNotFound
+	}
+
+	archive, err := s.store.Get(ctx, export.ArchiveKey())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return export, archive, nil
+}
+
+func (s *service) ProcessExports(ctx context.Context) (int, error) {
+	built := 0
+	for {
+		exports, err := s.exportRepo.ListPending(ctx, batchSize)
+		if err != nil {
+			return built, err
+		}
+		for _, export := range exports {
+			if err := s.build(ctx, export); err != nil {
+				return built, err
+			}
+			built++
+		}
+		if len(exports) < batchSize {
+			break
+		}
+	}
+
+	for {
+		expired, err := s.exportRepo.ListExpired(ctx, s.now(), batchSize)
+		if err != nil {
+			return built, err
+		}
+		for _, export := range expired {
+			if err := s.store.Delete(ctx, export.ArchiveKey()); err != nil {
+				return built, err
+			}
+			if err := s.exportRepo.Delete(ctx, export.ID); err != nil {
+				return built, err
+			}
+		}
+		if len(expired) < batchSize {
+			return built, nil
+		}
+	}
+}
+
+// build stores the archive of export and emails its link to the user. The link is only sent
+// before the export is marked ready, so that an export whose email failed is built again.
+func (s *service) build(ctx context.Context, export *entity.DataExport) error {
+	data, err := s.personalDataRepo.Collect(ctx, export.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The user was deleted meanwhile
+		return s.exportRepo.Delete(ctx, export.ID)
+	}
+	if err != nil {
+		return err
+	}
+	archive, err := buildArchive(data)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(ctx, export.ArchiveKey(), archive); err != nil {
+		return err
+	}
+
+	token, err := security.GenerateToken(entity.DataExportPrefix)
+	if err != nil {
+		return err
+	}
+	msg, err := s.exportMessage(data.User.Email, token)
+	if err != nil {
+		return err
+	}
+	if err := s.sender.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.config.ExportTTL)
+	export.Status = entity.DataExportReady
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	export.TokenHash = security.HashToken(token)
+	export.Size = int64(len(archive))
+	return s.exportRepo.Update(ctx, export)
+}
+
+func (s *service) RequestDeletion(ctx context.Context, userID string) (*entity.AccountDeletion, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.deletionRepo.FindByUserID(ctx, userID)
+	if err == nil && existing.Confirmed() {
+		return existing, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Erasing the owner would leave their organizations without one
+	memberships, err := s.organizationRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, membership := range memberships {
+		if membership.Role == entity.MembershipOwner {
+			return nil, ErrOrganizationOwner
+		}
+	}
+
+	token, err := security.GenerateToken(entity.AccountDeletionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := s.confirmMessage(user.Email, token)
+	if err != nil {
+		return nil, err
+	}
+
+	deletion := &entity.AccountDeletion{
+		UserID:    userID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: s.now().Add(s.config.ConfirmTTL),
+	}
+	if err := s.deletionRepo.Save(ctx, deletion); err != nil {
+		return nil, err
+	}
+	if err := s.sender.Send(ctx, msg); err != nil {
+		return nil, err
+	}
+	if err := s.Record(ctx, &entity.AuditEvent{UserID: userID, Action: entity.AuditAccountDeletionRequested}); err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+func (s *service) ConfirmDeletion(ctx context.Context, userID, token string) (*entity.AccountDeletion, error) {
+	deletion, err := s.Deletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if deletion.Confirmed() {
+		return deletion, nil
+	}
+
+	now := s.now()
+	if subtle.ConstantTimeCompare([]byte(deletion.TokenHash), []byte(security.HashToken(token))) != 1 ||
+		!now.Before(deletion.ExpiresAt) {
+		return nil, ErrInvalidDeletionToken
+	}
+
+	scheduledAt := now.Add(s.config.CoolingOff)
+	deletion.ConfirmedAt = &now
+	deletion.ScheduledAt = &scheduledAt
+	if err := s.deletionRepo.Save(ctx, deletion); err != nil {
+		return nil, err
+	}
+	if err := s.Record(ctx, &entity.AuditEvent{UserID: userID, Action: entity.AuditAccountDeletionConfirmed}); err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+func (s *service) Deletion(ctx context.Context, userID string) (*entity.AccountDeletion, error) {
+	deletion, err := s.deletionRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeletionNotFound
+	}
+	return deletion, err
+}
+
+func (s *service) CancelDeletion(ctx context.Context, userID string) error {
+	err := s.deletionRepo.Delete(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDeletionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.Record(ctx, &entity.AuditEvent{UserID: userID, Action: entity.AuditAccountDeletionCancelled})
+}
+
+func (s *service) EraseDueAccounts(ctx context.Context) (int, error) {
+	erased := 0
+	for {
+		due, err := s.deletionRepo.ListDue(ctx, s.now(), batchSize)
+		if err != nil {
+			return erased, err
+		}
+		for _, deletion := range due {
+			err := s.retentionService.Erase(ctx, deletion.UserID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return erased, err
+			}
+			if err == nil {
+				erased++
+			}
+			// Purging the user deletes the request too, unless the user was gone already
+			if err := s.deletionRepo.Delete(ctx, deletion.UserID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return erased, err
+			}
+		}
+		if len(due) < batchSize {
+			return erased, nil
+		}
+	}
+}
+
+func (s *service) exportMessage(to, token string) (mail.Message, error) {
+	link, err := tokenLink(s.config.DownloadURL, DownloadTokenParam, token)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		To:      to,
+		Subject: "Your data export is ready",
+		Body: fmt.Sprintf("The copy of your personal data you asked for is ready. Follow this link to download "+
+			"it as a ZIP archive:\n\n%s\n\nThe link expires in %d hours. Anyone with the link can download "+
+			"the archive, so do not share it.\n", link, int(s.config.ExportTTL.Hours())),
+	}, nil
+}
+
+func (s *service) confirmMessage(to, token string) (mail.Message, error) {
+	link, err := tokenLink(s.config.ConfirmURL, DeletionTokenParam, token)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		To:      to,
+		Subject: "Confirm the deletion of your account",
+		Body: fmt.Sprintf("You asked us to delete your account. Follow this link to confirm:\n\n%s\n\n"+
+			"The link expires in %d hours. Once confirmed, your account is erased after %d days, until "+
+			"when you can sign in and cancel the deletion.\n\nIf you did not ask for this, sign in and "+
+			"change your password.\n", link, int(s.config.ConfirmTTL.Hours()), int(s.config.CoolingOff.Hours()/24)),
+	}, nil
+}
+
+// tokenLink returns base with token in the query parameter param
+func tokenLink(base, param, token string) (string, error) {
+	link, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	query := link.Query()
+	query.Set(param, token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
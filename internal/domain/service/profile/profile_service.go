@@ -0,0 +1,139 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidDisplayName = fmt.Errorf("display names are at most %d characters without control characters",
+		entity.ProfileDisplayNameMaxLength)
+	ErrInvalidBio      = fmt.Errorf("bios are at most %d characters", entity.ProfileBioMaxLength)
+	ErrInvalidLocale   = errors.New("locales are BCP 47 language tags like en-US")
+	ErrInvalidTimezone = errors.New("time zones are IANA time zone names like Europe/Berlin")
+)
+
+// localeMaxLength bounds language tags to the length stored
+const localeMaxLength = 35
+
+// Patch changes the profile fields that are not nil. Setting a field to "" clears it.
+type Patch struct {
+	DisplayName *string
+	Bio         *string
+	Locale      *string
+	Timezone    *string
+}
+
+type Service interface {
+	// Get returns the profile of userID, with empty fields when the user never saved one
+	Get(ctx context.Context, userID string) (*entity.UserProfile, error)
+	// Update applies patch to the profile of userID and returns the profile saved. Nothing is
+	// saved when a field of the patch is invalid.
+	Update(ctx context.Context, userID string, patch Patch) (*entity.UserProfile, error)
+}
+
+type service struct {
+	userRepo    repository.UserRepository
+	profileRepo repository.UserProfileRepository
+}
+
+func NewService(userRepo repository.UserRepository, profileRepo repository.UserProfileRepository) Service {
+	return &service{
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+	}
+}
+
+func (s *service) Get(ctx context.Context, userID string) (*entity.UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Profile == nil {
+		return &entity.UserProfile{UserID: user.ID}, nil
+	}
+	return user.Profile, nil
+}
+
+func (s *service) Update(ctx context.Context, userID string, patch Patch) (*entity.UserProfile, error) {
+	profile, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.DisplayName != nil {
+		displayName := strings.TrimSpace(*patch.DisplayName)
+		if utf8.RuneCountInString(displayName) > entity.ProfileDisplayNameMaxLength || strings.ContainsFunc(displayName, unicode.IsControl) {
+			return nil, ErrInvalidDisplayName
+		}
+		profile.DisplayName = displayName
+	}
+	if patch.Bio != nil {
+		bio := strings.TrimSpace(*patch.Bio)
+		if utf8.RuneCountInString(bio) > entity.ProfileBioMaxLength {
+			return nil, ErrInvalidBio
+		}
+		profile.Bio = bio
+	}
+	if patch.Locale != nil {
+		locale, err := canonicalLocale(*patch.Locale)
+		if err != nil {
+			return nil, err
+		}
+		profile.Locale = locale
+	}
+	if patch.Timezone != nil {
+		if err := validTimezone(*patch.Timezone); err != nil {
+			return nil, err
+		}
+		profile.Timezone = *patch.Timezone
+	}
+
+	if err := s.profileRepo.Save(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// canonicalLocale returns the canonical form of a language tag, such as en-US for en_us
+func canonicalLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil || len(tag.String()) > localeMaxLength {
+		return "", ErrInvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// validTimezone accepts the names of the IANA time zone database, but not "Local", which
+// time.LoadLocation reads as the zone of the server
+func validTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if timezone == "Local" {
+		return ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+	return nil
+}
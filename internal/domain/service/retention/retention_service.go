@@ -0,0 +1,120 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/storage"
+)
+
+// purgeBatchSize bounds the deleted users loaded at once
+const purgeBatchSize = 100
+
+type Config struct {
+	// DeletedRetention is how long deleted users can be restored before their personal data
+	// is purged
+	DeletedRetention time.Duration
+}
+
+type Service interface {
+	// PurgeDeletedUsers erases the personal data and avatar of every user deleted longer than
+	// the retention period ago, and returns how many users it purged. Users purged already
+	// are skipped, so that concurrent runs do no harm.
+	PurgeDeletedUsers(ctx context.Context) (int, error)
+	// Erase deletes userID and purges their personal data at once, without retention period
+	Erase(ctx context.Context, userID string) error
+}
+
+type service struct {
+	userRepo   repository.UserRepository
+	exportRepo repository.DataExportRepository
+	store      storage.BlobStore
+	cfg        Config
+	now        func() time.Time
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	exportRepo repository.DataExportRepository,
+	store storage.BlobStore,
+	cfg Config,
+) Service {
+	return &service{
+		userRepo:   userRepo,
+		exportRepo: exportRepo,
+		store:      store,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+func (s *service) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	now := s.now()
+	deletedBefore := now.Add(-s.cfg.DeletedRetention)
+
+	purged := 0
+	for {
+		users, err := s.userRepo.ListPurgeable(ctx, deletedBefore, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		for _, user := range users {
+			err := s.purge(ctx, user, now)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Restored or purged by another run meanwhile
+				continue
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(users) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+func (s *service) Erase(ctx context.Context, userID string) error {
+	if err := s.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+	user, err := s.userRepo.FindDeleted(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Purged() {
+		return nil
+	}
+	return s.purge(ctx, user, s.now())
+}
+
+func (s *service) purge(ctx context.Context, user *entity.User, at time.Time) error {
+	// The avatar and data export archives go first, as nothing points to them once the
+	// profile and exports are deleted
+	var keys []string
+	if profile := user.Profile; profile != nil && profile.Avatar != "" {
+		for _, size := range entity.AvatarSizes {
+			keys = append(keys, profile.AvatarKey(size))
+		}
+	}
+	exports, err := s.exportRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		keys = append(keys, export.ArchiveKey())
+	}
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return s.userRepo.Purge(ctx, user.ID, at)
+}
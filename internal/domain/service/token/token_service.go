@@ -0,0 +1,155 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/security"
+	"example.com/pkg/tenant"
+)
+
+var (
+	ErrTokensDisabled      = errors.New("token authentication is not enabled")
+	ErrInvalidAccessToken  = errors.New("invalid access token")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+)
+
+type Service interface {
+	// Enabled reports whether JWT signing keys are configured
+	Enabled() bool
+	// IssuePair starts a new refresh token family for userID
+	IssuePair(ctx context.Context, userID string) (*entity.TokenPair, error)
+	// Refresh exchanges a refresh token for a new pair. Presenting a token that was already
+	// exchanged revokes its whole family, logging out both the attacker and the user.
+	Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+	// Authenticate verifies an access token and returns its claims
+	Authenticate(ctx context.Context, accessToken string) (*security.Claims, error)
+	JWKS() security.JWKS
+}
+
+type service struct {
+	tokenRepo  repository.RefreshTokenRepository
+	issuer     *security.JWTIssuer
+	now        func() time.Time
+	refreshTTL time.Duration
+}
+
+// NewService creates the token service; a nil issuer disables token authentication
+func NewService(tokenRepo repository.RefreshTokenRepository, issuer *security.JWTIssuer, refreshTTL time.Duration) Service {
+	return &service{
+		tokenRepo:  tokenRepo,
+		issuer:     issuer,
+		now:        time.Now,
+		refreshTTL: refreshTTL,
+	}
+}
+
+func (s *service) Enabled() bool {
+	return s.issuer != nil
+}
+
+func (s *service) IssuePair(ctx context.Context, userID string) (*entity.TokenPair, error) {
+	if s.issuer == nil {
+		return nil, ErrTokensDisabled
+	}
+	return s.issuePair(ctx, userID, uuid.NewString())
+}
+
+func (s *service) Refresh(ctx context.Context, plain string) (*entity.TokenPair, error) {
+	if s.issuer == nil {
+		return nil, ErrTokensDisabled
+	}
+	if !security.HasTokenPrefix(plain, entity.RefreshTokenPrefix) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, security.HashToken(plain))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	now := s.now()
+	if token.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if token.UsedAt != nil {
+		return nil, s.revokeFamily(ctx, token.FamilyID, now)
+	}
+	if !now.Before(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if err := s.tokenRepo.MarkUsed(ctx, token.ID, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Exchanged concurrently by someone else
+			return nil, s.revokeFamily(ctx, token.FamilyID, now)
+		}
+		return nil, err
+	}
+
+	return s.issuePair(ctx, token.UserID, token.FamilyID)
+}
+
+func (s *service) Authenticate(_ context.Context, accessToken string) (*security.Claims, error) {
+	if s.issuer == nil {
+		return nil, ErrTokensDisabled
+	}
+
+	claims, err := s.issuer.Verify(accessToken)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	return claims, nil
+}
+
+func (s *service) JWKS() security.JWKS {
+	if s.issuer == nil {
+		return security.JWKS{Keys: []security.JWK{}}
+	}
+	return s.issuer.JWKS()
+}
+
+func (s *service) issuePair(ctx context.Context, userID, familyID string) (*entity.TokenPair, error) {
+	// Access tokens only authenticate requests for the tenant they were issued in
+	tenantID, _ := tenant.FromContext(ctx)
+	accessToken, claims, err := s.issuer.Issue(userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := security.GenerateToken(entity.RefreshTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken := &entity.RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: security.HashToken(plain),
+		ExpiresAt: s.now().Add(s.refreshTTL),
+	}
+	if err := s.tokenRepo.Create(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenPair{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		RefreshToken:         plain,
+	}, nil
+}
+
+// revokeFamily answers the reuse of a refresh token
+func (s *service) revokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	if err := s.tokenRepo.RevokeFamily(ctx, familyID, now); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
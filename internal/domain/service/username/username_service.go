@@ -0,0 +1,291 @@
+package username
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/security"
+)
+
+// generateAttempts bounds the search for a free user name for a new user
+const generateAttempts = 5
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUsernameTaken is also returned for names that look like the name of another user, and
+	// for names another user left while their redirect lasts
+	ErrUsernameTaken       = errors.New("username belongs to another user")
+	ErrSameUsername        = errors.New("the new username is the current username")
+	ErrRenameTooSoon       = errors.New("the username was changed too recently")
+	ErrUsernameUnavailable = errors.New("no free username could be derived")
+)
+
+// Config tunes user names
+type Config struct {
+	// Policy refuses the names it rejects unless it is nil
+	Policy *security.UsernamePolicy
+	// RedirectTTL is how long an old name leads to the user who left it, during which nobody
+	// else may take it
+	RedirectTTL time.Duration
+	// RenameInterval is how long users wait between renames, so that they cannot hold on to
+	// many names at once through their redirects; 0 lets them rename at any time
+	RenameInterval time.Duration
+}
+
+type Service interface {
+	// Check returns the normalized form of username, or an error matching
+	// security.ErrInvalidUsername when it breaks the policy and ErrUsernameTaken when it is not
+	// free for userID, which is empty for users yet to be created
+	Check(ctx context.Context, userID, username string) (string, error)
+	// Generate derives a free user name for a new user from the first hint that yields one,
+	// such as a preferred username or the local part of an email, adding a random suffix while
+	// the name is taken or refused
+	Generate(ctx context.Context, hints ...string) (string, error)
+	// Rename gives userID the name username. The old name leads to the user for the redirect
+	// TTL, which lets the user take it back meanwhile.
+	Rename(ctx context.Context, userID, username string) (*entity.User, error)
+	// History returns userID and their renames, newest first
+	History(ctx context.Context, userID string) (*entity.User, []*entity.UserNameChange, error)
+	// Resolve returns the user named username or, while the redirect lasts, the user who left
+	// the name; moved reports the latter
+	Resolve(ctx context.Context, username string) (user *entity.User, moved bool, err error)
+}
+
+type service struct {
+	userRepo   repository.UserRepository
+	changeRepo repository.UserNameChangeRepository
+	auditRepo  repository.AuditEventRepository
+	config     Config
+	now        func() time.Time
+}
+
+func NewService(
+	userRepo repository.UserRepository,
+	changeRepo repository.UserNameChangeRepository,
+	auditRepo repository.AuditEventRepository,
+	config Config,
+) Service {
+	return &service{
+		userRepo:   userRepo,
+		changeRepo: changeRepo,
+		auditRepo:  auditRepo,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+func (s *service) Check(ctx context.Context, userID, username string) (string, error) {
+	name := security.NormalizeUsername(username)
+	if s.config.Policy != nil {
+		if err := s.config.Policy.Check(name); err != nil {
+			return "", err
+		}
+	}
+
+	skeleton := security.UsernameSkeleton(name)
+	other, err := s.userRepo.FindByUserNameSkeleton(ctx, skeleton)
+	if err == nil && other.ID != userID {
+		return "", ErrUsernameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+
+	change, err := s.changeRepo.FindRedirect(ctx, skeleton, s.now())
+	if err == nil && change.UserID != userID {
+		return "", ErrUsernameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	return name, nil
+}
+
+func (s *service) Generate(ctx context.Context, hints ...string) (string, error) {
+	var base string
+	for _, hint := range hints {
+		if base = sanitize(hint); base != "" {
+			break
+		}
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := fit(base, entity.UserNameMaxLength)
+	for range generateAttempts {
+		name, err := s.Check(ctx, "", candidate)
+		if err == nil {
+			return name, nil
+		}
+		// Suffixes also fix names that are reserved, too short or without a letter
+		if !errors.Is(err, ErrUsernameTaken) && !errors.Is(err, security.ErrInvalidUsername) {
+			return "", err
+		}
+
+		suffix, err := randomHex(2)
+		if err != nil {
+			return "", err
+		}
+		candidate = fit(base, entity.UserNameMaxLength-len(suffix)-1) + "_" + suffix
+	}
+	return "", ErrUsernameUnavailable
+}
+
+func (s *service) Rename(ctx context.Context, userID, username string) (*entity.User, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	name, err := s.Check(ctx, user.ID, username)
+	if err != nil {
+		return nil, err
+	}
+	if name == user.UserName {
+		return nil, ErrSameUsername
+	}
+
+	now := s.now()
+	if s.config.RenameInterval > 0 {
+		changes, err := s.changeRepo.ListByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 && now.Before(changes[0].CreatedAt.Add(s.config.RenameInterval)) {
+			return nil, ErrRenameTooSoon
+		}
+	}
+
+	err = s.userRepo.ChangeUserName(ctx, user.ID, user.UserName, name)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrUsernameTaken
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Another rename of the user came first
+		return nil, ErrRenameTooSoon
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	change := &entity.UserNameChange{
+		ID:                uuid.NewString(),
+		UserID:            user.ID,
+		OldUserName:       user.UserName,
+		OldSkeleton:       security.UsernameSkeleton(user.UserName),
+		NewUserName:       name,
+		RedirectExpiresAt: now.Add(s.config.RedirectTTL),
+	}
+	if err := s.changeRepo.Create(ctx, change); err != nil {
+		return nil, err
+	}
+	err = s.auditRepo.Create(ctx, &entity.AuditEvent{
+		ID:     uuid.NewString(),
+		UserID: user.ID,
+		Action: entity.AuditUserNameChanged,
+		Detail: change.OldUserName + " -> " + change.NewUserName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.UserName = name
+	return user, nil
+}
+
+func (s *service) History(ctx context.Context, userID string) (*entity.User, []*entity.UserNameChange, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := s.changeRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, changes, nil
+}
+
+func (s *service) Resolve(ctx context.Context, username string) (*entity.User, bool, error) {
+	name := security.NormalizeUsername(username)
+	user, err := s.userRepo.FindByUserName(ctx, name)
+	if err == nil {
+		return user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	change, err := s.changeRepo.FindRedirect(ctx, security.UsernameSkeleton(name), s.now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	// Lookalikes of the old name are reserved, but only the name itself redirects
+	if change.OldUserName != name {
+		return nil, false, ErrUserNotFound
+	}
+
+	user, err = s.findUser(ctx, change.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+func (s *service) findUser(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// sanitize keeps the letters, digits, dots, dashes and underscores of the normalized hint,
+// without leading, trailing or consecutive dots, dashes and underscores
+func sanitize(hint string) string {
+	var b strings.Builder
+	var separator rune
+	for _, r := range security.NormalizeUsername(hint) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			if separator != 0 && b.Len() > 0 {
+				b.WriteRune(separator)
+			}
+			separator = 0
+			b.WriteRune(r)
+		case separator == 0 && (r == '.' || r == '-' || r == '_'):
+			separator = r
+		}
+	}
+	return b.String()
+}
+
+// fit truncates name to n characters, dropping the separators truncation leaves at the end
+func fit(name string, n int) string {
+	if len(name) > n {
+		name = name[:n]
+	}
+	return strings.TrimRight(name, "._-")
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
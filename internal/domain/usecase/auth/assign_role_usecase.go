@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+
+	policyservice "example.com/internal/domain/service/policy"
+)
+
+type AssignRoleUseCase interface {
+	Call(ctx context.Context, userID, roleName string) error
+}
+
+type assignRoleUseCase struct {
+	policyService policyservice.Service
+}
+
+func NewAssignRoleUseCase(policyService policyservice.Service) AssignRoleUseCase {
+	return &assignRoleUseCase{
+		policyService: policyService,
+	}
+}
+
+func (uc *assignRoleUseCase) Call(ctx context.Context, userID, roleName string) error {
+	return uc.policyService.Assign(ctx, userID, roleName)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	tokenservice "example.com/internal/domain/service/token"
+	"example.com/pkg/security"
+)
+
+type AuthenticateAccessTokenUseCase interface {
+	Call(ctx context.Context, accessToken string) (*security.Claims, error)
+}
+
+type authenticateAccessTokenUseCase struct {
+	tokenService tokenservice.Service
+}
+
+func NewAuthenticateAccessTokenUseCase(tokenService tokenservice.Service) AuthenticateAccessTokenUseCase {
+	return &authenticateAccessTokenUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *authenticateAccessTokenUseCase) Call(ctx context.Context, accessToken string) (*security.Claims, error) {
+	return uc.tokenService.Authenticate(ctx, accessToken)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+)
+
+type AuthenticateAPITokenUseCase interface {
+	Call(ctx context.Context, token string) (*entity.APIToken, error)
+}
+
+type authenticateAPITokenUseCase struct {
+	tokenService apitokenservice.Service
+}
+
+func NewAuthenticateAPITokenUseCase(tokenService apitokenservice.Service) AuthenticateAPITokenUseCase {
+	return &authenticateAPITokenUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *authenticateAPITokenUseCase) Call(ctx context.Context, token string) (*entity.APIToken, error) {
+	return uc.tokenService.Authenticate(ctx, token)
+}
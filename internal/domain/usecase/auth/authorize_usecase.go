@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type AuthorizeUseCase interface {
+	// Call validates the request and issues a code for userID. It fails with ErrLoginRequired
+	// without a user and with ErrConsentRequired until the user granted the requested scopes.
+	Call(ctx context.Context, userID string, req *oauthservice.AuthorizationRequest) (*entity.OAuthClient, string, error)
+}
+
+type authorizeUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewAuthorizeUseCase(oauthService oauthservice.Service) AuthorizeUseCase {
+	return &authorizeUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *authorizeUseCase) Call(
+	ctx context.Context, userID string, req *oauthservice.AuthorizationRequest,
+) (*entity.OAuthClient, string, error) {
+	client, err := uc.oauthService.Authorize(ctx, req)
+	if err != nil {
+		return client, "", err
+	}
+	if userID == "" {
+		return client, "", oauthservice.ErrLoginRequired
+	}
+
+	consent, err := uc.oauthService.NeedsConsent(ctx, userID, client, req.Scopes)
+	if err != nil {
+		return client, "", err
+	}
+	if consent {
+		return client, "", oauthservice.ErrConsentRequired
+	}
+
+	code, err := uc.oauthService.IssueCode(ctx, userID, req)
+	return client, code, err
+}
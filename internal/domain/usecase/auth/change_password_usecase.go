@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	passwordservice "example.com/internal/domain/service/password"
+)
+
+type ChangePasswordUseCase interface {
+	// Call replaces the password of userID, ending the other sessions of the user
+	Call(ctx context.Context, userID, currentPassword, newPassword string) (*entity.User, error)
+}
+
+type changePasswordUseCase struct {
+	passwordService passwordservice.Service
+}
+
+func NewChangePasswordUseCase(passwordService passwordservice.Service) ChangePasswordUseCase {
+	return &changePasswordUseCase{
+		passwordService: passwordService,
+	}
+}
+
+func (uc *changePasswordUseCase) Call(ctx context.Context, userID, currentPassword, newPassword string) (*entity.User, error) {
+	return uc.passwordService.Change(ctx, userID, currentPassword, newPassword)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	policyservice "example.com/internal/domain/service/policy"
+)
+
+type CheckPermissionUseCase interface {
+	// Call reports whether userID holds permission through one of their roles
+	Call(ctx context.Context, userID, permission string) (bool, error)
+}
+
+type checkPermissionUseCase struct {
+	policyService policyservice.Service
+}
+
+func NewCheckPermissionUseCase(policyService policyservice.Service) CheckPermissionUseCase {
+	return &checkPermissionUseCase{
+		policyService: policyService,
+	}
+}
+
+func (uc *checkPermissionUseCase) Call(ctx context.Context, userID, permission string) (bool, error) {
+	return uc.policyService.Can(ctx, userID, permission)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	emailservice "example.com/internal/domain/service/email"
+)
+
+type ConfirmEmailChangeUseCase interface {
+	// Call gives the user of the change the email the token was sent to
+	Call(ctx context.Context, token string) (*entity.User, error)
+}
+
+type confirmEmailChangeUseCase struct {
+	emailService emailservice.Service
+}
+
+func NewConfirmEmailChangeUseCase(emailService emailservice.Service) ConfirmEmailChangeUseCase {
+	return &confirmEmailChangeUseCase{
+		emailService: emailService,
+	}
+}
+
+func (uc *confirmEmailChangeUseCase) Call(ctx context.Context, token string) (*entity.User, error) {
+	return uc.emailService.ConfirmChange(ctx, token)
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type ConsentUseCase interface {
+	// Describe validates a pending request again and returns the client asking for consent
+	Describe(ctx context.Context, req *oauthservice.AuthorizationRequest) (*entity.OAuthClient, error)
+	// Call records the decision of userID and issues a code when the request was approved
+	Call(ctx context.Context, userID string, req *oauthservice.AuthorizationRequest, approve bool) (string, error)
+}
+
+type consentUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewConsentUseCase(oauthService oauthservice.Service) ConsentUseCase {
+	return &consentUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *consentUseCase) Describe(ctx context.Context, req *oauthservice.AuthorizationRequest) (*entity.OAuthClient, error) {
+	return uc.oauthService.Authorize(ctx, req)
+}
+
+func (uc *consentUseCase) Call(ctx context.Context, userID string, req *oauthservice.AuthorizationRequest, approve bool) (string, error) {
+	// The client may have changed since the request was made
+	if _, err := uc.oauthService.Authorize(ctx, req); err != nil {
+		return "", err
+	}
+	if !approve {
+		return "", oauthservice.ErrAccessDenied
+	}
+
+	if err := uc.oauthService.GrantConsent(ctx, userID, req.ClientID, req.Scopes); err != nil {
+		return "", err
+	}
+	return uc.oauthService.IssueCode(ctx, userID, req)
+}
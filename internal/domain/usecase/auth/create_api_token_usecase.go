@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+)
+
+type CreateAPITokenUseCase interface {
+	// Call returns the stored token and the plain token, which can only be shown once
+	Call(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (*entity.APIToken, string, error)
+}
+
+type createAPITokenUseCase struct {
+	tokenService apitokenservice.Service
+}
+
+func NewCreateAPITokenUseCase(tokenService apitokenservice.Service) CreateAPITokenUseCase {
+	return &createAPITokenUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *createAPITokenUseCase) Call(
+	ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time,
+) (*entity.APIToken, string, error) {
+	return uc.tokenService.Issue(ctx, userID, name, scopes, expiresAt)
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type DeleteOAuthClientUseCase interface {
+	Call(ctx context.Context, ownerID, clientID string) error
+}
+
+type deleteOAuthClientUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewDeleteOAuthClientUseCase(oauthService oauthservice.Service) DeleteOAuthClientUseCase {
+	return &deleteOAuthClientUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *deleteOAuthClientUseCase) Call(ctx context.Context, ownerID, clientID string) error {
+	return uc.oauthService.DeleteClient(ctx, ownerID, clientID)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	tokenservice "example.com/internal/domain/service/token"
+	"example.com/pkg/security"
+)
+
+type GetJWKSUseCase interface {
+	Call(ctx context.Context) security.JWKS
+}
+
+type getJWKSUseCase struct {
+	tokenService tokenservice.Service
+}
+
+func NewGetJWKSUseCase(tokenService tokenservice.Service) GetJWKSUseCase {
+	return &getJWKSUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *getJWKSUseCase) Call(_ context.Context) security.JWKS {
+	return uc.tokenService.JWKS()
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type IntrospectOAuthTokenUseCase interface {
+	Call(ctx context.Context, clientID, clientSecret, token string) (*oauthservice.Introspection, error)
+}
+
+type introspectOAuthTokenUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewIntrospectOAuthTokenUseCase(oauthService oauthservice.Service) IntrospectOAuthTokenUseCase {
+	return &introspectOAuthTokenUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *introspectOAuthTokenUseCase) Call(
+	ctx context.Context, clientID, clientSecret, token string,
+) (*oauthservice.Introspection, error) {
+	client, err := uc.oauthService.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	return uc.oauthService.Introspect(ctx, client, token)
+}
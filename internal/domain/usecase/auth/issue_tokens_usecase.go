@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	tokenservice "example.com/internal/domain/service/token"
+)
+
+type IssueTokensUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.TokenPair, error)
+}
+
+type issueTokensUseCase struct {
+	tokenService tokenservice.Service
+}
+
+func NewIssueTokensUseCase(tokenService tokenservice.Service) IssueTokensUseCase {
+	return &issueTokensUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *issueTokensUseCase) Call(ctx context.Context, userID string) (*entity.TokenPair, error) {
+	return uc.tokenService.IssuePair(ctx, userID)
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	identityservice "example.com/internal/domain/service/identity"
+	"example.com/pkg/oidc"
+)
+
+type LinkIdentityUseCase interface {
+	// Call redeems the authorization code and links the identity it proves to userID
+	Call(ctx context.Context, userID, provider, code string, req oidc.AuthRequest) (*entity.UserIdentity, error)
+}
+
+type linkIdentityUseCase struct {
+	registry        *oidc.Registry
+	identityService identityservice.Service
+}
+
+func NewLinkIdentityUseCase(registry *oidc.Registry, identityService identityservice.Service) LinkIdentityUseCase {
+	return &linkIdentityUseCase{
+		registry:        registry,
+		identityService: identityService,
+	}
+}
+
+func (uc *linkIdentityUseCase) Call(
+	ctx context.Context, userID, provider, code string, req oidc.AuthRequest,
+) (*entity.UserIdentity, error) {
+	p, ok := uc.registry.Get(provider)
+	if !ok {
+		return nil, oidc.ErrUnknownProvider
+	}
+
+	claims, err := p.Exchange(ctx, code, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.identityService.Link(ctx, userID, provider, claims)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+)
+
+type ListAPITokensUseCase interface {
+	Call(ctx context.Context, userID string) ([]*entity.APIToken, error)
+}
+
+type listAPITokensUseCase struct {
+	tokenService apitokenservice.Service
+}
+
+func NewListAPITokensUseCase(tokenService apitokenservice.Service) ListAPITokensUseCase {
+	return &listAPITokensUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *listAPITokensUseCase) Call(ctx context.Context, userID string) ([]*entity.APIToken, error) {
+	return uc.tokenService.List(ctx, userID)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	identityservice "example.com/internal/domain/service/identity"
+)
+
+type ListIdentitiesUseCase interface {
+	Call(ctx context.Context, userID string) ([]*entity.UserIdentity, error)
+}
+
+type listIdentitiesUseCase struct {
+	identityService identityservice.Service
+}
+
+func NewListIdentitiesUseCase(identityService identityservice.Service) ListIdentitiesUseCase {
+	return &listIdentitiesUseCase{
+		identityService: identityService,
+	}
+}
+
+func (uc *listIdentitiesUseCase) Call(ctx context.Context, userID string) ([]*entity.UserIdentity, error) {
+	return uc.identityService.List(ctx, userID)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type ListOAuthClientsUseCase interface {
+	Call(ctx context.Context, ownerID string) ([]*entity.OAuthClient, error)
+}
+
+type listOAuthClientsUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewListOAuthClientsUseCase(oauthService oauthservice.Service) ListOAuthClientsUseCase {
+	return &listOAuthClientsUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *listOAuthClientsUseCase) Call(ctx context.Context, ownerID string) ([]*entity.OAuthClient, error) {
+	return uc.oauthService.ListClients(ctx, ownerID)
+}
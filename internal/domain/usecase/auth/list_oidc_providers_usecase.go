@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"example.com/pkg/oidc"
+)
+
+type ListOIDCProvidersUseCase interface {
+	Call() []oidc.ProviderInfo
+}
+
+type listOIDCProvidersUseCase struct {
+	registry *oidc.Registry
+}
+
+func NewListOIDCProvidersUseCase(registry *oidc.Registry) ListOIDCProvidersUseCase {
+	return &listOIDCProvidersUseCase{
+		registry: registry,
+	}
+}
+
+func (uc *listOIDCProvidersUseCase) Call() []oidc.ProviderInfo {
+	return uc.registry.Providers()
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	policyservice "example.com/internal/domain/service/policy"
+)
+
+type ListRolesUseCase interface {
+	Call(ctx context.Context) ([]*entity.Role, error)
+}
+
+type listRolesUseCase struct {
+	policyService policyservice.Service
+}
+
+func NewListRolesUseCase(policyService policyservice.Service) ListRolesUseCase {
+	return &listRolesUseCase{
+		policyService: policyService,
+	}
+}
+
+func (uc *listRolesUseCase) Call(ctx context.Context) ([]*entity.Role, error) {
+	return uc.policyService.ListRoles(ctx)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	policyservice "example.com/internal/domain/service/policy"
+)
+
+type ListUserRolesUseCase interface {
+	Call(ctx context.Context, userID string) ([]*entity.Role, error)
+}
+
+type listUserRolesUseCase struct {
+	policyService policyservice.Service
+}
+
+func NewListUserRolesUseCase(policyService policyservice.Service) ListUserRolesUseCase {
+	return &listUserRolesUseCase{
+		policyService: policyService,
+	}
+}
+
+func (uc *listUserRolesUseCase) Call(ctx context.Context, userID string) ([]*entity.Role, error) {
+	return uc.policyService.UserRoles(ctx, userID)
+}
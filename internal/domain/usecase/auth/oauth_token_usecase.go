@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type OAuthTokenUseCase interface {
+	// Call authenticates the client and serves its token request
+	Call(ctx context.Context, clientID, clientSecret string, req oauthservice.TokenRequest) (*entity.OAuthTokenSet, error)
+}
+
+type oauthTokenUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewOAuthTokenUseCase(oauthService oauthservice.Service) OAuthTokenUseCase {
+	return &oauthTokenUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *oauthTokenUseCase) Call(
+	ctx context.Context, clientID, clientSecret string, req oauthservice.TokenRequest,
+) (*entity.OAuthTokenSet, error) {
+	client, err := uc.oauthService.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case entity.GrantTypeAuthorizationCode:
+		return uc.oauthService.ExchangeCode(ctx, client, req.Code, req.RedirectURI, req.CodeVerifier)
+	case entity.GrantTypeClientCredentials:
+		return uc.oauthService.ClientCredentials(ctx, client, req.Scopes)
+	default:
+		return nil, oauthservice.ErrUnsupportedGrantType
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	identityservice "example.com/internal/domain/service/identity"
+	"example.com/pkg/oidc"
+)
+
+type OIDCLoginUseCase interface {
+	// Call redeems the authorization code and returns the user of the identity, creating it on first sign-in
+	Call(ctx context.Context, provider, code string, req oidc.AuthRequest) (*entity.User, error)
+}
+
+type oidcLoginUseCase struct {
+	registry        *oidc.Registry
+	identityService identityservice.Service
+}
+
+func NewOIDCLoginUseCase(registry *oidc.Registry, identityService identityservice.Service) OIDCLoginUseCase {
+	return &oidcLoginUseCase{
+		registry:        registry,
+		identityService: identityService,
+	}
+}
+
+func (uc *oidcLoginUseCase) Call(ctx context.Context, provider, code string, req oidc.AuthRequest) (*entity.User, error) {
+	p, ok := uc.registry.Get(provider)
+	if !ok {
+		return nil, oidc.ErrUnknownProvider
+	}
+
+	claims, err := p.Exchange(ctx, code, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.identityService.SignIn(ctx, provider, claims)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	passwordlessservice "example.com/internal/domain/service/passwordless"
+)
+
+type PasswordlessLoginUseCase interface {
+	// Call completes a passwordless login with the token of the emailed link or the emailed code
+	Call(ctx context.Context, binding passwordlessservice.Binding, secret string) (*entity.User, error)
+}
+
+type passwordlessLoginUseCase struct {
+	passwordlessService passwordlessservice.Service
+}
+
+func NewPasswordlessLoginUseCase(passwordlessService passwordlessservice.Service) PasswordlessLoginUseCase {
+	return &passwordlessLoginUseCase{
+		passwordlessService: passwordlessService,
+	}
+}
+
+func (uc *passwordlessLoginUseCase) Call(ctx context.Context, binding passwordlessservice.Binding, secret string) (*entity.User, error) {
+	return uc.passwordlessService.Verify(ctx, binding, secret)
+}
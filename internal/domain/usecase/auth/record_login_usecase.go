@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type RecordLoginUseCase interface {
+	// Call adds the login of userID with method to the audit events of the user
+	Call(ctx context.Context, userID, method, ipAddress, userAgent string) error
+}
+
+type recordLoginUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewRecordLoginUseCase(privacyService privacyservice.Service) RecordLoginUseCase {
+	return &recordLoginUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *recordLoginUseCase) Call(ctx context.Context, userID, method, ipAddress, userAgent string) error {
+	return uc.privacyService.Record(ctx, &entity.AuditEvent{
+		UserID:    userID,
+		Action:    entity.AuditLogin,
+		Detail:    method,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	})
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	tokenservice "example.com/internal/domain/service/token"
+)
+
+type RefreshTokenUseCase interface {
+	Call(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+}
+
+type refreshTokenUseCase struct {
+	tokenService tokenservice.Service
+}
+
+func NewRefreshTokenUseCase(tokenService tokenservice.Service) RefreshTokenUseCase {
+	return &refreshTokenUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *refreshTokenUseCase) Call(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	return uc.tokenService.Refresh(ctx, refreshToken)
+}
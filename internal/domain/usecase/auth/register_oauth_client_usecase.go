@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type RegisterOAuthClientUseCase interface {
+	Call(
+		ctx context.Context, ownerID, name string, redirectURIs, grantTypes, scopes []string, public bool,
+	) (*entity.OAuthClient, string, error)
+}
+
+type registerOAuthClientUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewRegisterOAuthClientUseCase(oauthService oauthservice.Service) RegisterOAuthClientUseCase {
+	return &registerOAuthClientUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *registerOAuthClientUseCase) Call(
+	ctx context.Context, ownerID, name string, redirectURIs, grantTypes, scopes []string, public bool,
+) (*entity.OAuthClient, string, error) {
+	return uc.oauthService.RegisterClient(ctx, ownerID, name, redirectURIs, grantTypes, scopes, public)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	emailservice "example.com/internal/domain/service/email"
+)
+
+type RequestEmailChangeUseCase interface {
+	// Call emails links to confirm and revert the change of the email of userID to newEmail
+	Call(ctx context.Context, userID, password, newEmail string) (*entity.EmailChange, error)
+}
+
+type requestEmailChangeUseCase struct {
+	emailService emailservice.Service
+}
+
+func NewRequestEmailChangeUseCase(emailService emailservice.Service) RequestEmailChangeUseCase {
+	return &requestEmailChangeUseCase{
+		emailService: emailService,
+	}
+}
+
+func (uc *requestEmailChangeUseCase) Call(ctx context.Context, userID, password, newEmail string) (*entity.EmailChange, error) {
+	return uc.emailService.RequestChange(ctx, userID, password, newEmail)
+}
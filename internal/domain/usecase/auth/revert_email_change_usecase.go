@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	emailservice "example.com/internal/domain/service/email"
+)
+
+type RevertEmailChangeUseCase interface {
+	// Call gives the user of the change back the email the token was sent to, ending their sessions
+	Call(ctx context.Context, token string) (*entity.User, error)
+}
+
+type revertEmailChangeUseCase struct {
+	emailService emailservice.Service
+}
+
+func NewRevertEmailChangeUseCase(emailService emailservice.Service) RevertEmailChangeUseCase {
+	return &revertEmailChangeUseCase{
+		emailService: emailService,
+	}
+}
+
+func (uc *revertEmailChangeUseCase) Call(ctx context.Context, token string) (*entity.User, error) {
+	return uc.emailService.RevertChange(ctx, token)
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+
+	apitokenservice "example.com/internal/domain/service/apitoken"
+)
+
+type RevokeAPITokenUseCase interface {
+	Call(ctx context.Context, userID, tokenID string) error
+}
+
+type revokeAPITokenUseCase struct {
+	tokenService apitokenservice.Service
+}
+
+func NewRevokeAPITokenUseCase(tokenService apitokenservice.Service) RevokeAPITokenUseCase {
+	return &revokeAPITokenUseCase{
+		tokenService: tokenService,
+	}
+}
+
+func (uc *revokeAPITokenUseCase) Call(ctx context.Context, userID, tokenID string) error {
+	return uc.tokenService.Revoke(ctx, userID, tokenID)
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"context"
+
+	oauthservice "example.com/internal/domain/service/oauth"
+)
+
+type RevokeOAuthTokenUseCase interface {
+	Call(ctx context.Context, clientID, clientSecret, token string) error
+}
+
+type revokeOAuthTokenUseCase struct {
+	oauthService oauthservice.Service
+}
+
+func NewRevokeOAuthTokenUseCase(oauthService oauthservice.Service) RevokeOAuthTokenUseCase {
+	return &revokeOAuthTokenUseCase{
+		oauthService: oauthService,
+	}
+}
+
+func (uc *revokeOAuthTokenUseCase) Call(ctx context.Context, clientID, clientSecret, token string) error {
+	client, err := uc.oauthService.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+	return uc.oauthService.Revoke(ctx, client, token)
+}
@@ -2,9 +2,12 @@ package auth
 
 import (
 	"context"
+	"strings"
 
 	"example.com/internal/domain/entity"
 	authservice "example.com/internal/domain/service/auth"
+	passwordservice "example.com/internal/domain/service/password"
+	usernameservice "example.com/internal/domain/service/username"
 )
 
 type SignupUseCase interface {
@@ -12,16 +15,46 @@ type SignupUseCase interface {
 }
 
 type signupUseCase struct {
-	authService authservice.Service
+	authService     authservice.Service
+	passwordService passwordservice.Service
+	usernameService usernameservice.Service
 }
 
-func NewSignupUseCase(authService authservice.Service) SignupUseCase {
+// NewSignupUseCase creates the signup use case; passwords are checked against the policy of
+// passwordService unless it is nil, and usernameService checks usernames and derives one from
+// the email when none is given unless it is nil
+func NewSignupUseCase(
+	authService authservice.Service, passwordService passwordservice.Service, usernameService usernameservice.Service,
+) SignupUseCase {
 	return &signupUseCase{
-		authService: authService,
+		authService:     authService,
+		passwordService: passwordService,
+		usernameService: usernameService,
 	}
 }
 
 func (uc *signupUseCase) Call(ctx context.Context, email, password, username string) (*entity.User, error) {
+	// Check the username, or derive one
+	if uc.usernameService != nil {
+		var err error
+		if username == "" {
+			localPart, _, _ := strings.Cut(email, "@")
+			username, err = uc.usernameService.Generate(ctx, localPart)
+		} else {
+			username, err = uc.usernameService.Check(ctx, "", username)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check the password policy
+	if uc.passwordService != nil {
+		if err := uc.passwordService.Check(ctx, password, username, email); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if user already exists
 	if err := uc.authService.CheckUserExists(ctx, email, username); err != nil {
 		return nil, err
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+
+	"example.com/pkg/oidc"
+)
+
+type StartOIDCUseCase interface {
+	// Call returns the provider URL to send the browser to and the request to keep until the callback
+	Call(ctx context.Context, provider string) (string, oidc.AuthRequest, error)
+}
+
+type startOIDCUseCase struct {
+	registry *oidc.Registry
+}
+
+func NewStartOIDCUseCase(registry *oidc.Registry) StartOIDCUseCase {
+	return &startOIDCUseCase{
+		registry: registry,
+	}
+}
+
+func (uc *startOIDCUseCase) Call(ctx context.Context, provider string) (string, oidc.AuthRequest, error) {
+	p, ok := uc.registry.Get(provider)
+	if !ok {
+		return "", oidc.AuthRequest{}, oidc.ErrUnknownProvider
+	}
+
+	req, err := oidc.NewAuthRequest()
+	if err != nil {
+		return "", oidc.AuthRequest{}, err
+	}
+
+	url, err := p.AuthCodeURL(ctx, req)
+	if err != nil {
+		return "", oidc.AuthRequest{}, err
+	}
+	return url, req, nil
+}
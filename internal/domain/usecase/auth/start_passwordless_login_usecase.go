@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+
+	passwordlessservice "example.com/internal/domain/service/passwordless"
+)
+
+type StartPasswordlessLoginUseCase interface {
+	// Call emails a login link or code and returns the binding the requesting device has to keep
+	Call(ctx context.Context, email, method string) (*passwordlessservice.Binding, error)
+}
+
+type startPasswordlessLoginUseCase struct {
+	passwordlessService passwordlessservice.Service
+}
+
+func NewStartPasswordlessLoginUseCase(passwordlessService passwordlessservice.Service) StartPasswordlessLoginUseCase {
+	return &startPasswordlessLoginUseCase{
+		passwordlessService: passwordlessService,
+	}
+}
+
+func (uc *startPasswordlessLoginUseCase) Call(ctx context.Context, email, method string) (*passwordlessservice.Binding, error) {
+	return uc.passwordlessService.Start(ctx, email, method)
+}
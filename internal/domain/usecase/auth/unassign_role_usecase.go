@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+
+	policyservice "example.com/internal/domain/service/policy"
+)
+
+type UnassignRoleUseCase interface {
+	Call(ctx context.Context, userID, roleName string) error
+}
+
+type unassignRoleUseCase struct {
+	policyService policyservice.Service
+}
+
+func NewUnassignRoleUseCase(policyService policyservice.Service) UnassignRoleUseCase {
+	return &unassignRoleUseCase{
+		policyService: policyService,
+	}
+}
+
+func (uc *unassignRoleUseCase) Call(ctx context.Context, userID, roleName string) error {
+	return uc.policyService.Unassign(ctx, userID, roleName)
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+
+	identityservice "example.com/internal/domain/service/identity"
+)
+
+type UnlinkIdentityUseCase interface {
+	Call(ctx context.Context, userID, identityID string) error
+}
+
+type unlinkIdentityUseCase struct {
+	identityService identityservice.Service
+}
+
+func NewUnlinkIdentityUseCase(identityService identityservice.Service) UnlinkIdentityUseCase {
+	return &unlinkIdentityUseCase{
+		identityService: identityService,
+	}
+}
+
+func (uc *unlinkIdentityUseCase) Call(ctx context.Context, userID, identityID string) error {
+	return uc.identityService.Unlink(ctx, userID, identityID)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	authservice "example.com/internal/domain/service/auth"
+)
+
+type ValidateSessionUseCase interface {
+	// Call reports whether a session of userID started at startedAt may still be used
+	Call(ctx context.Context, userID string, startedAt time.Time) (bool, error)
+}
+
+type validateSessionUseCase struct {
+	authService authservice.Service
+}
+
+func NewValidateSessionUseCase(authService authservice.Service) ValidateSessionUseCase {
+	return &validateSessionUseCase{
+		authService: authService,
+	}
+}
+
+func (uc *validateSessionUseCase) Call(ctx context.Context, userID string, startedAt time.Time) (bool, error) {
+	return uc.authService.SessionValid(ctx, userID, startedAt)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type AcceptInvitationUseCase interface {
+	Call(ctx context.Context, userID, token string) (*entity.Membership, error)
+}
+
+type acceptInvitationUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewAcceptInvitationUseCase(organizationService organizationservice.Service) AcceptInvitationUseCase {
+	return &acceptInvitationUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *acceptInvitationUseCase) Call(ctx context.Context, userID, token string) (*entity.Membership, error) {
+	return uc.organizationService.Accept(ctx, userID, token)
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type CancelAccountDeletionUseCase interface {
+	Call(ctx context.Context, userID string) error
+}
+
+type cancelAccountDeletionUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewCancelAccountDeletionUseCase(privacyService privacyservice.Service) CancelAccountDeletionUseCase {
+	return &cancelAccountDeletionUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *cancelAccountDeletionUseCase) Call(ctx context.Context, userID string) error {
+	return uc.privacyService.CancelDeletion(ctx, userID)
+}
@@ -0,0 +1,30 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	usernameservice "example.com/internal/domain/service/username"
+)
+
+type ChangeUsernameUseCase interface {
+	// Call renames userID to username and returns the user with their renames, newest first
+	Call(ctx context.Context, userID, username string) (*entity.User, []*entity.UserNameChange, error)
+}
+
+type changeUsernameUseCase struct {
+	usernameService usernameservice.Service
+}
+
+func NewChangeUsernameUseCase(usernameService usernameservice.Service) ChangeUsernameUseCase {
+	return &changeUsernameUseCase{
+		usernameService: usernameService,
+	}
+}
+
+func (uc *changeUsernameUseCase) Call(ctx context.Context, userID, username string) (*entity.User, []*entity.UserNameChange, error) {
+	if _, err := uc.usernameService.Rename(ctx, userID, username); err != nil {
+		return nil, nil, err
+	}
+	return uc.usernameService.History(ctx, userID)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type ConfirmAccountDeletionUseCase interface {
+	Call(ctx context.Context, userID, token string) (*entity.AccountDeletion, error)
+}
+
+type confirmAccountDeletionUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewConfirmAccountDeletionUseCase(privacyService privacyservice.Service) ConfirmAccountDeletionUseCase {
+	return &confirmAccountDeletionUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *confirmAccountDeletionUseCase) Call(ctx context.Context, userID, token string) (*entity.AccountDeletion, error) {
+	return uc.privacyService.ConfirmDeletion(ctx, userID, token)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type CreateOrganizationUseCase interface {
+	Call(ctx context.Context, userID, name string) (*entity.Membership, error)
+}
+
+type createOrganizationUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewCreateOrganizationUseCase(organizationService organizationservice.Service) CreateOrganizationUseCase {
+	return &createOrganizationUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *createOrganizationUseCase) Call(ctx context.Context, userID, name string) (*entity.Membership, error) {
+	return uc.organizationService.Create(ctx, userID, name)
+}
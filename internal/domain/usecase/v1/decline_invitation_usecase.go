@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type DeclineInvitationUseCase interface {
+	Call(ctx context.Context, userID, token string) error
+}
+
+type declineInvitationUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewDeclineInvitationUseCase(organizationService organizationservice.Service) DeclineInvitationUseCase {
+	return &declineInvitationUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *declineInvitationUseCase) Call(ctx context.Context, userID, token string) error {
+	return uc.organizationService.Decline(ctx, userID, token)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	avatarservice "example.com/internal/domain/service/avatar"
+)
+
+type DeleteAvatarUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.UserProfile, error)
+}
+
+type deleteAvatarUseCase struct {
+	avatarService avatarservice.Service
+}
+
+func NewDeleteAvatarUseCase(avatarService avatarservice.Service) DeleteAvatarUseCase {
+	return &deleteAvatarUseCase{
+		avatarService: avatarService,
+	}
+}
+
+func (uc *deleteAvatarUseCase) Call(ctx context.Context, userID string) (*entity.UserProfile, error) {
+	return uc.avatarService.Delete(ctx, userID)
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	adminservice "example.com/internal/domain/service/admin"
+)
+
+type DeleteUserUseCase interface {
+	Call(ctx context.Context, actorID, userID string) error
+}
+
+type deleteUserUseCase struct {
+	adminService adminservice.Service
+}
+
+func NewDeleteUserUseCase(adminService adminservice.Service) DeleteUserUseCase {
+	return &deleteUserUseCase{
+		adminService: adminService,
+	}
+}
+
+func (uc *deleteUserUseCase) Call(ctx context.Context, actorID, userID string) error {
+	return uc.adminService.Delete(ctx, actorID, userID)
+}
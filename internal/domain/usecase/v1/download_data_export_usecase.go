@@ -0,0 +1,27 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+	"example.com/pkg/storage"
+)
+
+type DownloadDataExportUseCase interface {
+	Call(ctx context.Context, token string) (*entity.DataExport, *storage.Blob, error)
+}
+
+type downloadDataExportUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewDownloadDataExportUseCase(privacyService privacyservice.Service) DownloadDataExportUseCase {
+	return &downloadDataExportUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *downloadDataExportUseCase) Call(ctx context.Context, token string) (*entity.DataExport, *storage.Blob, error) {
+	return uc.privacyService.Download(ctx, token)
+}
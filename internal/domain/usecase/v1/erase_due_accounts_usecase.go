@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type EraseDueAccountsUseCase interface {
+	Call(ctx context.Context) (int, error)
+}
+
+type eraseDueAccountsUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewEraseDueAccountsUseCase(privacyService privacyservice.Service) EraseDueAccountsUseCase {
+	return &eraseDueAccountsUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *eraseDueAccountsUseCase) Call(ctx context.Context) (int, error) {
+	return uc.privacyService.EraseDueAccounts(ctx)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type GetAccountDeletionUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.AccountDeletion, error)
+}
+
+type getAccountDeletionUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewGetAccountDeletionUseCase(privacyService privacyservice.Service) GetAccountDeletionUseCase {
+	return &getAccountDeletionUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *getAccountDeletionUseCase) Call(ctx context.Context, userID string) (*entity.AccountDeletion, error) {
+	return uc.privacyService.Deletion(ctx, userID)
+}
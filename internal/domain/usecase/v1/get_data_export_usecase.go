@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type GetDataExportUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.DataExport, error)
+}
+
+type getDataExportUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewGetDataExportUseCase(privacyService privacyservice.Service) GetDataExportUseCase {
+	return &getDataExportUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *getDataExportUseCase) Call(ctx context.Context, userID string) (*entity.DataExport, error) {
+	return uc.privacyService.LatestExport(ctx, userID)
+}
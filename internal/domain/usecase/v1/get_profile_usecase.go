@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	profileservice "example.com/internal/domain/service/profile"
+)
+
+type GetProfileUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.UserProfile, error)
+}
+
+type getProfileUseCase struct {
+	profileService profileservice.Service
+}
+
+func NewGetProfileUseCase(profileService profileservice.Service) GetProfileUseCase {
+	return &getProfileUseCase{
+		profileService: profileService,
+	}
+}
+
+func (uc *getProfileUseCase) Call(ctx context.Context, userID string) (*entity.UserProfile, error) {
+	return uc.profileService.Get(ctx, userID)
+}
@@ -0,0 +1,27 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	usernameservice "example.com/internal/domain/service/username"
+)
+
+type GetUsernameUseCase interface {
+	// Call returns userID with their renames, newest first
+	Call(ctx context.Context, userID string) (*entity.User, []*entity.UserNameChange, error)
+}
+
+type getUsernameUseCase struct {
+	usernameService usernameservice.Service
+}
+
+func NewGetUsernameUseCase(usernameService usernameservice.Service) GetUsernameUseCase {
+	return &getUsernameUseCase{
+		usernameService: usernameService,
+	}
+}
+
+func (uc *getUsernameUseCase) Call(ctx context.Context, userID string) (*entity.User, []*entity.UserNameChange, error) {
+	return uc.usernameService.History(ctx, userID)
+}
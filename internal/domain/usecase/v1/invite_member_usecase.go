@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type InviteMemberUseCase interface {
+	Call(ctx context.Context, actorID, organizationID, email, role string) (*entity.Invitation, error)
+}
+
+type inviteMemberUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewInviteMemberUseCase(organizationService organizationservice.Service) InviteMemberUseCase {
+	return &inviteMemberUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *inviteMemberUseCase) Call(ctx context.Context, actorID, organizationID, email, role string) (*entity.Invitation, error) {
+	return uc.organizationService.Invite(ctx, actorID, organizationID, email, role)
+}
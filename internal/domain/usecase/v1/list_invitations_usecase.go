@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type ListInvitationsUseCase interface {
+	Call(ctx context.Context, actorID, organizationID string) ([]*entity.Invitation, error)
+}
+
+type listInvitationsUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewListInvitationsUseCase(organizationService organizationservice.Service) ListInvitationsUseCase {
+	return &listInvitationsUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *listInvitationsUseCase) Call(ctx context.Context, actorID, organizationID string) ([]*entity.Invitation, error) {
+	return uc.organizationService.Invitations(ctx, actorID, organizationID)
+}
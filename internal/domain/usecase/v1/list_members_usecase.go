@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type ListMembersUseCase interface {
+	Call(ctx context.Context, actorID, organizationID string) ([]*entity.Membership, error)
+}
+
+type listMembersUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewListMembersUseCase(organizationService organizationservice.Service) ListMembersUseCase {
+	return &listMembersUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *listMembersUseCase) Call(ctx context.Context, actorID, organizationID string) ([]*entity.Membership, error) {
+	return uc.organizationService.Members(ctx, actorID, organizationID)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type ListOrganizationsUseCase interface {
+	Call(ctx context.Context, userID string) ([]*entity.Membership, error)
+}
+
+type listOrganizationsUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewListOrganizationsUseCase(organizationService organizationservice.Service) ListOrganizationsUseCase {
+	return &listOrganizationsUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *listOrganizationsUseCase) Call(ctx context.Context, userID string) ([]*entity.Membership, error) {
+	return uc.organizationService.List(ctx, userID)
+}
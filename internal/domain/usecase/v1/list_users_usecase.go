@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/repository"
+	adminservice "example.com/internal/domain/service/admin"
+)
+
+type ListUsersUseCase interface {
+	Call(ctx context.Context, query repository.UserQuery, cursor string) (*adminservice.Page, error)
+}
+
+type listUsersUseCase struct {
+	adminService adminservice.Service
+}
+
+func NewListUsersUseCase(adminService adminservice.Service) ListUsersUseCase {
+	return &listUsersUseCase{
+		adminService: adminService,
+	}
+}
+
+func (uc *listUsersUseCase) Call(ctx context.Context, query repository.UserQuery, cursor string) (*adminservice.Page, error) {
+	return uc.adminService.List(ctx, query, cursor)
+}
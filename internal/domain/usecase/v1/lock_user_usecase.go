@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	adminservice "example.com/internal/domain/service/admin"
+)
+
+type LockUserUseCase interface {
+	Call(ctx context.Context, actorID, userID string) (*entity.User, error)
+}
+
+type lockUserUseCase struct {
+	adminService adminservice.Service
+}
+
+func NewLockUserUseCase(adminService adminservice.Service) LockUserUseCase {
+	return &lockUserUseCase{
+		adminService: adminService,
+	}
+}
+
+func (uc *lockUserUseCase) Call(ctx context.Context, actorID, userID string) (*entity.User, error) {
+	return uc.adminService.Lock(ctx, actorID, userID)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	avatarservice "example.com/internal/domain/service/avatar"
+	"example.com/pkg/storage"
+)
+
+type OpenAvatarUseCase interface {
+	Call(ctx context.Context, key string) (*storage.Blob, error)
+}
+
+type openAvatarUseCase struct {
+	avatarService avatarservice.Service
+}
+
+func NewOpenAvatarUseCase(avatarService avatarservice.Service) OpenAvatarUseCase {
+	return &openAvatarUseCase{
+		avatarService: avatarService,
+	}
+}
+
+func (uc *openAvatarUseCase) Call(ctx context.Context, key string) (*storage.Blob, error) {
+	return uc.avatarService.Open(ctx, key)
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type ProcessDataExportsUseCase interface {
+	Call(ctx context.Context) (int, error)
+}
+
+type processDataExportsUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewProcessDataExportsUseCase(privacyService privacyservice.Service) ProcessDataExportsUseCase {
+	return &processDataExportsUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *processDataExportsUseCase) Call(ctx context.Context) (int, error) {
+	return uc.privacyService.ProcessExports(ctx)
+}
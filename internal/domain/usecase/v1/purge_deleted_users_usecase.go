@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	retentionservice "example.com/internal/domain/service/retention"
+)
+
+type PurgeDeletedUsersUseCase interface {
+	Call(ctx context.Context) (int, error)
+}
+
+type purgeDeletedUsersUseCase struct {
+	retentionService retentionservice.Service
+}
+
+func NewPurgeDeletedUsersUseCase(retentionService retentionservice.Service) PurgeDeletedUsersUseCase {
+	return &purgeDeletedUsersUseCase{
+		retentionService: retentionService,
+	}
+}
+
+func (uc *purgeDeletedUsersUseCase) Call(ctx context.Context) (int, error) {
+	return uc.retentionService.PurgeDeletedUsers(ctx)
+}
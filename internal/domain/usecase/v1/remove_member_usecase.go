@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type RemoveMemberUseCase interface {
+	Call(ctx context.Context, actorID, organizationID, userID string) error
+}
+
+type removeMemberUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewRemoveMemberUseCase(organizationService organizationservice.Service) RemoveMemberUseCase {
+	return &removeMemberUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *removeMemberUseCase) Call(ctx context.Context, actorID, organizationID, userID string) error {
+	return uc.organizationService.RemoveMember(ctx, actorID, organizationID, userID)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type RequestAccountDeletionUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.AccountDeletion, error)
+}
+
+type requestAccountDeletionUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewRequestAccountDeletionUseCase(privacyService privacyservice.Service) RequestAccountDeletionUseCase {
+	return &requestAccountDeletionUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *requestAccountDeletionUseCase) Call(ctx context.Context, userID string) (*entity.AccountDeletion, error) {
+	return uc.privacyService.RequestDeletion(ctx, userID)
+}
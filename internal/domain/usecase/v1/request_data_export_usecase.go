@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+)
+
+type RequestDataExportUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.DataExport, error)
+}
+
+type requestDataExportUseCase struct {
+	privacyService privacyservice.Service
+}
+
+func NewRequestDataExportUseCase(privacyService privacyservice.Service) RequestDataExportUseCase {
+	return &requestDataExportUseCase{
+		privacyService: privacyService,
+	}
+}
+
+func (uc *requestDataExportUseCase) Call(ctx context.Context, userID string) (*entity.DataExport, error) {
+	return uc.privacyService.RequestExport(ctx, userID)
+}
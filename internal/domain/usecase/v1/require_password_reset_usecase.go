@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	adminservice "example.com/internal/domain/service/admin"
+)
+
+type RequirePasswordResetUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.User, error)
+}
+
+type requirePasswordResetUseCase struct {
+	adminService adminservice.Service
+}
+
+func NewRequirePasswordResetUseCase(adminService adminservice.Service) RequirePasswordResetUseCase {
+	return &requirePasswordResetUseCase{
+		adminService: adminService,
+	}
+}
+
+func (uc *requirePasswordResetUseCase) Call(ctx context.Context, userID string) (*entity.User, error) {
+	return uc.adminService.RequirePasswordReset(ctx, userID)
+}
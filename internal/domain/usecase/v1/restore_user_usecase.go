@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	adminservice "example.com/internal/domain/service/admin"
+)
+
+type RestoreUserUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.User, error)
+}
+
+type restoreUserUseCase struct {
+	adminService adminservice.Service
+}
+
+func NewRestoreUserUseCase(adminService adminservice.Service) RestoreUserUseCase {
+	return &restoreUserUseCase{
+		adminService: adminService,
+	}
+}
+
+func (uc *restoreUserUseCase) Call(ctx context.Context, userID string) (*entity.User, error) {
+	return uc.adminService.Restore(ctx, userID)
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type RevokeInvitationUseCase interface {
+	Call(ctx context.Context, actorID, organizationID, invitationID string) error
+}
+
+type revokeInvitationUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewRevokeInvitationUseCase(organizationService organizationservice.Service) RevokeInvitationUseCase {
+	return &revokeInvitationUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *revokeInvitationUseCase) Call(ctx context.Context, actorID, organizationID, invitationID string) error {
+	return uc.organizationService.RevokeInvitation(ctx, actorID, organizationID, invitationID)
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	organizationservice "example.com/internal/domain/service/organization"
+)
+
+type TransferOwnershipUseCase interface {
+	Call(ctx context.Context, actorID, organizationID, newOwnerID string) error
+}
+
+type transferOwnershipUseCase struct {
+	organizationService organizationservice.Service
+}
+
+func NewTransferOwnershipUseCase(organizationService organizationservice.Service) TransferOwnershipUseCase {
+	return &transferOwnershipUseCase{
+		organizationService: organizationService,
+	}
+}
+
+func (uc *transferOwnershipUseCase) Call(ctx context.Context, actorID, organizationID, newOwnerID string) error {
+	return uc.organizationService.TransferOwnership(ctx, actorID, organizationID, newOwnerID)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	adminservice "example.com/internal/domain/service/admin"
+)
+
+type UnlockUserUseCase interface {
+	Call(ctx context.Context, userID string) (*entity.User, error)
+}
+
+type unlockUserUseCase struct {
+	adminService adminservice.Service
+}
+
+func NewUnlockUserUseCase(adminService adminservice.Service) UnlockUserUseCase {
+	return &unlockUserUseCase{
+		adminService: adminService,
+	}
+}
+
+func (uc *unlockUserUseCase) Call(ctx context.Context, userID string) (*entity.User, error) {
+	return uc.adminService.Unlock(ctx, userID)
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	profileservice "example.com/internal/domain/service/profile"
+)
+
+type UpdateProfileUseCase interface {
+	Call(ctx context.Context, userID string, patch profileservice.Patch) (*entity.UserProfile, error)
+}
+
+type updateProfileUseCase struct {
+	profileService profileservice.Service
+}
+
+func NewUpdateProfileUseCase(profileService profileservice.Service) UpdateProfileUseCase {
+	return &updateProfileUseCase{
+		profileService: profileService,
+	}
+}
+
+func (uc *updateProfileUseCase) Call(ctx context.Context, userID string, patch profileservice.Patch) (*entity.UserProfile, error) {
+	return uc.profileService.Update(ctx, userID, patch)
+}
@@ -0,0 +1,27 @@
+package user
+
+import (
+	"context"
+	"io"
+
+	"example.com/internal/domain/entity"
+	avatarservice "example.com/internal/domain/service/avatar"
+)
+
+type UploadAvatarUseCase interface {
+	Call(ctx context.Context, userID string, image io.Reader) (*entity.UserProfile, error)
+}
+
+type uploadAvatarUseCase struct {
+	avatarService avatarservice.Service
+}
+
+func NewUploadAvatarUseCase(avatarService avatarservice.Service) UploadAvatarUseCase {
+	return &uploadAvatarUseCase{
+		avatarService: avatarService,
+	}
+}
+
+func (uc *uploadAvatarUseCase) Call(ctx context.Context, userID string, image io.Reader) (*entity.UserProfile, error) {
+	return uc.avatarService.Upload(ctx, userID, image)
+}
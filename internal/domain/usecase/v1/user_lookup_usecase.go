@@ -5,28 +5,38 @@ import (
 
 	"example.com/internal/domain/entity"
 	userservice "example.com/internal/domain/service/v1"
+	"example.com/pkg/authz"
 )
 
 type UserLookupUseCase interface {
-	Call(ctx context.Context, email string) (*entity.User, error)
+	// Call finds the user with email on behalf of actorID
+	Call(ctx context.Context, actorID, email string) (*entity.User, error)
 }
 
 type userLookupUseCase struct {
 	userService userservice.Service
+	reader      userReadAuthorizer
 }
 
-func NewUserLookupUseCase(userService userservice.Service) UserLookupUseCase {
+// NewUserLookupUseCase returns a use case asking authorizer whether the actor may read the user
+// found; a nil authorizer lets every actor read every user
+func NewUserLookupUseCase(userService userservice.Service, authorizer authz.Authorizer) UserLookupUseCase {
 	return &userLookupUseCase{
 		userService: userService,
+		reader:      userReadAuthorizer{authorizer: authorizer},
 	}
 }
 
-func (uc *userLookupUseCase) Call(ctx context.Context, email string) (*entity.User, error) {
+func (uc *userLookupUseCase) Call(ctx context.Context, actorID, email string) (*entity.User, error) {
 	// Find user by email
 	user, err := uc.userService.FindUserByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := uc.reader.authorize(ctx, actorID, user, userservice.ErrUserNotFound); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
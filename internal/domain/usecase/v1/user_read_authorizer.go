@@ -0,0 +1,31 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"example.com/internal/domain/entity"
+	"example.com/pkg/authz"
+)
+
+// userReadAuthorizer decides whether an actor may read a user, for every lookup of users
+type userReadAuthorizer struct {
+	authorizer authz.Authorizer
+}
+
+// authorize returns notFound unless actorID may read user, so that lookups cannot probe which
+// users are registered. A nil authorizer lets every actor read every user.
+func (a userReadAuthorizer) authorize(ctx context.Context, actorID string, user *entity.User, notFound error) error {
+	if a.authorizer == nil {
+		return nil
+	}
+	err := authz.Enforce(ctx, a.authorizer, authz.Request{
+		Subject:  authz.Entity{Type: "user", ID: actorID},
+		Action:   entity.PermissionUsersRead,
+		Resource: authz.Entity{Type: "user", ID: user.ID, Attributes: map[string]any{"email": user.Email}},
+	})
+	if errors.Is(err, authz.ErrDenied) {
+		return notFound
+	}
+	return err
+}
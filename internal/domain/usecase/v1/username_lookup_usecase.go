@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+
+	"example.com/internal/domain/entity"
+	usernameservice "example.com/internal/domain/service/username"
+	"example.com/pkg/authz"
+)
+
+type UsernameLookupUseCase interface {
+	// Call finds the user with username on behalf of actorID, or the user who recently left
+	// username by a rename, in which case moved is true
+	Call(ctx context.Context, actorID, username string) (user *entity.User, moved bool, err error)
+}
+
+type usernameLookupUseCase struct {
+	usernameService usernameservice.Service
+	reader          userReadAuthorizer
+}
+
+// NewUsernameLookupUseCase returns a use case asking authorizer whether the actor may read the
+// user found; a nil authorizer lets every actor read every user
+func NewUsernameLookupUseCase(usernameService usernameservice.Service, authorizer authz.Authorizer) UsernameLookupUseCase {
+	return &usernameLookupUseCase{
+		usernameService: usernameService,
+		reader:          userReadAuthorizer{authorizer: authorizer},
+	}
+}
+
+func (uc *usernameLookupUseCase) Call(ctx context.Context, actorID, username string) (*entity.User, bool, error) {
+	user, moved, err := uc.usernameService.Resolve(ctx, username)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := uc.reader.authorize(ctx, actorID, user, usernameservice.ErrUserNotFound); err != nil {
+		return nil, false, err
+	}
+
+	return user, moved, nil
+}
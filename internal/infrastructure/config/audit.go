@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"example.com/pkg/security"
+)
+
+// minSecretLength is the shortest CSRF or session secret accepted in production
+const minSecretLength = 32
+
+// Finding is a setting that is unsafe to run in production
+type Finding struct {
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return f.Path + ": " + f.Message
+}
+
+// AuditError lists every finding that prevents starting in production
+type AuditError struct {
+	Findings []Finding
+}
+
+func (e *AuditError) Error() string {
+	lines := make([]string, len(e.Findings))
+	for i, finding := range e.Findings {
+		lines[i] = finding.String()
+	}
+	return "insecure configuration for production:\n" + strings.Join(lines, "\n")
+}
+
+// Audit reports default or short secrets, cookies sent over plain HTTP, database connections
+// without TLS, CORS open to every origin and login codes that are logged rather than emailed. The server refuses to start in production with
+// any finding and logs them elsewhere.
+func Audit(cfg *Config) []Finding {
+	var findings []Finding
+
+	findings = append(findings, auditKeys("security.csrf_keys", cfg.Security.CSRFKeys,
+		"security.csrf_secret", cfg.Security.CSRFSecret)...)
+	findings = append(findings, auditKeys("security.session_signing_keys", cfg.Security.SessionSigningKeys,
+		"security.session_secret", cfg.Security.SessionSecret)...)
+	findings = append(findings, auditKeys("security.session_encryption_keys", cfg.Security.SessionEncryptionKeys, "", "")...)
+	findings = append(findings, auditKeys("avatars.url_signing_keys", cfg.Avatars.URLSigningKeys, "", "")...)
+
+	csrf, csrfErr := cfg.Security.CSRFRing()
+	session, sessionErr := cfg.Security.SessionSigningRing()
+	if csrfErr == nil && sessionErr == nil && string(csrf.Current().Secret) == string(session.Current().Secret) {
+		findings = append(findings, Finding{Path: "security.csrf_secret", Message: "must differ from the session signing secret"})
+	}
+
+	if !cfg.Security.CookieSecure {
+		findings = append(findings, Finding{Path: "security.cookie_secure", Message: "session cookies are sent over plain HTTP"})
+	}
+
+	if finding, ok := auditDatabaseTLS(cfg.Database); ok {
+		findings = append(findings, finding)
+	}
+
+	if slices.Contains(cfg.Server.CORSAllowedOrigins, "*") {
+		findings = append(findings, Finding{Path: "server.cors_allowed_origins", Message: "allows credentialed requests from every origin"})
+	}
+
+	if cfg.Passwordless.Enabled && cfg.Mail.SMTPHost == "" {
+		findings = append(findings, Finding{Path: "mail.smtp_host", Message: "login codes are logged instead of emailed"})
+	}
+
+	return findings
+}
+
+// auditKeys checks the keys of a key ring, or the single fallback secret when no ring is set
+func auditKeys(keysPath string, specs []string, fallbackPath, fallback string) []Finding {
+	if len(specs) == 0 {
+		if fallbackPath == "" {
+			return nil
+		}
+		switch {
+		case fallback == defaultOf(fallbackPath):
+			return []Finding{{Path: fallbackPath, Message: "uses the built-in default"}}
+		case len(fallback) < minSecretLength:
+			return []Finding{{Path: fallbackPath, Message: fmt.Sprintf("is shorter than %d characters", minSecretLength)}}
+		}
+		return nil
+	}
+
+	var findings []Finding
+	for _, spec := range specs {
+		key, err := security.ParseKey(spec)
+		if err != nil {
+			// Reported when loading the configuration
+			continue
+		}
+		if len(key.Secret) < minSecretLength {
+			findings = append(findings, Finding{
+				Path:    keysPath,
+				Message: fmt.Sprintf("key %s is shorter than %d characters", key.ID, minSecretLength),
+			})
+		}
+	}
+	return findings
+}
+
+// insecureSSLModes do not require TLS; "prefer" silently falls back to plain text
+var insecureSSLModes = []string{"disable", "allow", "prefer"}
+
+func auditDatabaseTLS(cfg DatabaseConfig) (Finding, bool) {
+	if cfg.URL == "" {
+		if slices.Contains(insecureSSLModes, cfg.SSLMode) {
+			return Finding{Path: "database.sslmode", Message: fmt.Sprintf("%q does not require TLS", cfg.SSLMode)}, true
+		}
+		return Finding{}, false
+	}
+
+	mode, err := databaseSSLMode(cfg.URL)
+	if err != nil {
+		return Finding{Path: "database.url", Message: "cannot be parsed"}, true
+	}
+	if mode == "" {
+		return Finding{Path: "database.url", Message: "does not set sslmode and falls back to \"prefer\""}, true
+	}
+	if slices.Contains(insecureSSLModes, mode) {
+		return Finding{Path: "database.url", Message: fmt.Sprintf("sslmode %q does not require TLS", mode)}, true
+	}
+	return Finding{}, false
+}
+
+// databaseSSLMode returns the sslmode of a connection URL or key=value connection string
+func databaseSSLMode(dsn string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			return "", err
+		}
+		return parsed.Query().Get("sslmode"), nil
+	}
+
+	for _, pair := range strings.Fields(dsn) {
+		if key, value, ok := strings.Cut(pair, "="); ok && key == "sslmode" {
+			return strings.Trim(value, "'"), nil
+		}
+	}
+	return "", nil
+}
+
+// defaultOf returns the `default` tag of the field at path
+func defaultOf(path string) string {
+	for _, f := range collectFields(reflect.ValueOf(&Config{}).Elem(), "", "") {
+		if f.path == path {
+			return f.defaultTag
+		}
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+
+	"example.com/pkg/authz"
+)
+
+//go:embed policies.yaml
+var defaultPolicies []byte
+
+// Policies returns the policies of PolicyFile, or the built-in policies when it is unset
+func (c AuthzConfig) Policies() (*authz.PolicySet, error) {
+	if c.PolicyFile == "" {
+		return authz.ParsePolicies(bytes.NewReader(defaultPolicies))
+	}
+
+	file, err := os.Open(c.PolicyFile)
+	if err != nil {
+		return nil, fmt.Errorf("authz.policy_file: %w", err)
+	}
+	defer file.Close()
+
+	policies, err := authz.ParsePolicies(file)
+	if err != nil {
+		return nil, fmt.Errorf("authz.policy_file: %w", err)
+	}
+	return policies, nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+
+	"example.com/pkg/security"
+	"example.com/pkg/storage"
+)
+
+// BlobStore returns the store the avatar images are kept in
+func (c AvatarsConfig) BlobStore() (storage.BlobStore, error) {
+	if c.Storage == "s3" {
+		store, err := storage.NewS3Store(storage.S3Config{
+			Endpoint: c.S3Endpoint,
+			Region:   c.S3Region,
+			Bucket:   c.S3Bucket,
+			Credentials: storage.Credentials{
+				AccessKeyID:     c.S3AccessKeyID,
+				SecretAccessKey: c.S3SecretAccessKey,
+			},
+			PathStyle: c.S3PathStyle,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("avatars.s3_*: %w", err)
+		}
+		return store, nil
+	}
+	return storage.NewFileStore(c.Dir)
+}
+
+// URLSigner returns the signer of avatar URLs, whose keys fall back to the session secret of
+// sec. The signer tells its signatures apart from those of session cookies.
+func (c AvatarsConfig) URLSigner(sec SecurityConfig) (*security.URLSigner, error) {
+	ring, err := keyRing(c.URLSigningKeys, sec.SessionSecret, sec.KeyGracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	return security.NewURLSigner(ring, c.URLTTL), nil
+}
@@ -1,72 +1,317 @@
 package config
 
 import (
-	"os"
-	"strconv"
+	"time"
 )
 
+// Config is resolved from, in increasing order of precedence: the `default` tags below,
+// a YAML or TOML file, environment variables and command line flags. See Loader.
+//
+// Every leaf field is addressed by the dotted path of its `key` tags (e.g. "server.port"),
+// which is both its key in the config file and its command line flag (--server.port).
 type Config struct {
-	Security SecurityConfig
-	Server   ServerConfig
-	Database DatabaseConfig
+	Security SecurityConfig    `key:"security"`
+	Server   ServerConfig      `key:"server"`
+	Database DatabaseConfig    `key:"database"`
+	OpenAPI  OpenAPIConfig     `key:"openapi"`
+	Legacy   LegacyAPIConfig   `key:"legacy_api"`
+	Metrics  MetricsConfig     `key:"metrics"`
+	JWT      JWTConfig         `key:"jwt"`
+	OIDC     OIDCConfig        `key:"oidc"`
+	OAuth    OAuthServerConfig `key:"oauth_server"`
+	Mail     MailConfig        `key:"mail"`
+	// Passwordless needs Mail to reach users
+	Passwordless PasswordlessConfig `key:"passwordless"`
+	Password     PasswordConfig     `key:"password"`
+	Authz        AuthzConfig        `key:"authz"`
+	// Organizations needs Mail to send invitations
+	Organizations OrganizationsConfig `key:"organizations"`
+	Tenancy       TenancyConfig       `key:"tenancy"`
+	Avatars       AvatarsConfig       `key:"avatars"`
+	// Users needs Mail to confirm email changes
+	Users UsersConfig `key:"users"`
+	// Privacy needs Mail to send export and deletion links
+	Privacy PrivacyConfig `key:"privacy"`
 }
 
 type ServerConfig struct {
-	Port string
-	Env  string
+	Port string `key:"port" env:"PORT" default:"8080" validate:"required"`
+	Env  string `key:"env"  env:"ENV"  default:"development" validate:"required,oneof=development test staging production"`
+	// CORSAllowedOrigins defaults to every origin outside production and to none in production
+	CORSAllowedOrigins []string `key:"cors_allowed_origins" env:"CORS_ALLOWED_ORIGINS" validate:"origin"`
 }
 
 type DatabaseConfig struct {
-	URL      string
-	Host     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-	Port     int
+	// URL is a connection URL or a key=value connection string, and takes precedence over the
+	// other fields
+	URL      string `key:"url"      env:"DATABASE_URL" validate:"dsn" secret:"true"`
+	Host     string `key:"host"     env:"DB_HOST"      default:"localhost"`
+	User     string `key:"user"     env:"DB_USER"      default:"postgres"`
+	Password string `key:"password" env:"DB_PASSWORD"  secret:"true"`
+	DBName   string `key:"name"     env:"DB_NAME"      default:"app_db"`
+	SSLMode  string `key:"sslmode"  env:"DB_SSLMODE"   default:"disable" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	Port     int    `key:"port"     env:"DB_PORT"      default:"5432" validate:"min=1,max=65535"`
+}
+
+type OpenAPIConfig struct {
+	// PublicURL defaults to http://localhost:<server.port>
+	PublicURL    string `key:"public_url"    env:"PUBLIC_URL" validate:"url"`
+	DocsUsername string `key:"docs_username" env:"OPENAPI_DOCS_USERNAME"`
+	DocsPassword string `key:"docs_password" env:"OPENAPI_DOCS_PASSWORD" secret:"true"`
+	// DocsEnabled defaults to true outside production
+	DocsEnabled       bool `key:"docs_enabled"       env:"OPENAPI_DOCS_ENABLED"`
+	ValidateResponses bool `key:"validate_responses" env:"OPENAPI_VALIDATE_RESPONSES"`
+}
+
+// LegacyAPIConfig controls how the unversioned /auth/user/* routes are retired
+type LegacyAPIConfig struct {
+	DeprecatedAt      time.Time `key:"deprecated_at"       env:"LEGACY_API_DEPRECATED_AT"`
+	Sunset            time.Time `key:"sunset"              env:"LEGACY_API_SUNSET"`
+	DocsURL           string    `key:"docs_url"            env:"LEGACY_API_DOCS_URL" validate:"url"`
+	RejectAfterSunset bool      `key:"reject_after_sunset" env:"LEGACY_API_REJECT_AFTER_SUNSET"`
+}
+
+type MetricsConfig struct {
+	// Enabled defaults to true outside production
+	Enabled  bool   `key:"enabled"  env:"METRICS_ENABLED"`
+	Username string `key:"username" env:"METRICS_USERNAME"`
+	Password string `key:"password" env:"METRICS_PASSWORD" secret:"true"`
 }
 
 type SecurityConfig struct {
-	CSRFSecret    string
-	SessionSecret string
-}
-
-func Load() (*Config, error) {
-	cfg := &Config{
-		Server: ServerConfig{
-			Port: getEnvOrDefault("PORT", "8080"),
-			Env:  getEnvOrDefault("ENV", "development"),
-		},
-		Database: DatabaseConfig{
-			URL:      os.Getenv("DATABASE_URL"),
-			Host:     getEnvOrDefault("DB_HOST", "localhost"),
-			Port:     getEnvIntOrDefault("DB_PORT", 5432),
-			User:     getEnvOrDefault("DB_USER", "postgres"),
-			Password: getEnvOrDefault("DB_PASSWORD", ""),
-			DBName:   getEnvOrDefault("DB_NAME", "app_db"),
-			SSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
-		},
-		Security: SecurityConfig{
-			CSRFSecret:    getEnvOrDefault("CSRF_SECRET", "csrf-secret-key"),
-			SessionSecret: getEnvOrDefault("SESSION_SECRET", "session-secret-key"),
-		},
-	}
+	CSRFSecret    string `key:"csrf_secret"    env:"CSRF_SECRET"    default:"csrf-secret-key"    validate:"required" secret:"true"`
+	SessionSecret string `key:"session_secret" env:"SESSION_SECRET" default:"session-secret-key" validate:"required" secret:"true"`
+	// Key rings list the current key first as "<id>:<secret>", then previous keys as
+	// "<id>:<secret>@<retired-at RFC 3339>", which are accepted for KeyGracePeriod after
+	// retirement. SessionSigningKeys and CSRFKeys take precedence over the single secrets.
+	SessionSigningKeys    []string `key:"session_signing_keys"    env:"SESSION_SIGNING_KEYS"    validate:"keyring" secret:"true"`
+	SessionEncryptionKeys []string `key:"session_encryption_keys" env:"SESSION_ENCRYPTION_KEYS" validate:"keyring" secret:"true"`
+	CSRFKeys              []string `key:"csrf_keys"               env:"CSRF_KEYS"               validate:"keyring" secret:"true"`
+	// KeyGracePeriod defaults to the session lifetime
+	KeyGracePeriod time.Duration `key:"key_grace_period" env:"KEY_GRACE_PERIOD" default:"168h"`
+	// CookieSecure restricts the session cookie to HTTPS and defaults to true in production
+	CookieSecure bool `key:"cookie_secure" env:"COOKIE_SECURE"`
+}
 
-	return cfg, nil
+// JWTConfig enables JWT access tokens and refresh tokens next to cookie sessions
+type JWTConfig struct {
+	// SigningKeyFiles lists "<kid>:<path>" pairs of PKCS #8 PEM Ed25519 or RSA private keys.
+	// The first key signs, the others only verify until they are removed. Tokens are
+	// disabled when empty.
+	SigningKeyFiles []string `key:"signing_key_files" env:"JWT_SIGNING_KEY_FILES"`
+	// Issuer defaults to openapi.public_url
+	Issuer          string        `key:"issuer"            env:"JWT_ISSUER"            validate:"url"`
+	AccessTokenTTL  time.Duration `key:"access_token_ttl"  env:"JWT_ACCESS_TOKEN_TTL"  default:"15m"  validate:"required"`
+	RefreshTokenTTL time.Duration `key:"refresh_token_ttl" env:"JWT_REFRESH_TOKEN_TTL" default:"720h" validate:"required"`
+}
+
+// OAuthServerConfig enables the OAuth2 authorization server for other applications. ID tokens
+// are signed with the keys of JWTConfig and are only issued when those are configured.
+type OAuthServerConfig struct {
+	Enabled bool `key:"enabled" env:"OAUTH_SERVER_ENABLED"`
+	// LoginURL and ConsentURL are the pages of the frontend the authorization endpoint sends
+	// the browser to; both default to openapi.public_url
+	LoginURL   string `key:"login_url"   env:"OAUTH_SERVER_LOGIN_URL"   validate:"url"`
+	ConsentURL string `key:"consent_url" env:"OAUTH_SERVER_CONSENT_URL" validate:"url"`
+	// FirstPartyClients are trusted client IDs that skip the consent screen
+	FirstPartyClients []string      `key:"first_party_clients" env:"OAUTH_SERVER_FIRST_PARTY_CLIENTS"`
+	CodeTTL           time.Duration `key:"code_ttl"            env:"OAUTH_SERVER_CODE_TTL"         default:"1m" validate:"required"`
+	AccessTokenTTL    time.Duration `key:"access_token_ttl"    env:"OAUTH_SERVER_ACCESS_TOKEN_TTL" default:"1h" validate:"required"`
+}
+
+// MailConfig is the SMTP server outgoing email is relayed through. Without a host, emails are
+// written to the log instead, which is only meant for development.
+type MailConfig struct {
+	SMTPHost     string `key:"smtp_host"     env:"SMTP_HOST"`
+	SMTPPort     int    `key:"smtp_port"     env:"SMTP_PORT"     default:"587" validate:"min=1,max=65535"`
+	SMTPUsername string `key:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `key:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+	From         string `key:"from"          env:"MAIL_FROM"     default:"no-reply@localhost" validate:"required,email"`
+}
+
+// PasswordlessConfig enables logging in with a single-use link or code sent by email
+type PasswordlessConfig struct {
+	Enabled bool `key:"enabled" env:"PASSWORDLESS_ENABLED"`
+	// LinkURL is the frontend page login links point to, with the token in the
+	// passwordless_token query parameter; defaults to openapi.public_url
+	LinkURL string        `key:"link_url" env:"PASSWORDLESS_LINK_URL" validate:"url"`
+	TTL     time.Duration `key:"ttl"      env:"PASSWORDLESS_TTL"      default:"15m" validate:"required"`
+	// MaxAttempts is the number of wrong codes after which a challenge is void
+	MaxAttempts int `key:"max_attempts" env:"PASSWORDLESS_MAX_ATTEMPTS" default:"5" validate:"min=1"`
+	// MaxPerHour limits the emails sent to one user per hour
+	MaxPerHour int `key:"max_per_hour" env:"PASSWORDLESS_MAX_PER_HOUR" default:"5" validate:"min=1"`
+}
+
+// PasswordConfig is the policy passwords chosen at signup or changed later have to meet
+type PasswordConfig struct {
+	// MinLength cannot go below the 8 characters the API schema requires
+	MinLength int `key:"min_length" env:"PASSWORD_MIN_LENGTH" default:"8"  validate:"min=8,max=72"`
+	// MaxLength is in bytes; bcrypt ignores everything beyond 72 bytes
+	MaxLength int `key:"max_length" env:"PASSWORD_MAX_LENGTH" default:"72" validate:"min=8,max=72"`
+	// MinCharClasses is how many of lowercase letters, uppercase letters, digits and symbols
+	// a password has to mix
+	MinCharClasses int `key:"min_char_classes" env:"PASSWORD_MIN_CHAR_CLASSES" default:"1" validate:"min=1,max=4"`
+	// BannedListFile holds passwords rejected in addition to the built-in list of common
+	// passwords, one per line
+	BannedListFile string `key:"banned_list_file" env:"PASSWORD_BANNED_LIST_FILE"`
+	// BreachDatasetFile is a dataset of breached password hashes written by `go run ./cmd/passwords
+	// import`; passwords found in it are refused
+	BreachDatasetFile string `key:"breach_dataset_file" env:"PASSWORD_BREACH_DATASET_FILE"`
+	// HistorySize is the number of previous passwords a new password must not repeat
+	HistorySize int `key:"history_size" env:"PASSWORD_HISTORY_SIZE" validate:"max=24"`
+}
+
+// AuthzConfig selects the authorization policies deciding what users may do with resources
+type AuthzConfig struct {
+	// PolicyFile replaces the built-in policies, see authz.ParsePolicies for the format
+	PolicyFile string `key:"policy_file" env:"AUTHZ_POLICY_FILE"`
+	// LogDecisions writes every authorization decision to the log, for audits
+	LogDecisions bool `key:"log_decisions" env:"AUTHZ_LOG_DECISIONS" default:"true"`
+}
+
+// OrganizationsConfig tunes the invitations to join an organization
+type OrganizationsConfig struct {
+	// InviteURL is the frontend page invitation links point to, with the token in the
+	// invitation_token query parameter; defaults to openapi.public_url
+	InviteURL     string        `key:"invite_url"     env:"ORGANIZATIONS_INVITE_URL"     validate:"url"`
+	InvitationTTL time.Duration `key:"invitation_ttl" env:"ORGANIZATIONS_INVITATION_TTL" default:"168h" validate:"required"`
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// UsersConfig tunes how long deleted users are kept and how users change their email and username
+type UsersConfig struct {
+	// DeletedRetention is how long deleted users can be restored before their personal data is purged
+	DeletedRetention time.Duration `key:"deleted_retention" env:"USERS_DELETED_RETENTION" default:"720h" validate:"required"`
+	// PurgeInterval is how often the server looks for deleted users to purge
+	PurgeInterval time.Duration `key:"purge_interval" env:"USERS_PURGE_INTERVAL" default:"1h" validate:"required,min=1s"`
+	// EmailChangeURL is the frontend page email change links point to, with the token in the
+	// email_change_token or email_revert_token query parameter; defaults to openapi.public_url
+	EmailChangeURL string        `key:"email_change_url" env:"USERS_EMAIL_CHANGE_URL" validate:"url"`
+	EmailChangeTTL time.Duration `key:"email_change_ttl" env:"USERS_EMAIL_CHANGE_TTL" default:"24h"  validate:"required"`
+	// EmailRevertTTL is how long the link sent to the old email can revert a change
+	EmailRevertTTL time.Duration `key:"email_revert_ttl" env:"USERS_EMAIL_REVERT_TTL" default:"168h" validate:"required"`
+	// UsernameMinLength is in characters; usernames have at most 15
+	UsernameMinLength int `key:"username_min_length" env:"USERS_USERNAME_MIN_LENGTH" default:"3" validate:"min=1,max=15"`
+	// ReservedUsernamesFile holds usernames refused in addition to the built-in list of reserved
+	// names, one per line
+	ReservedUsernamesFile string `key:"reserved_usernames_file" env:"USERS_RESERVED_USERNAMES_FILE"`
+	// UsernameRedirectTTL is how long a username left by a rename leads to its user, who alone
+	// may take it meanwhile
+	UsernameRedirectTTL time.Duration `key:"username_redirect_ttl" env:"USERS_USERNAME_REDIRECT_TTL" default:"720h" validate:"required"`
+	// UsernameRenameInterval is how long users wait between renames; 0 lets them rename at any time
+	UsernameRenameInterval time.Duration `key:"username_rename_interval" env:"USERS_USERNAME_RENAME_INTERVAL" default:"24h"`
+}
+
+// PrivacyConfig tunes data exports and account deletions requested by users
+type PrivacyConfig struct {
+	// ExportTTL is how long the download link of a data export works
+	ExportTTL time.Duration `key:"export_ttl" env:"PRIVACY_EXPORT_TTL" default:"48h" validate:"required"`
+	// DeletionConfirmURL is the frontend page deletion links point to, with the token in the
+	// deletion_token query parameter; defaults to openapi.public_url
+	DeletionConfirmURL string        `key:"deletion_confirm_url" env:"PRIVACY_DELETION_CONFIRM_URL" validate:"url"`
+	DeletionConfirmTTL time.Duration `key:"deletion_confirm_ttl" env:"PRIVACY_DELETION_CONFIRM_TTL" default:"24h"  validate:"required"`
+	// DeletionCoolingOff is how long confirmed deletions can be cancelled before the account is erased
+	DeletionCoolingOff time.Duration `key:"deletion_cooling_off" env:"PRIVACY_DELETION_COOLING_OFF" default:"168h" validate:"required"`
+	// JobInterval is how often the server builds requested exports and erases due accounts
+	JobInterval time.Duration `key:"job_interval" env:"PRIVACY_JOB_INTERVAL" default:"1m" validate:"required,min=1s"`
+}
+
+// TenancyConfig hosts several tenants in one deployment. Every request acts for one tenant
+// and only sees the data of that tenant.
+type TenancyConfig struct {
+	Enabled bool `key:"enabled" env:"TENANCY_ENABLED"`
+	// Tenants lists the IDs of the tenants served; requests for other tenants are rejected
+	Tenants []string `key:"tenants" env:"TENANCY_TENANTS" validate:"tenant"`
+	// BaseDomain addresses requests to <tenant>.<base_domain> to that tenant
+	BaseDomain string `key:"base_domain" env:"TENANCY_BASE_DOMAIN"`
+	// Header names the request header carrying the tenant ID; empty ignores headers
+	Header string `key:"header" env:"TENANCY_HEADER" default:"X-Tenant-ID"`
+}
+
+// AvatarsConfig stores the profile pictures users upload and signs the URLs they are served from
+type AvatarsConfig struct {
+	// Storage keeps the images in Dir with "file" or in an S3-compatible bucket with "s3"
+	Storage           string `key:"storage"              env:"AVATARS_STORAGE"              default:"file" validate:"required,oneof=file s3"`
+	Dir               string `key:"dir"                  env:"AVATARS_DIR"                  default:"data/avatars"`
+	S3Endpoint        string `key:"s3_endpoint"          env:"AVATARS_S3_ENDPOINT"          validate:"url"`
+	S3Region          string `key:"s3_region"            env:"AVATARS_S3_REGION"            default:"us-east-1"`
+	S3Bucket          string `key:"s3_bucket"            env:"AVATARS_S3_BUCKET"`
+	S3AccessKeyID     string `key:"s3_access_key_id"     env:"AVATARS_S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `key:"s3_secret_access_key" env:"AVATARS_S3_SECRET_ACCESS_KEY" secret:"true"`
+	// S3PathStyle puts the bucket in the path instead of the host name, as MinIO expects
+	S3PathStyle bool `key:"s3_path_style" env:"AVATARS_S3_PATH_STYLE" default:"true"`
+	// MaxUploadSize is in bytes; MaxPixels bounds width times height, as images are decoded in memory
+	MaxUploadSize int `key:"max_upload_size" env:"AVATARS_MAX_UPLOAD_SIZE" default:"5242880"  validate:"min=1"`
+	MaxPixels     int `key:"max_pixels"      env:"AVATARS_MAX_PIXELS"      default:"16777216" validate:"min=1"`
+	// URLSigningKeys is a key ring like security.session_signing_keys, falling back to the
+	// session secret
+	URLSigningKeys []string `key:"url_signing_keys" env:"AVATARS_URL_SIGNING_KEYS" validate:"keyring" secret:"true"`
+	// URLTTL is how long image URLs are valid. The same URL is handed out for half of it, so
+	// browsers can cache the images.
+	URLTTL time.Duration `key:"url_ttl" env:"AVATARS_URL_TTL" default:"24h" validate:"required"`
+}
+
+// Load resolves the configuration from the process environment and args, the command line
+// flags of the server. Commands with flags of their own pass none.
+func Load(args []string) (*Config, error) {
+	cfg, _, err := NewLoader(args).Load()
+	if err != nil {
+		return nil, err
 	}
-	return defaultValue
+	return cfg, nil
 }
 
-func getEnvIntOrDefault(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+// derive fills in defaults that depend on other values, once every layer has been applied
+func derive(cfg *Config, sources Sources) {
+	if _, ok := sources["openapi.public_url"]; !ok {
+		cfg.OpenAPI.PublicURL = "http://localhost:" + cfg.Server.Port
+		sources["openapi.public_url"] = "derived from server.port"
+	}
+	if _, ok := sources["openapi.docs_enabled"]; !ok {
+		cfg.OpenAPI.DocsEnabled = cfg.Server.Env != "production"
+		sources["openapi.docs_enabled"] = "derived from server.env"
+	}
+	if _, ok := sources["metrics.enabled"]; !ok {
+		cfg.Metrics.Enabled = cfg.Server.Env != "production"
+		sources["metrics.enabled"] = "derived from server.env"
+	}
+	if _, ok := sources["jwt.issuer"]; !ok {
+		cfg.JWT.Issuer = cfg.OpenAPI.PublicURL
+		sources["jwt.issuer"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["oidc.redirect_url"]; !ok {
+		cfg.OIDC.RedirectURL = cfg.OpenAPI.PublicURL
+		sources["oidc.redirect_url"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["oauth_server.login_url"]; !ok {
+		cfg.OAuth.LoginURL = cfg.OpenAPI.PublicURL
+		sources["oauth_server.login_url"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["oauth_server.consent_url"]; !ok {
+		cfg.OAuth.ConsentURL = cfg.OpenAPI.PublicURL
+		sources["oauth_server.consent_url"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["passwordless.link_url"]; !ok {
+		cfg.Passwordless.LinkURL = cfg.OpenAPI.PublicURL
+		sources["passwordless.link_url"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["organizations.invite_url"]; !ok {
+		cfg.Organizations.InviteURL = cfg.OpenAPI.PublicURL
+		sources["organizations.invite_url"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["users.email_change_url"]; !ok {
+		cfg.Users.EmailChangeURL = cfg.OpenAPI.PublicURL
+		sources["users.email_change_url"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["privacy.deletion_confirm_url"]; !ok {
+		cfg.Privacy.DeletionConfirmURL = cfg.OpenAPI.PublicURL
+		sources["privacy.deletion_confirm_url"] = "derived from openapi.public_url"
+	}
+	if _, ok := sources["security.cookie_secure"]; !ok {
+		cfg.Security.CookieSecure = cfg.Server.Env == "production"
+		sources["security.cookie_secure"] = "derived from server.env"
+	}
+	if _, ok := sources["server.cors_allowed_origins"]; !ok && cfg.Server.Env != "production" {
+		cfg.Server.CORSAllowedOrigins = []string{"*"}
+		sources["server.cors_allowed_origins"] = "derived from server.env"
 	}
-	return defaultValue
 }
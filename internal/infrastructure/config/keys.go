@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"example.com/pkg/security"
+)
+
+// fallbackKeyID names the single key built from CSRFSecret or SessionSecret when no key ring is configured
+const fallbackKeyID = "default"
+
+// SessionSigningRing returns the keys signing session cookies, falling back to SessionSecret
+func (c SecurityConfig) SessionSigningRing() (*security.KeyRing, error) {
+	return keyRing(c.SessionSigningKeys, c.SessionSecret, c.KeyGracePeriod)
+}
+
+// SessionEncryptionRing returns the keys encrypting session cookies, or nil when cookies are only signed
+func (c SecurityConfig) SessionEncryptionRing() (*security.KeyRing, error) {
+	if len(c.SessionEncryptionKeys) == 0 {
+		return nil, nil
+	}
+	return security.ParseKeyRing(c.SessionEncryptionKeys, c.KeyGracePeriod)
+}
+
+// CSRFRing returns the keys deriving CSRF tokens, falling back to CSRFSecret
+func (c SecurityConfig) CSRFRing() (*security.KeyRing, error) {
+	return keyRing(c.CSRFKeys, c.CSRFSecret, c.KeyGracePeriod)
+}
+
+func keyRing(specs []string, fallback string, grace time.Duration) (*security.KeyRing, error) {
+	if len(specs) == 0 {
+		return security.NewKeyRing([]security.Key{{ID: fallbackKeyID, Secret: []byte(fallback)}}, grace)
+	}
+	return security.ParseKeyRing(specs, grace)
+}
+
+// Enabled reports whether JWT signing keys are configured
+func (c JWTConfig) Enabled() bool {
+	return len(c.SigningKeyFiles) > 0
+}
+
+// TokenIssuer reads the signing keys and returns the issuer of access tokens, or nil when
+// tokens are disabled
+func (c JWTConfig) TokenIssuer() (*security.JWTIssuer, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	keys := make([]security.SigningKey, 0, len(c.SigningKeyFiles))
+	for _, spec := range c.SigningKeyFiles {
+		id, path, ok := strings.Cut(strings.TrimSpace(spec), ":")
+		if !ok || id == "" || path == "" {
+			return nil, fmt.Errorf("signing key %q must be written as <kid>:<path>", spec)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key %s: %w", id, err)
+		}
+		key, err := security.ParseSigningKey(id, data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return security.NewJWTIssuer(c.Issuer, keys, c.AccessTokenTTL)
+}
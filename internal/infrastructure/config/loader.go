@@ -0,0 +1,440 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// configFileEnv and configFileFlag name the optional YAML or TOML config file
+	configFileEnv  = "CONFIG_FILE"
+	configFileFlag = "config"
+	// secretFileSuffix reads a value from the file named by <ENV>_FILE, e.g. Docker and Kubernetes secrets
+	secretFileSuffix = "_FILE"
+
+	sourceDefault = "default"
+)
+
+// Sources records where the effective value of each field came from, keyed by its dotted path:
+// "default", "file:<path>", "env:<NAME>", "env:<NAME>_FILE" or "flag:--<path>".
+// Fields without a default that no layer set have no entry.
+type Sources map[string]string
+
+// FieldError describes a configuration value that could not be parsed or is invalid
+type FieldError struct {
+	Path    string
+	Source  string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	return fmt.Sprintf("%s (from %s): %s", e.Path, e.Source, e.Message)
+}
+
+// Loader resolves a Config from defaults, a config file, the environment and command line flags,
+// each layer overriding the previous one
+type Loader struct {
+	// FlagSet receives the configuration flags; a new one is created when nil, so that
+	// commands can register flags of their own alongside them
+	FlagSet   *flag.FlagSet
+	LookupEnv func(key string) (string, bool)
+	ReadFile  func(name string) ([]byte, error)
+	Args      []string
+}
+
+func NewLoader(args []string) *Loader {
+	return &Loader{
+		LookupEnv: os.LookupEnv,
+		ReadFile:  os.ReadFile,
+		Args:      args,
+	}
+}
+
+// field is a leaf of Config together with its struct tags
+type field struct {
+	value      reflect.Value
+	path       string
+	env        string
+	defaultTag string
+	validate   string
+	secret     bool
+}
+
+// Load resolves the configuration. Every malformed or invalid value is reported at once as a
+// joined list of *FieldError; the partially resolved Config and its Sources are returned with
+// such errors so that they can still be inspected.
+func (l *Loader) Load() (*Config, Sources, error) {
+	cfg := &Config{}
+	sources := Sources{}
+	fields := collectFields(reflect.ValueOf(cfg).Elem(), "", "")
+
+	flags, configFile, err := l.parseFlags(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if configFile == "" {
+		configFile, _ = l.LookupEnv(configFileEnv)
+	}
+	var fileValues map[string]string
+	if configFile != "" {
+		fileValues, err = l.readConfigFile(configFile)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	r := &resolver{loader: l, sources: sources, fileValues: fileValues, configFile: configFile, flags: flags}
+	r.apply(fields)
+
+	// OIDC providers are named by oidc.providers, so their fields are only known now. They
+	// are read from the config file and the environment but have no command line flags.
+	providerFields, err := oidcProviderFields(cfg)
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	r.apply(providerFields)
+	fields = append(fields, providerFields...)
+
+	for _, key := range sortedKeys(fileValues) {
+		r.errs = append(r.errs, &FieldError{Path: key, Source: "file:" + configFile, Message: "unknown key"})
+	}
+
+	errs := r.errs
+	derive(cfg, sources)
+
+	for _, f := range fields {
+		for _, message := range validateField(f) {
+			errs = append(errs, &FieldError{Path: f.path, Source: sources[f.path], Message: message})
+		}
+	}
+
+	if len(errs) > 0 {
+		return cfg, sources, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return cfg, sources, nil
+}
+
+// resolver applies the default, file, environment and flag layers to fields
+type resolver struct {
+	loader     *Loader
+	sources    Sources
+	fileValues map[string]string
+	flags      map[string]string
+	configFile string
+	errs       []error
+}
+
+func (r *resolver) apply(fields []field) {
+	for _, f := range fields {
+		if f.defaultTag != "" {
+			r.set(f, f.defaultTag, sourceDefault)
+		}
+	}
+
+	for _, f := range fields {
+		if raw, ok := r.fileValues[f.path]; ok {
+			r.set(f, raw, "file:"+r.configFile)
+			delete(r.fileValues, f.path)
+		}
+	}
+
+	for _, f := range fields {
+		raw, source, err := r.loader.lookupEnv(f.env)
+		if err != nil {
+			r.errs = append(r.errs, &FieldError{Path: f.path, Source: source, Message: err.Error()})
+			continue
+		}
+		if source != "" {
+			r.set(f, raw, source)
+		}
+	}
+
+	for _, f := range fields {
+		if raw, ok := r.flags[f.path]; ok {
+			r.set(f, raw, "flag:--"+f.path)
+		}
+	}
+}
+
+func (r *resolver) set(f field, raw, source string) {
+	if err := setValue(f.value, raw); err != nil {
+		r.errs = append(r.errs, &FieldError{Path: f.path, Source: source, Message: err.Error()})
+		return
+	}
+	r.sources[f.path] = source
+}
+
+// parseFlags registers --config and one flag per field, returning the raw values of the flags
+// that were set
+func (l *Loader) parseFlags(fields []field) (map[string]string, string, error) {
+	fs := l.FlagSet
+	if fs == nil {
+		fs = flag.NewFlagSet("config", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+	}
+
+	values := make(map[string]string)
+	configFile := fs.String(configFileFlag, "", "path to a YAML or TOML config file (env "+configFileEnv+")")
+	for _, f := range fields {
+		fs.Var(&flagValue{path: f.path, values: values, isBool: f.value.Kind() == reflect.Bool},
+			f.path, "overrides env "+f.env)
+	}
+
+	if err := fs.Parse(l.Args); err != nil {
+		return nil, "", err
+	}
+	return values, *configFile, nil
+}
+
+func (l *Loader) lookupEnv(key string) (value, source string, err error) {
+	if key == "" {
+		return "", "", nil
+	}
+
+	value, hasValue := l.LookupEnv(key)
+	path, hasFile := l.LookupEnv(key + secretFileSuffix)
+	hasValue = hasValue && value != ""
+	hasFile = hasFile && path != ""
+
+	switch {
+	case hasValue && hasFile:
+		return "", "env:" + key, fmt.Errorf("both %s and %s%s are set", key, key, secretFileSuffix)
+	case hasFile:
+		source = "env:" + key + secretFileSuffix
+		data, err := l.ReadFile(path)
+		if err != nil {
+			return "", source, fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), source, nil
+	case hasValue:
+		return value, "env:" + key, nil
+	default:
+		return "", "", nil
+	}
+}
+
+// readConfigFile flattens a YAML or TOML document into raw values keyed by dotted path
+func (l *Loader) readConfigFile(path string) (map[string]string, error) {
+	data, err := l.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	document := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.NewDecoder(bytes.NewReader(data)).Decode(&document)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q: use .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", document, values)
+	return values, nil
+}
+
+func flatten(prefix string, node map[string]any, values map[string]string) {
+	for key, value := range node {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			flatten(path, v, values)
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = scalarString(item)
+			}
+			values[path] = strings.Join(items, ",")
+		default:
+			values[path] = scalarString(v)
+		}
+	}
+}
+
+func scalarString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case toml.LocalDateTime:
+		return v.String() + "Z"
+	case toml.LocalDate:
+		return v.String() + "T00:00:00Z"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// collectFields returns the leaves of v under the dotted path prefix, prepending envPrefix
+// to their environment variables
+func collectFields(v reflect.Value, prefix, envPrefix string) []field {
+	var fields []field
+	t := v.Type()
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		path := sf.Tag.Get("key")
+		if path == "" {
+			continue
+		}
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			fields = append(fields, collectFields(fv, path, envPrefix)...)
+			continue
+		}
+
+		env := sf.Tag.Get("env")
+		if env != "" {
+			env = envPrefix + env
+		}
+
+		fields = append(fields, field{
+			value:      fv,
+			path:       path,
+			env:        env,
+			defaultTag: sf.Tag.Get("default"),
+			validate:   sf.Tag.Get("validate"),
+			secret:     sf.Tag.Get("secret") == "true",
+		})
+	}
+
+	return fields
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+func setValue(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch {
+	case v.Type() == timeType:
+		if raw == "" {
+			v.Set(reflect.ValueOf(time.Time{}))
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("invalid RFC 3339 time %q", raw)
+		}
+		v.Set(reflect.ValueOf(t))
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
+
+// formatValue renders a field the way it is written in the environment
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Type() == timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Slice:
+		return strings.Join(v.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// flagValue collects a flag's raw value so that it can be applied as the last layer
+type flagValue struct {
+	values map[string]string
+	path   string
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil || f.values == nil {
+		return ""
+	}
+	return f.values[f.path]
+}
+
+func (f *flagValue) Set(raw string) error {
+	f.values[f.path] = raw
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
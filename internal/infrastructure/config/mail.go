@@ -0,0 +1,19 @@
+package config
+
+import (
+	"example.com/pkg/mail"
+)
+
+// Sender returns the SMTP sender, or a sender writing to logger when no SMTP host is set
+func (c MailConfig) Sender(logger mail.Logger) (mail.Sender, error) {
+	if c.SMTPHost == "" {
+		return mail.NewLogSender(logger), nil
+	}
+	return mail.NewSMTPSender(mail.SMTPConfig{
+		Host:     c.SMTPHost,
+		Port:     c.SMTPPort,
+		Username: c.SMTPUsername,
+		Password: c.SMTPPassword,
+		From:     c.From,
+	})
+}
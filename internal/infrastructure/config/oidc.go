@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"example.com/pkg/oidc"
+)
+
+// providerNamePattern keeps provider names usable in URLs, config keys and environment variables
+var providerNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// OIDCConfig registers the OpenID Connect providers users can sign in with
+type OIDCConfig struct {
+	// Providers names every provider; each one is configured under oidc.<name> in the config
+	// file or with OIDC_<NAME>_* environment variables, e.g. OIDC_CORP_CLIENT_ID
+	Providers []string `key:"providers" env:"OIDC_PROVIDERS"`
+	// RedirectURL is where the browser lands after signing in, defaults to openapi.public_url
+	RedirectURL string `key:"redirect_url" env:"OIDC_REDIRECT_URL" validate:"url"`
+	// Provider holds the configuration of each provider, keyed by name
+	Provider map[string]*OIDCProviderConfig
+}
+
+// OIDCProviderConfig is a relying party registration with one provider. Its redirect URI is
+// <openapi.public_url>/api/v1/auth/oidc/<name>/callback.
+type OIDCProviderConfig struct {
+	// Issuer is the provider's issuer URL, from which its endpoints are discovered
+	Issuer       string   `key:"issuer"        env:"ISSUER"        validate:"required,url"`
+	ClientID     string   `key:"client_id"     env:"CLIENT_ID"     validate:"required"`
+	ClientSecret string   `key:"client_secret" env:"CLIENT_SECRET" secret:"true"`
+	DisplayName  string   `key:"display_name"  env:"DISPLAY_NAME"`
+	Scopes       []string `key:"scopes"        env:"SCOPES"        default:"openid,email,profile"`
+}
+
+// oidcProviderFields creates the configuration of every provider named in cfg.OIDC.Providers
+// and returns its fields, keyed oidc.<name>.<key> with environment variables OIDC_<NAME>_<ENV>
+func oidcProviderFields(cfg *Config) ([]field, error) {
+	cfg.OIDC.Provider = make(map[string]*OIDCProviderConfig, len(cfg.OIDC.Providers))
+
+	var fields []field
+	var invalid []string
+	for _, name := range cfg.OIDC.Providers {
+		// "providers" would collide with the key listing the providers
+		if !providerNamePattern.MatchString(name) || name == "providers" {
+			invalid = append(invalid, name)
+			continue
+		}
+		if _, ok := cfg.OIDC.Provider[name]; ok {
+			continue
+		}
+
+		provider := &OIDCProviderConfig{}
+		cfg.OIDC.Provider[name] = provider
+		fields = append(fields, providerFields(name, provider)...)
+	}
+
+	if len(invalid) > 0 {
+		return fields, &FieldError{
+			Path:    "oidc.providers",
+			Message: fmt.Sprintf("invalid provider names %q: use lowercase letters, digits and dashes", invalid),
+		}
+	}
+	return fields, nil
+}
+
+func providerFields(name string, provider *OIDCProviderConfig) []field {
+	envPrefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+	return collectFields(reflect.ValueOf(provider).Elem(), "oidc."+name, envPrefix)
+}
+
+// allFields returns the fields of cfg followed by those of its OIDC providers
+func allFields(cfg *Config) []field {
+	fields := collectFields(reflect.ValueOf(cfg).Elem(), "", "")
+	for _, name := range sortedKeys(cfg.OIDC.Provider) {
+		fields = append(fields, providerFields(name, cfg.OIDC.Provider[name])...)
+	}
+	return fields
+}
+
+// Registry registers every configured provider with its callback under publicURL
+func (c OIDCConfig) Registry(publicURL string) *oidc.Registry {
+	registry := oidc.NewRegistry()
+	for _, name := range c.Providers {
+		provider, ok := c.Provider[name]
+		if !ok {
+			continue
+		}
+		registry.Register(name, provider.DisplayName, oidc.NewProvider(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  strings.TrimSuffix(publicURL, "/") + "/api/v1/auth/oidc/" + name + "/callback",
+			Scopes:       provider.Scopes,
+		}, nil))
+	}
+	return registry
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"example.com/pkg/security"
+)
+
+// Policy returns the password policy, reading the banned passwords of BannedListFile
+func (c PasswordConfig) Policy() (*security.PasswordPolicy, error) {
+	if c.MinLength > c.MaxLength {
+		return nil, fmt.Errorf("password.min_length (%d) exceeds password.max_length (%d)", c.MinLength, c.MaxLength)
+	}
+
+	var banned []string
+	if c.BannedListFile != "" {
+		file, err := os.Open(c.BannedListFile)
+		if err != nil {
+			return nil, fmt.Errorf("password.banned_list_file: %w", err)
+		}
+		defer file.Close()
+
+		if banned, err = security.ParsePasswordList(file); err != nil {
+			return nil, fmt.Errorf("password.banned_list_file: %w", err)
+		}
+	}
+
+	return security.NewPasswordPolicy(security.PasswordRules{
+		MinLength:      c.MinLength,
+		MaxLength:      c.MaxLength,
+		MinCharClasses: c.MinCharClasses,
+		Banned:         banned,
+	}), nil
+}
+
+// BreachChecker opens the dataset of BreachDatasetFile, or returns nil when none is configured
+func (c PasswordConfig) BreachChecker() (security.PasswordBreachChecker, error) {
+	if c.BreachDatasetFile == "" {
+		return nil, nil
+	}
+	dataset, err := security.OpenBreachDataset(c.BreachDatasetFile)
+	if err != nil {
+		return nil, fmt.Errorf("password.breach_dataset_file: %w", err)
+	}
+	return dataset, nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"io"
+	"text/tabwriter"
+)
+
+const redactedValue = "<redacted>"
+
+// Print writes every field of cfg with its environment variable, effective value and source.
+// With redacted set, the values of fields tagged `secret:"true"` are masked.
+func Print(w io.Writer, cfg *Config, sources Sources, redacted bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if _, err := io.WriteString(tw, "KEY\tENV\tVALUE\tSOURCE\n"); err != nil {
+		return err
+	}
+
+	for _, f := range allFields(cfg) {
+		value := formatValue(f.value)
+		if redacted && f.secret && value != "" {
+			value = redactedValue
+		}
+
+		source, ok := sources[f.path]
+		if !ok {
+			source = "unset"
+		}
+
+		if _, err := io.WriteString(tw, f.path+"\t"+f.env+"\t"+value+"\t"+source+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
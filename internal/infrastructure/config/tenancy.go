@@ -0,0 +1,15 @@
+package config
+
+import (
+	"errors"
+
+	"example.com/pkg/tenant"
+)
+
+// Resolver returns the resolver finding the tenant of requests
+func (c TenancyConfig) Resolver() (*tenant.Resolver, error) {
+	if len(c.Tenants) == 0 {
+		return nil, errors.New("tenancy.tenants lists no tenant")
+	}
+	return tenant.NewResolver(c.Tenants, c.BaseDomain, c.Header), nil
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"example.com/internal/domain/entity"
+	"example.com/pkg/security"
+)
+
+// UsernamePolicy returns the username policy, reading the reserved names of ReservedUsernamesFile
+func (c UsersConfig) UsernamePolicy() (*security.UsernamePolicy, error) {
+	var reserved []string
+	if c.ReservedUsernamesFile != "" {
+		file, err := os.Open(c.ReservedUsernamesFile)
+		if err != nil {
+			return nil, fmt.Errorf("users.reserved_usernames_file: %w", err)
+		}
+		defer file.Close()
+
+		// Reserved names are listed like banned passwords
+		if reserved, err = security.ParsePasswordList(file); err != nil {
+			return nil, fmt.Errorf("users.reserved_usernames_file: %w", err)
+		}
+	}
+
+	return security.NewUsernamePolicy(security.UsernameRules{
+		MinLength: c.UsernameMinLength,
+		MaxLength: entity.UserNameMaxLength,
+		Reserved:  reserved,
+	}), nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"example.com/pkg/security"
+	"example.com/pkg/tenant"
+)
+
+// validateField checks a field against the comma separated rules of its `validate` tag:
+//
+//	required      the value must not be empty
+//	min=N, max=N  bounds the value of ints and the length of strings
+//	min=D, max=D  bounds durations by a duration like 1s
+//	oneof=a b c   the value must be one of the space separated options
+//	url           the value must be an absolute URL
+//	dsn           the value must be a PostgreSQL connection URL or key=value connection string
+//	origin        the value must be "*" or a scheme://host[:port] origin
+//	email         the value must be an email address, optionally with a display name
+//	keyring       the []string must be a key ring as accepted by security.ParseKeyRing
+//	tenant        the value must be a tenant ID as accepted by tenant.Valid
+//
+// Apart from required, rules are only checked for non-empty values. Rules other than
+// required and keyring apply to each element of a []string.
+func validateField(f field) []string {
+	if f.validate == "" {
+		return nil
+	}
+
+	var messages []string
+	empty := f.value.IsZero()
+
+	for _, rule := range strings.Split(f.validate, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+
+		if name == "required" {
+			if empty {
+				messages = append(messages, "is required")
+			}
+			continue
+		}
+		if empty {
+			continue
+		}
+
+		if name == "keyring" {
+			if _, err := security.ParseKeyRing(f.value.Interface().([]string), 0); err != nil {
+				messages = append(messages, err.Error())
+			}
+			continue
+		}
+		if f.value.Kind() != reflect.Slice {
+			if message := checkRule(f.value, name, arg); message != "" {
+				messages = append(messages, message)
+			}
+			continue
+		}
+		for i := range f.value.Len() {
+			if message := checkRule(f.value.Index(i), name, arg); message != "" {
+				messages = append(messages, fmt.Sprintf("element %d %s", i, message))
+			}
+		}
+	}
+
+	return messages
+}
+
+func checkRule(v reflect.Value, name, arg string) string {
+	switch name {
+	case "min", "max":
+		if v.Type() == durationType {
+			return checkDurationBound(v, name, arg)
+		}
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Sprintf("invalid rule %s=%s", name, arg)
+		}
+
+		n, unit := 0, ""
+		switch v.Kind() {
+		case reflect.Int:
+			n = int(v.Int())
+		case reflect.String:
+			n, unit = len(v.String()), " characters"
+		default:
+			return fmt.Sprintf("rule %s does not apply to %s", name, v.Type())
+		}
+
+		if name == "min" && n < limit {
+			return fmt.Sprintf("must be at least %d%s", limit, unit)
+		}
+		if name == "max" && n > limit {
+			return fmt.Sprintf("must be at most %d%s", limit, unit)
+		}
+	case "oneof":
+		options := strings.Fields(arg)
+		value := fmt.Sprint(v.Interface())
+		for _, option := range options {
+			if value == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(options, ", "), value)
+	case "url":
+		parsed, err := url.Parse(v.String())
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return "must be an absolute URL"
+		}
+	case "dsn":
+		// The error of the parser could quote the connection string, which holds the password
+		if _, err := pgconn.ParseConfig(v.String()); err != nil {
+			return "must be a PostgreSQL connection URL like postgres://user@host/db or a key=value string like host=db dbname=app"
+		}
+	case "origin":
+		if v.String() == "*" {
+			return ""
+		}
+		parsed, err := url.Parse(v.String())
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			strings.TrimSuffix(parsed.Path, "/") != "" {
+			return fmt.Sprintf("must be \"*\" or an origin like https://app.example.com, got %q", v.String())
+		}
+	case "email":
+		if _, err := mail.ParseAddress(v.String()); err != nil {
+			return fmt.Sprintf("must be an email address, got %q", v.String())
+		}
+	case "tenant":
+		if !tenant.Valid(v.String()) {
+			return fmt.Sprintf("must be a lowercase DNS label like acme, got %q", v.String())
+		}
+	default:
+		return fmt.Sprintf("unknown rule %q", name)
+	}
+
+	return ""
+}
+
+// checkDurationBound checks the min or max rule of a duration
+func checkDurationBound(v reflect.Value, name, arg string) string {
+	limit, err := time.ParseDuration(arg)
+	if err != nil {
+		return fmt.Sprintf("invalid rule %s=%s", name, arg)
+	}
+	d := time.Duration(v.Int())
+	if name == "min" && d < limit {
+		return fmt.Sprintf("must be at least %s, got %s", limit, d)
+	}
+	if name == "max" && d > limit {
+		return fmt.Sprintf("must be at most %s, got %s", limit, d)
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type accountDeletionRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountDeletionRepository(db *gorm.DB) repository.AccountDeletionRepository {
+	return &accountDeletionRepository{db: db}
+}
+
+func (r *accountDeletionRepository) Save(ctx context.Context, deletion *entity.AccountDeletion) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"created_at", "expires_at", "confirmed_at", "scheduled_at", "token_hash"}),
+	}).Create(deletion).Error
+}
+
+func (r *accountDeletionRepository) FindByUserID(ctx context.Context, userID string) (*entity.AccountDeletion, error) {
+	var deletion entity.AccountDeletion
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&deletion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+func (r *accountDeletionRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*entity.AccountDeletion, error) {
+	var deletions []*entity.AccountDeletion
+	err := r.db.WithContext(ctx).
+		Where("scheduled_at <= ?", before).
+		Order("scheduled_at").
+		Limit(limit).
+		Find(&deletions).Error
+	if err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+func (r *accountDeletionRepository) Delete(ctx context.Context, userID string) error {
+	result := r.db.WithContext(ctx).Delete(&entity.AccountDeletion{}, "user_id = ?", userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
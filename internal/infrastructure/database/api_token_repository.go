@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) repository.APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *entity.APIToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *apiTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.APIToken, error) {
+	var token entity.APIToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *apiTokenRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.APIToken, error) {
+	var tokens []*entity.APIToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, userID, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entity.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) RevokeUser(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.APIToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.APIToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
@@ -0,0 +1,22 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) repository.AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+func (r *auditEventRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
@@ -44,9 +44,60 @@ func ConnectFromURL(databaseURL string) (*gorm.DB, error) {
 	return db, nil
 }
 
+// models lists every table of the schema
+var models = []any{
+	&entity.User{},
+	&entity.UserProfile{},
+	&entity.APIToken{},
+	&entity.RefreshToken{},
+	&entity.UserIdentity{},
+	&entity.OAuthClient{},
+	&entity.OAuthAuthorizationCode{},
+	&entity.OAuthConsent{},
+	&entity.OAuthAccessToken{},
+	&entity.LoginChallenge{},
+	&entity.PasswordHistory{},
+	&entity.Permission{},
+	&entity.Role{},
+	&entity.UserRole{},
+	&entity.Organization{},
+	&entity.Membership{},
+	&entity.Invitation{},
+	&entity.AuditEvent{},
+	&entity.DataExport{},
+	&entity.AccountDeletion{},
+	&entity.EmailChange{},
+	&entity.UserNameChange{},
+}
+
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&entity.User{},
-		&entity.UserProfile{},
-	)
+	if err := db.AutoMigrate(models...); err != nil {
+		return err
+	}
+
+	// Identities were unique across tenants before tenant_id joined the index
+	if db.Migrator().HasIndex(&entity.UserIdentity{}, "idx_user_identities_provider_subject") {
+		if err := db.Migrator().DropIndex(&entity.UserIdentity{}, "idx_user_identities_provider_subject"); err != nil {
+			return err
+		}
+	}
+
+	// User names and emails were unique among deleted users too before the indexes only
+	// covered users that are not deleted
+	for _, index := range []string{"idx_users_tenant_user_name", "idx_users_tenant_email"} {
+		if db.Migrator().HasIndex(&entity.User{}, index) {
+			if err := db.Migrator().DropIndex(&entity.User{}, index); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Confusable user names are looked up by their skeleton
+	err := db.Exec("CREATE INDEX IF NOT EXISTS idx_users_user_name_skeleton ON users (tenant_id, (" + userNameSkeleton + ")) " +
+		"WHERE deleted_at IS NULL").Error
+	if err != nil {
+		return err
+	}
+
+	return enableRowLevelSecurity(db)
 }
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) repository.DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+func (r *dataExportRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *dataExportRepository) Update(ctx context.Context, export *entity.DataExport) error {
+	return r.db.WithContext(ctx).Save(export).Error
+}
+
+func (r *dataExportRepository) FindLatest(ctx context.Context, userID string) (*entity.DataExport, error) {
+	var export entity.DataExport
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.DataExport, error) {
+	var export entity.DataExport
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *dataExportRepository) ListPending(ctx context.Context, limit int) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	err := r.db.WithContext(ctx).
+		Where("status = ?", entity.DataExportPending).
+		Order("created_at").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *dataExportRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entity.DataExport, error) {
+	var exports []*entity.DataExport
+	err := r.db.WithContext(ctx).Where("expires_at < ?", before).Order("expires_at").Limit(limit).Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *dataExportRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&entity.DataExport{}, "id = ?", id).Error
+}
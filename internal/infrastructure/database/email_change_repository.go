@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type emailChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeRepository(db *gorm.DB) repository.EmailChangeRepository {
+	return &emailChangeRepository{db: db}
+}
+
+func (r *emailChangeRepository) Create(ctx context.Context, change *entity.EmailChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+func (r *emailChangeRepository) FindByConfirmHash(ctx context.Context, confirmHash string) (*entity.EmailChange, error) {
+	return r.find(ctx, "confirm_hash = ?", confirmHash)
+}
+
+func (r *emailChangeRepository) FindByRevertHash(ctx context.Context, revertHash string) (*entity.EmailChange, error) {
+	return r.find(ctx, "revert_hash = ?", revertHash)
+}
+
+func (r *emailChangeRepository) find(ctx context.Context, query string, args ...any) (*entity.EmailChange, error) {
+	var change entity.EmailChange
+	err := r.db.WithContext(ctx).Where(query, args...).First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *emailChangeRepository) Confirm(ctx context.Context, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entity.EmailChange{}).
+		Where("id = ? AND confirmed_at IS NULL AND reverted_at IS NULL", id).
+		Update("confirmed_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *emailChangeRepository) Revert(ctx context.Context, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entity.EmailChange{}).
+		Where("id = ? AND reverted_at IS NULL", id).
+		Update("reverted_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *emailChangeRepository) RevertPending(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.EmailChange{}).
+		Where("user_id = ? AND confirmed_at IS NULL AND reverted_at IS NULL", userID).
+		Update("reverted_at", at).Error
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) repository.InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *entity.Invitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id string) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) ListPending(ctx context.Context, organizationID string, now time.Time) ([]*entity.Invitation, error) {
+	var invitations []*entity.Invitation
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND status = ? AND expires_at > ?", organizationID, entity.InvitationPending, now).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) Accept(ctx context.Context, id string, membership *entity.Membership, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := respond(tx, id, entity.InvitationAccepted, at); err != nil {
+			return err
+		}
+		return tx.Omit("Organization", "User").Create(membership).Error
+	})
+}
+
+func (r *invitationRepository) Respond(ctx context.Context, id, status string, at time.Time) error {
+	return respond(r.db.WithContext(ctx), id, status, at)
+}
+
+func (r *invitationRepository) RevokePending(ctx context.Context, organizationID, email string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.Invitation{}).
+		Where("organization_id = ? AND email = ? AND status = ?", organizationID, email, entity.InvitationPending).
+		Updates(map[string]any{"status": entity.InvitationRevoked, "responded_at": at}).Error
+}
+
+// respond closes the invitation if it is pending
+func respond(tx *gorm.DB, id, status string, at time.Time) error {
+	result := tx.Model(&entity.Invitation{}).
+		Where("id = ? AND status = ?", id, entity.InvitationPending).
+		Updates(map[string]any{"status": status, "responded_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
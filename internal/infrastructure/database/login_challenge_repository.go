@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type loginChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginChallengeRepository(db *gorm.DB) repository.LoginChallengeRepository {
+	return &loginChallengeRepository{db: db}
+}
+
+func (r *loginChallengeRepository) Create(ctx context.Context, challenge *entity.LoginChallenge) error {
+	return r.db.WithContext(ctx).Create(challenge).Error
+}
+
+func (r *loginChallengeRepository) FindByID(ctx context.Context, id string) (*entity.LoginChallenge, error) {
+	var challenge entity.LoginChallenge
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&challenge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *loginChallengeRepository) CountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.LoginChallenge{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *loginChallengeRepository) RecordFailedAttempt(ctx context.Context, id string, maxAttempts int) error {
+	result := r.db.WithContext(ctx).Model(&entity.LoginChallenge{}).
+		Where("id = ? AND used_at IS NULL AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *loginChallengeRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entity.LoginChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *loginChallengeRepository) MarkPendingUsed(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.LoginChallenge{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
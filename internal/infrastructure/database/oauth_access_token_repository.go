@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type oauthAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthAccessTokenRepository(db *gorm.DB) repository.OAuthAccessTokenRepository {
+	return &oauthAccessTokenRepository{db: db}
+}
+
+func (r *oauthAccessTokenRepository) Create(ctx context.Context, token *entity.OAuthAccessToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *oauthAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.OAuthAccessToken, error) {
+	var token entity.OAuthAccessToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *oauthAccessTokenRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.OAuthAccessToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *oauthAccessTokenRepository) RevokeByCode(ctx context.Context, codeID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.OAuthAccessToken{}).
+		Where("code_id = ? AND revoked_at IS NULL", codeID).
+		Update("revoked_at", at).Error
+}
+
+func (r *oauthAccessTokenRepository) RevokeUser(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.OAuthAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type oauthAuthorizationCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthAuthorizationCodeRepository(db *gorm.DB) repository.OAuthAuthorizationCodeRepository {
+	return &oauthAuthorizationCodeRepository{db: db}
+}
+
+func (r *oauthAuthorizationCodeRepository) Create(ctx context.Context, code *entity.OAuthAuthorizationCode) error {
+	return r.db.WithContext(ctx).Create(code).Error
+}
+
+func (r *oauthAuthorizationCodeRepository) FindByHash(ctx context.Context, codeHash string) (*entity.OAuthAuthorizationCode, error) {
+	var code entity.OAuthAuthorizationCode
+	err := r.db.WithContext(ctx).Where("code_hash = ?", codeHash).First(&code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (r *oauthAuthorizationCodeRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entity.OAuthAuthorizationCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *oauthAuthorizationCodeRepository) RevokeUser(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.OAuthAuthorizationCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(db *gorm.DB) repository.OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+func (r *oauthClientRepository) Create(ctx context.Context, client *entity.OAuthClient) error {
+	return r.db.WithContext(ctx).Create(client).Error
+}
+
+func (r *oauthClientRepository) FindByID(ctx context.Context, id string) (*entity.OAuthClient, error) {
+	var client entity.OAuthClient
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&client).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *oauthClientRepository) FindByOwnerID(ctx context.Context, ownerID string) ([]*entity.OAuthClient, error) {
+	var clients []*entity.OAuthClient
+	err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("created_at").Find(&clients).Error
+	if err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (r *oauthClientRepository) Delete(ctx context.Context, ownerID, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Delete(&entity.OAuthClient{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) repository.OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, organization *entity.Organization, owner *entity.Membership) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Organization", "User").Create(owner).Error
+	})
+}
+
+func (r *organizationRepository) FindByID(ctx context.Context, id string) (*entity.Organization, error) {
+	var organization entity.Organization
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&organization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.Membership, error) {
+	var memberships []*entity.Membership
+	err := r.db.WithContext(ctx).Joins("Organization").
+		Where("memberships.user_id = ?", userID).
+		Order(`"Organization".name`).
+		Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+func (r *organizationRepository) FindMembership(ctx context.Context, organizationID, userID string) (*entity.Membership, error) {
+	var membership entity.Membership
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, organizationID string) ([]*entity.Membership, error) {
+	var memberships []*entity.Membership
+	err := r.db.WithContext(ctx).Joins("User").
+		Where("memberships.organization_id = ?", organizationID).
+		Order("memberships.created_at").
+		Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&entity.Membership{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) TransferOwnership(ctx context.Context, organizationID, ownerID, newOwnerID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setMembershipRole(tx, organizationID, ownerID, entity.MembershipOwner, entity.MembershipAdmin); err != nil {
+			return err
+		}
+		return setMembershipRole(tx, organizationID, newOwnerID, "", entity.MembershipOwner)
+	})
+}
+
+// setMembershipRole changes the role of the membership, only when its role is from unless
+// from is empty
+func setMembershipRole(tx *gorm.DB, organizationID, userID, from, to string) error {
+	query := tx.Model(&entity.Membership{}).Where("organization_id = ? AND user_id = ?", organizationID, userID)
+	if from != "" {
+		query = query.Where("role = ?", from)
+	}
+	result := query.Update("role", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) repository.PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+func (r *passwordHistoryRepository) Create(ctx context.Context, entry *entity.PasswordHistory) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *passwordHistoryRepository) ListRecent(ctx context.Context, userID string, limit int) ([]*entity.PasswordHistory, error) {
+	var entries []*entity.PasswordHistory
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userID string, keep int) error {
+	kept := r.db.Model(&entity.PasswordHistory{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(keep)
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND id NOT IN (?)", userID, kept).
+		Delete(&entity.PasswordHistory{}).Error
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type personalDataRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalDataRepository(db *gorm.DB) repository.PersonalDataRepository {
+	return &personalDataRepository{db: db}
+}
+
+func (r *personalDataRepository) Collect(ctx context.Context, userID string) (*entity.PersonalData, error) {
+	db := r.db.WithContext(ctx)
+
+	var user entity.User
+	if err := db.Preload("Profile").Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	data := &entity.PersonalData{User: &user, Profile: user.Profile}
+
+	// Rows of the user, oldest first
+	for _, rows := range []any{
+		&data.Sessions,
+		&data.APITokens,
+		&data.Identities,
+		&data.OAuthConsents,
+		&data.LoginChallenges,
+		&data.EmailChanges,
+		&data.UserNameChanges,
+		&data.AuditEvents,
+	} {
+		if err := db.Where("user_id = ?", userID).Order("created_at").Find(rows).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := db.Preload("Organization").Where("user_id = ?", userID).Order("created_at").Find(&data.Memberships).Error; err != nil {
+		return nil, err
+	}
+
+	roles, err := findUserRoles(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	data.Roles = roles
+	return data, nil
+}
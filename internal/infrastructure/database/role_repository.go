@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) repository.RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) SavePermissions(ctx context.Context, permissions []entity.Permission) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description"}),
+	}).Create(&permissions).Error
+}
+
+func (r *roleRepository) Save(ctx context.Context, role *entity.Role) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing entity.Role
+		err := tx.Where("name = ?", role.Name).First(&existing).Error
+		switch {
+		case err == nil:
+			role.ID = existing.ID
+			role.CreatedAt = existing.CreatedAt
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name string) (*entity.Role, error) {
+	var role entity.Role
+	err := r.db.WithContext(ctx).Preload("Permissions").Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]*entity.Role, error) {
+	var roles []*entity.Role
+	err := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Role, error) {
+	return findUserRoles(r.db.WithContext(ctx), userID)
+}
+
+// findUserRoles returns the roles assigned to a user, by name. The assignments are read on their
+// own, since only statements on tenant-scoped tables see the rows of the tenant.
+func findUserRoles(db *gorm.DB, userID string) ([]*entity.Role, error) {
+	var roleIDs []string
+	if err := db.Model(&entity.UserRole{}).Where("user_id = ?", userID).Pluck("role_id", &roleIDs).Error; err != nil {
+		return nil, err
+	}
+	roles := []*entity.Role{}
+	if len(roleIDs) == 0 {
+		return roles, nil
+	}
+	if err := db.Preload("Permissions").Where("id IN ?", roleIDs).Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) Assign(ctx context.Context, userID, roleID string) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.UserRole{UserID: userID, RoleID: roleID}).Error
+}
+
+func (r *roleRepository) Unassign(ctx context.Context, userID, roleID string) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&entity.UserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *roleRepository) UserHasPermission(ctx context.Context, userID, permission string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.UserRole{}).
+		Joins("JOIN role_permissions ON role_permissions.role_id = user_roles.role_id").
+		Where("user_roles.user_id = ? AND role_permissions.permission_name = ?", userID, permission).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"log"
+	"slices"
 
 	"github.com/google/uuid"
 
@@ -11,13 +12,24 @@ import (
 	"example.com/pkg/security"
 )
 
-func SeedDatabase(userRepo repository.UserRepository, hasher security.PasswordHasher) error {
-	ctx := context.Background()
+// SeedDatabase stores the built-in roles and the demo users of the tenant ctx acts for
+func SeedDatabase(
+	ctx context.Context,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	hasher security.PasswordHasher,
+) error {
+	// Roles are brought up to date on every run, so that new permissions reach seeded databases
+	roles, err := SeedRoles(ctx, roleRepo)
+	if err != nil {
+		return err
+	}
+	adminRole := roles[entity.RoleAdmin]
 
 	// Check if users already exist
-	if _, err := userRepo.FindByEmail(ctx, "admin@example.com"); err == nil {
-		log.Println("Database already seeded, skipping...")
-		return nil
+	if adminUser, err := userRepo.FindByEmail(ctx, "admin@example.com"); err == nil {
+		log.Println("Database already seeded, skipping users...")
+		return roleRepo.Assign(ctx, adminUser.ID, adminRole.ID)
 	}
 
 	// Create admin user
@@ -36,6 +48,9 @@ func SeedDatabase(userRepo repository.UserRepository, hasher security.PasswordHa
 	if err := userRepo.Create(ctx, adminUser); err != nil {
 		return err
 	}
+	if err := roleRepo.Assign(ctx, adminUser.ID, adminRole.ID); err != nil {
+		return err
+	}
 
 	// Create test user
 	hashedPassword, err = hasher.Hash("testpass123")
@@ -57,3 +72,45 @@ func SeedDatabase(userRepo repository.UserRepository, hasher security.PasswordHa
 	log.Println("Database seeded successfully")
 	return nil
 }
+
+// SeedRoles stores every permission and the built-in roles, returned by name: admin holds
+// every permission and support may look up users and read their roles
+func SeedRoles(ctx context.Context, roleRepo repository.RoleRepository) (map[string]*entity.Role, error) {
+	if err := roleRepo.SavePermissions(ctx, entity.Permissions); err != nil {
+		return nil, err
+	}
+
+	builtIn := []*entity.Role{
+		{
+			Name:        entity.RoleAdmin,
+			Description: "Full access, including role assignment",
+			Permissions: entity.Permissions,
+		},
+		{
+			Name:        "support",
+			Description: "Looks up users and their roles",
+			Permissions: permissions(entity.PermissionUsersRead, entity.PermissionRolesRead),
+		},
+	}
+
+	roles := make(map[string]*entity.Role, len(builtIn))
+	for _, role := range builtIn {
+		role.ID = uuid.NewString()
+		if err := roleRepo.Save(ctx, role); err != nil {
+			return nil, err
+		}
+		roles[role.Name] = role
+	}
+	return roles, nil
+}
+
+// permissions returns the permissions with the given names
+func permissions(names ...string) []entity.Permission {
+	var selected []entity.Permission
+	for _, permission := range entity.Permissions {
+		if slices.Contains(names, permission.Name) {
+			selected = append(selected, permission)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"example.com/pkg/tenant"
+)
+
+// tenantColumn holds the tenant of every row of a tenant-scoped table. A model is tenant-scoped
+// when it has a field stored in this column.
+const tenantColumn = "tenant_id"
+
+// tenantSetting is the Postgres setting the row-level security policies compare tenantColumn
+// against, set for the transaction of every statement acting for a tenant
+const tenantSetting = "app.tenant_id"
+
+// tenantPolicyValue is the tenant the row-level security policies let statements see. Once a
+// connection ran a transaction setting tenantSetting, the setting reads as empty afterwards.
+const tenantPolicyValue = "coalesce(current_setting('" + tenantSetting + "', true), '')"
+
+// ErrTenantRequired is returned for statements on tenant-scoped tables whose context acts
+// for no tenant, so that a forgotten context never reads or writes across tenants
+var ErrTenantRequired = errors.New("the statement acts for no tenant")
+
+// EnableTenancy scopes every statement on a tenant-scoped table to the tenant of its context:
+// queries, updates and deletes only match rows of that tenant and created rows are stamped
+// with it. Each statement also runs in a transaction setting tenantSetting, so that the
+// row-level security policies of Migrate hold even for SQL that bypasses these callbacks.
+func EnableTenancy(db *gorm.DB) error {
+	create, query, update, remove := db.Callback().Create(), db.Callback().Query(), db.Callback().Update(), db.Callback().Delete()
+
+	if err := create.Before("gorm:create").After("gorm:begin_transaction").Register("tenant:stamp", stampTenant); err != nil {
+		return err
+	}
+	if err := query.Before("gorm:query").Register("tenant:scope", scopeQuery); err != nil {
+		return err
+	}
+	commit := callbacks.CommitOrRollbackTransaction
+	if err := query.After("gorm:after_query").Register("tenant:commit_or_rollback_transaction", commit); err != nil {
+		return err
+	}
+	if err := update.Before("gorm:update").After("gorm:begin_transaction").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	return remove.Before("gorm:delete").After("gorm:begin_transaction").Register("tenant:scope", scopeTenant)
+}
+
+// stampTenant sets the tenant of the rows created. Upserts only update rows of the tenant.
+func stampTenant(db *gorm.DB) {
+	id, field, ok := statementTenant(db)
+	if !ok {
+		return
+	}
+
+	if err := setField(db, field, id); err != nil {
+		db.AddError(err)
+		return
+	}
+	if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
+		if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+			onConflict.Where.Exprs = append(onConflict.Where.Exprs, tenantCondition(db.Statement.Table, id))
+			db.Statement.AddClause(onConflict)
+		}
+	}
+	setTenantSetting(db, id)
+}
+
+// scopeQuery runs queries on tenant-scoped tables in a transaction, which unlike writes they
+// do not have by default, and restricts them to the rows of the tenant
+func scopeQuery(db *gorm.DB) {
+	id, field, ok := statementTenant(db)
+	if !ok {
+		return
+	}
+	if !db.DryRun {
+		callbacks.BeginTransaction(db)
+	}
+	scope(db, field, id)
+}
+
+// scopeTenant restricts updates and deletes to the rows of the tenant
+func scopeTenant(db *gorm.DB) {
+	if id, field, ok := statementTenant(db); ok {
+		scope(db, field, id)
+	}
+}
+
+// scope adds the condition on the tenant to the statement, keeping the tenant of records
+// saved as a whole
+func scope(db *gorm.DB, field *schema.Field, id string) {
+	if db.Statement.ReflectValue.Kind() == reflect.Struct && db.Statement.ReflectValue.Type() == db.Statement.Schema.ModelType {
+		if err := setField(db, field, id); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{tenantCondition(clause.CurrentTable, id)}})
+	setTenantSetting(db, id)
+}
+
+// statementTenant returns the tenant of a statement on a tenant-scoped table and its tenant
+// field, failing the statement when its context acts for no tenant
+func statementTenant(db *gorm.DB) (string, *schema.Field, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return "", nil, false
+	}
+	field := db.Statement.Schema.LookUpField(tenantColumn)
+	if field == nil {
+		return "", nil, false
+	}
+
+	id, ok := tenant.FromContext(db.Statement.Context)
+	if !ok {
+		db.AddError(fmt.Errorf("%w: %s", ErrTenantRequired, db.Statement.Table))
+		return "", nil, false
+	}
+	return id, field, true
+}
+
+func tenantCondition(table, id string) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: table, Name: tenantColumn}, Value: id}
+}
+
+// setField sets field to value in the records of the statement
+func setField(db *gorm.DB, field *schema.Field, value string) error {
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range rv.Len() {
+			if err := field.Set(db.Statement.Context, reflect.Indirect(rv.Index(i)), value); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return field.Set(db.Statement.Context, rv, value)
+	}
+	return nil
+}
+
+// setTenantSetting sets tenantSetting for the transaction of the statement. Outside a
+// transaction the setting does not last until the statement, and the row-level security
+// policies hide every row.
+func setTenantSetting(db *gorm.DB, id string) {
+	if db.DryRun || db.Error != nil {
+		return
+	}
+	if _, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, "SELECT set_config($1, $2, true)", tenantSetting, id); err != nil {
+		db.AddError(fmt.Errorf("set tenant: %w", err))
+	}
+}
+
+// enableRowLevelSecurity lets statements see and write only the rows of tenant-scoped tables
+// whose tenant is tenantSetting, or rows without a tenant when it is not set. The policies
+// also bind the owner of the tables, but not superusers, which bypass row-level security.
+func enableRowLevelSecurity(db *gorm.DB) error {
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		if stmt.Schema.LookUpField(tenantColumn) == nil {
+			continue
+		}
+
+		table := stmt.Quote(stmt.Table)
+		for _, sql := range []string{
+			"ALTER TABLE " + table + " ENABLE ROW LEVEL SECURITY",
+			"ALTER TABLE " + table + " FORCE ROW LEVEL SECURITY",
+			"DROP POLICY IF EXISTS tenant_isolation ON " + table,
+			"CREATE POLICY tenant_isolation ON " + table + " USING (" + tenantColumn + " = " + tenantPolicyValue + ")",
+		} {
+			if err := db.Exec(sql).Error; err != nil {
+				return fmt.Errorf("enable row-level security on %s: %w", stmt.Table, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+)
+
+type userNameChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewUserNameChangeRepository(db *gorm.DB) repository.UserNameChangeRepository {
+	return &userNameChangeRepository{db: db}
+}
+
+func (r *userNameChangeRepository) Create(ctx context.Context, change *entity.UserNameChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+func (r *userNameChangeRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.UserNameChange, error) {
+	var changes []*entity.UserNameChange
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *userNameChangeRepository) FindRedirect(
+	ctx context.Context, oldSkeleton string, now time.Time,
+) (*entity.UserNameChange, error) {
+	var change entity.UserNameChange
+	err := r.db.WithContext(ctx).
+		Where("old_skeleton = ? AND redirect_expires_at > ?", oldSkeleton, now).
+		Order("created_at DESC").
+		First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
@@ -2,7 +2,12 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 
 	"example.com/internal/domain/entity"
@@ -18,12 +23,16 @@ func NewUserRepository(db *gorm.DB) repository.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	err := r.db.WithContext(ctx).Create(user).Error
+	if isUniqueViolation(err) {
+		return gorm.ErrDuplicatedKey
+	}
+	return err
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := r.db.WithContext(ctx).Preload("Profile").Where("id = ?", id).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +48,19 @@ func (r *userRepository) FindByUserName(ctx context.Context, userName string) (*
 	return &user, nil
 }
 
+// userNameSkeleton is security.UsernameSkeleton of the user name in SQL. It lower-cases the name
+// too, for the names stored before user names were normalized.
+const userNameSkeleton = "translate(replace(replace(lower(user_name), 'rn', 'm'), 'vv', 'w'), '01i.-', 'oll__')"
+
+func (r *userRepository) FindByUserNameSkeleton(ctx context.Context, skeleton string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).Where(userNameSkeleton+" = ?", skeleton).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
 	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
@@ -57,10 +79,205 @@ func (r *userRepository) FindByUserNameOrEmail(ctx context.Context, identifier s
 	return &user, nil
 }
 
+// likeEscaper escapes the wildcards of LIKE patterns, whose escape character is \ by default
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// userSortKeys returns the value users are sorted by for each column of repository.UserSort
+var userSortKeys = map[repository.UserSort]func(*entity.User) any{
+	repository.UserSortCreatedAt: func(u *entity.User) any { return u.CreatedAt },
+	repository.UserSortUserName:  func(u *entity.User) any { return u.UserName },
+	repository.UserSortEmail:     func(u *entity.User) any { return u.Email },
+}
+
+func (r *userRepository) List(ctx context.Context, query repository.UserQuery) ([]*entity.User, error) {
+	sort := query.Sort
+	if sort == "" {
+		sort = repository.UserSortCreatedAt
+	}
+	sortKey, ok := userSortKeys[sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown user sort %q", sort)
+	}
+
+	db := r.db.WithContext(ctx).Model(&entity.User{})
+	if query.Deleted != nil {
+		db = whereNull(db.Unscoped(), "deleted_at", !*query.Deleted)
+	}
+	for _, word := range strings.Fields(strings.ToLower(query.Search)) {
+		pattern := "%" + likeEscaper.Replace(word) + "%"
+		db = db.Where("(lower(user_name) LIKE ? OR lower(email) LIKE ?)", pattern, pattern)
+	}
+	if query.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *query.CreatedBefore)
+	}
+	if query.Verified != nil {
+		db = whereNull(db, "email_verified_at", !*query.Verified)
+	}
+	if query.Locked != nil {
+		db = whereNull(db, "locked_at", !*query.Locked)
+	}
+
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sort, comparison), sortKey(query.After), query.After.ID)
+	}
+
+	var users []*entity.User
+	err := db.Order(string(sort) + " " + direction).Order("id " + direction).Limit(query.Limit).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// whereNull keeps the rows whose column is NULL, or the others when null is false
+func whereNull(db *gorm.DB, column string, null bool) *gorm.DB {
+	if null {
+		return db.Where(column + " IS NULL")
+	}
+	return db.Where(column + " IS NOT NULL")
+}
+
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+func (r *userRepository) ChangeEmail(ctx context.Context, id, from, to string, verifiedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND email = ?", id, from).
+		Updates(map[string]any{"email": to, "email_verified_at": verifiedAt})
+	if isUniqueViolation(result.Error) {
+		return gorm.ErrDuplicatedKey
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) ChangeUserName(ctx context.Context, id, from, to string) error {
+	result := r.db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND user_name = ?", id, from).
+		Update("user_name", to)
+	if isUniqueViolation(result.Error) {
+		return gorm.ErrDuplicatedKey
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// uniqueViolation is the SQLSTATE of Postgres for duplicate keys
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is a violation of a unique index, such as another
+// user holding the same email
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
 func (r *userRepository) Delete(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Delete(&entity.User{}, "id = ?", id).Error
 }
+
+func (r *userRepository) FindDeleted(ctx context.Context, id string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).Unscoped().Preload("Profile").Where("id = ? AND deleted_at IS NOT NULL", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Restore(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&entity.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL AND purged_at IS NULL", id).
+		Update("deleted_at", nil)
+	if isUniqueViolation(result.Error) {
+		return gorm.ErrDuplicatedKey
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]*entity.User, error) {
+	var users []*entity.User
+	err := r.db.WithContext(ctx).Unscoped().Preload("Profile").
+		Where("deleted_at < ? AND purged_at IS NULL", deletedBefore).
+		Order("deleted_at").Limit(limit).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// purgedModels lists the tables whose rows of a user are deleted when the user is purged. Other
+// rows referring to the user, like the OAuth2 clients they registered, only refer to an
+// anonymous user once the user is purged.
+var purgedModels = []any{
+	&entity.UserProfile{},
+	&entity.UserIdentity{},
+	&entity.APIToken{},
+	&entity.RefreshToken{},
+	&entity.LoginChallenge{},
+	&entity.PasswordHistory{},
+	&entity.OAuthConsent{},
+	&entity.OAuthAuthorizationCode{},
+	&entity.OAuthAccessToken{},
+	&entity.UserRole{},
+	&entity.Membership{},
+	&entity.AuditEvent{},
+	&entity.DataExport{},
+	&entity.AccountDeletion{},
+	&entity.EmailChange{},
+	&entity.UserNameChange{},
+}
+
+func (r *userRepository) Purge(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL AND purged_at IS NULL", id).First(&user).Error
+		if err != nil {
+			return err
+		}
+
+		// Invitations others sent to the user hold their email
+		if err := tx.Where("email = ?", user.Email).Delete(&entity.Invitation{}).Error; err != nil {
+			return err
+		}
+		for _, model := range purgedModels {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().Model(&user).Updates(map[string]any{
+			"user_name":           repository.PurgedUserName,
+			"email":               repository.PurgedEmail,
+			"password_hash":       "",
+			"last_login_at":       nil,
+			"password_changed_at": nil,
+			"email_verified_at":   nil,
+			"purged_at":           at,
+		}).Error
+	})
+}
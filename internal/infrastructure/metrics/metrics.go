@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// overflowLabel replaces label values once a counter reaches its series limit
+const overflowLabel = "other"
+
+// labelValueEscaper escapes label values the way the text exposition format requires. Other
+// characters, including those Go would quote, are written as they are: values come from
+// client-controlled headers, and an escape Prometheus does not know would fail every scrape.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Registry holds every counter exposed on the metrics endpoint
+type Registry struct {
+	counters map[string]*CounterVec
+	mu       sync.Mutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		counters: make(map[string]*CounterVec),
+	}
+}
+
+// CounterVec is a monotonically increasing counter partitioned by label values
+type CounterVec struct {
+	values    map[string]uint64
+	name      string
+	help      string
+	labels    []string
+	maxSeries int
+	mu        sync.Mutex
+}
+
+// CounterVec registers a counter, or returns the existing one with the same name.
+// maxSeries bounds the number of label combinations so that client-controlled values
+// (e.g. user agents) cannot grow memory without limit.
+func (r *Registry) CounterVec(name, help string, maxSeries int, labels ...string) *CounterVec {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if counter, ok := r.counters[name]; ok {
+		return counter
+	}
+
+	counter := &CounterVec{
+		values:    make(map[string]uint64),
+		name:      name,
+		help:      help,
+		labels:    labels,
+		maxSeries: maxSeries,
+	}
+	r.counters[name] = counter
+	return counter
+}
+
+// Inc increments the series identified by labelValues, given in the order of the labels
+func (c *CounterVec) Inc(labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.values[key]; !ok && c.maxSeries > 0 && len(c.values) >= c.maxSeries {
+		overflow := make([]string, len(labelValues))
+		for i := range overflow {
+			overflow[i] = overflowLabel
+		}
+		key = strings.Join(overflow, "\xff")
+	}
+	c.values[key]++
+}
+
+// Value returns the current count of the series identified by labelValues
+func (c *CounterVec) Value(labelValues ...string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values[strings.Join(labelValues, "\xff")]
+}
+
+// Handler serves every registered counter in the Prometheus text exposition format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.Write(w)
+	})
+}
+
+// Write renders every registered counter in the Prometheus text exposition format
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.counters))
+	for name := range r.counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	counters := make([]*CounterVec, 0, len(names))
+	for _, name := range names {
+		counters = append(counters, r.counters[name])
+	}
+	r.mu.Unlock()
+
+	for _, counter := range counters {
+		if err := counter.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		values := strings.Split(key, "\xff")
+		pairs := make([]string, len(c.labels))
+		for i, label := range c.labels {
+			pairs[i] = label + `="` + labelValueEscaper.Replace(values[i]) + `"`
+		}
+
+		if _, err := fmt.Fprintf(w, "%s{%s} %d\n", c.name, strings.Join(pairs, ","), c.values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
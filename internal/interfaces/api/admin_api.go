@@ -0,0 +1,244 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	adminservice "example.com/internal/domain/service/admin"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// userSorts maps the values of the sort parameter, without the "-" asking for descending
+// order, to the order users are listed in
+var userSorts = map[string]repository.UserSort{
+	"createdAt": repository.UserSortCreatedAt,
+	"username":  repository.UserSortUserName,
+	"email":     repository.UserSortEmail,
+}
+
+// AdminAPIHandler extends the generated AdminAPI with actual business logic
+type AdminAPIHandler struct {
+	*v1api.AdminAPI
+	listUseCase                 userusecase.ListUsersUseCase
+	lockUseCase                 userusecase.LockUserUseCase
+	unlockUseCase               userusecase.UnlockUserUseCase
+	requirePasswordResetUseCase userusecase.RequirePasswordResetUseCase
+	deleteUseCase               userusecase.DeleteUserUseCase
+	restoreUseCase              userusecase.RestoreUserUseCase
+	logger                      logger.Logger
+}
+
+// NewAdminAPIHandler creates a new admin handler that extends the generated API
+func NewAdminAPIHandler(
+	listUseCase userusecase.ListUsersUseCase,
+	lockUseCase userusecase.LockUserUseCase,
+	unlockUseCase userusecase.UnlockUserUseCase,
+	requirePasswordResetUseCase userusecase.RequirePasswordResetUseCase,
+	deleteUseCase userusecase.DeleteUserUseCase,
+	restoreUseCase userusecase.RestoreUserUseCase,
+	logger logger.Logger,
+) *AdminAPIHandler {
+	return &AdminAPIHandler{
+		AdminAPI:                    &v1api.AdminAPI{},
+		listUseCase:                 listUseCase,
+		lockUseCase:                 lockUseCase,
+		unlockUseCase:               unlockUseCase,
+		requirePasswordResetUseCase: requirePasswordResetUseCase,
+		deleteUseCase:               deleteUseCase,
+		restoreUseCase:              restoreUseCase,
+		logger:                      logger,
+	}
+}
+
+// ListUsers lists a page of the users matching the filters of the query
+func (h *AdminAPIHandler) ListUsers(c *gin.Context) {
+	query, err := userQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	page, err := h.listUseCase.Call(c.Request.Context(), query, c.Query("cursor"))
+	if err != nil {
+		h.adminError(c, err, "Failed to list users", "")
+		return
+	}
+
+	response := v1api.AdminUserList{Users: make([]v1api.AdminUser, len(page.Users)), NextCursor: page.Next}
+	for i, user := range page.Users {
+		response.Users[i] = toAdminUser(user)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// LockUser locks the user in the path
+func (h *AdminAPIHandler) LockUser(c *gin.Context) {
+	actorID, userID := middleware.CurrentUserID(c), c.Param("userId")
+	user, err := h.lockUseCase.Call(c.Request.Context(), actorID, userID)
+	if err != nil {
+		h.adminError(c, err, "Failed to lock user", userID)
+		return
+	}
+
+	h.logger.Info("User locked", "user_id", userID, "by", actorID)
+	c.JSON(http.StatusOK, toAdminUser(user))
+}
+
+// UnlockUser unlocks the user in the path
+func (h *AdminAPIHandler) UnlockUser(c *gin.Context) {
+	userID := c.Param("userId")
+	user, err := h.unlockUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.adminError(c, err, "Failed to unlock user", userID)
+		return
+	}
+
+	h.logger.Info("User unlocked", "user_id", userID, "by", middleware.CurrentUserID(c))
+	c.JSON(http.StatusOK, toAdminUser(user))
+}
+
+// RequirePasswordReset makes the user in the path change their password
+func (h *AdminAPIHandler) RequirePasswordReset(c *gin.Context) {
+	userID := c.Param("userId")
+	user, err := h.requirePasswordResetUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.adminError(c, err, "Failed to require password reset", userID)
+		return
+	}
+
+	h.logger.Info("Password reset required", "user_id", userID, "by", middleware.CurrentUserID(c))
+	c.JSON(http.StatusOK, toAdminUser(user))
+}
+
+// DeleteUser soft-deletes the user in the path
+func (h *AdminAPIHandler) DeleteUser(c *gin.Context) {
+	actorID, userID := middleware.CurrentUserID(c), c.Param("userId")
+	if err := h.deleteUseCase.Call(c.Request.Context(), actorID, userID); err != nil {
+		h.adminError(c, err, "Failed to delete user", userID)
+		return
+	}
+
+	h.logger.Info("User deleted", "user_id", userID, "by", actorID)
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreUser undoes the deletion of the user in the path
+func (h *AdminAPIHandler) RestoreUser(c *gin.Context) {
+	userID := c.Param("userId")
+	user, err := h.restoreUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.adminError(c, err, "Failed to restore user", userID)
+		return
+	}
+
+	h.logger.Info("User restored", "user_id", userID, "by", middleware.CurrentUserID(c))
+	c.JSON(http.StatusOK, toAdminUser(user))
+}
+
+func (h *AdminAPIHandler) adminError(c *gin.Context, err error, message, userID string) {
+	switch {
+	case errors.Is(err, adminservice.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, v1api.Error{Error: "User not found"})
+	case errors.Is(err, adminservice.ErrSelf):
+		c.JSON(http.StatusConflict, v1api.Error{Error: "Cannot manage own account", Message: err.Error()})
+	case errors.Is(err, adminservice.ErrUserTaken):
+		c.JSON(http.StatusConflict, v1api.Error{Error: "User name or email taken", Message: err.Error()})
+	case errors.Is(err, adminservice.ErrUserPurged):
+		c.JSON(http.StatusGone, v1api.Error{Error: "User purged", Message: err.Error()})
+	case errors.Is(err, adminservice.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid cursor", Message: err.Error()})
+	default:
+		h.logger.Error(message, "error", err.Error(), "user_id", userID)
+		c.JSON(http.StatusInternalServerError, v1api.Error{Error: "Internal server error"})
+	}
+}
+
+// userQuery reads the filters and order of ListUsers from the query parameters
+func userQuery(c *gin.Context) (repository.UserQuery, error) {
+	query := repository.UserQuery{Search: c.Query("q")}
+
+	name, descending := strings.CutPrefix(c.DefaultQuery("sort", "createdAt"), "-")
+	sort, ok := userSorts[name]
+	if !ok {
+		return query, fmt.Errorf("unknown sort %q", name)
+	}
+	query.Sort, query.Descending = sort, descending
+
+	var err error
+	if query.CreatedAfter, err = timeParam(c, "createdAfter"); err != nil {
+		return query, err
+	}
+	if query.CreatedBefore, err = timeParam(c, "createdBefore"); err != nil {
+		return query, err
+	}
+	if query.Verified, err = boolParam(c, "verified"); err != nil {
+		return query, err
+	}
+	if query.Locked, err = boolParam(c, "locked"); err != nil {
+		return query, err
+	}
+	if query.Deleted, err = boolParam(c, "deleted"); err != nil {
+		return query, err
+	}
+	if limit, ok := c.GetQuery("limit"); ok {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("limit: %w", err)
+		}
+	}
+	return query, nil
+}
+
+// timeParam returns the RFC 3339 time of the query parameter, or nil when it is missing
+func timeParam(c *gin.Context, name string) (*time.Time, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &t, nil
+}
+
+// boolParam returns the boolean of the query parameter, or nil when it is missing
+func boolParam(c *gin.Context, name string) (*bool, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &b, nil
+}
+
+func toAdminUser(user *entity.User) v1api.AdminUser {
+	response := v1api.AdminUser{
+		Id:                    user.ID,
+		Username:              user.UserName,
+		Email:                 user.Email,
+		CreatedAt:             user.CreatedAt,
+		LastLoginAt:           user.LastLoginAt,
+		EmailVerifiedAt:       user.EmailVerifiedAt,
+		LockedAt:              user.LockedAt,
+		PurgedAt:              user.PurgedAt,
+		PasswordResetRequired: user.PasswordResetRequired,
+	}
+	if user.DeletedAt.Valid {
+		response.DeletedAt = &user.DeletedAt.Time
+	}
+	return response
+}
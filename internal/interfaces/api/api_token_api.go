@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// APITokenAPIHandler extends the generated APITokensAPI with actual business logic
+type APITokenAPIHandler struct {
+	*authapi.APITokensAPI
+	createUseCase authusecase.CreateAPITokenUseCase
+	listUseCase   authusecase.ListAPITokensUseCase
+	revokeUseCase authusecase.RevokeAPITokenUseCase
+	logger        logger.Logger
+}
+
+// NewAPITokenAPIHandler creates a new API token handler that extends the generated API
+func NewAPITokenAPIHandler(
+	createUseCase authusecase.CreateAPITokenUseCase,
+	listUseCase authusecase.ListAPITokensUseCase,
+	revokeUseCase authusecase.RevokeAPITokenUseCase,
+	logger logger.Logger,
+) *APITokenAPIHandler {
+	return &APITokenAPIHandler{
+		APITokensAPI:  &authapi.APITokensAPI{},
+		createUseCase: createUseCase,
+		listUseCase:   listUseCase,
+		revokeUseCase: revokeUseCase,
+		logger:        logger,
+	}
+}
+
+// CreateApiToken issues a token for the current user and returns it once
+func (h *APITokenAPIHandler) CreateApiToken(c *gin.Context) {
+	var req authapi.CreateApiTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid API token request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, authapi.Error{Error: "Invalid request format"})
+		return
+	}
+
+	scopes := make([]string, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	userID := middleware.CurrentUserID(c)
+	token, plain, err := h.createUseCase.Call(c.Request.Context(), userID, req.Name, scopes, req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, apitokenservice.ErrUnknownScope),
+			errors.Is(err, apitokenservice.ErrNoScopes),
+			errors.Is(err, apitokenservice.ErrExpiryInPast):
+			c.JSON(http.StatusBadRequest, authapi.Error{Error: "Invalid API token request", Message: err.Error()})
+		default:
+			h.logger.Error("Failed to create API token", "error", err.Error(), "user_id", userID)
+			c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		}
+		return
+	}
+
+	h.logger.Info("API token created", "user_id", userID, "token_id", token.ID, "scopes", token.Scopes)
+	c.JSON(http.StatusCreated, authapi.CreateApiTokenResponse{
+		Token:    plain,
+		ApiToken: toAPIToken(token),
+	})
+}
+
+// ListApiTokens lists every token of the current user without revealing the tokens
+func (h *APITokenAPIHandler) ListApiTokens(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	tokens, err := h.listUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list API tokens", "error", err.Error(), "user_id", userID)
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		return
+	}
+
+	response := authapi.ApiTokenList{Tokens: make([]authapi.ApiToken, len(tokens))}
+	for i, token := range tokens {
+		response.Tokens[i] = toAPIToken(token)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeApiToken revokes one of the current user's tokens
+func (h *APITokenAPIHandler) RevokeApiToken(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	tokenID := c.Param("tokenId")
+
+	if err := h.revokeUseCase.Call(c.Request.Context(), userID, tokenID); err != nil {
+		if errors.Is(err, apitokenservice.ErrTokenNotFound) {
+			c.JSON(http.StatusNotFound, authapi.Error{Error: "API token not found"})
+		} else {
+			h.logger.Error("Failed to revoke API token", "error", err.Error(), "user_id", userID)
+			c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		}
+		return
+	}
+
+	h.logger.Info("API token revoked", "user_id", userID, "token_id", tokenID)
+	c.Status(http.StatusNoContent)
+}
+
+func toAPIToken(token *entity.APIToken) authapi.ApiToken {
+	scopes := make([]authapi.ApiTokenScope, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = authapi.ApiTokenScope(scope)
+	}
+
+	return authapi.ApiToken{
+		Id:         token.ID,
+		Name:       token.Name,
+		Hint:       token.Hint,
+		Scopes:     scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+	}
+}
@@ -1,13 +1,21 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	tokenservice "example.com/internal/domain/service/token"
+	usernameservice "example.com/internal/domain/service/username"
 	authusecase "example.com/internal/domain/usecase/auth"
 	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
 )
 
 // AuthAPIHandler extends the generated AuthUserAPI with actual business logic
@@ -15,6 +23,7 @@ type AuthAPIHandler struct {
 	*authapi.AuthUserAPI
 	signupUseCase authusecase.SignupUseCase
 	loginUseCase  authusecase.LoginUseCase
+	completer     loginCompleter
 	logger        logger.Logger
 }
 
@@ -22,12 +31,14 @@ type AuthAPIHandler struct {
 func NewAuthAPIHandler(
 	signupUseCase authusecase.SignupUseCase,
 	loginUseCase authusecase.LoginUseCase,
+	issueTokensUseCase authusecase.IssueTokensUseCase,
 	logger logger.Logger,
 ) *AuthAPIHandler {
 	return &AuthAPIHandler{
 		AuthUserAPI:   &authapi.AuthUserAPI{},
 		signupUseCase: signupUseCase,
 		loginUseCase:  loginUseCase,
+		completer:     loginCompleter{issueTokensUseCase: issueTokensUseCase, logger: logger, method: "password"},
 		logger:        logger,
 	}
 }
@@ -47,12 +58,54 @@ func (h *AuthAPIHandler) UserLogin(c *gin.Context) {
 
 		if err.Error() == "invalid credentials" {
 			c.JSON(http.StatusUnauthorized, authapi.Error{Message: "Invalid credentials"})
+		} else if errors.Is(err, authservice.ErrAccountLocked) {
+			c.JSON(http.StatusForbidden, authapi.Error{Error: "Account locked", Message: "The account was locked by an administrator"})
+		} else if errors.Is(err, authservice.ErrPasswordResetRequired) {
+			c.JSON(http.StatusForbidden, authapi.Error{
+				Error:   "Password reset required",
+				Message: "Log in without the password, such as with a login code, and change it",
+			})
 		} else {
 			c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
 		}
 		return
 	}
 
+	h.completer.complete(c, user, req.IssueTokens)
+}
+
+// loginCompleter establishes the login of a user who proved their identity, shared by every
+// handler that logs users in so that they all start sessions and issue tokens alike
+type loginCompleter struct {
+	issueTokensUseCase authusecase.IssueTokensUseCase
+	logger             logger.Logger
+	// method names how users log in with the handler in their audit events
+	method string
+}
+
+// complete issues tokens when asked to or starts a cookie session, and writes the login response
+func (l loginCompleter) complete(c *gin.Context, user *entity.User, issueTokens bool) {
+	// Token logins are for clients without cookies, so no session is started for them
+	var pair *entity.TokenPair
+	if issueTokens {
+		var err error
+		pair, err = l.issueTokens(c.Request.Context(), user.ID)
+		if err != nil {
+			if errors.Is(err, tokenservice.ErrTokensDisabled) {
+				c.JSON(http.StatusBadRequest, authapi.Error{Error: "Token login is not enabled"})
+			} else {
+				l.logger.Error("Failed to issue tokens", "error", err.Error(), "user_id", user.ID)
+				c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+			}
+			return
+		}
+	} else if err := middleware.StartSession(c, user.ID); err != nil {
+		l.logger.Error("Failed to start session", "error", err.Error(), "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+		return
+	}
+	middleware.LoggedIn(c, user.ID, l.method)
+
 	// Convert domain model to API response
 	apiUser := authapi.User{
 		Id:        user.ID,
@@ -69,11 +122,25 @@ func (h *AuthAPIHandler) UserLogin(c *gin.Context) {
 		User:    apiUser,
 		Message: "Login successful",
 	}
+	if pair != nil {
+		response.AccessToken = pair.AccessToken
+		response.RefreshToken = pair.RefreshToken
+		response.TokenType = tokenType
+		response.ExpiresIn = expiresIn(pair)
+	}
 
-	h.logger.Info("User logged in successfully", "user_id", user.ID)
+	l.logger.Info("User logged in successfully", "user_id", user.ID)
 	c.JSON(http.StatusOK, response)
 }
 
+// issueTokens starts a token family, treating a handler built without token support as disabled
+func (l loginCompleter) issueTokens(ctx context.Context, userID string) (*entity.TokenPair, error) {
+	if l.issueTokensUseCase == nil {
+		return nil, tokenservice.ErrTokensDisabled
+	}
+	return l.issueTokensUseCase.Call(ctx, userID)
+}
+
 // UserSignup handles user registration with proper business logic
 func (h *AuthAPIHandler) UserSignup(c *gin.Context) {
 	var req authapi.SignupRequest
@@ -89,6 +156,12 @@ func (h *AuthAPIHandler) UserSignup(c *gin.Context) {
 
 		if err.Error() == "user already exists" {
 			c.JSON(http.StatusConflict, authapi.Error{Message: "User already exists"})
+		} else if errors.Is(err, usernameservice.ErrUsernameTaken) {
+			c.JSON(http.StatusConflict, authapi.Error{Error: "Username taken", Message: err.Error()})
+		} else if errors.Is(err, security.ErrWeakPassword) {
+			c.JSON(http.StatusBadRequest, weakPasswordError(err, "password"))
+		} else if errors.Is(err, security.ErrInvalidUsername) {
+			c.JSON(http.StatusBadRequest, invalidUsernameError(err, "username"))
 		} else {
 			c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
 		}
@@ -115,3 +188,15 @@ func (h *AuthAPIHandler) UserSignup(c *gin.Context) {
 	h.logger.Info("User created successfully", "user_id", user.ID, "email", user.Email)
 	c.JSON(http.StatusCreated, response)
 }
+
+// invalidUsernameError lists the rules a username broke as details of the given body field
+func invalidUsernameError(err error, field string) authapi.Error {
+	response := authapi.Error{Error: "Username does not meet the policy"}
+	var policyErr *security.UsernamePolicyError
+	if errors.As(err, &policyErr) {
+		for _, violation := range policyErr.Violations {
+			response.Details = append(response.Details, authapi.FieldError{Field: field, In: "body", Message: violation})
+		}
+	}
+	return response
+}
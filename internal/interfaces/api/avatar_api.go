@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	avatarservice "example.com/internal/domain/service/avatar"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/pkg/security"
+	"example.com/pkg/storage"
+)
+
+// AvatarAPIHandler extends the generated AvatarsAPI with actual business logic
+type AvatarAPIHandler struct {
+	*authapi.AvatarsAPI
+	openUseCase userusecase.OpenAvatarUseCase
+	avatarURLs  *security.URLSigner
+	logger      logger.Logger
+}
+
+// NewAvatarAPIHandler creates a new avatar handler that extends the generated API
+func NewAvatarAPIHandler(
+	openUseCase userusecase.OpenAvatarUseCase,
+	avatarURLs *security.URLSigner,
+	logger logger.Logger,
+) *AvatarAPIHandler {
+	return &AvatarAPIHandler{
+		AvatarsAPI:  &authapi.AvatarsAPI{},
+		openUseCase: openUseCase,
+		avatarURLs:  avatarURLs,
+		logger:      logger,
+	}
+}
+
+// GetAvatar serves a thumbnail to anyone holding a URL signed by ProfileAPIHandler. Thumbnails
+// are named by their content, so they are cached as immutable until the signature expires.
+func (h *AvatarAPIHandler) GetAvatar(c *gin.Context) {
+	query := c.Request.URL.Query()
+	if !h.avatarURLs.Verify(c.Request.URL.Path, query) {
+		c.JSON(http.StatusForbidden, authapi.Error{Error: "Invalid signature", Message: "The URL is not signed or has expired"})
+		return
+	}
+
+	file := c.Param("file")
+	etag := strconv.Quote(file)
+	maxAge := int(time.Until(h.avatarURLs.Expires(query)).Seconds())
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(max(maxAge, 0))+", immutable")
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	blob, err := h.openUseCase.Call(c.Request.Context(), "avatars/"+c.Param("userId")+"/"+file)
+	if errors.Is(err, avatarservice.ErrNotFound) {
+		c.JSON(http.StatusNotFound, authapi.Error{Error: "Not found", Message: "The avatar does not exist anymore"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to open avatar", "error", err.Error(), "file", file)
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error", Message: "Internal server error"})
+		return
+	}
+	defer blob.Body.Close()
+
+	c.DataFromReader(http.StatusOK, blob.Size, storage.ContentType(file), blob.Body, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	authapi "example.com/gen/openapi/auth/go"
+	emailservice "example.com/internal/domain/service/email"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// EmailAPIHandler extends the generated AuthEmailAPI with actual business logic
+type EmailAPIHandler struct {
+	*authapi.AuthEmailAPI
+	requestUseCase authusecase.RequestEmailChangeUseCase
+	confirmUseCase authusecase.ConfirmEmailChangeUseCase
+	revertUseCase  authusecase.RevertEmailChangeUseCase
+	logger         logger.Logger
+}
+
+// NewEmailAPIHandler creates a new email change handler
+func NewEmailAPIHandler(
+	requestUseCase authusecase.RequestEmailChangeUseCase,
+	confirmUseCase authusecase.ConfirmEmailChangeUseCase,
+	revertUseCase authusecase.RevertEmailChangeUseCase,
+	logger logger.Logger,
+) *EmailAPIHandler {
+	return &EmailAPIHandler{
+		AuthEmailAPI:   &authapi.AuthEmailAPI{},
+		requestUseCase: requestUseCase,
+		confirmUseCase: confirmUseCase,
+		revertUseCase:  revertUseCase,
+		logger:         logger,
+	}
+}
+
+// RequestEmailChange emails links to confirm and revert a new email of the current user
+func (h *EmailAPIHandler) RequestEmailChange(c *gin.Context) {
+	var req authapi.EmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, authapi.Error{Message: "Invalid request format"})
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	change, err := h.requestUseCase.Call(c.Request.Context(), userID, req.CurrentPassword, req.NewEmail)
+	if err != nil {
+		switch {
+		case errors.Is(err, emailservice.ErrIncorrectPassword):
+			c.JSON(http.StatusUnauthorized, authapi.Error{Error: "Incorrect current password"})
+		case errors.Is(err, emailservice.ErrSameEmail):
+			c.JSON(http.StatusBadRequest, authapi.Error{
+				Error:   "Email unchanged",
+				Details: []authapi.FieldError{{Field: "newEmail", In: "body", Message: "must differ from your current email"}},
+			})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusUnauthorized, authapi.Error{Error: "Authentication required"})
+		default:
+			h.emailError(c, err, "Failed to request email change")
+		}
+		return
+	}
+
+	h.logger.Info("Email change requested", "user_id", userID)
+	c.JSON(http.StatusAccepted, authapi.EmailChangeResponse{
+		Email:        change.OldEmail,
+		PendingEmail: change.NewEmail,
+		ExpiresAt:    &change.ExpiresAt,
+		Message:      "Confirm the new email with the link sent to it",
+	})
+}
+
+// ConfirmEmailChange gives the user the new email the token of the body was sent to
+func (h *EmailAPIHandler) ConfirmEmailChange(c *gin.Context) {
+	var req authapi.EmailTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, authapi.Error{Message: "Invalid request format"})
+		return
+	}
+
+	user, err := h.confirmUseCase.Call(c.Request.Context(), req.Token)
+	if err != nil {
+		h.emailError(c, err, "Failed to confirm email change")
+		return
+	}
+
+	h.logger.Info("Email changed", "user_id", user.ID)
+	c.JSON(http.StatusOK, authapi.EmailChangeResponse{Email: user.Email, Message: "Email changed"})
+}
+
+// RevertEmailChange gives the user back the old email the token of the body was sent to
+func (h *EmailAPIHandler) RevertEmailChange(c *gin.Context) {
+	var req authapi.EmailTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, authapi.Error{Message: "Invalid request format"})
+		return
+	}
+
+	user, err := h.revertUseCase.Call(c.Request.Context(), req.Token)
+	if err != nil {
+		h.emailError(c, err, "Failed to revert email change")
+		return
+	}
+
+	h.logger.Info("Email change reverted", "user_id", user.ID)
+	c.JSON(http.StatusOK, authapi.EmailChangeResponse{
+		Email:   user.Email,
+		Message: "Email restored and every session ended; change your password next",
+	})
+}
+
+func (h *EmailAPIHandler) emailError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, emailservice.ErrInvalidToken):
+		c.JSON(http.StatusBadRequest, authapi.Error{Error: "Invalid token", Message: err.Error()})
+	case errors.Is(err, emailservice.ErrEmailTaken):
+		c.JSON(http.StatusConflict, authapi.Error{Error: "Email taken", Message: err.Error()})
+	default:
+		h.logger.Error(message, "error", err.Error())
+		c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+	}
+}
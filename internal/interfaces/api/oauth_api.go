@@ -0,0 +1,404 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// oauthPendingKey holds the authorization request awaiting consent in the session
+const oauthPendingKey = "oauth_authorization"
+
+// OAuth 2.0 error codes (RFC 6749 sections 4.1.2.1 and 5.2)
+const (
+	oauthErrorInvalidRequest          = "invalid_request"
+	oauthErrorInvalidClient           = "invalid_client"
+	oauthErrorInvalidGrant            = "invalid_grant"
+	oauthErrorUnauthorizedClient      = "unauthorized_client"
+	oauthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	oauthErrorUnsupportedResponseType = "unsupported_response_type"
+	oauthErrorInvalidScope            = "invalid_scope"
+	oauthErrorAccessDenied            = "access_denied"
+	oauthErrorServer                  = "server_error"
+)
+
+// pendingAuthorization is an authorization request waiting for the consent of UserID
+type pendingAuthorization struct {
+	UserID  string                            `json:"user_id"`
+	Request oauthservice.AuthorizationRequest `json:"request"`
+}
+
+// OAuthServerSettings locate the authorization server and the frontend pages it sends browsers to
+type OAuthServerSettings struct {
+	Issuer     string
+	BaseURL    string
+	LoginURL   string
+	ConsentURL string
+	// SigningAlgorithms sign ID tokens; without any the openid scope is unavailable
+	SigningAlgorithms []string
+}
+
+// OAuthAPIHandler extends the generated OAuth2API with actual business logic
+type OAuthAPIHandler struct {
+	*authapi.OAuth2API
+	authorizeUseCase  authusecase.AuthorizeUseCase
+	consentUseCase    authusecase.ConsentUseCase
+	tokenUseCase      authusecase.OAuthTokenUseCase
+	introspectUseCase authusecase.IntrospectOAuthTokenUseCase
+	revokeUseCase     authusecase.RevokeOAuthTokenUseCase
+	logger            logger.Logger
+	settings          OAuthServerSettings
+}
+
+// NewOAuthAPIHandler creates a new authorization server handler that extends the generated API
+func NewOAuthAPIHandler(
+	authorizeUseCase authusecase.AuthorizeUseCase,
+	consentUseCase authusecase.ConsentUseCase,
+	tokenUseCase authusecase.OAuthTokenUseCase,
+	introspectUseCase authusecase.IntrospectOAuthTokenUseCase,
+	revokeUseCase authusecase.RevokeOAuthTokenUseCase,
+	logger logger.Logger,
+	settings OAuthServerSettings,
+) *OAuthAPIHandler {
+	return &OAuthAPIHandler{
+		OAuth2API:         &authapi.OAuth2API{},
+		authorizeUseCase:  authorizeUseCase,
+		consentUseCase:    consentUseCase,
+		tokenUseCase:      tokenUseCase,
+		introspectUseCase: introspectUseCase,
+		revokeUseCase:     revokeUseCase,
+		logger:            logger,
+		settings:          settings,
+	}
+}
+
+// GetOpenIdConfiguration publishes the discovery document of the authorization server
+func (h *OAuthAPIHandler) GetOpenIdConfiguration(c *gin.Context) {
+	scopes := []string{entity.OAuthScopeProfile, entity.OAuthScopeEmail}
+	response := authapi.OpenIdConfiguration{
+		Issuer:                            h.settings.Issuer,
+		AuthorizationEndpoint:             h.settings.BaseURL + "/oauth2/authorize",
+		TokenEndpoint:                     h.settings.BaseURL + "/oauth2/token",
+		IntrospectionEndpoint:             h.settings.BaseURL + "/oauth2/introspect",
+		RevocationEndpoint:                h.settings.BaseURL + "/oauth2/revoke",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{entity.GrantTypeAuthorizationCode, entity.GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+	}
+	if len(h.settings.SigningAlgorithms) > 0 {
+		scopes = append([]string{entity.OAuthScopeOpenID}, scopes...)
+		response.JwksUri = h.settings.BaseURL + "/.well-known/jwks.json"
+		response.IdTokenSigningAlgValuesSupported = h.settings.SigningAlgorithms
+	}
+	response.ScopesSupported = scopes
+
+	c.JSON(http.StatusOK, response)
+}
+
+// OauthAuthorize validates an authorization request and sends the browser on to the login
+// page, the consent page or back to the client with a code
+func (h *OAuthAPIHandler) OauthAuthorize(c *gin.Context) {
+	req := &oauthservice.AuthorizationRequest{
+		ClientID:            c.Query("client_id"),
+		RedirectURI:         c.Query("redirect_uri"),
+		ResponseType:        c.Query("response_type"),
+		State:               c.Query("state"),
+		Nonce:               c.Query("nonce"),
+		CodeChallenge:       c.Query("code_challenge"),
+		CodeChallengeMethod: c.Query("code_challenge_method"),
+		Scopes:              strings.Fields(c.Query("scope")),
+	}
+
+	// Only a browser session of this tenant can authorize a client, never a bearer token
+	session := sessions.Default(c)
+	userID := middleware.SessionUserID(c)
+
+	client, code, err := h.authorizeUseCase.Call(c.Request.Context(), userID, req)
+	switch {
+	case err == nil:
+		h.logger.Info("Authorization code issued", "user_id", userID, "client_id", client.ID)
+		c.Redirect(http.StatusFound, withQuery(req.RedirectURI, "code", code, "state", req.State))
+	case errors.Is(err, oauthservice.ErrClientNotFound):
+		c.JSON(http.StatusBadRequest, authapi.OAuthError{Error: oauthErrorInvalidClient, ErrorDescription: err.Error()})
+	case errors.Is(err, oauthservice.ErrInvalidRedirectURI):
+		c.JSON(http.StatusBadRequest, authapi.OAuthError{Error: oauthErrorInvalidRequest, ErrorDescription: err.Error()})
+	case client == nil:
+		h.logger.Error("Failed to authorize", "error", err.Error(), "client_id", req.ClientID)
+		c.JSON(http.StatusInternalServerError, authapi.OAuthError{Error: oauthErrorServer})
+	case errors.Is(err, oauthservice.ErrLoginRequired):
+		c.Redirect(http.StatusFound, withQuery(h.settings.LoginURL, "return_to", h.settings.BaseURL+c.Request.URL.RequestURI()))
+	case errors.Is(err, oauthservice.ErrConsentRequired):
+		if err := h.savePending(session, pendingAuthorization{UserID: userID, Request: *req}); err != nil {
+			h.logger.Error("Failed to save authorization request", "error", err.Error())
+			c.Redirect(http.StatusFound, authorizationError(req, oauthErrorServer, ""))
+			return
+		}
+		c.Redirect(http.StatusFound, h.settings.ConsentURL)
+	default:
+		c.Redirect(http.StatusFound, authorizationError(req, h.authorizeError(err, client.ID), err.Error()))
+	}
+}
+
+// GetOAuthConsent describes the authorization request awaiting the consent of the current user
+func (h *OAuthAPIHandler) GetOAuthConsent(c *gin.Context) {
+	pending, ok := h.pending(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, authapi.Error{Error: "No authorization request awaiting consent"})
+		return
+	}
+
+	client, err := h.consentUseCase.Describe(c.Request.Context(), &pending.Request)
+	if err != nil {
+		if errors.Is(err, oauthservice.ErrClientNotFound) || errors.Is(err, oauthservice.ErrInvalidRedirectURI) {
+			c.JSON(http.StatusNotFound, authapi.Error{Error: "No authorization request awaiting consent"})
+		} else {
+			h.logger.Error("Failed to describe authorization request", "error", err.Error())
+			c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, authapi.OAuthConsent{
+		ClientId:   client.ID,
+		ClientName: client.Name,
+		Scopes:     toAPIOAuthScopes(pending.Request.Scopes),
+	})
+}
+
+// SubmitOAuthConsent records the decision of the current user on the pending request. The
+// request is consumed either way, so a decision cannot be submitted twice.
+func (h *OAuthAPIHandler) SubmitOAuthConsent(c *gin.Context) {
+	var decision authapi.OAuthConsentDecision
+	if err := c.ShouldBindJSON(&decision); err != nil {
+		h.logger.Warn("Invalid consent request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, authapi.Error{Error: "Invalid request format"})
+		return
+	}
+
+	pending, ok := h.pending(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, authapi.Error{Error: "No authorization request awaiting consent"})
+		return
+	}
+	session := sessions.Default(c)
+	session.Delete(oauthPendingKey)
+	if err := session.Save(); err != nil {
+		h.logger.Error("Failed to save session", "error", err.Error())
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		return
+	}
+
+	req := &pending.Request
+	code, err := h.consentUseCase.Call(c.Request.Context(), pending.UserID, req, decision.Approve)
+	switch {
+	case err == nil:
+		h.logger.Info("OAuth consent granted", "user_id", pending.UserID, "client_id", req.ClientID, "scopes", req.Scopes)
+		c.JSON(http.StatusOK, authapi.OAuthConsentResult{RedirectTo: withQuery(req.RedirectURI, "code", code, "state", req.State)})
+	case errors.Is(err, oauthservice.ErrClientNotFound), errors.Is(err, oauthservice.ErrInvalidRedirectURI):
+		c.JSON(http.StatusNotFound, authapi.Error{Error: "No authorization request awaiting consent"})
+	default:
+		c.JSON(http.StatusOK, authapi.OAuthConsentResult{
+			RedirectTo: authorizationError(req, h.authorizeError(err, req.ClientID), err.Error()),
+		})
+	}
+}
+
+// OauthToken serves the authorization code and client credentials grants
+func (h *OAuthAPIHandler) OauthToken(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	clientID, clientSecret, ok := clientCredentials(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, authapi.OAuthError{Error: oauthErrorInvalidRequest, ErrorDescription: "client_id is required"})
+		return
+	}
+
+	tokens, err := h.tokenUseCase.Call(c.Request.Context(), clientID, clientSecret, oauthservice.TokenRequest{
+		GrantType:    c.PostForm("grant_type"),
+		Code:         c.PostForm("code"),
+		RedirectURI:  c.PostForm("redirect_uri"),
+		CodeVerifier: c.PostForm("code_verifier"),
+		Scopes:       strings.Fields(c.PostForm("scope")),
+	})
+	if err != nil {
+		h.tokenError(c, err, clientID)
+		return
+	}
+
+	c.JSON(http.StatusOK, authapi.OAuthTokenResponse{
+		AccessToken: tokens.AccessToken,
+		TokenType:   tokenType,
+		ExpiresIn:   int32(time.Until(tokens.ExpiresAt).Round(time.Second).Seconds()),
+		Scope:       strings.Join(tokens.Scopes, " "),
+		IdToken:     tokens.IDToken,
+	})
+}
+
+// OauthIntrospect describes an access token to a resource server
+func (h *OAuthAPIHandler) OauthIntrospect(c *gin.Context) {
+	clientID, clientSecret, ok := clientCredentials(c)
+	if !ok {
+		h.tokenError(c, oauthservice.ErrInvalidClient, "")
+		return
+	}
+
+	introspection, err := h.introspectUseCase.Call(c.Request.Context(), clientID, clientSecret, c.PostForm("token"))
+	if err != nil {
+		if errors.Is(err, oauthservice.ErrUnauthorizedClient) {
+			c.JSON(http.StatusForbidden, authapi.OAuthError{Error: oauthErrorUnauthorizedClient, ErrorDescription: err.Error()})
+			return
+		}
+		h.tokenError(c, err, clientID)
+		return
+	}
+
+	if !introspection.Active {
+		c.JSON(http.StatusOK, authapi.OAuthIntrospection{Active: false})
+		return
+	}
+	c.JSON(http.StatusOK, authapi.OAuthIntrospection{
+		Active:    true,
+		Scope:     strings.Join(introspection.Scopes, " "),
+		ClientId:  introspection.ClientID,
+		Sub:       introspection.UserID,
+		TokenType: tokenType,
+		Iat:       introspection.IssuedAt.Unix(),
+		Exp:       introspection.ExpiresAt.Unix(),
+	})
+}
+
+// OauthRevoke revokes an access token of the calling client
+func (h *OAuthAPIHandler) OauthRevoke(c *gin.Context) {
+	clientID, clientSecret, ok := clientCredentials(c)
+	if !ok {
+		h.tokenError(c, oauthservice.ErrInvalidClient, "")
+		return
+	}
+
+	if err := h.revokeUseCase.Call(c.Request.Context(), clientID, clientSecret, c.PostForm("token")); err != nil {
+		h.tokenError(c, err, clientID)
+		return
+	}
+
+	h.logger.Info("OAuth token revocation requested", "client_id", clientID)
+	c.Status(http.StatusOK)
+}
+
+// pending returns the authorization request awaiting the consent of the current user
+func (h *OAuthAPIHandler) pending(c *gin.Context) (pendingAuthorization, bool) {
+	var pending pendingAuthorization
+	raw, _ := sessions.Default(c).Get(oauthPendingKey).(string)
+	if raw == "" || json.Unmarshal([]byte(raw), &pending) != nil || pending.UserID != middleware.CurrentUserID(c) {
+		return pendingAuthorization{}, false
+	}
+	return pending, true
+}
+
+func (h *OAuthAPIHandler) savePending(session sessions.Session, pending pendingAuthorization) error {
+	raw, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	session.Set(oauthPendingKey, string(raw))
+	return session.Save()
+}
+
+// authorizeError maps a rejected authorization request to the error code sent to the client
+func (h *OAuthAPIHandler) authorizeError(err error, clientID string) string {
+	switch {
+	case errors.Is(err, oauthservice.ErrUnsupportedResponseType):
+		return oauthErrorUnsupportedResponseType
+	case errors.Is(err, oauthservice.ErrUnauthorizedClient):
+		return oauthErrorUnauthorizedClient
+	case errors.Is(err, oauthservice.ErrPKCERequired):
+		return oauthErrorInvalidRequest
+	case errors.Is(err, oauthservice.ErrInvalidScope):
+		return oauthErrorInvalidScope
+	case errors.Is(err, oauthservice.ErrAccessDenied):
+		return oauthErrorAccessDenied
+	default:
+		h.logger.Error("Failed to authorize", "error", err.Error(), "client_id", clientID)
+		return oauthErrorServer
+	}
+}
+
+// tokenError answers a failed request to the token, introspection or revocation endpoint
+func (h *OAuthAPIHandler) tokenError(c *gin.Context, err error, clientID string) {
+	switch {
+	case errors.Is(err, oauthservice.ErrInvalidClient):
+		if _, _, basic := c.Request.BasicAuth(); basic {
+			c.Header("WWW-Authenticate", `Basic realm="oauth2"`)
+		}
+		c.JSON(http.StatusUnauthorized, authapi.OAuthError{Error: oauthErrorInvalidClient, ErrorDescription: err.Error()})
+	case errors.Is(err, oauthservice.ErrInvalidGrant):
+		h.logger.Warn("OAuth grant rejected", "error", err.Error(), "client_id", clientID)
+		c.JSON(http.StatusBadRequest, authapi.OAuthError{Error: oauthErrorInvalidGrant, ErrorDescription: err.Error()})
+	case errors.Is(err, oauthservice.ErrUnauthorizedClient):
+		c.JSON(http.StatusBadRequest, authapi.OAuthError{Error: oauthErrorUnauthorizedClient, ErrorDescription: err.Error()})
+	case errors.Is(err, oauthservice.ErrUnsupportedGrantType):
+		c.JSON(http.StatusBadRequest, authapi.OAuthError{Error: oauthErrorUnsupportedGrantType, ErrorDescription: err.Error()})
+	case errors.Is(err, oauthservice.ErrInvalidScope):
+		c.JSON(http.StatusBadRequest, authapi.OAuthError{Error: oauthErrorInvalidScope, ErrorDescription: err.Error()})
+	default:
+		h.logger.Error("OAuth token request failed", "error", err.Error(), "client_id", clientID)
+		c.JSON(http.StatusInternalServerError, authapi.OAuthError{Error: oauthErrorServer})
+	}
+}
+
+// clientCredentials reads the client ID and secret from HTTP Basic authentication, whose values
+// are form-encoded (RFC 6749 section 2.3.1), or else from the request body
+func clientCredentials(c *gin.Context) (string, string, bool) {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		id, idErr := url.QueryUnescape(id)
+		secret, secretErr := url.QueryUnescape(secret)
+		return id, secret, idErr == nil && secretErr == nil && id != ""
+	}
+	id := c.PostForm("client_id")
+	return id, c.PostForm("client_secret"), id != ""
+}
+
+// authorizationError returns the redirect URI of req carrying an error for the client
+func authorizationError(req *oauthservice.AuthorizationRequest, code, description string) string {
+	return withQuery(req.RedirectURI, "error", code, "error_description", description, "state", req.State)
+}
+
+// withQuery adds the key and value pairs with a value to the query of target
+func withQuery(target string, pairs ...string) string {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	query := parsed.Query()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			query.Set(pairs[i], pairs[i+1])
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+func toAPIOAuthScopes(scopes []string) []authapi.OAuthScope {
+	result := make([]authapi.OAuthScope, 0, len(scopes))
+	for _, scope := range slices.Sorted(slices.Values(scopes)) {
+		result = append(result, authapi.OAuthScope(scope))
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// OAuthClientAPIHandler extends the generated OAuth2ClientsAPI with actual business logic
+type OAuthClientAPIHandler struct {
+	*authapi.OAuth2ClientsAPI
+	registerUseCase authusecase.RegisterOAuthClientUseCase
+	listUseCase     authusecase.ListOAuthClientsUseCase
+	deleteUseCase   authusecase.DeleteOAuthClientUseCase
+	logger          logger.Logger
+}
+
+// NewOAuthClientAPIHandler creates a new OAuth client handler that extends the generated API
+func NewOAuthClientAPIHandler(
+	registerUseCase authusecase.RegisterOAuthClientUseCase,
+	listUseCase authusecase.ListOAuthClientsUseCase,
+	deleteUseCase authusecase.DeleteOAuthClientUseCase,
+	logger logger.Logger,
+) *OAuthClientAPIHandler {
+	return &OAuthClientAPIHandler{
+		OAuth2ClientsAPI: &authapi.OAuth2ClientsAPI{},
+		registerUseCase:  registerUseCase,
+		listUseCase:      listUseCase,
+		deleteUseCase:    deleteUseCase,
+		logger:           logger,
+	}
+}
+
+// CreateOAuthClient registers a client owned by the current user and returns its secret once
+func (h *OAuthClientAPIHandler) CreateOAuthClient(c *gin.Context) {
+	var req authapi.CreateOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid OAuth client request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, authapi.Error{Error: "Invalid request format"})
+		return
+	}
+
+	scopes := make([]string, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	userID := middleware.CurrentUserID(c)
+	client, secret, err := h.registerUseCase.Call(c.Request.Context(), userID, req.Name, req.RedirectUris, req.GrantTypes, scopes, req.Public)
+	if err != nil {
+		if errors.Is(err, oauthservice.ErrInvalidClientMetadata) {
+			c.JSON(http.StatusBadRequest, authapi.Error{Error: "Invalid OAuth client", Message: err.Error()})
+		} else {
+			h.logger.Error("Failed to register OAuth client", "error", err.Error(), "user_id", userID)
+			c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		}
+		return
+	}
+
+	h.logger.Info("OAuth client registered", "user_id", userID, "client_id", client.ID, "grant_types", client.GrantTypes)
+	c.JSON(http.StatusCreated, authapi.CreateOAuthClientResponse{
+		Client:       toAPIOAuthClient(client),
+		ClientSecret: secret,
+	})
+}
+
+// ListOAuthClients lists the clients of the current user without their secrets
+func (h *OAuthClientAPIHandler) ListOAuthClients(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	clients, err := h.listUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list OAuth clients", "error", err.Error(), "user_id", userID)
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		return
+	}
+
+	response := authapi.OAuthClientList{Clients: make([]authapi.OAuthClient, len(clients))}
+	for i, client := range clients {
+		response.Clients[i] = toAPIOAuthClient(client)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteOAuthClient deletes one of the current user's clients
+func (h *OAuthClientAPIHandler) DeleteOAuthClient(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	clientID := c.Param("clientId")
+
+	if err := h.deleteUseCase.Call(c.Request.Context(), userID, clientID); err != nil {
+		if errors.Is(err, oauthservice.ErrClientNotFound) {
+			c.JSON(http.StatusNotFound, authapi.Error{Error: "OAuth client not found"})
+		} else {
+			h.logger.Error("Failed to delete OAuth client", "error", err.Error(), "user_id", userID)
+			c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		}
+		return
+	}
+
+	h.logger.Info("OAuth client deleted", "user_id", userID, "client_id", clientID)
+	c.Status(http.StatusNoContent)
+}
+
+func toAPIOAuthClient(client *entity.OAuthClient) authapi.OAuthClient {
+	return authapi.OAuthClient{
+		Id:           client.ID,
+		Name:         client.Name,
+		Confidential: client.Confidential(),
+		RedirectUris: client.RedirectURIs,
+		GrantTypes:   client.GrantTypes,
+		Scopes:       toAPIOAuthScopes(client.Scopes),
+		CreatedAt:    client.CreatedAt,
+	}
+}
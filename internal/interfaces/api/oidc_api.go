@@ -0,0 +1,291 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	identityservice "example.com/internal/domain/service/identity"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/oidc"
+)
+
+// oidcFlowKey holds the pending authorization request in the session until the callback
+const oidcFlowKey = "oidc_flow"
+
+// Values of the oidc_error query parameter the callback redirects with
+const (
+	oidcErrorAccessDenied     = "access_denied"
+	oidcErrorInvalidState     = "invalid_state"
+	oidcErrorLoginFailed      = "login_failed"
+	oidcErrorEmailNotVerified = "email_not_verified"
+	oidcErrorEmailInUse       = "email_in_use"
+	oidcErrorIdentityLinked   = "identity_linked"
+	oidcErrorAccountLocked    = "account_locked"
+	oidcErrorServer           = "server_error"
+)
+
+// oidcFlow is an authorization request in progress. LinkUserID is set when the identity is
+// to be linked to a logged in user rather than used to sign in.
+type oidcFlow struct {
+	Provider   string           `json:"provider"`
+	LinkUserID string           `json:"link_user_id,omitempty"`
+	Request    oidc.AuthRequest `json:"request"`
+}
+
+// OIDCAPIHandler extends the generated AuthOIDCAPI with actual business logic
+type OIDCAPIHandler struct {
+	*authapi.AuthOIDCAPI
+	listProvidersUseCase  authusecase.ListOIDCProvidersUseCase
+	startUseCase          authusecase.StartOIDCUseCase
+	loginUseCase          authusecase.OIDCLoginUseCase
+	linkUseCase           authusecase.LinkIdentityUseCase
+	listIdentitiesUseCase authusecase.ListIdentitiesUseCase
+	unlinkUseCase         authusecase.UnlinkIdentityUseCase
+	logger                logger.Logger
+	redirectURL           string
+}
+
+// NewOIDCAPIHandler creates a new OIDC handler; callbacks send the browser on to redirectURL
+func NewOIDCAPIHandler(
+	listProvidersUseCase authusecase.ListOIDCProvidersUseCase,
+	startUseCase authusecase.StartOIDCUseCase,
+	loginUseCase authusecase.OIDCLoginUseCase,
+	linkUseCase authusecase.LinkIdentityUseCase,
+	listIdentitiesUseCase authusecase.ListIdentitiesUseCase,
+	unlinkUseCase authusecase.UnlinkIdentityUseCase,
+	logger logger.Logger,
+	redirectURL string,
+) *OIDCAPIHandler {
+	if redirectURL == "" {
+		redirectURL = "/"
+	}
+	return &OIDCAPIHandler{
+		AuthOIDCAPI:           &authapi.AuthOIDCAPI{},
+		listProvidersUseCase:  listProvidersUseCase,
+		startUseCase:          startUseCase,
+		loginUseCase:          loginUseCase,
+		linkUseCase:           linkUseCase,
+		listIdentitiesUseCase: listIdentitiesUseCase,
+		unlinkUseCase:         unlinkUseCase,
+		logger:                logger,
+		redirectURL:           redirectURL,
+	}
+}
+
+// ListOidcProviders lists the configured providers
+func (h *OIDCAPIHandler) ListOidcProviders(c *gin.Context) {
+	providers := h.listProvidersUseCase.Call()
+
+	response := authapi.OidcProviderList{Providers: make([]authapi.OidcProvider, len(providers))}
+	for i, provider := range providers {
+		response.Providers[i] = authapi.OidcProvider{Name: provider.Name, DisplayName: provider.DisplayName}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// StartOidcLogin sends the browser to the provider to sign in
+func (h *OIDCAPIHandler) StartOidcLogin(c *gin.Context) {
+	h.start(c, "")
+}
+
+// StartOidcLink sends the browser to the provider to link an identity to the current user
+func (h *OIDCAPIHandler) StartOidcLink(c *gin.Context) {
+	h.start(c, middleware.CurrentUserID(c))
+}
+
+func (h *OIDCAPIHandler) start(c *gin.Context, linkUserID string) {
+	provider := c.Param("provider")
+
+	authURL, req, err := h.startUseCase.Call(c.Request.Context(), provider)
+	if err != nil {
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			c.JSON(http.StatusNotFound, authapi.Error{Error: "Unknown provider"})
+		} else {
+			h.logger.Error("Failed to start OIDC flow", "error", err.Error(), "provider", provider)
+			c.JSON(http.StatusBadGateway, authapi.Error{Error: "Identity provider unavailable"})
+		}
+		return
+	}
+
+	flow, err := json.Marshal(oidcFlow{Provider: provider, LinkUserID: linkUserID, Request: req})
+	if err != nil {
+		h.logger.Error("Failed to encode OIDC flow", "error", err.Error())
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		return
+	}
+
+	session := sessions.Default(c)
+	session.Set(oidcFlowKey, string(flow))
+	if err := session.Save(); err != nil {
+		h.logger.Error("Failed to save OIDC flow", "error", err.Error())
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OidcCallback completes the flow started by StartOidcLogin or StartOidcLink. The pending
+// request is consumed whatever the outcome, so a callback URL cannot be replayed.
+func (h *OIDCAPIHandler) OidcCallback(c *gin.Context) {
+	provider := c.Param("provider")
+	session := sessions.Default(c)
+
+	raw, _ := session.Get(oidcFlowKey).(string)
+	session.Delete(oidcFlowKey)
+
+	var flow oidcFlow
+	if raw == "" || json.Unmarshal([]byte(raw), &flow) != nil || flow.Provider != provider ||
+		subtle.ConstantTimeCompare([]byte(flow.Request.State), []byte(c.Query("state"))) != 1 {
+		h.fail(c, oidcErrorInvalidState)
+		return
+	}
+	if c.Query("error") != "" {
+		h.fail(c, oidcErrorAccessDenied)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if flow.LinkUserID != "" {
+		// The session must still belong to the user who started linking
+		if userID, _ := session.Get(middleware.UserIDKey).(string); userID != flow.LinkUserID {
+			h.fail(c, oidcErrorInvalidState)
+			return
+		}
+
+		identity, err := h.linkUseCase.Call(ctx, flow.LinkUserID, provider, c.Query("code"), flow.Request)
+		if err != nil {
+			h.fail(c, h.callbackError(err, provider))
+			return
+		}
+		if err := session.Save(); err != nil {
+			h.logger.Error("Failed to save session", "error", err.Error())
+		}
+
+		h.logger.Info("Identity linked", "user_id", flow.LinkUserID, "provider", provider, "identity_id", identity.ID)
+		c.Redirect(http.StatusFound, h.redirect("oidc", "linked"))
+		return
+	}
+
+	user, err := h.loginUseCase.Call(ctx, provider, c.Query("code"), flow.Request)
+	if err != nil {
+		h.fail(c, h.callbackError(err, provider))
+		return
+	}
+	if err := middleware.StartSession(c, user.ID); err != nil {
+		h.logger.Error("Failed to start session", "error", err.Error(), "user_id", user.ID)
+		h.fail(c, oidcErrorServer)
+		return
+	}
+	middleware.LoggedIn(c, user.ID, "oidc:"+provider)
+
+	h.logger.Info("User logged in with OIDC", "user_id", user.ID, "provider", provider)
+	c.Redirect(http.StatusFound, h.redirect("", ""))
+}
+
+// ListIdentities lists the identities linked to the current user
+func (h *OIDCAPIHandler) ListIdentities(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	identities, err := h.listIdentitiesUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list identities", "error", err.Error(), "user_id", userID)
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		return
+	}
+
+	response := authapi.UserIdentityList{Identities: make([]authapi.UserIdentity, len(identities))}
+	for i, identity := range identities {
+		response.Identities[i] = toAPIIdentity(identity)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UnlinkIdentity removes one of the current user's identities
+func (h *OIDCAPIHandler) UnlinkIdentity(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	identityID := c.Param("identityId")
+
+	if err := h.unlinkUseCase.Call(c.Request.Context(), userID, identityID); err != nil {
+		switch {
+		case errors.Is(err, identityservice.ErrIdentityNotFound):
+			c.JSON(http.StatusNotFound, authapi.Error{Error: "Identity not found"})
+		case errors.Is(err, identityservice.ErrLastIdentity):
+			c.JSON(http.StatusConflict, authapi.Error{Error: "Cannot unlink identity", Message: err.Error()})
+		default:
+			h.logger.Error("Failed to unlink identity", "error", err.Error(), "user_id", userID)
+			c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		}
+		return
+	}
+
+	h.logger.Info("Identity unlinked", "user_id", userID, "identity_id", identityID)
+	c.Status(http.StatusNoContent)
+}
+
+// callbackError maps a failed sign-in or link to the oidc_error reported to the application
+func (h *OIDCAPIHandler) callbackError(err error, provider string) string {
+	switch {
+	case errors.Is(err, oidc.ErrDiscovery), errors.Is(err, oidc.ErrExchange),
+		errors.Is(err, oidc.ErrInvalidToken), errors.Is(err, oidc.ErrNonceMismatch),
+		errors.Is(err, oidc.ErrUnknownProvider):
+		h.logger.Warn("OIDC callback rejected", "error", err.Error(), "provider", provider)
+		return oidcErrorLoginFailed
+	case errors.Is(err, identityservice.ErrEmailNotVerified):
+		return oidcErrorEmailNotVerified
+	case errors.Is(err, identityservice.ErrEmailInUse):
+		return oidcErrorEmailInUse
+	case errors.Is(err, identityservice.ErrIdentityLinked):
+		return oidcErrorIdentityLinked
+	case errors.Is(err, authservice.ErrAccountLocked):
+		return oidcErrorAccountLocked
+	default:
+		h.logger.Error("OIDC callback failed", "error", err.Error(), "provider", provider)
+		return oidcErrorServer
+	}
+}
+
+// fail saves the consumed flow and sends the browser back to the application with reason
+func (h *OIDCAPIHandler) fail(c *gin.Context, reason string) {
+	if err := sessions.Default(c).Save(); err != nil {
+		h.logger.Error("Failed to save session", "error", err.Error())
+	}
+	c.Redirect(http.StatusFound, h.redirect("oidc_error", reason))
+}
+
+// redirect returns the application URL, with the query parameter key set when key is not empty
+func (h *OIDCAPIHandler) redirect(key, value string) string {
+	if key == "" {
+		return h.redirectURL
+	}
+	target, err := url.Parse(h.redirectURL)
+	if err != nil {
+		return h.redirectURL
+	}
+	query := target.Query()
+	query.Set(key, value)
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+func toAPIIdentity(identity *entity.UserIdentity) authapi.UserIdentity {
+	return authapi.UserIdentity{
+		Id:          identity.ID,
+		Provider:    identity.Provider,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	}
+}
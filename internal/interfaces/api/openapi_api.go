@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	"gopkg.in/yaml.v3"
+
+	apispec "example.com/api"
+)
+
+const docsIndexTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <title>API Documentation</title>
+  <link rel="stylesheet" type="text/css" href="./swagger-ui.css">
+  <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32">
+  <link rel="icon" type="image/png" href="./favicon-16x16.png" sizes="16x16">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="./swagger-ui-bundle.js"></script>
+<script src="./swagger-ui-standalone-preset.js"></script>
+<script>
+window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    urls: {{ .URLs }},
+    "urls.primaryName": {{ .Primary }},
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    withCredentials: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+</script>
+</body>
+</html>
+`
+
+type docsSpecURL struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+type renderedSpec struct {
+	yaml []byte
+	json []byte
+}
+
+// OpenAPIHandler serves the embedded OpenAPI specifications and the Swagger UI
+type OpenAPIHandler struct {
+	specs map[string]renderedSpec
+	index []byte
+}
+
+// NewOpenAPIHandler renders every embedded spec once, pointing its servers at publicURL
+func NewOpenAPIHandler(publicURL string) (*OpenAPIHandler, error) {
+	specs := make(map[string]renderedSpec, len(apispec.Names))
+	urls := make([]docsSpecURL, 0, len(apispec.Names))
+
+	for _, name := range apispec.Names {
+		data, err := apispec.Spec(name)
+		if err != nil {
+			return nil, err
+		}
+
+		spec, err := renderSpec(data, publicURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render OpenAPI spec %s: %w", name, err)
+		}
+
+		specs[name] = spec
+		urls = append(urls, docsSpecURL{URL: "/openapi/" + name + ".yaml", Name: name})
+	}
+
+	tpl, err := template.New("docs").Parse(docsIndexTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var index strings.Builder
+	if err := tpl.Execute(&index, map[string]any{"URLs": urls, "Primary": apispec.Names[0]}); err != nil {
+		return nil, err
+	}
+
+	return &OpenAPIHandler{
+		specs: specs,
+		index: []byte(index.String()),
+	}, nil
+}
+
+// Spec serves /openapi/{name}.yaml and /openapi/{name}.json
+func (h *OpenAPIHandler) Spec(c *gin.Context) {
+	file := c.Param("file")
+	ext := path.Ext(file)
+
+	spec, ok := h.specs[strings.TrimSuffix(file, ext)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "OpenAPI spec not found"})
+		return
+	}
+
+	switch ext {
+	case ".yaml", ".yml":
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", spec.yaml)
+	case ".json":
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec.json)
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "OpenAPI spec not found"})
+	}
+}
+
+// Docs serves the Swagger UI with a selector listing every embedded spec
+func (h *OpenAPIHandler) Docs(c *gin.Context) {
+	file := strings.TrimPrefix(c.Param("any"), "/")
+
+	switch file {
+	case "":
+		c.Redirect(http.StatusMovedPermanently, "/docs/index.html")
+	case "index.html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", h.index)
+	default:
+		c.FileFromFS(file, swaggerFiles.HTTP)
+	}
+}
+
+// renderSpec replaces the documented servers with publicURL, keeping each server's base path
+func renderSpec(data []byte, publicURL string) (renderedSpec, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return renderedSpec{}, err
+	}
+
+	if publicURL != "" && len(root.Content) > 0 {
+		if err := rewriteServers(root.Content[0], strings.TrimSuffix(publicURL, "/")); err != nil {
+			return renderedSpec{}, err
+		}
+	}
+
+	var yamlData bytes.Buffer
+	encoder := yaml.NewEncoder(&yamlData)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return renderedSpec{}, err
+	}
+
+	var document map[string]any
+	if err := root.Decode(&document); err != nil {
+		return renderedSpec{}, err
+	}
+
+	jsonData, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return renderedSpec{}, err
+	}
+
+	return renderedSpec{yaml: yamlData.Bytes(), json: jsonData}, nil
+}
+
+func rewriteServers(document *yaml.Node, publicURL string) error {
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		if document.Content[i].Value != "servers" {
+			continue
+		}
+
+		var servers []struct {
+			URL string `yaml:"url"`
+		}
+		if err := document.Content[i+1].Decode(&servers); err != nil {
+			return err
+		}
+
+		var rewritten []map[string]string
+		seen := make(map[string]bool)
+		for _, server := range servers {
+			parsed, err := url.Parse(server.URL)
+			if err != nil {
+				return fmt.Errorf("invalid server URL %q: %w", server.URL, err)
+			}
+			if seen[parsed.Path] {
+				continue
+			}
+			seen[parsed.Path] = true
+			rewritten = append(rewritten, map[string]string{"url": publicURL + parsed.Path})
+		}
+
+		var node yaml.Node
+		if err := node.Encode(rewritten); err != nil {
+			return err
+		}
+		document.Content[i+1] = &node
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/domain/entity"
+	organizationservice "example.com/internal/domain/service/organization"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// OrganizationAPIHandler extends the generated OrganizationsAPI with actual business logic
+type OrganizationAPIHandler struct {
+	*v1api.OrganizationsAPI
+	createUseCase            userusecase.CreateOrganizationUseCase
+	listUseCase              userusecase.ListOrganizationsUseCase
+	listMembersUseCase       userusecase.ListMembersUseCase
+	removeMemberUseCase      userusecase.RemoveMemberUseCase
+	transferOwnershipUseCase userusecase.TransferOwnershipUseCase
+	inviteUseCase            userusecase.InviteMemberUseCase
+	listInvitationsUseCase   userusecase.ListInvitationsUseCase
+	revokeInvitationUseCase  userusecase.RevokeInvitationUseCase
+	acceptUseCase            userusecase.AcceptInvitationUseCase
+	declineUseCase           userusecase.DeclineInvitationUseCase
+	logger                   logger.Logger
+}
+
+// NewOrganizationAPIHandler creates a new organization handler that extends the generated API
+func NewOrganizationAPIHandler(
+	createUseCase userusecase.CreateOrganizationUseCase,
+	listUseCase userusecase.ListOrganizationsUseCase,
+	listMembersUseCase userusecase.ListMembersUseCase,
+	removeMemberUseCase userusecase.RemoveMemberUseCase,
+	transferOwnershipUseCase userusecase.TransferOwnershipUseCase,
+	inviteUseCase userusecase.InviteMemberUseCase,
+	listInvitationsUseCase userusecase.ListInvitationsUseCase,
+	revokeInvitationUseCase userusecase.RevokeInvitationUseCase,
+	acceptUseCase userusecase.AcceptInvitationUseCase,
+	declineUseCase userusecase.DeclineInvitationUseCase,
+	logger logger.Logger,
+) *OrganizationAPIHandler {
+	return &OrganizationAPIHandler{
+		OrganizationsAPI:         &v1api.OrganizationsAPI{},
+		createUseCase:            createUseCase,
+		listUseCase:              listUseCase,
+		listMembersUseCase:       listMembersUseCase,
+		removeMemberUseCase:      removeMemberUseCase,
+		transferOwnershipUseCase: transferOwnershipUseCase,
+		inviteUseCase:            inviteUseCase,
+		listInvitationsUseCase:   listInvitationsUseCase,
+		revokeInvitationUseCase:  revokeInvitationUseCase,
+		acceptUseCase:            acceptUseCase,
+		declineUseCase:           declineUseCase,
+		logger:                   logger,
+	}
+}
+
+// ListOrganizations lists the organizations of the current user with the user's role
+func (h *OrganizationAPIHandler) ListOrganizations(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	memberships, err := h.listUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.organizationError(c, err, "Failed to list organizations")
+		return
+	}
+
+	response := v1api.OrganizationList{Organizations: make([]v1api.Organization, len(memberships))}
+	for i, membership := range memberships {
+		response.Organizations[i] = toOrganization(membership)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateOrganization creates an organization owned by the current user
+func (h *OrganizationAPIHandler) CreateOrganization(c *gin.Context) {
+	var req v1api.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid organization request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	membership, err := h.createUseCase.Call(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		h.organizationError(c, err, "Failed to create organization")
+		return
+	}
+
+	h.logger.Info("Organization created", "organization_id", membership.OrganizationID, "user_id", userID)
+	c.JSON(http.StatusCreated, toOrganization(membership))
+}
+
+// ListMembers lists the members of the organization in the path
+func (h *OrganizationAPIHandler) ListMembers(c *gin.Context) {
+	memberships, err := h.listMembersUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c), c.Param("organizationId"))
+	if err != nil {
+		h.organizationError(c, err, "Failed to list members")
+		return
+	}
+
+	response := v1api.MemberList{Members: make([]v1api.Member, len(memberships))}
+	for i, membership := range memberships {
+		member := v1api.Member{
+			UserId:   membership.UserID,
+			Role:     v1api.MembershipRole(membership.Role),
+			JoinedAt: membership.CreatedAt,
+		}
+		if membership.User != nil {
+			member.Email = membership.User.Email
+			member.Username = membership.User.UserName
+		}
+		response.Members[i] = member
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveMember removes the user in the path from the organization in the path
+func (h *OrganizationAPIHandler) RemoveMember(c *gin.Context) {
+	actorID, organizationID, userID := middleware.CurrentUserID(c), c.Param("organizationId"), c.Param("userId")
+	if err := h.removeMemberUseCase.Call(c.Request.Context(), actorID, organizationID, userID); err != nil {
+		h.organizationError(c, err, "Failed to remove member")
+		return
+	}
+
+	h.logger.Info("Member removed", "organization_id", organizationID, "user_id", userID, "by", actorID)
+	c.Status(http.StatusNoContent)
+}
+
+// TransferOwnership hands the organization in the path to another member
+func (h *OrganizationAPIHandler) TransferOwnership(c *gin.Context) {
+	var req v1api.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid ownership transfer request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	actorID, organizationID := middleware.CurrentUserID(c), c.Param("organizationId")
+	if err := h.transferOwnershipUseCase.Call(c.Request.Context(), actorID, organizationID, req.UserId); err != nil {
+		h.organizationError(c, err, "Failed to transfer ownership")
+		return
+	}
+
+	h.logger.Info("Organization ownership transferred", "organization_id", organizationID, "from", actorID, "to", req.UserId)
+	c.Status(http.StatusNoContent)
+}
+
+// InviteMember emails an invitation to join the organization in the path
+func (h *OrganizationAPIHandler) InviteMember(c *gin.Context) {
+	var req v1api.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid invitation request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	actorID, organizationID := middleware.CurrentUserID(c), c.Param("organizationId")
+	invitation, err := h.inviteUseCase.Call(c.Request.Context(), actorID, organizationID, req.Email, req.Role)
+	if err != nil {
+		h.organizationError(c, err, "Failed to invite member")
+		return
+	}
+
+	h.logger.Info("Member invited", "organization_id", organizationID, "invitation_id", invitation.ID, "by", actorID)
+	c.JSON(http.StatusCreated, toInvitation(invitation))
+}
+
+// ListInvitations lists the pending invitations of the organization in the path
+func (h *OrganizationAPIHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.listInvitationsUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c), c.Param("organizationId"))
+	if err != nil {
+		h.organizationError(c, err, "Failed to list invitations")
+		return
+	}
+
+	response := v1api.InvitationList{Invitations: make([]v1api.Invitation, len(invitations))}
+	for i, invitation := range invitations {
+		response.Invitations[i] = toInvitation(invitation)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeInvitation voids the invitation in the path
+func (h *OrganizationAPIHandler) RevokeInvitation(c *gin.Context) {
+	actorID, organizationID, invitationID := middleware.CurrentUserID(c), c.Param("organizationId"), c.Param("invitationId")
+	if err := h.revokeInvitationUseCase.Call(c.Request.Context(), actorID, organizationID, invitationID); err != nil {
+		h.organizationError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	h.logger.Info("Invitation revoked", "organization_id", organizationID, "invitation_id", invitationID, "by", actorID)
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation makes the current user a member with the invitation token in the body
+func (h *OrganizationAPIHandler) AcceptInvitation(c *gin.Context) {
+	var req v1api.InvitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid invitation response", "error", err.Error())
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	membership, err := h.acceptUseCase.Call(c.Request.Context(), userID, req.Token)
+	if err != nil {
+		h.organizationError(c, err, "Failed to accept invitation")
+		return
+	}
+
+	h.logger.Info("Invitation accepted", "organization_id", membership.OrganizationID, "user_id", userID)
+	c.JSON(http.StatusOK, toOrganization(membership))
+}
+
+// DeclineInvitation refuses the invitation token in the body
+func (h *OrganizationAPIHandler) DeclineInvitation(c *gin.Context) {
+	var req v1api.InvitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid invitation response", "error", err.Error())
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	if err := h.declineUseCase.Call(c.Request.Context(), userID, req.Token); err != nil {
+		h.organizationError(c, err, "Failed to decline invitation")
+		return
+	}
+
+	h.logger.Info("Invitation declined", "user_id", userID)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OrganizationAPIHandler) organizationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, organizationservice.ErrInvalidName),
+		errors.Is(err, organizationservice.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request", Message: err.Error()})
+	case errors.Is(err, organizationservice.ErrNotAllowed):
+		c.JSON(http.StatusForbidden, v1api.Error{Error: "Forbidden", Message: err.Error()})
+	case errors.Is(err, organizationservice.ErrOrganizationNotFound),
+		errors.Is(err, organizationservice.ErrMemberNotFound),
+		errors.Is(err, organizationservice.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, v1api.Error{Error: "Not found", Message: err.Error()})
+	case errors.Is(err, organizationservice.ErrAlreadyMember),
+		errors.Is(err, organizationservice.ErrOwnerRemoval):
+		c.JSON(http.StatusConflict, v1api.Error{Error: "Conflict", Message: err.Error()})
+	default:
+		h.logger.Error(message, "error", err.Error(), "user_id", middleware.CurrentUserID(c))
+		c.JSON(http.StatusInternalServerError, v1api.Error{Error: "Internal server error", Message: "Internal server error"})
+	}
+}
+
+func toOrganization(membership *entity.Membership) v1api.Organization {
+	organization := v1api.Organization{
+		Id:   membership.OrganizationID,
+		Role: v1api.MembershipRole(membership.Role),
+	}
+	if membership.Organization != nil {
+		organization.Name = membership.Organization.Name
+		organization.CreatedAt = membership.Organization.CreatedAt
+	}
+	return organization
+}
+
+func toInvitation(invitation *entity.Invitation) v1api.Invitation {
+	return v1api.Invitation{
+		Id:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      v1api.MembershipRole(invitation.Role),
+		InvitedBy: invitation.InvitedBy,
+		CreatedAt: invitation.CreatedAt,
+		ExpiresAt: invitation.ExpiresAt,
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	authapi "example.com/gen/openapi/auth/go"
+	passwordservice "example.com/internal/domain/service/password"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+)
+
+// PasswordAPIHandler extends the generated AuthPasswordAPI with actual business logic
+type PasswordAPIHandler struct {
+	*authapi.AuthPasswordAPI
+	changeUseCase authusecase.ChangePasswordUseCase
+	logger        logger.Logger
+}
+
+// NewPasswordAPIHandler creates a new password handler
+func NewPasswordAPIHandler(changeUseCase authusecase.ChangePasswordUseCase, logger logger.Logger) *PasswordAPIHandler {
+	return &PasswordAPIHandler{
+		AuthPasswordAPI: &authapi.AuthPasswordAPI{},
+		changeUseCase:   changeUseCase,
+		logger:          logger,
+	}
+}
+
+// ChangePassword replaces the password of the current user and keeps only this session logged in
+func (h *PasswordAPIHandler) ChangePassword(c *gin.Context) {
+	var req authapi.PasswordChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, authapi.Error{Message: "Invalid request format"})
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	user, err := h.changeUseCase.Call(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, passwordservice.ErrIncorrectPassword):
+			c.JSON(http.StatusUnauthorized, authapi.Error{Error: "Incorrect current password"})
+		case errors.Is(err, security.ErrWeakPassword):
+			c.JSON(http.StatusBadRequest, weakPasswordError(err, "newPassword"))
+		case errors.Is(err, passwordservice.ErrPasswordReused):
+			c.JSON(http.StatusBadRequest, authapi.Error{
+				Error:   "Password was used recently",
+				Details: []authapi.FieldError{{Field: "newPassword", In: "body", Message: "must differ from your recent passwords"}},
+			})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusUnauthorized, authapi.Error{Error: "Authentication required"})
+		default:
+			h.logger.Error("Failed to change password", "error", err.Error(), "user_id", userID)
+			c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+		}
+		return
+	}
+
+	// The change revoked every session including this one, which is renewed to stay logged in
+	if err := middleware.StartSession(c, user.ID); err != nil {
+		h.logger.Error("Failed to renew session", "error", err.Error(), "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+		return
+	}
+
+	h.logger.Info("Password changed", "user_id", user.ID)
+	c.JSON(http.StatusOK, authapi.PasswordChangeResponse{Message: "Password changed"})
+}
+
+// weakPasswordError lists the rules a password broke as details of the given body field
+func weakPasswordError(err error, field string) authapi.Error {
+	response := authapi.Error{Error: "Password does not meet the policy"}
+	var policyErr *security.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		for _, violation := range policyErr.Violations {
+			response.Details = append(response.Details, authapi.FieldError{Field: field, In: "body", Message: violation})
+		}
+	}
+	return response
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	authservice "example.com/internal/domain/service/auth"
+	passwordlessservice "example.com/internal/domain/service/passwordless"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+)
+
+// passwordlessLoginKey holds the binding of the pending passwordless login in the session, which
+// makes the session the device the login has to be completed on
+const passwordlessLoginKey = "passwordless_login"
+
+// PasswordlessAPIHandler extends the generated AuthPasswordlessAPI with actual business logic
+type PasswordlessAPIHandler struct {
+	*authapi.AuthPasswordlessAPI
+	startUseCase authusecase.StartPasswordlessLoginUseCase
+	loginUseCase authusecase.PasswordlessLoginUseCase
+	completer    loginCompleter
+	logger       logger.Logger
+}
+
+// NewPasswordlessAPIHandler creates a new passwordless login handler; logins complete like
+// password logins, issuing tokens with issueTokensUseCase when asked to
+func NewPasswordlessAPIHandler(
+	startUseCase authusecase.StartPasswordlessLoginUseCase,
+	loginUseCase authusecase.PasswordlessLoginUseCase,
+	issueTokensUseCase authusecase.IssueTokensUseCase,
+	logger logger.Logger,
+) *PasswordlessAPIHandler {
+	return &PasswordlessAPIHandler{
+		AuthPasswordlessAPI: &authapi.AuthPasswordlessAPI{},
+		startUseCase:        startUseCase,
+		loginUseCase:        loginUseCase,
+		completer: loginCompleter{
+			issueTokensUseCase: issueTokensUseCase,
+			logger:             logger,
+			method:             "passwordless",
+		},
+		logger: logger,
+	}
+}
+
+// StartPasswordlessLogin emails a login link or code and binds the login to the session
+func (h *PasswordlessAPIHandler) StartPasswordlessLogin(c *gin.Context) {
+	var req authapi.PasswordlessStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid passwordless login request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, authapi.Error{Message: "Invalid request format"})
+		return
+	}
+
+	binding, err := h.startUseCase.Call(c.Request.Context(), req.Email, req.Method)
+	if err != nil {
+		if errors.Is(err, passwordlessservice.ErrInvalidMethod) {
+			c.JSON(http.StatusBadRequest, authapi.Error{Error: "Unsupported login method"})
+		} else {
+			h.logger.Error("Failed to start passwordless login", "error", err.Error(), "method", req.Method)
+			c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+		}
+		return
+	}
+
+	encoded, err := json.Marshal(binding)
+	if err != nil {
+		h.logger.Error("Failed to encode passwordless login", "error", err.Error())
+		c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+		return
+	}
+
+	session := sessions.Default(c)
+	session.Set(passwordlessLoginKey, string(encoded))
+	if err := session.Save(); err != nil {
+		h.logger.Error("Failed to save passwordless login", "error", err.Error())
+		c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, authapi.PasswordlessStartResponse{Message: "Check your email"})
+}
+
+// VerifyPasswordlessLogin completes the login bound to the session with the emailed link or code
+func (h *PasswordlessAPIHandler) VerifyPasswordlessLogin(c *gin.Context) {
+	var req authapi.PasswordlessVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Token == "") == (req.Code == "") {
+		c.JSON(http.StatusBadRequest, authapi.Error{Message: "Invalid request format"})
+		return
+	}
+	secret := req.Token
+	if secret == "" {
+		secret = req.Code
+	}
+
+	session := sessions.Default(c)
+	var binding passwordlessservice.Binding
+	encoded, _ := session.Get(passwordlessLoginKey).(string)
+	if encoded == "" || json.Unmarshal([]byte(encoded), &binding) != nil {
+		c.JSON(http.StatusBadRequest, authapi.Error{Error: "No pending login, request a new code"})
+		return
+	}
+
+	user, err := h.loginUseCase.Call(c.Request.Context(), binding, secret)
+	if err != nil {
+		switch {
+		case errors.Is(err, passwordlessservice.ErrIncorrectSecret):
+			c.JSON(http.StatusUnauthorized, authapi.Error{Error: "Incorrect code"})
+		case errors.Is(err, passwordlessservice.ErrNoPendingLogin):
+			h.forget(session)
+			c.JSON(http.StatusBadRequest, authapi.Error{Error: "No pending login, request a new code"})
+		case errors.Is(err, passwordlessservice.ErrTooManyAttempts):
+			h.forget(session)
+			c.JSON(http.StatusTooManyRequests, authapi.Error{Error: "Too many incorrect codes, request a new code"})
+		case errors.Is(err, authservice.ErrAccountLocked):
+			h.forget(session)
+			c.JSON(http.StatusForbidden, authapi.Error{Error: "Account locked"})
+		default:
+			h.logger.Error("Failed to verify passwordless login", "error", err.Error())
+			c.JSON(http.StatusInternalServerError, authapi.Error{Message: "Internal server error"})
+		}
+		return
+	}
+
+	// Saved along with the session being started; a token login leaves a used binding behind
+	session.Delete(passwordlessLoginKey)
+	h.completer.complete(c, user, req.IssueTokens)
+}
+
+// forget drops the pending login from the session; a failure only leaves a void binding behind
+func (h *PasswordlessAPIHandler) forget(session sessions.Session) {
+	session.Delete(passwordlessLoginKey)
+	if err := session.Save(); err != nil {
+		h.logger.Warn("Failed to clear passwordless login", "error", err.Error())
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/domain/entity"
+	privacyservice "example.com/internal/domain/service/privacy"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// PrivacyAPIHandler extends the generated PrivacyAPI with actual business logic
+type PrivacyAPIHandler struct {
+	*v1api.PrivacyAPI
+	requestExportUseCase   userusecase.RequestDataExportUseCase
+	getExportUseCase       userusecase.GetDataExportUseCase
+	downloadExportUseCase  userusecase.DownloadDataExportUseCase
+	requestDeletionUseCase userusecase.RequestAccountDeletionUseCase
+	confirmDeletionUseCase userusecase.ConfirmAccountDeletionUseCase
+	getDeletionUseCase     userusecase.GetAccountDeletionUseCase
+	cancelDeletionUseCase  userusecase.CancelAccountDeletionUseCase
+	logger                 logger.Logger
+}
+
+// NewPrivacyAPIHandler creates a new privacy handler that extends the generated API
+func NewPrivacyAPIHandler(
+	requestExportUseCase userusecase.RequestDataExportUseCase,
+	getExportUseCase userusecase.GetDataExportUseCase,
+	downloadExportUseCase userusecase.DownloadDataExportUseCase,
+	requestDeletionUseCase userusecase.RequestAccountDeletionUseCase,
+	confirmDeletionUseCase userusecase.ConfirmAccountDeletionUseCase,
+	getDeletionUseCase userusecase.GetAccountDeletionUseCase,
+	cancelDeletionUseCase userusecase.CancelAccountDeletionUseCase,
+	logger logger.Logger,
+) *PrivacyAPIHandler {
+	return &PrivacyAPIHandler{
+		PrivacyAPI:             &v1api.PrivacyAPI{},
+		requestExportUseCase:   requestExportUseCase,
+		getExportUseCase:       getExportUseCase,
+		downloadExportUseCase:  downloadExportUseCase,
+		requestDeletionUseCase: requestDeletionUseCase,
+		confirmDeletionUseCase: confirmDeletionUseCase,
+		getDeletionUseCase:     getDeletionUseCase,
+		cancelDeletionUseCase:  cancelDeletionUseCase,
+		logger:                 logger,
+	}
+}
+
+// RequestDataExport queues an export of the personal data of the current user
+func (h *PrivacyAPIHandler) RequestDataExport(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	export, err := h.requestExportUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.privacyError(c, err, "Failed to request data export")
+		return
+	}
+
+	h.logger.Info("Data export requested", "user_id", userID, "export_id", export.ID)
+	c.JSON(http.StatusAccepted, toDataExport(export))
+}
+
+// GetDataExport returns the export the current user requested last
+func (h *PrivacyAPIHandler) GetDataExport(c *gin.Context) {
+	export, err := h.getExportUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.privacyError(c, err, "Failed to get data export")
+		return
+	}
+	c.JSON(http.StatusOK, toDataExport(export))
+}
+
+// DownloadDataExport serves the archive of the export link token
+func (h *PrivacyAPIHandler) DownloadDataExport(c *gin.Context) {
+	export, archive, err := h.downloadExportUseCase.Call(c.Request.Context(), c.Query(privacyservice.DownloadTokenParam))
+	if err != nil {
+		h.privacyError(c, err, "Failed to download data export")
+		return
+	}
+	defer archive.Body.Close()
+
+	h.logger.Info("Data export downloaded", "user_id", export.UserID, "export_id", export.ID)
+	c.DataFromReader(http.StatusOK, archive.Size, "application/zip", archive.Body, map[string]string{
+		"Content-Disposition": `attachment; filename="data-export-` + export.CreatedAt.UTC().Format("2006-01-02") + `.zip"`,
+		"Cache-Control":       "no-store",
+	})
+}
+
+// RequestAccountDeletion emails the current user a link to confirm the deletion of their account
+func (h *PrivacyAPIHandler) RequestAccountDeletion(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	deletion, err := h.requestDeletionUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.privacyError(c, err, "Failed to request account deletion")
+		return
+	}
+
+	h.logger.Info("Account deletion requested", "user_id", userID)
+	c.JSON(http.StatusAccepted, toAccountDeletion(deletion))
+}
+
+// ConfirmAccountDeletion schedules the erasure of the account with the token in the body
+func (h *PrivacyAPIHandler) ConfirmAccountDeletion(c *gin.Context) {
+	var req v1api.DeletionTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid deletion confirmation", "error", err.Error())
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	deletion, err := h.confirmDeletionUseCase.Call(c.Request.Context(), userID, req.Token)
+	if err != nil {
+		h.privacyError(c, err, "Failed to confirm account deletion")
+		return
+	}
+
+	h.logger.Info("Account deletion confirmed", "user_id", userID, "scheduled_at", deletion.ScheduledAt)
+	c.JSON(http.StatusOK, toAccountDeletion(deletion))
+}
+
+// GetAccountDeletion returns the account deletion requested by the current user
+func (h *PrivacyAPIHandler) GetAccountDeletion(c *gin.Context) {
+	deletion, err := h.getDeletionUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.privacyError(c, err, "Failed to get account deletion")
+		return
+	}
+	c.JSON(http.StatusOK, toAccountDeletion(deletion))
+}
+
+// CancelAccountDeletion withdraws the account deletion of the current user
+func (h *PrivacyAPIHandler) CancelAccountDeletion(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	if err := h.cancelDeletionUseCase.Call(c.Request.Context(), userID); err != nil {
+		h.privacyError(c, err, "Failed to cancel account deletion")
+		return
+	}
+
+	h.logger.Info("Account deletion cancelled", "user_id", userID)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PrivacyAPIHandler) privacyError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, privacyservice.ErrExportNotFound):
+		c.JSON(http.StatusNotFound, v1api.Error{Error: "Data export not found", Message: err.Error()})
+	case errors.Is(err, privacyservice.ErrDeletionNotFound):
+		c.JSON(http.StatusNotFound, v1api.Error{Error: "Account deletion not found", Message: err.Error()})
+	case errors.Is(err, privacyservice.ErrInvalidDeletionToken):
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid token", Message: err.Error()})
+	case errors.Is(err, privacyservice.ErrOrganizationOwner):
+		c.JSON(http.StatusConflict, v1api.Error{Error: "Organization owner", Message: err.Error()})
+	default:
+		h.logger.Error(message, "error", err.Error(), "user_id", middleware.CurrentUserID(c))
+		c.JSON(http.StatusInternalServerError, v1api.Error{Error: "Internal server error"})
+	}
+}
+
+func toDataExport(export *entity.DataExport) v1api.DataExport {
+	response := v1api.DataExport{
+		Id:          export.ID,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+		Size:        export.Size,
+	}
+	// Expired exports are only deleted by the next run of the privacy job
+	if export.Status == entity.DataExportReady && !export.Available(time.Now()) {
+		response.Status = "expired"
+	}
+	return response
+}
+
+func toAccountDeletion(deletion *entity.AccountDeletion) v1api.AccountDeletion {
+	response := v1api.AccountDeletion{
+		Status:      "requested",
+		CreatedAt:   deletion.CreatedAt,
+		ExpiresAt:   deletion.ExpiresAt,
+		ConfirmedAt: deletion.ConfirmedAt,
+		ScheduledAt: deletion.ScheduledAt,
+	}
+	if deletion.Confirmed() {
+		response.Status = "scheduled"
+	}
+	return response
+}
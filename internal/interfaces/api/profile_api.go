@@ -0,0 +1,187 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/domain/entity"
+	avatarservice "example.com/internal/domain/service/avatar"
+	profileservice "example.com/internal/domain/service/profile"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+)
+
+// ProfileAPIHandler extends the generated ProfileAPI with actual business logic
+type ProfileAPIHandler struct {
+	*v1api.ProfileAPI
+	getUseCase          userusecase.GetProfileUseCase
+	updateUseCase       userusecase.UpdateProfileUseCase
+	uploadAvatarUseCase userusecase.UploadAvatarUseCase
+	deleteAvatarUseCase userusecase.DeleteAvatarUseCase
+	avatarURLs          *security.URLSigner
+	logger              logger.Logger
+	// publicURL is the base of the avatar URLs, which are served by AvatarAPIHandler
+	publicURL string
+}
+
+// NewProfileAPIHandler creates a new profile handler that extends the generated API
+func NewProfileAPIHandler(
+	getUseCase userusecase.GetProfileUseCase,
+	updateUseCase userusecase.UpdateProfileUseCase,
+	uploadAvatarUseCase userusecase.UploadAvatarUseCase,
+	deleteAvatarUseCase userusecase.DeleteAvatarUseCase,
+	avatarURLs *security.URLSigner,
+	logger logger.Logger,
+	publicURL string,
+) *ProfileAPIHandler {
+	return &ProfileAPIHandler{
+		ProfileAPI:          &v1api.ProfileAPI{},
+		getUseCase:          getUseCase,
+		updateUseCase:       updateUseCase,
+		uploadAvatarUseCase: uploadAvatarUseCase,
+		deleteAvatarUseCase: deleteAvatarUseCase,
+		avatarURLs:          avatarURLs,
+		logger:              logger,
+		publicURL:           strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// GetProfile returns the profile of the current user
+func (h *ProfileAPIHandler) GetProfile(c *gin.Context) {
+	profile, err := h.getUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.profileError(c, err, "Failed to get profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toProfile(profile))
+}
+
+// UpdateProfile applies the JSON Merge Patch in the body to the profile of the current user
+func (h *ProfileAPIHandler) UpdateProfile(c *gin.Context) {
+	// Decoding into pointers tells fields set to null, which are cleared, from missing fields
+	var fields map[string]*string
+	if err := c.ShouldBindJSON(&fields); err != nil {
+		h.logger.Warn("Invalid profile patch", "error", err.Error())
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+
+	patch := profileservice.Patch{
+		DisplayName: mergeField(fields, "displayName"),
+		Bio:         mergeField(fields, "bio"),
+		Locale:      mergeField(fields, "locale"),
+		Timezone:    mergeField(fields, "timezone"),
+	}
+	userID := middleware.CurrentUserID(c)
+	profile, err := h.updateUseCase.Call(c.Request.Context(), userID, patch)
+	if err != nil {
+		h.profileError(c, err, "Failed to update profile")
+		return
+	}
+
+	h.logger.Info("Profile updated", "user_id", userID)
+	c.JSON(http.StatusOK, h.toProfile(profile))
+}
+
+// UploadAvatar makes the image in the avatar field of the multipart body the avatar of the
+// current user. The body is limited by the route, see middleware.LimitBody.
+func (h *ProfileAPIHandler) UploadAvatar(c *gin.Context) {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, v1api.Error{Error: "Request too large", Message: avatarservice.ErrTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request format", Message: err.Error()})
+		return
+	}
+	image, err := file.Open()
+	if err != nil {
+		h.profileError(c, err, "Failed to read avatar")
+		return
+	}
+	defer image.Close()
+
+	userID := middleware.CurrentUserID(c)
+	profile, err := h.uploadAvatarUseCase.Call(c.Request.Context(), userID, image)
+	if err != nil {
+		h.profileError(c, err, "Failed to upload avatar")
+		return
+	}
+
+	h.logger.Info("Avatar uploaded", "user_id", userID)
+	c.JSON(http.StatusOK, h.toProfile(profile))
+}
+
+// DeleteAvatar removes the avatar of the current user
+func (h *ProfileAPIHandler) DeleteAvatar(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	if _, err := h.deleteAvatarUseCase.Call(c.Request.Context(), userID); err != nil {
+		h.profileError(c, err, "Failed to delete avatar")
+		return
+	}
+
+	h.logger.Info("Avatar deleted", "user_id", userID)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProfileAPIHandler) profileError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, profileservice.ErrInvalidDisplayName),
+		errors.Is(err, profileservice.ErrInvalidBio),
+		errors.Is(err, profileservice.ErrInvalidLocale),
+		errors.Is(err, profileservice.ErrInvalidTimezone),
+		errors.Is(err, avatarservice.ErrUnsupportedType),
+		errors.Is(err, avatarservice.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, v1api.Error{Error: "Invalid request", Message: err.Error()})
+	case errors.Is(err, avatarservice.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, v1api.Error{Error: "Request too large", Message: err.Error()})
+	default:
+		h.logger.Error(message, "error", err.Error(), "user_id", middleware.CurrentUserID(c))
+		c.JSON(http.StatusInternalServerError, v1api.Error{Error: "Internal server error", Message: "Internal server error"})
+	}
+}
+
+// mergeField returns the change a JSON Merge Patch makes to a string field: nil when the
+// field is missing and "" when it is null
+func mergeField(fields map[string]*string, name string) *string {
+	value, ok := fields[name]
+	if !ok {
+		return nil
+	}
+	if value == nil {
+		return new(string)
+	}
+	return value
+}
+
+func (h *ProfileAPIHandler) toProfile(profile *entity.UserProfile) v1api.Profile {
+	result := v1api.Profile{
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+	}
+	if profile.Avatar != "" {
+		result.Avatar = &v1api.Avatar{
+			Small:  h.avatarURL(profile, entity.AvatarSmall),
+			Medium: h.avatarURL(profile, entity.AvatarMedium),
+			Large:  h.avatarURL(profile, entity.AvatarLarge),
+		}
+	}
+	return result
+}
+
+// avatarURL signs the URL of a thumbnail, whose path is its blob key
+func (h *ProfileAPIHandler) avatarURL(profile *entity.UserProfile, size int) string {
+	path := "/" + profile.AvatarKey(size)
+	return h.publicURL + path + "?" + h.avatarURLs.Sign(path).Encode()
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	policyservice "example.com/internal/domain/service/policy"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+)
+
+// RoleAPIHandler extends the generated AuthRolesAPI with actual business logic
+type RoleAPIHandler struct {
+	*authapi.AuthRolesAPI
+	listRolesUseCase     authusecase.ListRolesUseCase
+	listUserRolesUseCase authusecase.ListUserRolesUseCase
+	assignUseCase        authusecase.AssignRoleUseCase
+	unassignUseCase      authusecase.UnassignRoleUseCase
+	logger               logger.Logger
+}
+
+// NewRoleAPIHandler creates a new role handler that extends the generated API
+func NewRoleAPIHandler(
+	listRolesUseCase authusecase.ListRolesUseCase,
+	listUserRolesUseCase authusecase.ListUserRolesUseCase,
+	assignUseCase authusecase.AssignRoleUseCase,
+	unassignUseCase authusecase.UnassignRoleUseCase,
+	logger logger.Logger,
+) *RoleAPIHandler {
+	return &RoleAPIHandler{
+		AuthRolesAPI:         &authapi.AuthRolesAPI{},
+		listRolesUseCase:     listRolesUseCase,
+		listUserRolesUseCase: listUserRolesUseCase,
+		assignUseCase:        assignUseCase,
+		unassignUseCase:      unassignUseCase,
+		logger:               logger,
+	}
+}
+
+// ListRoles lists every role with its permissions
+func (h *RoleAPIHandler) ListRoles(c *gin.Context) {
+	roles, err := h.listRolesUseCase.Call(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list roles", "error", err.Error())
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toRoleList(roles))
+}
+
+// ListUserRoles lists the roles of the user in the path
+func (h *RoleAPIHandler) ListUserRoles(c *gin.Context) {
+	userID := c.Param("userId")
+	roles, err := h.listUserRolesUseCase.Call(c.Request.Context(), userID)
+	if err != nil {
+		h.roleError(c, err, "Failed to list user roles", userID)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRoleList(roles))
+}
+
+// AssignRole gives the role in the path to the user in the path
+func (h *RoleAPIHandler) AssignRole(c *gin.Context) {
+	userID, role := c.Param("userId"), c.Param("role")
+	if err := h.assignUseCase.Call(c.Request.Context(), userID, role); err != nil {
+		h.roleError(c, err, "Failed to assign role", userID)
+		return
+	}
+
+	h.logger.Info("Role assigned", "user_id", userID, "role", role, "by", middleware.CurrentUserID(c))
+	c.Status(http.StatusNoContent)
+}
+
+// UnassignRole takes the role in the path from the user in the path
+func (h *RoleAPIHandler) UnassignRole(c *gin.Context) {
+	userID, role := c.Param("userId"), c.Param("role")
+	if err := h.unassignUseCase.Call(c.Request.Context(), userID, role); err != nil {
+		h.roleError(c, err, "Failed to remove role", userID)
+		return
+	}
+
+	h.logger.Info("Role removed", "user_id", userID, "role", role, "by", middleware.CurrentUserID(c))
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleAPIHandler) roleError(c *gin.Context, err error, message, userID string) {
+	switch {
+	case errors.Is(err, policyservice.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, authapi.Error{Error: "User not found"})
+	case errors.Is(err, policyservice.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, authapi.Error{Error: "Role not found"})
+	case errors.Is(err, policyservice.ErrRoleNotAssigned):
+		c.JSON(http.StatusNotFound, authapi.Error{Error: "Role not assigned", Message: err.Error()})
+	default:
+		h.logger.Error(message, "error", err.Error(), "user_id", userID)
+		c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+	}
+}
+
+func toRoleList(roles []*entity.Role) authapi.RoleList {
+	response := authapi.RoleList{Roles: make([]authapi.Role, len(roles))}
+	for i, role := range roles {
+		response.Roles[i] = authapi.Role{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.PermissionNames(),
+		}
+	}
+	return response
+}
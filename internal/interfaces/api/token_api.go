@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	tokenservice "example.com/internal/domain/service/token"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+)
+
+// tokenType is the OAuth 2.0 token type of access tokens
+const tokenType = "Bearer"
+
+// TokenAPIHandler extends the generated AuthTokenAPI with actual business logic
+type TokenAPIHandler struct {
+	*authapi.AuthTokenAPI
+	refreshUseCase authusecase.RefreshTokenUseCase
+	jwksUseCase    authusecase.GetJWKSUseCase
+	logger         logger.Logger
+}
+
+// NewTokenAPIHandler creates a new token handler that extends the generated API
+func NewTokenAPIHandler(
+	refreshUseCase authusecase.RefreshTokenUseCase,
+	jwksUseCase authusecase.GetJWKSUseCase,
+	logger logger.Logger,
+) *TokenAPIHandler {
+	return &TokenAPIHandler{
+		AuthTokenAPI:   &authapi.AuthTokenAPI{},
+		refreshUseCase: refreshUseCase,
+		jwksUseCase:    jwksUseCase,
+		logger:         logger,
+	}
+}
+
+// RefreshAccessToken exchanges a refresh token for a new access token and refresh token
+func (h *TokenAPIHandler) RefreshAccessToken(c *gin.Context) {
+	var req authapi.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid refresh request", "error", err.Error())
+		c.JSON(http.StatusBadRequest, authapi.Error{Error: "Invalid request format"})
+		return
+	}
+
+	pair, err := h.refreshUseCase.Call(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, tokenservice.ErrRefreshTokenReused):
+			h.logger.Warn("Refresh token reused, token family revoked")
+			c.JSON(http.StatusUnauthorized, authapi.Error{Error: "Invalid refresh token", Message: err.Error()})
+		case errors.Is(err, tokenservice.ErrInvalidRefreshToken):
+			c.JSON(http.StatusUnauthorized, authapi.Error{Error: "Invalid refresh token", Message: err.Error()})
+		default:
+			h.logger.Error("Failed to refresh token", "error", err.Error())
+			c.JSON(http.StatusInternalServerError, authapi.Error{Error: "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, authapi.TokenResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    tokenType,
+		ExpiresIn:    expiresIn(pair),
+	})
+}
+
+// GetJwks publishes the public keys verifying access tokens
+func (h *TokenAPIHandler) GetJwks(c *gin.Context) {
+	jwks := h.jwksUseCase.Call(c.Request.Context())
+
+	keys := make([]authapi.Jwk, len(jwks.Keys))
+	for i, key := range jwks.Keys {
+		keys[i] = authapi.Jwk{
+			Kty: key.KeyType,
+			Kid: key.KeyID,
+			Use: key.Use,
+			Alg: key.Algorithm,
+			Crv: key.Curve,
+			X:   key.X,
+			N:   key.N,
+			E:   key.E,
+		}
+	}
+
+	// Verifiers may cache the keys briefly; new keys are published before they sign
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, authapi.Jwks{Keys: keys})
+}
+
+// expiresIn is the remaining lifetime of the access token in seconds
+func expiresIn(pair *entity.TokenPair) int32 {
+	return int32(time.Until(pair.AccessTokenExpiresAt).Round(time.Second) / time.Second)
+}
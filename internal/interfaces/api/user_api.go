@@ -1,49 +1,60 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
 
 	v1api "example.com/gen/openapi/v1/go"
+	usernameservice "example.com/internal/domain/service/username"
+	userservice "example.com/internal/domain/service/v1"
 	userusecase "example.com/internal/domain/usecase/v1"
 	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
 )
 
 // UserAPIHandler extends the generated UserLoginAPIAPI with actual business logic
 type UserAPIHandler struct {
 	*v1api.UserLoginAPIAPI
-	userLookupUseCase userusecase.UserLookupUseCase
-	logger            logger.Logger
+	userLookupUseCase     userusecase.UserLookupUseCase
+	usernameLookupUseCase userusecase.UsernameLookupUseCase
+	logger                logger.Logger
 }
 
-// NewUserAPIHandler creates a new user API handler that extends the generated API
-func NewUserAPIHandler(userLookupUseCase userusecase.UserLookupUseCase, logger logger.Logger) *UserAPIHandler {
+// NewUserAPIHandler creates a new user API handler that extends the generated API; users are
+// only looked up by username when usernameLookupUseCase is not nil
+func NewUserAPIHandler(
+	userLookupUseCase userusecase.UserLookupUseCase,
+	usernameLookupUseCase userusecase.UsernameLookupUseCase,
+	logger logger.Logger,
+) *UserAPIHandler {
 	return &UserAPIHandler{
-		UserLoginAPIAPI:   &v1api.UserLoginAPIAPI{},
-		userLookupUseCase: userLookupUseCase,
-		logger:            logger,
+		UserLoginAPIAPI:       &v1api.UserLoginAPIAPI{},
+		userLookupUseCase:     userLookupUseCase,
+		usernameLookupUseCase: usernameLookupUseCase,
+		logger:                logger,
 	}
 }
 
-// UserLookup handles user lookup by email
+// UserLookup handles user lookup by email or username
 func (h *UserAPIHandler) UserLookup(c *gin.Context) {
-	email := c.Query("email")
-	if email == "" {
-		h.logger.Warn("Missing email parameter in lookup request")
-		c.JSON(http.StatusBadRequest, v1api.Error{Message: "Email parameter is required"})
+	email, username := c.Query("email"), c.Query("username")
+	if (email == "") == (username == "") {
+		h.logger.Warn("Lookup request without exactly one of email and username")
+		c.JSON(http.StatusBadRequest, v1api.Error{Message: "Exactly one of the email and username parameters is required"})
+		return
+	}
+	if username != "" {
+		h.lookupUsername(c, username)
 		return
 	}
 
-	user, err := h.userLookupUseCase.Call(c.Request.Context(), email)
+	user, err := h.userLookupUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c), email)
 	if err != nil {
 		h.logger.Warn("User lookup failed", "error", err.Error(), "email", email)
-
-		if err.Error() == "user not found" {
-			c.JSON(http.StatusNotFound, v1api.Error{Message: "User not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, v1api.Error{Message: "Internal server error"})
-		}
+		h.lookupError(c, err)
 		return
 	}
 
@@ -56,3 +67,34 @@ func (h *UserAPIHandler) UserLookup(c *gin.Context) {
 	h.logger.Info("User lookup successful", "email", email, "username", user.UserName)
 	c.JSON(http.StatusOK, response)
 }
+
+// lookupUsername looks the user up by username, redirecting to the lookup of their current
+// username when they left username by a rename
+func (h *UserAPIHandler) lookupUsername(c *gin.Context, username string) {
+	if h.usernameLookupUseCase == nil {
+		c.JSON(http.StatusBadRequest, v1api.Error{Message: "Lookup by username is not enabled"})
+		return
+	}
+
+	user, moved, err := h.usernameLookupUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c), username)
+	if err != nil {
+		h.logger.Warn("User lookup failed", "error", err.Error(), "username", username)
+		h.lookupError(c, err)
+		return
+	}
+	if moved {
+		location := url.URL{Path: c.Request.URL.Path, RawQuery: url.Values{"username": {user.UserName}}.Encode()}
+		c.Redirect(http.StatusTemporaryRedirect, location.String())
+		return
+	}
+
+	c.JSON(http.StatusOK, v1api.UserLookupResponse{Username: user.UserName, Email: user.Email})
+}
+
+func (h *UserAPIHandler) lookupError(c *gin.Context, err error) {
+	if errors.Is(err, userservice.ErrUserNotFound) || errors.Is(err, usernameservice.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, v1api.Error{Message: "User not found"})
+	} else {
+		c.JSON(http.StatusInternalServerError, v1api.Error{Message: "Internal server error"})
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/domain/entity"
+	usernameservice "example.com/internal/domain/service/username"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+)
+
+// UsernameAPIHandler extends the generated UsernameAPI with actual business logic
+type UsernameAPIHandler struct {
+	*v1api.UsernameAPI
+	getUseCase    userusecase.GetUsernameUseCase
+	changeUseCase userusecase.ChangeUsernameUseCase
+	logger        logger.Logger
+}
+
+// NewUsernameAPIHandler creates a new username handler
+func NewUsernameAPIHandler(
+	getUseCase userusecase.GetUsernameUseCase,
+	changeUseCase userusecase.ChangeUsernameUseCase,
+	logger logger.Logger,
+) *UsernameAPIHandler {
+	return &UsernameAPIHandler{
+		UsernameAPI:   &v1api.UsernameAPI{},
+		getUseCase:    getUseCase,
+		changeUseCase: changeUseCase,
+		logger:        logger,
+	}
+}
+
+// GetUsername returns the username of the current user and its changes
+func (h *UsernameAPIHandler) GetUsername(c *gin.Context) {
+	user, changes, err := h.getUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.usernameError(c, err, "Failed to get username")
+		return
+	}
+
+	c.JSON(http.StatusOK, usernameResponse(user, changes))
+}
+
+// ChangeUsername renames the current user
+func (h *UsernameAPIHandler) ChangeUsername(c *gin.Context) {
+	var req v1api.UsernameChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, v1api.Error{Message: "Invalid request format"})
+		return
+	}
+
+	user, changes, err := h.changeUseCase.Call(c.Request.Context(), middleware.CurrentUserID(c), req.Username)
+	if err != nil {
+		h.usernameError(c, err, "Failed to change username")
+		return
+	}
+
+	h.logger.Info("Username changed", "user_id", user.ID, "username", user.UserName)
+	c.JSON(http.StatusOK, usernameResponse(user, changes))
+}
+
+func (h *UsernameAPIHandler) usernameError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, security.ErrInvalidUsername):
+		response := v1api.Error{Error: "Username does not meet the policy", Message: err.Error()}
+		var policyErr *security.UsernamePolicyError
+		if errors.As(err, &policyErr) {
+			for _, violation := range policyErr.Violations {
+				response.Details = append(response.Details, v1api.FieldError{Field: "username", In: "body", Message: violation})
+			}
+		}
+		c.JSON(http.StatusBadRequest, response)
+	case errors.Is(err, usernameservice.ErrSameUsername):
+		c.JSON(http.StatusBadRequest, v1api.Error{
+			Error:   "Username unchanged",
+			Message: err.Error(),
+			Details: []v1api.FieldError{{Field: "username", In: "body", Message: "must differ from your current username"}},
+		})
+	case errors.Is(err, usernameservice.ErrUsernameTaken):
+		c.JSON(http.StatusConflict, v1api.Error{Error: "Username taken", Message: err.Error()})
+	case errors.Is(err, usernameservice.ErrRenameTooSoon):
+		c.JSON(http.StatusTooManyRequests, v1api.Error{Error: "Too many renames", Message: err.Error()})
+	case errors.Is(err, usernameservice.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, v1api.Error{Error: "Authentication required", Message: err.Error()})
+	default:
+		h.logger.Error(message, "error", err.Error(), "user_id", middleware.CurrentUserID(c))
+		c.JSON(http.StatusInternalServerError, v1api.Error{Error: "Internal server error", Message: "Internal server error"})
+	}
+}
+
+func usernameResponse(user *entity.User, changes []*entity.UserNameChange) v1api.Username {
+	response := v1api.Username{Username: user.UserName, Changes: []v1api.UsernameChange{}}
+	for _, change := range changes {
+		response.Changes = append(response.Changes, v1api.UsernameChange{
+			OldUsername:       change.OldUserName,
+			NewUsername:       change.NewUserName,
+			ChangedAt:         change.CreatedAt,
+			RedirectExpiresAt: change.RedirectExpiresAt,
+		})
+	}
+	return response
+}
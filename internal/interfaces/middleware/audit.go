@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+)
+
+// loginKey holds the login of the request, marked by LoggedIn for Audit to record
+const loginKey = "audit_login"
+
+// userAgentMaxLength bounds the user agents recorded, in characters
+const userAgentMaxLength = 255
+
+type login struct {
+	userID string
+	method string
+}
+
+// LoggedIn marks the request as the login of userID with method, such as "password" or
+// "oidc:google", whether it started a session or issued tokens
+func LoggedIn(c *gin.Context, userID, method string) {
+	c.Set(loginKey, login{userID: userID, method: method})
+}
+
+// Audit records the logins marked by LoggedIn with the address and user agent of the client,
+// once the request is served. Failing to record a login does not fail the login.
+func Audit(record authusecase.RecordLoginUseCase, log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		value, ok := c.Get(loginKey)
+		if !ok {
+			return
+		}
+		l := value.(login)
+		userAgent := []rune(c.Request.UserAgent())
+		if len(userAgent) > userAgentMaxLength {
+			userAgent = userAgent[:userAgentMaxLength]
+		}
+		if err := record.Call(c.Request.Context(), l.userID, l.method, c.ClientIP(), string(userAgent)); err != nil {
+			log.Error("Failed to record login", "error", err.Error(), "user_id", l.userID)
+		}
+	}
+}
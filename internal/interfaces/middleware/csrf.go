@@ -1,31 +1,69 @@
 package middleware
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"net/http"
+	"slices"
 
+	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	csrf "github.com/utrack/gin-csrf"
+
+	"example.com/pkg/security"
+)
+
+const (
+	// csrfSaltKey is the session value every token of the session is derived from
+	csrfSaltKey = "csrfSalt"
+	// csrfRingKey and csrfTokenKey hold the key ring and the issued token in the gin context
+	csrfRingKey  = "csrfKeyRing"
+	csrfTokenKey = "csrfToken"
 )
 
-func CSRF(secret string) gin.HandlerFunc {
-	return csrf.Middleware(csrf.Options{
-		Secret: secret,
-		ErrorFunc: func(c *gin.Context) {
+var csrfSafeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+
+// CSRF rejects unsafe requests that do not carry a token derived from the session salt with
+// one of the active keys of ring. Tokens are issued with the current key, so a rotation only
+// invalidates them once the previous key leaves its grace period. Requests authenticated by
+// BearerAuth are exempt, as are exemptPaths, which must not rely on cookies.
+func CSRF(ring *security.KeyRing, exemptPaths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(csrfRingKey, ring)
+
+		if slices.Contains(csrfSafeMethods, c.Request.Method) || BearerAuthenticated(c) ||
+			slices.Contains(exemptPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		if !validCSRFToken(c, csrfRequestToken(c)) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "CSRF token validation failed"})
 			c.Abort()
-		},
-	})
+			return
+		}
+
+		c.Next()
+	}
 }
 
 func CSRFToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := csrf.GetToken(c)
+		token := csrfToken(c)
 		c.JSON(http.StatusOK, gin.H{"token": token})
 	}
 }
 
+// RequireXSRF requires the X-XSRF-TOKEN header on every method; requests authenticated by
+// BearerAuth are exempt
 func RequireXSRF() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if BearerAuthenticated(c) {
+			c.Next()
+			return
+		}
+
 		headerToken := c.GetHeader("X-XSRF-TOKEN")
 		if headerToken == "" {
 			c.JSON(http.StatusForbidden, gin.H{"error": "X-XSRF-TOKEN header is required"})
@@ -33,8 +71,7 @@ func RequireXSRF() gin.HandlerFunc {
 			return
 		}
 
-		cookieToken := csrf.GetToken(c)
-		if headerToken != cookieToken {
+		if !validCSRFToken(c, headerToken) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "XSRF token mismatch"})
 			c.Abort()
 			return
@@ -43,3 +80,69 @@ func RequireXSRF() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// csrfToken returns the session's token under the current key, creating the salt on first use
+func csrfToken(c *gin.Context) string {
+	if token, ok := c.Get(csrfTokenKey); ok {
+		return token.(string)
+	}
+
+	session := sessions.Default(c)
+	salt, ok := session.Get(csrfSaltKey).(string)
+	if !ok || salt == "" {
+		salt = newCSRFSalt()
+		session.Set(csrfSaltKey, salt)
+		_ = session.Save()
+	}
+
+	ring := c.MustGet(csrfRingKey).(*security.KeyRing)
+	token := tokenize(ring.Current().Secret, salt)
+	c.Set(csrfTokenKey, token)
+
+	return token
+}
+
+func validCSRFToken(c *gin.Context, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	salt, ok := sessions.Default(c).Get(csrfSaltKey).(string)
+	if !ok || salt == "" {
+		return false
+	}
+
+	ring := c.MustGet(csrfRingKey).(*security.KeyRing)
+	for _, key := range ring.Active() {
+		if hmac.Equal([]byte(token), []byte(tokenize(key.Secret, salt))) {
+			return true
+		}
+	}
+	return false
+}
+
+func csrfRequestToken(c *gin.Context) string {
+	for _, header := range []string{"X-CSRF-TOKEN", "X-XSRF-TOKEN"} {
+		if token := c.GetHeader(header); token != "" {
+			return token
+		}
+	}
+	// Multipart bodies are uploads, which are only read once the route has limited their
+	// size; parsing them here would read any amount of data before that
+	if c.ContentType() == "multipart/form-data" {
+		return c.Query("_csrf")
+	}
+	return c.Request.FormValue("_csrf")
+}
+
+func newCSRFSalt() string {
+	salt := make([]byte, 16)
+	_, _ = rand.Read(salt)
+	return base64.RawURLEncoding.EncodeToString(salt)
+}
+
+func tokenize(secret []byte, salt string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(salt))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
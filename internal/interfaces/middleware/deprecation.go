@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"example.com/internal/infrastructure/metrics"
+)
+
+// DeprecationPolicy describes how a deprecated route is announced and retired
+type DeprecationPolicy struct {
+	DeprecatedAt time.Time
+	Sunset       time.Time
+	// DocsURL points at a human readable migration notice
+	DocsURL string
+	// RejectAfterSunset answers 410 Gone once Sunset has passed
+	RejectAfterSunset bool
+}
+
+// Deprecated announces deprecation of a route via the Deprecation (RFC 9745), Sunset (RFC 8594)
+// and Link headers, and counts its usage per client
+func Deprecated(policy DeprecationPolicy, successor string, usage *metrics.CounterVec) gin.HandlerFunc {
+	var links []string
+	if successor != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+	}
+	if policy.DocsURL != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, policy.DocsURL))
+	}
+	link := strings.Join(links, ", ")
+
+	return func(c *gin.Context) {
+		usage.Inc(c.FullPath(), ClientID(c))
+
+		if !policy.DeprecatedAt.IsZero() {
+			c.Header("Deprecation", fmt.Sprintf("@%d", policy.DeprecatedAt.Unix()))
+		} else {
+			c.Header("Deprecation", "true")
+		}
+		if !policy.Sunset.IsZero() {
+			c.Header("Sunset", policy.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if link != "" {
+			c.Header("Link", link)
+		}
+
+		if policy.RejectAfterSunset && !policy.Sunset.IsZero() && time.Now().After(policy.Sunset) {
+			c.JSON(http.StatusGone, gin.H{
+				"error": "Route has been retired",
+				"message": fmt.Sprintf("This route was retired on %s; use %s instead",
+					policy.Sunset.UTC().Format(time.DateOnly), successor),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ClientID identifies the calling client for usage metrics: the X-Client-ID header when set,
+// otherwise the product token of the User-Agent
+func ClientID(c *gin.Context) string {
+	if id := strings.TrimSpace(c.GetHeader("X-Client-ID")); id != "" {
+		return truncate(id, 64)
+	}
+
+	userAgent := strings.TrimSpace(c.GetHeader("User-Agent"))
+	if userAgent == "" {
+		return "unknown"
+	}
+	product, _, _ := strings.Cut(userAgent, " ")
+	name, _, _ := strings.Cut(product, "/")
+	return truncate(name, 64)
+}
+
+func truncate(s string, limit int) string {
+	if len(s) > limit {
+		return s[:limit]
+	}
+	return s
+}
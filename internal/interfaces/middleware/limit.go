@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitBody rejects requests announcing a body of more than limit bytes and stops reading
+// bodies of unknown length after limit bytes. It must run before anything reads the body,
+// such as request validation.
+func LimitBody(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "Request too large",
+				"message": "The body must not exceed " + strconv.FormatInt(limit, 10) + " bytes",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
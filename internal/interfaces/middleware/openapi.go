@@ -0,0 +1,270 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+
+	apispec "example.com/api"
+)
+
+var defineFormatsOnce sync.Once
+
+// FieldError describes a single request or response value that does not match the spec
+type FieldError struct {
+	Field   string `json:"field"`
+	In      string `json:"in"`
+	Message string `json:"message"`
+}
+
+type OpenAPIValidatorOptions struct {
+	// ValidateResponses buffers every response and rejects those that drift from the spec.
+	// It is meant for tests and local development, not for production traffic.
+	ValidateResponses bool
+}
+
+// OpenAPIValidator validates requests (and optionally responses) against the operations
+// declared in one or more OpenAPI documents
+type OpenAPIValidator struct {
+	routes  map[string]*routers.Route
+	options OpenAPIValidatorOptions
+}
+
+// LoadOpenAPIDocument parses and validates one of the specifications embedded in example.com/api
+func LoadOpenAPIDocument(name string) (*openapi3.T, error) {
+	data, err := apispec.Spec(name)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document %s: %w", name, err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document %s: %w", name, err)
+	}
+
+	return doc, nil
+}
+
+// NewOpenAPIValidator indexes every operation of the given documents by operationId
+func NewOpenAPIValidator(options OpenAPIValidatorOptions, docs ...*openapi3.T) (*OpenAPIValidator, error) {
+	defineFormatsOnce.Do(func() {
+		openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+		// Images and archives are served as is; only their presence is checked against the spec
+		openapi3filter.RegisterBodyDecoder("image/jpeg", openapi3filter.FileBodyDecoder)
+		openapi3filter.RegisterBodyDecoder("image/png", openapi3filter.FileBodyDecoder)
+		openapi3filter.RegisterBodyDecoder("application/zip", openapi3filter.FileBodyDecoder)
+	})
+
+	routes := make(map[string]*routers.Route)
+	for _, doc := range docs {
+		for path, pathItem := range doc.Paths.Map() {
+			for method, operation := range pathItem.Operations() {
+				if operation.OperationID == "" {
+					return nil, fmt.Errorf("operation %s %s has no operationId", method, path)
+				}
+				if _, exists := routes[operation.OperationID]; exists {
+					return nil, fmt.Errorf("duplicate operationId %q", operation.OperationID)
+				}
+
+				routes[operation.OperationID] = &routers.Route{
+					Spec:      doc,
+					Path:      path,
+					PathItem:  pathItem,
+					Method:    method,
+					Operation: operation,
+				}
+			}
+		}
+	}
+
+	return &OpenAPIValidator{
+		routes:  routes,
+		options: options,
+	}, nil
+}
+
+// Operation returns a middleware validating requests against the named operation.
+// It panics for unknown operation IDs so that a typo fails at route registration time.
+func (v *OpenAPIValidator) Operation(operationID string) gin.HandlerFunc {
+	route, ok := v.routes[operationID]
+	if !ok {
+		panic(fmt.Sprintf("openapi: unknown operationId %q", operationID))
+	}
+
+	return func(c *gin.Context) {
+		pathParams := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			pathParams[param.Key] = param.Value
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError: true,
+				// Security requirements are enforced by the session and XSRF middleware
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Request validation failed",
+				"message": "Request does not match the API specification",
+				"details": fieldErrors(err),
+			})
+			c.Abort()
+			return
+		}
+
+		if !v.options.ValidateResponses {
+			c.Next()
+			return
+		}
+
+		v.validateResponse(c, input)
+	}
+}
+
+func (v *OpenAPIValidator) validateResponse(c *gin.Context, input *openapi3filter.RequestValidationInput) {
+	original := c.Writer
+	recorder := &bufferedResponseWriter{ResponseWriter: original, status: http.StatusOK}
+	c.Writer = recorder
+
+	c.Next()
+
+	c.Writer = original
+
+	err := openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 recorder.status,
+		Header:                 recorder.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+		},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Response validation failed",
+			"message": fmt.Sprintf("%s %s returned a response that does not match the API specification",
+				input.Route.Method, input.Route.Path),
+			"details": fieldErrors(err),
+		})
+		return
+	}
+
+	original.WriteHeader(recorder.status)
+	_, _ = original.Write(recorder.body.Bytes())
+}
+
+// fieldErrors flattens kin-openapi validation errors into a list of field errors
+func fieldErrors(err error) []FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var result []FieldError
+		for _, nested := range e {
+			result = append(result, fieldErrors(nested)...)
+		}
+		return result
+	case *openapi3filter.RequestError:
+		in, field := "body", ""
+		if e.Parameter != nil {
+			in, field = e.Parameter.In, e.Parameter.Name
+		}
+		if e.Err == nil {
+			return []FieldError{{Field: field, In: in, Message: e.Reason}}
+		}
+
+		nested := fieldErrors(e.Err)
+		for i := range nested {
+			nested[i].In = in
+			if nested[i].Field == "" {
+				nested[i].Field = field
+			}
+		}
+		return nested
+	case *openapi3filter.ResponseError:
+		if e.Err == nil {
+			return []FieldError{{In: "response", Message: e.Reason}}
+		}
+
+		nested := fieldErrors(e.Err)
+		for i := range nested {
+			nested[i].In = "response"
+		}
+		return nested
+	case *openapi3.SchemaError:
+		return []FieldError{{
+			Field:   strings.Join(e.JSONPointer(), "."),
+			Message: e.Reason,
+		}}
+	}
+
+	var parseErr *openapi3filter.ParseError
+	if errors.As(err, &parseErr) {
+		return []FieldError{{Message: parseErr.Error()}}
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return fieldErrors(schemaErr)
+	}
+
+	return []FieldError{{Message: err.Error()}}
+}
+
+// bufferedResponseWriter holds the response back until it has been validated
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	status  int
+	written bool
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.written = true
+}
+
+func (w *bufferedResponseWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedResponseWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedResponseWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedResponseWriter) Written() bool {
+	return w.written
+}
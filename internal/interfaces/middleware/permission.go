@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authusecase "example.com/internal/domain/usecase/auth"
+)
+
+// permissionCheckKey holds the authusecase.CheckPermissionUseCase installed by Permissions
+const permissionCheckKey = "permission_check"
+
+// Permissions lets RequirePermission check permissions with check on every route
+func Permissions(check authusecase.CheckPermissionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(permissionCheckKey, check)
+		c.Next()
+	}
+}
+
+// RequirePermission requires an authenticated user holding permission through one of their
+// roles. It applies to sessions and bearer tokens alike; RequireScope additionally restricts
+// personal access tokens. Without Permissions installed every request is refused.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authenticate(c) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+
+		allowed := false
+		if check, ok := c.Get(permissionCheckKey); ok {
+			var err error
+			allowed, err = check.(authusecase.CheckPermissionUseCase).Call(c.Request.Context(), CurrentUserID(c), permission)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+				c.Abort()
+				return
+			}
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied", "message": "This route requires the " + permission + " permission"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,38 +1,241 @@
 package middleware
 
 import (
+	"crypto/sha256"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
+	gsessions "github.com/gorilla/sessions"
+
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/pkg/security"
 )
 
-func Session(secret string) gin.HandlerFunc {
-	store := cookie.NewStore([]byte(secret))
+// Session stores the session in a cookie signed with the current key of signing and, when
+// encryption is not nil, encrypted with the current key of encryption. Cookies written with
+// a previous key are read until that key leaves its grace period. secure restricts the
+// cookie to HTTPS.
+func Session(signing, encryption *security.KeyRing, secure bool) gin.HandlerFunc {
+	store := &keyRingStore{
+		signing:    signing,
+		encryption: encryption,
+	}
 	store.Options(sessions.Options{
 		MaxAge:   86400 * 7, // 7 days
 		Path:     "/",
 		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
+		Secure:   secure,
 		SameSite: http.SameSiteLaxMode,
 	})
 
 	return sessions.Sessions("session_id", store)
 }
 
-func RequireAuth() gin.HandlerFunc {
+// UserIDKey holds the ID of the authenticated user, both in the session and in the gin context
+const UserIDKey = "user_id"
+
+// sessionStartedAtKey holds the time the session was started, in Unix nanoseconds
+const sessionStartedAtKey = "started_at"
+
+// sessionTenantKey holds the tenant the session was started for. Cookies of a parent domain
+// reach every tenant, so a session only authenticates requests acting for its own tenant.
+const sessionTenantKey = "tenant_id"
+
+// StartSession records userID as the authenticated user of the session. Starting the session
+// of a user again renews it, so that it survives the revocation of the user's older sessions.
+func StartSession(c *gin.Context, userID string) error {
+	session := sessions.Default(c)
+	session.Set(UserIDKey, userID)
+	session.Set(sessionTenantKey, CurrentTenant(c))
+	session.Set(sessionStartedAtKey, time.Now().UnixNano())
+	return session.Save()
+}
+
+// ValidateSession ends sessions that validate rejects, such as sessions started before the
+// user changed their password, before any route reads the user from the session. Sessions
+// from before start times were recorded count as started at the zero time.
+func ValidateSession(validate authusecase.ValidateSessionUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		session := sessions.Default(c)
-		userID := session.Get("user_id")
+		userID, ok := session.Get(UserIDKey).(string)
+		if !ok || userID == "" || !sessionOfTenant(c, session) {
+			c.Next()
+			return
+		}
+
+		var startedAt time.Time
+		if nanos, ok := session.Get(sessionStartedAtKey).(int64); ok {
+			startedAt = time.Unix(0, nanos)
+		}
+
+		valid, err := validate.Call(c.Request.Context(), userID, startedAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.Abort()
+			return
+		}
+		if !valid {
+			session.Clear()
+			if err := session.Save(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentUserID returns the user authenticated by RequireAuth, RequireSessionAuth or BearerAuth
+func CurrentUserID(c *gin.Context) string {
+	return c.GetString(UserIDKey)
+}
 
-		if userID == nil {
+// RequireAuth requires a user authenticated by a bearer token or the session
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authenticate(c) {
 			c.JSON(401, gin.H{"error": "Authentication required"})
 			c.Abort()
 			return
 		}
 
-		c.Set("user_id", userID)
 		c.Next()
 	}
 }
+
+// authenticate sets the context user from the session unless a bearer token already did,
+// reporting whether the request has a user
+func authenticate(c *gin.Context) bool {
+	if CurrentUserID(c) != "" {
+		return true
+	}
+
+	userID := SessionUserID(c)
+	if userID == "" {
+		return false
+	}
+
+	c.Set(UserIDKey, userID)
+	return true
+}
+
+// SessionUserID returns the user of the session if it was started for the tenant the request
+// acts for. Unlike CurrentUserID it never returns a user authenticated by a bearer token.
+func SessionUserID(c *gin.Context) string {
+	session := sessions.Default(c)
+	userID, _ := session.Get(UserIDKey).(string)
+	if userID == "" || !sessionOfTenant(c, session) {
+		return ""
+	}
+	return userID
+}
+
+// sessionOfTenant reports whether session was started for the tenant the request acts for.
+// Sessions from before tenants were recorded belong to no tenant.
+func sessionOfTenant(c *gin.Context, session sessions.Session) bool {
+	id, _ := session.Get(sessionTenantKey).(string)
+	return id == CurrentTenant(c)
+}
+
+// RequireSessionAuth requires a user authenticated by the session, rejecting bearer tokens.
+// It guards routes that must not be reachable by scripts, such as managing the tokens themselves.
+func RequireSessionAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if BearerAuthenticated(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Session required", "message": "This route cannot be called with a bearer token"})
+			c.Abort()
+			return
+		}
+
+		RequireAuth()(c)
+	}
+}
+
+// keyRingStore is a cookie store whose codecs follow the active keys of its key rings
+type keyRingStore struct {
+	signing     *security.KeyRing
+	encryption  *security.KeyRing
+	store       cookie.Store
+	fingerprint string
+	options     sessions.Options
+	mu          sync.Mutex
+}
+
+func (s *keyRingStore) Get(r *http.Request, name string) (*gsessions.Session, error) {
+	return s.current().Get(r, name)
+}
+
+func (s *keyRingStore) New(r *http.Request, name string) (*gsessions.Session, error) {
+	return s.current().New(r, name)
+}
+
+func (s *keyRingStore) Save(r *http.Request, w http.ResponseWriter, session *gsessions.Session) error {
+	return s.current().Save(r, w, session)
+}
+
+func (s *keyRingStore) Options(options sessions.Options) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.options = options
+	s.store = nil
+}
+
+// current returns a cookie store for the keys active right now, rebuilding it when a
+// previous key has expired
+func (s *keyRingStore) current() cookie.Store {
+	signing := s.signing.Active()
+	var encryption []security.Key
+	if s.encryption != nil {
+		encryption = s.encryption.Active()
+	}
+
+	ids := make([]string, 0, len(signing)+len(encryption)+1)
+	for _, key := range signing {
+		ids = append(ids, key.ID)
+	}
+	ids = append(ids, "|")
+	for _, key := range encryption {
+		ids = append(ids, key.ID)
+	}
+	fingerprint := strings.Join(ids, ",")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.store == nil || s.fingerprint != fingerprint {
+		s.store = cookie.NewStore(keyPairs(signing, encryption)...)
+		s.store.Options(s.options)
+		s.fingerprint = fingerprint
+	}
+	return s.store
+}
+
+// keyPairs combines every active signing key with every active encryption key, current keys
+// first, so that cookies written under any combination of still active keys can be decoded
+func keyPairs(signing, encryption []security.Key) [][]byte {
+	if len(encryption) == 0 {
+		pairs := make([][]byte, 0, 2*len(signing))
+		for _, key := range signing {
+			pairs = append(pairs, key.Secret, nil)
+		}
+		return pairs
+	}
+
+	pairs := make([][]byte, 0, 2*len(signing)*len(encryption))
+	for _, sign := range signing {
+		for _, encrypt := range encryption {
+			// AES needs a 16, 24 or 32 byte key whatever the length of the configured secret
+			blockKey := sha256.Sum256(encrypt.Secret)
+			pairs = append(pairs, sign.Secret, blockKey[:])
+		}
+	}
+	return pairs
+}
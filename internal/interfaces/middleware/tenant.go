@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"example.com/pkg/tenant"
+)
+
+// ResolveTenant makes requests act for the tenant their host or header is addressed to.
+// Requests for unknown tenants are rejected; requests naming no tenant may still get one
+// from the claim of their access token in BearerAuth.
+func ResolveTenant(resolver *tenant.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := resolver.Resolve(c.Request)
+		switch {
+		case errors.Is(err, tenant.ErrUnknown):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown tenant", "message": "The request is addressed to a tenant that is not served"})
+			c.Abort()
+			return
+		case err != nil:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Ambiguous tenant", "message": err.Error()})
+			c.Abort()
+			return
+		}
+
+		if id != "" {
+			setTenant(c, id)
+		}
+		c.Next()
+	}
+}
+
+// RequireTenant rejects requests below one of prefixes that act for no tenant. Other routes,
+// such as the OpenAPI documents, serve every tenant alike.
+func RequireTenant(prefixes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if CurrentTenant(c) != "" || !hasAnyPrefix(c.Request.URL.Path, prefixes) {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant required", "message": "Address the request to a tenant by its host or header"})
+		c.Abort()
+	}
+}
+
+// CurrentTenant returns the tenant the request acts for, which is empty without tenancy
+func CurrentTenant(c *gin.Context) string {
+	id, _ := tenant.FromContext(c.Request.Context())
+	return id
+}
+
+// bindTenant makes a request authenticated by a credential of tenant act for that tenant,
+// reporting false when the request already acts for another one
+func bindTenant(c *gin.Context, id string) bool {
+	current := CurrentTenant(c)
+	if current == "" && id != "" {
+		setTenant(c, id)
+		return true
+	}
+	return current == id
+}
+
+func setTenant(c *gin.Context, id string) {
+	c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), id))
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+// Package authz decides whether a subject may perform an action on a resource by evaluating
+// declarative policies against the attributes of both, see ParsePolicies.
+package authz
+
+import (
+	"context"
+	"errors"
+	"maps"
+)
+
+// ErrDenied is returned by Enforce when the policies deny a request
+var ErrDenied = errors.New("access denied")
+
+// Entity is the subject or the resource of a request. Policies read its ID as "<role>.id",
+// its type as "<role>.type" and its attributes as "<role>.<attribute>", where role is
+// "subject" or "resource".
+type Entity struct {
+	Attributes map[string]any
+	Type       string
+	ID         string
+}
+
+// Request asks whether Subject may perform Action on Resource
+type Request struct {
+	Subject  Entity
+	Resource Entity
+	Action   string
+}
+
+// Decision is the outcome of a request
+type Decision struct {
+	// Policy names the policy that decided, empty when no policy applied and access is
+	// denied by default
+	Policy  string
+	Allowed bool
+}
+
+// Authorizer decides requests
+type Authorizer interface {
+	Authorize(ctx context.Context, req Request) (Decision, error)
+}
+
+// SubjectAttributes loads attributes of the subject of a request, such as its roles, so that
+// callers only need to know who is asking. Attributes set on the request take precedence.
+type SubjectAttributes func(ctx context.Context, subject Entity) (map[string]any, error)
+
+// ResourceAttributes loads attributes of the resource of a request, such as the organizations
+// a user is a member of, so that callers only need to know what is asked for. Attributes set
+// on the request take precedence.
+type ResourceAttributes func(ctx context.Context, resource Entity) (map[string]any, error)
+
+// DecisionLogger records every decision, for audits
+type DecisionLogger interface {
+	LogDecision(ctx context.Context, req Request, decision Decision)
+}
+
+// Config tunes an Authorizer
+type Config struct {
+	// Attributes adds attributes to the subject of every request unless it is nil
+	Attributes SubjectAttributes
+	// Resources adds attributes to the resource of every request unless it is nil
+	Resources ResourceAttributes
+	// Log records every decision unless it is nil
+	Log DecisionLogger
+}
+
+type authorizer struct {
+	policies *PolicySet
+	config   Config
+}
+
+// NewAuthorizer returns an Authorizer evaluating policies
+func NewAuthorizer(policies *PolicySet, config Config) Authorizer {
+	return &authorizer{policies: policies, config: config}
+}
+
+func (a *authorizer) Authorize(ctx context.Context, req Request) (Decision, error) {
+	if a.config.Attributes != nil {
+		attributes, err := loadAttributes(ctx, a.config.Attributes, req.Subject)
+		if err != nil {
+			return Decision{}, err
+		}
+		req.Subject.Attributes = attributes
+	}
+	if a.config.Resources != nil {
+		attributes, err := loadAttributes(ctx, a.config.Resources, req.Resource)
+		if err != nil {
+			return Decision{}, err
+		}
+		req.Resource.Attributes = attributes
+	}
+
+	decision := a.policies.Evaluate(req)
+	if a.config.Log != nil {
+		a.config.Log.LogDecision(ctx, req, decision)
+	}
+	return decision, nil
+}
+
+// loadAttributes returns the attributes load finds for entity, overridden by its own
+func loadAttributes(
+	ctx context.Context, load func(context.Context, Entity) (map[string]any, error), entity Entity,
+) (map[string]any, error) {
+	loaded, err := load(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	attributes := make(map[string]any, len(loaded)+len(entity.Attributes))
+	maps.Copy(attributes, loaded)
+	maps.Copy(attributes, entity.Attributes)
+	return attributes, nil
+}
+
+// Enforce authorizes req and returns ErrDenied unless it is allowed
+func Enforce(ctx context.Context, authorizer Authorizer, req Request) error {
+	decision, err := authorizer.Authorize(ctx, req)
+	if err != nil {
+		return err
+	}
+	if !decision.Allowed {
+		return ErrDenied
+	}
+	return nil
+}
+
+// Logger receives the entries of LogDecisions
+type Logger interface {
+	Info(msg string, args ...any)
+}
+
+type logDecisions struct {
+	logger Logger
+}
+
+// LogDecisions returns a DecisionLogger writing one entry per decision to logger
+func LogDecisions(logger Logger) DecisionLogger {
+	return &logDecisions{logger: logger}
+}
+
+func (l *logDecisions) LogDecision(_ context.Context, req Request, decision Decision) {
+	l.logger.Info("Authorization decision",
+		"allowed", decision.Allowed,
+		"policy", decision.Policy,
+		"subject_type", req.Subject.Type,
+		"subject_id", req.Subject.ID,
+		"action", req.Action,
+		"resource_type", req.Resource.Type,
+		"resource_id", req.Resource.ID,
+	)
+}
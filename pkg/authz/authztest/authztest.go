@@ -0,0 +1,64 @@
+// Package authztest asserts the decisions of an authz.Authorizer in tests
+package authztest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"example.com/pkg/authz"
+)
+
+// Matrix lists the expected decisions of one action for every pair of its subjects and
+// resources: the pairs in Allowed must be allowed and every other pair denied
+type Matrix struct {
+	Subjects  map[string]authz.Entity
+	Resources map[string]authz.Entity
+	Action    string
+	// Allowed holds pairs as "<subject name> -> <resource name>"
+	Allowed []string
+}
+
+// AssertMatrix authorizes every pair of m with authorizer, reporting each unexpected decision
+// together with the policy that made it
+func AssertMatrix(t testing.TB, authorizer authz.Authorizer, m Matrix) {
+	t.Helper()
+
+	pairs := make(map[string]bool, len(m.Allowed))
+	for _, pair := range m.Allowed {
+		pairs[pair] = true
+	}
+
+	for _, subjectName := range sortedKeys(m.Subjects) {
+		for _, resourceName := range sortedKeys(m.Resources) {
+			pair := subjectName + " -> " + resourceName
+			want := pairs[pair]
+			delete(pairs, pair)
+
+			decision, err := authorizer.Authorize(context.Background(), authz.Request{
+				Subject:  m.Subjects[subjectName],
+				Action:   m.Action,
+				Resource: m.Resources[resourceName],
+			})
+			switch {
+			case err != nil:
+				t.Errorf("%s %s: %v", m.Action, pair, err)
+			case decision.Allowed != want:
+				t.Errorf("%s %s: allowed = %t, want %t (policy %q)", m.Action, pair, decision.Allowed, want, decision.Policy)
+			}
+		}
+	}
+
+	for pair := range pairs {
+		t.Errorf("%s %s: allowed pair names an unknown subject or resource", m.Action, pair)
+	}
+}
+
+func sortedKeys(entities map[string]authz.Entity) []string {
+	keys := make([]string, 0, len(entities))
+	for key := range entities {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,209 @@
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// operators maps the operators of conditions to their comparison
+var operators = map[string]func(left, right any) bool{
+	"==":         equal,
+	"!=":         func(left, right any) bool { return !equal(left, right) },
+	"in":         func(left, right any) bool { return has(right, left) },
+	"contains":   has,
+	"intersects": intersects,
+}
+
+// condition is a parsed comparison, such as subject.id == resource.id
+type condition struct {
+	left, right operand
+	compare     func(left, right any) bool
+}
+
+func (c condition) holds(req Request) bool {
+	left, ok := c.left.value(req)
+	if !ok {
+		return false
+	}
+	right, ok := c.right.value(req)
+	if !ok {
+		return false
+	}
+	return c.compare(left, right)
+}
+
+func parseCondition(expression string) (condition, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return condition{}, err
+	}
+	if len(tokens) != 3 {
+		return condition{}, errors.New(`expected "<operand> <operator> <operand>"`)
+	}
+
+	compare, ok := operators[tokens[1]]
+	if !ok {
+		return condition{}, fmt.Errorf("unknown operator %q", tokens[1])
+	}
+	left, err := parseOperand(tokens[0])
+	if err != nil {
+		return condition{}, err
+	}
+	right, err := parseOperand(tokens[2])
+	if err != nil {
+		return condition{}, err
+	}
+	return condition{left: left, right: right, compare: compare}, nil
+}
+
+// tokenize splits expression at white space outside of double quotes
+func tokenize(expression string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+	for _, r := range expression {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t'):
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated string")
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
+
+// operand is an attribute path or, when path is nil, a literal
+type operand struct {
+	literal any
+	path    []string
+}
+
+func parseOperand(token string) (operand, error) {
+	switch {
+	case strings.HasPrefix(token, `"`):
+		if len(token) < 2 || !strings.HasSuffix(token, `"`) {
+			return operand{}, fmt.Errorf("malformed string %s", token)
+		}
+		return operand{literal: token[1 : len(token)-1]}, nil
+	case token == "true" || token == "false":
+		return operand{literal: token == "true"}, nil
+	case token == "action":
+		return operand{path: []string{token}}, nil
+	case strings.HasPrefix(token, "subject.") || strings.HasPrefix(token, "resource."):
+		path := strings.Split(token, ".")
+		for _, part := range path {
+			if part == "" {
+				return operand{}, fmt.Errorf("malformed attribute %q", token)
+			}
+		}
+		return operand{path: path}, nil
+	}
+
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return operand{literal: number}, nil
+	}
+	return operand{}, fmt.Errorf("unknown operand %q: use subject.<attribute>, resource.<attribute>, action or a literal", token)
+}
+
+// value resolves the operand for req, reporting whether the request has the attribute
+func (o operand) value(req Request) (any, bool) {
+	if o.path == nil {
+		return o.literal, true
+	}
+	if o.path[0] == "action" {
+		return req.Action, true
+	}
+
+	entity := req.Subject
+	if o.path[0] == "resource" {
+		entity = req.Resource
+	}
+	if len(o.path) == 2 {
+		switch o.path[1] {
+		case "id":
+			return entity.ID, entity.ID != ""
+		case "type":
+			return entity.Type, entity.Type != ""
+		}
+	}
+
+	var value any = entity.Attributes
+	for _, key := range o.path[1:] {
+		attributes, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = attributes[key]; !ok {
+			return nil, false
+		}
+	}
+	return normalize(value), true
+}
+
+// normalize converts numbers to float64 and lists to []any so that values of different Go
+// types compare as they read in a policy
+func normalize(value any) any {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Slice, reflect.Array:
+		list := make([]any, v.Len())
+		for i := range list {
+			list[i] = normalize(v.Index(i).Interface())
+		}
+		return list
+	default:
+		return value
+	}
+}
+
+func equal(left, right any) bool {
+	return reflect.DeepEqual(left, right)
+}
+
+// intersects reports whether the lists left and right share an element
+func intersects(left, right any) bool {
+	elements, ok := left.([]any)
+	if !ok {
+		return false
+	}
+	for _, element := range elements {
+		if has(right, element) {
+			return true
+		}
+	}
+	return false
+}
+
+// has reports whether list is a list with element in it
+func has(list, element any) bool {
+	elements, ok := list.([]any)
+	if !ok {
+		return false
+	}
+	for _, candidate := range elements {
+		if equal(candidate, element) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package authz
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Wildcard matches every action or resource type
+const Wildcard = "*"
+
+// Effect is what a policy decides for the requests it matches
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// ErrInvalidPolicy is wrapped by the errors of ParsePolicies
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// Policy decides requests for one of Actions on a resource of one of Resources whose
+// Conditions all hold. See ParsePolicies for the format.
+type Policy struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Effect      Effect   `yaml:"effect"`
+	Actions     []string `yaml:"actions"`
+	Resources   []string `yaml:"resources"`
+	Conditions  []string `yaml:"conditions"`
+	conditions  []condition
+}
+
+func (p *Policy) matches(req Request) bool {
+	if !matchesName(p.Actions, req.Action) || !matchesName(p.Resources, req.Resource.Type) {
+		return false
+	}
+	for _, condition := range p.conditions {
+		if !condition.holds(req) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesName(names []string, name string) bool {
+	return slices.Contains(names, Wildcard) || slices.Contains(names, name)
+}
+
+// PolicySet evaluates requests against its policies. A request is allowed when an allow
+// policy matches it and no deny policy does; requests no policy matches are denied.
+type PolicySet struct {
+	policies []*Policy
+}
+
+// ParsePolicies reads policies from YAML:
+//
+//	policies:
+//	  - name: read-own-user
+//	    description: Users may read their own account
+//	    effect: allow               # or deny
+//	    actions: [users:read]       # "*" matches every action
+//	    resources: [user]           # resource types, "*" matches every type
+//	    conditions:                 # all must hold, none means always
+//	      - subject.id == resource.id
+//
+// A condition compares two operands with ==, !=, in (the left value is an element of the
+// right list), contains (the left list has the right value as an element) or intersects
+// (both lists share an element). Operands are attributes such as subject.id, resource.type,
+// subject.permissions or action, or literals: "quoted strings", numbers, true and false.
+// A condition on an attribute the request lacks does not hold.
+func ParsePolicies(r io.Reader) (*PolicySet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		Policies []*Policy `yaml:"policies"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+
+	names := make(map[string]bool, len(document.Policies))
+	for i, policy := range document.Policies {
+		if err := policy.compile(); err != nil {
+			return nil, fmt.Errorf("%w: policy %d (%s): %w", ErrInvalidPolicy, i+1, policy.Name, err)
+		}
+		if names[policy.Name] {
+			return nil, fmt.Errorf("%w: policy name %q is used twice", ErrInvalidPolicy, policy.Name)
+		}
+		names[policy.Name] = true
+	}
+
+	return &PolicySet{policies: document.Policies}, nil
+}
+
+func (p *Policy) compile() error {
+	switch {
+	case p.Name == "":
+		return errors.New("name is required")
+	case p.Effect != Allow && p.Effect != Deny:
+		return fmt.Errorf("effect must be %q or %q", Allow, Deny)
+	case len(p.Actions) == 0:
+		return errors.New("actions are required, use \"*\" for every action")
+	case len(p.Resources) == 0:
+		return errors.New("resources are required, use \"*\" for every type")
+	}
+
+	p.conditions = make([]condition, len(p.Conditions))
+	for i, expression := range p.Conditions {
+		condition, err := parseCondition(expression)
+		if err != nil {
+			return fmt.Errorf("condition %q: %w", expression, err)
+		}
+		p.conditions[i] = condition
+	}
+	return nil
+}
+
+// Policies returns the policies of the set in evaluation order
+func (s *PolicySet) Policies() []*Policy {
+	return slices.Clone(s.policies)
+}
+
+// Evaluate decides req: deny policies take precedence over allow policies
+func (s *PolicySet) Evaluate(req Request) Decision {
+	allowedBy := ""
+	for _, policy := range s.policies {
+		if !policy.matches(req) {
+			continue
+		}
+		if policy.Effect == Deny {
+			return Decision{Policy: policy.Name}
+		}
+		if allowedBy == "" {
+			allowedBy = policy.Name
+		}
+	}
+	return Decision{Policy: allowedBy, Allowed: allowedBy != ""}
+}
@@ -0,0 +1,226 @@
+// Package client is the first-party Go SDK for the auth and v1 APIs.
+//
+// It wraps the clients generated from api/auth/openapi.yaml and api/v1/openapi.yaml
+// with the plumbing every caller needs: a session cookie jar, XSRF token handling
+// and retries of transient failures.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"sync"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	authclient "example.com/gen/openapi/auth/client"
+	v1client "example.com/gen/openapi/v1/client"
+)
+
+const (
+	xsrfHeader    = "X-XSRF-TOKEN"
+	csrfTokenPath = "/csrf-token"
+)
+
+// APIError is returned for every non-2xx response
+type APIError struct {
+	Details    []authclient.FieldError
+	Message    string
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+// Client talks to the API on behalf of a single session
+type Client struct {
+	// Auth and V1 expose the generated clients for operations without a convenience method
+	Auth *authclient.ClientWithResponses
+	V1   *v1client.ClientWithResponses
+
+	httpClient   *http.Client
+	baseURL      string
+	apiToken     string
+	csrfToken    string
+	maxRetries   int
+	retryBackoff time.Duration
+	mu           sync.Mutex
+}
+
+type Option func(*Client)
+
+// WithHTTPClient uses the given client; a cookie jar is added when it has none
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times idempotent requests are retried after a transient failure
+// and the initial backoff, which doubles after every attempt
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithAPIToken authenticates every request with a personal access token instead of a session;
+// no XSRF token is fetched then
+func WithAPIToken(token string) Option {
+	return func(c *Client) {
+		c.apiToken = token
+	}
+}
+
+// New creates a client for the API served at baseURL, e.g. "https://api.example.com"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	c := &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		maxRetries:   2,
+		retryBackoff: 200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if c.httpClient.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		c.httpClient.Jar = jar
+	}
+
+	doer := &sessionDoer{client: c}
+
+	authClient, err := authclient.NewClientWithResponses(c.baseURL, authclient.WithHTTPClient(doer))
+	if err != nil {
+		return nil, err
+	}
+	v1Client, err := v1client.NewClientWithResponses(c.baseURL+"/api/v1", v1client.WithHTTPClient(doer))
+	if err != nil {
+		return nil, err
+	}
+
+	c.Auth = authClient
+	c.V1 = v1Client
+
+	return c, nil
+}
+
+// Signup registers a new user; username may be empty
+func (c *Client) Signup(ctx context.Context, email, password, username string) (*authclient.SignupResponse, error) {
+	body := authclient.SignupRequest{
+		Email:    openapi_types.Email(email),
+		Password: password,
+	}
+	if username != "" {
+		body.Username = &username
+	}
+
+	res, err := c.Auth.UserSignupWithResponse(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON201 == nil {
+		return nil, apiError(res.StatusCode(), res.Body)
+	}
+
+	return res.JSON201, nil
+}
+
+// Login authenticates the session held by this client
+func (c *Client) Login(ctx context.Context, email, password string) (*authclient.LoginResponse, error) {
+	res, err := c.Auth.UserLoginWithResponse(ctx, authclient.LoginRequest{
+		Email:    email,
+		Password: password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, apiError(res.StatusCode(), res.Body)
+	}
+
+	return res.JSON200, nil
+}
+
+// LookupUser returns the user registered with the given email
+func (c *Client) LookupUser(ctx context.Context, email string) (*v1client.UserLookupResponse, error) {
+	address := openapi_types.Email(email)
+	return c.lookupUser(ctx, &v1client.UserLookupParams{Email: &address})
+}
+
+// LookupUsername returns the user with the given username, following the redirect of a
+// username its user recently left
+func (c *Client) LookupUsername(ctx context.Context, username string) (*v1client.UserLookupResponse, error) {
+	return c.lookupUser(ctx, &v1client.UserLookupParams{Username: &username})
+}
+
+func (c *Client) lookupUser(ctx context.Context, params *v1client.UserLookupParams) (*v1client.UserLookupResponse, error) {
+	res, err := c.V1.UserLookupWithResponse(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, apiError(res.StatusCode(), res.Body)
+	}
+
+	return res.JSON200, nil
+}
+
+// token returns the cached XSRF token, fetching one when refresh is set or none is cached
+func (c *Client) token(ctx context.Context, refresh bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.csrfToken != "" && !refresh {
+		return c.csrfToken, nil
+	}
+
+	res, err := c.Auth.GetCsrfTokenWithResponse(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch XSRF token: %w", err)
+	}
+	if res.JSON200 == nil {
+		return "", apiError(res.StatusCode(), res.Body)
+	}
+
+	c.csrfToken = res.JSON200.Token
+	return c.csrfToken, nil
+}
+
+func apiError(status int, body []byte) error {
+	apiErr := &APIError{StatusCode: status, Message: http.StatusText(status)}
+
+	var parsed authclient.Error
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		switch {
+		case parsed.Message != nil && *parsed.Message != "":
+			apiErr.Message = *parsed.Message
+		case parsed.Error != "":
+			apiErr.Message = parsed.Error
+		}
+		if parsed.Details != nil {
+			apiErr.Details = *parsed.Details
+		}
+	}
+
+	return apiErr
+}
+
+// IsStatus reports whether err is an APIError with the given status code
+func IsStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
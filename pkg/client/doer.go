@@ -0,0 +1,125 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sessionDoer sits between the generated clients and the HTTP client. It attaches the
+// XSRF token, or the API token when one is configured, refreshes the XSRF token once when
+// the server rejects it and retries transient failures.
+type sessionDoer struct {
+	client *Client
+}
+
+func (d *sessionDoer) Do(req *http.Request) (*http.Response, error) {
+	needsToken := req.URL.Path != csrfTokenPath && d.client.apiToken == ""
+	if d.client.apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+d.client.apiToken)
+	}
+	if needsToken {
+		token, err := d.client.token(req.Context(), false)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(xsrfHeader, token)
+	}
+
+	refreshed := false
+	attempt := 0
+	for {
+		res, err := d.client.httpClient.Do(req)
+
+		switch {
+		case needsToken && !refreshed && err == nil && isXSRFRejection(res):
+			refreshed = true
+			token, tokenErr := d.client.token(req.Context(), true)
+			if tokenErr != nil {
+				return nil, tokenErr
+			}
+			req.Header.Set(xsrfHeader, token)
+		case attempt < d.client.maxRetries && isRetryable(req, res, err):
+			if res != nil {
+				_, _ = io.Copy(io.Discard, res.Body)
+				_ = res.Body.Close()
+			}
+			if err := sleep(req.Context(), d.client.retryBackoff<<attempt); err != nil {
+				return nil, err
+			}
+			attempt++
+		default:
+			return res, err
+		}
+
+		if err := rewindBody(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryable retries network errors and gateway failures of idempotent requests only,
+// so that a signup is never submitted twice
+func isRetryable(req *http.Request, res *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isXSRFRejection detects the 403 responses of the CSRF and XSRF middleware,
+// leaving the body readable for the caller
+func isXSRFRejection(res *http.Response) bool {
+	if res.StatusCode != http.StatusForbidden {
+		return false
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	text := string(body)
+	return strings.Contains(text, "CSRF") || strings.Contains(text, "XSRF")
+}
+
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMessage = errors.New("invalid email message")
+
+// Message is a plain text email to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Logger receives the messages of a LogSender
+type Logger interface {
+	Info(msg string, args ...any)
+}
+
+type logSender struct {
+	logger Logger
+}
+
+// NewLogSender returns a Sender that writes messages to logger instead of delivering them.
+// Messages may carry login codes, so it is only meant for development.
+func NewLogSender(logger Logger) Sender {
+	return &logSender{logger: logger}
+}
+
+func (s *logSender) Send(_ context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	s.logger.Info("Email not sent, no SMTP server is configured", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// validate rejects line breaks in headers, which would let a recipient or subject inject headers
+func (m Message) validate() error {
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return fmt.Errorf("%w: line break in header", ErrInvalidMessage)
+	}
+	if _, err := mail.ParseAddress(m.To); err != nil {
+		return fmt.Errorf("%w: recipient: %v", ErrInvalidMessage, err)
+	}
+	return nil
+}
+
+// encode renders the message as a MIME document with a quoted-printable UTF-8 body
+func (m Message) encode(from string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	// Line breaks of the body are written as CRLF in text mode
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(m.Body)); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// implicitTLSPort is the submission port that expects TLS from the first byte (RFC 8314)
+const implicitTLSPort = 465
+
+// sendTimeout bounds a delivery when the context has no deadline
+const sendTimeout = 30 * time.Second
+
+// SMTPConfig is the submission server messages are relayed through
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username and Password authenticate with PLAIN, which net/smtp only allows over TLS or to localhost
+	Username string
+	Password string
+	// From is the sender address, optionally with a display name
+	From string
+}
+
+type smtpSender struct {
+	cfg  SMTPConfig
+	from *mail.Address
+	now  func() time.Time
+}
+
+// NewSMTPSender returns a Sender relaying through an SMTP server. Port 465 uses implicit TLS;
+// other ports upgrade with STARTTLS whenever the server offers it.
+func NewSMTPSender(cfg SMTPConfig) (Sender, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.From, err)
+	}
+	return &smtpSender{cfg: cfg, from: from, now: time.Now}, nil
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	to, _ := mail.ParseAddress(msg.To)
+
+	data, err := msg.encode(s.from.String(), s.now())
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sendTimeout)
+		defer cancel()
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := s.deliver(client, to.Address, data); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *smtpSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	var err error
+	if s.cfg.Port == implicitTLSPort {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("smtp handshake: %w", err)
+	}
+
+	if s.cfg.Port != implicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("smtp starttls: %w", err)
+			}
+		}
+	}
+	return client, nil
+}
+
+func (s *smtpSender) deliver(client *smtp.Client, to string, data []byte) error {
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("smtp sender rejected: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp recipient rejected: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return w.Close()
+}
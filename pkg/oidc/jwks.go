@@ -0,0 +1,145 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// refreshInterval limits how often the key set is fetched again for an unknown key ID
+const refreshInterval = time.Minute
+
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+// keySet caches the provider's signing keys and refetches them when a token names an
+// unknown key, which is how providers roll their keys
+type keySet struct {
+	fetched    time.Time
+	httpClient *http.Client
+	keys       map[string]crypto.PublicKey
+	url        string
+	mu         sync.Mutex
+}
+
+func newKeySet(httpClient *http.Client, url string) *keySet {
+	return &keySet{httpClient: httpClient, url: url}
+}
+
+func (s *keySet) verify(ctx context.Context, alg, kid string, input, signature []byte) error {
+	key, err := s.key(ctx, kid)
+	if err != nil {
+		return err
+	}
+
+	switch public := key.(type) {
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(input)
+		if alg == "RS256" && rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(input)
+		if alg == "ES256" && len(signature) == 64 && ecdsa.Verify(public, digest[:],
+			new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if alg == "EdDSA" && ed25519.Verify(public, input, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+}
+
+func (s *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(s.fetched) < refreshInterval {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, s.httpClient, s.url, &document); err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch signing keys: %v", ErrInvalidToken, err)
+	}
+
+	s.keys = make(map[string]crypto.PublicKey, len(document.Keys))
+	for _, k := range document.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err == nil {
+			s.keys[k.KeyID] = key
+		}
+	}
+	s.fetched = time.Now()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+}
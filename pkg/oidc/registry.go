@@ -0,0 +1,38 @@
+package oidc
+
+// ProviderInfo describes a registered provider to clients, e.g. for login buttons
+type ProviderInfo struct {
+	Name        string
+	DisplayName string
+}
+
+// Registry holds the providers users can sign in with, keyed by name
+type Registry struct {
+	providers map[string]*Provider
+	info      []ProviderInfo
+}
+
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]*Provider)}
+}
+
+// Register adds provider under name; displayName defaults to name
+func (r *Registry) Register(name, displayName string, provider *Provider) {
+	if displayName == "" {
+		displayName = name
+	}
+	if _, ok := r.providers[name]; !ok {
+		r.info = append(r.info, ProviderInfo{Name: name, DisplayName: displayName})
+	}
+	r.providers[name] = provider
+}
+
+func (r *Registry) Get(name string) (*Provider, bool) {
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// Providers lists the registered providers in registration order
+func (r *Registry) Providers() []ProviderInfo {
+	return r.info
+}
@@ -0,0 +1,216 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1" // #nosec G505 - breach corpora are published as SHA-1 hashes, nothing is protected with it
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// A breach dataset file is a header, the sorted entries and an index of the entries by prefix:
+//
+//	header   "PWBR" followed by the format version and three zero bytes
+//	entries  8 bytes per hash, bytes 2 to 9 of its SHA-1 digest, sorted
+//	index    2^20+1 little-endian uint32, entry i of prefix p is at index[p] <= i < index[p+1]
+//
+// The prefix is the first 20 bits (5 hex digits) of the digest, as in k-anonymity range
+// datasets. The 80 bits kept per hash make false positives negligible even for a billion
+// hashes, while the file takes 8 bytes per hash plus a 4 MiB index. Lookups read the entries
+// of one prefix, a few kilobytes, with a single ReadAt.
+const (
+	breachPrefixes  = 1 << 20
+	breachEntrySize = 8
+	breachIndexSize = (breachPrefixes + 1) * 4
+)
+
+var breachHeader = []byte{'P', 'W', 'B', 'R', 1, 0, 0, 0}
+
+var (
+	ErrInvalidBreachDataset = errors.New("invalid breach dataset")
+	ErrUnsortedBreachHashes = errors.New("breach hashes must be added in ascending order")
+)
+
+// PasswordBreachChecker tells whether a password is known from data breaches
+type PasswordBreachChecker interface {
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// BreachDataset is a PasswordBreachChecker reading a dataset written by BreachDatasetWriter.
+// Only the index is held in memory; it is safe for concurrent use.
+type BreachDataset struct {
+	data   io.ReaderAt
+	closer io.Closer
+	index  []uint32
+}
+
+// OpenBreachDataset opens the dataset file at path; Close releases it
+func OpenBreachDataset(path string) (*BreachDataset, error) {
+	file, err := os.Open(path) // #nosec G304 - the path comes from the configuration
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	dataset, err := NewBreachDataset(file, info.Size())
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dataset.closer = file
+	return dataset, nil
+}
+
+// NewBreachDataset reads the index of the size bytes long dataset in data
+func NewBreachDataset(data io.ReaderAt, size int64) (*BreachDataset, error) {
+	if size < int64(len(breachHeader))+breachIndexSize {
+		return nil, fmt.Errorf("%w: truncated", ErrInvalidBreachDataset)
+	}
+	header := make([]byte, len(breachHeader))
+	if _, err := data.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header, breachHeader) {
+		return nil, fmt.Errorf("%w: unknown header", ErrInvalidBreachDataset)
+	}
+
+	raw := make([]byte, breachIndexSize)
+	if _, err := data.ReadAt(raw, size-breachIndexSize); err != nil {
+		return nil, err
+	}
+	index := make([]uint32, breachPrefixes+1)
+	for i := range index {
+		index[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		if i > 0 && index[i] < index[i-1] {
+			return nil, fmt.Errorf("%w: corrupt index", ErrInvalidBreachDataset)
+		}
+	}
+	entries := int64(index[breachPrefixes])
+	if index[0] != 0 || int64(len(breachHeader))+entries*breachEntrySize+breachIndexSize != size {
+		return nil, fmt.Errorf("%w: size does not match the index", ErrInvalidBreachDataset)
+	}
+
+	return &BreachDataset{data: data, index: index}, nil
+}
+
+// Len returns the number of hashes in the dataset
+func (d *BreachDataset) Len() int {
+	return int(d.index[breachPrefixes])
+}
+
+func (d *BreachDataset) Breached(_ context.Context, password string) (bool, error) {
+	return d.Contains(sha1.Sum([]byte(password))) // #nosec G401 - see the import
+}
+
+// Contains reports whether the SHA-1 digest is in the dataset
+func (d *BreachDataset) Contains(digest [sha1.Size]byte) (bool, error) {
+	prefix, entry := breachKey(digest)
+	lo, hi := d.index[prefix], d.index[prefix+1]
+	if lo == hi {
+		return false, nil
+	}
+
+	block := make([]byte, int(hi-lo)*breachEntrySize)
+	if _, err := d.data.ReadAt(block, int64(len(breachHeader))+int64(lo)*breachEntrySize); err != nil {
+		return false, err
+	}
+	n := len(block) / breachEntrySize
+	i := sort.Search(n, func(i int) bool {
+		return binary.BigEndian.Uint64(block[i*breachEntrySize:]) >= entry
+	})
+	return i < n && binary.BigEndian.Uint64(block[i*breachEntrySize:]) == entry, nil
+}
+
+// Close releases the file of a dataset opened with OpenBreachDataset
+func (d *BreachDataset) Close() error {
+	if d.closer == nil {
+		return nil
+	}
+	return d.closer.Close()
+}
+
+// BreachDatasetWriter streams digests in ascending order into a dataset, so that datasets of
+// any size are written without holding them in memory
+type BreachDatasetWriter struct {
+	w      *bufio.Writer
+	counts []uint32
+	last   [10]byte
+	n      uint32
+}
+
+// NewBreachDatasetWriter writes the header of a dataset to w
+func NewBreachDatasetWriter(w io.Writer) (*BreachDatasetWriter, error) {
+	buffered := bufio.NewWriter(w)
+	if _, err := buffered.Write(breachHeader); err != nil {
+		return nil, err
+	}
+	return &BreachDatasetWriter{w: buffered, counts: make([]uint32, breachPrefixes)}, nil
+}
+
+// Add appends a SHA-1 digest. Digests must come in ascending order; repeated digests are
+// skipped.
+func (w *BreachDatasetWriter) Add(digest [sha1.Size]byte) error {
+	var key [10]byte
+	copy(key[:], digest[:10])
+	if w.n > 0 {
+		switch bytes.Compare(key[:], w.last[:]) {
+		case 0:
+			return nil
+		case -1:
+			return fmt.Errorf("%w: %X after %X", ErrUnsortedBreachHashes, digest[:10], w.last)
+		}
+	}
+	if w.n == math.MaxUint32 {
+		return fmt.Errorf("%w: more than %d hashes", ErrInvalidBreachDataset, uint32(math.MaxUint32))
+	}
+
+	prefix, entry := breachKey(digest)
+	var buf [breachEntrySize]byte
+	binary.BigEndian.PutUint64(buf[:], entry)
+	if _, err := w.w.Write(buf[:]); err != nil {
+		return err
+	}
+	w.counts[prefix]++
+	w.last = key
+	w.n++
+	return nil
+}
+
+// Len returns the number of hashes added so far
+func (w *BreachDatasetWriter) Len() int {
+	return int(w.n)
+}
+
+// Close writes the index and flushes the dataset; it does not close the underlying writer
+func (w *BreachDatasetWriter) Close() error {
+	var buf [4]byte
+	var offset uint32
+	for _, count := range w.counts {
+		binary.LittleEndian.PutUint32(buf[:], offset)
+		if _, err := w.w.Write(buf[:]); err != nil {
+			return err
+		}
+		offset += count
+	}
+	binary.LittleEndian.PutUint32(buf[:], offset)
+	if _, err := w.w.Write(buf[:]); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+// breachKey splits a digest into its 20 bit prefix and the 8 bytes stored for it
+func breachKey(digest [sha1.Size]byte) (prefix int, entry uint64) {
+	prefix = int(digest[0])<<12 | int(digest[1])<<4 | int(digest[2])>>4
+	return prefix, binary.BigEndian.Uint64(digest[2:10])
+}
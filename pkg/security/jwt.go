@@ -0,0 +1,309 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Signing algorithms supported by JWTIssuer, named as in RFC 7518 and RFC 8037
+const (
+	AlgEdDSA = "EdDSA"
+	AlgRS256 = "RS256"
+)
+
+// Token types of the typ header. Verify only accepts access tokens, so that an ID token
+// handed to a client can never be replayed as an access token (RFC 9068).
+const (
+	accessTokenType = "at+jwt"
+	idTokenType     = "JWT"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing
+const minRSABits = 2048
+
+var (
+	ErrInvalidJWT         = errors.New("invalid token")
+	ErrJWTExpired         = errors.New("token has expired")
+	ErrUnknownJWTKey      = errors.New("token was signed with an unknown key")
+	ErrNoSigningKeys      = errors.New("at least one signing key is required")
+	ErrUnsupportedKeyType = errors.New("signing keys must be Ed25519 or RSA private keys in PKCS #8 PEM")
+	ErrWeakRSAKey         = errors.New("RSA signing keys need at least 2048 bits")
+)
+
+// SigningKey is a private key with the key ID ("kid") that identifies it in tokens and the JWKS
+type SigningKey struct {
+	Signer    crypto.Signer
+	ID        string
+	Algorithm string
+}
+
+// ParseSigningKey reads an Ed25519 or RSA private key from a PKCS #8 PEM block
+func ParseSigningKey(id string, pemData []byte) (SigningKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("%w: key %s is not PEM encoded", ErrUnsupportedKeyType, id)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("%w: key %s: %v", ErrUnsupportedKeyType, id, err)
+	}
+
+	switch key := parsed.(type) {
+	case ed25519.PrivateKey:
+		return SigningKey{ID: id, Algorithm: AlgEdDSA, Signer: key}, nil
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSABits {
+			return SigningKey{}, fmt.Errorf("%w: key %s", ErrWeakRSAKey, id)
+		}
+		return SigningKey{ID: id, Algorithm: AlgRS256, Signer: key}, nil
+	default:
+		return SigningKey{}, fmt.Errorf("%w: key %s", ErrUnsupportedKeyType, id)
+	}
+}
+
+// Claims are the registered claims of an access token and the tenant it acts for
+type Claims struct {
+	Issuer  string `json:"iss"`
+	Subject string `json:"sub"`
+	ID      string `json:"jti"`
+	// Tenant is empty for tokens issued without tenancy
+	Tenant    string `json:"tid,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// JWK is the public half of a signing key as published in the JWKS (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+// JWTIssuer signs short-lived access tokens with the first of its keys and verifies tokens
+// signed with any of them, so a new key can be put first while tokens signed with the
+// previous one are still valid. Every key is published in the JWKS.
+type JWTIssuer struct {
+	now    func() time.Time
+	issuer string
+	keys   []SigningKey
+	ttl    time.Duration
+}
+
+// NewJWTIssuer creates an issuer for tokens valid for ttl, with keys ordered from current to oldest
+func NewJWTIssuer(issuer string, keys []SigningKey, ttl time.Duration) (*JWTIssuer, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateKeyID, key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	return &JWTIssuer{
+		now:    time.Now,
+		issuer: issuer,
+		keys:   keys,
+		ttl:    ttl,
+	}, nil
+}
+
+// Issuer is the iss claim of every token signed by i
+func (i *JWTIssuer) Issuer() string {
+	return i.issuer
+}
+
+// TTL is how long issued access tokens are valid
+func (i *JWTIssuer) TTL() time.Duration {
+	return i.ttl
+}
+
+// Issue signs an access token for subject of tenant, which is empty without tenancy
+func (i *JWTIssuer) Issue(subject, tenant string) (string, Claims, error) {
+	now := i.now()
+	claims := Claims{
+		Issuer:    i.issuer,
+		Subject:   subject,
+		Tenant:    tenant,
+		ID:        uuid.NewString(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(i.ttl).Unix(),
+	}
+
+	token, err := i.signToken(accessTokenType, claims)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	return token, claims, nil
+}
+
+// Sign signs claims as an ID token with the current key. The caller sets every claim,
+// including iss and exp; Verify rejects the result as an access token.
+func (i *JWTIssuer) Sign(claims any) (string, error) {
+	return i.signToken(idTokenType, claims)
+}
+
+func (i *JWTIssuer) signToken(tokenType string, claims any) (string, error) {
+	key := i.keys[0]
+	header, err := encodeSegment(jwtHeader{Algorithm: key.Algorithm, KeyID: key.ID, Type: tokenType})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := header + "." + payload
+	signature, err := sign(key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks the signature, issuer and expiry of token and returns its claims
+func (i *JWTIssuer) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidJWT
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Type != accessTokenType {
+		return nil, ErrInvalidJWT
+	}
+
+	key, ok := i.key(header.KeyID)
+	if !ok {
+		return nil, ErrUnknownJWTKey
+	}
+	// The algorithm is taken from the key, never from the token, so "none" or an HMAC
+	// signed with the public key cannot be smuggled in
+	if header.Algorithm != key.Algorithm {
+		return nil, ErrInvalidJWT
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !verify(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidJWT
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidJWT
+	}
+	if claims.Issuer != i.issuer || claims.Subject == "" {
+		return nil, ErrInvalidJWT
+	}
+	if !i.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrJWTExpired
+	}
+
+	return &claims, nil
+}
+
+// JWKS returns the public keys of every signing key
+func (i *JWTIssuer) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(i.keys))}
+	for _, key := range i.keys {
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch public := key.Signer.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+// WithClock replaces the clock used to issue and expire tokens
+func (i *JWTIssuer) WithClock(now func() time.Time) *JWTIssuer {
+	i.now = now
+	return i
+}
+
+func (i *JWTIssuer) key(id string) (SigningKey, bool) {
+	for _, key := range i.keys {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return SigningKey{}, false
+}
+
+func sign(key SigningKey, input []byte) ([]byte, error) {
+	if key.Algorithm == AlgEdDSA {
+		return key.Signer.Sign(rand.Reader, input, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(input)
+	return key.Signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+func verify(key SigningKey, input, signature []byte) bool {
+	switch public := key.Signer.Public().(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(public, input, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+func encodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,127 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmptyKeyRing      = errors.New("key ring has no keys")
+	ErrInvalidKeySpec    = errors.New("key must be written as <id>:<secret>[@<retired-at RFC 3339>]")
+	ErrDuplicateKeyID    = errors.New("duplicate key id")
+	ErrCurrentKeyRetired = errors.New("the current (first) key cannot be retired")
+	ErrMissingRetirement = errors.New("previous keys need a retirement time")
+)
+
+// Key is a named secret. Retired is when the key stopped being the current one; it is zero
+// for the current key.
+type Key struct {
+	Retired time.Time
+	ID      string
+	Secret  []byte
+}
+
+// KeyRing is an ordered set of keys: the first one signs or encrypts, the others are still
+// accepted for verification until their grace period after retirement has passed. This lets
+// secrets be rotated without invalidating every session at once.
+type KeyRing struct {
+	now   func() time.Time
+	keys  []Key
+	grace time.Duration
+}
+
+// NewKeyRing validates keys, ordered from current to oldest
+func NewKeyRing(keys []Key, grace time.Duration) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, ErrEmptyKeyRing
+	}
+	if !keys[0].Retired.IsZero() {
+		return nil, fmt.Errorf("%w: %s", ErrCurrentKeyRetired, keys[0].ID)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateKeyID, key.ID)
+		}
+		seen[key.ID] = true
+
+		if i > 0 && key.Retired.IsZero() {
+			return nil, fmt.Errorf("%w: %s", ErrMissingRetirement, key.ID)
+		}
+	}
+
+	return &KeyRing{
+		now:   time.Now,
+		keys:  keys,
+		grace: grace,
+	}, nil
+}
+
+// ParseKeyRing builds a key ring from specs as accepted by ParseKey
+func ParseKeyRing(specs []string, grace time.Duration) (*KeyRing, error) {
+	keys := make([]Key, 0, len(specs))
+	for _, spec := range specs {
+		key, err := ParseKey(spec)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyRing(keys, grace)
+}
+
+// ParseKey parses "<id>:<secret>" or, for a previous key, "<id>:<secret>@<retired-at>"
+// with the retirement time in RFC 3339
+func ParseKey(spec string) (Key, error) {
+	id, secret, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok || id == "" {
+		return Key{}, ErrInvalidKeySpec
+	}
+
+	key := Key{ID: id}
+	if at := strings.LastIndex(secret, "@"); at >= 0 {
+		retired, err := time.Parse(time.RFC3339, secret[at+1:])
+		if err != nil {
+			return Key{}, fmt.Errorf("%w: key %s has an invalid retirement time", ErrInvalidKeySpec, id)
+		}
+		key.Retired = retired
+		secret = secret[:at]
+	}
+	if secret == "" {
+		return Key{}, fmt.Errorf("%w: key %s has no secret", ErrInvalidKeySpec, id)
+	}
+	key.Secret = []byte(secret)
+
+	return key, nil
+}
+
+// Current is the key used to sign or encrypt
+func (r *KeyRing) Current() Key {
+	return r.keys[0]
+}
+
+// Active returns the current key followed by every previous key still within its grace period
+func (r *KeyRing) Active() []Key {
+	now := r.now()
+	active := []Key{r.keys[0]}
+	for _, key := range r.keys[1:] {
+		if now.Before(key.Retired.Add(r.grace)) {
+			active = append(active, key)
+		}
+	}
+	return active
+}
+
+// Keys returns every key in the ring, including expired ones
+func (r *KeyRing) Keys() []Key {
+	return r.keys
+}
+
+// WithClock replaces the clock used to expire previous keys
+func (r *KeyRing) WithClock(now func() time.Time) *KeyRing {
+	r.now = now
+	return r
+}
@@ -0,0 +1,149 @@
+package security
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BcryptMaxPasswordBytes is the length beyond which bcrypt ignores the rest of a password
+const BcryptMaxPasswordBytes = 72
+
+// minPersonalLength is the length from which a username or email part counts as personal
+// information, so that short names like "al" do not reject every password containing them
+const minPersonalLength = 3
+
+var ErrWeakPassword = errors.New("password does not meet the policy")
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PasswordRules configures a PasswordPolicy
+type PasswordRules struct {
+	MinLength int
+	// MaxLength is in bytes and should not exceed BcryptMaxPasswordBytes
+	MaxLength int
+	// MinCharClasses is the number of classes out of lowercase letters, uppercase letters,
+	// digits and symbols a password has to mix
+	MinCharClasses int
+	// Banned lists passwords rejected in addition to the built-in list of common passwords
+	Banned []string
+}
+
+// PasswordPolicy decides which passwords users may choose
+type PasswordPolicy struct {
+	rules  PasswordRules
+	banned map[string]struct{}
+}
+
+// PasswordPolicyError lists every rule a password breaks and matches ErrWeakPassword
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error() + ": " + strings.Join(e.Violations, "; ")
+}
+
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// NewPasswordPolicy returns a policy enforcing rules
+func NewPasswordPolicy(rules PasswordRules) *PasswordPolicy {
+	banned := make(map[string]struct{})
+	// Reading from a string cannot fail
+	common, _ := ParsePasswordList(strings.NewReader(commonPasswords))
+	for _, password := range common {
+		banned[strings.ToLower(password)] = struct{}{}
+	}
+	for _, password := range rules.Banned {
+		banned[strings.ToLower(password)] = struct{}{}
+	}
+
+	return &PasswordPolicy{rules: rules, banned: banned}
+}
+
+// Check returns a *PasswordPolicyError when password breaks the policy. personal holds values
+// the password must not contain, such as the username and email of its owner.
+func (p *PasswordPolicy) Check(password string, personal ...string) error {
+	var violations []string
+
+	if n := utf8.RuneCountInString(password); n < p.rules.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.rules.MinLength))
+	}
+	if p.rules.MaxLength > 0 && len(password) > p.rules.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", p.rules.MaxLength))
+	}
+	if charClasses(password) < p.rules.MinCharClasses {
+		violations = append(violations, fmt.Sprintf(
+			"must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.rules.MinCharClasses))
+	}
+
+	lower := strings.ToLower(password)
+	if _, ok := p.banned[lower]; ok {
+		violations = append(violations, "is too common")
+	}
+	for _, value := range personalParts(personal) {
+		if strings.Contains(lower, value) {
+			violations = append(violations, "must not contain your username or email")
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// ParsePasswordList reads one password per line, skipping blank lines and "#" comments
+func ParsePasswordList(r io.Reader) ([]string, error) {
+	var passwords []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords = append(passwords, line)
+	}
+	return passwords, scanner.Err()
+}
+
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// personalParts lowercases the personal values and adds the local part of email addresses
+func personalParts(personal []string) []string {
+	var parts []string
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if local, _, ok := strings.Cut(value, "@"); ok && len(local) >= minPersonalLength {
+			parts = append(parts, local)
+		}
+		if len(value) >= minPersonalLength {
+			parts = append(parts, value)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,148 @@
+package security
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var ErrInvalidUsername = errors.New("username does not meet the policy")
+
+//go:embed reserved_usernames.txt
+var reservedUsernames string
+
+// UsernameRules configures a UsernamePolicy
+type UsernameRules struct {
+	MinLength int
+	MaxLength int
+	// Reserved lists names refused in addition to the built-in list of reserved names
+	Reserved []string
+}
+
+// UsernamePolicy decides which user names users may choose. Names are checked in the form
+// NormalizeUsername gives them, which is the form they are stored in: lower-case letters,
+// digits, dots, dashes and underscores.
+type UsernamePolicy struct {
+	rules    UsernameRules
+	reserved map[string]struct{}
+}
+
+// UsernamePolicyError lists every rule a user name breaks and matches ErrInvalidUsername
+type UsernamePolicyError struct {
+	Violations []string
+}
+
+func (e *UsernamePolicyError) Error() string {
+	return ErrInvalidUsername.Error() + ": " + strings.Join(e.Violations, "; ")
+}
+
+func (e *UsernamePolicyError) Is(target error) bool {
+	return target == ErrInvalidUsername
+}
+
+// NewUsernamePolicy returns a policy enforcing rules
+func NewUsernamePolicy(rules UsernameRules) *UsernamePolicy {
+	reserved := make(map[string]struct{})
+	// Reading from a string cannot fail; reserved names are listed like passwords
+	builtIn, _ := ParsePasswordList(strings.NewReader(reservedUsernames))
+	for _, name := range append(builtIn, rules.Reserved...) {
+		reserved[UsernameSkeleton(NormalizeUsername(name))] = struct{}{}
+	}
+
+	return &UsernamePolicy{rules: rules, reserved: reserved}
+}
+
+// Check returns a *UsernamePolicyError when the normalized user name breaks the policy
+func (p *UsernamePolicy) Check(username string) error {
+	var violations []string
+
+	n := utf8.RuneCountInString(username)
+	if n < p.rules.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.rules.MinLength))
+	}
+	if p.rules.MaxLength > 0 && n > p.rules.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters long", p.rules.MaxLength))
+	}
+	if strings.ContainsFunc(username, func(r rune) bool { return !isUsernameAlphanumeric(r) && !isUsernameSeparator(r) }) {
+		violations = append(violations, "may only contain letters a-z, digits, dots, dashes and underscores")
+	}
+	if username != "" {
+		first, _ := utf8.DecodeRuneInString(username)
+		last, _ := utf8.DecodeLastRuneInString(username)
+		if isUsernameSeparator(first) || isUsernameSeparator(last) {
+			violations = append(violations, "must start and end with a letter or digit")
+		}
+	}
+	if hasRepeatedSeparator(username) {
+		violations = append(violations, "must not contain consecutive dots, dashes or underscores")
+	}
+	if !strings.ContainsFunc(username, func(r rune) bool { return r >= 'a' && r <= 'z' }) {
+		violations = append(violations, "must contain a letter")
+	}
+	if p.Reserved(username) {
+		violations = append(violations, "is reserved")
+	}
+
+	if len(violations) > 0 {
+		return &UsernamePolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Reserved reports whether the normalized user name is reserved or looks like a reserved name
+func (p *UsernamePolicy) Reserved(username string) bool {
+	_, ok := p.reserved[UsernameSkeleton(username)]
+	return ok
+}
+
+// NormalizeUsername returns the form user names are checked, stored and looked up in: the NFKC
+// normalization, which folds compatibility characters like fullwidth letters into their plain
+// form, in lower case and without surrounding spaces. "Ｊａｎｅ" and "JANE" become "jane".
+func NormalizeUsername(username string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimSpace(username)))
+}
+
+// skeletonReplacer joins letter pairs that read as a single letter
+var skeletonReplacer = strings.NewReplacer("rn", "m", "vv", "w")
+
+// UsernameSkeleton returns what the normalized user name looks like, so that names readers
+// could confuse share a skeleton: "rn" reads as "m", "vv" as "w", 0 as o, 1 and i as l, and
+// dots and dashes as underscores. "adm1n" and "admin" look alike, "jane.doe" and "jane_doe" too.
+//
+// The user repository computes skeletons in SQL alike, so both must change together.
+func UsernameSkeleton(username string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '0':
+			return 'o'
+		case '1', 'i':
+			return 'l'
+		case '.', '-':
+			return '_'
+		}
+		return r
+	}, skeletonReplacer.Replace(strings.ToLower(username)))
+}
+
+func isUsernameAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
+
+func isUsernameSeparator(r rune) bool {
+	return r == '.' || r == '-' || r == '_'
+}
+
+func hasRepeatedSeparator(username string) bool {
+	var previous rune
+	for _, r := range username {
+		if isUsernameSeparator(r) && isUsernameSeparator(previous) {
+			return true
+		}
+		previous = r
+	}
+	return false
+}
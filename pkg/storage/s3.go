@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// s3Timeout bounds a request when the caller provides no HTTP client
+const s3Timeout = 30 * time.Second
+
+// S3Config is the bucket of an S3-compatible object store, such as AWS S3 or MinIO
+type S3Config struct {
+	// Endpoint is the base URL of the service, like https://s3.eu-central-1.amazonaws.com
+	Endpoint string
+	Region   string
+	Bucket   string
+	Credentials
+	// PathStyle addresses the bucket in the path instead of as a subdomain of Endpoint, which
+	// most S3-compatible servers expect
+	PathStyle bool
+}
+
+type s3Store struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3Store returns a BlobStore keeping every blob as an object of an S3 bucket. Requests
+// are sent with client, or with a default client when it is nil.
+func NewS3Store(cfg S3Config, client *http.Client) (BlobStore, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, errors.New("s3 bucket and region are required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("s3 credentials are required")
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: s3Timeout}
+	}
+	return &s3Store{cfg: cfg, endpoint: endpoint, client: client, now: time.Now}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	req, err := s.request(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType(key))
+	resp, err := s.send(req, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp, key)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (*Blob, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.send(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &Blob{Body: resp.Body, Size: resp.ContentLength}, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(resp, key)
+	}
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.send(req, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 answers 204 whether or not the object existed, other servers may answer 404
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error(resp, key)
+	}
+	return nil
+}
+
+// request addresses the object stored under key. checkKey only lets through keys that need
+// no escaping, so the key is used as the path as is.
+func (s *s3Store) request(ctx context.Context, method, key string, data []byte) (*http.Request, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	target := *s.endpoint
+	base := strings.TrimSuffix(target.Path, "/")
+	if s.cfg.PathStyle {
+		target.Path = base + "/" + s.cfg.Bucket + "/" + key
+	} else {
+		target.Host = s.cfg.Bucket + "." + target.Host
+		target.Path = base + "/" + key
+	}
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	return http.NewRequestWithContext(ctx, method, target.String(), body)
+}
+
+func (s *s3Store) send(req *http.Request, data []byte) (*http.Response, error) {
+	SignV4(req, hexSHA256(data), s.cfg.Region, s.cfg.Credentials, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s failed: %w", req.Method, err)
+	}
+	return resp, nil
+}
+
+// s3Error reports an unexpected response together with the error code S3 sends in its body
+func s3Error(resp *http.Response, key string) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	code := ""
+	if start := bytes.Index(body, []byte("<Code>")); start >= 0 {
+		if end := bytes.Index(body[start:], []byte("</Code>")); end >= 0 {
+			code = ": " + string(body[start+len("<Code>"):start+end])
+		}
+	}
+	return fmt.Errorf("s3 %s %s: %s%s", resp.Request.Method, key, resp.Status, code)
+}
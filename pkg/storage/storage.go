@@ -0,0 +1,65 @@
+// Package storage keeps blobs, such as uploaded images, in a local directory or in a bucket
+// of an S3-compatible object store
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Blob is a stored blob being read. Callers close Body.
+type Blob struct {
+	Body io.ReadCloser
+	Size int64
+}
+
+// BlobStore keeps blobs under slash-separated keys like "avatars/<user>/<name>.jpg". Keys
+// are made of letters, digits and "-", "_", "." and "/" only, and must not contain empty,
+// "." or ".." segments.
+type BlobStore interface {
+	// Put stores data under key, replacing any blob stored there
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns ErrNotFound when no blob is stored under key
+	Get(ctx context.Context, key string) (*Blob, error)
+	// Delete removes the blob stored under key, if any
+	Delete(ctx context.Context, key string) error
+}
+
+// ContentType returns the media type of a blob from the extension of its key
+func ContentType(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// checkKey rejects keys that could escape the directory of a FileStore or need escaping in URLs
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	for _, r := range key {
+		if !isKeyRune(r) {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+func isKeyRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./", r)
+}
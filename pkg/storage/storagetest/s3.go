@@ -0,0 +1,142 @@
+// Package storagetest runs a local stand-in for an S3-compatible object store in tests
+package storagetest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/pkg/storage"
+)
+
+// S3Server serves one bucket with path-style addressing, as MinIO does. It keeps objects in
+// memory and rejects requests whose signature does not verify with its credentials.
+type S3Server struct {
+	*httptest.Server
+	Bucket      string
+	Region      string
+	Credentials storage.Credentials
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// NewS3Server starts a stand-in serving bucket, closed when the test ends
+func NewS3Server(t testing.TB, bucket string) *S3Server {
+	t.Helper()
+
+	s := &S3Server{
+		Bucket:      bucket,
+		Region:      "us-east-1",
+		Credentials: storage.Credentials{AccessKeyID: "test-access-key", SecretAccessKey: "test-secret-key"},
+		objects:     map[string][]byte{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Config returns the settings of a store reaching the stand-in
+func (s *S3Server) Config() storage.S3Config {
+	return storage.S3Config{
+		Endpoint:    s.URL,
+		Region:      s.Region,
+		Bucket:      s.Bucket,
+		Credentials: s.Credentials,
+		PathStyle:   true,
+	}
+}
+
+// Object returns the object stored under key
+func (s *S3Server) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.objects[key]
+	return data, ok
+}
+
+func (s *S3Server) serve(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.Bucket+"/")
+	if !ok || key == "" {
+		s3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s3Error(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	if !s.verify(r, body) {
+		s3Error(w, http.StatusForbidden, "SignatureDoesNotMatch")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[key] = body
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		data, ok := s.objects[key]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// verify signs a copy of the request with the headers it claims to have signed and compares
+// the result with its Authorization header
+func (s *S3Server) verify(r *http.Request, body []byte) bool {
+	authorization := r.Header.Get("Authorization")
+	_, signedHeaders, ok := strings.Cut(authorization, "SignedHeaders=")
+	if !ok {
+		return false
+	}
+	signedHeaders, _, _ = strings.Cut(signedHeaders, ",")
+	date, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash != storage.UnsignedPayload && payloadHash != hexSHA256(body) {
+		return false
+	}
+
+	signed := r.Clone(r.Context())
+	signed.Host = r.Host
+	signed.Header = http.Header{}
+	for _, name := range strings.Split(signedHeaders, ";") {
+		if name != "host" && name != "x-amz-date" && name != "x-amz-content-sha256" {
+			signed.Header[http.CanonicalHeaderKey(name)] = r.Header.Values(name)
+		}
+	}
+	storage.SignV4(signed, payloadHash, s.Region, s.Credentials, date)
+	return signed.Header.Get("Authorization") == authorization
+}
+
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	io.WriteString(w, "<Error><Code>"+code+"</Code></Error>")
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
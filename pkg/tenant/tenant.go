@@ -0,0 +1,104 @@
+// Package tenant carries the tenant a request acts for in its context and resolves it from
+// the host name or a header of the request.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnknown is returned for tenants that are not served
+	ErrUnknown = errors.New("unknown tenant")
+	// ErrConflict is returned when the host and the header name different tenants
+	ErrConflict = errors.New("the host and the header name different tenants")
+)
+
+// idPattern matches a DNS label, so that every tenant can be served from a subdomain
+var idPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+type contextKey struct{}
+
+// Valid reports whether id can name a tenant: a lowercase DNS label of at most 63 characters
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// NewContext returns a copy of ctx acting for the tenant id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ctx acts for
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Resolver finds the tenant a request is addressed to
+type Resolver struct {
+	baseDomain string
+	header     string
+	tenants    []string
+}
+
+// NewResolver returns a resolver for the given tenants. Requests to <tenant>.<baseDomain>
+// and requests with header set to a tenant are addressed to that tenant; an empty baseDomain
+// or header disables that source.
+func NewResolver(tenants []string, baseDomain, header string) *Resolver {
+	return &Resolver{
+		baseDomain: strings.ToLower(strings.Trim(baseDomain, ".")),
+		header:     header,
+		tenants:    tenants,
+	}
+}
+
+// Known reports whether id is one of the tenants served
+func (r *Resolver) Known(id string) bool {
+	return slices.Contains(r.tenants, id)
+}
+
+// Resolve returns the tenant r is addressed to, or "" when neither the host nor the header
+// names one
+func (r *Resolver) Resolve(req *http.Request) (string, error) {
+	fromHost := r.fromHost(req.Host)
+	var fromHeader string
+	if r.header != "" {
+		fromHeader = strings.ToLower(strings.TrimSpace(req.Header.Get(r.header)))
+	}
+
+	id := fromHost
+	switch {
+	case fromHost != "" && fromHeader != "" && fromHost != fromHeader:
+		return "", ErrConflict
+	case fromHost == "":
+		id = fromHeader
+	}
+
+	if id != "" && !r.Known(id) {
+		return "", ErrUnknown
+	}
+	return id, nil
+}
+
+// fromHost returns the subdomain of host below the base domain. Hosts outside the base
+// domain and deeper subdomains name no tenant.
+func (r *Resolver) fromHost(host string) string {
+	if r.baseDomain == "" {
+		return ""
+	}
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+r.baseDomain)
+	if !ok || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -22,6 +22,15 @@ if [ -f "api/v1/openapi.yaml" ]; then
     --additional-properties=packageName=v1api,enumClassPrefix=true,outputAsLibrary=true
 fi
 
+# Generate typed Go clients
+for spec in auth v1; do
+  if [ -f "api/${spec}/openapi.yaml" ]; then
+    go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 \
+      -config "configs/oapi-codegen/${spec}-client.yaml" \
+      "api/${spec}/openapi.yaml"
+  fi
+done
+
 # Format generated files
 echo "Formatting generated Go files..."
 gofmt -w ./gen/openapi/auth/go/*.go 2>/dev/null || true
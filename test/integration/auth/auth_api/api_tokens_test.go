@@ -0,0 +1,254 @@
+package login_api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+	authservice "example.com/internal/domain/service/auth"
+	policyservice "example.com/internal/domain/service/policy"
+	userservice "example.com/internal/domain/service/v1"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/authz"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+func setupTokenRouter(t *testing.T) (*gin.Engine, *mocks.MockAPITokenRepository) {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	user := &entity.User{
+		ID:           "user-123",
+		Email:        "test@example.com",
+		UserName:     "testuser",
+		PasswordHash: "hashed_password",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	userRepo := &mocks.MockUserRepository{}
+	userRepo.On("FindByUserNameOrEmail", mock.Anything, "test@example.com").Return(user, nil)
+	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	userRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+	userRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", "password123", "hashed_password").Return(true)
+	// Nobody holds a role, so users may only look themselves up
+	roleRepo := &mocks.MockRoleRepository{}
+	roleRepo.On("FindByUserID", mock.Anything, mock.Anything).Return([]*entity.Role{}, nil)
+	organizationRepo := &mocks.MockOrganizationRepository{}
+	organizationRepo.On("ListByUserID", mock.Anything, mock.Anything).Return([]*entity.Membership{}, nil)
+	policies, err := config.AuthzConfig{}.Policies()
+	require.NoError(t, err)
+	policySvc := policyservice.NewService(roleRepo, userRepo, organizationRepo)
+	authorizer := authz.NewAuthorizer(policies, authz.Config{
+		Attributes: policySvc.SubjectAttributes,
+		Resources:  policySvc.ResourceAttributes,
+	})
+
+	tokenRepo := &mocks.MockAPITokenRepository{}
+	testLogger := logger.New("test")
+	authSvc := authservice.NewService(userRepo, hasher)
+	tokenSvc := apitokenservice.NewService(tokenRepo)
+
+	router, err := app.NewRouter(browsertest.Config(), app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User: api.NewUserAPIHandler(userusecase.NewUserLookupUseCase(userservice.NewService(userRepo), authorizer), nil, testLogger),
+		APITokens: api.NewAPITokenAPIHandler(
+			authusecase.NewCreateAPITokenUseCase(tokenSvc),
+			authusecase.NewListAPITokensUseCase(tokenSvc),
+			authusecase.NewRevokeAPITokenUseCase(tokenSvc),
+			testLogger,
+		),
+		AuthenticateToken: authusecase.NewAuthenticateAPITokenUseCase(tokenSvc),
+	})
+	require.NoError(t, err)
+
+	return router, tokenRepo
+}
+
+// loggedIn returns the cookies and XSRF token of a logged-in session
+func loggedIn(t *testing.T, router *gin.Engine) ([]*http.Cookie, string) {
+	cookies, token := issueToken(t, router)
+	w := login(router, cookies, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The session cookie is rewritten on login
+	return w.Result().Cookies(), token
+}
+
+func serve(router *gin.Engine, req *http.Request, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPITokens_CreateWithSession(t *testing.T) {
+	router, tokenRepo := setupTokenRouter(t)
+	cookies, xsrf := loggedIn(t, router)
+
+	tokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(token *entity.APIToken) bool {
+		return token.UserID == "user-123" && token.Name == "ci"
+	})).Return(nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/tokens", bytes.NewBufferString(`{"name":"ci","scopes":["users:read"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-XSRF-TOKEN", xsrf)
+	w := serve(router, req, cookies)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response authapi.CreateApiTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Token, entity.APITokenPrefix)
+	assert.Equal(t, "ci", response.ApiToken.Name)
+	assert.Equal(t, response.Token[:len(response.ApiToken.Hint)], response.ApiToken.Hint)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAPITokens_RequireSession(t *testing.T) {
+	router, _ := setupTokenRouter(t)
+	cookies, xsrf := issueToken(t, router)
+
+	req := httptest.NewRequest("GET", "/api/v1/auth/tokens", nil)
+	req.Header.Set("X-XSRF-TOKEN", xsrf)
+	w := serve(router, req, cookies)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAPITokens_RevokeUnknownToken(t *testing.T) {
+	router, tokenRepo := setupTokenRouter(t)
+	cookies, xsrf := loggedIn(t, router)
+
+	tokenRepo.On("Revoke", mock.Anything, "user-123", "missing", mock.Anything).Return(gorm.ErrRecordNotFound)
+
+	req := httptest.NewRequest("DELETE", "/api/v1/auth/tokens/missing", nil)
+	req.Header.Set("X-XSRF-TOKEN", xsrf)
+	w := serve(router, req, cookies)
+
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestAPITokens_BearerLookupWithoutXSRF(t *testing.T) {
+	router, tokenRepo := setupTokenRouter(t)
+
+	plain := entity.APITokenPrefix + "read-token"
+	tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(&entity.APIToken{
+		ID:     "token-1",
+		UserID: "user-123",
+		Scopes: []string{entity.ScopeUsersRead},
+	}, nil)
+	tokenRepo.On("UpdateLastUsed", mock.Anything, "token-1", mock.Anything).Return(nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/user/lookup?email=test@example.com", nil)
+	req.Header.Set("Authorization", "Bearer "+plain)
+	w := serve(router, req, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestAPITokens_BearerLookupWithoutPermission(t *testing.T) {
+	router, tokenRepo := setupTokenRouter(t)
+
+	tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(&entity.APIToken{
+		ID:     "token-1",
+		UserID: "user-456",
+		Scopes: []string{entity.ScopeUsersRead},
+	}, nil)
+	tokenRepo.On("UpdateLastUsed", mock.Anything, "token-1", mock.Anything).Return(nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/user/lookup?email=test@example.com", nil)
+	req.Header.Set("Authorization", "Bearer "+entity.APITokenPrefix+"read-token")
+	w := serve(router, req, nil)
+
+	assert.Equal(t, http.StatusNotFound, w.Code, "the scope does not grant what the user may not do")
+}
+
+func TestAPITokens_LookupRequiresAuthentication(t *testing.T) {
+	router, _ := setupTokenRouter(t)
+	cookies, xsrf := issueToken(t, router)
+
+	req := httptest.NewRequest("GET", "/api/v1/user/lookup?email=test@example.com", nil)
+	req.Header.Set("X-XSRF-TOKEN", xsrf)
+	w := serve(router, req, cookies)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAPITokens_BearerMissingScope(t *testing.T) {
+	router, tokenRepo := setupTokenRouter(t)
+
+	tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(&entity.APIToken{ID: "token-1", UserID: "user-123"}, nil)
+	tokenRepo.On("UpdateLastUsed", mock.Anything, "token-1", mock.Anything).Return(nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/user/lookup?email=test@example.com", nil)
+	req.Header.Set("Authorization", "Bearer "+entity.APITokenPrefix+"no-scope")
+	w := serve(router, req, nil)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAPITokens_BearerCannotManageTokens(t *testing.T) {
+	router, tokenRepo := setupTokenRouter(t)
+
+	tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(&entity.APIToken{
+		ID:     "token-1",
+		UserID: "user-123",
+		Scopes: []string{entity.ScopeUsersRead},
+	}, nil)
+	tokenRepo.On("UpdateLastUsed", mock.Anything, "token-1", mock.Anything).Return(nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/tokens", bytes.NewBufferString(`{"name":"ci","scopes":["users:read"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+entity.APITokenPrefix+"read-token")
+	w := serve(router, req, nil)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAPITokens_InvalidBearerToken(t *testing.T) {
+	router, tokenRepo := setupTokenRouter(t)
+
+	tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	req := httptest.NewRequest("GET", "/api/v1/user/lookup?email=test@example.com", nil)
+	req.Header.Set("Authorization", "Bearer "+entity.APITokenPrefix+"unknown")
+	w := serve(router, req, nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+}
@@ -0,0 +1,171 @@
+package login_api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	authservice "example.com/internal/domain/service/auth"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	oldCSRFKey    = "csrf-2026-09:old-csrf-secret-with-at-least-32-chars"
+	newCSRFKey    = "csrf-2026-10:new-csrf-secret-with-at-least-32-chars"
+	oldSigningKey = "sign-2026-09:old-signing-secret-with-32-characters"
+	newSigningKey = "sign-2026-10:new-signing-secret-with-32-characters"
+	encryptionKey = "enc-2026-09:encryption-secret-with-32-characters"
+)
+
+func setupRotationRouter(t *testing.T, security config.SecurityConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	mockRepo := &mocks.MockUserRepository{}
+	mockRepo.On("FindByUserNameOrEmail", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+	authSvc := authservice.NewService(mockRepo, &mocks.MockPasswordHasher{})
+	authAPIHandler := api.NewAuthAPIHandler(
+		authusecase.NewSignupUseCase(authSvc, nil, nil),
+		authusecase.NewLoginUseCase(authSvc),
+		nil,
+		logger.New("test"),
+	)
+
+	router, err := app.NewRouter(&config.Config{
+		Server:   config.ServerConfig{Env: "test"},
+		Security: security,
+	}, app.Handlers{
+		Validator: validator,
+		Auth:      authAPIHandler,
+		User:      &api.UserAPIHandler{},
+	})
+	require.NoError(t, err)
+
+	return router
+}
+
+// issueToken starts a session on router and returns its cookies and XSRF token
+func issueToken(t *testing.T, router *gin.Engine) ([]*http.Cookie, string) {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/csrf-token", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var token authapi.CsrfToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &token))
+	return w.Result().Cookies(), token.Token
+}
+
+func login(router *gin.Engine, cookies []*http.Cookie, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/v1/auth/login",
+		bytes.NewBufferString(`{"email":"test@example.com","password":"password123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-XSRF-TOKEN", token)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func retired(key string, at time.Time) string {
+	return key + "@" + at.UTC().Format(time.RFC3339)
+}
+
+func TestKeyRotation_PreviousKeysAcceptedDuringGracePeriod(t *testing.T) {
+	before := setupRotationRouter(t, config.SecurityConfig{
+		CSRFKeys:              []string{oldCSRFKey},
+		SessionSigningKeys:    []string{oldSigningKey},
+		SessionEncryptionKeys: []string{encryptionKey},
+		KeyGracePeriod:        time.Hour,
+	})
+	cookies, token := issueToken(t, before)
+
+	rotatedAt := time.Now().Add(-time.Minute)
+	after := setupRotationRouter(t, config.SecurityConfig{
+		CSRFKeys:              []string{newCSRFKey, retired(oldCSRFKey, rotatedAt)},
+		SessionSigningKeys:    []string{newSigningKey, retired(oldSigningKey, rotatedAt)},
+		SessionEncryptionKeys: []string{encryptionKey},
+		KeyGracePeriod:        time.Hour,
+	})
+
+	w := login(after, cookies, token)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+
+	// New tokens are issued with the current key
+	_, newToken := issueToken(t, after)
+	assert.NotEqual(t, token, newToken)
+}
+
+func TestKeyRotation_PreviousKeysRejectedAfterGracePeriod(t *testing.T) {
+	before := setupRotationRouter(t, config.SecurityConfig{
+		CSRFKeys:           []string{oldCSRFKey},
+		SessionSigningKeys: []string{oldSigningKey},
+		KeyGracePeriod:     time.Hour,
+	})
+	cookies, token := issueToken(t, before)
+
+	rotatedAt := time.Now().Add(-2 * time.Hour)
+	after := setupRotationRouter(t, config.SecurityConfig{
+		CSRFKeys:           []string{newCSRFKey, retired(oldCSRFKey, rotatedAt)},
+		SessionSigningKeys: []string{newSigningKey, retired(oldSigningKey, rotatedAt)},
+		KeyGracePeriod:     time.Hour,
+	})
+
+	w := login(after, cookies, token)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestKeyRotation_SingleSecretsStillWork(t *testing.T) {
+	router := setupRotationRouter(t, config.SecurityConfig{
+		CSRFSecret:    "test-csrf-secret",
+		SessionSecret: "test-session-secret",
+	})
+	cookies, token := issueToken(t, router)
+
+	w := login(router, cookies, token)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+}
+
+func TestKeyRotation_EncryptedCookieUnreadableWithoutKey(t *testing.T) {
+	encrypted := setupRotationRouter(t, config.SecurityConfig{
+		CSRFKeys:              []string{oldCSRFKey},
+		SessionSigningKeys:    []string{oldSigningKey},
+		SessionEncryptionKeys: []string{encryptionKey},
+	})
+	cookies, token := issueToken(t, encrypted)
+
+	signedOnly := setupRotationRouter(t, config.SecurityConfig{
+		CSRFKeys:           []string{oldCSRFKey},
+		SessionSigningKeys: []string{oldSigningKey},
+	})
+
+	w := login(signedOnly, cookies, token)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
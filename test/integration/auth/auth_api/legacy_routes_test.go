@@ -0,0 +1,158 @@
+package login_api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	authservice "example.com/internal/domain/service/auth"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/infrastructure/metrics"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+func setupLegacyRouter(t *testing.T, legacy config.LegacyAPIConfig) (*gin.Engine, *mocks.MockUserRepository) {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	mockRepo := &mocks.MockUserRepository{}
+	authSvc := authservice.NewService(mockRepo, &mocks.MockPasswordHasher{})
+	authAPIHandler := api.NewAuthAPIHandler(
+		authusecase.NewSignupUseCase(authSvc, nil, nil),
+		authusecase.NewLoginUseCase(authSvc),
+		nil,
+		logger.New("test"),
+	)
+
+	cfg := browsertest.Config()
+	cfg.Legacy = legacy
+	cfg.Metrics = config.MetricsConfig{Enabled: true}
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth:      authAPIHandler,
+		User:      &api.UserAPIHandler{},
+		Metrics:   metrics.NewRegistry(),
+	})
+	require.NoError(t, err)
+
+	return router, mockRepo
+}
+
+// postWithXSRF fetches an XSRF token for a fresh session and posts body to path with it
+func postWithXSRF(t *testing.T, router *gin.Engine, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/csrf-token", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var token authapi.CsrfToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &token))
+
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-XSRF-TOKEN", token.Token)
+	for _, cookie := range w.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLegacyRoutes_DeprecationHeadersAndUsage(t *testing.T) {
+	deprecatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Now().Add(90 * 24 * time.Hour).UTC()
+	router, mockRepo := setupLegacyRouter(t, config.LegacyAPIConfig{
+		DeprecatedAt: deprecatedAt,
+		Sunset:       sunset,
+		DocsURL:      "https://docs.example.com/migrate-to-v1",
+	})
+
+	mockRepo.On("FindByUserNameOrEmail", mock.Anything, "test@example.com").Return(nil, assert.AnError)
+
+	w := postWithXSRF(t, router, "/auth/user/login", `{"email":"test@example.com","password":"password123"}`,
+		map[string]string{"X-Client-ID": "billing-service"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "@"+strconv.FormatInt(deprecatedAt.Unix(), 10), w.Header().Get("Deprecation"))
+	assert.Equal(t, sunset.Format(http.TimeFormat), w.Header().Get("Sunset"))
+	assert.Contains(t, w.Header().Get("Link"), `</api/v1/auth/login>; rel="successor-version"`)
+	assert.Contains(t, w.Header().Get("Link"), `<https://docs.example.com/migrate-to-v1>; rel="deprecation"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `legacy_api_requests_total{route="/auth/user/login",client="billing-service"} 1`)
+}
+
+func TestLegacyRoutes_RejectedAfterSunset(t *testing.T) {
+	router, mockRepo := setupLegacyRouter(t, config.LegacyAPIConfig{
+		Sunset:            time.Now().Add(-time.Hour),
+		RejectAfterSunset: true,
+	})
+
+	w := postWithXSRF(t, router, "/auth/user/signup", `{"email":"test@example.com","password":"password123"}`, nil)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.NotEmpty(t, w.Header().Get("Sunset"))
+
+	var errorResp authapi.Error
+	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+	assert.NoError(t, err)
+	assert.Contains(t, errorResp.Message, "/api/v1/auth/signup")
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestLegacyRoutes_SunsetWithoutRejectionStillServes(t *testing.T) {
+	router, mockRepo := setupLegacyRouter(t, config.LegacyAPIConfig{
+		Sunset: time.Now().Add(-time.Hour),
+	})
+
+	mockRepo.On("FindByUserNameOrEmail", mock.Anything, "test@example.com").Return(nil, assert.AnError)
+
+	w := postWithXSRF(t, router, "/auth/user/login", `{"email":"test@example.com","password":"password123"}`, nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+}
+
+func TestVersionedRoutes_NotDeprecated(t *testing.T) {
+	router, mockRepo := setupLegacyRouter(t, config.LegacyAPIConfig{Sunset: time.Now().Add(-time.Hour), RejectAfterSunset: true})
+
+	mockRepo.On("FindByUserNameOrEmail", mock.Anything, "test@example.com").Return(nil, assert.AnError)
+
+	w := postWithXSRF(t, router, "/api/v1/auth/login", `{"email":"test@example.com","password":"password123"}`, nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "v1", w.Header().Get("API-Version"))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+}
@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,13 +30,14 @@ func setupLoginRouter() (*gin.Engine, *mocks.MockUserRepository, *mocks.MockPass
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
 	authSvc := authservice.NewService(mockRepo, mockHasher)
-	signupUseCase := authusecase.NewSignupUseCase(authSvc)
+	signupUseCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
 	loginUseCase := authusecase.NewLoginUseCase(authSvc)
 	testLogger := logger.New("test")
 
-	authAPIHandler := api.NewAuthAPIHandler(signupUseCase, loginUseCase, testLogger)
+	authAPIHandler := api.NewAuthAPIHandler(signupUseCase, loginUseCase, nil, testLogger)
 
 	router := gin.New()
+	router.Use(testSession())
 	auth := router.Group("/auth")
 	{
 		auth.POST("/login", authAPIHandler.UserLogin)
@@ -43,6 +46,11 @@ func setupLoginRouter() (*gin.Engine, *mocks.MockUserRepository, *mocks.MockPass
 	return router, mockRepo, mockHasher
 }
 
+// testSession stores sessions in a cookie signed with a fixed test secret
+func testSession() gin.HandlerFunc {
+	return sessions.Sessions("session_id", cookie.NewStore([]byte("test-session-secret")))
+}
+
 func TestLoginAPI_Success(t *testing.T) {
 	router, mockRepo, mockHasher := setupLoginRouter()
 
@@ -82,6 +90,7 @@ func TestLoginAPI_Success(t *testing.T) {
 	assert.Equal(t, "test@example.com", response.User.Email)
 	assert.Equal(t, "testuser", response.User.Username)
 	assert.Equal(t, "Login successful", response.Message)
+	assert.NotEmpty(t, w.Result().Cookies(), "login should start a session")
 
 	mockRepo.AssertExpectations(t)
 	mockHasher.AssertExpectations(t)
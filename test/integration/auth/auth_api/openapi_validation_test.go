@@ -0,0 +1,159 @@
+package login_api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/test/unit/mocks"
+)
+
+func setupValidatedRouter(t *testing.T, validateResponses bool) (*gin.Engine, *mocks.MockUserRepository, *mocks.MockPasswordHasher) {
+	gin.SetMode(gin.TestMode)
+
+	doc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{
+		ValidateResponses: validateResponses,
+	}, doc)
+	require.NoError(t, err)
+
+	mockRepo := &mocks.MockUserRepository{}
+	mockHasher := &mocks.MockPasswordHasher{}
+	authSvc := authservice.NewService(mockRepo, mockHasher)
+	signupUseCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
+	loginUseCase := authusecase.NewLoginUseCase(authSvc)
+	testLogger := logger.New("test")
+
+	authAPIHandler := api.NewAuthAPIHandler(signupUseCase, loginUseCase, nil, testLogger)
+
+	router := gin.New()
+	router.Use(testSession())
+	auth := router.Group("/auth")
+	{
+		auth.POST("/signup", validator.Operation("userSignup"), authAPIHandler.UserSignup)
+		auth.POST("/login", validator.Operation("userLogin"), authAPIHandler.UserLogin)
+		auth.POST("/drift", validator.Operation("userLogin"), func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "missing user", "unexpected": true})
+		})
+	}
+
+	return router, mockRepo, mockHasher
+}
+
+func postJSON(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOpenAPIValidation_ShortPassword(t *testing.T) {
+	router, mockRepo, mockHasher := setupValidatedRouter(t, false)
+
+	w := postJSON(router, "/auth/signup", `{"email":"test@example.com","password":"short"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResp authapi.Error
+	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Request validation failed", errorResp.Error)
+	require.Len(t, errorResp.Details, 1)
+	assert.Equal(t, "password", errorResp.Details[0].Field)
+	assert.Equal(t, "body", errorResp.Details[0].In)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockHasher.AssertNotCalled(t, "Hash", mock.Anything)
+}
+
+func TestOpenAPIValidation_InvalidEmail(t *testing.T) {
+	router, _, _ := setupValidatedRouter(t, false)
+
+	w := postJSON(router, "/auth/signup", `{"email":"not-an-email","password":"password123"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResp authapi.Error
+	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+	assert.NoError(t, err)
+	require.Len(t, errorResp.Details, 1)
+	assert.Equal(t, "email", errorResp.Details[0].Field)
+}
+
+func TestOpenAPIValidation_MultipleErrors(t *testing.T) {
+	router, _, _ := setupValidatedRouter(t, false)
+
+	w := postJSON(router, "/auth/signup", `{"email":"not-an-email","password":"short","role":"admin"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResp authapi.Error
+	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+	assert.NoError(t, err)
+	assert.Len(t, errorResp.Details, 3)
+}
+
+func TestOpenAPIValidation_MissingRequiredField(t *testing.T) {
+	router, _, _ := setupValidatedRouter(t, false)
+
+	w := postJSON(router, "/auth/login", `{"email":"test@example.com"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOpenAPIValidation_ValidRequestReachesHandler(t *testing.T) {
+	router, mockRepo, mockHasher := setupValidatedRouter(t, true)
+
+	now := time.Now()
+	mockRepo.On("FindByUserName", mock.Anything, "testuser").Return(nil, assert.AnError)
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(nil, assert.AnError)
+	mockHasher.On("Hash", "password123").Return("hashed_password", nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+		user := args.Get(1).(*entity.User)
+		user.CreatedAt = now
+		user.UpdatedAt = now
+	}).Return(nil)
+
+	w := postJSON(router, "/auth/signup", `{"email":"test@example.com","password":"password123","username":"testuser"}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response authapi.SignupResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", response.User.Email)
+
+	mockRepo.AssertExpectations(t)
+	mockHasher.AssertExpectations(t)
+}
+
+func TestOpenAPIValidation_ResponseDrift(t *testing.T) {
+	router, _, _ := setupValidatedRouter(t, true)
+
+	w := postJSON(router, "/auth/drift", `{"email":"test@example.com","password":"password123"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var errorResp authapi.Error
+	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Response validation failed", errorResp.Error)
+	assert.NotEmpty(t, errorResp.Details)
+}
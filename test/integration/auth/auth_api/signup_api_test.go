@@ -27,11 +27,11 @@ func setupSignupRouter() (*gin.Engine, *mocks.MockUserRepository, *mocks.MockPas
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
 	authSvc := authservice.NewService(mockRepo, mockHasher)
-	signupUseCase := authusecase.NewSignupUseCase(authSvc)
+	signupUseCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
 	loginUseCase := authusecase.NewLoginUseCase(authSvc)
 	testLogger := logger.New("test")
 
-	authAPIHandler := api.NewAuthAPIHandler(signupUseCase, loginUseCase, testLogger)
+	authAPIHandler := api.NewAuthAPIHandler(signupUseCase, loginUseCase, nil, testLogger)
 
 	router := gin.New()
 	auth := router.Group("/auth")
@@ -0,0 +1,105 @@
+package login_api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/infrastructure/config"
+)
+
+func setupTenantRouter(t *testing.T) *gin.Engine {
+	cfg := jwtConfig(t)
+	cfg.Tenancy = config.TenancyConfig{
+		Enabled:    true,
+		Tenants:    []string{"acme", "globex"},
+		BaseDomain: "example.com",
+		Header:     "X-Tenant-ID",
+	}
+	return newJWTRouter(t, cfg)
+}
+
+// session is a browser logged in to a tenant
+type session struct {
+	cookies []*http.Cookie
+	xsrf    string
+}
+
+// tenantLogin logs in to tenant over its subdomain, with a session or asking for tokens
+func tenantLogin(t *testing.T, router *gin.Engine, tenant string, issueTokens bool) (session, authapi.LoginResponse) {
+	cookies, xsrf := issueToken(t, router)
+
+	body, err := json.Marshal(map[string]any{"email": "test@example.com", "password": "password123", "issueTokens": issueTokens})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/api/v1/auth/login", bytes.NewReader(body))
+	req.Host = tenant + ".example.com"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-XSRF-TOKEN", xsrf)
+	w := serve(router, req, cookies)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response authapi.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	// The session cookie is rewritten on login
+	return session{cookies: w.Result().Cookies(), xsrf: xsrf}, response
+}
+
+// lookup requests the lookup route with the tenant header, unless tenant is empty
+func lookup(router *gin.Engine, tenant string, browser session, accessToken string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/api/v1/user/lookup?email=test@example.com", nil)
+	if tenant != "" {
+		req.Header.Set("X-Tenant-ID", tenant)
+	}
+	if browser.xsrf != "" {
+		req.Header.Set("X-XSRF-TOKEN", browser.xsrf)
+	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return serve(router, req, browser.cookies)
+}
+
+func TestTenancy_SessionOnlyAuthenticatesItsTenant(t *testing.T) {
+	router := setupTenantRouter(t)
+	browser, _ := tenantLogin(t, router, "acme", false)
+
+	assert.Equal(t, http.StatusOK, lookup(router, "acme", browser, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, lookup(router, "globex", browser, "").Code)
+	assert.Equal(t, http.StatusBadRequest, lookup(router, "", browser, "").Code)
+}
+
+func TestTenancy_AccessTokenOnlyAuthenticatesItsTenant(t *testing.T) {
+	router := setupTenantRouter(t)
+	_, response := tenantLogin(t, router, "acme", true)
+
+	// A request naming no tenant acts for the tenant of the token
+	assert.Equal(t, http.StatusOK, lookup(router, "", session{}, response.AccessToken).Code)
+	assert.Equal(t, http.StatusOK, lookup(router, "acme", session{}, response.AccessToken).Code)
+	assert.Equal(t, http.StatusUnauthorized, lookup(router, "globex", session{}, response.AccessToken).Code)
+}
+
+func TestTenancy_RejectsUnknownAndConflictingTenants(t *testing.T) {
+	router := setupTenantRouter(t)
+
+	assert.Equal(t, http.StatusNotFound, lookup(router, "initech", session{}, "").Code)
+
+	req := httptest.NewRequest("GET", "/api/v1/user/lookup?email=test@example.com", nil)
+	req.Host = "acme.example.com"
+	req.Header.Set("X-Tenant-ID", "globex")
+	assert.Equal(t, http.StatusBadRequest, serve(router, req, nil).Code)
+}
+
+func TestTenancy_SharedRoutesNeedNoTenant(t *testing.T) {
+	router := setupTenantRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
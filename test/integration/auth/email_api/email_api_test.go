@@ -0,0 +1,248 @@
+package email_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	authservice "example.com/internal/domain/service/auth"
+	emailservice "example.com/internal/domain/service/email"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	oldEmail = "jane@example.com"
+	newEmail = "jane@work.example.com"
+	password = "initial secret"
+)
+
+var (
+	confirmPattern = regexp.MustCompile(`email_change_token=(ecc_[A-Za-z0-9_-]+)`)
+	revertPattern  = regexp.MustCompile(`email_revert_token=(ecr_[A-Za-z0-9_-]+)`)
+)
+
+type testEnv struct {
+	router        *gin.Engine
+	outbox        *mocks.Outbox
+	refreshTokens *mocks.MockRefreshTokenRepository
+}
+
+func setupEmailRouter(t *testing.T) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	hasher := security.NewBcryptHasher()
+	users := mocks.NewMemoryUserRepository()
+	refreshTokens := &mocks.MockRefreshTokenRepository{}
+	refreshTokens.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	audit := &mocks.MockAuditEventRepository{}
+	audit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditEvent")).Return(nil)
+	sent := &mocks.Outbox{}
+
+	authSvc := authservice.NewService(users, hasher)
+	emailSvc := emailservice.NewService(users, &memoryChanges{}, refreshTokens, audit, hasher, sent, emailservice.Config{
+		LinkURL:    "https://app.example.com/email",
+		ConfirmTTL: time.Hour,
+		RevertTTL:  24 * time.Hour,
+	})
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(browsertest.Config(), app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User:      &api.UserAPIHandler{},
+		APITokens: &api.APITokenAPIHandler{},
+		Email: api.NewEmailAPIHandler(
+			authusecase.NewRequestEmailChangeUseCase(emailSvc),
+			authusecase.NewConfirmEmailChangeUseCase(emailSvc),
+			authusecase.NewRevertEmailChangeUseCase(emailSvc),
+			testLogger,
+		),
+		ValidateSession: authusecase.NewValidateSessionUseCase(authSvc),
+	})
+	require.NoError(t, err)
+
+	env := &testEnv{router: router, outbox: sent, refreshTokens: refreshTokens}
+	env.signup(t, oldEmail, "jane")
+	return env
+}
+
+func (e *testEnv) signup(t *testing.T, email, username string) {
+	w := e.browser().API("POST", "/api/v1/auth/signup", `{"email":"`+email+`","password":"`+password+`","username":"`+username+`"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+// token returns the token of the last link matching pattern emailed to the address
+func (e *testEnv) token(t *testing.T, to string, pattern *regexp.Regexp) string {
+	body := e.outbox.LastTo(to).Body
+	match := pattern.FindStringSubmatch(body)
+	require.NotNil(t, match, body)
+	return match[1]
+}
+
+// browser is one user agent of the frontend
+type browser struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) browser() *browser {
+	return &browser{Browser: browsertest.New(e.router)}
+}
+
+func (b *browser) login(t *testing.T, email string) *httptest.ResponseRecorder {
+	return b.API("POST", "/api/v1/auth/login", `{"email":"`+email+`","password":"`+password+`"}`)
+}
+
+func (b *browser) change(t *testing.T, current, email string) *httptest.ResponseRecorder {
+	return b.API("POST", "/api/v1/auth/email/change", `{"currentPassword":"`+current+`","newEmail":"`+email+`"}`)
+}
+
+func (b *browser) confirm(t *testing.T, token string) *httptest.ResponseRecorder {
+	return b.API("POST", "/api/v1/auth/email/confirm", `{"token":"`+token+`"}`)
+}
+
+func (b *browser) revert(t *testing.T, token string) *httptest.ResponseRecorder {
+	return b.API("POST", "/api/v1/auth/email/revert", `{"token":"`+token+`"}`)
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) authapi.EmailChangeResponse {
+	var response authapi.EmailChangeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestEmailAPI_Change(t *testing.T) {
+	env := setupEmailRouter(t)
+	laptop := env.browser()
+	require.Equal(t, http.StatusOK, laptop.login(t, oldEmail).Code)
+
+	w := laptop.change(t, password, newEmail)
+
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	requested := decode(t, w)
+	assert.Equal(t, oldEmail, requested.Email)
+	assert.Equal(t, newEmail, requested.PendingEmail)
+	require.NotNil(t, requested.ExpiresAt)
+	assert.Contains(t, env.outbox.LastTo(oldEmail).Body, newEmail, "the old address is told about the change")
+	assert.Equal(t, http.StatusOK, env.browser().login(t, oldEmail).Code, "the old email works until confirmed")
+
+	// The link may be opened on another device
+	w = env.browser().confirm(t, env.token(t, newEmail, confirmPattern))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, newEmail, decode(t, w).Email)
+	assert.Equal(t, http.StatusOK, env.browser().login(t, newEmail).Code)
+	assert.Equal(t, http.StatusUnauthorized, env.browser().login(t, oldEmail).Code)
+
+	w = env.browser().confirm(t, env.token(t, newEmail, confirmPattern))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "links work once")
+}
+
+func TestEmailAPI_Refusals(t *testing.T) {
+	env := setupEmailRouter(t)
+	env.signup(t, "john@example.com", "john")
+	b := env.browser()
+	require.Equal(t, http.StatusOK, b.login(t, oldEmail).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, b.change(t, "wrong password", newEmail).Code)
+	assert.Equal(t, http.StatusBadRequest, b.change(t, password, oldEmail).Code)
+	assert.Equal(t, http.StatusConflict, b.change(t, password, "john@example.com").Code)
+	assert.Equal(t, http.StatusBadRequest, b.change(t, password, "not an email").Code)
+	assert.Equal(t, http.StatusBadRequest, env.browser().confirm(t, "ecc_unknown").Code)
+	assert.Equal(t, http.StatusBadRequest, env.browser().revert(t, "ecr_unknown").Code)
+}
+
+func TestEmailAPI_TakenBeforeConfirmed(t *testing.T) {
+	env := setupEmailRouter(t)
+	b := env.browser()
+	require.Equal(t, http.StatusOK, b.login(t, oldEmail).Code)
+	require.Equal(t, http.StatusAccepted, b.change(t, password, newEmail).Code)
+
+	env.signup(t, newEmail, "other")
+	w := env.browser().confirm(t, env.token(t, newEmail, confirmPattern))
+
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusOK, env.browser().login(t, oldEmail).Code, "the user keeps the old email")
+}
+
+func TestEmailAPI_NewRequestVoidsPending(t *testing.T) {
+	env := setupEmailRouter(t)
+	b := env.browser()
+	require.Equal(t, http.StatusOK, b.login(t, oldEmail).Code)
+	require.Equal(t, http.StatusAccepted, b.change(t, password, newEmail).Code)
+	first := env.token(t, newEmail, confirmPattern)
+
+	require.Equal(t, http.StatusAccepted, b.change(t, password, "jane@home.example.com").Code)
+
+	assert.Equal(t, http.StatusBadRequest, env.browser().confirm(t, first).Code)
+	w := env.browser().confirm(t, env.token(t, "jane@home.example.com", confirmPattern))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestEmailAPI_Revert(t *testing.T) {
+	env := setupEmailRouter(t)
+	laptop := env.browser()
+	require.Equal(t, http.StatusOK, laptop.login(t, oldEmail).Code)
+	require.Equal(t, http.StatusAccepted, laptop.change(t, password, newEmail).Code)
+	revertToken := env.token(t, oldEmail, revertPattern)
+	require.Equal(t, http.StatusOK, env.browser().confirm(t, env.token(t, newEmail, confirmPattern)).Code)
+
+	w := env.browser().revert(t, revertToken)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, oldEmail, decode(t, w).Email)
+	assert.Equal(t, http.StatusUnauthorized, laptop.change(t, password, newEmail).Code, "every session is ended")
+	env.refreshTokens.AssertCalled(t, "RevokeUser", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusOK, env.browser().login(t, oldEmail).Code)
+	assert.Equal(t, http.StatusUnauthorized, env.browser().login(t, newEmail).Code)
+
+	assert.Equal(t, http.StatusBadRequest, env.browser().revert(t, revertToken).Code, "links work once")
+}
+
+func TestEmailAPI_RevertPending(t *testing.T) {
+	env := setupEmailRouter(t)
+	b := env.browser()
+	require.Equal(t, http.StatusOK, b.login(t, oldEmail).Code)
+	require.Equal(t, http.StatusAccepted, b.change(t, password, newEmail).Code)
+
+	w := env.browser().revert(t, env.token(t, oldEmail, revertPattern))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusBadRequest, env.browser().confirm(t, env.token(t, newEmail, confirmPattern)).Code,
+		"reverting cancels the change")
+}
+
+func TestEmailAPI_RequiresSession(t *testing.T) {
+	env := setupEmailRouter(t)
+
+	w := env.browser().change(t, password, newEmail)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+}
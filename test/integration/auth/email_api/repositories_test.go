@@ -0,0 +1,83 @@
+package email_api_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+)
+
+// The memory repositories keep records in memory so that an email change can be followed from
+// the request to the emailed links
+
+type memoryChanges struct {
+	changes []*entity.EmailChange
+	mu      sync.Mutex
+}
+
+func (r *memoryChanges) Create(_ context.Context, change *entity.EmailChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *change
+	stored.CreatedAt = time.Now()
+	r.changes = append(r.changes, &stored)
+	return nil
+}
+
+func (r *memoryChanges) FindByConfirmHash(_ context.Context, confirmHash string) (*entity.EmailChange, error) {
+	return r.find(func(c *entity.EmailChange) bool { return c.ConfirmHash == confirmHash })
+}
+
+func (r *memoryChanges) FindByRevertHash(_ context.Context, revertHash string) (*entity.EmailChange, error) {
+	return r.find(func(c *entity.EmailChange) bool { return c.RevertHash == revertHash })
+}
+
+func (r *memoryChanges) Confirm(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, change := range r.changes {
+		if change.ID == id && change.ConfirmedAt == nil && change.RevertedAt == nil {
+			change.ConfirmedAt = &at
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryChanges) Revert(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, change := range r.changes {
+		if change.ID == id && change.RevertedAt == nil {
+			change.RevertedAt = &at
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryChanges) RevertPending(_ context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, change := range r.changes {
+		if change.UserID == userID && change.ConfirmedAt == nil && change.RevertedAt == nil {
+			change.RevertedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryChanges) find(match func(*entity.EmailChange) bool) (*entity.EmailChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, change := range r.changes {
+		if match(change) {
+			found := *change
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
@@ -0,0 +1,560 @@
+package oauth_api_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	oauthservice "example.com/internal/domain/service/oauth"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	publicURL   = "http://localhost:8080"
+	loginURL    = "http://app.example.com/login"
+	consentURL  = "http://app.example.com/consent"
+	redirectURI = "https://partner.example.com/callback"
+	verifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+// existingUser can log in with password123
+var existingUser = &entity.User{
+	ID:           "8f14e45f-ceea-467f-a0e5-4a3f4e2c3b1d",
+	Email:        "test@example.com",
+	UserName:     "testuser",
+	PasswordHash: "hashed_password",
+}
+
+type testEnv struct {
+	cfg    *config.Config
+	router *gin.Engine
+	tokens *memoryTokens
+}
+
+func setupOAuthRouter(t *testing.T, enabled bool) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	cfg := browsertest.Config()
+	cfg.OAuth = config.OAuthServerConfig{
+		Enabled:        enabled,
+		LoginURL:       loginURL,
+		ConsentURL:     consentURL,
+		CodeTTL:        time.Minute,
+		AccessTokenTTL: time.Hour,
+	}
+	return newOAuthEnv(t, cfg)
+}
+
+// setupTenantOAuthRouter serves the tenants acme and globex, chosen by the X-Tenant-ID header
+func setupTenantOAuthRouter(t *testing.T) *testEnv {
+	env := setupOAuthRouter(t, true)
+	env.cfg.Tenancy = config.TenancyConfig{Enabled: true, Tenants: []string{"acme", "globex"}, Header: "X-Tenant-ID"}
+	return newOAuthEnv(t, env.cfg)
+}
+
+func newOAuthEnv(t *testing.T, cfg *config.Config) *testEnv {
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	issuer, err := security.NewJWTIssuer(publicURL, []security.SigningKey{
+		{ID: "ed-1", Algorithm: security.AlgEdDSA, Signer: private},
+	}, 15*time.Minute)
+	require.NoError(t, err)
+
+	users := mocks.NewMemoryUserRepository(existingUser)
+	tokens := &memoryTokens{}
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", "password123", "hashed_password").Return(true)
+	hasher.On("Verify", mock.Anything, mock.Anything).Return(false)
+
+	authSvc := authservice.NewService(users, hasher)
+	oauthSvc := oauthservice.NewService(
+		&memoryClients{}, &memoryCodes{}, &memoryConsents{consents: map[string]*entity.OAuthConsent{}}, tokens, users, issuer,
+		oauthservice.Config{CodeTTL: cfg.OAuth.CodeTTL, AccessTokenTTL: cfg.OAuth.AccessTokenTTL},
+	)
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User: &api.UserAPIHandler{},
+		OAuth: api.NewOAuthAPIHandler(
+			authusecase.NewAuthorizeUseCase(oauthSvc),
+			authusecase.NewConsentUseCase(oauthSvc),
+			authusecase.NewOAuthTokenUseCase(oauthSvc),
+			authusecase.NewIntrospectOAuthTokenUseCase(oauthSvc),
+			authusecase.NewRevokeOAuthTokenUseCase(oauthSvc),
+			testLogger,
+			api.OAuthServerSettings{
+				Issuer:            publicURL,
+				BaseURL:           publicURL,
+				LoginURL:          loginURL,
+				ConsentURL:        consentURL,
+				SigningAlgorithms: []string{security.AlgEdDSA},
+			},
+		),
+		OAuthClients: api.NewOAuthClientAPIHandler(
+			authusecase.NewRegisterOAuthClientUseCase(oauthSvc),
+			authusecase.NewListOAuthClientsUseCase(oauthSvc),
+			authusecase.NewDeleteOAuthClientUseCase(oauthSvc),
+			testLogger,
+		),
+	})
+	require.NoError(t, err)
+
+	return &testEnv{cfg: cfg, router: router, tokens: tokens}
+}
+
+// browser is one user agent of the frontend
+type browser struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) browser() *browser {
+	return &browser{Browser: browsertest.New(e.router)}
+}
+
+func (b *browser) login(t *testing.T) {
+	b.Login(t, existingUser.Email, "password123")
+}
+
+// registerClient registers a client as the logged in user of b
+func (b *browser) registerClient(t *testing.T, request authapi.CreateOAuthClientRequest) authapi.CreateOAuthClientResponse {
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+	w := b.API("POST", "/api/v1/oauth2/clients", string(body))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var response authapi.CreateOAuthClientResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func partnerClient() authapi.CreateOAuthClientRequest {
+	return authapi.CreateOAuthClientRequest{
+		Name:         "Partner",
+		RedirectUris: []string{redirectURI},
+		GrantTypes:   []string{entity.GrantTypeAuthorizationCode, entity.GrantTypeClientCredentials},
+		Scopes:       []authapi.OAuthScope{authapi.OAUTH_SCOPE_OPENID, authapi.OAUTH_SCOPE_EMAIL},
+	}
+}
+
+func authorizePath(clientID, scope string) string {
+	digest := sha256.Sum256([]byte(verifier))
+	return "/oauth2/authorize?" + url.Values{
+		"client_id":             {clientID},
+		"redirect_uri":          {redirectURI},
+		"response_type":         {"code"},
+		"scope":                 {scope},
+		"state":                 {"state-1"},
+		"nonce":                 {"nonce-1"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(digest[:])},
+		"code_challenge_method": {"S256"},
+	}.Encode()
+}
+
+func withoutParam(path, name string) string {
+	parsed, _ := url.Parse(path)
+	query := parsed.Query()
+	query.Del(name)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+func location(t *testing.T, raw string) *url.URL {
+	parsed, err := url.Parse(raw)
+	require.NoError(t, err)
+	return parsed
+}
+
+// post sends a form to an endpoint of the authorization server, authenticating with HTTP Basic
+// when a secret is given and with the client_id form value otherwise
+func (e *testEnv) post(path, clientID, secret string, form url.Values) *httptest.ResponseRecorder {
+	if secret == "" {
+		form.Set("client_id", clientID)
+	}
+	req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if secret != "" {
+		req.SetBasicAuth(clientID, secret)
+	}
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+func (e *testEnv) redeem(t *testing.T, clientID, secret, code string) *httptest.ResponseRecorder {
+	return e.post("/oauth2/token", clientID, secret, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	})
+}
+
+func (e *testEnv) introspect(t *testing.T, clientID, secret, token string) authapi.OAuthIntrospection {
+	w := e.post("/oauth2/introspect", clientID, secret, url.Values{"token": {token}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var introspection authapi.OAuthIntrospection
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &introspection))
+	return introspection
+}
+
+func oauthError(t *testing.T, w *httptest.ResponseRecorder) string {
+	var response authapi.OAuthError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return response.Error
+}
+
+func TestOAuth_Discovery(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+
+	w := env.browser().Get("/.well-known/openid-configuration")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var discovery authapi.OpenIdConfiguration
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &discovery))
+	assert.Equal(t, publicURL, discovery.Issuer)
+	assert.Equal(t, publicURL+"/oauth2/authorize", discovery.AuthorizationEndpoint)
+	assert.Equal(t, publicURL+"/oauth2/token", discovery.TokenEndpoint)
+	assert.Equal(t, publicURL+"/.well-known/jwks.json", discovery.JwksUri)
+	assert.Equal(t, []string{"EdDSA"}, discovery.IdTokenSigningAlgValuesSupported)
+	assert.Equal(t, []string{"S256"}, discovery.CodeChallengeMethodsSupported)
+	assert.Contains(t, discovery.ScopesSupported, "openid")
+}
+
+func TestOAuth_DisabledServerServesNothing(t *testing.T) {
+	env := setupOAuthRouter(t, false)
+
+	assert.Equal(t, http.StatusNotFound, env.browser().Get("/.well-known/openid-configuration").Code)
+	assert.Equal(t, http.StatusNotFound, env.browser().Get(authorizePath("client", "email")).Code)
+}
+
+func TestOAuth_AuthorizationCodeFlow(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	owner := env.browser()
+	owner.login(t)
+	client := owner.registerClient(t, partnerClient())
+	require.NotEmpty(t, client.ClientSecret)
+
+	// A user who is not logged in is sent to the login page, which returns to the request
+	b := env.browser()
+	w := b.Get(authorizePath(client.Client.Id, "openid email"))
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	login := location(t, w.Header().Get("Location"))
+	assert.Equal(t, loginURL, login.Scheme+"://"+login.Host+login.Path)
+	returnTo := location(t, login.Query().Get("return_to"))
+	assert.Equal(t, publicURL, returnTo.Scheme+"://"+returnTo.Host)
+
+	// Once logged in, the user is asked for consent
+	b.login(t)
+	w = b.Get(returnTo.RequestURI())
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	assert.Equal(t, consentURL, w.Header().Get("Location"))
+
+	w = b.API("GET", "/api/v1/oauth2/consent", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var consent authapi.OAuthConsent
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &consent))
+	assert.Equal(t, "Partner", consent.ClientName)
+	assert.Equal(t, []authapi.OAuthScope{authapi.OAUTH_SCOPE_EMAIL, authapi.OAUTH_SCOPE_OPENID}, consent.Scopes)
+
+	w = b.API("POST", "/api/v1/oauth2/consent", `{"approve":true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result authapi.OAuthConsentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	callback := location(t, result.RedirectTo)
+	assert.Equal(t, "state-1", callback.Query().Get("state"))
+	code := callback.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	// The decision was consumed
+	assert.Equal(t, http.StatusNotFound, b.API("GET", "/api/v1/oauth2/consent", "").Code)
+
+	// The client redeems the code without XSRF token or session
+	w = env.redeem(t, client.Client.Id, client.ClientSecret, code)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	var tokens authapi.OAuthTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, "openid email", tokens.Scope)
+	assert.InDelta(t, time.Hour.Seconds(), tokens.ExpiresIn, 2)
+	require.NotEmpty(t, tokens.IdToken)
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(tokens.IdToken, ".")[1])
+	require.NoError(t, err)
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	assert.Equal(t, existingUser.ID, claims["sub"])
+	assert.Equal(t, client.Client.Id, claims["aud"])
+	assert.Equal(t, "nonce-1", claims["nonce"])
+	assert.Equal(t, existingUser.Email, claims["email"])
+
+	introspection := env.introspect(t, client.Client.Id, client.ClientSecret, tokens.AccessToken)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, existingUser.ID, introspection.Sub)
+	assert.Equal(t, client.Client.Id, introspection.ClientId)
+
+	// The consent is remembered: the next request returns straight to the client
+	w = b.Get(authorizePath(client.Client.Id, "email"))
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	again := location(t, w.Header().Get("Location"))
+	assert.Equal(t, redirectURI, again.Scheme+"://"+again.Host+again.Path)
+	assert.NotEmpty(t, again.Query().Get("code"))
+}
+
+func TestOAuth_CodeReplayRevokesTokens(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+
+	require.Equal(t, http.StatusFound, b.Get(authorizePath(client.Client.Id, "email")).Code)
+	w := b.API("POST", "/api/v1/oauth2/consent", `{"approve":true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result authapi.OAuthConsentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	code := location(t, result.RedirectTo).Query().Get("code")
+
+	w = env.redeem(t, client.Client.Id, client.ClientSecret, code)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tokens authapi.OAuthTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+	assert.Empty(t, tokens.IdToken, "no ID token without the openid scope")
+
+	w = env.redeem(t, client.Client.Id, client.ClientSecret, code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid_grant", oauthError(t, w))
+	assert.False(t, env.introspect(t, client.Client.Id, client.ClientSecret, tokens.AccessToken).Active)
+}
+
+func TestOAuth_ConsentDenied(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+
+	require.Equal(t, http.StatusFound, b.Get(authorizePath(client.Client.Id, "email")).Code)
+	w := b.API("POST", "/api/v1/oauth2/consent", `{"approve":false}`)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result authapi.OAuthConsentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	callback := location(t, result.RedirectTo)
+	assert.Equal(t, "access_denied", callback.Query().Get("error"))
+	assert.Equal(t, "state-1", callback.Query().Get("state"))
+	assert.Empty(t, callback.Query().Get("code"))
+}
+
+func TestOAuth_ConsentBelongsToTheRequestingUser(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+	require.Equal(t, http.StatusFound, b.Get(authorizePath(client.Client.Id, "email")).Code)
+
+	// Another browser has no pending request, and a logged out one cannot decide
+	other := env.browser()
+	other.login(t)
+	assert.Equal(t, http.StatusNotFound, other.API("POST", "/api/v1/oauth2/consent", `{"approve":true}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, env.browser().API("GET", "/api/v1/oauth2/consent", "").Code)
+}
+
+func TestOAuth_AuthorizeRequiresASessionOfTheTenant(t *testing.T) {
+	env := setupTenantOAuthRouter(t)
+	b := env.browser()
+	b.Header.Set("X-Tenant-ID", "acme")
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+	require.Equal(t, http.StatusFound, b.Get(authorizePath(client.Client.Id, "email")).Code)
+	assert.Equal(t, consentURL, b.Get(authorizePath(client.Client.Id, "email")).Header().Get("Location"))
+
+	// The session cookie reaches globex too, but authenticates nobody there
+	b.Header.Set("X-Tenant-ID", "globex")
+	w := b.Get(authorizePath(client.Client.Id, "email"))
+
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	assert.True(t, strings.HasPrefix(w.Header().Get("Location"), loginURL+"?"), w.Header().Get("Location"))
+	assert.Equal(t, http.StatusUnauthorized, b.API("GET", "/api/v1/oauth2/consent", "").Code)
+}
+
+func TestOAuth_AuthorizeRejections(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+
+	t.Run("unregistered redirect URI is not followed", func(t *testing.T) {
+		path := strings.Replace(authorizePath(client.Client.Id, "email"), url.QueryEscape(redirectURI),
+			url.QueryEscape("https://attacker.example.com/callback"), 1)
+
+		w := b.Get(path)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_request", oauthError(t, w))
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		w := b.Get(authorizePath("3c1c9b4e-1f0a-4d7e-8d56-9a1f0f1c2b3d", "email"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_client", oauthError(t, w))
+	})
+
+	redirected := []struct {
+		name  string
+		path  string
+		error string
+	}{
+		{name: "missing PKCE", path: withoutParam(authorizePath(client.Client.Id, "email"), "code_challenge"), error: "invalid_request"},
+		{name: "unregistered scope", path: authorizePath(client.Client.Id, "profile"), error: "invalid_scope"},
+	}
+	for _, tt := range redirected {
+		t.Run(tt.name, func(t *testing.T) {
+			w := b.Get(tt.path)
+
+			require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+			callback := location(t, w.Header().Get("Location"))
+			assert.Equal(t, redirectURI, callback.Scheme+"://"+callback.Host+callback.Path)
+			assert.Equal(t, tt.error, callback.Query().Get("error"))
+			assert.Equal(t, "state-1", callback.Query().Get("state"))
+		})
+	}
+}
+
+func TestOAuth_ClientCredentials(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+
+	// client_secret_post
+	form := url.Values{"grant_type": {"client_credentials"}, "scope": {"email"}, "client_secret": {client.ClientSecret}}
+	w := env.post("/oauth2/token", client.Client.Id, "", form)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tokens authapi.OAuthTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+	assert.Empty(t, tokens.IdToken)
+
+	introspection := env.introspect(t, client.Client.Id, client.ClientSecret, tokens.AccessToken)
+	assert.True(t, introspection.Active)
+	assert.Empty(t, introspection.Sub)
+	assert.Equal(t, "email", introspection.Scope)
+
+	w = env.post("/oauth2/revoke", client.Client.Id, client.ClientSecret, url.Values{"token": {tokens.AccessToken}})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, env.introspect(t, client.Client.Id, client.ClientSecret, tokens.AccessToken).Active)
+
+	// openid needs a user
+	w = env.post("/oauth2/token", client.Client.Id, client.ClientSecret, url.Values{"grant_type": {"client_credentials"}, "scope": {"openid"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid_scope", oauthError(t, w))
+}
+
+func TestOAuth_PublicClient(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+	b.login(t)
+	client := b.registerClient(t, authapi.CreateOAuthClientRequest{
+		Name:         "SPA",
+		RedirectUris: []string{redirectURI},
+		GrantTypes:   []string{entity.GrantTypeAuthorizationCode},
+		Scopes:       []authapi.OAuthScope{authapi.OAUTH_SCOPE_EMAIL},
+		Public:       true,
+	})
+	assert.Empty(t, client.ClientSecret)
+	assert.False(t, client.Client.Confidential)
+
+	require.Equal(t, http.StatusFound, b.Get(authorizePath(client.Client.Id, "email")).Code)
+	w := b.API("POST", "/api/v1/oauth2/consent", `{"approve":true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result authapi.OAuthConsentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+	w = env.redeem(t, client.Client.Id, "", location(t, result.RedirectTo).Query().Get("code"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tokens authapi.OAuthTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+
+	// Public clients cannot prove who they are, so they may not inspect tokens
+	w = env.post("/oauth2/introspect", client.Client.Id, "", url.Values{"token": {tokens.AccessToken}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestOAuth_TokenRejectsWrongSecret(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+
+	w := env.post("/oauth2/token", client.Client.Id, "ocs_wrong", url.Values{"grant_type": {"client_credentials"}})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "invalid_client", oauthError(t, w))
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+	assert.Empty(t, env.tokens.tokens)
+}
+
+func TestOAuth_ClientManagement(t *testing.T) {
+	env := setupOAuthRouter(t, true)
+	b := env.browser()
+
+	assert.Equal(t, http.StatusUnauthorized, b.API("GET", "/api/v1/oauth2/clients", "").Code)
+
+	b.login(t)
+	client := b.registerClient(t, partnerClient())
+
+	w := b.API("POST", "/api/v1/oauth2/clients",
+		`{"name":"Insecure","redirectUris":["http://partner.example.com/cb"],"grantTypes":["authorization_code"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = b.API("GET", "/api/v1/oauth2/clients", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list authapi.OAuthClientList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Clients, 1)
+	assert.Equal(t, client.Client.Id, list.Clients[0].Id)
+	assert.True(t, list.Clients[0].Confidential)
+
+	w = b.API("DELETE", "/api/v1/oauth2/clients/"+client.Client.Id, "")
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = b.API("DELETE", "/api/v1/oauth2/clients/"+client.Client.Id, "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
@@ -0,0 +1,177 @@
+package oauth_api_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+)
+
+// The memory repositories keep records in memory so that a flow can be followed end to end
+
+type memoryClients struct {
+	clients []*entity.OAuthClient
+	mu      sync.Mutex
+}
+
+func (r *memoryClients) Create(_ context.Context, client *entity.OAuthClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client.CreatedAt = time.Now()
+	r.clients = append(r.clients, client)
+	return nil
+}
+
+func (r *memoryClients) FindByID(_ context.Context, id string) (*entity.OAuthClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, client := range r.clients {
+		if client.ID == id {
+			return client, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryClients) FindByOwnerID(_ context.Context, ownerID string) ([]*entity.OAuthClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entity.OAuthClient
+	for _, client := range r.clients {
+		if client.OwnerID == ownerID {
+			found = append(found, client)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryClients) Delete(_ context.Context, ownerID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, client := range r.clients {
+		if client.ID == id && client.OwnerID == ownerID {
+			r.clients = append(r.clients[:i], r.clients[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+type memoryCodes struct {
+	codes []*entity.OAuthAuthorizationCode
+	mu    sync.Mutex
+}
+
+func (r *memoryCodes) Create(_ context.Context, code *entity.OAuthAuthorizationCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes = append(r.codes, code)
+	return nil
+}
+
+func (r *memoryCodes) FindByHash(_ context.Context, codeHash string) (*entity.OAuthAuthorizationCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, code := range r.codes {
+		if code.CodeHash == codeHash {
+			found := *code
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryCodes) MarkUsed(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, code := range r.codes {
+		if code.ID == id && code.UsedAt == nil {
+			code.UsedAt = &at
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryCodes) RevokeUser(_ context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, code := range r.codes {
+		if code.UserID == userID && code.UsedAt == nil {
+			code.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+type memoryConsents struct {
+	consents map[string]*entity.OAuthConsent
+	mu       sync.Mutex
+}
+
+func (r *memoryConsents) Find(_ context.Context, userID, clientID string) (*entity.OAuthConsent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if consent, ok := r.consents[userID+"/"+clientID]; ok {
+		return consent, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryConsents) Save(_ context.Context, consent *entity.OAuthConsent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.consents[consent.UserID+"/"+consent.ClientID] = consent
+	return nil
+}
+
+type memoryTokens struct {
+	tokens []*entity.OAuthAccessToken
+	mu     sync.Mutex
+}
+
+func (r *memoryTokens) Create(_ context.Context, token *entity.OAuthAccessToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token.CreatedAt = time.Now()
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryTokens) FindByHash(_ context.Context, tokenHash string) (*entity.OAuthAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTokens) Revoke(_ context.Context, id string, at time.Time) error {
+	return r.revoke(func(token *entity.OAuthAccessToken) bool { return token.ID == id }, at)
+}
+
+func (r *memoryTokens) RevokeByCode(_ context.Context, codeID string, at time.Time) error {
+	return r.revoke(func(token *entity.OAuthAccessToken) bool { return token.CodeID == codeID }, at)
+}
+
+func (r *memoryTokens) RevokeUser(_ context.Context, userID string, at time.Time) error {
+	return r.revoke(func(token *entity.OAuthAccessToken) bool { return token.UserID == userID }, at)
+}
+
+func (r *memoryTokens) revoke(match func(*entity.OAuthAccessToken) bool, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+package oidc_api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	identityservice "example.com/internal/domain/service/identity"
+	usernameservice "example.com/internal/domain/service/username"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const appURL = "http://app.example.com/account"
+
+// memoryIdentities keeps identities in memory so that provisioning and linking can be followed
+// end to end
+type memoryIdentities struct {
+	identities []*entity.UserIdentity
+	mu         sync.Mutex
+}
+
+func (r *memoryIdentities) Create(_ context.Context, identity *entity.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	identity.CreatedAt = time.Now()
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *memoryIdentities) FindByProviderSubject(_ context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryIdentities) FindByUserID(_ context.Context, userID string) ([]*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entity.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			found = append(found, identity)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryIdentities) UpdateLastLogin(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.ID == id {
+			identity.LastLoginAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryIdentities) Delete(_ context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, identity := range r.identities {
+		if identity.ID == id && identity.UserID == userID {
+			r.identities = append(r.identities[:i], r.identities[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+type testEnv struct {
+	router     *gin.Engine
+	provider   *mockProvider
+	users      *mocks.MemoryUserRepository
+	identities *memoryIdentities
+}
+
+// existingUser has signed up with a password and can log in with password123
+var existingUser = &entity.User{
+	ID:           "8f14e45f-ceea-467f-a0e5-4a3f4e2c3b1d",
+	Email:        "test@example.com",
+	UserName:     "testuser",
+	PasswordHash: "hashed_password",
+}
+
+func setupOIDCRouter(t *testing.T) *testEnv {
+	gin.SetMode(gin.TestMode)
+	provider := newMockProvider(t)
+
+	cfg := browsertest.Config()
+	cfg.OIDC = config.OIDCConfig{
+		Providers:   []string{"acme"},
+		RedirectURL: appURL,
+		Provider: map[string]*config.OIDCProviderConfig{
+			"acme": {
+				Issuer:       provider.URL,
+				ClientID:     testClientID,
+				ClientSecret: testClientSecret,
+				DisplayName:  "Acme",
+				Scopes:       []string{"openid", "email"},
+			},
+		},
+	}
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	users := mocks.NewMemoryUserRepository(existingUser)
+	identities := &memoryIdentities{}
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", "password123", "hashed_password").Return(true)
+	hasher.On("Hash", mock.AnythingOfType("string")).Return("random_password_hash", nil)
+
+	authSvc := authservice.NewService(users, hasher)
+	// Renames are not served by these tests, so usernames only need to be free
+	usernameChanges := &mocks.MockUserNameChangeRepository{}
+	usernameChanges.On("FindRedirect", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	usernameSvc := usernameservice.NewService(users, usernameChanges, nil, usernameservice.Config{
+		Policy: security.NewUsernamePolicy(security.UsernameRules{MinLength: 3, MaxLength: entity.UserNameMaxLength}),
+	})
+	identitySvc := identityservice.NewService(identities, users, authSvc, usernameSvc)
+	registry := cfg.OIDC.Registry("http://localhost:8080")
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User: &api.UserAPIHandler{},
+		OIDC: api.NewOIDCAPIHandler(
+			authusecase.NewListOIDCProvidersUseCase(registry),
+			authusecase.NewStartOIDCUseCase(registry),
+			authusecase.NewOIDCLoginUseCase(registry, identitySvc),
+			authusecase.NewLinkIdentityUseCase(registry, identitySvc),
+			authusecase.NewListIdentitiesUseCase(identitySvc),
+			authusecase.NewUnlinkIdentityUseCase(identitySvc),
+			testLogger,
+			cfg.OIDC.RedirectURL,
+		),
+	})
+	require.NoError(t, err)
+
+	return &testEnv{router: router, provider: provider, users: users, identities: identities}
+}
+
+// browser is one user agent of the frontend
+type browser struct {
+	*browsertest.Browser
+	env *testEnv
+}
+
+func (e *testEnv) browser() *browser {
+	return &browser{Browser: browsertest.New(e.router), env: e}
+}
+
+// signIn runs the redirect flow started at startPath with who signing in at the provider and
+// returns where the callback sends the browser
+func (b *browser) signIn(t *testing.T, startPath string, who identity) *url.URL {
+	w := b.Get(startPath)
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+
+	code, state := b.env.provider.approve(t, w.Header().Get("Location"), who)
+	return b.callback(t, url.Values{"code": {code}, "state": {state}})
+}
+
+func (b *browser) callback(t *testing.T, query url.Values) *url.URL {
+	w := b.Get("/api/v1/auth/oidc/acme/callback?" + query.Encode())
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	return location
+}
+
+func (b *browser) identities(t *testing.T) []authapi.UserIdentity {
+	w := b.API("GET", "/api/v1/auth/identities", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var list authapi.UserIdentityList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	return list.Identities
+}
+
+var newcomer = identity{Subject: "acme-42", Email: "new@example.com", EmailVerified: true}
+
+func TestOIDC_ListProviders(t *testing.T) {
+	env := setupOIDCRouter(t)
+
+	w := env.browser().Get("/api/v1/auth/oidc/providers")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var list authapi.OidcProviderList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, []authapi.OidcProvider{{Name: "acme", DisplayName: "Acme"}}, list.Providers)
+}
+
+func TestOIDC_UnknownProvider(t *testing.T) {
+	env := setupOIDCRouter(t)
+
+	w := env.browser().Get("/api/v1/auth/oidc/other/login")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOIDC_LoginProvisionsUserOnce(t *testing.T) {
+	env := setupOIDCRouter(t)
+	b := env.browser()
+
+	location := b.signIn(t, "/api/v1/auth/oidc/acme/login", newcomer)
+
+	assert.Equal(t, appURL, location.String())
+	provisioned, err := env.users.FindByEmail(context.Background(), newcomer.Email)
+	require.NoError(t, err)
+	assert.Equal(t, "new", provisioned.UserName)
+
+	identities := b.identities(t)
+	require.Len(t, identities, 1)
+	assert.Equal(t, "acme", identities[0].Provider)
+	assert.Equal(t, newcomer.Email, identities[0].Email)
+
+	// Signing in again, from another browser, finds the same user
+	again := env.browser()
+	assert.Equal(t, appURL, again.signIn(t, "/api/v1/auth/oidc/acme/login", newcomer).String())
+	assert.Equal(t, 2, env.users.Len())
+	assert.Len(t, again.identities(t), 1)
+}
+
+func TestOIDC_LoginRejections(t *testing.T) {
+	tests := []struct {
+		name string
+		who  identity
+		want string
+	}{
+		{
+			name: "unverified email",
+			who:  identity{Subject: "acme-1", Email: "new@example.com"},
+			want: "email_not_verified",
+		},
+		{
+			name: "email of a password user",
+			who:  identity{Subject: "acme-2", Email: existingUser.Email, EmailVerified: true},
+			want: "email_in_use",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupOIDCRouter(t)
+			b := env.browser()
+
+			location := b.signIn(t, "/api/v1/auth/oidc/acme/login", tt.who)
+
+			assert.Equal(t, tt.want, location.Query().Get("oidc_error"))
+			assert.Empty(t, env.identities.identities)
+			assert.Equal(t, 1, env.users.Len())
+		})
+	}
+}
+
+func TestOIDC_CallbackRequiresMatchingState(t *testing.T) {
+	env := setupOIDCRouter(t)
+	b := env.browser()
+
+	w := b.Get("/api/v1/auth/oidc/acme/login")
+	require.Equal(t, http.StatusFound, w.Code)
+	code, state := env.provider.approve(t, w.Header().Get("Location"), newcomer)
+
+	// A callback forged for another browser carries a state this session never issued
+	location := env.browser().callback(t, url.Values{"code": {code}, "state": {state}})
+	assert.Equal(t, "invalid_state", location.Query().Get("oidc_error"))
+
+	location = b.callback(t, url.Values{"code": {code}, "state": {"forged"}})
+	assert.Equal(t, "invalid_state", location.Query().Get("oidc_error"))
+
+	// The failed attempt consumed the pending request, so the code cannot be replayed
+	location = b.callback(t, url.Values{"code": {code}, "state": {state}})
+	assert.Equal(t, "invalid_state", location.Query().Get("oidc_error"))
+	assert.Empty(t, env.identities.identities)
+}
+
+func TestOIDC_CallbackRejectsNonceMismatch(t *testing.T) {
+	env := setupOIDCRouter(t)
+	env.provider.nonceOverride = "replayed-nonce"
+
+	location := env.browser().signIn(t, "/api/v1/auth/oidc/acme/login", newcomer)
+
+	assert.Equal(t, "login_failed", location.Query().Get("oidc_error"))
+	assert.Empty(t, env.identities.identities)
+}
+
+func TestOIDC_ProviderDeclined(t *testing.T) {
+	env := setupOIDCRouter(t)
+	b := env.browser()
+
+	w := b.Get("/api/v1/auth/oidc/acme/login")
+	require.Equal(t, http.StatusFound, w.Code)
+	authURL, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+
+	location := b.callback(t, url.Values{"error": {"access_denied"}, "state": {authURL.Query().Get("state")}})
+
+	assert.Equal(t, "access_denied", location.Query().Get("oidc_error"))
+}
+
+func TestOIDC_LinkAndUnlink(t *testing.T) {
+	env := setupOIDCRouter(t)
+	b := env.browser()
+	b.Login(t, existingUser.Email, "password123")
+
+	location := b.signIn(t, "/api/v1/auth/oidc/acme/link", newcomer)
+
+	assert.Equal(t, "linked", location.Query().Get("oidc"))
+	identities := b.identities(t)
+	require.Len(t, identities, 1)
+
+	// The linked identity now signs in as the existing user instead of provisioning one
+	other := env.browser()
+	other.signIn(t, "/api/v1/auth/oidc/acme/login", newcomer)
+	assert.Equal(t, 1, env.users.Len())
+	assert.Equal(t, existingUser.ID, env.identities.identities[0].UserID)
+
+	w := b.API("DELETE", "/api/v1/auth/identities/"+identities[0].Id, "")
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Empty(t, b.identities(t))
+}
+
+func TestOIDC_LinkRequiresSession(t *testing.T) {
+	env := setupOIDCRouter(t)
+
+	w := env.browser().Get("/api/v1/auth/oidc/acme/link")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOIDC_CannotUnlinkOnlyLoginOfProvisionedUser(t *testing.T) {
+	env := setupOIDCRouter(t)
+	b := env.browser()
+	b.signIn(t, "/api/v1/auth/oidc/acme/login", newcomer)
+	identities := b.identities(t)
+	require.Len(t, identities, 1)
+
+	w := b.API("DELETE", "/api/v1/auth/identities/"+identities[0].Id, "")
+
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Len(t, b.identities(t), 1)
+}
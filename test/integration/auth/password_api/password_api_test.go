@@ -0,0 +1,218 @@
+package password_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+	authservice "example.com/internal/domain/service/auth"
+	passwordservice "example.com/internal/domain/service/password"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	email           = "test@example.com"
+	initialPassword = "initial secret"
+	newPassword     = "brand new secret"
+)
+
+type testEnv struct {
+	router        *gin.Engine
+	history       *memoryHistory
+	refreshTokens *mocks.MockRefreshTokenRepository
+	apiTokens     *mocks.MockAPITokenRepository
+	oauthCodes    *mocks.MockOAuthAuthorizationCodeRepository
+	oauthTokens   *mocks.MockOAuthAccessTokenRepository
+}
+
+func setupPasswordRouter(t *testing.T, historySize int) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	cfg := browsertest.Config()
+	cfg.Password = config.PasswordConfig{MinLength: 8, MaxLength: 72, MinCharClasses: 1, HistorySize: historySize}
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	hasher := security.NewBcryptHasher()
+	users := mocks.NewMemoryUserRepository()
+	history := &memoryHistory{}
+	refreshTokens := &mocks.MockRefreshTokenRepository{}
+	refreshTokens.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	apiTokens := &mocks.MockAPITokenRepository{}
+	apiTokens.On("FindByUserID", mock.Anything, mock.Anything).Return([]*entity.APIToken{}, nil)
+	apiTokens.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	oauthCodes := &mocks.MockOAuthAuthorizationCodeRepository{}
+	oauthCodes.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	oauthTokens := &mocks.MockOAuthAccessTokenRepository{}
+	oauthTokens.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	policy, err := cfg.Password.Policy()
+	require.NoError(t, err)
+	authSvc := authservice.NewService(users, hasher)
+	passwordSvc := passwordservice.NewService(
+		users, history, refreshTokens, apiTokens, oauthCodes, oauthTokens, hasher,
+		passwordservice.Config{Policy: policy, HistorySize: cfg.Password.HistorySize},
+	)
+
+	apiTokenSvc := apitokenservice.NewService(apiTokens)
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, passwordSvc, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User: &api.UserAPIHandler{},
+		APITokens: api.NewAPITokenAPIHandler(
+			authusecase.NewCreateAPITokenUseCase(apiTokenSvc),
+			authusecase.NewListAPITokensUseCase(apiTokenSvc),
+			authusecase.NewRevokeAPITokenUseCase(apiTokenSvc),
+			testLogger,
+		),
+		Password:        api.NewPasswordAPIHandler(authusecase.NewChangePasswordUseCase(passwordSvc), testLogger),
+		ValidateSession: authusecase.NewValidateSessionUseCase(authSvc),
+	})
+	require.NoError(t, err)
+
+	env := &testEnv{
+		router: router, history: history,
+		refreshTokens: refreshTokens, apiTokens: apiTokens, oauthCodes: oauthCodes, oauthTokens: oauthTokens,
+	}
+	w := env.browser().API("POST", "/api/v1/auth/signup", `{"email":"`+email+`","password":"`+initialPassword+`","username":"testuser"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	return env
+}
+
+// browser is one user agent of the frontend
+type browser struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) browser() *browser {
+	return &browser{Browser: browsertest.New(e.router)}
+}
+
+func (b *browser) login(t *testing.T, password string) *httptest.ResponseRecorder {
+	return b.API("POST", "/api/v1/auth/login", `{"email":"`+email+`","password":"`+password+`"}`)
+}
+
+func (b *browser) change(t *testing.T, current, next string) *httptest.ResponseRecorder {
+	return b.API("POST", "/api/v1/auth/password/change", `{"currentPassword":"`+current+`","newPassword":"`+next+`"}`)
+}
+
+// loggedIn reports whether the session of b authenticates a route requiring a session
+func (b *browser) loggedIn(t *testing.T) bool {
+	return b.API("GET", "/api/v1/auth/tokens", "").Code == http.StatusOK
+}
+
+func details(t *testing.T, w *httptest.ResponseRecorder) []authapi.FieldError {
+	var response authapi.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Details
+}
+
+func TestPasswordAPI_ChangeRevokesOtherSessions(t *testing.T) {
+	env := setupPasswordRouter(t, 0)
+	laptop, phone := env.browser(), env.browser()
+	require.Equal(t, http.StatusOK, laptop.login(t, initialPassword).Code)
+	require.Equal(t, http.StatusOK, phone.login(t, initialPassword).Code)
+
+	w := laptop.change(t, initialPassword, newPassword)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, laptop.loggedIn(t), "the session making the change stays logged in")
+	assert.False(t, phone.loggedIn(t), "other sessions are revoked")
+	for _, tokens := range []*mock.Mock{&env.refreshTokens.Mock, &env.apiTokens.Mock, &env.oauthCodes.Mock, &env.oauthTokens.Mock} {
+		tokens.AssertCalled(t, "RevokeUser", mock.Anything, mock.Anything, mock.Anything)
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, phone.login(t, initialPassword).Code)
+	assert.Equal(t, http.StatusOK, phone.login(t, newPassword).Code)
+	assert.True(t, phone.loggedIn(t))
+}
+
+func TestPasswordAPI_IncorrectCurrentPassword(t *testing.T) {
+	env := setupPasswordRouter(t, 0)
+	b := env.browser()
+	require.Equal(t, http.StatusOK, b.login(t, initialPassword).Code)
+
+	w := b.change(t, "wrong password", newPassword)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+	assert.True(t, b.loggedIn(t))
+	assert.Equal(t, http.StatusOK, env.browser().login(t, initialPassword).Code)
+}
+
+func TestPasswordAPI_PolicyViolations(t *testing.T) {
+	env := setupPasswordRouter(t, 0)
+	b := env.browser()
+	require.Equal(t, http.StatusOK, b.login(t, initialPassword).Code)
+
+	w := b.change(t, initialPassword, "password123")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Equal(t, []authapi.FieldError{{Field: "newPassword", In: "body", Message: "is too common"}}, details(t, w))
+
+	w = b.change(t, initialPassword, "testuser secret")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Equal(t, "must not contain your username or email", details(t, w)[0].Message)
+
+	w = b.change(t, initialPassword, initialPassword)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "the current password is not a new one")
+}
+
+func TestPasswordAPI_History(t *testing.T) {
+	env := setupPasswordRouter(t, 1)
+	b := env.browser()
+	require.Equal(t, http.StatusOK, b.login(t, initialPassword).Code)
+
+	require.Equal(t, http.StatusOK, b.change(t, initialPassword, newPassword).Code)
+	w := b.change(t, newPassword, initialPassword)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	require.Equal(t, http.StatusOK, b.change(t, newPassword, "third secret").Code)
+	assert.Equal(t, 1, env.history.count(), "only the configured number of passwords is kept")
+	w = b.change(t, "third secret", initialPassword)
+	assert.Equal(t, http.StatusOK, w.Code, "passwords older than the history may be chosen again")
+}
+
+func TestPasswordAPI_RequiresSession(t *testing.T) {
+	env := setupPasswordRouter(t, 0)
+
+	w := env.browser().change(t, initialPassword, newPassword)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+}
+
+func TestPasswordAPI_SignupPolicy(t *testing.T) {
+	env := setupPasswordRouter(t, 0)
+
+	w := env.browser().API("POST", "/api/v1/auth/signup", `{"email":"other@example.com","password":"qwerty123","username":"other"}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Equal(t, []authapi.FieldError{{Field: "password", In: "body", Message: "is too common"}}, details(t, w))
+}
@@ -0,0 +1,60 @@
+package password_api_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"example.com/internal/domain/entity"
+)
+
+// The memory repositories keep records in memory so that a password change can be followed
+// across logins
+
+type memoryHistory struct {
+	entries []*entity.PasswordHistory
+	mu      sync.Mutex
+}
+
+func (r *memoryHistory) Create(_ context.Context, entry *entity.PasswordHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *entry
+	stored.CreatedAt = time.Now()
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+func (r *memoryHistory) ListRecent(_ context.Context, userID string, limit int) ([]*entity.PasswordHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var entries []*entity.PasswordHistory
+	for _, entry := range slices.Backward(r.entries) {
+		if entry.UserID == userID && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *memoryHistory) Prune(_ context.Context, userID string, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := 0
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if r.entries[i].UserID != userID {
+			continue
+		}
+		if kept++; kept > keep {
+			r.entries = slices.Delete(r.entries, i, i+1)
+		}
+	}
+	return nil
+}
+
+func (r *memoryHistory) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
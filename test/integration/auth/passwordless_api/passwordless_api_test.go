@@ -0,0 +1,299 @@
+package passwordless_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+	authservice "example.com/internal/domain/service/auth"
+	passwordlessservice "example.com/internal/domain/service/passwordless"
+	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const linkURL = "http://app.example.com/login"
+
+var (
+	codePattern = regexp.MustCompile(`code is (\d{6})\n`)
+	linkPattern = regexp.MustCompile(`passwordless_token=(mlt_[A-Za-z0-9_-]+)`)
+)
+
+var existingUser = &entity.User{
+	ID:       "8f14e45f-ceea-467f-a0e5-4a3f4e2c3b1d",
+	Email:    "test@example.com",
+	UserName: "testuser",
+}
+
+type testEnv struct {
+	router *gin.Engine
+	outbox *mocks.Outbox
+}
+
+func setupPasswordlessRouter(t *testing.T, enabled bool) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	cfg := browsertest.Config()
+	cfg.Passwordless = config.PasswordlessConfig{
+		Enabled:     enabled,
+		LinkURL:     linkURL,
+		TTL:         15 * time.Minute,
+		MaxAttempts: 3,
+		MaxPerHour:  5,
+	}
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	users := mocks.NewMemoryUserRepository(existingUser)
+	sent := &mocks.Outbox{}
+	authSvc := authservice.NewService(users, &mocks.MockPasswordHasher{})
+	passwordlessSvc := passwordlessservice.NewService(
+		&memoryChallenges{challenges: map[string]*entity.LoginChallenge{}}, users, authSvc, sent,
+		passwordlessservice.Config{
+			LinkURL:     cfg.Passwordless.LinkURL,
+			TTL:         cfg.Passwordless.TTL,
+			MaxAttempts: cfg.Passwordless.MaxAttempts,
+			MaxPerHour:  cfg.Passwordless.MaxPerHour,
+		},
+	)
+
+	apiTokens := &mocks.MockAPITokenRepository{}
+	apiTokens.On("FindByUserID", mock.Anything, mock.Anything).Return([]*entity.APIToken{}, nil)
+	apiTokenSvc := apitokenservice.NewService(apiTokens)
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User: &api.UserAPIHandler{},
+		APITokens: api.NewAPITokenAPIHandler(
+			authusecase.NewCreateAPITokenUseCase(apiTokenSvc),
+			authusecase.NewListAPITokensUseCase(apiTokenSvc),
+			authusecase.NewRevokeAPITokenUseCase(apiTokenSvc),
+			testLogger,
+		),
+		Passwordless: api.NewPasswordlessAPIHandler(
+			authusecase.NewStartPasswordlessLoginUseCase(passwordlessSvc),
+			authusecase.NewPasswordlessLoginUseCase(passwordlessSvc),
+			nil,
+			testLogger,
+		),
+	})
+	require.NoError(t, err)
+
+	return &testEnv{router: router, outbox: sent}
+}
+
+// browser is one user agent of the frontend
+type browser struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) browser() *browser {
+	return &browser{Browser: browsertest.New(e.router)}
+}
+
+func (b *browser) start(t *testing.T, email, method string) {
+	w := b.API("POST", "/api/v1/auth/passwordless/start", `{"email":"`+email+`","method":"`+method+`"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+}
+
+func (b *browser) verify(t *testing.T, field, secret string) *httptest.ResponseRecorder {
+	return b.API("POST", "/api/v1/auth/passwordless/verify", `{"`+field+`":"`+secret+`"}`)
+}
+
+// loggedIn reports whether the session of b authenticates a route requiring a session
+func (b *browser) loggedIn(t *testing.T) bool {
+	return b.API("GET", "/api/v1/auth/tokens", "").Code == http.StatusOK
+}
+
+func sentCode(t *testing.T, env *testEnv) string {
+	match := codePattern.FindStringSubmatch(env.outbox.Last().Body)
+	require.Len(t, match, 2, env.outbox.Last().Body)
+	return match[1]
+}
+
+func sentLinkToken(t *testing.T, env *testEnv) string {
+	match := linkPattern.FindStringSubmatch(env.outbox.Last().Body)
+	require.Len(t, match, 2, env.outbox.Last().Body)
+	return match[1]
+}
+
+// wrongCode returns a code that differs from code
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestPasswordlessAPI_CodeLogin(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	b := env.browser()
+
+	b.start(t, existingUser.Email, "code")
+	require.Equal(t, 1, env.outbox.Len())
+	assert.Equal(t, existingUser.Email, env.outbox.Last().To)
+	code := sentCode(t, env)
+
+	w := b.verify(t, "code", wrongCode(code))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+	assert.False(t, b.loggedIn(t))
+
+	w = b.verify(t, "code", code)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response authapi.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, existingUser.ID, response.User.Id)
+	assert.Empty(t, response.AccessToken, "no tokens unless asked for")
+	assert.True(t, b.loggedIn(t))
+
+	w = b.verify(t, "code", code)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "codes are single use")
+}
+
+func TestPasswordlessAPI_LinkLogin(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	b := env.browser()
+
+	b.start(t, existingUser.Email, "link")
+	assert.Contains(t, env.outbox.Last().Body, linkURL+"?passwordless_token=")
+	token := sentLinkToken(t, env)
+
+	w := b.verify(t, "token", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, b.loggedIn(t))
+}
+
+func TestPasswordlessAPI_BoundToRequestingDevice(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	requester := env.browser()
+	requester.start(t, existingUser.Email, "link")
+	token := sentLinkToken(t, env)
+
+	// The link opened in another browser, or with another pending login, is refused
+	other := env.browser()
+	w := other.verify(t, "token", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	other.start(t, "nobody@example.com", "link")
+	w = other.verify(t, "token", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.False(t, other.loggedIn(t))
+
+	w = requester.verify(t, "token", token)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestPasswordlessAPI_AttemptLimit(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	b := env.browser()
+	b.start(t, existingUser.Email, "code")
+	code := sentCode(t, env)
+
+	for range 2 {
+		w := b.verify(t, "code", wrongCode(code))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+	}
+	w := b.verify(t, "code", wrongCode(code))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+
+	w = b.verify(t, "code", code)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "the login is void after too many attempts")
+	assert.False(t, b.loggedIn(t))
+}
+
+func TestPasswordlessAPI_NewLoginVoidsPending(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	first := env.browser()
+	first.start(t, existingUser.Email, "code")
+	code := sentCode(t, env)
+
+	env.browser().start(t, existingUser.Email, "code")
+
+	w := first.verify(t, "code", code)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestPasswordlessAPI_UnknownEmail(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	b := env.browser()
+
+	b.start(t, "nobody@example.com", "code")
+
+	assert.Zero(t, env.outbox.Len())
+	w := b.verify(t, "code", "123456")
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestPasswordlessAPI_HourlyLimit(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	b := env.browser()
+
+	for range 6 {
+		b.start(t, existingUser.Email, "code")
+	}
+
+	assert.Equal(t, 5, env.outbox.Len())
+}
+
+func TestPasswordlessAPI_InvalidRequests(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	b := env.browser()
+
+	w := b.API("POST", "/api/v1/auth/passwordless/start", `{"email":"test@example.com","method":"sms"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = b.verify(t, "code", "123456")
+	assert.Equal(t, http.StatusBadRequest, w.Code, "no pending login")
+
+	b.start(t, existingUser.Email, "code")
+	w = b.API("POST", "/api/v1/auth/passwordless/verify", `{"code":"123456","token":"mlt_x"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = b.API("POST", "/api/v1/auth/passwordless/verify", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestPasswordlessAPI_TokensDisabled(t *testing.T) {
+	env := setupPasswordlessRouter(t, true)
+	b := env.browser()
+	b.start(t, existingUser.Email, "code")
+
+	w := b.API("POST", "/api/v1/auth/passwordless/verify", `{"code":"`+sentCode(t, env)+`","issueTokens":true}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.False(t, b.loggedIn(t))
+}
+
+func TestPasswordlessAPI_Disabled(t *testing.T) {
+	env := setupPasswordlessRouter(t, false)
+
+	w := env.browser().API("POST", "/api/v1/auth/passwordless/start", `{"email":"test@example.com","method":"code"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
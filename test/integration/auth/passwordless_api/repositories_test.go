@@ -0,0 +1,83 @@
+package passwordless_api_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+)
+
+// The memory repositories keep records in memory so that a login can be followed end to end
+
+type memoryChallenges struct {
+	challenges map[string]*entity.LoginChallenge
+	mu         sync.Mutex
+}
+
+func (r *memoryChallenges) Create(_ context.Context, challenge *entity.LoginChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *challenge
+	stored.CreatedAt = time.Now()
+	r.challenges[challenge.ID] = &stored
+	return nil
+}
+
+func (r *memoryChallenges) FindByID(_ context.Context, id string) (*entity.LoginChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	challenge, ok := r.challenges[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *challenge
+	return &found, nil
+}
+
+func (r *memoryChallenges) CountSince(_ context.Context, userID string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, challenge := range r.challenges {
+		if challenge.UserID == userID && !challenge.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryChallenges) RecordFailedAttempt(_ context.Context, id string, maxAttempts int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	challenge, ok := r.challenges[id]
+	if !ok || challenge.UsedAt != nil || challenge.Attempts >= maxAttempts {
+		return gorm.ErrRecordNotFound
+	}
+	challenge.Attempts++
+	return nil
+}
+
+func (r *memoryChallenges) MarkUsed(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	challenge, ok := r.challenges[id]
+	if !ok || challenge.UsedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	challenge.UsedAt = &at
+	return nil
+}
+
+func (r *memoryChallenges) MarkPendingUsed(_ context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, challenge := range r.challenges {
+		if challenge.UserID == userID && challenge.UsedAt == nil {
+			challenge.UsedAt = &at
+		}
+	}
+	return nil
+}
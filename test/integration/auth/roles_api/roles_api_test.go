@@ -0,0 +1,234 @@
+package roles_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authapi "example.com/gen/openapi/auth/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	policyservice "example.com/internal/domain/service/policy"
+	userservice "example.com/internal/domain/service/v1"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/authz"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	adminID  = "0b6f3c1e-2d4a-4f8e-9a7b-1c2d3e4f5a6b"
+	memberID = "7e8f9a0b-1c2d-4e3f-8a5b-6c7d8e9f0a1b"
+)
+
+var adminRole = &entity.Role{
+	ID:   "1f2e3d4c-5b6a-4798-8a6b-5c4d3e2f1a0b",
+	Name: entity.RoleAdmin,
+	Permissions: []entity.Permission{
+		{Name: entity.PermissionUsersRead}, {Name: entity.PermissionRolesRead}, {Name: entity.PermissionRolesAssign},
+	},
+}
+
+var supportRole = &entity.Role{
+	ID:          "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d",
+	Name:        "support",
+	Description: "Looks up users and their roles",
+	Permissions: []entity.Permission{{Name: entity.PermissionUsersRead}, {Name: entity.PermissionRolesRead}},
+}
+
+type testEnv struct {
+	router        *gin.Engine
+	roles         *mocks.MockRoleRepository
+	organizations *mocks.MockOrganizationRepository
+}
+
+// setupRolesRouter serves an admin holding every permission and a member holding none
+func setupRolesRouter(t *testing.T) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	users := &mocks.MockUserRepository{}
+	for _, user := range []*entity.User{
+		{ID: adminID, Email: "admin@example.com", UserName: "admin", PasswordHash: "hash", CreatedAt: time.Now()},
+		{ID: memberID, Email: "member@example.com", UserName: "member", PasswordHash: "hash", CreatedAt: time.Now()},
+	} {
+		users.On("FindByUserNameOrEmail", mock.Anything, user.Email).Return(user, nil)
+		users.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+		users.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	}
+	users.On("FindByID", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	users.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", "password123", "hash").Return(true)
+
+	roles := &mocks.MockRoleRepository{}
+	roles.On("UserHasPermission", mock.Anything, adminID, mock.Anything).Return(true, nil)
+	roles.On("UserHasPermission", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	roles.On("FindByName", mock.Anything, "support").Return(supportRole, nil)
+	roles.On("FindByName", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	roles.On("FindByUserID", mock.Anything, adminID).Return([]*entity.Role{adminRole}, nil).Maybe()
+
+	authSvc := authservice.NewService(users, hasher)
+	organizations := &mocks.MockOrganizationRepository{}
+	policySvc := policyservice.NewService(roles, users, organizations)
+	testLogger := logger.New("test")
+	policies, err := config.AuthzConfig{}.Policies()
+	require.NoError(t, err)
+	authorizer := authz.NewAuthorizer(policies, authz.Config{
+		Attributes: policySvc.SubjectAttributes,
+		Resources:  policySvc.ResourceAttributes,
+	})
+
+	router, err := app.NewRouter(browsertest.Config(), app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User:      api.NewUserAPIHandler(userusecase.NewUserLookupUseCase(userservice.NewService(users), authorizer), nil, testLogger),
+		APITokens: &api.APITokenAPIHandler{},
+		Roles: api.NewRoleAPIHandler(
+			authusecase.NewListRolesUseCase(policySvc),
+			authusecase.NewListUserRolesUseCase(policySvc),
+			authusecase.NewAssignRoleUseCase(policySvc),
+			authusecase.NewUnassignRoleUseCase(policySvc),
+			testLogger,
+		),
+		CheckPermission: authusecase.NewCheckPermissionUseCase(policySvc),
+	})
+	require.NoError(t, err)
+
+	return &testEnv{router: router, roles: roles, organizations: organizations}
+}
+
+// session is the browser of one user, logged in unless anonymous
+type session struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) login(t *testing.T, email string) *session {
+	s := &session{Browser: browsertest.New(e.router)}
+	if email != "" {
+		s.Login(t, email, "password123")
+	}
+	return s
+}
+
+func TestRolesAPI_ListRoles(t *testing.T) {
+	env := setupRolesRouter(t)
+	env.roles.On("List", mock.Anything).Return([]*entity.Role{supportRole}, nil)
+
+	w := env.login(t, "admin@example.com").API("GET", "/api/v1/auth/roles", "")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response authapi.RoleList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []authapi.Role{{
+		Name:        "support",
+		Description: "Looks up users and their roles",
+		Permissions: []string{"roles:read", "users:read"},
+	}}, response.Roles)
+}
+
+func TestRolesAPI_RequirePermission(t *testing.T) {
+	env := setupRolesRouter(t)
+	env.roles.On("FindByUserID", mock.Anything, memberID).Return([]*entity.Role{}, nil)
+	env.organizations.On("ListByUserID", mock.Anything, mock.Anything).Return([]*entity.Membership{}, nil)
+	member := env.login(t, "member@example.com")
+
+	assert.Equal(t, http.StatusForbidden, member.API("GET", "/api/v1/auth/roles", "").Code)
+	assert.Equal(t, http.StatusForbidden, member.API("PUT", "/api/v1/auth/users/"+memberID+"/roles/support", "").Code)
+	assert.Equal(t, http.StatusNotFound, member.API("GET", "/api/v1/user/lookup?email=admin@example.com", "").Code,
+		"users the member may not read look unknown")
+	assert.Equal(t, http.StatusOK, member.API("GET", "/api/v1/user/lookup?email=member@example.com", "").Code)
+	assert.Equal(t, http.StatusOK, env.login(t, "admin@example.com").API("GET", "/api/v1/user/lookup?email=member@example.com", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, env.login(t, "").API("GET", "/api/v1/auth/roles", "").Code)
+	env.roles.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRolesAPI_OrganizationAdminsReadMembers(t *testing.T) {
+	env := setupRolesRouter(t)
+	env.roles.On("FindByUserID", mock.Anything, memberID).Return([]*entity.Role{}, nil)
+	env.organizations.On("ListByUserID", mock.Anything, memberID).Return([]*entity.Membership{
+		{OrganizationID: "org-1", UserID: memberID, Role: entity.MembershipAdmin},
+		{OrganizationID: "org-2", UserID: memberID, Role: entity.MembershipMember},
+	}, nil)
+	member := env.login(t, "member@example.com")
+
+	env.organizations.On("ListByUserID", mock.Anything, adminID).Return([]*entity.Membership{
+		{OrganizationID: "org-2", UserID: adminID, Role: entity.MembershipOwner},
+	}, nil).Once()
+	assert.Equal(t, http.StatusNotFound, member.API("GET", "/api/v1/user/lookup?email=admin@example.com", "").Code,
+		"members of an organization the member does not manage look unknown")
+
+	env.organizations.On("ListByUserID", mock.Anything, adminID).Return([]*entity.Membership{
+		{OrganizationID: "org-1", UserID: adminID, Role: entity.MembershipMember},
+	}, nil).Once()
+	assert.Equal(t, http.StatusOK, member.API("GET", "/api/v1/user/lookup?email=admin@example.com", "").Code,
+		"admins of an organization read its members")
+}
+
+func TestRolesAPI_AssignGrantsPermissions(t *testing.T) {
+	env := setupRolesRouter(t)
+	env.roles.On("Assign", mock.Anything, memberID, supportRole.ID).Return(nil)
+
+	w := env.login(t, "admin@example.com").API("PUT", "/api/v1/auth/users/"+memberID+"/roles/support", "")
+
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	env.roles.AssertExpectations(t)
+}
+
+func TestRolesAPI_ListUserRoles(t *testing.T) {
+	env := setupRolesRouter(t)
+	env.roles.On("FindByUserID", mock.Anything, memberID).Return([]*entity.Role{supportRole}, nil)
+	admin := env.login(t, "admin@example.com")
+
+	w := admin.API("GET", "/api/v1/auth/users/"+memberID+"/roles", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response authapi.RoleList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Roles, 1)
+	assert.Equal(t, "support", response.Roles[0].Name)
+
+	w = admin.API("GET", "/api/v1/auth/users/9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a/roles", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestRolesAPI_NotFound(t *testing.T) {
+	env := setupRolesRouter(t)
+	env.roles.On("Unassign", mock.Anything, memberID, supportRole.ID).Return(gorm.ErrRecordNotFound)
+	admin := env.login(t, "admin@example.com")
+
+	w := admin.API("PUT", "/api/v1/auth/users/"+memberID+"/roles/superuser", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	w = admin.API("PUT", "/api/v1/auth/users/9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a/roles/support", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	w = admin.API("DELETE", "/api/v1/auth/users/"+memberID+"/roles/support", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, "the member does not have the role")
+
+	w = admin.API("DELETE", "/api/v1/auth/users/9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a/roles/support", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
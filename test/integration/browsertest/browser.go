@@ -0,0 +1,78 @@
+// Package browsertest drives a router the way the frontend does from a browser
+package browsertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+)
+
+// Browser keeps the cookies of one user agent across requests and sends the XSRF token of its
+// session with API requests
+type Browser struct {
+	// Header is sent with every request, like the tenant header
+	Header  http.Header
+	handler http.Handler
+	cookies map[string]*http.Cookie
+}
+
+func New(handler http.Handler) *Browser {
+	return &Browser{Header: http.Header{}, handler: handler, cookies: map[string]*http.Cookie{}}
+}
+
+// Do sends req with the cookies of the browser and keeps the cookies set by the response
+func (b *Browser) Do(req *http.Request) *httptest.ResponseRecorder {
+	for name, values := range b.Header {
+		req.Header[name] = values
+	}
+	for _, cookie := range b.cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	b.handler.ServeHTTP(w, req)
+	for _, cookie := range w.Result().Cookies() {
+		b.cookies[cookie.Name] = cookie
+	}
+	return w
+}
+
+func (b *Browser) Get(path string) *httptest.ResponseRecorder {
+	return b.Do(httptest.NewRequest("GET", path, nil))
+}
+
+// Send sends body of contentType, or no body when it is empty, with an XSRF token fetched for
+// the session first. When no token can be fetched, the failed response of /csrf-token is
+// returned instead.
+func (b *Browser) Send(method, path, contentType, body string) *httptest.ResponseRecorder {
+	w := b.Get("/csrf-token")
+	var token authapi.CsrfToken
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &token) != nil {
+		return w
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("X-XSRF-TOKEN", token.Token)
+	return b.Do(req)
+}
+
+// API sends a JSON body like Send
+func (b *Browser) API(method, path, body string) *httptest.ResponseRecorder {
+	return b.Send(method, path, "application/json", body)
+}
+
+// Login logs in with email and password, failing the test unless it succeeds
+func (b *Browser) Login(t *testing.T, email, password string) {
+	body, err := json.Marshal(authapi.LoginRequest{Email: email, Password: password})
+	require.NoError(t, err)
+	w := b.API("POST", "/api/v1/auth/login", string(body))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
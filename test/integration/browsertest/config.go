@@ -0,0 +1,17 @@
+package browsertest
+
+import (
+	"example.com/internal/infrastructure/config"
+)
+
+// Config returns the configuration of a router under test, with fixed secrets for the session
+// cookies and XSRF tokens its browsers carry. Tests set the sections they exercise on top.
+func Config() *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{Env: "test"},
+		Security: config.SecurityConfig{
+			CSRFSecret:    "test-csrf-secret",
+			SessionSecret: "test-session-secret",
+		},
+	}
+}
@@ -0,0 +1,239 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	apitokenservice "example.com/internal/domain/service/apitoken"
+	authservice "example.com/internal/domain/service/auth"
+	userservice "example.com/internal/domain/service/v1"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/client"
+	"example.com/pkg/security"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+// testAPIToken is accepted by the server of setupServer with the users:read scope
+const testAPIToken = entity.APITokenPrefix + "client-test-token"
+
+// countingTransport counts the requests sent to each path
+type countingTransport struct {
+	counts map[string]*atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if counter, ok := t.counts[req.URL.Path]; ok {
+		counter.Add(1)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func setupServer(t *testing.T) (*httptest.Server, *mocks.MockUserRepository, *mocks.MockPasswordHasher) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := browsertest.Config()
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	mockRepo := &mocks.MockUserRepository{}
+	mockHasher := &mocks.MockPasswordHasher{}
+	testLogger := logger.New("test")
+	authSvc := authservice.NewService(mockRepo, mockHasher)
+	userSvc := userservice.NewService(mockRepo)
+
+	tokenRepo := &mocks.MockAPITokenRepository{}
+	tokenRepo.On("FindByHash", mock.Anything, security.HashToken(testAPIToken)).Return(&entity.APIToken{
+		ID:     "token-1",
+		UserID: "user-123",
+		Scopes: []string{entity.ScopeUsersRead},
+	}, nil)
+	tokenRepo.On("UpdateLastUsed", mock.Anything, "token-1", mock.Anything).Return(nil)
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User:              api.NewUserAPIHandler(userusecase.NewUserLookupUseCase(userSvc, nil), nil, testLogger),
+		AuthenticateToken: authusecase.NewAuthenticateAPITokenUseCase(apitokenservice.NewService(tokenRepo)),
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server, mockRepo, mockHasher
+}
+
+func newCountingClient(t *testing.T, baseURL string, paths ...string) (*client.Client, *http.Client, map[string]*atomic.Int32) {
+	counts := make(map[string]*atomic.Int32, len(paths))
+	for _, path := range paths {
+		counts[path] = &atomic.Int32{}
+	}
+
+	httpClient := &http.Client{Transport: &countingTransport{counts: counts}}
+	c, err := client.New(baseURL, client.WithHTTPClient(httpClient), client.WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+
+	return c, httpClient, counts
+}
+
+func TestClient_SignupLoginLookup(t *testing.T) {
+	server, mockRepo, mockHasher := setupServer(t)
+	c, _, counts := newCountingClient(t, server.URL, "/csrf-token")
+	ctx := context.Background()
+
+	user := &entity.User{
+		ID:           "3f2b8a4e-8c7d-4c1a-9f0e-2b1d5a6c7e8f",
+		Email:        "test@example.com",
+		UserName:     "testuser",
+		PasswordHash: "hashed_password",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	mockRepo.On("FindByUserName", mock.Anything, "testuser").Return(nil, assert.AnError)
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(nil, assert.AnError).Once()
+	mockHasher.On("Hash", "password123").Return("hashed_password", nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	signup, err := c.Signup(ctx, "test@example.com", "password123", "testuser")
+	require.NoError(t, err)
+	assert.Equal(t, "test@example.com", string(signup.User.Email))
+
+	mockRepo.On("FindByUserNameOrEmail", mock.Anything, "test@example.com").Return(user, nil)
+	mockHasher.On("Verify", "password123", "hashed_password").Return(true)
+	mockRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	login, err := c.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, "Login successful", login.Message)
+
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+
+	lookup, err := c.LookupUser(ctx, "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "testuser", lookup.Username)
+
+	assert.Equal(t, int32(1), counts["/csrf-token"].Load())
+	mockRepo.AssertExpectations(t)
+	mockHasher.AssertExpectations(t)
+}
+
+func TestClient_RefreshesRejectedXSRFToken(t *testing.T) {
+	server, mockRepo, _ := setupServer(t)
+	c, httpClient, counts := newCountingClient(t, server.URL, "/csrf-token", "/api/v1/auth/login")
+	ctx := context.Background()
+
+	mockRepo.On("FindByUserNameOrEmail", mock.Anything, "test@example.com").Return(nil, assert.AnError)
+
+	_, err := c.Login(ctx, "test@example.com", "password123")
+	assert.True(t, client.IsStatus(err, http.StatusUnauthorized))
+
+	// Losing the session cookie invalidates the cached token
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	httpClient.Jar = jar
+
+	_, err = c.Login(ctx, "test@example.com", "password123")
+	assert.True(t, client.IsStatus(err, http.StatusUnauthorized))
+
+	assert.Equal(t, int32(2), counts["/csrf-token"].Load())
+	assert.Equal(t, int32(3), counts["/api/v1/auth/login"].Load())
+}
+
+func TestClient_ValidationErrorDetails(t *testing.T) {
+	server, mockRepo, _ := setupServer(t)
+	c, _, _ := newCountingClient(t, server.URL)
+
+	_, err := c.Signup(context.Background(), "test@example.com", "short", "")
+
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Len(t, apiErr.Details, 1)
+	assert.Equal(t, "password", apiErr.Details[0].Field)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestClient_RetriesIdempotentRequestsOnly(t *testing.T) {
+	var lookups, signups atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/csrf-token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"token"}`))
+	})
+	mux.HandleFunc("/api/v1/user/lookup", func(w http.ResponseWriter, r *http.Request) {
+		if lookups.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"username":"testuser"}`))
+	})
+	mux.HandleFunc("/api/v1/auth/signup", func(w http.ResponseWriter, r *http.Request) {
+		signups.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, _, _ := newCountingClient(t, server.URL)
+
+	lookup, err := c.LookupUser(context.Background(), "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "testuser", lookup.Username)
+	assert.Equal(t, int32(3), lookups.Load())
+
+	_, err = c.Signup(context.Background(), "test@example.com", "password123", "")
+	assert.True(t, client.IsStatus(err, http.StatusServiceUnavailable))
+	assert.Equal(t, int32(1), signups.Load())
+}
+
+func TestClient_APITokenSkipsXSRF(t *testing.T) {
+	server, mockRepo, _ := setupServer(t)
+	counts := map[string]*atomic.Int32{"/csrf-token": {}}
+	httpClient := &http.Client{Transport: &countingTransport{counts: counts}}
+	c, err := client.New(server.URL, client.WithHTTPClient(httpClient), client.WithAPIToken(testAPIToken))
+	require.NoError(t, err)
+
+	mockRepo.On("FindByEmail", mock.Anything, "other@example.com").Return(&entity.User{
+		ID:       "user-456",
+		Email:    "other@example.com",
+		UserName: "other",
+	}, nil)
+
+	user, err := c.LookupUser(context.Background(), "other@example.com")
+
+	require.NoError(t, err)
+	assert.Equal(t, "other", user.Username)
+	assert.Zero(t, counts["/csrf-token"].Load())
+}
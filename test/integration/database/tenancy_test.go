@@ -0,0 +1,220 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/infrastructure/database"
+	"example.com/pkg/tenant"
+)
+
+// openDatabase migrates the database of DATABASE_URL and enables tenancy on it, skipping the
+// test when no database is configured
+func openDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+	require.NoError(t, database.EnableTenancy(db))
+	return db
+}
+
+// newUser returns a user whose name and email are unique to the test run
+func newUser() *entity.User {
+	id := uuid.NewString()
+	return &entity.User{
+		ID:           id,
+		UserName:     "user-" + id[:8],
+		Email:        id[:8] + "@example.com",
+		PasswordHash: "hash",
+	}
+}
+
+func TestTenancy_IsolatesRepositories(t *testing.T) {
+	db := openDatabase(t)
+	users := database.NewUserRepository(db)
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	john := newUser()
+	require.NoError(t, users.Create(acme, john))
+
+	// The same name and email may sign up in another tenant
+	other := newUser()
+	other.UserName, other.Email = john.UserName, john.Email
+	require.NoError(t, users.Create(globex, other))
+
+	found, err := users.FindByEmail(acme, john.Email)
+	require.NoError(t, err)
+	assert.Equal(t, john.ID, found.ID)
+	assert.Equal(t, "acme", found.TenantID)
+
+	found, err = users.FindByUserNameOrEmail(globex, john.Email)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, found.ID)
+
+	_, err = users.FindByID(globex, john.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// Writes addressed to a row of another tenant leave it alone
+	hijacked := *john
+	hijacked.PasswordHash = "hijacked"
+	require.NoError(t, users.Update(globex, &hijacked))
+	require.NoError(t, users.Delete(globex, john.ID))
+
+	found, err = users.FindByID(acme, john.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hash", found.PasswordHash)
+
+	_, err = users.FindByID(context.Background(), john.ID)
+	require.ErrorIs(t, err, database.ErrTenantRequired)
+}
+
+// newUserWithRows creates a user in tenant together with a profile, a login challenge, a
+// password history entry and the support role
+func newUserWithRows(t *testing.T, db *gorm.DB, tenantID string) *entity.User {
+	t.Helper()
+	ctx := tenant.NewContext(context.Background(), tenantID)
+
+	user := newUser()
+	require.NoError(t, database.NewUserRepository(db).Create(ctx, user))
+	require.NoError(t, database.NewUserProfileRepository(db).Save(ctx, &entity.UserProfile{UserID: user.ID, Bio: "Hello"}))
+	require.NoError(t, database.NewLoginChallengeRepository(db).Create(ctx, &entity.LoginChallenge{
+		ID:         uuid.NewString(),
+		UserID:     user.ID,
+		Method:     entity.LoginMethodCode,
+		SecretHash: "secret",
+		DeviceHash: "device",
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}))
+	require.NoError(t, database.NewPasswordHistoryRepository(db).Create(ctx, &entity.PasswordHistory{
+		ID:           uuid.NewString(),
+		UserID:       user.ID,
+		PasswordHash: "old",
+	}))
+
+	roles := database.NewRoleRepository(db)
+	seeded, err := database.SeedRoles(ctx, roles)
+	require.NoError(t, err)
+	require.NoError(t, roles.Assign(ctx, user.ID, seeded["support"].ID))
+	return user
+}
+
+func TestTenancy_IsolatesRowsOfUsers(t *testing.T) {
+	db := openDatabase(t)
+	profiles := database.NewUserProfileRepository(db)
+	challenges := database.NewLoginChallengeRepository(db)
+	histories := database.NewPasswordHistoryRepository(db)
+	roles := database.NewRoleRepository(db)
+	john := newUserWithRows(t, db, "acme")
+	support, err := roles.FindByName(context.Background(), "support")
+	require.NoError(t, err)
+
+	// found reports how many rows of john each repository finds when acting for ctx
+	found := func(ctx context.Context) (profileFound bool, challengeCount int64, historyCount, roleCount int) {
+		t.Helper()
+		_, err := profiles.FindByUserID(ctx, john.ID)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			require.NoError(t, err)
+			profileFound = true
+		}
+		challengeCount, err = challenges.CountSince(ctx, john.ID, time.Time{})
+		require.NoError(t, err)
+		entries, err := histories.ListRecent(ctx, john.ID, 10)
+		require.NoError(t, err)
+		assigned, err := roles.FindByUserID(ctx, john.ID)
+		require.NoError(t, err)
+		return profileFound, challengeCount, len(entries), len(assigned)
+	}
+
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	profileFound, challengeCount, historyCount, roleCount := found(acme)
+	assert.True(t, profileFound)
+	assert.Equal(t, int64(1), challengeCount)
+	assert.Equal(t, 1, historyCount)
+	assert.Equal(t, 1, roleCount)
+
+	profileFound, challengeCount, historyCount, roleCount = found(globex)
+	assert.False(t, profileFound)
+	assert.Zero(t, challengeCount)
+	assert.Zero(t, historyCount)
+	assert.Zero(t, roleCount)
+
+	// Roles cannot be taken from users of another tenant
+	require.ErrorIs(t, roles.Unassign(globex, john.ID, support.ID), gorm.ErrRecordNotFound)
+	_, _, _, roleCount = found(acme)
+	assert.Equal(t, 1, roleCount)
+}
+
+func TestTenancy_RowLevelSecurity(t *testing.T) {
+	db := openDatabase(t)
+
+	var canCreateRoles bool
+	require.NoError(t, db.Raw("SELECT rolsuper OR rolcreaterole FROM pg_roles WHERE rolname = current_user").Scan(&canCreateRoles).Error)
+	if !canCreateRoles {
+		t.Skip("the database user cannot create roles")
+	}
+
+	// Superusers bypass row-level security, so the queries run as a role without that privilege
+	require.NoError(t, db.Exec(`DO $$ BEGIN
+		IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'tenancy_test_reader') THEN
+			CREATE ROLE tenancy_test_reader NOLOGIN;
+		END IF;
+	END $$`).Error)
+	for _, table := range rowLevelSecurityTables {
+		require.NoError(t, db.Exec("GRANT SELECT ON "+table+" TO tenancy_test_reader").Error)
+	}
+
+	john, jane := newUserWithRows(t, db, "acme"), newUserWithRows(t, db, "globex")
+
+	// visible returns the IDs of john and jane whose rows in table raw SQL sees when acting for id
+	visible := func(table, id string) []string {
+		column := "user_id"
+		if table == "users" {
+			column = "id"
+		}
+		var ids []string
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SET LOCAL ROLE tenancy_test_reader").Error; err != nil {
+				return err
+			}
+			if id != "" {
+				if err := tx.Exec("SELECT set_config('app.tenant_id', ?, true)", id).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Raw("SELECT "+column+" FROM "+table+" WHERE "+column+" IN ?", []string{john.ID, jane.ID}).Scan(&ids).Error
+		})
+		require.NoError(t, err)
+		return ids
+	}
+
+	for _, table := range rowLevelSecurityTables {
+		assert.Equal(t, []string{john.ID}, visible(table, "acme"), table)
+		assert.Equal(t, []string{jane.ID}, visible(table, "globex"), table)
+		assert.Empty(t, visible(table, ""), table)
+	}
+}
+
+// rowLevelSecurityTables are tenant-scoped tables holding one row of each user of
+// newUserWithRows
+var rowLevelSecurityTables = []string{"users", "user_profiles", "login_challenges", "password_histories", "user_roles"}
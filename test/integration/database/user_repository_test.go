@@ -0,0 +1,175 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/internal/infrastructure/database"
+	"example.com/pkg/security"
+	"example.com/pkg/tenant"
+)
+
+func TestUserRepository_List(t *testing.T) {
+	db := openDatabase(t)
+	users := database.NewUserRepository(db)
+	// A tenant of its own leaves the users of other runs out
+	ctx := tenant.NewContext(context.Background(), "list-"+uuid.NewString()[:8])
+
+	now := time.Now()
+	ann, bob, cid := newUser(), newUser(), newUser()
+	ann.UserName, ann.Email, ann.EmailVerifiedAt = "ann", "ann@example.com", &now
+	bob.UserName, bob.Email, bob.LockedAt = "bob", "bob_100%@example.com", &now
+	cid.UserName, cid.Email = "cid", "cid@example.org"
+	for _, user := range []*entity.User{ann, bob, cid} {
+		require.NoError(t, users.Create(ctx, user))
+	}
+	require.NoError(t, users.Delete(ctx, cid.ID))
+
+	names := func(query repository.UserQuery) []string {
+		t.Helper()
+		query.Sort, query.Limit = repository.UserSortUserName, 10
+		listed, err := users.List(ctx, query)
+		require.NoError(t, err)
+		var names []string
+		for _, user := range listed {
+			names = append(names, user.UserName)
+		}
+		return names
+	}
+	yes, no := true, false
+
+	assert.Equal(t, []string{"ann", "bob"}, names(repository.UserQuery{}), "deleted users are left out")
+	assert.Equal(t, []string{"cid"}, names(repository.UserQuery{Deleted: &yes}))
+	assert.Equal(t, []string{"bob"}, names(repository.UserQuery{Search: "100%"}), "wildcards match literally")
+	assert.Equal(t, []string{"ann"}, names(repository.UserQuery{Search: "ANN example.com"}))
+	assert.Equal(t, []string{"ann"}, names(repository.UserQuery{Verified: &yes}))
+	assert.Equal(t, []string{"ann"}, names(repository.UserQuery{Locked: &no}))
+	assert.Equal(t, []string{"bob"}, names(repository.UserQuery{After: &entity.User{ID: ann.ID, UserName: "ann"}}))
+
+	listed, err := users.List(ctx, repository.UserQuery{Sort: repository.UserSortUserName, Descending: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "bob", listed[0].UserName)
+}
+
+func TestUserRepository_DeleteRestorePurge(t *testing.T) {
+	db := openDatabase(t)
+	users := database.NewUserRepository(db)
+	ctx := tenant.NewContext(context.Background(), "purge-"+uuid.NewString()[:8])
+
+	jane := newUser()
+	require.NoError(t, users.Create(ctx, jane))
+	require.NoError(t, users.Delete(ctx, jane.ID))
+
+	// The user name and email of deleted users are free again
+	again := newUser()
+	again.UserName, again.Email = jane.UserName, jane.Email
+	require.NoError(t, users.Create(ctx, again))
+	require.NoError(t, users.Delete(ctx, again.ID))
+
+	require.NoError(t, users.Restore(ctx, jane.ID))
+	assert.ErrorIs(t, users.Restore(ctx, again.ID), gorm.ErrDuplicatedKey, "jane holds the user name and email again")
+	restored, err := users.FindByID(ctx, jane.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jane.Email, restored.Email)
+	_, err = users.FindDeleted(ctx, jane.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	purgeable, err := users.ListPurgeable(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, purgeable, 1)
+	assert.Equal(t, again.ID, purgeable[0].ID)
+
+	require.NoError(t, users.Purge(ctx, again.ID, time.Now()))
+	purged, err := users.FindDeleted(ctx, again.ID)
+	require.NoError(t, err)
+	assert.True(t, purged.Purged())
+	assert.Equal(t, repository.PurgedEmail, purged.Email)
+	assert.Empty(t, purged.PasswordHash)
+	assert.ErrorIs(t, users.Restore(ctx, again.ID), gorm.ErrRecordNotFound, "purged users stay deleted")
+	assert.ErrorIs(t, users.Purge(ctx, again.ID, time.Now()), gorm.ErrRecordNotFound, "purged once")
+}
+
+func TestUserRepository_ChangeEmail(t *testing.T) {
+	db := openDatabase(t)
+	users := database.NewUserRepository(db)
+	ctx := tenant.NewContext(context.Background(), "email-"+uuid.NewString()[:8])
+
+	jane, john := newUser(), newUser()
+	require.NoError(t, users.Create(ctx, jane))
+	require.NoError(t, users.Create(ctx, john))
+	newEmail := "new-" + jane.Email
+
+	now := time.Now()
+	require.NoError(t, users.ChangeEmail(ctx, jane.ID, jane.Email, newEmail, now))
+	changed, err := users.FindByEmail(ctx, newEmail)
+	require.NoError(t, err)
+	assert.Equal(t, jane.ID, changed.ID)
+	assert.NotNil(t, changed.EmailVerifiedAt)
+
+	assert.ErrorIs(t, users.ChangeEmail(ctx, jane.ID, jane.Email, "other-"+jane.Email, now), gorm.ErrRecordNotFound,
+		"the user no longer has the old email")
+	assert.ErrorIs(t, users.ChangeEmail(ctx, john.ID, john.Email, newEmail, now), gorm.ErrDuplicatedKey,
+		"the unique index settles races")
+}
+
+func TestUserRepository_UserNameSkeleton(t *testing.T) {
+	db := openDatabase(t)
+	users := database.NewUserRepository(db)
+	ctx := tenant.NewContext(context.Background(), "skeleton-"+uuid.NewString()[:8])
+
+	// The skeleton computed in SQL must agree with the one of the username policy
+	for _, name := range []string{"modern.1ily-0", "vvill_iam"} {
+		user := newUser()
+		user.UserName = name
+		require.NoError(t, users.Create(ctx, user))
+
+		found, err := users.FindByUserNameSkeleton(ctx, security.UsernameSkeleton(name))
+		require.NoError(t, err, name)
+		assert.Equal(t, user.ID, found.ID)
+	}
+
+	_, err := users.FindByUserNameSkeleton(ctx, security.UsernameSkeleton("modern"))
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestUserRepository_ChangeUserName(t *testing.T) {
+	db := openDatabase(t)
+	users := database.NewUserRepository(db)
+	changes := database.NewUserNameChangeRepository(db)
+	ctx := tenant.NewContext(context.Background(), "rename-"+uuid.NewString()[:8])
+
+	jane, john := newUser(), newUser()
+	require.NoError(t, users.Create(ctx, jane))
+	require.NoError(t, users.Create(ctx, john))
+
+	require.NoError(t, users.ChangeUserName(ctx, jane.ID, jane.UserName, "jane.doe"))
+	now := time.Now()
+	require.NoError(t, changes.Create(ctx, &entity.UserNameChange{
+		ID:                uuid.NewString(),
+		UserID:            jane.ID,
+		OldUserName:       jane.UserName,
+		OldSkeleton:       security.UsernameSkeleton(jane.UserName),
+		NewUserName:       "jane.doe",
+		RedirectExpiresAt: now.Add(time.Hour),
+	}))
+
+	redirect, err := changes.FindRedirect(ctx, security.UsernameSkeleton(jane.UserName), now)
+	require.NoError(t, err)
+	assert.Equal(t, jane.ID, redirect.UserID)
+	_, err = changes.FindRedirect(ctx, security.UsernameSkeleton(jane.UserName), now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "redirects expire")
+
+	assert.ErrorIs(t, users.ChangeUserName(ctx, jane.ID, jane.UserName, "jane.smith"), gorm.ErrRecordNotFound,
+		"the user no longer has the old name")
+	assert.ErrorIs(t, users.ChangeUserName(ctx, john.ID, john.UserName, "jane.doe"), gorm.ErrDuplicatedKey,
+		"the unique index settles races")
+}
@@ -0,0 +1,111 @@
+package docs_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"example.com/internal/interfaces/api"
+)
+
+type specDocument struct {
+	Servers []struct {
+		URL string `json:"url" yaml:"url"`
+	} `json:"servers" yaml:"servers"`
+	Paths map[string]any `json:"paths" yaml:"paths"`
+}
+
+func setupDocsRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	openAPIHandler, err := api.NewOpenAPIHandler("https://api.example.com/")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/openapi/:file", openAPIHandler.Spec)
+	router.GET("/docs/*any", openAPIHandler.Docs)
+
+	return router
+}
+
+func TestDocsAPI_SpecYAML(t *testing.T) {
+	router := setupDocsRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/openapi/auth.yaml", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/yaml")
+
+	var doc specDocument
+	err := yaml.Unmarshal(w.Body.Bytes(), &doc)
+	assert.NoError(t, err)
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "https://api.example.com", doc.Servers[0].URL)
+	assert.Contains(t, doc.Paths, "/auth/user/signup")
+}
+
+func TestDocsAPI_SpecJSON_KeepsServerBasePath(t *testing.T) {
+	router := setupDocsRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/openapi/v1.json", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var doc specDocument
+	err := json.Unmarshal(w.Body.Bytes(), &doc)
+	assert.NoError(t, err)
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "https://api.example.com/api/v1", doc.Servers[0].URL)
+	assert.Contains(t, doc.Paths, "/user/lookup")
+}
+
+func TestDocsAPI_UnknownSpec(t *testing.T) {
+	router := setupDocsRouter(t)
+
+	for _, path := range []string{"/openapi/missing.yaml", "/openapi/auth.txt"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestDocsAPI_IndexListsEverySpec(t *testing.T) {
+	router := setupDocsRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/docs/index.html", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `/openapi/auth.yaml`)
+	assert.Contains(t, w.Body.String(), `/openapi/v1.yaml`)
+}
+
+func TestDocsAPI_Assets(t *testing.T) {
+	router := setupDocsRouter(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/docs/swagger-ui.css", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Body.Bytes())
+}
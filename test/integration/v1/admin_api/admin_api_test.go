@@ -0,0 +1,257 @@
+package admin_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	adminservice "example.com/internal/domain/service/admin"
+	authservice "example.com/internal/domain/service/auth"
+	policyservice "example.com/internal/domain/service/policy"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	adminID  = "0b6f3c1e-2d4a-4f8e-9a7b-1c2d3e4f5a6b"
+	memberID = "7e8f9a0b-1c2d-4e3f-8a5b-6c7d8e9f0a1b"
+)
+
+type testEnv struct {
+	router *gin.Engine
+	users  *mocks.MockUserRepository
+}
+
+// setupAdminRouter serves an admin holding every permission and a member holding none
+func setupAdminRouter(t *testing.T) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	users := &mocks.MockUserRepository{}
+	for _, user := range []*entity.User{
+		{ID: adminID, Email: "admin@example.com", UserName: "admin", PasswordHash: "hash", CreatedAt: time.Now()},
+		{ID: memberID, Email: "member@example.com", UserName: "member", PasswordHash: "hash", CreatedAt: time.Now()},
+	} {
+		users.On("FindByUserNameOrEmail", mock.Anything, user.Email).Return(user, nil)
+		users.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	}
+	users.On("FindByID", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	users.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", "password123", "hash").Return(true)
+
+	roles := &mocks.MockRoleRepository{}
+	roles.On("UserHasPermission", mock.Anything, adminID, mock.Anything).Return(true, nil)
+	roles.On("UserHasPermission", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	refreshTokens := &mocks.MockRefreshTokenRepository{}
+	refreshTokens.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	apiTokens := &mocks.MockAPITokenRepository{}
+	apiTokens.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	oauthCodes := &mocks.MockOAuthAuthorizationCodeRepository{}
+	oauthCodes.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	oauthTokens := &mocks.MockOAuthAccessTokenRepository{}
+	oauthTokens.On("RevokeUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	authSvc := authservice.NewService(users, hasher)
+	adminSvc := adminservice.NewService(users, refreshTokens, apiTokens, oauthCodes, oauthTokens)
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(browsertest.Config(), app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User:      &api.UserAPIHandler{},
+		APITokens: &api.APITokenAPIHandler{},
+		Admin: api.NewAdminAPIHandler(
+			userusecase.NewListUsersUseCase(adminSvc),
+			userusecase.NewLockUserUseCase(adminSvc),
+			userusecase.NewUnlockUserUseCase(adminSvc),
+			userusecase.NewRequirePasswordResetUseCase(adminSvc),
+			userusecase.NewDeleteUserUseCase(adminSvc),
+			userusecase.NewRestoreUserUseCase(adminSvc),
+			testLogger,
+		),
+		CheckPermission: authusecase.NewCheckPermissionUseCase(policyservice.NewService(roles, users, &mocks.MockOrganizationRepository{})),
+		ValidateSession: authusecase.NewValidateSessionUseCase(authSvc),
+	})
+	require.NoError(t, err)
+
+	return &testEnv{router: router, users: users}
+}
+
+// session is the browser of one user, logged in unless anonymous
+type session struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) session() *session {
+	return &session{Browser: browsertest.New(e.router)}
+}
+
+func (e *testEnv) login(t *testing.T, email string) *session {
+	s := e.session()
+	s.Login(t, email, "password123")
+	return s
+}
+
+func TestAdminAPI_ListUsers(t *testing.T) {
+	env := setupAdminRouter(t)
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	yes := true
+	env.users.On("List", mock.Anything, repository.UserQuery{
+		Search:       "exa mple",
+		CreatedAfter: &after,
+		Verified:     &yes,
+		Deleted:      &yes,
+		Sort:         repository.UserSortEmail,
+		Descending:   true,
+		Limit:        2,
+	}).Return([]*entity.User{
+		{ID: memberID, Email: "member@example.com", UserName: "member", DeletedAt: gorm.DeletedAt{Time: after, Valid: true}},
+		{ID: adminID, Email: "admin@example.com", UserName: "admin"},
+	}, nil)
+
+	w := env.login(t, "admin@example.com").
+		API("GET", "/api/v1/admin/users?q=exa+mple&createdAfter=2026-01-01T00:00:00Z&verified=true&deleted=true&sort=-email&limit=1", "")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response v1api.AdminUserList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Users, 1)
+	assert.Equal(t, memberID, response.Users[0].Id)
+	assert.Equal(t, &after, response.Users[0].DeletedAt)
+	assert.NotEmpty(t, response.NextCursor)
+}
+
+func TestAdminAPI_ListUsers_InvalidQuery(t *testing.T) {
+	env := setupAdminRouter(t)
+	admin := env.login(t, "admin@example.com")
+
+	assert.Equal(t, http.StatusBadRequest, admin.API("GET", "/api/v1/admin/users?sort=password", "").Code)
+	assert.Equal(t, http.StatusBadRequest, admin.API("GET", "/api/v1/admin/users?limit=1000", "").Code)
+	assert.Equal(t, http.StatusBadRequest, admin.API("GET", "/api/v1/admin/users?cursor=bogus", "").Code)
+	env.users.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestAdminAPI_RequirePermission(t *testing.T) {
+	env := setupAdminRouter(t)
+	member := env.login(t, "member@example.com")
+
+	assert.Equal(t, http.StatusForbidden, member.API("GET", "/api/v1/admin/users", "").Code)
+	assert.Equal(t, http.StatusForbidden, member.API("PUT", "/api/v1/admin/users/"+adminID+"/lock", "").Code)
+	assert.Equal(t, http.StatusForbidden, member.API("DELETE", "/api/v1/admin/users/"+adminID, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, env.session().API("GET", "/api/v1/admin/users", "").Code)
+	env.users.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestAdminAPI_LockUser(t *testing.T) {
+	env := setupAdminRouter(t)
+	admin := env.login(t, "admin@example.com")
+	member := env.login(t, "member@example.com")
+
+	w := admin.API("PUT", "/api/v1/admin/users/"+memberID+"/lock", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var locked v1api.AdminUser
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &locked))
+	assert.NotNil(t, locked.LockedAt)
+
+	assert.Equal(t, http.StatusUnauthorized, member.API("GET", "/api/v1/admin/users", "").Code, "session ended")
+	w = env.session().API("POST", "/api/v1/auth/login", `{"email":"member@example.com","password":"password123"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Account locked")
+
+	w = admin.API("DELETE", "/api/v1/admin/users/"+memberID+"/lock", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	env.login(t, "member@example.com")
+
+	assert.Equal(t, http.StatusConflict, admin.API("PUT", "/api/v1/admin/users/"+adminID+"/lock", "").Code, "own account")
+	assert.Equal(t, http.StatusNotFound, admin.API("PUT", "/api/v1/admin/users/5d6e7f80-1a2b-4c3d-8e9f-0a1b2c3d4e5f/lock", "").Code)
+}
+
+func TestAdminAPI_RequirePasswordReset(t *testing.T) {
+	env := setupAdminRouter(t)
+
+	w := env.login(t, "admin@example.com").API("POST", "/api/v1/admin/users/"+memberID+"/password-reset", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = env.session().API("POST", "/api/v1/auth/login", `{"email":"member@example.com","password":"password123"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Password reset required")
+}
+
+func TestAdminAPI_DeleteUser(t *testing.T) {
+	env := setupAdminRouter(t)
+	env.users.On("Delete", mock.Anything, memberID).Return(nil)
+	admin := env.login(t, "admin@example.com")
+
+	assert.Equal(t, http.StatusNoContent, admin.API("DELETE", "/api/v1/admin/users/"+memberID, "").Code)
+	assert.Equal(t, http.StatusConflict, admin.API("DELETE", "/api/v1/admin/users/"+adminID, "").Code, "own account")
+	env.users.AssertCalled(t, "Delete", mock.Anything, memberID)
+}
+
+func TestAdminAPI_RestoreUser(t *testing.T) {
+	env := setupAdminRouter(t)
+	const (
+		deletedID = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+		purgedID  = "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"
+		takenID   = "3c4d5e6f-7a8b-4c9d-8e1f-2a3b4c5d6e7f"
+	)
+	now := time.Now()
+	env.users.On("FindDeleted", mock.Anything, deletedID).Return(&entity.User{
+		ID: deletedID, UserName: "gone", Email: "gone@example.com", DeletedAt: gorm.DeletedAt{Time: now, Valid: true},
+	}, nil)
+	env.users.On("FindDeleted", mock.Anything, purgedID).Return(&entity.User{
+		ID: purgedID, UserName: repository.PurgedUserName, Email: repository.PurgedEmail,
+		DeletedAt: gorm.DeletedAt{Time: now, Valid: true}, PurgedAt: &now,
+	}, nil)
+	env.users.On("FindDeleted", mock.Anything, takenID).Return(&entity.User{
+		ID: takenID, UserName: "member", Email: "old@example.com", DeletedAt: gorm.DeletedAt{Time: now, Valid: true},
+	}, nil)
+	env.users.On("FindDeleted", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	env.users.On("FindByUserName", mock.Anything, "member").Return(&entity.User{ID: memberID}, nil)
+	env.users.On("FindByUserName", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	env.users.On("FindByEmail", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	env.users.On("Restore", mock.Anything, deletedID).Return(nil)
+	admin := env.login(t, "admin@example.com")
+
+	w := admin.API("POST", "/api/v1/admin/users/"+deletedID+"/restore", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var restored v1api.AdminUser
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Nil(t, restored.DeletedAt)
+	env.users.AssertCalled(t, "Restore", mock.Anything, deletedID)
+
+	assert.Equal(t, http.StatusGone, admin.API("POST", "/api/v1/admin/users/"+purgedID+"/restore", "").Code)
+	assert.Equal(t, http.StatusConflict, admin.API("POST", "/api/v1/admin/users/"+takenID+"/restore", "").Code)
+	assert.Equal(t, http.StatusOK, admin.API("POST", "/api/v1/admin/users/"+memberID+"/restore", "").Code, "not deleted")
+	env.users.AssertNumberOfCalls(t, "Restore", 1)
+	member := env.login(t, "member@example.com")
+	assert.Equal(t, http.StatusForbidden, member.API("POST", "/api/v1/admin/users/"+deletedID+"/restore", "").Code)
+}
@@ -0,0 +1,253 @@
+package organizations_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	organizationservice "example.com/internal/domain/service/organization"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	ownerID  = "0b6f3c1e-2d4a-4f8e-9a7b-1c2d3e4f5a6b"
+	janeID   = "7e8f9a0b-1c2d-4e3f-8a5b-6c7d8e9f0a1b"
+	johnID   = "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"
+	password = "password123"
+)
+
+var tokenPattern = regexp.MustCompile(`invitation_token=(inv_[A-Za-z0-9_-]+)`)
+
+type testEnv struct {
+	router *gin.Engine
+	outbox *mocks.Outbox
+}
+
+// setupOrganizationsRouter serves an owner and two users, Jane and John, without organizations
+func setupOrganizationsRouter(t *testing.T) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	organizations := &memoryOrganizations{users: map[string]*entity.User{}, organizations: map[string]*entity.Organization{}}
+	users := &mocks.MockUserRepository{}
+	for _, user := range []*entity.User{
+		{ID: ownerID, Email: "owner@example.com", UserName: "owner", PasswordHash: "hash", CreatedAt: time.Now()},
+		{ID: janeID, Email: "jane@example.com", UserName: "jane", PasswordHash: "hash", CreatedAt: time.Now()},
+		{ID: johnID, Email: "john@example.com", UserName: "john", PasswordHash: "hash", CreatedAt: time.Now()},
+	} {
+		organizations.users[user.ID] = user
+		users.On("FindByUserNameOrEmail", mock.Anything, user.Email).Return(user, nil)
+		users.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+		users.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	}
+	users.On("FindByEmail", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	users.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", password, "hash").Return(true)
+
+	invitations := &memoryInvitations{organizations: organizations, invitations: map[string]*entity.Invitation{}}
+	sent := &mocks.Outbox{}
+	authSvc := authservice.NewService(users, hasher)
+	organizationSvc := organizationservice.NewService(organizations, invitations, users, sent, organizationservice.Config{
+		InviteURL: "https://app.example.com/invite",
+		TTL:       time.Hour,
+	})
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(browsertest.Config(), app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User:      &api.UserAPIHandler{},
+		APITokens: &api.APITokenAPIHandler{},
+		Organizations: api.NewOrganizationAPIHandler(
+			userusecase.NewCreateOrganizationUseCase(organizationSvc),
+			userusecase.NewListOrganizationsUseCase(organizationSvc),
+			userusecase.NewListMembersUseCase(organizationSvc),
+			userusecase.NewRemoveMemberUseCase(organizationSvc),
+			userusecase.NewTransferOwnershipUseCase(organizationSvc),
+			userusecase.NewInviteMemberUseCase(organizationSvc),
+			userusecase.NewListInvitationsUseCase(organizationSvc),
+			userusecase.NewRevokeInvitationUseCase(organizationSvc),
+			userusecase.NewAcceptInvitationUseCase(organizationSvc),
+			userusecase.NewDeclineInvitationUseCase(organizationSvc),
+			testLogger,
+		),
+	})
+	require.NoError(t, err)
+
+	return &testEnv{router: router, outbox: sent}
+}
+
+// session is the browser of one user, logged in unless anonymous
+type session struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) login(t *testing.T, email string) *session {
+	s := &session{Browser: browsertest.New(e.router)}
+	if email != "" {
+		s.Login(t, email, password)
+	}
+	return s
+}
+
+// createOrganization creates an organization owned by the session's user and returns its ID
+func (s *session) createOrganization(t *testing.T, name string) string {
+	w := s.API("POST", "/api/v1/organizations", `{"name":"`+name+`"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var organization v1api.Organization
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &organization))
+	assert.Equal(t, v1api.OWNER, organization.Role)
+	return organization.Id
+}
+
+// invite invites email to the organization and returns the token of the invitation email
+func (e *testEnv) invite(t *testing.T, s *session, organizationID, email, role string) string {
+	w := s.API("POST", "/api/v1/organizations/"+organizationID+"/invitations", `{"email":"`+email+`","role":"`+role+`"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	msg := e.outbox.Last()
+	require.Equal(t, email, msg.To)
+	match := tokenPattern.FindStringSubmatch(msg.Body)
+	require.NotNil(t, match, msg.Body)
+	return match[1]
+}
+
+func members(t *testing.T, s *session, organizationID string) map[string]v1api.MembershipRole {
+	w := s.API("GET", "/api/v1/organizations/"+organizationID+"/members", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response v1api.MemberList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	roles := map[string]v1api.MembershipRole{}
+	for _, member := range response.Members {
+		roles[member.Username] = member.Role
+	}
+	return roles
+}
+
+func TestOrganizationsAPI_InvitationFlow(t *testing.T) {
+	env := setupOrganizationsRouter(t)
+	owner := env.login(t, "owner@example.com")
+	jane := env.login(t, "jane@example.com")
+	organizationID := owner.createOrganization(t, "Acme")
+
+	token := env.invite(t, owner, organizationID, "jane@example.com", "admin")
+
+	w := owner.API("GET", "/api/v1/organizations/"+organizationID+"/invitations", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var pending v1api.InvitationList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	require.Len(t, pending.Invitations, 1)
+	assert.Equal(t, v1api.ADMIN, pending.Invitations[0].Role)
+
+	john := env.login(t, "john@example.com")
+	assert.Equal(t, http.StatusNotFound, john.API("POST", "/api/v1/invitations/accept", `{"token":"`+token+`"}`).Code,
+		"invitations only work for the email they were sent to")
+
+	w = jane.API("POST", "/api/v1/invitations/accept", `{"token":"`+token+`"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var organization v1api.Organization
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &organization))
+	assert.Equal(t, v1api.Organization{Id: organizationID, Name: "Acme", Role: v1api.ADMIN, CreatedAt: organization.CreatedAt}, organization)
+
+	assert.Equal(t, http.StatusNotFound, jane.API("POST", "/api/v1/invitations/accept", `{"token":"`+token+`"}`).Code)
+	assert.Equal(t, map[string]v1api.MembershipRole{"owner": v1api.OWNER, "jane": v1api.ADMIN}, members(t, jane, organizationID))
+
+	w = jane.API("GET", "/api/v1/organizations", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list v1api.OrganizationList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Organizations, 1)
+	assert.Equal(t, "Acme", list.Organizations[0].Name)
+}
+
+func TestOrganizationsAPI_DeclineAndRevoke(t *testing.T) {
+	env := setupOrganizationsRouter(t)
+	owner := env.login(t, "owner@example.com")
+	jane := env.login(t, "jane@example.com")
+	organizationID := owner.createOrganization(t, "Acme")
+
+	token := env.invite(t, owner, organizationID, "jane@example.com", "member")
+	assert.Equal(t, http.StatusNoContent, jane.API("POST", "/api/v1/invitations/decline", `{"token":"`+token+`"}`).Code)
+	assert.Equal(t, http.StatusNotFound, jane.API("POST", "/api/v1/invitations/accept", `{"token":"`+token+`"}`).Code)
+
+	token = env.invite(t, owner, organizationID, "jane@example.com", "member")
+	w := owner.API("GET", "/api/v1/organizations/"+organizationID+"/invitations", "")
+	var pending v1api.InvitationList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	require.Len(t, pending.Invitations, 1)
+	path := "/api/v1/organizations/" + organizationID + "/invitations/" + pending.Invitations[0].Id
+	assert.Equal(t, http.StatusNoContent, owner.API("DELETE", path, "").Code)
+	assert.Equal(t, http.StatusNotFound, owner.API("DELETE", path, "").Code)
+	assert.Equal(t, http.StatusNotFound, jane.API("POST", "/api/v1/invitations/accept", `{"token":"`+token+`"}`).Code)
+}
+
+func TestOrganizationsAPI_Roles(t *testing.T) {
+	env := setupOrganizationsRouter(t)
+	owner := env.login(t, "owner@example.com")
+	jane := env.login(t, "jane@example.com")
+	john := env.login(t, "john@example.com")
+	organizationID := owner.createOrganization(t, "Acme")
+	base := "/api/v1/organizations/" + organizationID
+
+	assert.Equal(t, http.StatusNotFound, jane.API("GET", base+"/members", "").Code, "non-members cannot see the organization")
+
+	for _, email := range []string{"jane@example.com", "john@example.com"} {
+		token := env.invite(t, owner, organizationID, email, "member")
+		member := jane
+		if email == "john@example.com" {
+			member = john
+		}
+		require.Equal(t, http.StatusOK, member.API("POST", "/api/v1/invitations/accept", `{"token":"`+token+`"}`).Code)
+	}
+
+	assert.Equal(t, http.StatusForbidden, jane.API("POST", base+"/invitations", `{"email":"new@example.com","role":"member"}`).Code)
+	assert.Equal(t, http.StatusForbidden, jane.API("DELETE", base+"/members/"+johnID, "").Code)
+	assert.Equal(t, http.StatusConflict, owner.API("DELETE", base+"/members/"+ownerID, "").Code)
+	assert.Equal(t, http.StatusConflict, owner.API("POST", base+"/invitations", `{"email":"jane@example.com","role":"member"}`).Code)
+	assert.Equal(t, http.StatusForbidden, jane.API("PUT", base+"/owner", `{"userId":"`+janeID+`"}`).Code)
+
+	require.Equal(t, http.StatusNoContent, owner.API("PUT", base+"/owner", `{"userId":"`+janeID+`"}`).Code)
+	assert.Equal(t, map[string]v1api.MembershipRole{"owner": v1api.ADMIN, "jane": v1api.OWNER, "john": v1api.MEMBER},
+		members(t, john, organizationID))
+
+	assert.Equal(t, http.StatusNoContent, owner.API("DELETE", base+"/members/"+johnID, "").Code, "admins remove members")
+	assert.Equal(t, http.StatusNoContent, owner.API("DELETE", base+"/members/"+ownerID, "").Code, "admins may leave")
+	assert.Equal(t, http.StatusNotFound, owner.API("GET", base+"/members", "").Code)
+	assert.Equal(t, map[string]v1api.MembershipRole{"jane": v1api.OWNER}, members(t, jane, organizationID))
+}
+
+func TestOrganizationsAPI_RequireSession(t *testing.T) {
+	env := setupOrganizationsRouter(t)
+
+	assert.Equal(t, http.StatusUnauthorized, env.login(t, "").API("GET", "/api/v1/organizations", "").Code)
+	assert.Equal(t, http.StatusBadRequest, env.login(t, "owner@example.com").API("POST", "/api/v1/organizations", `{"name":" "}`).Code)
+}
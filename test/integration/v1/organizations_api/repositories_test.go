@@ -0,0 +1,202 @@
+package organizations_api_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+)
+
+// The memory repositories keep records in memory so that an invitation can be followed from
+// the email to the membership it grants
+
+type memoryOrganizations struct {
+	users         map[string]*entity.User
+	organizations map[string]*entity.Organization
+	memberships   []*entity.Membership
+	mu            sync.Mutex
+}
+
+func (r *memoryOrganizations) Create(_ context.Context, organization *entity.Organization, owner *entity.Membership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Like GORM, creating fills in the timestamps of the records given
+	organization.CreatedAt, owner.CreatedAt = time.Now(), time.Now()
+	stored := *organization
+	r.organizations[organization.ID] = &stored
+	r.memberships = append(r.memberships, &entity.Membership{
+		CreatedAt: owner.CreatedAt, OrganizationID: owner.OrganizationID, UserID: owner.UserID, Role: owner.Role,
+	})
+	return nil
+}
+
+func (r *memoryOrganizations) FindByID(_ context.Context, id string) (*entity.Organization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if organization, ok := r.organizations[id]; ok {
+		stored := *organization
+		return &stored, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryOrganizations) ListByUserID(_ context.Context, userID string) ([]*entity.Membership, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var memberships []*entity.Membership
+	for _, membership := range r.memberships {
+		if membership.UserID == userID {
+			stored := *membership
+			stored.Organization = r.organizations[membership.OrganizationID]
+			memberships = append(memberships, &stored)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *memoryOrganizations) FindMembership(_ context.Context, organizationID, userID string) (*entity.Membership, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(organizationID, userID); i >= 0 {
+		stored := *r.memberships[i]
+		return &stored, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryOrganizations) ListMembers(_ context.Context, organizationID string) ([]*entity.Membership, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var memberships []*entity.Membership
+	for _, membership := range r.memberships {
+		if membership.OrganizationID == organizationID {
+			stored := *membership
+			stored.User = r.users[membership.UserID]
+			memberships = append(memberships, &stored)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *memoryOrganizations) RemoveMember(_ context.Context, organizationID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(organizationID, userID)
+	if i < 0 {
+		return gorm.ErrRecordNotFound
+	}
+	r.memberships = slices.Delete(r.memberships, i, i+1)
+	return nil
+}
+
+func (r *memoryOrganizations) TransferOwnership(_ context.Context, organizationID, ownerID, newOwnerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	owner, newOwner := r.index(organizationID, ownerID), r.index(organizationID, newOwnerID)
+	if owner < 0 || newOwner < 0 || r.memberships[owner].Role != entity.MembershipOwner {
+		return gorm.ErrRecordNotFound
+	}
+	r.memberships[owner].Role = entity.MembershipAdmin
+	r.memberships[newOwner].Role = entity.MembershipOwner
+	return nil
+}
+
+func (r *memoryOrganizations) add(membership *entity.Membership) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.memberships = append(r.memberships, membership)
+}
+
+func (r *memoryOrganizations) index(organizationID, userID string) int {
+	return slices.IndexFunc(r.memberships, func(m *entity.Membership) bool {
+		return m.OrganizationID == organizationID && m.UserID == userID
+	})
+}
+
+type memoryInvitations struct {
+	organizations *memoryOrganizations
+	invitations   map[string]*entity.Invitation
+	mu            sync.Mutex
+}
+
+func (r *memoryInvitations) Create(_ context.Context, invitation *entity.Invitation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	invitation.CreatedAt = time.Now()
+	stored := *invitation
+	r.invitations[invitation.ID] = &stored
+	return nil
+}
+
+func (r *memoryInvitations) FindByID(_ context.Context, id string) (*entity.Invitation, error) {
+	return r.find(func(i *entity.Invitation) bool { return i.ID == id })
+}
+
+func (r *memoryInvitations) FindByTokenHash(_ context.Context, tokenHash string) (*entity.Invitation, error) {
+	return r.find(func(i *entity.Invitation) bool { return i.TokenHash == tokenHash })
+}
+
+func (r *memoryInvitations) ListPending(_ context.Context, organizationID string, now time.Time) ([]*entity.Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var invitations []*entity.Invitation
+	for _, invitation := range r.invitations {
+		if invitation.OrganizationID == organizationID && invitation.Pending(now) {
+			stored := *invitation
+			invitations = append(invitations, &stored)
+		}
+	}
+	return invitations, nil
+}
+
+func (r *memoryInvitations) Accept(ctx context.Context, id string, membership *entity.Membership, at time.Time) error {
+	if err := r.Respond(ctx, id, entity.InvitationAccepted, at); err != nil {
+		return err
+	}
+	membership.CreatedAt = at
+	r.organizations.add(&entity.Membership{
+		CreatedAt: at, OrganizationID: membership.OrganizationID, UserID: membership.UserID, Role: membership.Role,
+	})
+	return nil
+}
+
+func (r *memoryInvitations) Respond(_ context.Context, id, status string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	invitation, ok := r.invitations[id]
+	if !ok || invitation.Status != entity.InvitationPending {
+		return gorm.ErrRecordNotFound
+	}
+	invitation.Status = status
+	invitation.RespondedAt = &at
+	return nil
+}
+
+func (r *memoryInvitations) RevokePending(_ context.Context, organizationID, email string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, invitation := range r.invitations {
+		if invitation.OrganizationID == organizationID && invitation.Email == email &&
+			invitation.Status == entity.InvitationPending {
+			invitation.Status = entity.InvitationRevoked
+			invitation.RespondedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryInvitations) find(match func(*entity.Invitation) bool) (*entity.Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, invitation := range r.invitations {
+		if match(invitation) {
+			stored := *invitation
+			return &stored, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
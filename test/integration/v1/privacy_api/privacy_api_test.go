@@ -0,0 +1,247 @@
+package privacy_api_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	privacyservice "example.com/internal/domain/service/privacy"
+	retentionservice "example.com/internal/domain/service/retention"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/storage"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	janeID  = "7e8f9a0b-1c2d-4e3f-8a5b-6c7d8e9f0a1b"
+	ownerID = "0b6f3c1e-2d4a-4f8e-9a7b-1c2d3e4f5a6b"
+)
+
+var (
+	downloadPattern = regexp.MustCompile(`http://localhost:8080(/api/v1/data-export/download\?token=dex_[A-Za-z0-9_-]+)`)
+	confirmPattern  = regexp.MustCompile(`deletion_token=(adr_[A-Za-z0-9_-]+)`)
+)
+
+type testEnv struct {
+	router  *gin.Engine
+	privacy privacyservice.Service
+	audit   *memoryAuditEvents
+	outbox  *mocks.Outbox
+}
+
+// setupPrivacyRouter serves Jane and the owner of an organization, logins being recorded
+func setupPrivacyRouter(t *testing.T) *testEnv {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	users := &mocks.MockUserRepository{}
+	organizations := &mocks.MockOrganizationRepository{}
+	personalData := &mocks.MockPersonalDataRepository{}
+	for _, user := range []*entity.User{
+		{ID: janeID, Email: "jane@example.com", UserName: "jane", PasswordHash: "hash", CreatedAt: time.Now()},
+		{ID: ownerID, Email: "owner@example.com", UserName: "owner", PasswordHash: "hash", CreatedAt: time.Now()},
+	} {
+		users.On("FindByUserNameOrEmail", mock.Anything, user.Email).Return(user, nil)
+		users.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+		personalData.On("Collect", mock.Anything, user.ID).Return(&entity.PersonalData{User: user}, nil)
+	}
+	users.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	organizations.On("ListByUserID", mock.Anything, janeID).Return([]*entity.Membership{}, nil)
+	organizations.On("ListByUserID", mock.Anything, ownerID).Return([]*entity.Membership{{Role: entity.MembershipOwner}}, nil)
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", "password123", "hash").Return(true)
+
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	exports := &memoryExports{}
+	audit := &memoryAuditEvents{}
+	sent := &mocks.Outbox{}
+	authSvc := authservice.NewService(users, hasher)
+	retentionSvc := retentionservice.NewService(users, exports, store, retentionservice.Config{DeletedRetention: time.Hour})
+	privacySvc := privacyservice.NewService(
+		users, organizations, exports, &memoryDeletions{deletions: map[string]*entity.AccountDeletion{}}, personalData, audit,
+		retentionSvc, store, sent,
+		privacyservice.Config{
+			DownloadURL: "http://localhost:8080/api/v1/data-export/download",
+			ExportTTL:   time.Hour,
+			ConfirmURL:  "https://app.example.com/delete",
+			ConfirmTTL:  time.Hour,
+			CoolingOff:  24 * time.Hour,
+		},
+	)
+	testLogger := logger.New("test")
+
+	router, err := app.NewRouter(browsertest.Config(), app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User:      &api.UserAPIHandler{},
+		APITokens: &api.APITokenAPIHandler{},
+		Privacy: api.NewPrivacyAPIHandler(
+			userusecase.NewRequestDataExportUseCase(privacySvc),
+			userusecase.NewGetDataExportUseCase(privacySvc),
+			userusecase.NewDownloadDataExportUseCase(privacySvc),
+			userusecase.NewRequestAccountDeletionUseCase(privacySvc),
+			userusecase.NewConfirmAccountDeletionUseCase(privacySvc),
+			userusecase.NewGetAccountDeletionUseCase(privacySvc),
+			userusecase.NewCancelAccountDeletionUseCase(privacySvc),
+			testLogger,
+		),
+		RecordLogin: authusecase.NewRecordLoginUseCase(privacySvc),
+		Logger:      testLogger,
+	})
+	require.NoError(t, err)
+
+	return &testEnv{router: router, privacy: privacySvc, audit: audit, outbox: sent}
+}
+
+// session is the browser of one user, logged in unless anonymous
+type session struct {
+	*browsertest.Browser
+}
+
+func (e *testEnv) login(t *testing.T, email string) *session {
+	s := &session{Browser: browsertest.New(e.router)}
+	s.Header.Set("User-Agent", "privacy-test")
+	if email != "" {
+		s.Login(t, email, "password123")
+	}
+	return s
+}
+
+func TestPrivacyAPI_DataExport(t *testing.T) {
+	env := setupPrivacyRouter(t)
+	jane := env.login(t, "jane@example.com")
+
+	events := env.audit.list()
+	require.Len(t, events, 1, "the login is recorded")
+	assert.Equal(t, entity.AuditLogin, events[0].Action)
+	assert.Equal(t, "password", events[0].Detail)
+	assert.Equal(t, "privacy-test", events[0].UserAgent)
+	assert.NotEmpty(t, events[0].IPAddress)
+
+	assert.Equal(t, http.StatusNotFound, jane.API("GET", "/api/v1/users/me/data-export", "").Code)
+
+	w := jane.API("POST", "/api/v1/users/me/data-export", "")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var requested v1api.DataExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &requested))
+	assert.Equal(t, entity.DataExportPending, requested.Status)
+
+	w = jane.API("POST", "/api/v1/users/me/data-export", "")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var again v1api.DataExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &again))
+	assert.Equal(t, requested.Id, again.Id, "the pending export is returned")
+
+	built, err := env.privacy.ProcessExports(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, built)
+
+	w = jane.API("GET", "/api/v1/users/me/data-export", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var ready v1api.DataExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ready))
+	assert.Equal(t, entity.DataExportReady, ready.Status)
+	require.NotNil(t, ready.ExpiresAt)
+
+	// The link works without a session
+	match := downloadPattern.FindStringSubmatch(env.outbox.Last().Body)
+	require.NotNil(t, match, env.outbox.Last().Body)
+	anonymous := env.login(t, "")
+	w = anonymous.Do(httptest.NewRequest("GET", match[1], nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+	assert.NotEmpty(t, archive.File)
+
+	w = anonymous.Do(httptest.NewRequest("GET", "/api/v1/data-export/download?token="+url.QueryEscape("dex_bogus"), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPrivacyAPI_AccountDeletion(t *testing.T) {
+	env := setupPrivacyRouter(t)
+	jane := env.login(t, "jane@example.com")
+
+	w := jane.API("POST", "/api/v1/users/me/deletion", "")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var requested v1api.AccountDeletion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &requested))
+	assert.Equal(t, "requested", requested.Status)
+	assert.Equal(t, "jane@example.com", env.outbox.Last().To)
+	match := confirmPattern.FindStringSubmatch(env.outbox.Last().Body)
+	require.NotNil(t, match, env.outbox.Last().Body)
+
+	w = jane.API("POST", "/api/v1/users/me/deletion/confirm", `{"token":"adr_wrong"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = jane.API("POST", "/api/v1/users/me/deletion/confirm", `{"token":"`+match[1]+`"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var scheduled v1api.AccountDeletion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scheduled))
+	assert.Equal(t, "scheduled", scheduled.Status)
+	require.NotNil(t, scheduled.ScheduledAt)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), *scheduled.ScheduledAt, time.Minute)
+
+	w = jane.API("GET", "/api/v1/users/me/deletion", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, jane.API("DELETE", "/api/v1/users/me/deletion", "").Code)
+	assert.Equal(t, http.StatusNotFound, jane.API("GET", "/api/v1/users/me/deletion", "").Code)
+	assert.Equal(t, http.StatusNotFound, jane.API("DELETE", "/api/v1/users/me/deletion", "").Code)
+}
+
+func TestPrivacyAPI_AccountDeletion_OrganizationOwner(t *testing.T) {
+	env := setupPrivacyRouter(t)
+
+	w := env.login(t, "owner@example.com").API("POST", "/api/v1/users/me/deletion", "")
+
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+}
+
+func TestPrivacyAPI_RequireSession(t *testing.T) {
+	env := setupPrivacyRouter(t)
+	anonymous := env.login(t, "")
+
+	for _, route := range []struct{ method, path string }{
+		{"GET", "/api/v1/users/me/data-export"},
+		{"POST", "/api/v1/users/me/data-export"},
+		{"POST", "/api/v1/users/me/deletion"},
+		{"DELETE", "/api/v1/users/me/deletion"},
+	} {
+		assert.Equal(t, http.StatusUnauthorized, anonymous.API(route.method, route.path, "").Code, route.path)
+	}
+}
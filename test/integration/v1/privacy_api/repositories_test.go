@@ -0,0 +1,162 @@
+package privacy_api_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+)
+
+// The memory repositories keep records in memory so that an export or deletion can be
+// followed from the request to the emailed link
+
+type memoryExports struct {
+	exports []*entity.DataExport
+	mu      sync.Mutex
+}
+
+func (r *memoryExports) Create(_ context.Context, export *entity.DataExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	export.CreatedAt = time.Now()
+	stored := *export
+	r.exports = append(r.exports, &stored)
+	return nil
+}
+
+func (r *memoryExports) Update(_ context.Context, export *entity.DataExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.exports {
+		if stored.ID == export.ID {
+			updated := *export
+			r.exports[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (r *memoryExports) FindLatest(_ context.Context, userID string) (*entity.DataExport, error) {
+	exports := r.list(func(e *entity.DataExport) bool { return e.UserID == userID })
+	if len(exports) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return exports[len(exports)-1], nil
+}
+
+func (r *memoryExports) FindByTokenHash(_ context.Context, tokenHash string) (*entity.DataExport, error) {
+	exports := r.list(func(e *entity.DataExport) bool { return e.TokenHash != "" && e.TokenHash == tokenHash })
+	if len(exports) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return exports[0], nil
+}
+
+func (r *memoryExports) ListByUserID(_ context.Context, userID string) ([]*entity.DataExport, error) {
+	return r.list(func(e *entity.DataExport) bool { return e.UserID == userID }), nil
+}
+
+func (r *memoryExports) ListPending(_ context.Context, limit int) ([]*entity.DataExport, error) {
+	exports := r.list(func(e *entity.DataExport) bool { return e.Status == entity.DataExportPending })
+	return exports[:min(limit, len(exports))], nil
+}
+
+func (r *memoryExports) ListExpired(_ context.Context, before time.Time, limit int) ([]*entity.DataExport, error) {
+	exports := r.list(func(e *entity.DataExport) bool { return e.ExpiresAt != nil && e.ExpiresAt.Before(before) })
+	return exports[:min(limit, len(exports))], nil
+}
+
+func (r *memoryExports) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exports = slices.DeleteFunc(r.exports, func(e *entity.DataExport) bool { return e.ID == id })
+	return nil
+}
+
+// list returns copies of the exports matching, oldest first
+func (r *memoryExports) list(match func(*entity.DataExport) bool) []*entity.DataExport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var exports []*entity.DataExport
+	for _, export := range r.exports {
+		if match(export) {
+			stored := *export
+			exports = append(exports, &stored)
+		}
+	}
+	return exports
+}
+
+type memoryDeletions struct {
+	deletions map[string]*entity.AccountDeletion
+	mu        sync.Mutex
+}
+
+func (r *memoryDeletions) Save(_ context.Context, deletion *entity.AccountDeletion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if deletion.CreatedAt.IsZero() {
+		deletion.CreatedAt = time.Now()
+	}
+	stored := *deletion
+	r.deletions[deletion.UserID] = &stored
+	return nil
+}
+
+func (r *memoryDeletions) FindByUserID(_ context.Context, userID string) (*entity.AccountDeletion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deletion, ok := r.deletions[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	stored := *deletion
+	return &stored, nil
+}
+
+func (r *memoryDeletions) ListDue(_ context.Context, before time.Time, limit int) ([]*entity.AccountDeletion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*entity.AccountDeletion
+	for _, deletion := range r.deletions {
+		if deletion.ScheduledAt != nil && !deletion.ScheduledAt.After(before) && len(due) < limit {
+			stored := *deletion
+			due = append(due, &stored)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryDeletions) Delete(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deletions[userID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.deletions, userID)
+	return nil
+}
+
+type memoryAuditEvents struct {
+	events []*entity.AuditEvent
+	mu     sync.Mutex
+}
+
+func (r *memoryAuditEvents) Create(_ context.Context, event *entity.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.CreatedAt = time.Now()
+	stored := *event
+	r.events = append(r.events, &stored)
+	return nil
+}
+
+func (r *memoryAuditEvents) list() []*entity.AuditEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
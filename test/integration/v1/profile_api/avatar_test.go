@@ -0,0 +1,173 @@
+package profile_api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authapi "example.com/gen/openapi/auth/go"
+)
+
+// uploadAvatar sends data as the avatar field of a multipart body
+func (s *session) uploadAvatar(t *testing.T, data []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("avatar", "me.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	return s.Send("PUT", "/api/v1/users/me/profile/avatar", form.FormDataContentType(), body.String())
+}
+
+func squarePNG(t *testing.T, size int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// fetch requests an avatar URL of a profile without any credentials
+func fetch(router http.Handler, rawURL string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", rawURL, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAvatarAPI_UploadAndServe(t *testing.T) {
+	router, _ := setupProfileRouter(t)
+	jane := login(t, router, "jane@example.com")
+
+	w := jane.uploadAvatar(t, squarePNG(t, 300))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	profile := jane.profile(t)
+	require.NotNil(t, profile.Avatar)
+	assert.True(t, strings.HasPrefix(profile.Avatar.Large, "http://localhost:8080/avatars/"+janeID+"/"), profile.Avatar.Large)
+	assert.Equal(t, profile.Avatar, jane.profile(t).Avatar, "URLs are stable, so browsers can cache the images")
+
+	w = fetch(router, profile.Avatar.Medium, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "immutable")
+	img, _, err := image.Decode(w.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 128, img.Bounds().Dx())
+
+	w = fetch(router, profile.Avatar.Medium, http.Header{"If-None-Match": {w.Header().Get("ETag")}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestAvatarAPI_RejectsUnsignedURLs(t *testing.T) {
+	router, _ := setupProfileRouter(t)
+	jane := login(t, router, "jane@example.com")
+	require.Equal(t, http.StatusOK, jane.uploadAvatar(t, squarePNG(t, 64)).Code)
+	signed, err := url.Parse(jane.profile(t).Avatar.Small)
+	require.NoError(t, err)
+
+	unsigned := *signed
+	unsigned.RawQuery = ""
+	assert.Equal(t, http.StatusBadRequest, fetch(router, unsigned.String(), nil).Code)
+
+	query := signed.Query()
+	query.Set("expires", "9999999999")
+	extended := *signed
+	extended.RawQuery = query.Encode()
+	assert.Equal(t, http.StatusForbidden, fetch(router, extended.String(), nil).Code)
+
+	other := *signed
+	other.Path = strings.Replace(signed.Path, "_64.", "_256.", 1)
+	assert.Equal(t, http.StatusForbidden, fetch(router, other.String(), nil).Code)
+}
+
+func TestAvatarAPI_RejectsInvalidUploads(t *testing.T) {
+	router, profiles := setupProfileRouter(t)
+	jane := login(t, router, "jane@example.com")
+
+	assert.Equal(t, http.StatusBadRequest, jane.uploadAvatar(t, []byte("GIF89a")).Code)
+	assert.Equal(t, http.StatusBadRequest, jane.uploadAvatar(t, []byte("#!/bin/sh")).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, jane.uploadAvatar(t, make([]byte, maxUploadSize+1)).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, jane.uploadAvatar(t, make([]byte, 2*maxUploadSize)).Code)
+
+	assert.Empty(t, profiles.profiles)
+}
+
+// countingReader counts the bytes read from a request body
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestAvatarAPI_CSRFTokenIsNotReadFromUploads(t *testing.T) {
+	router, _ := setupProfileRouter(t)
+	jane := login(t, router, "jane@example.com")
+	w := jane.Get("/csrf-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	var token authapi.CsrfToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &token))
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	require.NoError(t, form.WriteField("_csrf", token.Token))
+	part, err := form.CreateFormFile("avatar", "me.png")
+	require.NoError(t, err)
+	_, err = part.Write(make([]byte, 2*maxUploadSize))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+	upload := &countingReader{r: &body}
+	req := httptest.NewRequest("PUT", "/api/v1/users/me/profile/avatar", upload)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	assert.Equal(t, http.StatusForbidden, jane.Do(req).Code)
+	assert.Zero(t, upload.read, "the body is refused unread")
+}
+
+func TestAvatarAPI_Delete(t *testing.T) {
+	router, _ := setupProfileRouter(t)
+	jane := login(t, router, "jane@example.com")
+	require.Equal(t, http.StatusOK, jane.uploadAvatar(t, squarePNG(t, 64)).Code)
+	large := jane.profile(t).Avatar.Large
+
+	w := jane.Send("DELETE", "/api/v1/users/me/profile/avatar", "", "")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	assert.Nil(t, jane.profile(t).Avatar)
+	assert.Equal(t, http.StatusNotFound, fetch(router, large, nil).Code)
+	assert.Equal(t, http.StatusNoContent, jane.Send("DELETE", "/api/v1/users/me/profile/avatar", "", "").Code)
+}
+
+func TestAvatarAPI_RequiresAuthentication(t *testing.T) {
+	router, _ := setupProfileRouter(t)
+	anonymous := login(t, router, "")
+
+	assert.Equal(t, http.StatusUnauthorized, anonymous.uploadAvatar(t, squarePNG(t, 64)).Code)
+	assert.Equal(t, http.StatusUnauthorized, anonymous.Send("DELETE", "/api/v1/users/me/profile/avatar", "", "").Code)
+}
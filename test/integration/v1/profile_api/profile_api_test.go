@@ -0,0 +1,179 @@
+package profile_api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	avatarservice "example.com/internal/domain/service/avatar"
+	profileservice "example.com/internal/domain/service/profile"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/config"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/storage"
+	"example.com/pkg/storage/storagetest"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	janeID   = "7e8f9a0b-1c2d-4e3f-8a5b-6c7d8e9f0a1b"
+	password = "password123"
+)
+
+// maxUploadSize bounds avatar uploads in these tests
+const maxUploadSize = 256 << 10
+
+// setupProfileRouter serves Jane, who has no profile yet. Avatars are kept in a stand-in for S3.
+func setupProfileRouter(t *testing.T) (*gin.Engine, *memoryProfiles) {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	jane := &entity.User{ID: janeID, Email: "jane@example.com", UserName: "jane", PasswordHash: "hash", CreatedAt: time.Now()}
+	mockUsers := &mocks.MockUserRepository{}
+	mockUsers.On("FindByUserNameOrEmail", mock.Anything, jane.Email).Return(jane, nil)
+	mockUsers.On("FindByID", mock.Anything, jane.ID).Return(jane, nil)
+	mockUsers.On("Update", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", password, "hash").Return(true)
+
+	profiles := &memoryProfiles{profiles: map[string]*entity.UserProfile{}}
+	users := &usersWithProfiles{MockUserRepository: mockUsers, profiles: profiles}
+	authSvc := authservice.NewService(users, hasher)
+	profileSvc := profileservice.NewService(users, profiles)
+	testLogger := logger.New("test")
+
+	cfg := browsertest.Config()
+	cfg.Avatars = config.AvatarsConfig{MaxUploadSize: maxUploadSize, MaxPixels: 1 << 20, URLTTL: time.Hour}
+	store, err := storage.NewS3Store(storagetest.NewS3Server(t, "avatars").Config(), nil)
+	require.NoError(t, err)
+	avatarSvc := avatarservice.NewService(profileSvc, profiles, store, avatarservice.Config{
+		MaxBytes:  cfg.Avatars.MaxUploadSize,
+		MaxPixels: cfg.Avatars.MaxPixels,
+	})
+	avatarURLs, err := cfg.Avatars.URLSigner(cfg.Security)
+	require.NoError(t, err)
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, nil),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User:      &api.UserAPIHandler{},
+		APITokens: &api.APITokenAPIHandler{},
+		Profile: api.NewProfileAPIHandler(
+			userusecase.NewGetProfileUseCase(profileSvc),
+			userusecase.NewUpdateProfileUseCase(profileSvc),
+			userusecase.NewUploadAvatarUseCase(avatarSvc),
+			userusecase.NewDeleteAvatarUseCase(avatarSvc),
+			avatarURLs,
+			testLogger,
+			"http://localhost:8080",
+		),
+		Avatars: api.NewAvatarAPIHandler(userusecase.NewOpenAvatarUseCase(avatarSvc), avatarURLs, testLogger),
+	})
+	require.NoError(t, err)
+
+	return router, profiles
+}
+
+// session is the browser of one user, logged in unless anonymous
+type session struct {
+	*browsertest.Browser
+}
+
+func login(t *testing.T, router *gin.Engine, email string) *session {
+	s := &session{Browser: browsertest.New(router)}
+	if email != "" {
+		s.Login(t, email, password)
+	}
+	return s
+}
+
+// patch sends a JSON Merge Patch of the profile
+func (s *session) patch(body string) *httptest.ResponseRecorder {
+	return s.Send("PATCH", "/api/v1/users/me/profile", "application/merge-patch+json", body)
+}
+
+func (s *session) profile(t *testing.T) v1api.Profile {
+	w := s.Send("GET", "/api/v1/users/me/profile", "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var profile v1api.Profile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+	return profile
+}
+
+func TestProfileAPI_MergePatch(t *testing.T) {
+	router, profiles := setupProfileRouter(t)
+	jane := login(t, router, "jane@example.com")
+
+	assert.Equal(t, v1api.Profile{}, jane.profile(t))
+
+	w := jane.patch(`{"displayName":"Jane Doe","bio":"Gardener","locale":"en-us","timezone":"Europe/Berlin"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	want := v1api.Profile{DisplayName: "Jane Doe", Bio: "Gardener", Locale: "en-US", Timezone: "Europe/Berlin"}
+	assert.Equal(t, want, jane.profile(t))
+
+	// Fields set to null are cleared, fields left out keep their value
+	w = jane.patch(`{"bio":null,"locale":"de-DE"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	want = v1api.Profile{DisplayName: "Jane Doe", Locale: "de-DE", Timezone: "Europe/Berlin"}
+	assert.Equal(t, want, jane.profile(t))
+
+	saved, err := profiles.FindByUserID(context.Background(), janeID)
+	require.NoError(t, err)
+	assert.Empty(t, saved.Bio)
+}
+
+func TestProfileAPI_InvalidPatch(t *testing.T) {
+	router, profiles := setupProfileRouter(t)
+	jane := login(t, router, "jane@example.com")
+
+	for _, body := range []string{
+		`{"timezone":"Mars/Olympus_Mons"}`,
+		`{"locale":"not a locale"}`,
+		`{"displayName":"Jane","nickname":"JD"}`,
+		`{"bio":42}`,
+		`[]`,
+	} {
+		w := jane.patch(body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	w := jane.Send("PATCH", "/api/v1/users/me/profile", "application/json", `{"bio":"Gardener"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "merge patches need their media type")
+
+	assert.Empty(t, profiles.profiles)
+}
+
+func TestProfileAPI_RequiresAuthentication(t *testing.T) {
+	router, _ := setupProfileRouter(t)
+	anonymous := login(t, router, "")
+
+	assert.Equal(t, http.StatusUnauthorized, anonymous.Send("GET", "/api/v1/users/me/profile", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, anonymous.patch(`{"bio":"Gardener"}`).Code)
+}
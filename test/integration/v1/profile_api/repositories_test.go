@@ -0,0 +1,52 @@
+package profile_api_test
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/test/unit/mocks"
+)
+
+// memoryProfiles keeps profiles in memory, keyed by user
+type memoryProfiles struct {
+	profiles map[string]*entity.UserProfile
+	mu       sync.Mutex
+}
+
+func (r *memoryProfiles) FindByUserID(_ context.Context, userID string) (*entity.UserProfile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *profile
+	return &found, nil
+}
+
+func (r *memoryProfiles) Save(_ context.Context, profile *entity.UserProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := *profile
+	r.profiles[profile.UserID] = &saved
+	return nil
+}
+
+// usersWithProfiles preloads the profile of the users found by ID, like the database repository
+type usersWithProfiles struct {
+	*mocks.MockUserRepository
+	profiles *memoryProfiles
+}
+
+func (r *usersWithProfiles) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	user, err := r.MockUserRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	found := *user
+	found.Profile, _ = r.profiles.FindByUserID(ctx, id)
+	return &found, nil
+}
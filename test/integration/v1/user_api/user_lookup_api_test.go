@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	v1api "example.com/gen/openapi/v1/go"
 	"example.com/internal/domain/entity"
@@ -18,6 +19,7 @@ import (
 	userusecase "example.com/internal/domain/usecase/v1"
 	"example.com/internal/infrastructure/logger"
 	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
 	"example.com/test/unit/mocks"
 )
 
@@ -26,10 +28,10 @@ func setupUserLookupRouter() (*gin.Engine, *mocks.MockUserRepository) {
 
 	mockRepo := &mocks.MockUserRepository{}
 	userSvc := userservice.NewService(mockRepo)
-	userLookupUseCase := userusecase.NewUserLookupUseCase(userSvc)
+	userLookupUseCase := userusecase.NewUserLookupUseCase(userSvc, nil)
 	testLogger := logger.New("test")
 
-	userAPIHandler := api.NewUserAPIHandler(userLookupUseCase, testLogger)
+	userAPIHandler := api.NewUserAPIHandler(userLookupUseCase, nil, testLogger)
 
 	router := gin.New()
 	user := router.Group("/user")
@@ -102,7 +104,7 @@ func TestUserLookupAPI_MissingEmailParameter(t *testing.T) {
 	var errorResp v1api.Error
 	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
 	assert.NoError(t, err)
-	assert.Equal(t, "Email parameter is required", errorResp.Message)
+	assert.Equal(t, "Exactly one of the email and username parameters is required", errorResp.Message)
 }
 
 func TestUserLookupAPI_DatabaseError(t *testing.T) {
@@ -124,3 +126,32 @@ func TestUserLookupAPI_DatabaseError(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserLookupAPI_OpenAPIValidation_InvalidEmail(t *testing.T) {
+	router, mockRepo := setupUserLookupRouter()
+
+	doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, doc)
+	require.NoError(t, err)
+
+	userSvc := userservice.NewService(mockRepo)
+	userAPIHandler := api.NewUserAPIHandler(userusecase.NewUserLookupUseCase(userSvc, nil), nil, logger.New("test"))
+	router.GET("/validated/user/lookup", validator.Operation("userLookup"), userAPIHandler.UserLookup)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/validated/user/lookup?email=not-an-email", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResp v1api.Error
+	err = json.Unmarshal(w.Body.Bytes(), &errorResp)
+	assert.NoError(t, err)
+	require.Len(t, errorResp.Details, 1)
+	assert.Equal(t, "email", errorResp.Details[0].Field)
+	assert.Equal(t, "query", errorResp.Details[0].In)
+
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,63 @@
+package username_api_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+)
+
+// memoryUserNameChanges keeps renames in memory
+type memoryUserNameChanges struct {
+	changes []*entity.UserNameChange
+	mu      sync.Mutex
+}
+
+func (r *memoryUserNameChanges) Create(_ context.Context, change *entity.UserNameChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := *change
+	created.CreatedAt = time.Now()
+	change.CreatedAt = created.CreatedAt
+	r.changes = append(r.changes, &created)
+	return nil
+}
+
+func (r *memoryUserNameChanges) ListByUserID(_ context.Context, userID string) ([]*entity.UserNameChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var changes []*entity.UserNameChange
+	for _, change := range r.changes {
+		if change.UserID == userID {
+			found := *change
+			changes = append(changes, &found)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].CreatedAt.After(changes[j].CreatedAt) })
+	return changes, nil
+}
+
+func (r *memoryUserNameChanges) FindRedirect(_ context.Context, oldSkeleton string, now time.Time) (*entity.UserNameChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.changes) - 1; i >= 0; i-- {
+		if change := r.changes[i]; change.OldSkeleton == oldSkeleton && change.RedirectExpiresAt.After(now) {
+			found := *change
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// expire ends every redirect, as if the grace period had passed
+func (r *memoryUserNameChanges) expire() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, change := range r.changes {
+		change.RedirectExpiresAt = time.Now().Add(-time.Second)
+	}
+}
@@ -0,0 +1,225 @@
+package username_api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	v1api "example.com/gen/openapi/v1/go"
+	"example.com/internal/app"
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	usernameservice "example.com/internal/domain/service/username"
+	userservice "example.com/internal/domain/service/v1"
+	authusecase "example.com/internal/domain/usecase/auth"
+	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/internal/infrastructure/logger"
+	"example.com/internal/interfaces/api"
+	"example.com/internal/interfaces/middleware"
+	"example.com/pkg/security"
+	"example.com/test/integration/browsertest"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	janeID   = "7e8f9a0b-1c2d-4e3f-8a5b-6c7d8e9f0a1b"
+	bobID    = "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"
+	password = "password123"
+)
+
+// setupUsernameRouter serves Jane and Bob, who wait a day between renames and whose old names
+// redirect for a month
+func setupUsernameRouter(t *testing.T) (*gin.Engine, *memoryUserNameChanges, *mocks.MockAuditEventRepository) {
+	gin.SetMode(gin.TestMode)
+
+	authDoc, err := middleware.LoadOpenAPIDocument("auth")
+	require.NoError(t, err)
+	v1Doc, err := middleware.LoadOpenAPIDocument("v1")
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(middleware.OpenAPIValidatorOptions{ValidateResponses: true}, authDoc, v1Doc)
+	require.NoError(t, err)
+
+	users := mocks.NewMemoryUserRepository(
+		&entity.User{ID: janeID, Email: "jane@example.com", UserName: "jane", PasswordHash: "hash", CreatedAt: time.Now()},
+		&entity.User{ID: bobID, Email: "bob@example.com", UserName: "bob", PasswordHash: "hash", CreatedAt: time.Now()},
+	)
+	changes := &memoryUserNameChanges{}
+	audit := &mocks.MockAuditEventRepository{}
+	audit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditEvent")).Return(nil)
+	hasher := &mocks.MockPasswordHasher{}
+	hasher.On("Verify", password, "hash").Return(true)
+
+	authSvc := authservice.NewService(users, hasher)
+	usernameSvc := usernameservice.NewService(users, changes, audit, usernameservice.Config{
+		Policy:         security.NewUsernamePolicy(security.UsernameRules{MinLength: 3, MaxLength: entity.UserNameMaxLength}),
+		RedirectTTL:    30 * 24 * time.Hour,
+		RenameInterval: 24 * time.Hour,
+	})
+	testLogger := logger.New("test")
+
+	cfg := browsertest.Config()
+
+	router, err := app.NewRouter(cfg, app.Handlers{
+		Validator: validator,
+		Auth: api.NewAuthAPIHandler(
+			authusecase.NewSignupUseCase(authSvc, nil, usernameSvc),
+			authusecase.NewLoginUseCase(authSvc),
+			nil,
+			testLogger,
+		),
+		User: api.NewUserAPIHandler(
+			userusecase.NewUserLookupUseCase(userservice.NewService(users), nil),
+			userusecase.NewUsernameLookupUseCase(usernameSvc, nil),
+			testLogger,
+		),
+		APITokens: &api.APITokenAPIHandler{},
+		Username: api.NewUsernameAPIHandler(
+			userusecase.NewGetUsernameUseCase(usernameSvc),
+			userusecase.NewChangeUsernameUseCase(usernameSvc),
+			testLogger,
+		),
+	})
+	require.NoError(t, err)
+
+	return router, changes, audit
+}
+
+// session is the browser of one user, logged in unless anonymous
+type session struct {
+	*browsertest.Browser
+}
+
+func login(t *testing.T, router *gin.Engine, email string) *session {
+	s := &session{Browser: browsertest.New(router)}
+	if email != "" {
+		s.Login(t, email, password)
+	}
+	return s
+}
+
+func (s *session) rename(username string) *httptest.ResponseRecorder {
+	return s.API("PUT", "/api/v1/users/me/username", `{"username":"`+username+`"}`)
+}
+
+func (s *session) lookup(username string) *httptest.ResponseRecorder {
+	return s.API("GET", "/api/v1/user/lookup?username="+username, "")
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) v1api.Error {
+	var errorResp v1api.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResp))
+	return errorResp
+}
+
+func TestUsernameAPI_RenameKeepsHistory(t *testing.T) {
+	router, _, audit := setupUsernameRouter(t)
+	jane := login(t, router, "jane@example.com")
+
+	w := jane.rename("Jane.Doe")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var renamed v1api.Username
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &renamed))
+	assert.Equal(t, "jane.doe", renamed.Username)
+	require.Len(t, renamed.Changes, 1)
+	assert.Equal(t, "jane", renamed.Changes[0].OldUsername)
+	assert.Equal(t, "jane.doe", renamed.Changes[0].NewUsername)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), renamed.Changes[0].RedirectExpiresAt, time.Minute)
+
+	w = jane.API("GET", "/api/v1/users/me/username", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var current v1api.Username
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &current))
+	assert.Equal(t, renamed.Username, current.Username)
+	assert.Len(t, current.Changes, 1)
+
+	event := audit.Calls[0].Arguments.Get(1).(*entity.AuditEvent)
+	assert.Equal(t, entity.AuditUserNameChanged, event.Action)
+	assert.Equal(t, janeID, event.UserID)
+
+	// Renames are rate limited
+	w = jane.rename("janedoe")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+}
+
+func TestUsernameAPI_OldNameRedirects(t *testing.T) {
+	router, changes, _ := setupUsernameRouter(t)
+	jane := login(t, router, "jane@example.com")
+	bob := login(t, router, "bob@example.com")
+	require.Equal(t, http.StatusOK, jane.rename("jane.doe").Code)
+
+	w := bob.lookup("jane")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code, w.Body.String())
+	assert.Equal(t, "/api/v1/user/lookup?username=jane.doe", w.Header().Get("Location"))
+
+	w = bob.lookup("jane.doe")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var found v1api.UserLookupResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+	assert.Equal(t, "jane@example.com", found.Email)
+
+	// Nobody else may take the old name, or a lookalike, while it redirects
+	assert.Equal(t, http.StatusConflict, bob.rename("jane").Code)
+	assert.Equal(t, http.StatusConflict, bob.rename("JANE").Code)
+
+	changes.expire()
+	assert.Equal(t, http.StatusNotFound, bob.lookup("jane").Code)
+	assert.Equal(t, http.StatusOK, bob.rename("jane").Code)
+}
+
+func TestUsernameAPI_RefusesNames(t *testing.T) {
+	router, _, _ := setupUsernameRouter(t)
+	jane := login(t, router, "jane@example.com")
+
+	w := jane.rename("b0b")
+	assert.Equal(t, http.StatusConflict, w.Code, "lookalikes of other users' names are taken")
+
+	w = jane.rename("jane")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Username unchanged", decodeError(t, w).Error)
+
+	w = jane.rename("adm1n")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	errorResp := decodeError(t, w)
+	require.Len(t, errorResp.Details, 1)
+	assert.Equal(t, "username", errorResp.Details[0].Field)
+	assert.Equal(t, "is reserved", errorResp.Details[0].Message)
+
+	w = jane.rename("-jane..doe")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, decodeError(t, w).Details, 2)
+}
+
+func TestUsernameAPI_RequiresAuthentication(t *testing.T) {
+	router, _, _ := setupUsernameRouter(t)
+	anonymous := login(t, router, "")
+
+	assert.Equal(t, http.StatusUnauthorized, anonymous.API("GET", "/api/v1/users/me/username", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, anonymous.rename("jane.doe").Code)
+}
+
+func TestLoginAPI_UsernameIgnoresCase(t *testing.T) {
+	router, _, _ := setupUsernameRouter(t)
+
+	jane := login(t, router, "Jane")
+
+	w := jane.API("GET", "/api/v1/users/me/username", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestSignupAPI_Usernames(t *testing.T) {
+	router, _, _ := setupUsernameRouter(t)
+	anonymous := login(t, router, "")
+
+	w := anonymous.API("POST", "/api/v1/auth/signup", `{"email":"jane.doe@example.com","password":"password123","username":"JANE"}`)
+	assert.Equal(t, http.StatusConflict, w.Code, "usernames are unique regardless of case")
+
+	w = anonymous.API("POST", "/api/v1/auth/signup", `{"email":"jane.doe@example.com","password":"password123","username":"root"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
@@ -0,0 +1,230 @@
+package admin_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/internal/domain/service/admin"
+	"example.com/test/unit/mocks"
+)
+
+type fixture struct {
+	users         *mocks.MockUserRepository
+	refreshTokens *mocks.MockRefreshTokenRepository
+	apiTokens     *mocks.MockAPITokenRepository
+	oauthCodes    *mocks.MockOAuthAuthorizationCodeRepository
+	oauthTokens   *mocks.MockOAuthAccessTokenRepository
+	svc           admin.Service
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		users:         &mocks.MockUserRepository{},
+		refreshTokens: &mocks.MockRefreshTokenRepository{},
+		apiTokens:     &mocks.MockAPITokenRepository{},
+		oauthCodes:    &mocks.MockOAuthAuthorizationCodeRepository{},
+		oauthTokens:   &mocks.MockOAuthAccessTokenRepository{},
+	}
+	f.svc = admin.NewService(f.users, f.refreshTokens, f.apiTokens, f.oauthCodes, f.oauthTokens)
+	return f
+}
+
+// expectRevokeTokens expects every kind of token of userID to be revoked once
+func (f *fixture) expectRevokeTokens(ctx context.Context, userID string) {
+	f.refreshTokens.On("RevokeUser", ctx, userID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	f.apiTokens.On("RevokeUser", ctx, userID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	f.oauthCodes.On("RevokeUser", ctx, userID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	f.oauthTokens.On("RevokeUser", ctx, userID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+}
+
+func (f *fixture) assertTokensRevoked(t *testing.T) {
+	f.refreshTokens.AssertExpectations(t)
+	f.apiTokens.AssertExpectations(t)
+	f.oauthCodes.AssertExpectations(t)
+	f.oauthTokens.AssertExpectations(t)
+}
+
+func TestAdminService_List_Pages(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	users := []*entity.User{
+		{ID: "user-1", UserName: "ann", CreatedAt: created},
+		{ID: "user-2", UserName: "bob", CreatedAt: created.Add(time.Hour)},
+		{ID: "user-3", UserName: "cid", CreatedAt: created.Add(2 * time.Hour)},
+	}
+	query := repository.UserQuery{Search: "example", Sort: repository.UserSortUserName, Descending: true, Limit: 2}
+
+	f.users.On("List", ctx, mock.MatchedBy(func(q repository.UserQuery) bool { return q.After == nil })).Return(users, nil).Once()
+	page, err := f.svc.List(ctx, query, "")
+	require.NoError(t, err)
+	assert.Equal(t, users[:2], page.Users)
+	require.NotEmpty(t, page.Next)
+	listed := f.users.Calls[0].Arguments.Get(1).(repository.UserQuery)
+	assert.Equal(t, 3, listed.Limit, "one more user tells whether there is a next page")
+	assert.Equal(t, "example", listed.Search)
+
+	f.users.On("List", ctx, mock.MatchedBy(func(q repository.UserQuery) bool { return q.After != nil })).Return(users[2:], nil).Once()
+	page, err = f.svc.List(ctx, query, page.Next)
+	require.NoError(t, err)
+	assert.Equal(t, users[2:], page.Users)
+	assert.Empty(t, page.Next, "last page")
+	after := f.users.Calls[1].Arguments.Get(1).(repository.UserQuery).After
+	assert.Equal(t, "user-2", after.ID)
+	assert.Equal(t, "bob", after.UserName)
+}
+
+func TestAdminService_List_Defaults(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("List", ctx, repository.UserQuery{Sort: repository.UserSortCreatedAt, Limit: admin.DefaultPageSize + 1}).
+		Return([]*entity.User{}, nil)
+
+	page, err := f.svc.List(ctx, repository.UserQuery{Limit: admin.MaxPageSize + 1}, "")
+
+	require.NoError(t, err)
+	assert.Empty(t, page.Users)
+	f.users.AssertExpectations(t)
+}
+
+func TestAdminService_List_InvalidCursor(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	users := []*entity.User{{ID: "user-1"}, {ID: "user-2"}}
+	f.users.On("List", ctx, mock.Anything).Return(users, nil).Once()
+	page, err := f.svc.List(ctx, repository.UserQuery{Limit: 1}, "")
+	require.NoError(t, err)
+
+	_, err = f.svc.List(ctx, repository.UserQuery{Limit: 1, Sort: repository.UserSortEmail}, page.Next)
+	assert.ErrorIs(t, err, admin.ErrInvalidCursor, "cursor of another sort order")
+	_, err = f.svc.List(ctx, repository.UserQuery{Limit: 1}, "not a cursor")
+	assert.ErrorIs(t, err, admin.ErrInvalidCursor)
+}
+
+func TestAdminService_Lock(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	user := &entity.User{ID: "user-1"}
+	f.users.On("FindByID", ctx, "user-1").Return(user, nil)
+	f.users.On("Update", ctx, user).Return(nil).Once()
+	f.expectRevokeTokens(ctx, "user-1")
+
+	locked, err := f.svc.Lock(ctx, "admin-1", "user-1")
+
+	require.NoError(t, err)
+	assert.True(t, locked.Locked())
+	assert.Equal(t, locked.LockedAt, locked.SessionsRevokedAt, "sessions end")
+
+	_, err = f.svc.Lock(ctx, "admin-1", "user-1")
+	require.NoError(t, err, "locking a locked user changes nothing")
+	f.users.AssertExpectations(t)
+	f.assertTokensRevoked(t)
+
+	f.users.On("Update", ctx, user).Return(nil).Once()
+	unlocked, err := f.svc.Unlock(ctx, "user-1")
+	require.NoError(t, err)
+	assert.False(t, unlocked.Locked())
+}
+
+func TestAdminService_RequirePasswordReset(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	user := &entity.User{ID: "user-1"}
+	f.users.On("FindByID", ctx, "user-1").Return(user, nil)
+	f.users.On("Update", ctx, user).Return(nil)
+	f.expectRevokeTokens(ctx, "user-1")
+
+	got, err := f.svc.RequirePasswordReset(ctx, "user-1")
+
+	require.NoError(t, err)
+	assert.True(t, got.PasswordResetRequired)
+	assert.NotNil(t, got.SessionsRevokedAt)
+	f.assertTokensRevoked(t)
+}
+
+func TestAdminService_Delete(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1"}, nil)
+	f.users.On("Delete", ctx, "user-1").Return(nil)
+	f.expectRevokeTokens(ctx, "user-1")
+
+	require.NoError(t, f.svc.Delete(ctx, "admin-1", "user-1"))
+	f.users.AssertExpectations(t)
+	f.assertTokensRevoked(t)
+}
+
+func TestAdminService_Refusals(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := f.svc.Lock(ctx, "admin-1", "admin-1")
+	assert.ErrorIs(t, err, admin.ErrSelf)
+	assert.ErrorIs(t, f.svc.Delete(ctx, "admin-1", "admin-1"), admin.ErrSelf)
+
+	_, err = f.svc.Lock(ctx, "admin-1", "missing")
+	assert.ErrorIs(t, err, admin.ErrUserNotFound)
+	_, err = f.svc.Unlock(ctx, "missing")
+	assert.ErrorIs(t, err, admin.ErrUserNotFound)
+	assert.ErrorIs(t, f.svc.Delete(ctx, "admin-1", "missing"), admin.ErrUserNotFound)
+	f.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	f.users.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestAdminService_Restore(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	deleted := &entity.User{ID: "user-1", UserName: "jane", Email: "jane@example.com", DeletedAt: gorm.DeletedAt{Valid: true}}
+	f.users.On("FindDeleted", ctx, "user-1").Return(deleted, nil)
+	f.users.On("FindByUserName", ctx, "jane").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByEmail", ctx, "jane@example.com").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("Restore", ctx, "user-1").Return(nil)
+
+	restored, err := f.svc.Restore(ctx, "user-1")
+
+	require.NoError(t, err)
+	assert.False(t, restored.DeletedAt.Valid)
+	f.users.AssertExpectations(t)
+}
+
+func TestAdminService_Restore_Refusals(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	now := time.Now()
+	f.users.On("FindDeleted", ctx, "purged").Return(&entity.User{ID: "purged", PurgedAt: &now}, nil)
+	f.users.On("FindDeleted", ctx, "taken").Return(&entity.User{ID: "taken", UserName: "jane", Email: "jane@example.com"}, nil)
+	f.users.On("FindDeleted", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByUserName", ctx, "jane").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByEmail", ctx, "jane@example.com").Return(&entity.User{ID: "user-2"}, nil)
+
+	_, err := f.svc.Restore(ctx, "purged")
+	assert.ErrorIs(t, err, admin.ErrUserPurged)
+	_, err = f.svc.Restore(ctx, "taken")
+	assert.ErrorIs(t, err, admin.ErrUserTaken, "another user signed up with the email")
+	_, err = f.svc.Restore(ctx, "missing")
+	assert.ErrorIs(t, err, admin.ErrUserNotFound)
+	f.users.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
+func TestAdminService_Restore_TakenMeanwhile(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindDeleted", ctx, "user-1").Return(&entity.User{ID: "user-1", UserName: "jane", Email: "jane@example.com"}, nil)
+	f.users.On("FindByUserName", ctx, "jane").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByEmail", ctx, "jane@example.com").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("Restore", ctx, "user-1").Return(gorm.ErrDuplicatedKey)
+
+	_, err := f.svc.Restore(ctx, "user-1")
+
+	assert.ErrorIs(t, err, admin.ErrUserTaken, "another user signed up between the checks and the restore")
+}
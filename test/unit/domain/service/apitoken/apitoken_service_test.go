@@ -0,0 +1,140 @@
+package apitoken_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/apitoken"
+	"example.com/pkg/security"
+	"example.com/test/unit/mocks"
+)
+
+func TestAPITokenService_Issue_StoresHashOnly(t *testing.T) {
+	mockRepo := &mocks.MockAPITokenRepository{}
+	svc := apitoken.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.APIToken")).Return(nil)
+
+	token, plain, err := svc.Issue(ctx, "user-1", "ci", []string{entity.ScopeUsersRead, entity.ScopeUsersRead}, nil)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plain, entity.APITokenPrefix))
+	assert.Equal(t, security.HashToken(plain), token.TokenHash)
+	assert.NotContains(t, token.TokenHash, plain)
+	assert.Equal(t, plain[:len(token.Hint)], token.Hint)
+	assert.Equal(t, []string{entity.ScopeUsersRead}, token.Scopes)
+	assert.Equal(t, "user-1", token.UserID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPITokenService_Issue_RejectsInvalidRequests(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name      string
+		scopes    []string
+		expiresAt *time.Time
+		want      error
+	}{
+		{name: "no scopes", scopes: nil, want: apitoken.ErrNoScopes},
+		{name: "unknown scope", scopes: []string{"users:write"}, want: apitoken.ErrUnknownScope},
+		{name: "expiry in the past", scopes: []string{entity.ScopeUsersRead}, expiresAt: &past, want: apitoken.ErrExpiryInPast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MockAPITokenRepository{}
+			svc := apitoken.NewService(mockRepo)
+
+			_, _, err := svc.Issue(context.Background(), "user-1", "ci", tt.scopes, tt.expiresAt)
+
+			assert.ErrorIs(t, err, tt.want)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAPITokenService_Authenticate_Success(t *testing.T) {
+	mockRepo := &mocks.MockAPITokenRepository{}
+	svc := apitoken.NewService(mockRepo)
+	ctx := context.Background()
+
+	plain := entity.APITokenPrefix + "secret"
+	stored := &entity.APIToken{ID: "token-1", UserID: "user-1", Scopes: []string{entity.ScopeUsersRead}}
+	mockRepo.On("FindByHash", ctx, security.HashToken(plain)).Return(stored, nil)
+	mockRepo.On("UpdateLastUsed", ctx, "token-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	token, err := svc.Authenticate(ctx, plain)
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", token.UserID)
+	assert.NotNil(t, token.LastUsedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPITokenService_Authenticate_RecentlyUsedSkipsUpdate(t *testing.T) {
+	mockRepo := &mocks.MockAPITokenRepository{}
+	svc := apitoken.NewService(mockRepo)
+	ctx := context.Background()
+
+	plain := entity.APITokenPrefix + "secret"
+	lastUsed := time.Now().Add(-10 * time.Second)
+	mockRepo.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.APIToken{ID: "token-1", LastUsedAt: &lastUsed}, nil)
+
+	_, err := svc.Authenticate(ctx, plain)
+
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAPITokenService_Authenticate_Rejects(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		plain  string
+		stored *entity.APIToken
+	}{
+		{name: "wrong prefix", plain: "ghp_secret"},
+		{name: "unknown token", plain: entity.APITokenPrefix + "unknown"},
+		{name: "revoked", plain: entity.APITokenPrefix + "revoked", stored: &entity.APIToken{ID: "t", RevokedAt: &past}},
+		{name: "expired", plain: entity.APITokenPrefix + "expired", stored: &entity.APIToken{ID: "t", ExpiresAt: &past}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MockAPITokenRepository{}
+			svc := apitoken.NewService(mockRepo)
+			if tt.stored != nil {
+				mockRepo.On("FindByHash", mock.Anything, security.HashToken(tt.plain)).Return(tt.stored, nil)
+			} else {
+				mockRepo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+			}
+
+			_, err := svc.Authenticate(context.Background(), tt.plain)
+
+			assert.ErrorIs(t, err, apitoken.ErrInvalidToken)
+			mockRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAPITokenService_Revoke_NotFound(t *testing.T) {
+	mockRepo := &mocks.MockAPITokenRepository{}
+	svc := apitoken.NewService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("Revoke", ctx, "user-1", "token-1", mock.AnythingOfType("time.Time")).Return(gorm.ErrRecordNotFound)
+
+	err := svc.Revoke(ctx, "user-1", "token-1")
+
+	assert.ErrorIs(t, err, apitoken.ErrTokenNotFound)
+	mockRepo.AssertExpectations(t)
+}
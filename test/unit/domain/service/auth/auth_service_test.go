@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"example.com/internal/domain/entity"
 	authservice "example.com/internal/domain/service/auth"
@@ -127,6 +128,27 @@ func TestAuthService_AuthenticateUser_Success(t *testing.T) {
 	mockHasher.AssertExpectations(t)
 }
 
+func TestAuthService_AuthenticateUser_UserNameIgnoresCase(t *testing.T) {
+	mockRepo := &mocks.MockUserRepository{}
+	mockHasher := &mocks.MockPasswordHasher{}
+	authSvc := authservice.NewService(mockRepo, mockHasher)
+	ctx := context.Background()
+
+	existingUser := &entity.User{ID: "user-123", Email: "Jane@Example.com", UserName: "jane", PasswordHash: "hashed_password"}
+
+	// User names are looked up normalized, emails as given
+	mockRepo.On("FindByUserNameOrEmail", ctx, "jane").Return(existingUser, nil)
+	mockRepo.On("FindByUserNameOrEmail", ctx, "Jane@Example.com").Return(existingUser, nil)
+	mockHasher.On("Verify", "password123", "hashed_password").Return(true)
+
+	for _, identifier := range []string{"Jane", "JANE", "Jane@Example.com"} {
+		user, err := authSvc.AuthenticateUser(ctx, identifier, "password123")
+		require.NoError(t, err, identifier)
+		assert.Equal(t, existingUser, user)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
 func TestAuthService_AuthenticateUser_UserNotFound(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
@@ -173,6 +195,35 @@ func TestAuthService_AuthenticateUser_InvalidPassword(t *testing.T) {
 	mockHasher.AssertExpectations(t)
 }
 
+func TestAuthService_AuthenticateUser_AccountState(t *testing.T) {
+	lockedAt := time.Now()
+	tests := []struct {
+		name string
+		user entity.User
+		want error
+	}{
+		{name: "locked", user: entity.User{LockedAt: &lockedAt}, want: authservice.ErrAccountLocked},
+		{name: "password reset required", user: entity.User{PasswordResetRequired: true}, want: authservice.ErrPasswordResetRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MockUserRepository{}
+			mockHasher := &mocks.MockPasswordHasher{}
+			authSvc := authservice.NewService(mockRepo, mockHasher)
+			ctx := context.Background()
+
+			user := tt.user
+			user.ID, user.PasswordHash = "user-123", "hashed_password"
+			mockRepo.On("FindByUserNameOrEmail", ctx, "test@example.com").Return(&user, nil)
+			mockHasher.On("Verify", "password123", "hashed_password").Return(true)
+
+			_, err := authSvc.AuthenticateUser(ctx, "test@example.com", "password123")
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
 func TestAuthService_UpdateLastLogin_Success(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
@@ -198,3 +249,20 @@ func TestAuthService_UpdateLastLogin_Success(t *testing.T) {
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	mockRepo := &mocks.MockUserRepository{}
+	authSvc := authservice.NewService(mockRepo, &mocks.MockPasswordHasher{})
+	ctx := context.Background()
+
+	verifiedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("FindByID", ctx, "user-123").Return(&entity.User{ID: "user-123"}, nil).Once()
+	mockRepo.On("FindByID", ctx, "user-456").Return(&entity.User{ID: "user-456", EmailVerifiedAt: &verifiedAt}, nil).Once()
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(user *entity.User) bool {
+		return user.ID == "user-123" && user.EmailVerifiedAt != nil
+	})).Return(nil).Once()
+
+	require.NoError(t, authSvc.VerifyEmail(ctx, "user-123"))
+	require.NoError(t, authSvc.VerifyEmail(ctx, "user-456"), "the first verification is kept")
+	mockRepo.AssertExpectations(t)
+}
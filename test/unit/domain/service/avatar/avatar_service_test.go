@@ -0,0 +1,215 @@
+package avatar_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/avatar"
+	"example.com/internal/domain/service/profile"
+	"example.com/pkg/storage"
+	"example.com/test/unit/mocks"
+)
+
+const userID = "user-1"
+
+var (
+	red  = color.RGBA{R: 255, A: 255}
+	blue = color.RGBA{B: 255, A: 255}
+)
+
+func newService(
+	t *testing.T, existing *entity.UserProfile, cfg avatar.Config,
+) (avatar.Service, storage.BlobStore, *mocks.MockUserProfileRepository) {
+	t.Helper()
+
+	users := &mocks.MockUserRepository{}
+	users.On("FindByID", mock.Anything, userID).Return(&entity.User{ID: userID, Profile: existing}, nil)
+	profiles := &mocks.MockUserProfileRepository{}
+	profiles.On("Save", mock.Anything, mock.AnythingOfType("*entity.UserProfile")).Return(nil)
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	if cfg.MaxBytes == 0 {
+		cfg = avatar.Config{MaxBytes: 1 << 20, MaxPixels: 1 << 20}
+	}
+	return avatar.NewService(profile.NewService(users, profiles), profiles, store, cfg), store, profiles
+}
+
+// halves draws an image whose left half is red and right half the given color
+func halves(width, height int, right color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			if x < width/2 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, right)
+			}
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// jpegWithEXIF encodes img as a JPEG carrying an EXIF orientation and a comment in its EXIF
+// segment that must not survive the upload
+func jpegWithEXIF(t *testing.T, img image.Image, orientation uint16) []byte {
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = append(tiff, 0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	tiff = append(tiff, "GPS 52.5200 N 13.4050 E"...)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, encoded.Bytes()[2:]...)
+}
+
+func readThumbnail(t *testing.T, store storage.BlobStore, saved *entity.UserProfile, size int) ([]byte, image.Image) {
+	t.Helper()
+
+	blob, err := store.Get(context.Background(), saved.AvatarKey(size))
+	require.NoError(t, err)
+	defer blob.Body.Close()
+	data, err := io.ReadAll(blob.Body)
+	require.NoError(t, err)
+	img, _, err := image.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	return data, img
+}
+
+func TestService_UploadStoresThumbnails(t *testing.T) {
+	svc, store, profiles := newService(t, nil, avatar.Config{})
+
+	saved, err := svc.Upload(context.Background(), userID, bytes.NewReader(encodePNG(t, halves(400, 200, blue))))
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{32}\.jpg$`, saved.Avatar, "opaque images become JPEGs")
+	profiles.AssertCalled(t, "Save", mock.Anything, saved)
+	for _, size := range entity.AvatarSizes {
+		_, img := readThumbnail(t, store, saved, size)
+		assert.Equal(t, image.Rect(0, 0, size, size), img.Bounds())
+	}
+
+	// The center square of the image is kept, half red and half blue
+	_, large := readThumbnail(t, store, saved, entity.AvatarLarge)
+	r, _, b, _ := large.At(10, 128).RGBA()
+	assert.Greater(t, r, b)
+	r, _, b, _ = large.At(245, 128).RGBA()
+	assert.Greater(t, b, r)
+}
+
+func TestService_UploadKeepsTransparency(t *testing.T) {
+	svc, store, _ := newService(t, nil, avatar.Config{})
+
+	saved, err := svc.Upload(context.Background(), userID, bytes.NewReader(encodePNG(t, halves(100, 100, color.Transparent))))
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(saved.Avatar, ".png"))
+	_, img := readThumbnail(t, store, saved, entity.AvatarSmall)
+	_, _, _, a := img.At(60, 32).RGBA()
+	assert.Zero(t, a)
+}
+
+func TestService_UploadStripsEXIFAndAppliesOrientation(t *testing.T) {
+	svc, store, _ := newService(t, nil, avatar.Config{})
+
+	// Orientation 6 asks to turn the image right, which moves the red left half to the top
+	saved, err := svc.Upload(context.Background(), userID, bytes.NewReader(jpegWithEXIF(t, halves(100, 100, blue), 6)))
+
+	require.NoError(t, err)
+	data, img := readThumbnail(t, store, saved, entity.AvatarLarge)
+	assert.NotContains(t, string(data), "Exif")
+	assert.NotContains(t, string(data), "GPS")
+	r, _, b, _ := img.At(128, 10).RGBA()
+	assert.Greater(t, r, b, "top is red")
+	r, _, b, _ = img.At(128, 245).RGBA()
+	assert.Greater(t, b, r, "bottom is blue")
+}
+
+func TestService_UploadReplacesPreviousAvatar(t *testing.T) {
+	previous := &entity.UserProfile{UserID: userID, Avatar: "0123456789abcdef0123456789abcdef.jpg"}
+	svc, store, _ := newService(t, previous, avatar.Config{})
+	for _, size := range entity.AvatarSizes {
+		require.NoError(t, store.Put(context.Background(), previous.AvatarKey(size), []byte("old")))
+	}
+	oldKey := previous.AvatarKey(entity.AvatarLarge)
+
+	saved, err := svc.Upload(context.Background(), userID, bytes.NewReader(encodePNG(t, halves(64, 64, blue))))
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "0123456789abcdef0123456789abcdef.jpg", saved.Avatar)
+	_, err = store.Get(context.Background(), oldKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestService_UploadRejectsInvalidImages(t *testing.T) {
+	image := encodePNG(t, halves(300, 300, blue))
+
+	tests := map[string]struct {
+		data []byte
+		cfg  avatar.Config
+		want error
+	}{
+		"too many bytes":  {data: image, cfg: avatar.Config{MaxBytes: len(image) - 1, MaxPixels: 1 << 20}, want: avatar.ErrTooLarge},
+		"too many pixels": {data: image, cfg: avatar.Config{MaxBytes: 1 << 20, MaxPixels: 300*300 - 1}, want: avatar.ErrTooLarge},
+		"not an image":    {data: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"), want: avatar.ErrUnsupportedType},
+		"truncated":       {data: image[:100], want: avatar.ErrInvalidImage},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc, _, profiles := newService(t, nil, tt.cfg)
+
+			_, err := svc.Upload(context.Background(), userID, bytes.NewReader(tt.data))
+
+			assert.ErrorIs(t, err, tt.want)
+			profiles.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	previous := &entity.UserProfile{UserID: userID, Avatar: "0123456789abcdef0123456789abcdef.jpg"}
+	svc, store, _ := newService(t, previous, avatar.Config{})
+	key := previous.AvatarKey(entity.AvatarSmall)
+	require.NoError(t, store.Put(context.Background(), key, []byte("old")))
+
+	saved, err := svc.Delete(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Empty(t, saved.Avatar)
+	_, err = svc.Open(context.Background(), key)
+	assert.ErrorIs(t, err, avatar.ErrNotFound)
+}
+
+func TestService_OpenInvalidKey(t *testing.T) {
+	svc, _, _ := newService(t, nil, avatar.Config{})
+
+	_, err := svc.Open(context.Background(), "avatars/../../etc/passwd")
+
+	assert.ErrorIs(t, err, avatar.ErrNotFound)
+}
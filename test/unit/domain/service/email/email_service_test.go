@@ -0,0 +1,250 @@
+package email_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/email"
+	"example.com/pkg/mail"
+	"example.com/pkg/security"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	userID   = "user-1"
+	oldEmail = "jane@example.com"
+	newEmail = "jane@work.example.com"
+)
+
+var (
+	confirmPattern = regexp.MustCompile(`https://app\.example\.com/email\?email_change_token=(ecc_[A-Za-z0-9_-]+)`)
+	revertPattern  = regexp.MustCompile(`https://app\.example\.com/email\?email_revert_token=(ecr_[A-Za-z0-9_-]+)`)
+)
+
+type fixture struct {
+	users         *mocks.MockUserRepository
+	changes       *mocks.MockEmailChangeRepository
+	refreshTokens *mocks.MockRefreshTokenRepository
+	audit         *mocks.MockAuditEventRepository
+	hasher        *mocks.MockPasswordHasher
+	sender        *mocks.MockMailSender
+	svc           email.Service
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		users:         &mocks.MockUserRepository{},
+		changes:       &mocks.MockEmailChangeRepository{},
+		refreshTokens: &mocks.MockRefreshTokenRepository{},
+		audit:         &mocks.MockAuditEventRepository{},
+		hasher:        &mocks.MockPasswordHasher{},
+		sender:        &mocks.MockMailSender{},
+	}
+	f.users.On("FindByID", mock.Anything, userID).Return(&entity.User{ID: userID, Email: oldEmail, PasswordHash: "hash"}, nil)
+	f.hasher.On("Verify", "secret", "hash").Return(true)
+	f.hasher.On("Verify", mock.Anything, "hash").Return(false)
+	f.audit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditEvent")).Return(nil)
+	f.svc = email.NewService(f.users, f.changes, f.refreshTokens, f.audit, f.hasher, f.sender, email.Config{
+		LinkURL:    "https://app.example.com/email",
+		ConfirmTTL: 24 * time.Hour,
+		RevertTTL:  7 * 24 * time.Hour,
+	})
+	return f
+}
+
+// change stores a change of the user from oldEmail to newEmail and returns its tokens
+func (f *fixture) change(t *testing.T, confirmed bool) (*entity.EmailChange, string, string) {
+	confirmToken, err := security.GenerateToken(entity.EmailConfirmPrefix)
+	require.NoError(t, err)
+	revertToken, err := security.GenerateToken(entity.EmailRevertPrefix)
+	require.NoError(t, err)
+	change := &entity.EmailChange{
+		ID:              "change-1",
+		UserID:          userID,
+		OldEmail:        oldEmail,
+		NewEmail:        newEmail,
+		ExpiresAt:       time.Now().Add(time.Hour),
+		RevertExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+	if confirmed {
+		now := time.Now()
+		change.ConfirmedAt = &now
+	}
+	f.changes.On("FindByConfirmHash", mock.Anything, security.HashToken(confirmToken)).Return(change, nil)
+	f.changes.On("FindByRevertHash", mock.Anything, security.HashToken(revertToken)).Return(change, nil)
+	return change, confirmToken, revertToken
+}
+
+func TestEmailService_RequestChange(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindByEmail", ctx, newEmail).Return(nil, gorm.ErrRecordNotFound)
+	f.changes.On("RevertPending", ctx, userID, mock.Anything).Return(nil)
+	f.changes.On("Create", ctx, mock.AnythingOfType("*entity.EmailChange")).Return(nil)
+	var sent []mail.Message
+	f.sender.On("Send", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(1).(mail.Message))
+	}).Return(nil)
+
+	change, err := f.svc.RequestChange(ctx, userID, "secret", newEmail)
+
+	require.NoError(t, err)
+	assert.Equal(t, oldEmail, change.OldEmail)
+	assert.Equal(t, newEmail, change.NewEmail)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), change.ExpiresAt, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), change.RevertExpiresAt, time.Minute)
+	require.Len(t, sent, 2)
+
+	assert.Equal(t, newEmail, sent[0].To)
+	confirm := confirmPattern.FindStringSubmatch(sent[0].Body)
+	require.NotNil(t, confirm, sent[0].Body)
+	assert.Equal(t, security.HashToken(confirm[1]), change.ConfirmHash, "only the hash is stored")
+
+	assert.Equal(t, oldEmail, sent[1].To)
+	assert.Contains(t, sent[1].Body, newEmail)
+	revert := revertPattern.FindStringSubmatch(sent[1].Body)
+	require.NotNil(t, revert, sent[1].Body)
+	assert.Equal(t, security.HashToken(revert[1]), change.RevertHash)
+
+	f.changes.AssertCalled(t, "RevertPending", ctx, userID, mock.Anything)
+	f.audit.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e *entity.AuditEvent) bool {
+		return e.Action == entity.AuditEmailChangeRequested
+	}))
+}
+
+func TestEmailService_RequestChange_Refusals(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindByEmail", ctx, "taken@example.com").Return(&entity.User{ID: "user-2"}, nil)
+
+	_, err := f.svc.RequestChange(ctx, userID, "wrong", newEmail)
+	assert.ErrorIs(t, err, email.ErrIncorrectPassword)
+
+	_, err = f.svc.RequestChange(ctx, userID, "secret", oldEmail)
+	assert.ErrorIs(t, err, email.ErrSameEmail)
+
+	_, err = f.svc.RequestChange(ctx, userID, "secret", "taken@example.com")
+	assert.ErrorIs(t, err, email.ErrEmailTaken)
+
+	f.changes.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	f.sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestEmailService_ConfirmChange(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	change, token, _ := f.change(t, false)
+	f.users.On("FindByEmail", ctx, newEmail).Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("ChangeEmail", ctx, userID, oldEmail, newEmail, mock.Anything).Return(nil)
+	f.changes.On("Confirm", ctx, change.ID, mock.Anything).Return(nil)
+
+	user, err := f.svc.ConfirmChange(ctx, token)
+
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, user.Email)
+	assert.NotNil(t, user.EmailVerifiedAt, "the new email is verified")
+	f.changes.AssertCalled(t, "Confirm", ctx, change.ID, mock.Anything)
+}
+
+func TestEmailService_ConfirmChange_Races(t *testing.T) {
+	tests := []struct {
+		name        string
+		findByEmail []any
+		changeEmail error
+		expected    error
+	}{
+		{"taken before confirming", []any{&entity.User{ID: "user-2"}, nil}, nil, email.ErrEmailTaken},
+		{"taken while confirming", []any{nil, gorm.ErrRecordNotFound}, gorm.ErrDuplicatedKey, email.ErrEmailTaken},
+		{"email changed meanwhile", []any{nil, gorm.ErrRecordNotFound}, gorm.ErrRecordNotFound, email.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			ctx := context.Background()
+			_, token, _ := f.change(t, false)
+			f.users.On("FindByEmail", ctx, newEmail).Return(tt.findByEmail...)
+			f.users.On("ChangeEmail", ctx, userID, oldEmail, newEmail, mock.Anything).Return(tt.changeEmail)
+
+			_, err := f.svc.ConfirmChange(ctx, token)
+
+			assert.ErrorIs(t, err, tt.expected)
+			f.changes.AssertNotCalled(t, "Confirm", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEmailService_ConfirmChange_InvalidTokens(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	_, token, revertToken := f.change(t, true)
+	f.changes.On("FindByConfirmHash", ctx, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := f.svc.ConfirmChange(ctx, token)
+	assert.ErrorIs(t, err, email.ErrInvalidToken, "confirmed changes are not confirmed again")
+
+	_, err = f.svc.ConfirmChange(ctx, revertToken)
+	assert.ErrorIs(t, err, email.ErrInvalidToken, "revert tokens do not confirm")
+
+	f.users.AssertNotCalled(t, "ChangeEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailService_RevertChange(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	change, _, token := f.change(t, true)
+	f.users.ExpectedCalls = nil
+	f.users.On("FindByID", ctx, userID).Return(&entity.User{ID: userID, Email: newEmail}, nil)
+	f.users.On("FindByEmail", ctx, oldEmail).Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("ChangeEmail", ctx, userID, newEmail, oldEmail, mock.Anything).Return(nil)
+	f.users.On("Update", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+	f.changes.On("Revert", ctx, change.ID, mock.Anything).Return(nil)
+	f.changes.On("RevertPending", ctx, userID, mock.Anything).Return(nil)
+	f.refreshTokens.On("RevokeUser", ctx, userID, mock.Anything).Return(nil)
+
+	user, err := f.svc.RevertChange(ctx, token)
+
+	require.NoError(t, err)
+	assert.Equal(t, oldEmail, user.Email)
+	assert.NotNil(t, user.SessionsRevokedAt, "whoever made the change is logged out")
+	f.refreshTokens.AssertCalled(t, "RevokeUser", ctx, userID, mock.Anything)
+	f.audit.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e *entity.AuditEvent) bool {
+		return e.Action == entity.AuditEmailChangeReverted
+	}))
+}
+
+func TestEmailService_RevertChange_Pending(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	change, _, token := f.change(t, false)
+	f.users.On("Update", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+	f.changes.On("Revert", ctx, change.ID, mock.Anything).Return(nil)
+	f.changes.On("RevertPending", ctx, userID, mock.Anything).Return(nil)
+	f.refreshTokens.On("RevokeUser", ctx, userID, mock.Anything).Return(nil)
+
+	user, err := f.svc.RevertChange(ctx, token)
+
+	require.NoError(t, err)
+	assert.Equal(t, oldEmail, user.Email)
+	f.users.AssertNotCalled(t, "ChangeEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.changes.AssertCalled(t, "Revert", ctx, change.ID, mock.Anything)
+}
+
+func TestEmailService_RevertChange_Expired(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	change, _, token := f.change(t, true)
+	change.RevertExpiresAt = time.Now().Add(-time.Minute)
+
+	_, err := f.svc.RevertChange(ctx, token)
+
+	assert.ErrorIs(t, err, email.ErrInvalidToken)
+	f.changes.AssertNotCalled(t, "Revert", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,192 @@
+package identity_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	"example.com/internal/domain/service/identity"
+	"example.com/internal/domain/service/username"
+	"example.com/pkg/oidc"
+	"example.com/test/unit/mocks"
+)
+
+type fixture struct {
+	identities *mocks.MockUserIdentityRepository
+	users      *mocks.MockUserRepository
+	hasher     *mocks.MockPasswordHasher
+	svc        identity.Service
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		identities: &mocks.MockUserIdentityRepository{},
+		users:      &mocks.MockUserRepository{},
+		hasher:     &mocks.MockPasswordHasher{},
+	}
+	changes := &mocks.MockUserNameChangeRepository{}
+	changes.On("FindRedirect", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	usernames := username.NewService(f.users, changes, nil, username.Config{})
+	f.svc = identity.NewService(f.identities, f.users, authservice.NewService(f.users, f.hasher), usernames)
+	return f
+}
+
+func verifiedClaims() *oidc.Claims {
+	return &oidc.Claims{Subject: "sub-1", Email: "Jane.Doe@example.com", EmailVerified: true, PreferredUsername: "Jane Doe!"}
+}
+
+func TestIdentityService_SignIn_KnownIdentity(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	user := &entity.User{ID: "user-1"}
+
+	f.identities.On("FindByProviderSubject", ctx, "acme", "sub-1").Return(&entity.UserIdentity{ID: "id-1", UserID: "user-1"}, nil)
+	f.identities.On("UpdateLastLogin", ctx, "id-1", mock.AnythingOfType("time.Time")).Return(nil)
+	f.users.On("FindByID", ctx, "user-1").Return(user, nil)
+	f.users.On("Update", ctx, user).Return(nil)
+
+	got, err := f.svc.SignIn(ctx, "acme", verifiedClaims())
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", got.ID)
+	f.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	f.identities.AssertExpectations(t)
+}
+
+func TestIdentityService_SignIn_LockedUser(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	lockedAt := time.Now()
+
+	f.identities.On("FindByProviderSubject", ctx, "acme", "sub-1").Return(&entity.UserIdentity{ID: "id-1", UserID: "user-1"}, nil)
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1", LockedAt: &lockedAt}, nil)
+
+	_, err := f.svc.SignIn(ctx, "acme", verifiedClaims())
+
+	assert.ErrorIs(t, err, authservice.ErrAccountLocked)
+	f.identities.AssertNotCalled(t, "UpdateLastLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdentityService_SignIn_ProvisionsUser(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	claims := verifiedClaims()
+
+	f.identities.On("FindByProviderSubject", ctx, "acme", "sub-1").Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByEmail", ctx, claims.Email).Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByUserNameSkeleton", ctx, "janedoe").Return(&entity.User{ID: "someone-else"}, nil)
+	f.users.On("FindByUserNameSkeleton", ctx, mock.MatchedBy(func(name string) bool { return name != "janedoe" })).
+		Return(nil, gorm.ErrRecordNotFound)
+	f.hasher.On("Hash", mock.AnythingOfType("string")).Return("hash", nil)
+	f.users.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+	f.identities.On("Create", ctx, mock.AnythingOfType("*entity.UserIdentity")).Return(nil)
+	f.users.On("Update", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	user, err := f.svc.SignIn(ctx, "acme", claims)
+
+	require.NoError(t, err)
+	assert.Equal(t, claims.Email, user.Email)
+	assert.Regexp(t, `^janedoe_[0-9a-f]{4}$`, user.UserName)
+	assert.NotNil(t, user.EmailVerifiedAt, "the provider verified the email")
+
+	created := f.identities.Calls[len(f.identities.Calls)-1].Arguments.Get(1).(*entity.UserIdentity)
+	assert.Equal(t, user.ID, created.UserID)
+	assert.Equal(t, "sub-1", created.Subject)
+	assert.True(t, created.Provisioned)
+}
+
+func TestIdentityService_SignIn_RefusesUnsafeProvisioning(t *testing.T) {
+	t.Run("unverified email", func(t *testing.T) {
+		f := newFixture()
+		ctx := context.Background()
+		claims := verifiedClaims()
+		claims.EmailVerified = false
+
+		f.identities.On("FindByProviderSubject", ctx, "acme", "sub-1").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := f.svc.SignIn(ctx, "acme", claims)
+
+		assert.ErrorIs(t, err, identity.ErrEmailNotVerified)
+		f.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("email of an existing user", func(t *testing.T) {
+		f := newFixture()
+		ctx := context.Background()
+		claims := verifiedClaims()
+
+		f.identities.On("FindByProviderSubject", ctx, "acme", "sub-1").Return(nil, gorm.ErrRecordNotFound)
+		f.users.On("FindByEmail", ctx, claims.Email).Return(&entity.User{ID: "victim"}, nil)
+
+		_, err := f.svc.SignIn(ctx, "acme", claims)
+
+		assert.ErrorIs(t, err, identity.ErrEmailInUse)
+		f.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		f.identities.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestIdentityService_Link(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	f.identities.On("FindByProviderSubject", ctx, "acme", "sub-1").Return(nil, gorm.ErrRecordNotFound).Once()
+	f.identities.On("Create", ctx, mock.AnythingOfType("*entity.UserIdentity")).Return(nil)
+
+	linked, err := f.svc.Link(ctx, "user-1", "acme", verifiedClaims())
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", linked.UserID)
+	assert.False(t, linked.Provisioned)
+
+	f.identities.On("FindByProviderSubject", ctx, "acme", "sub-1").Return(linked, nil)
+
+	_, err = f.svc.Link(ctx, "user-2", "acme", verifiedClaims())
+
+	assert.ErrorIs(t, err, identity.ErrIdentityLinked)
+	f.identities.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestIdentityService_Unlink(t *testing.T) {
+	provisioned := &entity.UserIdentity{ID: "id-1", UserID: "user-1", Provisioned: true}
+	linked := &entity.UserIdentity{ID: "id-2", UserID: "user-1"}
+
+	tests := []struct {
+		name       string
+		identities []*entity.UserIdentity
+		target     string
+		want       error
+	}{
+		{name: "unknown identity", identities: []*entity.UserIdentity{linked}, target: "id-9", want: identity.ErrIdentityNotFound},
+		{name: "only identity of a provisioned user", identities: []*entity.UserIdentity{provisioned}, target: "id-1", want: identity.ErrLastIdentity},
+		{name: "provisioned identity with another one", identities: []*entity.UserIdentity{provisioned, linked}, target: "id-1"},
+		{name: "linked identity", identities: []*entity.UserIdentity{linked}, target: "id-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			ctx := context.Background()
+
+			f.identities.On("FindByUserID", ctx, "user-1").Return(tt.identities, nil)
+			f.identities.On("Delete", ctx, "user-1", tt.target).Return(nil)
+
+			err := f.svc.Unlink(ctx, "user-1", tt.target)
+
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				f.identities.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			f.identities.AssertCalled(t, "Delete", ctx, "user-1", tt.target)
+		})
+	}
+}
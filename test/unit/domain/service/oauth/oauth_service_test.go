@@ -0,0 +1,386 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	oauthservice "example.com/internal/domain/service/oauth"
+	"example.com/pkg/security"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	clientID    = "0b5a0e8e-5a3c-4a57-9d1e-1f5d7c0b2a11"
+	redirectURI = "https://partner.example.com/callback"
+	verifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+type fixture struct {
+	clients  *mocks.MockOAuthClientRepository
+	codes    *mocks.MockOAuthAuthorizationCodeRepository
+	consents *mocks.MockOAuthConsentRepository
+	tokens   *mocks.MockOAuthAccessTokenRepository
+	users    *mocks.MockUserRepository
+	issuer   *security.JWTIssuer
+	svc      oauthservice.Service
+}
+
+func newFixture(t *testing.T, withIssuer bool) *fixture {
+	f := &fixture{
+		clients:  &mocks.MockOAuthClientRepository{},
+		codes:    &mocks.MockOAuthAuthorizationCodeRepository{},
+		consents: &mocks.MockOAuthConsentRepository{},
+		tokens:   &mocks.MockOAuthAccessTokenRepository{},
+		users:    &mocks.MockUserRepository{},
+	}
+	if withIssuer {
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		f.issuer, err = security.NewJWTIssuer("https://auth.example.com", []security.SigningKey{
+			{ID: "ed-1", Algorithm: security.AlgEdDSA, Signer: private},
+		}, 15*time.Minute)
+		require.NoError(t, err)
+	}
+	f.svc = oauthservice.NewService(f.clients, f.codes, f.consents, f.tokens, f.users, f.issuer, oauthservice.Config{
+		CodeTTL:        time.Minute,
+		AccessTokenTTL: time.Hour,
+	})
+	return f
+}
+
+func partnerClient() *entity.OAuthClient {
+	return &entity.OAuthClient{
+		ID:           clientID,
+		Name:         "Partner",
+		SecretHash:   security.HashToken(entity.OAuthClientSecretPrefix + "secret"),
+		RedirectURIs: []string{redirectURI},
+		GrantTypes:   []string{entity.GrantTypeAuthorizationCode, entity.GrantTypeClientCredentials},
+		Scopes:       entity.OAuthScopes,
+	}
+}
+
+func challenge(verifier string) string {
+	digest := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+func authorizationRequest(scopes ...string) *oauthservice.AuthorizationRequest {
+	return &oauthservice.AuthorizationRequest{
+		ClientID:            clientID,
+		RedirectURI:         redirectURI,
+		ResponseType:        "code",
+		CodeChallenge:       challenge(verifier),
+		CodeChallengeMethod: "S256",
+		Nonce:               "nonce-1",
+		Scopes:              scopes,
+	}
+}
+
+func TestOAuthService_RegisterClient(t *testing.T) {
+	t.Run("confidential client gets a secret", func(t *testing.T) {
+		f := newFixture(t, false)
+		ctx := context.Background()
+		f.clients.On("Create", ctx, mock.AnythingOfType("*entity.OAuthClient")).Return(nil)
+
+		client, secret, err := f.svc.RegisterClient(ctx, "owner-1", "Partner", []string{redirectURI},
+			[]string{entity.GrantTypeClientCredentials, entity.GrantTypeAuthorizationCode}, []string{"email"}, false)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(secret, entity.OAuthClientSecretPrefix))
+		assert.Equal(t, security.HashToken(secret), client.SecretHash)
+		assert.Equal(t, []string{entity.GrantTypeAuthorizationCode, entity.GrantTypeClientCredentials}, client.GrantTypes)
+	})
+
+	t.Run("public client gets none", func(t *testing.T) {
+		f := newFixture(t, false)
+		ctx := context.Background()
+		f.clients.On("Create", ctx, mock.AnythingOfType("*entity.OAuthClient")).Return(nil)
+
+		client, secret, err := f.svc.RegisterClient(ctx, "owner-1", "SPA", []string{"http://localhost:3000/cb"},
+			[]string{entity.GrantTypeAuthorizationCode}, nil, true)
+
+		require.NoError(t, err)
+		assert.Empty(t, secret)
+		assert.False(t, client.Confidential())
+		assert.Equal(t, []string{}, client.Scopes)
+	})
+
+	invalid := []struct {
+		name         string
+		redirectURIs []string
+		grantTypes   []string
+		scopes       []string
+		public       bool
+	}{
+		{name: "public client credentials", grantTypes: []string{entity.GrantTypeClientCredentials}, public: true},
+		{name: "unknown grant", redirectURIs: []string{redirectURI}, grantTypes: []string{"password"}},
+		{name: "code grant without redirect URI", grantTypes: []string{entity.GrantTypeAuthorizationCode}},
+		{name: "plain http", redirectURIs: []string{"http://partner.example.com/cb"}, grantTypes: []string{entity.GrantTypeAuthorizationCode}},
+		{name: "fragment", redirectURIs: []string{redirectURI + "#x"}, grantTypes: []string{entity.GrantTypeAuthorizationCode}},
+		{name: "unknown scope", grantTypes: []string{entity.GrantTypeClientCredentials}, scopes: []string{"admin"}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, false)
+
+			_, _, err := f.svc.RegisterClient(context.Background(), "owner-1", "Bad", tt.redirectURIs, tt.grantTypes, tt.scopes, tt.public)
+
+			assert.ErrorIs(t, err, oauthservice.ErrInvalidClientMetadata)
+			f.clients.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOAuthService_Authorize(t *testing.T) {
+	f := newFixture(t, false)
+	ctx := context.Background()
+	f.clients.On("FindByID", ctx, clientID).Return(partnerClient(), nil)
+
+	client, err := f.svc.Authorize(ctx, authorizationRequest("email"))
+	require.NoError(t, err)
+	assert.Equal(t, clientID, client.ID)
+
+	// Errors about the client or redirect URI come without client: they must not be redirected
+	req := authorizationRequest("email")
+	req.RedirectURI = "https://attacker.example.com/callback"
+	client, err = f.svc.Authorize(ctx, req)
+	assert.ErrorIs(t, err, oauthservice.ErrInvalidRedirectURI)
+	assert.Nil(t, client)
+
+	req = authorizationRequest("email")
+	req.ClientID = "not-a-uuid"
+	_, err = f.svc.Authorize(ctx, req)
+	assert.ErrorIs(t, err, oauthservice.ErrClientNotFound)
+
+	req = authorizationRequest("email")
+	req.CodeChallengeMethod = "plain"
+	client, err = f.svc.Authorize(ctx, req)
+	assert.ErrorIs(t, err, oauthservice.ErrPKCERequired)
+	assert.NotNil(t, client)
+
+	// Without signing keys there are no ID tokens
+	_, err = f.svc.Authorize(ctx, authorizationRequest("openid"))
+	assert.ErrorIs(t, err, oauthservice.ErrInvalidScope)
+}
+
+func TestOAuthService_NeedsConsent(t *testing.T) {
+	f := newFixture(t, false)
+	ctx := context.Background()
+	client := partnerClient()
+
+	f.consents.On("Find", ctx, "user-1", clientID).Return(&entity.OAuthConsent{Scopes: []string{"email"}}, nil)
+
+	needed, err := f.svc.NeedsConsent(ctx, "user-1", client, []string{"email"})
+	require.NoError(t, err)
+	assert.False(t, needed)
+
+	needed, err = f.svc.NeedsConsent(ctx, "user-1", client, []string{"email", "profile"})
+	require.NoError(t, err)
+	assert.True(t, needed)
+}
+
+func TestOAuthService_ExchangeCode_IssuesIDToken(t *testing.T) {
+	f := newFixture(t, true)
+	ctx := context.Background()
+	client := partnerClient()
+	code := &entity.OAuthAuthorizationCode{
+		ID:            "code-1",
+		ClientID:      clientID,
+		UserID:        "user-1",
+		RedirectURI:   redirectURI,
+		CodeChallenge: challenge(verifier),
+		Nonce:         "nonce-1",
+		Scopes:        []string{"openid", "email"},
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+
+	f.codes.On("FindByHash", ctx, security.HashToken("plain-code")).Return(code, nil)
+	f.codes.On("MarkUsed", ctx, "code-1", mock.AnythingOfType("time.Time")).Return(nil)
+	f.tokens.On("Create", ctx, mock.AnythingOfType("*entity.OAuthAccessToken")).Return(nil)
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1", Email: "jane@example.com", UserName: "jane"}, nil)
+
+	tokens, err := f.svc.ExchangeCode(ctx, client, "plain-code", redirectURI, verifier)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(tokens.AccessToken, entity.OAuthAccessTokenPrefix))
+	stored := f.tokens.Calls[0].Arguments.Get(1).(*entity.OAuthAccessToken)
+	assert.Equal(t, "code-1", stored.CodeID)
+	assert.Equal(t, "user-1", stored.UserID)
+
+	parts := strings.Split(tokens.IDToken, ".")
+	require.Len(t, parts, 3)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	assert.Equal(t, "https://auth.example.com", claims["iss"])
+	assert.Equal(t, "user-1", claims["sub"])
+	assert.Equal(t, clientID, claims["aud"])
+	assert.Equal(t, "nonce-1", claims["nonce"])
+	assert.Equal(t, "jane@example.com", claims["email"])
+	assert.NotContains(t, claims, "preferred_username")
+
+	// An ID token is no access token for this API
+	_, err = f.issuer.Verify(tokens.IDToken)
+	assert.Error(t, err)
+}
+
+func TestOAuthService_ExchangeCode_Rejections(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(code *entity.OAuthAuthorizationCode)
+		redirectURI string
+		verifier    string
+	}{
+		{name: "wrong verifier", verifier: strings.Repeat("a", 43)},
+		{name: "short verifier", verifier: "short"},
+		{name: "other redirect URI", redirectURI: "https://partner.example.com/other"},
+		{name: "expired", modify: func(code *entity.OAuthAuthorizationCode) { code.ExpiresAt = time.Now().Add(-time.Second) }},
+		{name: "other client", modify: func(code *entity.OAuthAuthorizationCode) { code.ClientID = "someone-else" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, false)
+			ctx := context.Background()
+			code := &entity.OAuthAuthorizationCode{
+				ID:            "code-1",
+				ClientID:      clientID,
+				RedirectURI:   redirectURI,
+				CodeChallenge: challenge(verifier),
+				ExpiresAt:     time.Now().Add(time.Minute),
+			}
+			if tt.modify != nil {
+				tt.modify(code)
+			}
+			if tt.redirectURI == "" {
+				tt.redirectURI = redirectURI
+			}
+			if tt.verifier == "" {
+				tt.verifier = verifier
+			}
+			f.codes.On("FindByHash", ctx, mock.Anything).Return(code, nil)
+
+			_, err := f.svc.ExchangeCode(ctx, partnerClient(), "plain-code", tt.redirectURI, tt.verifier)
+
+			assert.ErrorIs(t, err, oauthservice.ErrInvalidGrant)
+			f.codes.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything, mock.Anything)
+			f.tokens.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOAuthService_ExchangeCode_ReplayRevokesTokens(t *testing.T) {
+	f := newFixture(t, false)
+	ctx := context.Background()
+	usedAt := time.Now().Add(-time.Second)
+	code := &entity.OAuthAuthorizationCode{
+		ID:            "code-1",
+		ClientID:      clientID,
+		RedirectURI:   redirectURI,
+		CodeChallenge: challenge(verifier),
+		ExpiresAt:     time.Now().Add(time.Minute),
+		UsedAt:        &usedAt,
+	}
+
+	f.codes.On("FindByHash", ctx, mock.Anything).Return(code, nil)
+	f.tokens.On("RevokeByCode", ctx, "code-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := f.svc.ExchangeCode(ctx, partnerClient(), "plain-code", redirectURI, verifier)
+
+	assert.ErrorIs(t, err, oauthservice.ErrInvalidGrant)
+	f.tokens.AssertExpectations(t)
+}
+
+func TestOAuthService_AuthenticateClient(t *testing.T) {
+	f := newFixture(t, false)
+	ctx := context.Background()
+	f.clients.On("FindByID", ctx, clientID).Return(partnerClient(), nil)
+
+	_, err := f.svc.AuthenticateClient(ctx, clientID, entity.OAuthClientSecretPrefix+"secret")
+	assert.NoError(t, err)
+
+	_, err = f.svc.AuthenticateClient(ctx, clientID, "")
+	assert.ErrorIs(t, err, oauthservice.ErrInvalidClient)
+
+	_, err = f.svc.AuthenticateClient(ctx, "unknown", "whatever")
+	assert.ErrorIs(t, err, oauthservice.ErrInvalidClient)
+}
+
+func TestOAuthService_Introspect(t *testing.T) {
+	f := newFixture(t, false)
+	ctx := context.Background()
+	plain := entity.OAuthAccessTokenPrefix + "token"
+	revokedAt := time.Now()
+
+	f.tokens.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.OAuthAccessToken{
+		ClientID: clientID, UserID: "user-1", Scopes: []string{"email"}, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil).Once()
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1"}, nil).Once()
+
+	introspection, err := f.svc.Introspect(ctx, partnerClient(), plain)
+	require.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, "user-1", introspection.UserID)
+
+	f.tokens.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.OAuthAccessToken{
+		ClientID: clientID, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt,
+	}, nil).Once()
+
+	introspection, err = f.svc.Introspect(ctx, partnerClient(), plain)
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+
+	f.tokens.On("FindByHash", ctx, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	introspection, err = f.svc.Introspect(ctx, partnerClient(), entity.OAuthAccessTokenPrefix+"unknown")
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+
+	public := partnerClient()
+	public.SecretHash = ""
+	_, err = f.svc.Introspect(ctx, public, plain)
+	assert.ErrorIs(t, err, oauthservice.ErrUnauthorizedClient)
+}
+
+func TestOAuthService_Introspect_InactiveForLockedAndDeletedUsers(t *testing.T) {
+	f := newFixture(t, false)
+	ctx := context.Background()
+	lockedAt := time.Now()
+	f.users.On("FindByID", ctx, "locked").Return(&entity.User{ID: "locked", LockedAt: &lockedAt}, nil)
+	f.users.On("FindByID", ctx, "deleted").Return(nil, gorm.ErrRecordNotFound)
+
+	for _, userID := range []string{"locked", "deleted"} {
+		plain := entity.OAuthAccessTokenPrefix + userID
+		f.tokens.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.OAuthAccessToken{
+			ClientID: clientID, UserID: userID, Scopes: []string{"email"}, ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+
+		introspection, err := f.svc.Introspect(ctx, partnerClient(), plain)
+		require.NoError(t, err, userID)
+		assert.False(t, introspection.Active, userID)
+	}
+}
+
+func TestOAuthService_Revoke_IgnoresTokensOfOtherClients(t *testing.T) {
+	f := newFixture(t, false)
+	ctx := context.Background()
+	plain := entity.OAuthAccessTokenPrefix + "token"
+
+	f.tokens.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.OAuthAccessToken{ID: "token-1", ClientID: "other"}, nil)
+
+	require.NoError(t, f.svc.Revoke(ctx, partnerClient(), plain))
+	f.tokens.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,304 @@
+package organization_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/organization"
+	"example.com/pkg/mail"
+	"example.com/pkg/security"
+	"example.com/test/unit/mocks"
+)
+
+const (
+	orgID   = "org-1"
+	ownerID = "owner-1"
+	adminID = "admin-1"
+	email   = "jane@example.com"
+)
+
+var linkPattern = regexp.MustCompile(`https://app\.example\.com/invite\?invitation_token=(inv_[A-Za-z0-9_-]+)`)
+
+type fixture struct {
+	organizations *mocks.MockOrganizationRepository
+	invitations   *mocks.MockInvitationRepository
+	users         *mocks.MockUserRepository
+	sender        *mocks.MockMailSender
+	svc           organization.Service
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		organizations: &mocks.MockOrganizationRepository{},
+		invitations:   &mocks.MockInvitationRepository{},
+		users:         &mocks.MockUserRepository{},
+		sender:        &mocks.MockMailSender{},
+	}
+	f.svc = organization.NewService(f.organizations, f.invitations, f.users, f.sender, organization.Config{
+		InviteURL: "https://app.example.com/invite",
+		TTL:       72 * time.Hour,
+	})
+	return f
+}
+
+// member makes userID a member of the organization with role
+func (f *fixture) member(userID, role string) {
+	f.organizations.On("FindMembership", mock.Anything, orgID, userID).
+		Return(&entity.Membership{OrganizationID: orgID, UserID: userID, Role: role}, nil)
+}
+
+// pending stores a pending invitation for email and returns its token
+func (f *fixture) pending(t *testing.T, email string) (*entity.Invitation, string) {
+	token, err := security.GenerateToken(entity.InvitationPrefix)
+	require.NoError(t, err)
+	invitation := &entity.Invitation{
+		ID:             "inv-1",
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           entity.MembershipMember,
+		Status:         entity.InvitationPending,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+	f.invitations.On("FindByTokenHash", mock.Anything, security.HashToken(token)).Return(invitation, nil)
+	return invitation, token
+}
+
+func TestOrganizationService_Create(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.organizations.On("Create", ctx, mock.AnythingOfType("*entity.Organization"), mock.AnythingOfType("*entity.Membership")).Return(nil)
+
+	owner, err := f.svc.Create(ctx, ownerID, "  Acme  ")
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.MembershipOwner, owner.Role)
+	assert.Equal(t, ownerID, owner.UserID)
+	assert.Equal(t, "Acme", owner.Organization.Name)
+	assert.Equal(t, owner.Organization.ID, owner.OrganizationID)
+}
+
+func TestOrganizationService_CreateInvalidName(t *testing.T) {
+	f := newFixture()
+
+	for _, name := range []string{"", "   ", "Acme\nInc"} {
+		_, err := f.svc.Create(context.Background(), ownerID, name)
+		assert.ErrorIs(t, err, organization.ErrInvalidName, name)
+	}
+	f.organizations.AssertNotCalled(t, "Create")
+}
+
+func TestOrganizationService_MembersHidesOtherOrganizations(t *testing.T) {
+	f := newFixture()
+	f.organizations.On("FindMembership", mock.Anything, orgID, "stranger").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := f.svc.Members(context.Background(), "stranger", orgID)
+
+	assert.ErrorIs(t, err, organization.ErrOrganizationNotFound)
+	f.organizations.AssertNotCalled(t, "ListMembers")
+}
+
+func TestOrganizationService_RemoveMember(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorRole string
+		userRole  string
+		self      bool
+		wantErr   error
+	}{
+		{name: "owner removes admin", actorRole: entity.MembershipOwner, userRole: entity.MembershipAdmin},
+		{name: "admin removes member", actorRole: entity.MembershipAdmin, userRole: entity.MembershipMember},
+		{name: "admin removes admin", actorRole: entity.MembershipAdmin, userRole: entity.MembershipAdmin, wantErr: organization.ErrNotAllowed},
+		{
+			name: "member removes member", actorRole: entity.MembershipMember, userRole: entity.MembershipMember,
+			wantErr: organization.ErrNotAllowed,
+		},
+		{name: "admin removes owner", actorRole: entity.MembershipAdmin, userRole: entity.MembershipOwner, wantErr: organization.ErrOwnerRemoval},
+		{name: "member leaves", actorRole: entity.MembershipMember, self: true},
+		{name: "owner leaves", actorRole: entity.MembershipOwner, self: true, wantErr: organization.ErrOwnerRemoval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			f.member("actor", tt.actorRole)
+			userID := "actor"
+			if !tt.self {
+				userID = "user"
+				f.member(userID, tt.userRole)
+			}
+			f.organizations.On("RemoveMember", mock.Anything, orgID, userID).Return(nil)
+
+			err := f.svc.RemoveMember(context.Background(), "actor", orgID, userID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				f.organizations.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			f.organizations.AssertCalled(t, "RemoveMember", mock.Anything, orgID, userID)
+		})
+	}
+}
+
+func TestOrganizationService_TransferOwnership(t *testing.T) {
+	f := newFixture()
+	f.member(ownerID, entity.MembershipOwner)
+	f.member(adminID, entity.MembershipAdmin)
+	f.organizations.On("TransferOwnership", mock.Anything, orgID, ownerID, adminID).Return(nil)
+
+	require.NoError(t, f.svc.TransferOwnership(context.Background(), ownerID, orgID, adminID))
+	f.organizations.AssertExpectations(t)
+}
+
+func TestOrganizationService_TransferOwnershipRequiresOwner(t *testing.T) {
+	f := newFixture()
+	f.member(adminID, entity.MembershipAdmin)
+
+	err := f.svc.TransferOwnership(context.Background(), adminID, orgID, adminID)
+
+	assert.ErrorIs(t, err, organization.ErrNotAllowed)
+}
+
+func TestOrganizationService_TransferOwnershipToNonMember(t *testing.T) {
+	f := newFixture()
+	f.member(ownerID, entity.MembershipOwner)
+	f.organizations.On("FindMembership", mock.Anything, orgID, "stranger").Return(nil, gorm.ErrRecordNotFound)
+
+	err := f.svc.TransferOwnership(context.Background(), ownerID, orgID, "stranger")
+
+	assert.ErrorIs(t, err, organization.ErrMemberNotFound)
+	f.organizations.AssertNotCalled(t, "TransferOwnership")
+}
+
+func TestOrganizationService_Invite(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.member(ownerID, entity.MembershipOwner)
+	f.users.On("FindByEmail", ctx, email).Return(nil, gorm.ErrRecordNotFound)
+	f.organizations.On("FindByID", ctx, orgID).Return(&entity.Organization{ID: orgID, Name: "Acme"}, nil)
+	f.invitations.On("RevokePending", ctx, orgID, email, mock.AnythingOfType("time.Time")).Return(nil)
+	f.invitations.On("Create", ctx, mock.AnythingOfType("*entity.Invitation")).Return(nil)
+	var sent mail.Message
+	f.sender.On("Send", ctx, mock.AnythingOfType("mail.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(mail.Message) }).
+		Return(nil)
+
+	invitation, err := f.svc.Invite(ctx, ownerID, orgID, email, entity.MembershipAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.MembershipAdmin, invitation.Role)
+	assert.Equal(t, entity.InvitationPending, invitation.Status)
+	assert.WithinDuration(t, time.Now().Add(72*time.Hour), invitation.ExpiresAt, time.Minute)
+	assert.Equal(t, email, sent.To)
+	assert.Equal(t, "You are invited to join Acme", sent.Subject)
+
+	match := linkPattern.FindStringSubmatch(sent.Body)
+	require.NotNil(t, match, sent.Body)
+	assert.Equal(t, security.HashToken(match[1]), invitation.TokenHash)
+}
+
+func TestOrganizationService_InviteRoles(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorRole string
+		role      string
+		wantErr   error
+	}{
+		{name: "admin invites admin", actorRole: entity.MembershipAdmin, role: entity.MembershipAdmin, wantErr: organization.ErrNotAllowed},
+		{name: "member invites member", actorRole: entity.MembershipMember, role: entity.MembershipMember, wantErr: organization.ErrNotAllowed},
+		{name: "owner invites owner", actorRole: entity.MembershipOwner, role: entity.MembershipOwner, wantErr: organization.ErrInvalidRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			f.member("actor", tt.actorRole)
+
+			_, err := f.svc.Invite(context.Background(), "actor", orgID, email, tt.role)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			f.sender.AssertNotCalled(t, "Send")
+		})
+	}
+}
+
+func TestOrganizationService_InviteMember(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.member(ownerID, entity.MembershipOwner)
+	f.member("user-1", entity.MembershipMember)
+	f.users.On("FindByEmail", ctx, email).Return(&entity.User{ID: "user-1", Email: email}, nil)
+
+	_, err := f.svc.Invite(ctx, ownerID, orgID, email, entity.MembershipMember)
+
+	assert.ErrorIs(t, err, organization.ErrAlreadyMember)
+	f.invitations.AssertNotCalled(t, "Create")
+}
+
+func TestOrganizationService_RevokeInvitationOfOtherOrganization(t *testing.T) {
+	f := newFixture()
+	f.member(ownerID, entity.MembershipOwner)
+	f.invitations.On("FindByID", mock.Anything, "inv-2").Return(&entity.Invitation{ID: "inv-2", OrganizationID: "org-2"}, nil)
+
+	err := f.svc.RevokeInvitation(context.Background(), ownerID, orgID, "inv-2")
+
+	assert.ErrorIs(t, err, organization.ErrInvitationNotFound)
+	f.invitations.AssertNotCalled(t, "Respond")
+}
+
+func TestOrganizationService_Accept(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	invitation, token := f.pending(t, email)
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1", Email: "Jane@Example.com"}, nil)
+	f.organizations.On("FindMembership", ctx, orgID, "user-1").Return(nil, gorm.ErrRecordNotFound)
+	f.organizations.On("FindByID", ctx, orgID).Return(&entity.Organization{ID: orgID, Name: "Acme"}, nil)
+	f.invitations.On("Accept", ctx, invitation.ID, mock.AnythingOfType("*entity.Membership"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	membership, err := f.svc.Accept(ctx, "user-1", token)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.MembershipMember, membership.Role)
+	assert.Equal(t, "Acme", membership.Organization.Name)
+}
+
+func TestOrganizationService_AcceptAddressedToOtherEmail(t *testing.T) {
+	f := newFixture()
+	_, token := f.pending(t, email)
+	f.users.On("FindByID", mock.Anything, "user-2").Return(&entity.User{ID: "user-2", Email: "john@example.com"}, nil)
+
+	_, err := f.svc.Accept(context.Background(), "user-2", token)
+
+	assert.ErrorIs(t, err, organization.ErrInvitationNotFound)
+	f.invitations.AssertNotCalled(t, "Accept")
+}
+
+func TestOrganizationService_AcceptExpired(t *testing.T) {
+	f := newFixture()
+	invitation, token := f.pending(t, email)
+	invitation.ExpiresAt = time.Now().Add(-time.Minute)
+
+	_, err := f.svc.Accept(context.Background(), "user-1", token)
+
+	assert.ErrorIs(t, err, organization.ErrInvitationNotFound)
+}
+
+func TestOrganizationService_Decline(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	invitation, token := f.pending(t, email)
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1", Email: email}, nil)
+	f.invitations.On("Respond", ctx, invitation.ID, entity.InvitationDeclined, mock.AnythingOfType("time.Time")).Return(nil)
+
+	require.NoError(t, f.svc.Decline(ctx, "user-1", token))
+	f.invitations.AssertExpectations(t)
+}
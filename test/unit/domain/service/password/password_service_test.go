@@ -0,0 +1,164 @@
+package password_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"example.com/internal/domain/entity"
+	passwordservice "example.com/internal/domain/service/password"
+	"example.com/pkg/security"
+	"example.com/test/unit/mocks"
+)
+
+type fixture struct {
+	users   *mocks.MockUserRepository
+	history *mocks.MockPasswordHistoryRepository
+	refresh *mocks.MockRefreshTokenRepository
+	pats    *mocks.MockAPITokenRepository
+	codes   *mocks.MockOAuthAuthorizationCodeRepository
+	grants  *mocks.MockOAuthAccessTokenRepository
+	hasher  *mocks.MockPasswordHasher
+	svc     passwordservice.Service
+	user    *entity.User
+	// saved is the user as passed to Update
+	saved entity.User
+}
+
+func newFixture(historySize int) *fixture {
+	f := &fixture{
+		users:   &mocks.MockUserRepository{},
+		history: &mocks.MockPasswordHistoryRepository{},
+		refresh: &mocks.MockRefreshTokenRepository{},
+		pats:    &mocks.MockAPITokenRepository{},
+		codes:   &mocks.MockOAuthAuthorizationCodeRepository{},
+		grants:  &mocks.MockOAuthAccessTokenRepository{},
+		hasher:  &mocks.MockPasswordHasher{},
+		user:    &entity.User{ID: "user-1", UserName: "janedoe", Email: "jane@example.com", PasswordHash: "hash-current"},
+	}
+	f.svc = passwordservice.NewService(f.users, f.history, f.refresh, f.pats, f.codes, f.grants, f.hasher, passwordservice.Config{
+		Policy:      security.NewPasswordPolicy(security.PasswordRules{MinLength: 8, MaxLength: 72, MinCharClasses: 1}),
+		HistorySize: historySize,
+	})
+	f.users.On("FindByID", mock.Anything, "user-1").Return(f.user, nil)
+	f.hasher.On("Verify", "current secret", "hash-current").Return(true).Maybe()
+	return f
+}
+
+// expectSave accepts the new password and captures the updated user
+func (f *fixture) expectSave() {
+	f.hasher.On("Hash", "brand new secret").Return("hash-new", nil)
+	f.users.On("Update", mock.Anything, f.user).
+		Run(func(args mock.Arguments) { f.saved = *args.Get(1).(*entity.User) }).
+		Return(nil)
+	for _, tokens := range []*mock.Mock{&f.refresh.Mock, &f.pats.Mock, &f.codes.Mock, &f.grants.Mock} {
+		tokens.On("RevokeUser", mock.Anything, "user-1", mock.AnythingOfType("time.Time")).Return(nil)
+	}
+}
+
+func TestPasswordService_Change(t *testing.T) {
+	f := newFixture(0)
+	f.hasher.On("Verify", "brand new secret", "hash-current").Return(false)
+	f.expectSave()
+
+	user, err := f.svc.Change(context.Background(), "user-1", "current secret", "brand new secret")
+
+	require.NoError(t, err)
+	assert.Equal(t, "hash-new", user.PasswordHash)
+	assert.Equal(t, "hash-new", f.saved.PasswordHash)
+	require.NotNil(t, f.saved.SessionsRevokedAt)
+	assert.Equal(t, f.saved.SessionsRevokedAt, f.saved.PasswordChangedAt)
+	for _, tokens := range []*mock.Mock{&f.refresh.Mock, &f.pats.Mock, &f.codes.Mock, &f.grants.Mock} {
+		tokens.AssertExpectations(t)
+	}
+	f.history.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPasswordService_ChangeKeepsHistory(t *testing.T) {
+	f := newFixture(3)
+	f.hasher.On("Verify", "brand new secret", "hash-current").Return(false)
+	f.hasher.On("Verify", "brand new secret", "hash-old").Return(false)
+	f.history.On("ListRecent", mock.Anything, "user-1", 3).Return([]*entity.PasswordHistory{{PasswordHash: "hash-old"}}, nil)
+	f.history.On("Create", mock.Anything, mock.MatchedBy(func(entry *entity.PasswordHistory) bool {
+		return entry.UserID == "user-1" && entry.PasswordHash == "hash-current"
+	})).Return(nil)
+	f.history.On("Prune", mock.Anything, "user-1", 3).Return(nil)
+	f.expectSave()
+
+	_, err := f.svc.Change(context.Background(), "user-1", "current secret", "brand new secret")
+
+	require.NoError(t, err)
+	f.history.AssertExpectations(t)
+}
+
+func TestPasswordService_ChangeRejections(t *testing.T) {
+	t.Run("incorrect current password", func(t *testing.T) {
+		f := newFixture(0)
+		f.hasher.On("Verify", "guess", "hash-current").Return(false)
+
+		_, err := f.svc.Change(context.Background(), "user-1", "guess", "brand new secret")
+
+		assert.ErrorIs(t, err, passwordservice.ErrIncorrectPassword)
+		f.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("weak password", func(t *testing.T) {
+		f := newFixture(0)
+
+		_, err := f.svc.Change(context.Background(), "user-1", "current secret", "janedoe-1234")
+
+		assert.ErrorIs(t, err, security.ErrWeakPassword)
+		f.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("current password", func(t *testing.T) {
+		f := newFixture(0)
+
+		_, err := f.svc.Change(context.Background(), "user-1", "current secret", "current secret")
+
+		assert.ErrorIs(t, err, passwordservice.ErrPasswordReused)
+	})
+
+	t.Run("password in history", func(t *testing.T) {
+		f := newFixture(3)
+		f.hasher.On("Verify", "brand new secret", "hash-current").Return(false)
+		f.hasher.On("Verify", "brand new secret", "hash-old").Return(true)
+		f.history.On("ListRecent", mock.Anything, "user-1", 3).Return([]*entity.PasswordHistory{{PasswordHash: "hash-old"}}, nil)
+
+		_, err := f.svc.Change(context.Background(), "user-1", "current secret", "brand new secret")
+
+		assert.ErrorIs(t, err, passwordservice.ErrPasswordReused)
+		f.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPasswordService_CheckWithoutPolicy(t *testing.T) {
+	svc := passwordservice.NewService(nil, nil, nil, nil, nil, nil, nil, passwordservice.Config{})
+
+	assert.NoError(t, svc.Check(context.Background(), "x", "janedoe", "jane@example.com"))
+}
+
+// breachedPasswords is a security.PasswordBreachChecker knowing a fixed set of passwords
+type breachedPasswords map[string]bool
+
+func (b breachedPasswords) Breached(_ context.Context, password string) (bool, error) {
+	return b[password], nil
+}
+
+func TestPasswordService_CheckBreached(t *testing.T) {
+	svc := passwordservice.NewService(nil, nil, nil, nil, nil, nil, nil, passwordservice.Config{
+		Policy:        security.NewPasswordPolicy(security.PasswordRules{MinLength: 8, MaxLength: 72, MinCharClasses: 1}),
+		BreachChecker: breachedPasswords{"leaked secret": true},
+	})
+
+	err := svc.Check(context.Background(), "leaked secret", "janedoe", "jane@example.com")
+	assert.ErrorIs(t, err, security.ErrWeakPassword)
+	var policyErr *security.PasswordPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, []string{"appears in a known data breach"}, policyErr.Violations)
+
+	assert.NoError(t, svc.Check(context.Background(), "unknown secret", "janedoe", "jane@example.com"))
+}
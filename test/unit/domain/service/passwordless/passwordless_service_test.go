@@ -0,0 +1,281 @@
+package passwordless_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	authservice "example.com/internal/domain/service/auth"
+	"example.com/internal/domain/service/passwordless"
+	"example.com/pkg/mail"
+	"example.com/test/unit/mocks"
+)
+
+const email = "jane@example.com"
+
+var (
+	codePattern = regexp.MustCompile(`code is (\d{6})\n`)
+	linkPattern = regexp.MustCompile(`https://app\.example\.com/login\?passwordless_token=(mlt_[A-Za-z0-9_-]+)`)
+)
+
+type fixture struct {
+	challenges *mocks.MockLoginChallengeRepository
+	users      *mocks.MockUserRepository
+	sender     *mocks.MockMailSender
+	svc        passwordless.Service
+	// created and sent capture the challenge and email of the last Start
+	created *entity.LoginChallenge
+	sent    mail.Message
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		challenges: &mocks.MockLoginChallengeRepository{},
+		users:      &mocks.MockUserRepository{},
+		sender:     &mocks.MockMailSender{},
+	}
+	f.svc = passwordless.NewService(f.challenges, f.users, authservice.NewService(f.users, &mocks.MockPasswordHasher{}), f.sender,
+		passwordless.Config{
+			LinkURL:     "https://app.example.com/login",
+			TTL:         15 * time.Minute,
+			MaxAttempts: 3,
+			MaxPerHour:  5,
+		})
+	return f
+}
+
+// start runs a successful Start for the user and captures the stored challenge and the email
+func (f *fixture) start(t *testing.T, method string) *passwordless.Binding {
+	ctx := context.Background()
+	f.users.On("FindByEmail", ctx, email).Return(&entity.User{ID: "user-1", Email: email}, nil).Once()
+	f.challenges.On("CountSince", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+	f.challenges.On("MarkPendingUsed", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	f.challenges.On("Create", ctx, mock.AnythingOfType("*entity.LoginChallenge")).
+		Run(func(args mock.Arguments) { f.created = args.Get(1).(*entity.LoginChallenge) }).
+		Return(nil).Once()
+	f.sender.On("Send", ctx, mock.AnythingOfType("mail.Message")).
+		Run(func(args mock.Arguments) { f.sent = args.Get(1).(mail.Message) }).
+		Return(nil).Once()
+
+	binding, err := f.svc.Start(ctx, email, method)
+	require.NoError(t, err)
+	return binding
+}
+
+func TestPasswordlessService_StartCode(t *testing.T) {
+	f := newFixture()
+
+	binding := f.start(t, entity.LoginMethodCode)
+
+	assert.Equal(t, binding.ChallengeID, f.created.ID)
+	assert.Equal(t, entity.LoginMethodCode, f.created.Method)
+	assert.NotEmpty(t, binding.DeviceSecret)
+	assert.NotContains(t, f.created.DeviceHash, binding.DeviceSecret)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), f.created.ExpiresAt, time.Minute)
+
+	assert.Equal(t, email, f.sent.To)
+	match := codePattern.FindStringSubmatch(f.sent.Body)
+	require.Len(t, match, 2, f.sent.Body)
+	assert.NotContains(t, f.created.SecretHash, match[1])
+	assert.NotContains(t, f.sent.Subject, match[1], "codes must not show on lock screens")
+}
+
+func TestPasswordlessService_StartLink(t *testing.T) {
+	f := newFixture()
+
+	f.start(t, entity.LoginMethodLink)
+
+	assert.Equal(t, entity.LoginMethodLink, f.created.Method)
+	assert.Regexp(t, linkPattern, f.sent.Body)
+}
+
+func TestPasswordlessService_StartUnknownEmail(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindByEmail", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	binding, err := f.svc.Start(ctx, "nobody@example.com", entity.LoginMethodCode)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, binding.ChallengeID)
+	f.challenges.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	f.sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestPasswordlessService_StartOverHourlyLimit(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindByEmail", ctx, email).Return(&entity.User{ID: "user-1", Email: email}, nil)
+	f.challenges.On("CountSince", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(int64(5), nil)
+
+	binding, err := f.svc.Start(ctx, email, entity.LoginMethodLink)
+
+	require.NoError(t, err)
+	assert.NotNil(t, binding)
+	f.sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestPasswordlessService_StartInvalidMethod(t *testing.T) {
+	f := newFixture()
+
+	_, err := f.svc.Start(context.Background(), email, "sms")
+
+	assert.ErrorIs(t, err, passwordless.ErrInvalidMethod)
+}
+
+func TestPasswordlessService_StartSendFailure(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.users.On("FindByEmail", ctx, email).Return(&entity.User{ID: "user-1", Email: email}, nil)
+	f.challenges.On("CountSince", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	f.challenges.On("MarkPendingUsed", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(nil)
+	f.challenges.On("Create", ctx, mock.AnythingOfType("*entity.LoginChallenge")).Return(nil)
+	f.sender.On("Send", ctx, mock.AnythingOfType("mail.Message")).Return(errors.New("connection refused"))
+
+	_, err := f.svc.Start(ctx, email, entity.LoginMethodCode)
+
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestPasswordlessService_VerifyCode(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	binding := f.start(t, entity.LoginMethodCode)
+	code := codePattern.FindStringSubmatch(f.sent.Body)[1]
+
+	user := &entity.User{ID: "user-1", Email: email}
+	f.challenges.On("FindByID", ctx, binding.ChallengeID).Return(f.created, nil)
+	f.challenges.On("MarkUsed", ctx, binding.ChallengeID, mock.AnythingOfType("time.Time")).Return(nil)
+	f.users.On("FindByID", ctx, "user-1").Return(user, nil)
+	f.users.On("Update", ctx, user).Return(nil)
+
+	got, err := f.svc.Verify(ctx, *binding, " "+code+" ")
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", got.ID)
+	assert.NotNil(t, got.LastLoginAt)
+	assert.NotNil(t, got.EmailVerifiedAt, "the code reached the user by email")
+}
+
+func TestPasswordlessService_VerifyLockedUser(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	binding := f.start(t, entity.LoginMethodCode)
+	code := codePattern.FindStringSubmatch(f.sent.Body)[1]
+
+	lockedAt := time.Now()
+	f.challenges.On("FindByID", ctx, binding.ChallengeID).Return(f.created, nil)
+	f.challenges.On("MarkUsed", ctx, binding.ChallengeID, mock.AnythingOfType("time.Time")).Return(nil)
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1", LockedAt: &lockedAt}, nil)
+
+	_, err := f.svc.Verify(ctx, *binding, code)
+
+	assert.ErrorIs(t, err, authservice.ErrAccountLocked)
+	f.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPasswordlessService_VerifyLink(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	binding := f.start(t, entity.LoginMethodLink)
+	token := linkPattern.FindStringSubmatch(f.sent.Body)[1]
+
+	f.challenges.On("FindByID", ctx, binding.ChallengeID).Return(f.created, nil)
+	f.challenges.On("MarkUsed", ctx, binding.ChallengeID, mock.AnythingOfType("time.Time")).Return(nil)
+	f.users.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1"}, nil)
+	f.users.On("Update", ctx, mock.Anything).Return(nil)
+
+	got, err := f.svc.Verify(ctx, *binding, token)
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", got.ID)
+}
+
+func TestPasswordlessService_VerifyRejections(t *testing.T) {
+	t.Run("another device", func(t *testing.T) {
+		f := newFixture()
+		ctx := context.Background()
+		binding := f.start(t, entity.LoginMethodCode)
+		f.challenges.On("FindByID", ctx, binding.ChallengeID).Return(f.created, nil)
+
+		stolen := passwordless.Binding{ChallengeID: binding.ChallengeID, DeviceSecret: "attacker"}
+		_, err := f.svc.Verify(ctx, stolen, codePattern.FindStringSubmatch(f.sent.Body)[1])
+
+		assert.ErrorIs(t, err, passwordless.ErrNoPendingLogin)
+		f.challenges.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		f := newFixture()
+		ctx := context.Background()
+		binding := f.start(t, entity.LoginMethodCode)
+		f.created.ExpiresAt = time.Now().Add(-time.Second)
+		f.challenges.On("FindByID", ctx, binding.ChallengeID).Return(f.created, nil)
+
+		_, err := f.svc.Verify(ctx, *binding, codePattern.FindStringSubmatch(f.sent.Body)[1])
+
+		assert.ErrorIs(t, err, passwordless.ErrNoPendingLogin)
+	})
+
+	t.Run("already used", func(t *testing.T) {
+		f := newFixture()
+		ctx := context.Background()
+		binding := f.start(t, entity.LoginMethodCode)
+		used := time.Now()
+		f.created.UsedAt = &used
+		f.challenges.On("FindByID", ctx, binding.ChallengeID).Return(f.created, nil)
+
+		_, err := f.svc.Verify(ctx, *binding, codePattern.FindStringSubmatch(f.sent.Body)[1])
+
+		assert.ErrorIs(t, err, passwordless.ErrNoPendingLogin)
+	})
+
+	t.Run("unknown challenge", func(t *testing.T) {
+		f := newFixture()
+		ctx := context.Background()
+		f.challenges.On("FindByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := f.svc.Verify(ctx, passwordless.Binding{ChallengeID: "missing", DeviceSecret: "device"}, "123456")
+
+		assert.ErrorIs(t, err, passwordless.ErrNoPendingLogin)
+	})
+}
+
+func TestPasswordlessService_VerifyAttemptLimit(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	binding := f.start(t, entity.LoginMethodCode)
+	code := codePattern.FindStringSubmatch(f.sent.Body)[1]
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	// Each lookup sees the attempts recorded so far
+	for attempts := range 4 {
+		loaded := *f.created
+		loaded.Attempts = attempts
+		f.challenges.On("FindByID", ctx, binding.ChallengeID).Return(&loaded, nil).Once()
+	}
+	f.challenges.On("RecordFailedAttempt", ctx, binding.ChallengeID, 3).Return(nil)
+
+	_, err := f.svc.Verify(ctx, *binding, wrong)
+	assert.ErrorIs(t, err, passwordless.ErrIncorrectSecret)
+	_, err = f.svc.Verify(ctx, *binding, wrong)
+	assert.ErrorIs(t, err, passwordless.ErrIncorrectSecret)
+	_, err = f.svc.Verify(ctx, *binding, wrong)
+	assert.ErrorIs(t, err, passwordless.ErrTooManyAttempts, "the last attempt voids the challenge")
+
+	_, err = f.svc.Verify(ctx, *binding, code)
+	assert.ErrorIs(t, err, passwordless.ErrTooManyAttempts, "the right code no longer helps")
+	f.challenges.AssertNumberOfCalls(t, "RecordFailedAttempt", 3)
+	f.challenges.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,122 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/policy"
+	"example.com/pkg/authz"
+	"example.com/test/unit/mocks"
+)
+
+func TestPolicyService_Can(t *testing.T) {
+	roleRepo := &mocks.MockRoleRepository{}
+	svc := policy.NewService(roleRepo, &mocks.MockUserRepository{}, &mocks.MockOrganizationRepository{})
+	ctx := context.Background()
+
+	roleRepo.On("UserHasPermission", ctx, "user-1", entity.PermissionUsersRead).Return(true, nil)
+	roleRepo.On("UserHasPermission", ctx, "user-1", entity.PermissionRolesAssign).Return(false, nil)
+
+	allowed, err := svc.Can(ctx, "user-1", entity.PermissionUsersRead)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = svc.Can(ctx, "user-1", entity.PermissionRolesAssign)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = svc.Can(ctx, "", entity.PermissionUsersRead)
+	require.NoError(t, err)
+	assert.False(t, allowed, "anonymous requests hold no permission")
+}
+
+func TestPolicyService_Assign(t *testing.T) {
+	roleRepo := &mocks.MockRoleRepository{}
+	userRepo := &mocks.MockUserRepository{}
+	svc := policy.NewService(roleRepo, userRepo, &mocks.MockOrganizationRepository{})
+	ctx := context.Background()
+
+	userRepo.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1"}, nil)
+	userRepo.On("FindByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+	roleRepo.On("FindByName", ctx, "support").Return(&entity.Role{ID: "role-1", Name: "support"}, nil)
+	roleRepo.On("FindByName", ctx, "unknown").Return(nil, gorm.ErrRecordNotFound)
+	roleRepo.On("Assign", ctx, "user-1", "role-1").Return(nil)
+
+	require.NoError(t, svc.Assign(ctx, "user-1", "support"))
+	assert.ErrorIs(t, svc.Assign(ctx, "missing", "support"), policy.ErrUserNotFound)
+	assert.ErrorIs(t, svc.Assign(ctx, "user-1", "unknown"), policy.ErrRoleNotFound)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestPolicyService_Unassign(t *testing.T) {
+	roleRepo := &mocks.MockRoleRepository{}
+	userRepo := &mocks.MockUserRepository{}
+	svc := policy.NewService(roleRepo, userRepo, &mocks.MockOrganizationRepository{})
+	ctx := context.Background()
+
+	userRepo.On("FindByID", ctx, "user-1").Return(&entity.User{ID: "user-1"}, nil)
+	// Users of other tenants are not found in the tenant of the context
+	userRepo.On("FindByID", ctx, "other-tenant-user").Return(nil, gorm.ErrRecordNotFound)
+	roleRepo.On("FindByName", ctx, "support").Return(&entity.Role{ID: "role-1", Name: "support"}, nil)
+	roleRepo.On("Unassign", ctx, "user-1", "role-1").Return(nil).Once()
+	roleRepo.On("Unassign", ctx, "user-1", "role-1").Return(gorm.ErrRecordNotFound)
+
+	require.NoError(t, svc.Unassign(ctx, "user-1", "support"))
+	assert.ErrorIs(t, svc.Unassign(ctx, "user-1", "support"), policy.ErrRoleNotAssigned)
+	assert.ErrorIs(t, svc.Unassign(ctx, "other-tenant-user", "support"), policy.ErrUserNotFound)
+	roleRepo.AssertNotCalled(t, "Unassign", ctx, "other-tenant-user", "role-1")
+}
+
+func TestPolicyService_SubjectAttributes(t *testing.T) {
+	roleRepo := &mocks.MockRoleRepository{}
+	organizationRepo := &mocks.MockOrganizationRepository{}
+	svc := policy.NewService(roleRepo, &mocks.MockUserRepository{}, organizationRepo)
+	ctx := context.Background()
+
+	roleRepo.On("FindByUserID", ctx, "user-1").Return([]*entity.Role{
+		{Name: "support", Permissions: []entity.Permission{{Name: entity.PermissionUsersRead}, {Name: entity.PermissionRolesRead}}},
+		{Name: "auditor", Permissions: []entity.Permission{{Name: entity.PermissionUsersRead}}},
+	}, nil)
+	organizationRepo.On("ListByUserID", ctx, "user-1").Return([]*entity.Membership{
+		{OrganizationID: "org-1", Role: entity.MembershipOwner},
+		{OrganizationID: "org-2", Role: entity.MembershipMember},
+		{OrganizationID: "org-3", Role: entity.MembershipAdmin},
+	}, nil)
+
+	attributes, err := svc.SubjectAttributes(ctx, authz.Entity{Type: "user", ID: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"roles":                 []string{"support", "auditor"},
+		"permissions":           []string{entity.PermissionRolesRead, entity.PermissionUsersRead},
+		"organizations":         []string{"org-1", "org-2", "org-3"},
+		"managed_organizations": []string{"org-1", "org-3"},
+	}, attributes)
+
+	attributes, err = svc.SubjectAttributes(ctx, authz.Entity{Type: "user"})
+	require.NoError(t, err)
+	assert.Empty(t, attributes, "anonymous subjects have no attributes")
+}
+
+func TestPolicyService_ResourceAttributes(t *testing.T) {
+	organizationRepo := &mocks.MockOrganizationRepository{}
+	svc := policy.NewService(&mocks.MockRoleRepository{}, &mocks.MockUserRepository{}, organizationRepo)
+	ctx := context.Background()
+
+	organizationRepo.On("ListByUserID", ctx, "user-1").Return([]*entity.Membership{
+		{OrganizationID: "org-1", Role: entity.MembershipMember},
+	}, nil)
+
+	attributes, err := svc.ResourceAttributes(ctx, authz.Entity{Type: "user", ID: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"organizations": []string{"org-1"}}, attributes)
+
+	attributes, err = svc.ResourceAttributes(ctx, authz.Entity{Type: "document", ID: "doc-1"})
+	require.NoError(t, err)
+	assert.Empty(t, attributes, "only users belong to organizations")
+	organizationRepo.AssertNumberOfCalls(t, "ListByUserID", 1)
+}
@@ -0,0 +1,271 @@
+package privacy_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/privacy"
+	"example.com/internal/domain/service/retention"
+	"example.com/pkg/mail"
+	"example.com/pkg/security"
+	"example.com/pkg/storage"
+	"example.com/test/unit/mocks"
+)
+
+const userID = "user-1"
+
+var (
+	downloadPattern = regexp.MustCompile(`https://api\.example\.com/api/v1/data-export/download\?token=(dex_[A-Za-z0-9_-]+)`)
+	confirmPattern  = regexp.MustCompile(`https://app\.example\.com/delete\?deletion_token=(adr_[A-Za-z0-9_-]+)`)
+)
+
+type fixture struct {
+	users         *mocks.MockUserRepository
+	organizations *mocks.MockOrganizationRepository
+	exports       *mocks.MockDataExportRepository
+	deletions     *mocks.MockAccountDeletionRepository
+	personalData  *mocks.MockPersonalDataRepository
+	audit         *mocks.MockAuditEventRepository
+	sender        *mocks.MockMailSender
+	store         storage.BlobStore
+	svc           privacy.Service
+}
+
+func newFixture(t *testing.T) *fixture {
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	f := &fixture{
+		users:         &mocks.MockUserRepository{},
+		organizations: &mocks.MockOrganizationRepository{},
+		exports:       &mocks.MockDataExportRepository{},
+		deletions:     &mocks.MockAccountDeletionRepository{},
+		personalData:  &mocks.MockPersonalDataRepository{},
+		audit:         &mocks.MockAuditEventRepository{},
+		sender:        &mocks.MockMailSender{},
+		store:         store,
+	}
+	retentionSvc := retention.NewService(f.users, f.exports, store, retention.Config{DeletedRetention: time.Hour})
+	f.svc = privacy.NewService(
+		f.users, f.organizations, f.exports, f.deletions, f.personalData, f.audit, retentionSvc, store, f.sender,
+		privacy.Config{
+			DownloadURL: "https://api.example.com/api/v1/data-export/download",
+			ExportTTL:   48 * time.Hour,
+			ConfirmURL:  "https://app.example.com/delete",
+			ConfirmTTL:  24 * time.Hour,
+			CoolingOff:  7 * 24 * time.Hour,
+		},
+	)
+	return f
+}
+
+// outbox records the messages sent
+func (f *fixture) outbox(ctx context.Context) *[]mail.Message {
+	var sent []mail.Message
+	f.sender.On("Send", ctx, mock.AnythingOfType("mail.Message")).
+		Run(func(args mock.Arguments) { sent = append(sent, args.Get(1).(mail.Message)) }).
+		Return(nil)
+	return &sent
+}
+
+// audited returns the actions of the audit events recorded
+func (f *fixture) audited(ctx context.Context) *[]string {
+	var actions []string
+	f.audit.On("Create", ctx, mock.AnythingOfType("*entity.AuditEvent")).
+		Run(func(args mock.Arguments) { actions = append(actions, args.Get(1).(*entity.AuditEvent).Action) }).
+		Return(nil)
+	return &actions
+}
+
+func TestPrivacyService_RequestExport(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	actions := f.audited(ctx)
+	f.exports.On("FindLatest", ctx, userID).Return(&entity.DataExport{ID: "old", Status: entity.DataExportReady}, nil).Once()
+	f.exports.On("Create", ctx, mock.AnythingOfType("*entity.DataExport")).Return(nil).Once()
+
+	export, err := f.svc.RequestExport(ctx, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.DataExportPending, export.Status)
+	assert.Equal(t, userID, export.UserID)
+	assert.Equal(t, []string{entity.AuditDataExportRequested}, *actions)
+
+	f.exports.On("FindLatest", ctx, userID).Return(export, nil).Once()
+	again, err := f.svc.RequestExport(ctx, userID)
+	require.NoError(t, err)
+	assert.Same(t, export, again, "the pending export is returned")
+	f.exports.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestPrivacyService_ProcessExports(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	sent := f.outbox(ctx)
+	pending := &entity.DataExport{ID: "export-1", UserID: userID, Status: entity.DataExportPending}
+	f.exports.On("ListPending", ctx, 20).Return([]*entity.DataExport{pending}, nil)
+	f.personalData.On("Collect", ctx, userID).Return(&entity.PersonalData{
+		User:        &entity.User{ID: userID, UserName: "jane", Email: "jane@example.com", PasswordHash: "secret-hash"},
+		Profile:     &entity.UserProfile{UserID: userID, DisplayName: "Jane"},
+		AuditEvents: []*entity.AuditEvent{{Action: entity.AuditLogin, Detail: "password"}, {Action: entity.AuditDataExportRequested}},
+	}, nil)
+	f.exports.On("Update", ctx, pending).Return(nil)
+	expired := &entity.DataExport{ID: "export-0", UserID: userID}
+	require.NoError(t, f.store.Put(ctx, expired.ArchiveKey(), []byte("zip")))
+	f.exports.On("ListExpired", ctx, mock.AnythingOfType("time.Time"), 20).Return([]*entity.DataExport{expired}, nil)
+	f.exports.On("Delete", ctx, "export-0").Return(nil)
+
+	built, err := f.svc.ProcessExports(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, built)
+	assert.Equal(t, entity.DataExportReady, pending.Status)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), *pending.ExpiresAt, time.Minute)
+	_, err = f.store.Get(ctx, expired.ArchiveKey())
+	assert.ErrorIs(t, err, storage.ErrNotFound, "expired archive deleted")
+	f.exports.AssertExpectations(t)
+
+	require.Len(t, *sent, 1)
+	assert.Equal(t, "jane@example.com", (*sent)[0].To)
+	match := downloadPattern.FindStringSubmatch((*sent)[0].Body)
+	require.NotNil(t, match, (*sent)[0].Body)
+	assert.Equal(t, security.HashToken(match[1]), pending.TokenHash)
+
+	f.exports.On("FindByTokenHash", ctx, pending.TokenHash).Return(pending, nil)
+	_, archive, err := f.svc.Download(ctx, match[1])
+	require.NoError(t, err)
+	defer archive.Body.Close()
+	data, err := io.ReadAll(archive.Body)
+	require.NoError(t, err)
+	assert.Equal(t, pending.Size, int64(len(data)))
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, file := range reader.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+	for _, name := range []string{"user.json", "profile.json", "sessions.json", "login_history.json", "audit_events.json"} {
+		assert.Contains(t, files, name)
+	}
+	assert.Contains(t, files["user.json"], `"email": "jane@example.com"`)
+	assert.NotContains(t, files["user.json"], "secret-hash", "secrets are left out")
+	assert.Equal(t, "[]\n", files["sessions.json"])
+	assert.Contains(t, files["login_history.json"], `"detail": "password"`)
+	assert.NotContains(t, files["login_history.json"], entity.AuditDataExportRequested)
+}
+
+func TestPrivacyService_Download_Refusals(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	f.exports.On("FindByTokenHash", ctx, security.HashToken("dex_unknown")).Return(nil, gorm.ErrRecordNotFound)
+	f.exports.On("FindByTokenHash", ctx, security.HashToken("dex_expired")).
+		Return(&entity.DataExport{ID: "export-1", UserID: userID, Status: entity.DataExportReady, ExpiresAt: &past}, nil)
+
+	_, _, err := f.svc.Download(ctx, "dex_unknown")
+	assert.ErrorIs(t, err, privacy.ErrExportNotFound)
+	_, _, err = f.svc.Download(ctx, "dex_expired")
+	assert.ErrorIs(t, err, privacy.ErrExportNotFound)
+}
+
+func TestPrivacyService_Deletion(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	sent := f.outbox(ctx)
+	actions := f.audited(ctx)
+	f.users.On("FindByID", ctx, userID).Return(&entity.User{ID: userID, Email: "jane@example.com"}, nil)
+	f.deletions.On("FindByUserID", ctx, userID).Return(nil, gorm.ErrRecordNotFound).Once()
+	f.organizations.On("ListByUserID", ctx, userID).Return([]*entity.Membership{{Role: entity.MembershipAdmin}}, nil)
+	var saved *entity.AccountDeletion
+	f.deletions.On("Save", ctx, mock.AnythingOfType("*entity.AccountDeletion")).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*entity.AccountDeletion) }).
+		Return(nil)
+
+	deletion, err := f.svc.RequestDeletion(ctx, userID)
+
+	require.NoError(t, err)
+	assert.False(t, deletion.Confirmed())
+	require.Len(t, *sent, 1)
+	match := confirmPattern.FindStringSubmatch((*sent)[0].Body)
+	require.NotNil(t, match, (*sent)[0].Body)
+	assert.Equal(t, security.HashToken(match[1]), saved.TokenHash)
+
+	f.deletions.On("FindByUserID", ctx, userID).Return(saved, nil)
+	_, err = f.svc.ConfirmDeletion(ctx, userID, "adr_wrong")
+	assert.ErrorIs(t, err, privacy.ErrInvalidDeletionToken)
+	assert.False(t, saved.Confirmed())
+
+	confirmed, err := f.svc.ConfirmDeletion(ctx, userID, match[1])
+	require.NoError(t, err)
+	assert.True(t, confirmed.Confirmed())
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), *confirmed.ScheduledAt, time.Minute)
+
+	again, err := f.svc.RequestDeletion(ctx, userID)
+	require.NoError(t, err)
+	assert.Same(t, confirmed, again, "confirmed requests are kept")
+	assert.Len(t, *sent, 1)
+
+	f.deletions.On("Delete", ctx, userID).Return(nil).Once()
+	require.NoError(t, f.svc.CancelDeletion(ctx, userID))
+	assert.Equal(t, []string{
+		entity.AuditAccountDeletionRequested,
+		entity.AuditAccountDeletionConfirmed,
+		entity.AuditAccountDeletionCancelled,
+	}, *actions)
+}
+
+func TestPrivacyService_Deletion_Refusals(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	expired := &entity.AccountDeletion{UserID: "user-2", TokenHash: security.HashToken("adr_token"), ExpiresAt: time.Now().Add(-time.Minute)}
+	f.users.On("FindByID", ctx, userID).Return(&entity.User{ID: userID}, nil)
+	f.deletions.On("FindByUserID", ctx, userID).Return(nil, gorm.ErrRecordNotFound)
+	f.deletions.On("FindByUserID", ctx, "user-2").Return(expired, nil)
+	f.deletions.On("Delete", ctx, userID).Return(gorm.ErrRecordNotFound)
+	f.organizations.On("ListByUserID", ctx, userID).Return([]*entity.Membership{{Role: entity.MembershipOwner}}, nil)
+
+	_, err := f.svc.RequestDeletion(ctx, userID)
+	assert.ErrorIs(t, err, privacy.ErrOrganizationOwner)
+	_, err = f.svc.ConfirmDeletion(ctx, userID, "adr_token")
+	assert.ErrorIs(t, err, privacy.ErrDeletionNotFound)
+	_, err = f.svc.ConfirmDeletion(ctx, "user-2", "adr_token")
+	assert.ErrorIs(t, err, privacy.ErrInvalidDeletionToken, "expired")
+	assert.ErrorIs(t, f.svc.CancelDeletion(ctx, userID), privacy.ErrDeletionNotFound)
+	f.deletions.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestPrivacyService_EraseDueAccounts(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.deletions.On("ListDue", ctx, mock.AnythingOfType("time.Time"), 20).
+		Return([]*entity.AccountDeletion{{UserID: userID}, {UserID: "gone"}}, nil)
+	f.users.On("Delete", ctx, mock.Anything).Return(nil)
+	f.users.On("FindDeleted", ctx, userID).Return(&entity.User{ID: userID}, nil)
+	f.users.On("FindDeleted", ctx, "gone").Return(nil, gorm.ErrRecordNotFound)
+	f.exports.On("ListByUserID", ctx, userID).Return([]*entity.DataExport{}, nil)
+	f.users.On("Purge", ctx, userID, mock.AnythingOfType("time.Time")).Return(nil)
+	f.deletions.On("Delete", ctx, userID).Return(gorm.ErrRecordNotFound)
+	f.deletions.On("Delete", ctx, "gone").Return(nil)
+
+	erased, err := f.svc.EraseDueAccounts(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	f.users.AssertExpectations(t)
+	f.deletions.AssertExpectations(t)
+}
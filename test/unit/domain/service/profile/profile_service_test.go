@@ -0,0 +1,118 @@
+package profile_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/profile"
+	"example.com/test/unit/mocks"
+)
+
+const userID = "user-1"
+
+func newService(user *entity.User) (profile.Service, *mocks.MockUserProfileRepository) {
+	users := &mocks.MockUserRepository{}
+	users.On("FindByID", mock.Anything, userID).Return(user, nil)
+	users.On("FindByID", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	profiles := &mocks.MockUserProfileRepository{}
+	return profile.NewService(users, profiles), profiles
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestService_GetWithoutProfile(t *testing.T) {
+	svc, _ := newService(&entity.User{ID: userID})
+
+	found, err := svc.Get(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, &entity.UserProfile{UserID: userID}, found)
+
+	_, err = svc.Get(context.Background(), "user-2")
+	assert.ErrorIs(t, err, profile.ErrUserNotFound)
+}
+
+func TestService_UpdateMergesPatch(t *testing.T) {
+	svc, profiles := newService(&entity.User{ID: userID, Profile: &entity.UserProfile{
+		ID:          7,
+		UserID:      userID,
+		DisplayName: "Jane",
+		Bio:         "Gardener",
+		Locale:      "de-DE",
+	}})
+	profiles.On("Save", mock.Anything, mock.AnythingOfType("*entity.UserProfile")).Return(nil)
+
+	updated, err := svc.Update(context.Background(), userID, profile.Patch{
+		DisplayName: ptr("  Jane Doe "),
+		Bio:         ptr(""),
+		Locale:      ptr("en_us"),
+		Timezone:    ptr("Europe/Berlin"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &entity.UserProfile{
+		ID:          7,
+		UserID:      userID,
+		DisplayName: "Jane Doe",
+		Locale:      "en-US",
+		Timezone:    "Europe/Berlin",
+	}, updated)
+	profiles.AssertCalled(t, "Save", mock.Anything, updated)
+}
+
+func TestService_UpdateKeepsFieldsLeftOut(t *testing.T) {
+	svc, profiles := newService(&entity.User{ID: userID, Profile: &entity.UserProfile{UserID: userID, Bio: "Gardener"}})
+	profiles.On("Save", mock.Anything, mock.AnythingOfType("*entity.UserProfile")).Return(nil)
+
+	updated, err := svc.Update(context.Background(), userID, profile.Patch{Timezone: ptr("UTC")})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Gardener", updated.Bio)
+	assert.Equal(t, "UTC", updated.Timezone)
+}
+
+func TestService_UpdateRejectsInvalidFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch profile.Patch
+		err   error
+	}{
+		{name: "long display name", patch: profile.Patch{DisplayName: ptr(strings.Repeat("a", 51))}, err: profile.ErrInvalidDisplayName},
+		{name: "control character", patch: profile.Patch{DisplayName: ptr("Jane\u0007")}, err: profile.ErrInvalidDisplayName},
+		{name: "long bio", patch: profile.Patch{Bio: ptr(strings.Repeat("é", 501))}, err: profile.ErrInvalidBio},
+		{name: "locale", patch: profile.Patch{Locale: ptr("not a locale")}, err: profile.ErrInvalidLocale},
+		{name: "time zone", patch: profile.Patch{Timezone: ptr("Mars/Olympus_Mons")}, err: profile.ErrInvalidTimezone},
+		{name: "server time zone", patch: profile.Patch{Timezone: ptr("Local")}, err: profile.ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, profiles := newService(&entity.User{ID: userID})
+
+			_, err := svc.Update(context.Background(), userID, tt.patch)
+
+			require.ErrorIs(t, err, tt.err)
+			profiles.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_UpdateAcceptsMultilineBio(t *testing.T) {
+	svc, profiles := newService(&entity.User{ID: userID})
+	profiles.On("Save", mock.Anything, mock.AnythingOfType("*entity.UserProfile")).Return(nil)
+
+	updated, err := svc.Update(context.Background(), userID, profile.Patch{Bio: ptr("Gardener\nBeekeeper")})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Gardener\nBeekeeper", updated.Bio)
+	assert.Equal(t, userID, updated.UserID)
+}
@@ -0,0 +1,92 @@
+package retention_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/retention"
+	"example.com/pkg/storage"
+	"example.com/test/unit/mocks"
+)
+
+func TestRetentionService_PurgeDeletedUsers(t *testing.T) {
+	ctx := context.Background()
+	users := &mocks.MockUserRepository{}
+	exports := &mocks.MockDataExportRepository{}
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	svc := retention.NewService(users, exports, store, retention.Config{DeletedRetention: 720 * time.Hour})
+
+	avatar := &entity.UserProfile{UserID: "user-1", Avatar: "0123456789abcdef.png"}
+	for _, size := range entity.AvatarSizes {
+		require.NoError(t, store.Put(ctx, avatar.AvatarKey(size), []byte("png")))
+	}
+	var deletedBefore time.Time
+	users.On("ListPurgeable", ctx, mock.AnythingOfType("time.Time"), 100).
+		Run(func(args mock.Arguments) { deletedBefore = args.Get(1).(time.Time) }).
+		Return([]*entity.User{{ID: "user-1", Profile: avatar}, {ID: "user-2"}, {ID: "user-3"}}, nil)
+	export := &entity.DataExport{ID: "export-1", UserID: "user-1"}
+	require.NoError(t, store.Put(ctx, export.ArchiveKey(), []byte("zip")))
+	exports.On("ListByUserID", ctx, "user-1").Return([]*entity.DataExport{export}, nil)
+	exports.On("ListByUserID", ctx, mock.Anything).Return(nil, nil)
+	users.On("Purge", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(nil)
+	users.On("Purge", ctx, "user-2", mock.AnythingOfType("time.Time")).Return(gorm.ErrRecordNotFound)
+	users.On("Purge", ctx, "user-3", mock.AnythingOfType("time.Time")).Return(nil)
+
+	purged, err := svc.PurgeDeletedUsers(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged, "user-2 was restored or purged meanwhile")
+	assert.WithinDuration(t, time.Now().Add(-720*time.Hour), deletedBefore, time.Minute)
+	for _, size := range entity.AvatarSizes {
+		_, err := store.Get(ctx, avatar.AvatarKey(size))
+		assert.ErrorIs(t, err, storage.ErrNotFound, "avatar deleted")
+	}
+	_, err = store.Get(ctx, export.ArchiveKey())
+	assert.ErrorIs(t, err, storage.ErrNotFound, "data export deleted")
+	users.AssertExpectations(t)
+}
+
+func TestRetentionService_PurgeDeletedUsers_Failure(t *testing.T) {
+	ctx := context.Background()
+	users := &mocks.MockUserRepository{}
+	exports := &mocks.MockDataExportRepository{}
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	svc := retention.NewService(users, exports, store, retention.Config{DeletedRetention: time.Hour})
+	failure := errors.New("connection lost")
+	exports.On("ListByUserID", ctx, mock.Anything).Return(nil, nil)
+	users.On("ListPurgeable", ctx, mock.Anything, mock.Anything).Return([]*entity.User{{ID: "user-1"}, {ID: "user-2"}}, nil)
+	users.On("Purge", ctx, "user-1", mock.Anything).Return(nil)
+	users.On("Purge", ctx, "user-2", mock.Anything).Return(failure)
+
+	purged, err := svc.PurgeDeletedUsers(ctx)
+
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, purged)
+}
+
+func TestRetentionService_Erase(t *testing.T) {
+	ctx := context.Background()
+	users := &mocks.MockUserRepository{}
+	exports := &mocks.MockDataExportRepository{}
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	svc := retention.NewService(users, exports, store, retention.Config{DeletedRetention: 720 * time.Hour})
+	users.On("Delete", ctx, "user-1").Return(nil)
+	users.On("FindDeleted", ctx, "user-1").Return(&entity.User{ID: "user-1"}, nil)
+	exports.On("ListByUserID", ctx, "user-1").Return([]*entity.DataExport{}, nil)
+	users.On("Purge", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	require.NoError(t, svc.Erase(ctx, "user-1"), "no retention period")
+
+	users.AssertExpectations(t)
+}
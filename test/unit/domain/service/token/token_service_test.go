@@ -0,0 +1,160 @@
+package token_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	tokenservice "example.com/internal/domain/service/token"
+	"example.com/pkg/security"
+	"example.com/test/unit/mocks"
+)
+
+func newService(t *testing.T) (tokenservice.Service, *mocks.MockRefreshTokenRepository) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	issuer, err := security.NewJWTIssuer("https://api.example.com", []security.SigningKey{
+		{ID: "ed-1", Algorithm: security.AlgEdDSA, Signer: private},
+	}, 15*time.Minute)
+	require.NoError(t, err)
+
+	mockRepo := &mocks.MockRefreshTokenRepository{}
+	return tokenservice.NewService(mockRepo, issuer, time.Hour), mockRepo
+}
+
+func TestTokenService_IssuePair(t *testing.T) {
+	svc, mockRepo := newService(t)
+	ctx := context.Background()
+
+	var stored *entity.RefreshToken
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.RefreshToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*entity.RefreshToken) }).
+		Return(nil)
+
+	pair, err := svc.IssuePair(ctx, "user-123")
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(pair.RefreshToken, entity.RefreshTokenPrefix))
+	assert.Equal(t, security.HashToken(pair.RefreshToken), stored.TokenHash)
+	assert.Equal(t, "user-123", stored.UserID)
+	assert.NotEmpty(t, stored.FamilyID)
+
+	claims, err := svc.Authenticate(ctx, pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", claims.Subject)
+}
+
+func TestTokenService_Refresh_RotatesWithinFamily(t *testing.T) {
+	svc, mockRepo := newService(t)
+	ctx := context.Background()
+
+	plain := entity.RefreshTokenPrefix + "current"
+	mockRepo.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.RefreshToken{
+		ID:        "rt-1",
+		UserID:    "user-123",
+		FamilyID:  "family-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	mockRepo.On("MarkUsed", ctx, "rt-1", mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(token *entity.RefreshToken) bool {
+		return token.FamilyID == "family-1" && token.UserID == "user-123"
+	})).Return(nil)
+
+	pair, err := svc.Refresh(ctx, plain)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, plain, pair.RefreshToken)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTokenService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	svc, mockRepo := newService(t)
+	ctx := context.Background()
+
+	plain := entity.RefreshTokenPrefix + "used"
+	usedAt := time.Now().Add(-time.Minute)
+	mockRepo.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.RefreshToken{
+		ID:        "rt-1",
+		FamilyID:  "family-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}, nil)
+	mockRepo.On("RevokeFamily", ctx, "family-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := svc.Refresh(ctx, plain)
+
+	assert.ErrorIs(t, err, tokenservice.ErrRefreshTokenReused)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTokenService_Refresh_ConcurrentExchangeRevokesFamily(t *testing.T) {
+	svc, mockRepo := newService(t)
+	ctx := context.Background()
+
+	plain := entity.RefreshTokenPrefix + "raced"
+	mockRepo.On("FindByHash", ctx, security.HashToken(plain)).Return(&entity.RefreshToken{
+		ID:        "rt-1",
+		FamilyID:  "family-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	mockRepo.On("MarkUsed", ctx, "rt-1", mock.AnythingOfType("time.Time")).Return(gorm.ErrRecordNotFound)
+	mockRepo.On("RevokeFamily", ctx, "family-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := svc.Refresh(ctx, plain)
+
+	assert.ErrorIs(t, err, tokenservice.ErrRefreshTokenReused)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTokenService_Refresh_Rejects(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name   string
+		plain  string
+		stored *entity.RefreshToken
+	}{
+		{name: "wrong prefix", plain: "pat_token"},
+		{name: "unknown", plain: entity.RefreshTokenPrefix + "unknown"},
+		{name: "expired", plain: entity.RefreshTokenPrefix + "expired", stored: &entity.RefreshToken{ID: "rt", ExpiresAt: past}},
+		{name: "revoked", plain: entity.RefreshTokenPrefix + "revoked", stored: &entity.RefreshToken{
+			ID: "rt", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &past,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo := newService(t)
+			if tt.stored != nil {
+				mockRepo.On("FindByHash", mock.Anything, security.HashToken(tt.plain)).Return(tt.stored, nil)
+			} else {
+				mockRepo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+			}
+
+			_, err := svc.Refresh(context.Background(), tt.plain)
+
+			assert.ErrorIs(t, err, tokenservice.ErrInvalidRefreshToken)
+			mockRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTokenService_Disabled(t *testing.T) {
+	svc := tokenservice.NewService(&mocks.MockRefreshTokenRepository{}, nil, time.Hour)
+
+	_, err := svc.IssuePair(context.Background(), "user-123")
+	assert.ErrorIs(t, err, tokenservice.ErrTokensDisabled)
+	_, err = svc.Authenticate(context.Background(), "token")
+	assert.ErrorIs(t, err, tokenservice.ErrTokensDisabled)
+	assert.False(t, svc.Enabled())
+	assert.Empty(t, svc.JWKS().Keys)
+}
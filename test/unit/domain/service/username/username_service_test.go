@@ -0,0 +1,207 @@
+package username_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/service/username"
+	"example.com/pkg/security"
+	"example.com/test/unit/mocks"
+)
+
+type fixture struct {
+	users   *mocks.MockUserRepository
+	changes *mocks.MockUserNameChangeRepository
+	audit   *mocks.MockAuditEventRepository
+	svc     username.Service
+}
+
+func newFixture(config username.Config) *fixture {
+	f := &fixture{
+		users:   &mocks.MockUserRepository{},
+		changes: &mocks.MockUserNameChangeRepository{},
+		audit:   &mocks.MockAuditEventRepository{},
+	}
+	if config.Policy == nil {
+		config.Policy = security.NewUsernamePolicy(security.UsernameRules{MinLength: 3, MaxLength: entity.UserNameMaxLength})
+	}
+	f.svc = username.NewService(f.users, f.changes, f.audit, config)
+	return f
+}
+
+// free makes every name free of users and redirects but the ones set up before
+func (f *fixture) free() {
+	f.users.On("FindByUserNameSkeleton", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	f.changes.On("FindRedirect", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+}
+
+func TestService_Check(t *testing.T) {
+	f := newFixture(username.Config{})
+	ctx := context.Background()
+	f.users.On("FindByUserNameSkeleton", ctx, "jane_doe").Return(&entity.User{ID: "user-1", UserName: "jane.doe"}, nil)
+	f.changes.On("FindRedirect", ctx, "bob", mock.Anything).Return(&entity.UserNameChange{UserID: "user-2", OldUserName: "bob"}, nil)
+	f.free()
+
+	name, err := f.svc.Check(ctx, "", "  Ｊａｎｅ ")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", name)
+
+	_, err = f.svc.Check(ctx, "", "Jane-Doe")
+	assert.ErrorIs(t, err, username.ErrUsernameTaken, "lookalikes of another user's name are taken")
+	name, err = f.svc.Check(ctx, "user-1", "jane_doe")
+	require.NoError(t, err, "users may switch between lookalikes of their own name")
+	assert.Equal(t, "jane_doe", name)
+
+	_, err = f.svc.Check(ctx, "user-1", "b0b")
+	assert.ErrorIs(t, err, username.ErrUsernameTaken, "names left by other users are held during their redirect")
+	_, err = f.svc.Check(ctx, "user-2", "bob")
+	assert.NoError(t, err, "users may take back the name they left")
+
+	_, err = f.svc.Check(ctx, "", "root")
+	assert.ErrorIs(t, err, security.ErrInvalidUsername)
+}
+
+func TestService_Generate(t *testing.T) {
+	t.Run("from the first usable hint", func(t *testing.T) {
+		f := newFixture(username.Config{})
+		f.free()
+
+		name, err := f.svc.Generate(context.Background(), "!!!", "Jane..Doe+news")
+		require.NoError(t, err)
+		assert.Equal(t, "jane.doenews", name)
+
+		name, err = f.svc.Generate(context.Background(), "Jonathan.Livingston.Seagull")
+		require.NoError(t, err)
+		assert.Equal(t, "jonathan.living", name)
+		assert.Len(t, name, entity.UserNameMaxLength)
+	})
+
+	t.Run("suffixed while taken or refused", func(t *testing.T) {
+		f := newFixture(username.Config{})
+		f.users.On("FindByUserNameSkeleton", mock.Anything, "janedoe").Return(&entity.User{ID: "user-1"}, nil)
+		f.free()
+
+		name, err := f.svc.Generate(context.Background(), "janedoe")
+		require.NoError(t, err)
+		assert.Regexp(t, `^janedoe_[0-9a-f]{4}$`, name)
+
+		for hint, pattern := range map[string]string{"admin": `^admin_[0-9a-f]{4}$`, "": `^user_[0-9a-f]{4}$`, "42": `^42_[0-9a-f]{4}$`} {
+			name, err = f.svc.Generate(context.Background(), hint)
+			require.NoError(t, err, hint)
+			assert.Regexp(t, pattern, name, hint)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		f := newFixture(username.Config{})
+		f.users.On("FindByUserNameSkeleton", mock.Anything, mock.Anything).Return(&entity.User{ID: "user-1"}, nil)
+		f.changes.On("FindRedirect", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := f.svc.Generate(context.Background(), "jane")
+		assert.ErrorIs(t, err, username.ErrUsernameUnavailable)
+	})
+}
+
+func TestService_Rename(t *testing.T) {
+	ctx := context.Background()
+	user := func() *entity.User { return &entity.User{ID: "user-1", UserName: "jane"} }
+
+	t.Run("keeps a redirect for the old name", func(t *testing.T) {
+		f := newFixture(username.Config{RedirectTTL: 30 * 24 * time.Hour, RenameInterval: 24 * time.Hour})
+		f.users.On("FindByID", ctx, "user-1").Return(user(), nil)
+		f.free()
+		f.changes.On("ListByUserID", ctx, "user-1").
+			Return([]*entity.UserNameChange{{CreatedAt: time.Now().Add(-48 * time.Hour)}}, nil)
+		f.users.On("ChangeUserName", ctx, "user-1", "jane", "jane.doe").Return(nil)
+		f.changes.On("Create", ctx, mock.AnythingOfType("*entity.UserNameChange")).Return(nil)
+		f.audit.On("Create", ctx, mock.AnythingOfType("*entity.AuditEvent")).Return(nil)
+
+		renamed, err := f.svc.Rename(ctx, "user-1", "Jane.Doe")
+
+		require.NoError(t, err)
+		assert.Equal(t, "jane.doe", renamed.UserName)
+		change := f.changes.Calls[len(f.changes.Calls)-1].Arguments.Get(1).(*entity.UserNameChange)
+		assert.Equal(t, "jane", change.OldUserName)
+		assert.Equal(t, "jane", change.OldSkeleton)
+		assert.Equal(t, "jane.doe", change.NewUserName)
+		assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), change.RedirectExpiresAt, time.Minute)
+		event := f.audit.Calls[0].Arguments.Get(1).(*entity.AuditEvent)
+		assert.Equal(t, entity.AuditUserNameChanged, event.Action)
+		assert.Equal(t, "jane -> jane.doe", event.Detail)
+	})
+
+	t.Run("refuses", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			username string
+			setup    func(f *fixture)
+			want     error
+		}{
+			{name: "unknown user", username: "jane.doe", want: username.ErrUserNotFound, setup: func(f *fixture) {
+				f.users.On("FindByID", ctx, "user-1").Return(nil, gorm.ErrRecordNotFound)
+			}},
+			{name: "same name", username: "JANE", want: username.ErrSameUsername},
+			{name: "invalid name", username: "j", want: security.ErrInvalidUsername},
+			{name: "renamed recently", username: "jane.doe", want: username.ErrRenameTooSoon, setup: func(f *fixture) {
+				f.changes.On("ListByUserID", ctx, "user-1").Return([]*entity.UserNameChange{{CreatedAt: time.Now().Add(-time.Hour)}}, nil)
+			}},
+			{name: "taken meanwhile", username: "jane.doe", want: username.ErrUsernameTaken, setup: func(f *fixture) {
+				f.users.On("ChangeUserName", ctx, "user-1", "jane", "jane.doe").Return(gorm.ErrDuplicatedKey)
+			}},
+			{name: "renamed meanwhile", username: "jane.doe", want: username.ErrRenameTooSoon, setup: func(f *fixture) {
+				f.users.On("ChangeUserName", ctx, "user-1", "jane", "jane.doe").Return(gorm.ErrRecordNotFound)
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				f := newFixture(username.Config{RenameInterval: 24 * time.Hour})
+				if tt.setup != nil {
+					tt.setup(f)
+				}
+				f.users.On("FindByID", ctx, "user-1").Return(user(), nil)
+				f.changes.On("ListByUserID", ctx, "user-1").Return(nil, nil)
+				f.free()
+
+				_, err := f.svc.Rename(ctx, "user-1", tt.username)
+
+				assert.ErrorIs(t, err, tt.want)
+				f.changes.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				f.audit.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	jane := &entity.User{ID: "user-1", UserName: "jane.doe"}
+	f := newFixture(username.Config{})
+	f.users.On("FindByUserName", ctx, "jane.doe").Return(jane, nil)
+	f.users.On("FindByUserName", ctx, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	f.users.On("FindByID", ctx, "user-1").Return(jane, nil)
+	f.changes.On("FindRedirect", ctx, "bob", mock.Anything).Return(&entity.UserNameChange{UserID: "user-1", OldUserName: "bob"}, nil)
+	f.changes.On("FindRedirect", ctx, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	found, moved, err := f.svc.Resolve(ctx, "Jane.Doe")
+	require.NoError(t, err)
+	assert.Equal(t, jane, found)
+	assert.False(t, moved)
+
+	found, moved, err = f.svc.Resolve(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, jane, found)
+	assert.True(t, moved)
+
+	_, _, err = f.svc.Resolve(ctx, "b0b")
+	assert.ErrorIs(t, err, username.ErrUserNotFound)
+	_, _, err = f.svc.Resolve(ctx, "nobody")
+	assert.ErrorIs(t, err, username.ErrUserNotFound)
+}
@@ -7,10 +7,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 
 	"example.com/internal/domain/entity"
 	authservice "example.com/internal/domain/service/auth"
+	passwordservice "example.com/internal/domain/service/password"
+	usernameservice "example.com/internal/domain/service/username"
 	authusecase "example.com/internal/domain/usecase/auth"
+	"example.com/pkg/security"
 	"example.com/test/unit/mocks"
 )
 
@@ -18,7 +22,7 @@ func TestSignupUseCase_Call_Success(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
 	authSvc := authservice.NewService(mockRepo, mockHasher)
-	useCase := authusecase.NewSignupUseCase(authSvc)
+	useCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
 
 	ctx := context.Background()
 	email := "test@example.com"
@@ -52,7 +56,7 @@ func TestSignupUseCase_Call_UserAlreadyExists_Email(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
 	authSvc := authservice.NewService(mockRepo, mockHasher)
-	useCase := authusecase.NewSignupUseCase(authSvc)
+	useCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
 
 	ctx := context.Background()
 	email := "test@example.com"
@@ -81,7 +85,7 @@ func TestSignupUseCase_Call_UserAlreadyExists_Username(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
 	authSvc := authservice.NewService(mockRepo, mockHasher)
-	useCase := authusecase.NewSignupUseCase(authSvc)
+	useCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
 
 	ctx := context.Background()
 	email := "test@example.com"
@@ -109,7 +113,7 @@ func TestSignupUseCase_Call_HashingError(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
 	authSvc := authservice.NewService(mockRepo, mockHasher)
-	useCase := authusecase.NewSignupUseCase(authSvc)
+	useCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
 
 	ctx := context.Background()
 	email := "test@example.com"
@@ -136,7 +140,7 @@ func TestSignupUseCase_Call_DatabaseError(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	mockHasher := &mocks.MockPasswordHasher{}
 	authSvc := authservice.NewService(mockRepo, mockHasher)
-	useCase := authusecase.NewSignupUseCase(authSvc)
+	useCase := authusecase.NewSignupUseCase(authSvc, nil, nil)
 
 	ctx := context.Background()
 	email := "test@example.com"
@@ -162,3 +166,76 @@ func TestSignupUseCase_Call_DatabaseError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockHasher.AssertExpectations(t)
 }
+
+func TestSignupUseCase_Call_WeakPassword(t *testing.T) {
+	mockRepo := &mocks.MockUserRepository{}
+	mockHasher := &mocks.MockPasswordHasher{}
+	authSvc := authservice.NewService(mockRepo, mockHasher)
+	passwordSvc := passwordservice.NewService(mockRepo, nil, nil, nil, nil, nil, mockHasher, passwordservice.Config{
+		Policy: security.NewPasswordPolicy(security.PasswordRules{MinLength: 8, MaxLength: 72, MinCharClasses: 1}),
+	})
+	useCase := authusecase.NewSignupUseCase(authSvc, passwordSvc, nil)
+
+	ctx := context.Background()
+
+	// Passwords containing the username are refused before anything is stored
+	user, err := useCase.Call(ctx, "test@example.com", "testuser2026", "testuser")
+
+	assert.ErrorIs(t, err, security.ErrWeakPassword)
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockHasher.AssertNotCalled(t, "Hash", mock.Anything)
+}
+
+func TestSignupUseCase_Call_Username(t *testing.T) {
+	newUseCase := func() (authusecase.SignupUseCase, *mocks.MockUserRepository) {
+		mockRepo := &mocks.MockUserRepository{}
+		mockHasher := &mocks.MockPasswordHasher{}
+		changes := &mocks.MockUserNameChangeRepository{}
+		changes.On("FindRedirect", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		usernameSvc := usernameservice.NewService(mockRepo, changes, nil, usernameservice.Config{
+			Policy: security.NewUsernamePolicy(security.UsernameRules{MinLength: 3, MaxLength: entity.UserNameMaxLength}),
+		})
+		mockHasher.On("Hash", mock.Anything).Return("hashed_password", nil)
+		return authusecase.NewSignupUseCase(authservice.NewService(mockRepo, mockHasher), nil, usernameSvc), mockRepo
+	}
+	ctx := context.Background()
+
+	t.Run("derived from the email when omitted", func(t *testing.T) {
+		useCase, mockRepo := newUseCase()
+		mockRepo.On("FindByUserNameSkeleton", ctx, "jane_doe").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("FindByUserName", ctx, "jane-doe").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("FindByEmail", ctx, "Jane-Doe@example.com").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+		user, err := useCase.Call(ctx, "Jane-Doe@example.com", "password123", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jane-doe", user.UserName)
+	})
+
+	t.Run("stored in lower case", func(t *testing.T) {
+		useCase, mockRepo := newUseCase()
+		mockRepo.On("FindByUserNameSkeleton", ctx, "testuser").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("FindByUserName", ctx, "testuser").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("FindByEmail", ctx, "test@example.com").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+		user, err := useCase.Call(ctx, "test@example.com", "password123", "TestUser")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "testuser", user.UserName)
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		useCase, mockRepo := newUseCase()
+		mockRepo.On("FindByUserNameSkeleton", ctx, "testuser").Return(&entity.User{ID: "existing-user", UserName: "testuser"}, nil)
+
+		_, err := useCase.Call(ctx, "test@example.com", "password123", "TESTUSER")
+		assert.ErrorIs(t, err, usernameservice.ErrUsernameTaken)
+
+		_, err = useCase.Call(ctx, "test@example.com", "password123", "adm1n")
+		assert.ErrorIs(t, err, security.ErrInvalidUsername)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
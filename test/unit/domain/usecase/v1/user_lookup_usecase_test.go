@@ -11,13 +11,14 @@ import (
 	"example.com/internal/domain/entity"
 	userservice "example.com/internal/domain/service/v1"
 	userusecase "example.com/internal/domain/usecase/v1"
+	"example.com/pkg/authz"
 	"example.com/test/unit/mocks"
 )
 
 func TestUserLookupUseCase_Call_Success(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	userSvc := userservice.NewService(mockRepo)
-	useCase := userusecase.NewUserLookupUseCase(userSvc)
+	useCase := userusecase.NewUserLookupUseCase(userSvc, nil)
 
 	ctx := context.Background()
 	email := "test@example.com"
@@ -32,7 +33,7 @@ func TestUserLookupUseCase_Call_Success(t *testing.T) {
 
 	mockRepo.On("FindByEmail", ctx, email).Return(expectedUser, nil)
 
-	user, err := useCase.Call(ctx, email)
+	user, err := useCase.Call(ctx, "actor-1", email)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
@@ -42,14 +43,14 @@ func TestUserLookupUseCase_Call_Success(t *testing.T) {
 func TestUserLookupUseCase_Call_UserNotFound(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	userSvc := userservice.NewService(mockRepo)
-	useCase := userusecase.NewUserLookupUseCase(userSvc)
+	useCase := userusecase.NewUserLookupUseCase(userSvc, nil)
 
 	ctx := context.Background()
 	email := "notfound@example.com"
 
 	mockRepo.On("FindByEmail", ctx, email).Return(nil, errors.New("user not found"))
 
-	user, err := useCase.Call(ctx, email)
+	user, err := useCase.Call(ctx, "actor-1", email)
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -60,17 +61,60 @@ func TestUserLookupUseCase_Call_UserNotFound(t *testing.T) {
 func TestUserLookupUseCase_Call_DatabaseError(t *testing.T) {
 	mockRepo := &mocks.MockUserRepository{}
 	userSvc := userservice.NewService(mockRepo)
-	useCase := userusecase.NewUserLookupUseCase(userSvc)
+	useCase := userusecase.NewUserLookupUseCase(userSvc, nil)
 
 	ctx := context.Background()
 	email := "test@example.com"
 
 	mockRepo.On("FindByEmail", ctx, email).Return(nil, errors.New("database connection failed"))
 
-	user, err := useCase.Call(ctx, email)
+	user, err := useCase.Call(ctx, "actor-1", email)
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
 	assert.Equal(t, "database connection failed", err.Error())
 	mockRepo.AssertExpectations(t)
 }
+
+// allowSelf is an authz.Authorizer letting users read themselves only
+type allowSelf struct {
+	requests []authz.Request
+}
+
+func (a *allowSelf) Authorize(_ context.Context, req authz.Request) (authz.Decision, error) {
+	a.requests = append(a.requests, req)
+	return authz.Decision{Policy: "self", Allowed: req.Subject.ID == req.Resource.ID}, nil
+}
+
+func TestUserLookupUseCase_Call_Authorized(t *testing.T) {
+	mockRepo := &mocks.MockUserRepository{}
+	authorizer := &allowSelf{}
+	useCase := userusecase.NewUserLookupUseCase(userservice.NewService(mockRepo), authorizer)
+
+	ctx := context.Background()
+	expectedUser := &entity.User{ID: "user-123", Email: "test@example.com", UserName: "testuser"}
+	mockRepo.On("FindByEmail", ctx, "test@example.com").Return(expectedUser, nil)
+
+	user, err := useCase.Call(ctx, "user-123", "test@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUser, user)
+	assert.Equal(t, []authz.Request{{
+		Subject:  authz.Entity{Type: "user", ID: "user-123"},
+		Action:   entity.PermissionUsersRead,
+		Resource: authz.Entity{Type: "user", ID: "user-123", Attributes: map[string]any{"email": "test@example.com"}},
+	}}, authorizer.requests)
+}
+
+func TestUserLookupUseCase_Call_DeniedLooksNotFound(t *testing.T) {
+	mockRepo := &mocks.MockUserRepository{}
+	useCase := userusecase.NewUserLookupUseCase(userservice.NewService(mockRepo), &allowSelf{})
+
+	ctx := context.Background()
+	mockRepo.On("FindByEmail", ctx, "test@example.com").Return(&entity.User{ID: "user-123", Email: "test@example.com"}, nil)
+
+	user, err := useCase.Call(ctx, "user-456", "test@example.com")
+
+	assert.ErrorIs(t, err, userservice.ErrUserNotFound)
+	assert.Nil(t, user)
+}
@@ -0,0 +1,142 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/internal/infrastructure/config"
+)
+
+func findingPaths(findings []config.Finding) []string {
+	paths := make([]string, len(findings))
+	for i, finding := range findings {
+		paths[i] = finding.Path
+	}
+	return paths
+}
+
+func TestAudit_ProductionDefaults(t *testing.T) {
+	cfg, _, err := newLoader(nil, map[string]string{"ENV": "production"}, nil).Load()
+	require.NoError(t, err)
+
+	findings := config.Audit(cfg)
+
+	assert.ElementsMatch(t, []string{
+		"security.csrf_secret",
+		"security.session_secret",
+		"database.sslmode",
+	}, findingPaths(findings))
+}
+
+func TestAudit_DevelopmentDefaults(t *testing.T) {
+	cfg, _, err := newLoader(nil, nil, nil).Load()
+	require.NoError(t, err)
+
+	findings := config.Audit(cfg)
+
+	assert.Contains(t, findingPaths(findings), "security.cookie_secure")
+	assert.Contains(t, findingPaths(findings), "server.cors_allowed_origins")
+}
+
+func TestAudit_ShortAndSharedSecrets(t *testing.T) {
+	env := map[string]string{
+		"ENV":            "production",
+		"CSRF_SECRET":    "too-short",
+		"SESSION_SECRET": "too-short",
+		"DATABASE_URL":   "postgres://app@db.internal:5432/app",
+	}
+	cfg, _, err := newLoader(nil, env, nil).Load()
+	require.NoError(t, err)
+
+	findings := config.Audit(cfg)
+
+	assert.ElementsMatch(t, []string{
+		"security.csrf_secret",
+		"security.session_secret",
+		"security.csrf_secret",
+		"database.url",
+	}, findingPaths(findings))
+}
+
+func TestAudit_SecureProduction(t *testing.T) {
+	env := map[string]string{
+		"ENV":                  "production",
+		"CSRF_SECRET":          strings.Repeat("c", 32),
+		"SESSION_SECRET":       strings.Repeat("s", 32),
+		"DATABASE_URL":         "postgres://app@db.internal:5432/app?sslmode=verify-full",
+		"CORS_ALLOWED_ORIGINS": "https://app.example.com",
+	}
+	cfg, _, err := newLoader(nil, env, nil).Load()
+	require.NoError(t, err)
+
+	assert.Empty(t, config.Audit(cfg))
+}
+
+func TestAudit_WildcardCORSAndInsecureCookies(t *testing.T) {
+	env := map[string]string{
+		"ENV":                  "production",
+		"CSRF_SECRET":          strings.Repeat("c", 32),
+		"SESSION_SECRET":       strings.Repeat("s", 32),
+		"DB_SSLMODE":           "require",
+		"COOKIE_SECURE":        "false",
+		"CORS_ALLOWED_ORIGINS": "https://app.example.com,*",
+	}
+	cfg, _, err := newLoader(nil, env, nil).Load()
+	require.NoError(t, err)
+
+	findings := config.Audit(cfg)
+
+	assert.ElementsMatch(t, []string{"security.cookie_secure", "server.cors_allowed_origins"}, findingPaths(findings))
+	assert.Contains(t, (&config.AuditError{Findings: findings}).Error(), "session cookies are sent over plain HTTP")
+}
+
+func TestAudit_PasswordlessWithoutSMTP(t *testing.T) {
+	env := map[string]string{
+		"ENV":                  "production",
+		"CSRF_SECRET":          strings.Repeat("c", 32),
+		"SESSION_SECRET":       strings.Repeat("s", 32),
+		"DB_SSLMODE":           "require",
+		"CORS_ALLOWED_ORIGINS": "https://app.example.com",
+		"PASSWORDLESS_ENABLED": "true",
+	}
+	cfg, _, err := newLoader(nil, env, nil).Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mail.smtp_host"}, findingPaths(config.Audit(cfg)))
+
+	env["SMTP_HOST"] = "smtp.example.com"
+	cfg, _, err = newLoader(nil, env, nil).Load()
+	require.NoError(t, err)
+	assert.Empty(t, config.Audit(cfg))
+}
+
+func TestLoader_InvalidMailFrom(t *testing.T) {
+	_, _, err := newLoader(nil, map[string]string{"MAIL_FROM": "not an address"}, nil).Load()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mail.from")
+}
+
+func TestLoader_InvalidCORSOrigin(t *testing.T) {
+	_, _, err := newLoader(nil, map[string]string{"CORS_ALLOWED_ORIGINS": "app.example.com"}, nil).Load()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.cors_allowed_origins")
+}
+
+func TestAudit_DatabaseConnectionString(t *testing.T) {
+	env := map[string]string{
+		"ENV":            "production",
+		"CSRF_SECRET":    strings.Repeat("c", 32),
+		"SESSION_SECRET": strings.Repeat("s", 32),
+		"DATABASE_URL":   "host=db.internal user=app dbname=app sslmode=verify-full",
+	}
+	cfg, _, err := newLoader(nil, env, nil).Load()
+	require.NoError(t, err)
+	assert.Empty(t, config.Audit(cfg))
+
+	cfg.Database.URL = "host=db.internal user=app dbname=app sslmode=prefer"
+	assert.Equal(t, []string{"database.url"}, findingPaths(config.Audit(cfg)))
+}
@@ -0,0 +1,125 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/infrastructure/database"
+	"example.com/pkg/tenant"
+)
+
+// dryRun returns a database with tenancy enabled that builds statements without running them
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, database.EnableTenancy(db))
+	return db
+}
+
+func TestEnableTenancy_ScopesQueries(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	var user entity.User
+	stmt := db.WithContext(ctx).Where("email = ?", "john@example.com").First(&user).Statement
+
+	assert.Contains(t, stmt.SQL.String(), `"users"."tenant_id" = $`)
+	assert.Contains(t, stmt.Vars, "acme")
+}
+
+func TestEnableTenancy_ScopesUpdatesAndDeletes(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	stmt := db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", "user-1").Update("user_name", "john").Statement
+	assert.Contains(t, stmt.SQL.String(), `"users"."tenant_id" = $`)
+	assert.Contains(t, stmt.Vars, "acme")
+
+	stmt = db.WithContext(ctx).Where("user_id = ?", "user-1").Delete(&entity.APIToken{}).Statement
+	assert.Contains(t, stmt.SQL.String(), `"api_tokens"."tenant_id" = $`)
+	assert.Contains(t, stmt.Vars, "acme")
+}
+
+func TestEnableTenancy_StampsCreatedRows(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	// A tenant set by the caller is overwritten, so rows cannot be planted in another tenant
+	user := &entity.User{ID: "user-1", UserName: "john", Email: "john@example.com", TenantID: "globex"}
+	require.NoError(t, db.WithContext(ctx).Create(user).Error)
+	assert.Equal(t, "acme", user.TenantID)
+
+	tokens := []*entity.RefreshToken{{ID: "token-1"}, {ID: "token-2"}}
+	require.NoError(t, db.WithContext(ctx).Create(&tokens).Error)
+	for _, token := range tokens {
+		assert.Equal(t, "acme", token.TenantID)
+	}
+}
+
+func TestEnableTenancy_ScopesRowsOfUsers(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	for _, model := range []any{&entity.UserProfile{}, &entity.LoginChallenge{}, &entity.PasswordHistory{}, &entity.UserRole{}} {
+		stmt := db.WithContext(ctx).Where("user_id = ?", "user-1").Find(model).Statement
+		assert.Contains(t, stmt.SQL.String(), `"`+stmt.Table+`"."tenant_id" = $`)
+		assert.Contains(t, stmt.Vars, "acme")
+	}
+}
+
+func TestEnableTenancy_GuardsUpserts(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	user := &entity.User{ID: "user-1", UserName: "john", Email: "john@example.com"}
+	stmt := db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(user).Statement
+
+	// The conflicting row of another tenant is left alone instead of being taken over
+	assert.Contains(t, stmt.SQL.String(), `ON CONFLICT ("id") DO UPDATE SET`)
+	assert.Contains(t, stmt.SQL.String(), `WHERE "users"."tenant_id" = $`)
+}
+
+func TestEnableTenancy_RequiresTenant(t *testing.T) {
+	db := dryRun(t)
+
+	var user entity.User
+	err := db.WithContext(context.Background()).First(&user, "id = ?", "user-1").Error
+	require.ErrorIs(t, err, database.ErrTenantRequired)
+
+	err = db.WithContext(context.Background()).Create(&entity.User{ID: "user-1"}).Error
+	require.ErrorIs(t, err, database.ErrTenantRequired)
+}
+
+func TestEnableTenancy_SkipsSharedTables(t *testing.T) {
+	db := dryRun(t)
+
+	var roles []entity.Role
+	stmt := db.WithContext(context.Background()).Find(&roles).Statement
+
+	require.NoError(t, stmt.Error)
+	assert.NotContains(t, stmt.SQL.String(), "tenant_id")
+}
+
+func TestEnableTenancy_ScopesDisjunctions(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	var user entity.User
+	stmt := db.WithContext(ctx).Where("user_name = ? OR email = ?", "john", "john").First(&user).Statement
+
+	// Without parentheses the tenant would only restrict the second alternative
+	assert.Contains(t, stmt.SQL.String(), `(user_name = $1 OR email = $2) AND "users"."tenant_id" = $3`)
+}
@@ -0,0 +1,35 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/internal/infrastructure/metrics"
+)
+
+func TestRegistry_WriteEscapesLabelValues(t *testing.T) {
+	registry := metrics.NewRegistry()
+	counter := registry.CounterVec("requests_total", "Requests.", 10, "client")
+	counter.Inc("curl/8.0")
+	counter.Inc("evil\"\\\nclient\x01é")
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.Write(&buf))
+
+	// Only backslashes, quotes and newlines are escaped; Go escapes like \x01 would break scrapes
+	assert.Equal(t, "# HELP requests_total Requests.\n# TYPE requests_total counter\n"+
+		"requests_total{client=\"curl/8.0\"} 1\n"+
+		"requests_total{client=\"evil\\\"\\\\\\nclient\x01é\"} 1\n", buf.String())
+}
+
+func TestCounterVec_MaxSeries(t *testing.T) {
+	counter := metrics.NewRegistry().CounterVec("requests_total", "Requests.", 1, "client")
+	counter.Inc("a")
+	counter.Inc("b")
+
+	assert.Equal(t, uint64(1), counter.Value("a"))
+	assert.Equal(t, uint64(1), counter.Value("other"))
+}
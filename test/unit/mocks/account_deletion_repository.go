@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockAccountDeletionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountDeletionRepository) Save(ctx context.Context, deletion *entity.AccountDeletion) error {
+	args := m.Called(ctx, deletion)
+	return args.Error(0)
+}
+
+func (m *MockAccountDeletionRepository) FindByUserID(ctx context.Context, userID string) (*entity.AccountDeletion, error) {
+	args := m.Called(ctx, userID)
+	if deletion := args.Get(0); deletion != nil {
+		return deletion.(*entity.AccountDeletion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAccountDeletionRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*entity.AccountDeletion, error) {
+	args := m.Called(ctx, before, limit)
+	if deletions := args.Get(0); deletions != nil {
+		return deletions.([]*entity.AccountDeletion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAccountDeletionRepository) Delete(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockAPITokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPITokenRepository) Create(ctx context.Context, token *entity.APIToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.APIToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if token := args.Get(0); token != nil {
+		return token.(*entity.APIToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPITokenRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.APIToken, error) {
+	args := m.Called(ctx, userID)
+	if tokens := args.Get(0); tokens != nil {
+		return tokens.([]*entity.APIToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPITokenRepository) Revoke(ctx context.Context, userID, id string, at time.Time) error {
+	args := m.Called(ctx, userID, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepository) RevokeUser(ctx context.Context, userID string, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepository) UpdateLastUsed(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockDataExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockDataExportRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	args := m.Called(ctx, export)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepository) Update(ctx context.Context, export *entity.DataExport) error {
+	args := m.Called(ctx, export)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepository) FindLatest(ctx context.Context, userID string) (*entity.DataExport, error) {
+	args := m.Called(ctx, userID)
+	if export := args.Get(0); export != nil {
+		return export.(*entity.DataExport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDataExportRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.DataExport, error) {
+	args := m.Called(ctx, tokenHash)
+	if export := args.Get(0); export != nil {
+		return export.(*entity.DataExport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDataExportRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.DataExport, error) {
+	args := m.Called(ctx, userID)
+	if exports := args.Get(0); exports != nil {
+		return exports.([]*entity.DataExport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDataExportRepository) ListPending(ctx context.Context, limit int) ([]*entity.DataExport, error) {
+	args := m.Called(ctx, limit)
+	if exports := args.Get(0); exports != nil {
+		return exports.([]*entity.DataExport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDataExportRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entity.DataExport, error) {
+	args := m.Called(ctx, before, limit)
+	if exports := args.Get(0); exports != nil {
+		return exports.([]*entity.DataExport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDataExportRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockEmailChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailChangeRepository) Create(ctx context.Context, change *entity.EmailChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRepository) FindByConfirmHash(ctx context.Context, confirmHash string) (*entity.EmailChange, error) {
+	args := m.Called(ctx, confirmHash)
+	if change := args.Get(0); change != nil {
+		return change.(*entity.EmailChange), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEmailChangeRepository) FindByRevertHash(ctx context.Context, revertHash string) (*entity.EmailChange, error) {
+	args := m.Called(ctx, revertHash)
+	if change := args.Get(0); change != nil {
+		return change.(*entity.EmailChange), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEmailChangeRepository) Confirm(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRepository) Revert(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRepository) RevertPending(ctx context.Context, userID string, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
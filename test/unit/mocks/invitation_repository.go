@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockInvitationRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvitationRepository) Create(ctx context.Context, invitation *entity.Invitation) error {
+	args := m.Called(ctx, invitation)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) FindByID(ctx context.Context, id string) (*entity.Invitation, error) {
+	args := m.Called(ctx, id)
+	if invitation := args.Get(0); invitation != nil {
+		return invitation.(*entity.Invitation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error) {
+	args := m.Called(ctx, tokenHash)
+	if invitation := args.Get(0); invitation != nil {
+		return invitation.(*entity.Invitation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInvitationRepository) ListPending(ctx context.Context, organizationID string, now time.Time) ([]*entity.Invitation, error) {
+	args := m.Called(ctx, organizationID, now)
+	if invitations := args.Get(0); invitations != nil {
+		return invitations.([]*entity.Invitation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInvitationRepository) Accept(ctx context.Context, id string, membership *entity.Membership, at time.Time) error {
+	args := m.Called(ctx, id, membership, at)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) Respond(ctx context.Context, id, status string, at time.Time) error {
+	args := m.Called(ctx, id, status, at)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) RevokePending(ctx context.Context, organizationID, email string, at time.Time) error {
+	args := m.Called(ctx, organizationID, email, at)
+	return args.Error(0)
+}
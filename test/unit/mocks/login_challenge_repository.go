@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockLoginChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginChallengeRepository) Create(ctx context.Context, challenge *entity.LoginChallenge) error {
+	args := m.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockLoginChallengeRepository) FindByID(ctx context.Context, id string) (*entity.LoginChallenge, error) {
+	args := m.Called(ctx, id)
+	if challenge := args.Get(0); challenge != nil {
+		return challenge.(*entity.LoginChallenge), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLoginChallengeRepository) CountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLoginChallengeRepository) RecordFailedAttempt(ctx context.Context, id string, maxAttempts int) error {
+	args := m.Called(ctx, id, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MockLoginChallengeRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockLoginChallengeRepository) MarkPendingUsed(ctx context.Context, userID string, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
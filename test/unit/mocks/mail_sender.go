@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/pkg/mail"
+)
+
+type MockMailSender struct {
+	mock.Mock
+}
+
+func (m *MockMailSender) Send(ctx context.Context, msg mail.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
@@ -0,0 +1,154 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
+	"example.com/pkg/security"
+)
+
+// MemoryUserRepository keeps users in memory for integration tests that follow a user through
+// several requests. It enforces the unique emails and user names of the database and hands out
+// copies, so that changes only stick once saved. Deleted users are forgotten at once, so there
+// is nothing to restore or purge, and List is not served.
+type MemoryUserRepository struct {
+	users map[string]*entity.User
+	mu    sync.Mutex
+}
+
+// NewMemoryUserRepository returns a repository holding copies of users
+func NewMemoryUserRepository(users ...*entity.User) *MemoryUserRepository {
+	r := &MemoryUserRepository{users: map[string]*entity.User{}}
+	for _, user := range users {
+		stored := *user
+		r.users[user.ID] = &stored
+	}
+	return r
+}
+
+// Len returns the number of users
+func (r *MemoryUserRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.users)
+}
+
+func (r *MemoryUserRepository) Create(_ context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, other := range r.users {
+		if other.Email == user.Email || other.UserName == user.UserName {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+func (r *MemoryUserRepository) FindByID(_ context.Context, id string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return u.ID == id })
+}
+
+func (r *MemoryUserRepository) FindByEmail(_ context.Context, email string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return u.Email == email })
+}
+
+func (r *MemoryUserRepository) FindByUserName(_ context.Context, userName string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return u.UserName == userName })
+}
+
+func (r *MemoryUserRepository) FindByUserNameSkeleton(_ context.Context, skeleton string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return security.UsernameSkeleton(u.UserName) == skeleton })
+}
+
+func (r *MemoryUserRepository) FindByUserNameOrEmail(_ context.Context, identifier string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return u.UserName == identifier || u.Email == identifier })
+}
+
+func (r *MemoryUserRepository) List(_ context.Context, _ repository.UserQuery) ([]*entity.User, error) {
+	return nil, nil
+}
+
+func (r *MemoryUserRepository) Update(_ context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+func (r *MemoryUserRepository) ChangeEmail(_ context.Context, id, from, to string, verifiedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, other := range r.users {
+		if other.Email == to {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	user, ok := r.users[id]
+	if !ok || user.Email != from {
+		return gorm.ErrRecordNotFound
+	}
+	user.Email = to
+	user.EmailVerifiedAt = &verifiedAt
+	return nil
+}
+
+func (r *MemoryUserRepository) ChangeUserName(_ context.Context, id, from, to string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, other := range r.users {
+		if other.UserName == to {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	user, ok := r.users[id]
+	if !ok || user.UserName != from {
+		return gorm.ErrRecordNotFound
+	}
+	user.UserName = to
+	return nil
+}
+
+func (r *MemoryUserRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+func (r *MemoryUserRepository) FindDeleted(_ context.Context, _ string) (*entity.User, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *MemoryUserRepository) Restore(_ context.Context, _ string) error {
+	return gorm.ErrRecordNotFound
+}
+
+func (r *MemoryUserRepository) ListPurgeable(_ context.Context, _ time.Time, _ int) ([]*entity.User, error) {
+	return nil, nil
+}
+
+func (r *MemoryUserRepository) Purge(_ context.Context, _ string, _ time.Time) error {
+	return gorm.ErrRecordNotFound
+}
+
+func (r *MemoryUserRepository) find(match func(*entity.User) bool) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if match(user) {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
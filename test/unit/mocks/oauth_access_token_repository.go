@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockOAuthAccessTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthAccessTokenRepository) Create(ctx context.Context, token *entity.OAuthAccessToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockOAuthAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.OAuthAccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if token := args.Get(0); token != nil {
+		return token.(*entity.OAuthAccessToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthAccessTokenRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockOAuthAccessTokenRepository) RevokeByCode(ctx context.Context, codeID string, at time.Time) error {
+	args := m.Called(ctx, codeID, at)
+	return args.Error(0)
+}
+
+func (m *MockOAuthAccessTokenRepository) RevokeUser(ctx context.Context, userID string, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
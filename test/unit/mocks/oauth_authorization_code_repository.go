@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockOAuthAuthorizationCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthAuthorizationCodeRepository) Create(ctx context.Context, code *entity.OAuthAuthorizationCode) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func (m *MockOAuthAuthorizationCodeRepository) FindByHash(ctx context.Context, codeHash string) (*entity.OAuthAuthorizationCode, error) {
+	args := m.Called(ctx, codeHash)
+	if code := args.Get(0); code != nil {
+		return code.(*entity.OAuthAuthorizationCode), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthAuthorizationCodeRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockOAuthAuthorizationCodeRepository) RevokeUser(ctx context.Context, userID string, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
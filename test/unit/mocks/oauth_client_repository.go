@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockOAuthClientRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthClientRepository) Create(ctx context.Context, client *entity.OAuthClient) error {
+	args := m.Called(ctx, client)
+	return args.Error(0)
+}
+
+func (m *MockOAuthClientRepository) FindByID(ctx context.Context, id string) (*entity.OAuthClient, error) {
+	args := m.Called(ctx, id)
+	if client := args.Get(0); client != nil {
+		return client.(*entity.OAuthClient), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) FindByOwnerID(ctx context.Context, ownerID string) ([]*entity.OAuthClient, error) {
+	args := m.Called(ctx, ownerID)
+	if clients := args.Get(0); clients != nil {
+		return clients.([]*entity.OAuthClient), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) Delete(ctx context.Context, ownerID, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockOAuthConsentRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthConsentRepository) Find(ctx context.Context, userID, clientID string) (*entity.OAuthConsent, error) {
+	args := m.Called(ctx, userID, clientID)
+	if consent := args.Get(0); consent != nil {
+		return consent.(*entity.OAuthConsent), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOAuthConsentRepository) Save(ctx context.Context, consent *entity.OAuthConsent) error {
+	args := m.Called(ctx, consent)
+	return args.Error(0)
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, organization *entity.Organization, owner *entity.Membership) error {
+	args := m.Called(ctx, organization, owner)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) FindByID(ctx context.Context, id string) (*entity.Organization, error) {
+	args := m.Called(ctx, id)
+	if organization := args.Get(0); organization != nil {
+		return organization.(*entity.Organization), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrganizationRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.Membership, error) {
+	args := m.Called(ctx, userID)
+	if memberships := args.Get(0); memberships != nil {
+		return memberships.([]*entity.Membership), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindMembership(ctx context.Context, organizationID, userID string) (*entity.Membership, error) {
+	args := m.Called(ctx, organizationID, userID)
+	if membership := args.Get(0); membership != nil {
+		return membership.(*entity.Membership), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrganizationRepository) ListMembers(ctx context.Context, organizationID string) ([]*entity.Membership, error) {
+	args := m.Called(ctx, organizationID)
+	if memberships := args.Get(0); memberships != nil {
+		return memberships.([]*entity.Membership), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	args := m.Called(ctx, organizationID, userID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) TransferOwnership(ctx context.Context, organizationID, ownerID, newOwnerID string) error {
+	args := m.Called(ctx, organizationID, ownerID, newOwnerID)
+	return args.Error(0)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"example.com/pkg/mail"
+)
+
+// Outbox is a mail.Sender recording the emails that would have been sent, for integration
+// tests that follow the links and codes they carry
+type Outbox struct {
+	messages []mail.Message
+	mu       sync.Mutex
+}
+
+func (o *Outbox) Send(_ context.Context, msg mail.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+// Len returns the number of emails sent
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.messages)
+}
+
+// Last returns the last email sent
+func (o *Outbox) Last() mail.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.messages[len(o.messages)-1]
+}
+
+// LastTo returns the last email sent to the address
+func (o *Outbox) LastTo(to string) mail.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		if o.messages[i].To == to {
+			return o.messages[i]
+		}
+	}
+	return mail.Message{}
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordHistoryRepository) Create(ctx context.Context, entry *entity.PasswordHistory) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockPasswordHistoryRepository) ListRecent(ctx context.Context, userID string, limit int) ([]*entity.PasswordHistory, error) {
+	args := m.Called(ctx, userID, limit)
+	if entries := args.Get(0); entries != nil {
+		return entries.([]*entity.PasswordHistory), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPasswordHistoryRepository) Prune(ctx context.Context, userID string, keep int) error {
+	args := m.Called(ctx, userID, keep)
+	return args.Error(0)
+}
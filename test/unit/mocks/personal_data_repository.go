@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockPersonalDataRepository struct {
+	mock.Mock
+}
+
+func (m *MockPersonalDataRepository) Collect(ctx context.Context, userID string) (*entity.PersonalData, error) {
+	args := m.Called(ctx, userID)
+	if data := args.Get(0); data != nil {
+		return data.(*entity.PersonalData), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) SavePermissions(ctx context.Context, permissions []entity.Permission) error {
+	args := m.Called(ctx, permissions)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Save(ctx context.Context, role *entity.Role) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) FindByName(ctx context.Context, name string) (*entity.Role, error) {
+	args := m.Called(ctx, name)
+	if role := args.Get(0); role != nil {
+		return role.(*entity.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRoleRepository) List(ctx context.Context) ([]*entity.Role, error) {
+	args := m.Called(ctx)
+	if roles := args.Get(0); roles != nil {
+		return roles.([]*entity.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Role, error) {
+	args := m.Called(ctx, userID)
+	if roles := args.Get(0); roles != nil {
+		return roles.([]*entity.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRoleRepository) Assign(ctx context.Context, userID, roleID string) error {
+	args := m.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Unassign(ctx context.Context, userID, roleID string) error {
+	args := m.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) UserHasPermission(ctx context.Context, userID, permission string) (bool, error) {
+	args := m.Called(ctx, userID, permission)
+	return args.Bool(0), args.Error(1)
+}
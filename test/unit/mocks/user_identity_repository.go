@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockUserIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *MockUserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	args := m.Called(ctx, provider, subject)
+	if identity := args.Get(0); identity != nil {
+		return identity.(*entity.UserIdentity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.UserIdentity, error) {
+	args := m.Called(ctx, userID)
+	if identities := args.Get(0); identities != nil {
+		return identities.([]*entity.UserIdentity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockUserIdentityRepository) Delete(ctx context.Context, userID, id string) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
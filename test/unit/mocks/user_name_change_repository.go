@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockUserNameChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserNameChangeRepository) Create(ctx context.Context, change *entity.UserNameChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockUserNameChangeRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.UserNameChange, error) {
+	args := m.Called(ctx, userID)
+	if changes := args.Get(0); changes != nil {
+		return changes.([]*entity.UserNameChange), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserNameChangeRepository) FindRedirect(
+	ctx context.Context, oldSkeleton string, now time.Time,
+) (*entity.UserNameChange, error) {
+	args := m.Called(ctx, oldSkeleton, now)
+	if change := args.Get(0); change != nil {
+		return change.(*entity.UserNameChange), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
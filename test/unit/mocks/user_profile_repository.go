@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"example.com/internal/domain/entity"
+)
+
+type MockUserProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserProfileRepository) FindByUserID(ctx context.Context, userID string) (*entity.UserProfile, error) {
+	args := m.Called(ctx, userID)
+	if profile := args.Get(0); profile != nil {
+		return profile.(*entity.UserProfile), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserProfileRepository) Save(ctx context.Context, profile *entity.UserProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
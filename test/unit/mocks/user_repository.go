@@ -2,10 +2,12 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
 	"example.com/internal/domain/entity"
+	"example.com/internal/domain/repository"
 )
 
 type MockUserRepository struct {
@@ -41,6 +43,14 @@ func (m *MockUserRepository) FindByUserName(ctx context.Context, userName string
 	return nil, args.Error(1)
 }
 
+func (m *MockUserRepository) FindByUserNameSkeleton(ctx context.Context, skeleton string) (*entity.User, error) {
+	args := m.Called(ctx, skeleton)
+	if user := args.Get(0); user != nil {
+		return user.(*entity.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockUserRepository) FindByUserNameOrEmail(ctx context.Context, identifier string) (*entity.User, error) {
 	args := m.Called(ctx, identifier)
 	if user := args.Get(0); user != nil {
@@ -49,6 +59,14 @@ func (m *MockUserRepository) FindByUserNameOrEmail(ctx context.Context, identifi
 	return nil, args.Error(1)
 }
 
+func (m *MockUserRepository) List(ctx context.Context, query repository.UserQuery) ([]*entity.User, error) {
+	args := m.Called(ctx, query)
+	if users := args.Get(0); users != nil {
+		return users.([]*entity.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
@@ -58,3 +76,39 @@ func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockUserRepository) FindDeleted(ctx context.Context, id string) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if user := args.Get(0); user != nil {
+		return user.(*entity.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]*entity.User, error) {
+	args := m.Called(ctx, deletedBefore, limit)
+	if users := args.Get(0); users != nil {
+		return users.([]*entity.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) ChangeEmail(ctx context.Context, id, from, to string, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, from, to, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ChangeUserName(ctx context.Context, id, from, to string) error {
+	args := m.Called(ctx, id, from, to)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Purge(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
@@ -0,0 +1,177 @@
+package authz_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/authz"
+	"example.com/pkg/authz/authztest"
+)
+
+const ownerPolicies = `
+policies:
+  - name: owners
+    effect: allow
+    actions: [documents:edit]
+    resources: [document]
+    conditions: ['subject.id == resource.owner']
+  - name: editors
+    effect: allow
+    actions: [documents:edit]
+    resources: [document]
+    conditions: ['"editor" in subject.roles']
+`
+
+// recorder is an authz.DecisionLogger keeping the decisions
+type recorder struct {
+	decisions []authz.Decision
+}
+
+func (r *recorder) LogDecision(_ context.Context, _ authz.Request, decision authz.Decision) {
+	r.decisions = append(r.decisions, decision)
+}
+
+func TestAuthorizer_Attributes(t *testing.T) {
+	var loadedFor []string
+	authorizer := authz.NewAuthorizer(parse(t, ownerPolicies), authz.Config{
+		Attributes: func(_ context.Context, subject authz.Entity) (map[string]any, error) {
+			loadedFor = append(loadedFor, subject.ID)
+			return map[string]any{"roles": []string{"editor"}}, nil
+		},
+	})
+	document := authz.Entity{Type: "document", ID: "doc-1", Attributes: map[string]any{"owner": "user-2"}}
+
+	decision, err := authorizer.Authorize(context.Background(), authz.Request{
+		Subject: authz.Entity{Type: "user", ID: "user-1"}, Action: "documents:edit", Resource: document,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, authz.Decision{Policy: "editors", Allowed: true}, decision)
+	assert.Equal(t, []string{"user-1"}, loadedFor)
+
+	decision, err = authorizer.Authorize(context.Background(), authz.Request{
+		Subject: authz.Entity{Type: "user", ID: "user-1", Attributes: map[string]any{"roles": []string{}}},
+		Action:  "documents:edit", Resource: document,
+	})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "attributes of the request take precedence over loaded ones")
+}
+
+func TestAuthorizer_ResourceAttributes(t *testing.T) {
+	authorizer := authz.NewAuthorizer(parse(t, ownerPolicies), authz.Config{
+		Resources: func(context.Context, authz.Entity) (map[string]any, error) {
+			return map[string]any{"owner": "user-1"}, nil
+		},
+	})
+	subject := authz.Entity{Type: "user", ID: "user-1"}
+
+	decision, err := authorizer.Authorize(context.Background(), authz.Request{
+		Subject: subject, Action: "documents:edit", Resource: authz.Entity{Type: "document", ID: "doc-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, authz.Decision{Policy: "owners", Allowed: true}, decision)
+
+	decision, err = authorizer.Authorize(context.Background(), authz.Request{
+		Subject: subject, Action: "documents:edit",
+		Resource: authz.Entity{Type: "document", ID: "doc-1", Attributes: map[string]any{"owner": "user-2"}},
+	})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "attributes of the request take precedence over loaded ones")
+}
+
+func TestAuthorizer_AttributesError(t *testing.T) {
+	failure := errors.New("database unavailable")
+	log := &recorder{}
+	authorizer := authz.NewAuthorizer(parse(t, ownerPolicies), authz.Config{
+		Attributes: func(context.Context, authz.Entity) (map[string]any, error) { return nil, failure },
+		Log:        log,
+	})
+
+	err := authz.Enforce(context.Background(), authorizer, authz.Request{Action: "documents:edit"})
+
+	assert.ErrorIs(t, err, failure)
+	assert.NotErrorIs(t, err, authz.ErrDenied)
+	assert.Empty(t, log.decisions, "no decision was made")
+}
+
+func TestEnforce(t *testing.T) {
+	log := &recorder{}
+	authorizer := authz.NewAuthorizer(parse(t, ownerPolicies), authz.Config{Log: log})
+	req := authz.Request{
+		Subject:  authz.Entity{Type: "user", ID: "user-1"},
+		Action:   "documents:edit",
+		Resource: authz.Entity{Type: "document", ID: "doc-1", Attributes: map[string]any{"owner": "user-1"}},
+	}
+
+	assert.NoError(t, authz.Enforce(context.Background(), authorizer, req))
+	req.Resource.Attributes["owner"] = "user-2"
+	assert.ErrorIs(t, authz.Enforce(context.Background(), authorizer, req), authz.ErrDenied)
+
+	assert.Equal(t, []authz.Decision{{Policy: "owners", Allowed: true}, {}}, log.decisions)
+}
+
+// logger is an authz.Logger keeping the entries
+type logger struct {
+	entries []string
+}
+
+func (l *logger) Info(msg string, args ...any) {
+	l.entries = append(l.entries, fmt.Sprint(append([]any{msg}, args...)...))
+}
+
+func TestLogDecisions(t *testing.T) {
+	l := &logger{}
+
+	authz.LogDecisions(l).LogDecision(context.Background(), authz.Request{
+		Subject:  authz.Entity{Type: "user", ID: "user-1"},
+		Action:   "documents:edit",
+		Resource: authz.Entity{Type: "document", ID: "doc-1"},
+	}, authz.Decision{Policy: "owners", Allowed: true})
+
+	assert.Equal(t, []string{fmt.Sprint("Authorization decision", "allowed", true, "policy", "owners",
+		"subject_type", "user", "subject_id", "user-1", "action", "documents:edit",
+		"resource_type", "document", "resource_id", "doc-1")}, l.entries)
+}
+
+// failures is a testing.TB recording the failures of an assertion
+type failures struct {
+	testing.TB
+	messages []string
+}
+
+func (f *failures) Helper() {}
+
+func (f *failures) Errorf(format string, args ...any) {
+	f.messages = append(f.messages, fmt.Sprintf(format, args...))
+}
+
+func TestAssertMatrix(t *testing.T) {
+	authorizer := authz.NewAuthorizer(parse(t, ownerPolicies), authz.Config{})
+	matrix := authztest.Matrix{
+		Subjects: map[string]authz.Entity{
+			"owner":  {Type: "user", ID: "user-1"},
+			"editor": {Type: "user", ID: "user-2", Attributes: map[string]any{"roles": []string{"editor"}}},
+			"guest":  {Type: "user", ID: "user-3"},
+		},
+		Resources: map[string]authz.Entity{
+			"doc": {Type: "document", ID: "doc-1", Attributes: map[string]any{"owner": "user-1"}},
+		},
+		Action:  "documents:edit",
+		Allowed: []string{"owner -> doc", "editor -> doc"},
+	}
+	authztest.AssertMatrix(t, authorizer, matrix)
+
+	matrix.Allowed = []string{"owner -> doc", "guest -> doc", "owner -> nothing"}
+	f := &failures{TB: t}
+	authztest.AssertMatrix(f, authorizer, matrix)
+
+	assert.Equal(t, []string{
+		`documents:edit editor -> doc: allowed = true, want false (policy "editors")`,
+		`documents:edit guest -> doc: allowed = false, want true (policy "")`,
+		`documents:edit owner -> nothing: allowed pair names an unknown subject or resource`,
+	}, f.messages)
+}
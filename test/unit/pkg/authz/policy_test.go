@@ -0,0 +1,154 @@
+package authz_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/authz"
+)
+
+func parse(t *testing.T, document string) *authz.PolicySet {
+	t.Helper()
+	policies, err := authz.ParsePolicies(strings.NewReader(document))
+	require.NoError(t, err)
+	return policies
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies := parse(t, `
+policies:
+  - name: read-own-user
+    description: Users may read their own account
+    effect: allow
+    actions: [users:read]
+    resources: [user]
+    conditions:
+      - subject.id == resource.id
+`)
+
+	require.Len(t, policies.Policies(), 1)
+	policy := policies.Policies()[0]
+	assert.Equal(t, "read-own-user", policy.Name)
+	assert.Equal(t, authz.Allow, policy.Effect)
+	assert.Equal(t, []string{"subject.id == resource.id"}, policy.Conditions)
+
+	assert.Empty(t, parse(t, "").Policies(), "an empty document has no policies")
+}
+
+func TestParsePolicies_Invalid(t *testing.T) {
+	// valid is a policy the cases append a field to
+	const valid = "policies:\n  - name: a\n    effect: allow\n    actions: ['*']\n    resources: ['*']\n"
+
+	tests := map[string]string{
+		"syntax":           "policies: [",
+		"unknown field":    valid + "    subjects: [user]\n",
+		"missing name":     "policies:\n  - effect: allow\n    actions: ['*']\n    resources: ['*']\n",
+		"unknown effect":   "policies:\n  - name: a\n    effect: permit\n    actions: ['*']\n    resources: ['*']\n",
+		"missing actions":  "policies:\n  - name: a\n    effect: allow\n    resources: ['*']\n",
+		"missing resource": "policies:\n  - name: a\n    effect: allow\n    actions: ['*']\n",
+		"operator":         valid + "    conditions: ['subject.id > 1']\n",
+		"operand":          valid + "    conditions: ['user.id == 1']\n",
+		"string":           valid + "    conditions: ['subject.id == \"1']\n",
+		"arity":            valid + "    conditions: ['subject.admin']\n",
+		"duplicate name":   valid + "  - name: a\n    effect: deny\n    actions: ['*']\n    resources: ['*']\n",
+	}
+
+	for name, document := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := authz.ParsePolicies(strings.NewReader(document))
+			assert.ErrorIs(t, err, authz.ErrInvalidPolicy)
+		})
+	}
+}
+
+func TestPolicySet_Conditions(t *testing.T) {
+	req := authz.Request{
+		Subject: authz.Entity{Type: "user", ID: "user-1", Attributes: map[string]any{
+			"roles":  []string{"support", "vip"},
+			"level":  3,
+			"active": true,
+			"org":    map[string]any{"id": "org-1"},
+		}},
+		Action: "users:read",
+		Resource: authz.Entity{Type: "user", ID: "user-2", Attributes: map[string]any{
+			"tags":   []any{"vip", "eu"},
+			"org_id": "org-1",
+		}},
+	}
+
+	tests := map[string]bool{
+		`subject.id == "user-1"`:                 true,
+		`subject.id == resource.id`:              false,
+		`subject.id != resource.id`:              true,
+		`resource.type == "user"`:                true,
+		`action == "users:read"`:                 true,
+		`subject.level == 3`:                     true,
+		`subject.active == true`:                 true,
+		`"support" in subject.roles`:             true,
+		`"admin" in subject.roles`:               false,
+		`resource.tags contains "eu"`:            true,
+		`subject.roles intersects resource.tags`: true,
+		`subject.org.id == resource.org_id`:      true,
+		`subject.missing != "x"`:                 false,
+		`subject.org.missing == resource.id`:     false,
+		`subject.level in subject.roles`:         false,
+	}
+
+	for condition, want := range tests {
+		t.Run(condition, func(t *testing.T) {
+			policies := parse(t, "policies:\n  - name: test\n    effect: allow\n    actions: ['*']\n    resources: ['*']\n"+
+				"    conditions: ['"+condition+"']\n")
+
+			assert.Equal(t, want, policies.Evaluate(req).Allowed)
+		})
+	}
+}
+
+func TestPolicySet_Evaluate(t *testing.T) {
+	policies := parse(t, `
+policies:
+  - name: readers
+    effect: allow
+    actions: [users:read, users:list]
+    resources: [user]
+    conditions: ['"reader" in subject.roles']
+  - name: everything-for-admins
+    effect: allow
+    actions: ['*']
+    resources: ['*']
+    conditions: ['"admin" in subject.roles']
+  - name: locked-accounts
+    effect: deny
+    actions: ['*']
+    resources: [user]
+    conditions: ['resource.locked == true']
+`)
+	reader := authz.Entity{ID: "user-1", Attributes: map[string]any{"roles": []string{"reader"}}}
+	admin := authz.Entity{ID: "user-2", Attributes: map[string]any{"roles": []string{"admin", "reader"}}}
+	account := authz.Entity{Type: "user", ID: "user-3"}
+	locked := authz.Entity{Type: "user", ID: "user-4", Attributes: map[string]any{"locked": true}}
+
+	tests := []struct {
+		name string
+		req  authz.Request
+		want authz.Decision
+	}{
+		{"first allow policy", authz.Request{Subject: admin, Action: "users:read", Resource: account},
+			authz.Decision{Policy: "readers", Allowed: true}},
+		{"wildcards", authz.Request{Subject: admin, Action: "users:delete", Resource: authz.Entity{Type: "role"}},
+			authz.Decision{Policy: "everything-for-admins", Allowed: true}},
+		{"deny overrides allow", authz.Request{Subject: admin, Action: "users:read", Resource: locked},
+			authz.Decision{Policy: "locked-accounts"}},
+		{"denied by default", authz.Request{Subject: reader, Action: "users:delete", Resource: account},
+			authz.Decision{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policies.Evaluate(tt.req))
+		})
+	}
+}
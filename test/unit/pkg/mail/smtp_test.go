@@ -0,0 +1,148 @@
+package mail_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/mail"
+)
+
+// envelope is what the fake server received for one message
+type envelope struct {
+	from string
+	to   string
+	data string
+}
+
+// fakeSMTPServer accepts one plain text session without extensions and reports the message
+func fakeSMTPServer(t *testing.T) (host string, port int, received <-chan envelope) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	ch := make(chan envelope, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		var msg envelope
+		_ = text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case verb == "EHLO" || verb == "HELO":
+				_ = text.PrintfLine("250 localhost")
+			case strings.HasPrefix(line, "MAIL FROM:"):
+				msg.from = strings.TrimPrefix(line, "MAIL FROM:")
+				_ = text.PrintfLine("250 OK")
+			case strings.HasPrefix(line, "RCPT TO:"):
+				msg.to = strings.TrimPrefix(line, "RCPT TO:")
+				_ = text.PrintfLine("250 OK")
+			case verb == "DATA":
+				_ = text.PrintfLine("354 go ahead")
+				data, err := io.ReadAll(text.DotReader())
+				if err != nil {
+					return
+				}
+				msg.data = string(data)
+				_ = text.PrintfLine("250 queued")
+			case verb == "QUIT":
+				_ = text.PrintfLine("221 bye")
+				ch <- msg
+				return
+			default:
+				_ = text.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	sender, err := mail.NewSMTPSender(mail.SMTPConfig{Host: host, Port: port, From: "App <no-reply@example.com>"})
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), mail.Message{
+		To:      "jane@example.com",
+		Subject: "Your sign-in code ✓",
+		Body:    "Your sign-in code is 123456\n\nThanks",
+	})
+	require.NoError(t, err)
+
+	msg := <-received
+	assert.Equal(t, "<no-reply@example.com>", msg.from)
+	assert.Equal(t, "<jane@example.com>", msg.to)
+
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(msg.data)))
+	header, err := reader.ReadMIMEHeader()
+	require.NoError(t, err)
+	assert.Equal(t, `"App" <no-reply@example.com>`, header.Get("From"))
+	assert.Equal(t, "=?utf-8?q?Your_sign-in_code_=E2=9C=93?=", header.Get("Subject"))
+	assert.Equal(t, "quoted-printable", header.Get("Content-Transfer-Encoding"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(reader.R))
+	require.NoError(t, err)
+	// The server reads the data with normalized line breaks
+	assert.Equal(t, "Your sign-in code is 123456\n\nThanks\n", string(body))
+}
+
+func TestSMTPSender_RejectsHeaderInjection(t *testing.T) {
+	sender, err := mail.NewSMTPSender(mail.SMTPConfig{Host: "127.0.0.1", Port: 1, From: "no-reply@example.com"})
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), mail.Message{To: "jane@example.com", Subject: "Hi\r\nBcc: all@example.com"})
+	assert.ErrorIs(t, err, mail.ErrInvalidMessage)
+
+	err = sender.Send(context.Background(), mail.Message{To: "jane@example.com\nBcc: all@example.com", Subject: "Hi"})
+	assert.ErrorIs(t, err, mail.ErrInvalidMessage)
+}
+
+func TestNewSMTPSender_Validation(t *testing.T) {
+	_, err := mail.NewSMTPSender(mail.SMTPConfig{Port: 587, From: "no-reply@example.com"})
+	assert.Error(t, err)
+
+	_, err = mail.NewSMTPSender(mail.SMTPConfig{Host: "smtp.example.com", Port: 587, From: "not an address"})
+	assert.Error(t, err)
+}
+
+// logRecorder is a mail.Logger remembering its last entry
+type logRecorder struct {
+	msg  string
+	args []any
+}
+
+func (l *logRecorder) Info(msg string, args ...any) {
+	l.msg, l.args = msg, args
+}
+
+func TestLogSender_Send(t *testing.T) {
+	log := &logRecorder{}
+	sender := mail.NewLogSender(log)
+
+	err := sender.Send(context.Background(), mail.Message{To: "jane@example.com", Subject: "Hi", Body: "code 123456"})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, log.msg)
+	assert.Contains(t, log.args, "jane@example.com")
+	assert.Contains(t, log.args, "code 123456")
+}
@@ -0,0 +1,114 @@
+package security_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/security"
+)
+
+// writeDataset builds a dataset of the given passwords
+func writeDataset(t *testing.T, passwords ...string) []byte {
+	digests := make([][sha1.Size]byte, 0, len(passwords))
+	for _, password := range passwords {
+		digests = append(digests, sha1.Sum([]byte(password)))
+	}
+	slices.SortFunc(digests, func(a, b [sha1.Size]byte) int { return bytes.Compare(a[:], b[:]) })
+
+	var buf bytes.Buffer
+	w, err := security.NewBreachDatasetWriter(&buf)
+	require.NoError(t, err)
+	for _, digest := range digests {
+		require.NoError(t, w.Add(digest))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestBreachDataset_Breached(t *testing.T) {
+	data := writeDataset(t, "hunter2", "correct horse", "letmein", "letmein")
+	dataset, err := security.NewBreachDataset(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, dataset.Len(), "duplicates are stored once")
+	for _, password := range []string{"hunter2", "correct horse", "letmein"} {
+		breached, err := dataset.Breached(context.Background(), password)
+		require.NoError(t, err)
+		assert.True(t, breached, password)
+	}
+	for _, password := range []string{"hunter3", "", "Letmein"} {
+		breached, err := dataset.Breached(context.Background(), password)
+		require.NoError(t, err)
+		assert.False(t, breached, password)
+	}
+}
+
+func TestBreachDataset_SamePrefix(t *testing.T) {
+	// Digests sharing the 5 digit prefix are told apart by the stored bytes
+	var a, b, c [sha1.Size]byte
+	a[2], a[5] = 0x0f, 1
+	b[2], b[5] = 0x0f, 2
+	c[2], c[5] = 0x0f, 3
+
+	var buf bytes.Buffer
+	w, err := security.NewBreachDatasetWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Add(a))
+	require.NoError(t, w.Add(c))
+	require.NoError(t, w.Close())
+	dataset, err := security.NewBreachDataset(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	found, err := dataset.Contains(a)
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = dataset.Contains(b)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestBreachDatasetWriter_RequiresOrder(t *testing.T) {
+	w, err := security.NewBreachDatasetWriter(&bytes.Buffer{})
+	require.NoError(t, err)
+
+	require.NoError(t, w.Add([sha1.Size]byte{0xff}))
+	assert.ErrorIs(t, w.Add([sha1.Size]byte{0x01}), security.ErrUnsortedBreachHashes)
+}
+
+func TestOpenBreachDataset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.bin")
+	require.NoError(t, os.WriteFile(path, writeDataset(t, "hunter2"), 0o600))
+
+	dataset, err := security.OpenBreachDataset(path)
+	require.NoError(t, err)
+	defer dataset.Close()
+
+	breached, err := dataset.Breached(context.Background(), "hunter2")
+	require.NoError(t, err)
+	assert.True(t, breached)
+}
+
+func TestOpenBreachDataset_Invalid(t *testing.T) {
+	data := writeDataset(t, "hunter2")
+	dir := t.TempDir()
+
+	for name, content := range map[string][]byte{
+		"truncated":  data[:len(data)-1],
+		"header":     append([]byte("XXXX"), data[4:]...),
+		"extra data": append(slices.Clone(data), 0),
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+
+		_, err := security.OpenBreachDataset(path)
+		assert.ErrorIs(t, err, security.ErrInvalidBreachDataset, name)
+	}
+}
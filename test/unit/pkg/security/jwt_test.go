@@ -0,0 +1,159 @@
+package security_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/security"
+)
+
+func pemKey(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func ed25519Key(t *testing.T, id string) security.SigningKey {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := security.ParseSigningKey(id, pemKey(t, private))
+	require.NoError(t, err)
+	return key
+}
+
+func TestParseSigningKey(t *testing.T) {
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rsaKey, err := security.ParseSigningKey("rsa-1", pemKey(t, rsaPrivate))
+	require.NoError(t, err)
+	assert.Equal(t, security.AlgRS256, rsaKey.Algorithm)
+
+	assert.Equal(t, security.AlgEdDSA, ed25519Key(t, "ed-1").Algorithm)
+
+	_, err = security.ParseSigningKey("bad", []byte("not a key"))
+	assert.ErrorIs(t, err, security.ErrUnsupportedKeyType)
+}
+
+func TestJWTIssuer_IssueAndVerify(t *testing.T) {
+	issuer, err := security.NewJWTIssuer("https://api.example.com", []security.SigningKey{ed25519Key(t, "ed-1")}, 15*time.Minute)
+	require.NoError(t, err)
+
+	token, issued, err := issuer.Issue("user-123", "acme")
+	require.NoError(t, err)
+
+	claims, err := issuer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", claims.Subject)
+	assert.Equal(t, "acme", claims.Tenant)
+	assert.Equal(t, issued.ID, claims.ID)
+	assert.Equal(t, "https://api.example.com", claims.Issuer)
+
+	jwks := issuer.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+}
+
+func TestJWTIssuer_RS256(t *testing.T) {
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := security.ParseSigningKey("rsa-1", pemKey(t, rsaPrivate))
+	require.NoError(t, err)
+	issuer, err := security.NewJWTIssuer("https://api.example.com", []security.SigningKey{key}, time.Minute)
+	require.NoError(t, err)
+
+	token, _, err := issuer.Issue("user-123", "")
+	require.NoError(t, err)
+	_, err = issuer.Verify(token)
+	require.NoError(t, err)
+
+	jwks := issuer.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+}
+
+func TestJWTIssuer_Expired(t *testing.T) {
+	issuer, err := security.NewJWTIssuer("iss", []security.SigningKey{ed25519Key(t, "ed-1")}, time.Minute)
+	require.NoError(t, err)
+
+	token, _, err := issuer.Issue("user-123", "")
+	require.NoError(t, err)
+
+	issuer.WithClock(func() time.Time { return time.Now().Add(2 * time.Minute) })
+	_, err = issuer.Verify(token)
+
+	assert.ErrorIs(t, err, security.ErrJWTExpired)
+}
+
+func TestJWTIssuer_KeyRotation(t *testing.T) {
+	previous := ed25519Key(t, "2026-09")
+	current := ed25519Key(t, "2026-10")
+
+	before, err := security.NewJWTIssuer("iss", []security.SigningKey{previous}, time.Minute)
+	require.NoError(t, err)
+	token, _, err := before.Issue("user-123", "")
+	require.NoError(t, err)
+
+	after, err := security.NewJWTIssuer("iss", []security.SigningKey{current, previous}, time.Minute)
+	require.NoError(t, err)
+	_, err = after.Verify(token)
+	assert.NoError(t, err, "tokens of the previous key stay valid")
+
+	removed, err := security.NewJWTIssuer("iss", []security.SigningKey{current}, time.Minute)
+	require.NoError(t, err)
+	_, err = removed.Verify(token)
+	assert.ErrorIs(t, err, security.ErrUnknownJWTKey)
+
+	kids := []string{after.JWKS().Keys[0].KeyID, after.JWKS().Keys[1].KeyID}
+	assert.Equal(t, []string{"2026-10", "2026-09"}, kids)
+}
+
+func TestJWTIssuer_RejectsTampering(t *testing.T) {
+	issuer, err := security.NewJWTIssuer("iss", []security.SigningKey{ed25519Key(t, "ed-1")}, time.Minute)
+	require.NoError(t, err)
+	token, _, err := issuer.Issue("user-123", "")
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	forgedPayload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"iss","sub":"admin","exp":9999999999}`))
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"ed-1","typ":"at+jwt"}`))
+
+	for name, forged := range map[string]string{
+		"payload":   parts[0] + "." + forgedPayload + "." + parts[2],
+		"alg none":  noneHeader + "." + parts[1] + ".",
+		"truncated": parts[0] + "." + parts[1],
+	} {
+		_, err := issuer.Verify(forged)
+		assert.Error(t, err, name)
+	}
+
+	other, err := security.NewJWTIssuer("other", []security.SigningKey{ed25519Key(t, "ed-1")}, time.Minute)
+	require.NoError(t, err)
+	_, err = other.Verify(token)
+	assert.ErrorIs(t, err, security.ErrInvalidJWT, "signed by a different key with the same kid")
+}
+
+func TestJWTIssuer_SignedIDTokenIsNoAccessToken(t *testing.T) {
+	issuer, err := security.NewJWTIssuer("iss", []security.SigningKey{ed25519Key(t, "ed-1")}, time.Minute)
+	require.NoError(t, err)
+
+	idToken, err := issuer.Sign(map[string]any{"iss": "iss", "sub": "user-123", "aud": "client", "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	_, err = issuer.Verify(idToken)
+	assert.ErrorIs(t, err, security.ErrInvalidJWT)
+}
@@ -0,0 +1,64 @@
+package security_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/security"
+)
+
+func TestParseKey(t *testing.T) {
+	key, err := security.ParseKey("2026-09:c2VjcmV0+/=@2026-10-01T00:00:00Z")
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-09", key.ID)
+	assert.Equal(t, []byte("c2VjcmV0+/="), key.Secret)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), key.Retired)
+}
+
+func TestParseKey_Invalid(t *testing.T) {
+	for _, spec := range []string{"no-separator", ":secret", "id:", "id:secret@yesterday"} {
+		_, err := security.ParseKey(spec)
+		assert.ErrorIs(t, err, security.ErrInvalidKeySpec, spec)
+	}
+}
+
+func TestNewKeyRing_Validation(t *testing.T) {
+	retired := time.Now()
+
+	_, err := security.NewKeyRing(nil, time.Hour)
+	assert.ErrorIs(t, err, security.ErrEmptyKeyRing)
+
+	_, err = security.NewKeyRing([]security.Key{{ID: "a", Secret: []byte("s"), Retired: retired}}, time.Hour)
+	assert.ErrorIs(t, err, security.ErrCurrentKeyRetired)
+
+	_, err = security.NewKeyRing([]security.Key{{ID: "a", Secret: []byte("s")}, {ID: "b", Secret: []byte("s")}}, time.Hour)
+	assert.ErrorIs(t, err, security.ErrMissingRetirement)
+
+	_, err = security.NewKeyRing([]security.Key{{ID: "a", Secret: []byte("s")}, {ID: "a", Secret: []byte("t"), Retired: retired}}, time.Hour)
+	assert.ErrorIs(t, err, security.ErrDuplicateKeyID)
+}
+
+func TestKeyRing_ActiveExpiresPreviousKeys(t *testing.T) {
+	rotatedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	ring, err := security.ParseKeyRing([]string{
+		"current:new-secret",
+		"previous:old-secret@" + rotatedAt.Format(time.RFC3339),
+	}, 24*time.Hour)
+	require.NoError(t, err)
+
+	now := rotatedAt.Add(23 * time.Hour)
+	ring.WithClock(func() time.Time { return now })
+
+	assert.Equal(t, "current", ring.Current().ID)
+	assert.Len(t, ring.Active(), 2)
+
+	now = rotatedAt.Add(24 * time.Hour)
+	active := ring.Active()
+	require.Len(t, active, 1)
+	assert.Equal(t, "current", active[0].ID)
+	assert.Len(t, ring.Keys(), 2)
+}
@@ -0,0 +1,72 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/security"
+)
+
+func defaultPolicy() *security.PasswordPolicy {
+	return security.NewPasswordPolicy(security.PasswordRules{MinLength: 8, MaxLength: 72, MinCharClasses: 1})
+}
+
+func violations(t *testing.T, err error) []string {
+	var policyErr *security.PasswordPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	return policyErr.Violations
+}
+
+func TestPasswordPolicy_Accepts(t *testing.T) {
+	assert.NoError(t, defaultPolicy().Check("correct horse battery staple", "jane", "jane@example.com"))
+}
+
+func TestPasswordPolicy_Length(t *testing.T) {
+	policy := defaultPolicy()
+
+	err := policy.Check("kP9#xz")
+	assert.ErrorIs(t, err, security.ErrWeakPassword)
+	assert.Equal(t, []string{"must be at least 8 characters long"}, violations(t, err))
+
+	// Length is counted in characters at the bottom and in bytes at the top
+	assert.NoError(t, policy.Check("ünïcødé!"))
+	err = policy.Check(strings.Repeat("é", 37))
+	assert.Equal(t, []string{"must be at most 72 bytes long"}, violations(t, err))
+}
+
+func TestPasswordPolicy_CharClasses(t *testing.T) {
+	policy := security.NewPasswordPolicy(security.PasswordRules{MinLength: 8, MinCharClasses: 3})
+
+	err := policy.Check("onlylowercase")
+	assert.Len(t, violations(t, err), 1)
+	assert.NoError(t, policy.Check("Mixed case 4"))
+}
+
+func TestPasswordPolicy_Banned(t *testing.T) {
+	policy := security.NewPasswordPolicy(security.PasswordRules{MinLength: 8, Banned: []string{"Acme-Corp-2026"}})
+
+	assert.Contains(t, violations(t, policy.Check("PassWord123")), "is too common")
+	assert.Contains(t, violations(t, policy.Check("acme-corp-2026")), "is too common")
+}
+
+func TestPasswordPolicy_Personal(t *testing.T) {
+	policy := defaultPolicy()
+
+	for _, password := range []string{"janedoe-rocks", "my JANE.DOE secret", "jane.doe@example.com!"} {
+		err := policy.Check(password, "janedoe", "jane.doe@example.com")
+		assert.Equal(t, []string{"must not contain your username or email"}, violations(t, err), password)
+	}
+
+	// Very short names would reject too many passwords
+	assert.NoError(t, policy.Check("always a long secret", "al", ""))
+}
+
+func TestParsePasswordList(t *testing.T) {
+	passwords, err := security.ParsePasswordList(strings.NewReader("# comment\n\nsummer2026\n  winter2026  \n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"summer2026", "winter2026"}, passwords)
+}
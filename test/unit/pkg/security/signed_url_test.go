@@ -0,0 +1,69 @@
+package security_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/security"
+)
+
+func newURLSigner(t *testing.T, now *time.Time, keys ...security.Key) *security.URLSigner {
+	t.Helper()
+
+	ring, err := security.NewKeyRing(keys, time.Hour)
+	require.NoError(t, err)
+	clock := func() time.Time { return *now }
+	ring.WithClock(clock)
+	return security.NewURLSigner(ring, 24*time.Hour).WithClock(clock)
+}
+
+func TestURLSigner(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	signer := newURLSigner(t, &now, security.Key{ID: "a", Secret: []byte("secret-a")})
+
+	query := signer.Sign("/avatars/1/a.jpg")
+	assert.True(t, signer.Verify("/avatars/1/a.jpg", query))
+	assert.False(t, signer.Verify("/avatars/2/a.jpg", query), "another path")
+
+	tampered := map[string][]string{"expires": {"9999999999"}, "signature": query["signature"]}
+	assert.False(t, signer.Verify("/avatars/1/a.jpg", tampered), "extended expiry")
+	assert.False(t, signer.Verify("/avatars/1/a.jpg", nil), "unsigned")
+}
+
+func TestURLSigner_CacheableExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC)
+	signer := newURLSigner(t, &now, security.Key{ID: "a", Secret: []byte("secret-a")})
+
+	first := signer.Sign("/avatars/1/a.jpg")
+	assert.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), signer.Expires(first).UTC())
+
+	now = now.Add(11 * time.Hour)
+	assert.Equal(t, first, signer.Sign("/avatars/1/a.jpg"), "same URL within half of the TTL")
+
+	now = now.Add(time.Hour)
+	second := signer.Sign("/avatars/1/a.jpg")
+	assert.NotEqual(t, first, second)
+	assert.True(t, signer.Verify("/avatars/1/a.jpg", first), "still valid for at least half of the TTL")
+
+	now = time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	assert.False(t, signer.Verify("/avatars/1/a.jpg", first), "expired")
+	assert.True(t, signer.Verify("/avatars/1/a.jpg", second))
+}
+
+func TestURLSigner_KeyRotation(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	old := newURLSigner(t, &now, security.Key{ID: "a", Secret: []byte("secret-a")})
+	query := old.Sign("/avatars/1/a.jpg")
+
+	rotated := newURLSigner(t, &now,
+		security.Key{ID: "b", Secret: []byte("secret-b")},
+		security.Key{ID: "a", Secret: []byte("secret-a"), Retired: now},
+	)
+	assert.True(t, rotated.Verify("/avatars/1/a.jpg", query), "previous key within its grace period")
+
+	now = now.Add(2 * time.Hour)
+	assert.False(t, rotated.Verify("/avatars/1/a.jpg", query), "previous key after its grace period")
+}
@@ -0,0 +1,86 @@
+package security_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/security"
+)
+
+func defaultUsernamePolicy() *security.UsernamePolicy {
+	return security.NewUsernamePolicy(security.UsernameRules{MinLength: 3, MaxLength: 15})
+}
+
+func usernameViolations(t *testing.T, err error) []string {
+	var policyErr *security.UsernamePolicyError
+	require.ErrorAs(t, err, &policyErr)
+	return policyErr.Violations
+}
+
+func TestUsernamePolicy_Accepts(t *testing.T) {
+	policy := defaultUsernamePolicy()
+
+	for _, name := range []string{"jane", "jane.doe", "jane-doe_42", "j4ne", "007bond"} {
+		assert.NoError(t, policy.Check(name), name)
+	}
+}
+
+func TestUsernamePolicy_Violations(t *testing.T) {
+	policy := defaultUsernamePolicy()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "jd", want: "must be at least 3 characters long"},
+		{name: "a-very-long-username", want: "must be at most 15 characters long"},
+		{name: "jane doe", want: "may only contain letters a-z, digits, dots, dashes and underscores"},
+		{name: "jané", want: "may only contain letters a-z, digits, dots, dashes and underscores"},
+		{name: "_jane", want: "must start and end with a letter or digit"},
+		{name: "jane.", want: "must start and end with a letter or digit"},
+		{name: "jane..doe", want: "must not contain consecutive dots, dashes or underscores"},
+		{name: "jane-_doe", want: "must not contain consecutive dots, dashes or underscores"},
+		{name: "12345", want: "must contain a letter"},
+	}
+
+	for _, tt := range tests {
+		err := policy.Check(tt.name)
+		assert.ErrorIs(t, err, security.ErrInvalidUsername, tt.name)
+		assert.Equal(t, []string{tt.want}, usernameViolations(t, err), tt.name)
+	}
+}
+
+func TestUsernamePolicy_Reserved(t *testing.T) {
+	policy := security.NewUsernamePolicy(security.UsernameRules{MinLength: 3, Reserved: []string{"Acme"}})
+
+	// Lookalikes of reserved names are reserved too
+	for _, name := range []string{"admin", "adm1n", "supp0rt", "no_reply", "acme", "acrne"} {
+		assert.Equal(t, []string{"is reserved"}, usernameViolations(t, policy.Check(name)), name)
+	}
+	assert.NoError(t, policy.Check("admiral"))
+	assert.True(t, policy.Reserved("r00t"))
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	assert.Equal(t, "jane", security.NormalizeUsername("  JANE "))
+	assert.Equal(t, "jane", security.NormalizeUsername("Ｊａｎｅ"), "fullwidth letters fold into plain ones")
+	assert.Equal(t, "jane2", security.NormalizeUsername("jane²"))
+}
+
+func TestUsernameSkeleton(t *testing.T) {
+	same := [][2]string{
+		{"admin", "adm1n"},
+		{"jane.doe", "jane_doe"},
+		{"jane-doe", "jane_doe"},
+		{"modern", "rnodern"},
+		{"wolf", "vvolf"},
+		{"bob", "b0b"},
+		{"lily", "1i1y"},
+	}
+	for _, pair := range same {
+		assert.Equal(t, security.UsernameSkeleton(pair[0]), security.UsernameSkeleton(pair[1]), pair)
+	}
+	assert.NotEqual(t, security.UsernameSkeleton("jane"), security.UsernameSkeleton("john"))
+}
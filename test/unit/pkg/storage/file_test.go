@@ -0,0 +1,58 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/storage"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewFileStore(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "users/1/a.png", []byte("first")))
+	require.NoError(t, store.Put(ctx, "users/1/a.png", []byte("second")))
+	data, err := os.ReadFile(filepath.Join(dir, "users", "1", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), data)
+
+	blob, err := store.Get(ctx, "users/1/a.png")
+	require.NoError(t, err)
+	content, err := io.ReadAll(blob.Body)
+	require.NoError(t, blob.Body.Close())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), content)
+	assert.Equal(t, int64(6), blob.Size)
+
+	require.NoError(t, store.Delete(ctx, "users/1/a.png"))
+	_, err = store.Get(ctx, "users/1/a.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.NoError(t, store.Delete(ctx, "users/1/a.png"), "deleting a missing blob")
+
+	_, err = store.Get(ctx, "users/1")
+	assert.ErrorIs(t, err, storage.ErrNotFound, "directories are no blobs")
+}
+
+func TestFileStore_InvalidKeys(t *testing.T) {
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "a//b", "a/./b", "a/", "a b", "a%2Fb", `a\b`} {
+		assert.ErrorIs(t, store.Put(context.Background(), key, []byte("x")), storage.ErrInvalidKey, key)
+	}
+}
+
+func TestContentType(t *testing.T) {
+	assert.Equal(t, "image/jpeg", storage.ContentType("avatars/1/a.jpg"))
+	assert.Equal(t, "image/png", storage.ContentType("avatars/1/a.png"))
+	assert.Equal(t, "application/octet-stream", storage.ContentType("avatars/1/a"))
+}
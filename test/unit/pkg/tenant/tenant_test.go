@@ -0,0 +1,87 @@
+package tenant_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example.com/pkg/tenant"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := tenant.NewResolver([]string{"acme", "globex"}, "example.com", "X-Tenant-ID")
+
+	tests := []struct {
+		name    string
+		host    string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{name: "subdomain", host: "acme.example.com", want: "acme"},
+		{name: "subdomain with port", host: "acme.example.com:8080", want: "acme"},
+		{name: "subdomain in upper case", host: "ACME.Example.com", want: "acme"},
+		{name: "header", host: "localhost:8080", header: "Globex", want: "globex"},
+		{name: "host and header agree", host: "acme.example.com", header: "acme", want: "acme"},
+		{name: "base domain", host: "example.com"},
+		{name: "deeper subdomain", host: "www.acme.example.com"},
+		{name: "other domain", host: "acme.example.org"},
+		{name: "host and header disagree", host: "acme.example.com", header: "globex", wantErr: tenant.ErrConflict},
+		{name: "unknown subdomain", host: "initech.example.com", wantErr: tenant.ErrUnknown},
+		{name: "unknown header", host: "localhost", header: "initech", wantErr: tenant.ErrUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/user/me", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+
+			id, err := resolver.Resolve(req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, id)
+		})
+	}
+}
+
+func TestResolver_Resolve_SourcesDisabled(t *testing.T) {
+	resolver := tenant.NewResolver([]string{"acme"}, "", "")
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Host = "acme.example.com"
+	req.Header.Set("X-Tenant-ID", "acme")
+
+	id, err := resolver.Resolve(req)
+	require.NoError(t, err)
+	assert.Empty(t, id)
+}
+
+func TestContext(t *testing.T) {
+	_, ok := tenant.FromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = tenant.FromContext(tenant.NewContext(context.Background(), ""))
+	assert.False(t, ok, "an empty tenant acts for no tenant")
+
+	id, ok := tenant.FromContext(tenant.NewContext(context.Background(), "acme"))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", id)
+}
+
+func TestValid(t *testing.T) {
+	for _, id := range []string{"acme", "a", "acme-2", "0day"} {
+		assert.True(t, tenant.Valid(id), id)
+	}
+	for _, id := range []string{"", "Acme", "-acme", "acme-", "acme.corp", "acme_corp", string(make([]byte, 64))} {
+		assert.False(t, tenant.Valid(id), id)
+	}
+}